@@ -0,0 +1,44 @@
+package main
+
+import (
+	"slices"
+
+	trackingactions "github.com/footprint-tools/cli/internal/actions/tracking"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+)
+
+// hotPathFlags are the flags fp record accepts on the fast path. Anything
+// else (--help, a typo) goes through the full dispatcher so it gets the
+// usual help and error messages.
+var hotPathFlags = []string{"--verbose", "--manual", "--read-only", "--no-color", "--no-pager", "--quiet", "-q"}
+
+// isHotPath reports whether this invocation is fp record, which git hooks
+// run on every commit, checkout and ref update. It needs neither the command
+// tree, completions nor the theme, so run skips building them. Hook managers
+// call it as "fp record <hook-name>", which is allowed too.
+func isHotPath(commands, rawFlags []string) bool {
+	if len(commands) == 0 || len(commands) > 2 || commands[0] != "record" {
+		return false
+	}
+	for _, f := range rawFlags {
+		if !slices.Contains(hotPathFlags, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// runHotPath runs fp record directly, logging its duration like Dispatch
+// would. fp record never fails the hook: errors are logged, not returned.
+func runHotPath(args []string, flags *dispatchers.ParsedFlags) int {
+	res := dispatchers.Resolution{
+		Node:    &dispatchers.DispatchNode{Path: []string{"fp", "record"}},
+		Args:    args,
+		Flags:   flags,
+		Execute: trackingactions.Record,
+	}
+	if err := res.Run(nil); err != nil {
+		return 1
+	}
+	return 0
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	helpactions "github.com/footprint-tools/cli/internal/actions/help"
 	updateactions "github.com/footprint-tools/cli/internal/actions/update"
@@ -14,6 +15,7 @@ import (
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/log"
 	"github.com/footprint-tools/cli/internal/paths"
+	"github.com/footprint-tools/cli/internal/store"
 	"github.com/footprint-tools/cli/internal/ui"
 	"github.com/footprint-tools/cli/internal/ui/style"
 	"github.com/footprint-tools/cli/internal/usage"
@@ -25,21 +27,53 @@ func main() {
 }
 
 func run() int {
+	args := os.Args[1:]
+
+	rawFlags, commands := extractFlagsAndCommands(args)
+	flags := dispatchers.NewParsedFlags(rawFlags)
+
+	// The profile decides which config file and database everything below
+	// reads
+	if ue := applyProfile(flags); ue != nil {
+		fmt.Fprintln(os.Stderr, ue.Error())
+		return ue.GetExitCode()
+	}
+
 	// Initialize logger based on config (must read config before CLI setup)
 	initLogger()
 	defer func() { _ = log.Close() }()
 
-	args := os.Args[1:]
+	// An encrypted store is worked on in an unlocked copy; write the
+	// command's changes back to it
+	defer func() {
+		if err := store.Seal(); err != nil {
+			log.Error("store: could not seal the encrypted database: %v", err)
+		}
+	}()
 
-	rawFlags, commands := extractFlagsAndCommands(args)
-	flags := dispatchers.NewParsedFlags(rawFlags)
+	// Delete the executable a Windows update left behind
+	updateactions.RemoveOldBinary()
+
+	if isHotPath(commands, rawFlags) {
+		if flags.Has("--read-only") {
+			config.EnableReadOnly()
+		}
+		if flags.Has("--quiet") || flags.Has("-q") {
+			ui.EnableQuiet()
+		}
+		return runHotPath(commands[1:], flags)
+	}
 
-	// Enable styling if stdout is a terminal and --no-color is not set
-	enableColor := term.IsTerminal(int(os.Stdout.Fd())) && !flags.Has("--no-color")
+	// Enable styling if stdout is a terminal (or --force-color is set) and
+	// --no-color is not set
+	enableColor := (term.IsTerminal(int(os.Stdout.Fd())) || flags.Has("--force-color")) && !flags.Has("--no-color")
 	cfg, err := config.GetAll()
 	if err != nil {
 		log.Debug("main: failed to load config, using defaults: %v", err)
 	}
+	for _, err := range style.LoadThemeFiles(paths.ThemesDir()) {
+		log.Warn("theme: skipping theme file %v", err)
+	}
 	style.Init(enableColor, cfg)
 
 	// Disable pager if --no-pager is set
@@ -57,6 +91,31 @@ func run() int {
 		ui.EnableQuiet()
 	}
 
+	// Swap full-screen views for plain numbered prompts if --accessible is
+	// set (FP_ACCESSIBLE or accessible=true in config also enable it)
+	if flags.Has("--accessible") {
+		ui.EnableAccessible()
+	}
+
+	// Show times in the zone asked for (--utc, --tz or the timezone key)
+	if ue := applyTimezone(flags); ue != nil {
+		fmt.Fprintln(os.Stderr, ue.Error())
+		return ue.GetExitCode()
+	}
+
+	// Enable read-only mode if --read-only is set (read_only=true in config also enables it)
+	if flags.Has("--read-only") {
+		config.EnableReadOnly()
+	}
+	dispatchers.SetReadOnlyFunc(config.IsReadOnly)
+
+	// Ask for missing arguments only when someone is at the terminal; scripts
+	// and hooks still get the usual error
+	if term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stderr.Fd())) {
+		dispatchers.SetArgPromptFunc(promptForArg)
+		store.SetPassphrasePrompt(ui.ReadPassword)
+	}
+
 	// Set BuildTree function for help browser (avoids import cycle)
 	helpactions.SetBuildTreeFunc(cli.BuildTree)
 
@@ -84,8 +143,12 @@ func run() int {
 		updateactions.PrintUpdateNotice()
 	}
 
-	if err := res.Execute(res.Args, res.Flags); err != nil {
+	if err := res.Run(printSlowHint); err != nil {
+		err = dispatchers.WithCommand(err, res.Node, root)
 		fmt.Fprintln(os.Stderr, err.Error())
+		if ue, ok := err.(*usage.Error); ok {
+			return ue.GetExitCode()
+		}
 		return 1
 	}
 
@@ -93,6 +156,16 @@ func run() int {
 	return res.ExitCode
 }
 
+// printSlowHint tells the user how to speed up a command that took too long.
+func printSlowHint(node *dispatchers.DispatchNode, elapsed time.Duration) {
+	if ui.IsQuiet() {
+		return
+	}
+	if hint := cli.SlowCommandHint(node, elapsed); hint != "" {
+		fmt.Fprintln(os.Stderr, style.Muted("hint: "+hint))
+	}
+}
+
 // initLogger initializes the logger based on config settings.
 func initLogger() {
 	// Check if logging is enabled
@@ -123,7 +196,7 @@ func extractFlagsAndCommands(args []string) ([]string, []string) {
 	}
 
 	// Flags that require a value (long form prefix)
-	valueFlagsLong := []string{"--limit", "--pager", "--status", "--source", "--since", "--until", "--repo", "--root", "--depth", "--branch", "--tag"}
+	valueFlagsLong := []string{"--limit", "--pager", "--status", "--source", "--since", "--until", "--repo", "--root", "--depth", "--branch", "--tag", "--format", "--year", "--html", "--out", "--metric", "--group-by", "--path", "--search", "--device", "--tz", "--values", "--speed", "--file", "--count", "--note", "--project", "--sqlite", "--from-github", "--author", "--tail", "--level", "--profile", "--interval", "--name", "--channel"}
 
 	i := 0
 	for i < len(args) {
@@ -3,6 +3,9 @@ package main
 import (
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
 )
 
 func TestExtractFlagsAndCommands(t *testing.T) {
@@ -30,6 +33,18 @@ func TestExtractFlagsAndCommands(t *testing.T) {
 			wantFlags:    []string{"--help", "-h", "--oneline"},
 			wantCommands: []string{},
 		},
+		{
+			name:         "logs --tail with and without a count",
+			args:         []string{"logs", "--tail", "100", "--level", "warn"},
+			wantFlags:    []string{"--tail=100", "--level=warn"},
+			wantCommands: []string{"logs"},
+		},
+		{
+			name:         "logs bare --tail",
+			args:         []string{"logs", "--tail"},
+			wantFlags:    []string{"--tail"},
+			wantCommands: []string{"logs"},
+		},
 		{
 			name:         "numeric shorthand -5",
 			args:         []string{"-5"},
@@ -114,6 +129,30 @@ func TestExtractFlagsAndCommands(t *testing.T) {
 			wantFlags:    []string{"--pager=less"},
 			wantCommands: []string{},
 		},
+		{
+			name:         "replay flags",
+			args:         []string{"dev", "replay", "--speed", "10x", "--file", "demo.jsonl", "--count", "5"},
+			wantFlags:    []string{"--speed=10x", "--file=demo.jsonl", "--count=5"},
+			wantCommands: []string{"dev", "replay"},
+		},
+		{
+			name:         "record annotations",
+			args:         []string{"record", "--tag", "pairing", "--note", "with Sam"},
+			wantFlags:    []string{"--tag=pairing", "--note=with Sam"},
+			wantCommands: []string{"record"},
+		},
+		{
+			name:         "top refresh interval",
+			args:         []string{"top", "--interval", "5"},
+			wantFlags:    []string{"--interval=5"},
+			wantCommands: []string{"top"},
+		},
+		{
+			name:         "theme import name",
+			args:         []string{"theme", "import", "t.toml", "--name", "mine"},
+			wantFlags:    []string{"--name=mine"},
+			wantCommands: []string{"theme", "import", "t.toml"},
+		},
 		{
 			name:         "-n without value",
 			args:         []string{"-n"},
@@ -153,3 +192,55 @@ func TestExtractFlagsAndCommands(t *testing.T) {
 		})
 	}
 }
+
+func TestIsHotPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []string
+		flags    []string
+		want     bool
+	}{
+		{"record from a hook", []string{"record"}, nil, true},
+		{"record with known flags", []string{"record"}, []string{"--verbose", "--read-only"}, true},
+		{"record help uses the dispatcher", []string{"record"}, []string{"--help"}, false},
+		{"unknown flag uses the dispatcher", []string{"record"}, []string{"--bogus"}, false},
+		{"other command", []string{"activity"}, nil, false},
+		{"record from a hook manager", []string{"record", "post-commit"}, nil, true},
+		{"record with too many args", []string{"record", "post-commit", "x"}, nil, false},
+		{"no command", nil, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isHotPath(tt.commands, tt.flags); got != tt.want {
+				t.Errorf("isHotPath(%v, %v) = %v, want %v", tt.commands, tt.flags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyTimezone(t *testing.T) {
+	machine := time.Local
+	t.Cleanup(func() { time.Local = machine })
+	t.Setenv("HOME", t.TempDir())
+
+	if ue := applyTimezone(dispatchers.NewParsedFlags([]string{"--utc"})); ue != nil {
+		t.Fatalf("--utc: %v", ue)
+	}
+	if time.Local != time.UTC {
+		t.Errorf("--utc: time.Local = %v, want UTC", time.Local)
+	}
+
+	if ue := applyTimezone(dispatchers.NewParsedFlags([]string{"--tz=Asia/Tokyo"})); ue != nil {
+		t.Fatalf("--tz: %v", ue)
+	}
+	if time.Local.String() != "Asia/Tokyo" {
+		t.Errorf("--tz: time.Local = %v, want Asia/Tokyo", time.Local)
+	}
+
+	if ue := applyTimezone(dispatchers.NewParsedFlags([]string{"--tz=Nowhere/Land"})); ue == nil || ue.GetExitCode() != 2 {
+		t.Errorf("invalid --tz: got %v, want a usage error", ue)
+	}
+	if ue := applyTimezone(dispatchers.NewParsedFlags([]string{"--utc", "--tz=UTC"})); ue == nil {
+		t.Error("--utc with --tz: want a conflicting flags error")
+	}
+}
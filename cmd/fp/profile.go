@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/paths"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// applyProfile selects the config profile: --profile, then FP_PROFILE,
+// then the default one. It runs before anything reads the config or opens
+// the database, as both depend on it.
+func applyProfile(flags *dispatchers.ParsedFlags) *usage.Error {
+	name := flags.String("--profile", os.Getenv("FP_PROFILE"))
+	if name == "" {
+		return nil
+	}
+	if err := config.ValidateProfileName(name); err != nil {
+		return &usage.Error{Kind: usage.ErrInvalidFlag, Message: "fp: --profile: " + err.Error()}
+	}
+	if !config.ProfileExists(name) {
+		return &usage.Error{
+			Kind:    usage.ErrInvalidFlag,
+			Message: "fp: profile '" + name + "' does not exist; create it with 'fp config profile create " + name + "'",
+		}
+	}
+	paths.SetProfile(name)
+	return nil
+}
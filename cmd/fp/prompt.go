@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/ui"
+	"github.com/footprint-tools/cli/internal/ui/components"
+)
+
+// promptForArg asks for a missing required argument, offering the
+// argument's choices when it has them. In accessible mode the choices are
+// a numbered list on plain lines.
+func promptForArg(node *dispatchers.DispatchNode, arg dispatchers.ArgSpec) (string, error) {
+	label := fmt.Sprintf("%s: %s", strings.Join(node.Path, " "), arg.Description)
+	if ui.IsAccessible() {
+		return promptForArgLinear(ui.NewTerminalPrompt(), label, arg)
+	}
+	prompt := components.NewThemedPrompt(label, arg.Name)
+	if arg.Choices != nil {
+		prompt = prompt.WithChoices(arg.Choices())
+	}
+	return components.RunPrompt(prompt)
+}
+
+func promptForArgLinear(p *ui.LinearPrompt, label string, arg dispatchers.ArgSpec) (string, error) {
+	var choices []string
+	if arg.Choices != nil {
+		choices = arg.Choices()
+	}
+	if len(choices) == 0 {
+		return p.Ask(label)
+	}
+	i, err := p.Choose(label, choices)
+	if err != nil {
+		return "", err
+	}
+	return choices[i], nil
+}
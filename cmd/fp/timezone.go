@@ -0,0 +1,43 @@
+package main
+
+import (
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// applyTimezone sets the zone times are shown in: --utc, then --tz, then
+// the timezone key, then the machine's own. A bad --tz is a usage error; a
+// bad timezone key only falls back to local time, so a typo in the config
+// doesn't break every command.
+func applyTimezone(flags *dispatchers.ParsedFlags) *usage.Error {
+	if flags.Has("--utc") && flags.String("--tz", "") != "" {
+		return usage.ConflictingFlags("--utc", "--tz")
+	}
+
+	if flags.Has("--utc") {
+		loc, _ := format.LoadZone("UTC")
+		format.SetZone(loc)
+		return nil
+	}
+
+	if name := flags.String("--tz", ""); name != "" {
+		loc, err := format.LoadZone(name)
+		if err != nil {
+			return &usage.Error{Kind: usage.ErrInvalidFlag, Message: "fp: --tz: " + err.Error()}
+		}
+		format.SetZone(loc)
+		return nil
+	}
+
+	name, _ := config.Get("timezone")
+	loc, err := format.LoadZone(name)
+	if err != nil {
+		log.Warn("config: timezone: %v, using local time", err)
+		return nil
+	}
+	format.SetZone(loc)
+	return nil
+}
@@ -26,6 +26,14 @@ func Completions(args []string, flags *dispatchers.ParsedFlags) error {
 }
 
 func completionsCmd(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	// --values: list candidates for the completion scripts, one per line
+	if kind := flags.String("--values", ""); kind != "" {
+		for _, value := range completions.Values(kind) {
+			_, _ = deps.Println(value)
+		}
+		return nil
+	}
+
 	var shell completions.Shell
 
 	if len(args) > 0 {
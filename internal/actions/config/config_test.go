@@ -1,13 +1,19 @@
 package config
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/ui"
+	"github.com/footprint-tools/cli/internal/ui/text"
 )
 
 // =========== GET TESTS ===========
@@ -170,6 +176,94 @@ func TestSet_WriteLinesError(t *testing.T) {
 	require.Contains(t, err.Error(), "cannot write config")
 }
 
+func TestSet_RejectsInvalidEntries(t *testing.T) {
+	written := false
+	deps := Deps{
+		ReadLines:  func() ([]string, error) { return []string{}, nil },
+		Set:        func(lines []string, key, value string) ([]string, bool) { return lines, false },
+		WriteLines: func([]string) error { written = true; return nil },
+		Printf:     func(string, ...any) (int, error) { return 0, nil },
+	}
+	flags := dispatchers.NewParsedFlags([]string{})
+
+	err := set([]string{"export_intervl_sec", "600"}, flags, deps)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown config key 'export_intervl_sec'")
+	require.Contains(t, err.Error(), "export_interval_sec", "the closest key is suggested")
+
+	err = set([]string{"export_format", "jsonn"}, flags, deps)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "export_format must be one of csv, jsonl, parquet")
+	require.Contains(t, err.Error(), "Did you mean 'jsonl'?")
+
+	err = set([]string{"retention_days", "forever"}, flags, deps)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "non-negative number")
+	require.False(t, written)
+
+	require.NoError(t, set([]string{"enrich_exec.jira", "~/bin/jira.sh"}, flags, deps), "user-named keys are allowed")
+	require.True(t, written)
+}
+
+// =========== EDIT TESTS ===========
+
+func TestEdit_SavesValidConfig(t *testing.T) {
+	var saved []string
+	deps := Deps{
+		ReadLines: func() ([]string, error) { return []string{"theme=default"}, nil },
+		RunEditor: func(path string) error {
+			return os.WriteFile(path, []byte("theme=neon-dark\nexport_format=jsonl\n"), 0600)
+		},
+		WriteLines: func(lines []string) error { saved = lines; return nil },
+		Println:    func(...any) (int, error) { return 0, nil },
+	}
+
+	require.NoError(t, edit(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{"theme=neon-dark", "export_format=jsonl"}, saved)
+}
+
+func TestEdit_ReopensUntilValidOrDropped(t *testing.T) {
+	var printed []string
+	edits := []string{
+		"enable_log=maybe\nexport_fromat=csv\n",
+		"enable_log=true\n",
+	}
+	var saved []string
+	answers := []string{"y"}
+	deps := Deps{
+		ReadLines: func() ([]string, error) { return []string{"enable_log=false"}, nil },
+		RunEditor: func(path string) error {
+			content := edits[0]
+			edits = edits[1:]
+			return os.WriteFile(path, []byte(content), 0600)
+		},
+		WriteLines: func(lines []string) error { saved = lines; return nil },
+		Println: func(a ...any) (int, error) {
+			printed = append(printed, fmt.Sprint(a...))
+			return 0, nil
+		},
+		Print: func(...any) (int, error) { return 0, nil },
+		Scanln: func(a ...any) (int, error) {
+			*(a[0].(*string)) = answers[0]
+			answers = answers[1:]
+			return 1, nil
+		},
+	}
+
+	require.NoError(t, edit(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.Contains(t, printed, "line 1: enable_log must be true or false, got 'maybe'")
+	require.Contains(t, printed, "line 2: unknown config key 'export_fromat' (did you mean 'export_format'?)")
+	require.Equal(t, []string{"enable_log=true"}, saved)
+
+	edits = []string{"theme\n"}
+	answers = []string{"n"}
+	saved = nil
+	err := edit(nil, dispatchers.NewParsedFlags(nil), deps)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "config not saved")
+	require.Nil(t, saved)
+}
+
 // =========== UNSET TESTS ===========
 
 func TestUnset_Success(t *testing.T) {
@@ -327,7 +421,7 @@ func TestList_Success(t *testing.T) {
 
 	require.NoError(t, err)
 	// Should show visible keys (HideIfEmpty keys are hidden when not set)
-	require.Len(t, printedLines, 8) // 8 always-visible keys
+	require.Len(t, printedLines, 13) // 13 always-visible keys
 }
 
 func TestList_ShowsDefaults(t *testing.T) {
@@ -347,7 +441,7 @@ func TestList_ShowsDefaults(t *testing.T) {
 
 	require.NoError(t, err)
 	// Should show visible keys with defaults (HideIfEmpty keys are hidden)
-	require.Len(t, printedLines, 8)
+	require.Len(t, printedLines, 13)
 }
 
 func TestList_ShowsColorOverridesWhenSet(t *testing.T) {
@@ -369,8 +463,34 @@ func TestList_ShowsColorOverridesWhenSet(t *testing.T) {
 	err := list([]string{}, flags, deps)
 
 	require.NoError(t, err)
-	// 8 always-visible + 2 color overrides that are set
-	require.Len(t, printedLines, 10)
+	// 13 always-visible + 2 color overrides that are set
+	require.Len(t, printedLines, 15)
+}
+
+func TestList_MasksSecrets(t *testing.T) {
+	var printed string
+	deps := Deps{
+		GetAll: func() (map[string]string, error) {
+			return map[string]string{"export_http_token": "s3cret"}, nil
+		},
+		Printf: func(format string, a ...any) (int, error) {
+			printed += fmt.Sprintf(format, a...)
+			return 0, nil
+		},
+		Println: func(a ...any) (int, error) {
+			printed += fmt.Sprint(a...)
+			return 0, nil
+		},
+	}
+
+	require.NoError(t, list(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.Contains(t, printed, "export_http_token")
+	require.NotContains(t, printed, "s3cret")
+
+	printed = ""
+	require.NoError(t, list(nil, dispatchers.NewParsedFlags([]string{"--json"}), deps))
+	require.Contains(t, printed, "export_http_token")
+	require.NotContains(t, printed, "s3cret")
 }
 
 func TestList_GetAllError(t *testing.T) {
@@ -410,3 +530,67 @@ func TestList_JSON(t *testing.T) {
 	require.Contains(t, printedOutput, `"key":`)
 	require.Contains(t, printedOutput, `"value":`)
 }
+
+func TestList_Defaults(t *testing.T) {
+	var printed []string
+	deps := Deps{
+		GetAll: func() (map[string]string, error) {
+			return map[string]string{"theme": "neon-dark", "pager": "less -FRSX", "github_token": "ghp_secret"}, nil
+		},
+		Default: func(key string) string {
+			value, _ := domain.GetDefaultValue(key)
+			return value
+		},
+		Printf: func(format string, a ...any) (int, error) {
+			printed = append(printed, text.StripANSI(fmt.Sprintf(format, a...)))
+			return 0, nil
+		},
+	}
+
+	require.NoError(t, list(nil, dispatchers.NewParsedFlags([]string{"--defaults"}), deps))
+	require.Len(t, printed, len(domain.VisibleConfigKeys()), "optional keys are listed too")
+	require.Contains(t, printed, "theme=neon-dark (default: default)\n")
+	require.Contains(t, printed, "pager=less -FRSX (default)\n")
+	require.Contains(t, printed, "github_token=******** (default: not set)\n")
+	require.Contains(t, printed, "export_remote= (not set)\n")
+}
+
+// =========== ACCESSIBLE EDITOR TESTS ===========
+
+func accessibleDeps(input string, lines *[]string, prompts *bytes.Buffer) Deps {
+	return Deps{
+		GetAll:     func() (map[string]string, error) { return map[string]string{}, nil },
+		ReadLines:  func() ([]string, error) { return *lines, nil },
+		WriteLines: func(l []string) error { *lines = l; return nil },
+		Set: func(l []string, key, value string) ([]string, bool) {
+			return append(l, key+"="+value), false
+		},
+		Unset:      func(l []string, key string) ([]string, bool) { return nil, true },
+		Println:    func(...any) (int, error) { return 0, nil },
+		Accessible: func() bool { return true },
+		NewPrompt: func() *ui.LinearPrompt {
+			return ui.NewLinearPrompt(strings.NewReader(input), prompts)
+		},
+	}
+}
+
+func TestInteractive_AccessibleSetsValue(t *testing.T) {
+	var lines []string
+	var prompts bytes.Buffer
+	deps := accessibleDeps("pager\n1\nless -R\n\n", &lines, &prompts)
+
+	require.NoError(t, interactive(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{"pager=less -R"}, lines)
+	require.Contains(t, prompts.String(), "1. pager = less -FRSX (default)")
+	require.Contains(t, prompts.String(), "pager = less -R")
+}
+
+func TestInteractive_AccessibleRejectsInvalidValue(t *testing.T) {
+	var lines []string
+	var prompts bytes.Buffer
+	deps := accessibleDeps("time_format\n1\n13h\n\n", &lines, &prompts)
+
+	require.NoError(t, interactive(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.Empty(t, lines)
+	require.Contains(t, prompts.String(), "Error: ")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/ui"
 )
 
 type Deps struct {
@@ -14,8 +15,21 @@ type Deps struct {
 	Unset      func([]string, string) ([]string, bool)
 	Get        func(string) (string, bool)
 	GetAll     func() (map[string]string, error)
-	Printf     func(string, ...any) (int, error)
-	Println    func(...any) (int, error)
+	Default    func(string) string
+	RunEditor  func(path string) error
+
+	CreateProfile func(string) (string, error)
+	Profiles      func() ([]string, error)
+
+	Printf  func(string, ...any) (int, error)
+	Println func(...any) (int, error)
+	Print   func(...any) (int, error)
+	Scanln  func(...any) (int, error)
+
+	// Accessible reports whether the editor should be numbered prompts
+	// instead of a TUI (see ui.IsAccessible), asked through NewPrompt.
+	Accessible func() bool
+	NewPrompt  func() *ui.LinearPrompt
 }
 
 func DefaultDeps() Deps {
@@ -27,7 +41,18 @@ func DefaultDeps() Deps {
 		Unset:      config.Unset,
 		Get:        config.Get,
 		GetAll:     config.GetAll,
-		Printf:     fmt.Printf,
-		Println:    fmt.Println,
+		Default:    config.Default,
+		RunEditor:  runEditor,
+
+		CreateProfile: config.CreateProfile,
+		Profiles:      config.Profiles,
+
+		Printf:  fmt.Printf,
+		Println: fmt.Println,
+		Print:   fmt.Print,
+		Scanln:  fmt.Scanln,
+
+		Accessible: ui.IsAccessible,
+		NewPrompt:  ui.NewTerminalPrompt,
 	}
 }
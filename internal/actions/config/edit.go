@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+)
+
+// Edit opens the config file in an editor and saves it once it validates.
+func Edit(args []string, flags *dispatchers.ParsedFlags) error {
+	return edit(args, flags, DefaultDeps())
+}
+
+// edit has the config edited in a temp copy, so a file that doesn't
+// validate never replaces the real one. After each save the problems are
+// listed and the copy can be edited again, or dropped.
+func edit(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	lines, err := deps.ReadLines()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "fprc-*.conf")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+	_, err = tmp.WriteString(strings.Join(lines, "\n") + "\n")
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	var edited []string
+	for {
+		if err := deps.RunEditor(tmpPath); err != nil {
+			return fmt.Errorf("fp: editor failed: %w", err)
+		}
+
+		data, err := os.ReadFile(tmpPath)
+		if err != nil {
+			return err
+		}
+		edited = strings.Split(strings.TrimRight(strings.ReplaceAll(string(data), "\r", ""), "\n"), "\n")
+
+		problems := validateLines(edited)
+		if len(problems) == 0 {
+			break
+		}
+		for _, p := range problems {
+			_, _ = deps.Println(p)
+		}
+		_, _ = deps.Print("edit again? [Y/n]: ")
+		var resp string
+		_, _ = deps.Scanln(&resp)
+		if resp == "n" || resp == "no" {
+			return fmt.Errorf("fp: config not saved (%d problems)", len(problems))
+		}
+	}
+
+	if slices.Equal(edited, lines) {
+		_, _ = deps.Println("no changes")
+		return nil
+	}
+	if err := deps.WriteLines(edited); err != nil {
+		return err
+	}
+	_, _ = deps.Println("config saved")
+	return nil
+}
+
+// editorCommand returns the editor set in $VISUAL or $EDITOR, falling back
+// to vi (notepad on Windows).
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if cmd := strings.TrimSpace(os.Getenv(env)); cmd != "" {
+			return cmd
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// runEditor opens path in the editor, which may come with arguments such
+// as "code --wait", and waits for it to exit.
+func runEditor(path string) error {
+	parts := strings.Fields(editorCommand())
+	cmd := exec.Command(parts[0], append(parts[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
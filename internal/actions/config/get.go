@@ -2,6 +2,7 @@ package config
 
 import (
 	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/domain"
 	"github.com/footprint-tools/cli/internal/usage"
 )
 
@@ -15,6 +16,9 @@ func get(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 	}
 
 	key := args[0]
+	if current, renamed := domain.RenamedConfigKey(key); renamed {
+		key = current
+	}
 
 	value, found := deps.Get(key)
 	if !found {
@@ -24,6 +24,10 @@ func Interactive(args []string, flags *dispatchers.ParsedFlags) error {
 }
 
 func interactive(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	if deps.Accessible != nil && deps.Accessible() {
+		return interactiveLinear(deps)
+	}
+
 	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
 		return errors.New("config editor requires an interactive terminal")
 	}
@@ -46,7 +50,7 @@ func interactive(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 	}
 
 	p := tea.NewProgram(
-		m,
+		components.NewSizeGuard(m),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
@@ -56,7 +60,7 @@ func interactive(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 		return err
 	}
 
-	fm := final.(configModel)
+	fm := final.(components.SizeGuard).Model.(configModel)
 
 	if fm.cancelled {
 		_, _ = deps.Println("\nCancelled")
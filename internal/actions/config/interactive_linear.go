@@ -0,0 +1,117 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/ui"
+)
+
+// Actions offered for a key in the accessible editor.
+const (
+	linearChange = "Change value"
+	linearReset  = "Reset to default"
+	linearBack   = "Back"
+)
+
+// interactiveLinear is the config editor in accessible mode: a numbered
+// list of keys with their values, then what to do with the one picked,
+// until the list is left with an empty answer.
+func interactiveLinear(deps Deps) error {
+	values, err := deps.GetAll()
+	if err != nil {
+		return err
+	}
+	keys := domain.VisibleConfigKeys()
+	p := deps.NewPrompt()
+	changed := false
+
+	for {
+		options := make([]string, len(keys))
+		for i, k := range keys {
+			options[i] = fmt.Sprintf("%s = %s", k.Name, displayValue(k, values))
+		}
+		i, err := p.Choose("Settings:", options)
+		if errors.Is(err, ui.ErrCancelled) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		key := keys[i]
+		p.Println(key.Description)
+		action, err := p.Choose(options[i], []string{linearChange, linearReset, linearBack})
+		if errors.Is(err, ui.ErrCancelled) || (err == nil && action == 2) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if action == 1 {
+			if err := unsetKey(key.Name, deps); err != nil {
+				p.Println("Error: " + err.Error())
+				continue
+			}
+			delete(values, key.Name)
+			changed = true
+			p.Println(key.Name + " reset")
+			continue
+		}
+
+		value, err := p.Ask("New value for " + key.Name)
+		if errors.Is(err, ui.ErrCancelled) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := validateEntry(key.Name, value); err != nil {
+			p.Println("Error: " + err.Error())
+			continue
+		}
+		if err := setKey(key.Name, value, deps); err != nil {
+			p.Println("Error: " + err.Error())
+			continue
+		}
+		values[key.Name] = value
+		changed = true
+		p.Println(key.Name + " saved")
+	}
+
+	if changed {
+		_, _ = deps.Println("Settings updated")
+	}
+	return nil
+}
+
+// displayValue is the value shown for k: the one set, else its default.
+func displayValue(k domain.ConfigKey, values map[string]string) string {
+	if v, ok := values[k.Name]; ok && v != "" {
+		return v
+	}
+	if k.Default != "" {
+		return k.Default + " (default)"
+	}
+	return "(not set)"
+}
+
+func setKey(key, value string, deps Deps) error {
+	lines, err := deps.ReadLines()
+	if err != nil {
+		return err
+	}
+	lines, _ = deps.Set(lines, key, value)
+	return deps.WriteLines(lines)
+}
+
+func unsetKey(key string, deps Deps) error {
+	lines, err := deps.ReadLines()
+	if err != nil {
+		return err
+	}
+	lines, _ = deps.Unset(lines, key)
+	return deps.WriteLines(lines)
+}
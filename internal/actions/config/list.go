@@ -11,6 +11,9 @@ func List(args []string, flags *dispatchers.ParsedFlags) error {
 	return list(args, flags, DefaultDeps())
 }
 
+// maskedValue replaces secret values in config list output.
+const maskedValue = "********"
+
 func list(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	jsonOutput := flags.Has("--json")
 
@@ -19,6 +22,13 @@ func list(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		return err
 	}
 
+	if flags.Has("--defaults") {
+		if jsonOutput {
+			return listDefaultsJSON(configMap, deps)
+		}
+		return listDefaults(configMap, deps)
+	}
+
 	if jsonOutput {
 		return listJSON(configMap, deps)
 	}
@@ -34,6 +44,8 @@ func list(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		}
 
 		switch {
+		case hasValue && key.Secret:
+			_, _ = deps.Printf("%s=%s\n", style.Info(key.Name), maskedValue)
 		case hasValue:
 			_, _ = deps.Printf("%s=%s\n", style.Info(key.Name), value)
 		case key.Default != "":
@@ -70,7 +82,9 @@ func listJSON(configMap map[string]string, deps Deps) error {
 			Default: key.Default,
 			IsSet:   hasValue,
 		}
-		if hasValue {
+		if hasValue && key.Secret {
+			entry.Value = maskedValue
+		} else if hasValue {
 			entry.Value = value
 		} else {
 			entry.Value = key.Default
@@ -80,3 +94,69 @@ func listJSON(configMap map[string]string, deps Deps) error {
 
 	return output.JSON(deps.Println, entries)
 }
+
+// listDefaults shows every visible key with its effective value next to
+// its default, so settings that differ from the default stand out.
+func listDefaults(configMap map[string]string, deps Deps) error {
+	for _, key := range domain.VisibleConfigKeys() {
+		value, def := effectiveValue(key, configMap, deps)
+
+		shown := value
+		if key.Secret && value != "" {
+			shown = maskedValue
+		}
+
+		switch {
+		case value == "" && def == "":
+			_, _ = deps.Printf("%s= %s\n", style.Info(key.Name), style.Muted("(not set)"))
+		case value == def:
+			_, _ = deps.Printf("%s=%s %s\n", style.Info(key.Name), shown, style.Muted("(default)"))
+		case def == "":
+			_, _ = deps.Printf("%s=%s %s\n", style.Info(key.Name), shown, style.Muted("(default: not set)"))
+		default:
+			_, _ = deps.Printf("%s=%s %s\n", style.Info(key.Name), shown, style.Muted("(default: "+def+")"))
+		}
+	}
+	return nil
+}
+
+func listDefaultsJSON(configMap map[string]string, deps Deps) error {
+	type configEntry struct {
+		Key     string   `json:"key"`
+		Value   string   `json:"value"`
+		Default string   `json:"default"`
+		Type    string   `json:"type"`
+		Values  []string `json:"values,omitempty"`
+	}
+
+	keys := domain.VisibleConfigKeys()
+	entries := make([]configEntry, 0, len(keys))
+	for _, key := range keys {
+		value, def := effectiveValue(key, configMap, deps)
+		if key.Secret && value != "" {
+			value = maskedValue
+		}
+		entries = append(entries, configEntry{
+			Key:     key.Name,
+			Value:   value,
+			Default: def,
+			Type:    key.Type.String(),
+			Values:  key.Values,
+		})
+	}
+
+	return output.JSON(deps.Println, entries)
+}
+
+// effectiveValue returns the value fp uses for key and its default.
+func effectiveValue(key domain.ConfigKey, configMap map[string]string, deps Deps) (value, def string) {
+	def = key.Default
+	if deps.Default != nil {
+		def = deps.Default(key.Name)
+	}
+	value, ok := configMap[key.Name]
+	if !ok {
+		value = def
+	}
+	return value, def
+}
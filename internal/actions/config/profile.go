@@ -0,0 +1,78 @@
+package config
+
+import (
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/paths"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// defaultProfileName is how the default profile is listed.
+const defaultProfileName = "default"
+
+// ProfileCreate creates a config profile.
+func ProfileCreate(args []string, flags *dispatchers.ParsedFlags) error {
+	return profileCreate(args, flags, DefaultDeps())
+}
+
+func profileCreate(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) < 1 {
+		return usage.MissingArgument("name")
+	}
+	name := args[0]
+
+	path, err := deps.CreateProfile(name)
+	if err != nil {
+		return err
+	}
+
+	_, _ = deps.Printf("Created profile %s (%s)\n\n", style.Info(name), path)
+	_, _ = deps.Println("It has its own database and export repository, and starts with your")
+	_, _ = deps.Println("settings minus exports, identities and repository rules. Next:")
+	_, _ = deps.Println("")
+	_, _ = deps.Printf("  fp --profile %s config set repos_include ~/work\n", name)
+	_, _ = deps.Printf("  fp --profile %s config set identities you@work.example\n", name)
+	_, _ = deps.Println("")
+	_, _ = deps.Println(style.Muted("Hooks in repositories matching its repos_include record into it."))
+	return nil
+}
+
+// ProfileList lists the config profiles, marking the active one.
+func ProfileList(args []string, flags *dispatchers.ParsedFlags) error {
+	return profileList(args, flags, DefaultDeps())
+}
+
+func profileList(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	names, err := deps.Profiles()
+	if err != nil {
+		return err
+	}
+	names = append([]string{defaultProfileName}, names...)
+
+	active := paths.Profile()
+	if active == "" {
+		active = defaultProfileName
+	}
+
+	if flags.Has("--json") {
+		type profileEntry struct {
+			Name   string `json:"name"`
+			Active bool   `json:"active"`
+		}
+		entries := make([]profileEntry, 0, len(names))
+		for _, name := range names {
+			entries = append(entries, profileEntry{Name: name, Active: name == active})
+		}
+		return output.JSON(deps.Println, entries)
+	}
+
+	for _, name := range names {
+		if name == active {
+			_, _ = deps.Printf("* %s\n", style.Info(name))
+		} else {
+			_, _ = deps.Printf("  %s\n", name)
+		}
+	}
+	return nil
+}
@@ -19,9 +19,13 @@ func set(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 	key := args[0]
 	value := args[1]
 
-	// Warn if key is not a recognized config key
-	if !domain.IsValidConfigKey(key) {
-		_, _ = deps.Printf("warning: '%s' is not a recognized config key\n", key)
+	if current, renamed := domain.RenamedConfigKey(key); renamed {
+		_, _ = deps.Printf("warning: '%s' was renamed to '%s'\n", key, current)
+		key = current
+	}
+
+	if err := validateEntry(key, value); err != nil {
+		return err
 	}
 
 	lines, err := deps.ReadLines()
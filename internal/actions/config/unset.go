@@ -2,6 +2,7 @@ package config
 
 import (
 	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/domain"
 	"github.com/footprint-tools/cli/internal/usage"
 )
 
@@ -28,6 +29,10 @@ func unset(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	}
 
 	key := args[0]
+	if current, renamed := domain.RenamedConfigKey(key); renamed {
+		_, _ = deps.Printf("warning: '%s' was renamed to '%s'\n", key, current)
+		key = current
+	}
 
 	lines, err := deps.ReadLines()
 	if err != nil {
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// maxKeySuggestions is how many known keys are suggested for an unknown one.
+const maxKeySuggestions = 3
+
+// similarKeys returns the known config keys closest to an unknown one.
+func similarKeys(key string) []string {
+	names := make([]string, 0, len(domain.ConfigKeys))
+	for _, k := range domain.ConfigKeys {
+		names = append(names, k.Name)
+	}
+	return dispatchers.FindSimilar(key, names, maxKeySuggestions)
+}
+
+// checkEntry returns what is wrong with key=value according to the config
+// schema, if anything, and the known key or allowed value that was
+// probably meant.
+func checkEntry(key, value string) (problem, suggestion string) {
+	if !domain.IsValidConfigKey(key) {
+		if similar := similarKeys(key); len(similar) > 0 {
+			suggestion = similar[0]
+		}
+		return fmt.Sprintf("unknown config key '%s'", key), suggestion
+	}
+
+	if err := domain.ValidateConfigValue(key, value); err != nil {
+		k, _ := domain.GetConfigKey(key)
+		if k.Type == domain.ConfigEnum {
+			if similar := dispatchers.FindSimilar(strings.TrimSpace(value), k.Values, 1); len(similar) > 0 {
+				suggestion = similar[0]
+			}
+		}
+		return err.Error(), suggestion
+	}
+	return "", ""
+}
+
+// validateEntry is checkEntry for fp config set: an unknown key is a usage
+// error listing the keys that were probably meant.
+func validateEntry(key, value string) error {
+	if !domain.IsValidConfigKey(key) {
+		return usage.InvalidConfigKey(key, similarKeys(key)...)
+	}
+	problem, suggestion := checkEntry(key, value)
+	switch {
+	case problem == "":
+		return nil
+	case suggestion != "":
+		return fmt.Errorf("fp: %s\nDid you mean '%s'?", problem, suggestion)
+	default:
+		return fmt.Errorf("fp: %s", problem)
+	}
+}
+
+// validateLines checks every setting in a config file and returns one
+// problem per line that doesn't fit the schema, with its line number.
+func validateLines(lines []string) []string {
+	var problems []string
+	for i, line := range lines {
+		if i == 0 {
+			line = strings.TrimPrefix(line, "\uFEFF") // BOM safety
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(trimmed, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			problems = append(problems, fmt.Sprintf("line %d: expected key=value", i+1))
+			continue
+		}
+		if strings.HasSuffix(key, "[]") {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = value[1 : len(value)-1]
+		}
+		problem, suggestion := checkEntry(key, value)
+		switch {
+		case problem == "":
+		case suggestion != "":
+			problems = append(problems, fmt.Sprintf("line %d: %s (did you mean '%s'?)", i+1, problem, suggestion))
+		default:
+			problems = append(problems, fmt.Sprintf("line %d: %s", i+1, problem))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,109 @@
+package daemon
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+)
+
+const (
+	// stopTimeout is how long Stop waits for the daemon to exit after SIGTERM
+	stopTimeout = 5 * time.Second
+	// stopPollInterval is how often Stop checks whether the daemon exited
+	stopPollInterval = 100 * time.Millisecond
+)
+
+// Start handles `fp daemon start`.
+func Start(args []string, flags *dispatchers.ParsedFlags) error {
+	return start(args, flags, DefaultDeps())
+}
+
+func start(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	pidPath := deps.PIDFilePath()
+
+	if pid, ok := deps.Running(pidPath); ok {
+		_, _ = deps.Printf("daemon already running (pid %d)\n", pid)
+		return nil
+	}
+
+	if flags.Has("--foreground") {
+		return run(deps)
+	}
+
+	exe, err := deps.Executable()
+	if err != nil {
+		return fmt.Errorf("could not locate fp executable: %w", err)
+	}
+
+	pid, err := deps.StartProcess(exe, []string{"daemon", "start", "--foreground"})
+	if err != nil {
+		return fmt.Errorf("could not start daemon: %w", err)
+	}
+
+	log.Info("daemon: spawned background process (pid=%d)", pid)
+	_, _ = deps.Printf("daemon started (pid %d)\n", pid)
+	return nil
+}
+
+// Stop handles `fp daemon stop`.
+func Stop(args []string, flags *dispatchers.ParsedFlags) error {
+	return stop(args, flags, DefaultDeps())
+}
+
+func stop(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	pidPath := deps.PIDFilePath()
+
+	pid, ok := deps.Running(pidPath)
+	if !ok {
+		// Clean up a stale pidfile left behind by a crashed daemon
+		_ = deps.RemovePID(pidPath)
+		_, _ = deps.Println("daemon is not running")
+		return nil
+	}
+
+	if err := deps.Signal(pid, syscall.SIGTERM); err != nil {
+		return fmt.Errorf("could not stop daemon (pid %d): %w", pid, err)
+	}
+
+	deadline := deps.Now().Add(stopTimeout)
+	for deps.Now().Before(deadline) {
+		if _, ok := deps.Running(pidPath); !ok {
+			_, _ = deps.Printf("daemon stopped (pid %d)\n", pid)
+			return nil
+		}
+		deps.Sleep(stopPollInterval)
+	}
+
+	return fmt.Errorf("daemon (pid %d) did not exit within %s", pid, stopTimeout)
+}
+
+// Status handles `fp daemon status`.
+func Status(args []string, flags *dispatchers.ParsedFlags) error {
+	return status(args, flags, DefaultDeps())
+}
+
+func status(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	pidPath := deps.PIDFilePath()
+	pid, running := deps.Running(pidPath)
+
+	if flags.Has("--json") {
+		type statusJSON struct {
+			Running bool   `json:"running"`
+			PID     int    `json:"pid,omitempty"`
+			PIDFile string `json:"pid_file"`
+		}
+		return output.JSON(deps.Println, statusJSON{Running: running, PID: pid, PIDFile: pidPath})
+	}
+
+	if !running {
+		_, _ = deps.Println("daemon is not running")
+		return nil
+	}
+
+	_, _ = deps.Printf("daemon running (pid %d)\n", pid)
+	return nil
+}
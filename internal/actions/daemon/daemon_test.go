@@ -0,0 +1,184 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	updateactions "github.com/footprint-tools/cli/internal/actions/update"
+	"github.com/footprint-tools/cli/internal/daemon"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/paths"
+	"github.com/stretchr/testify/require"
+)
+
+func testDeps(printed *[]string) Deps {
+	return Deps{
+		PIDFilePath: func() string { return "/tmp/fp-test/daemon.pid" },
+		Running:     func(string) (int, bool) { return 0, false },
+		RemovePID:   func(string) error { return nil },
+		Printf: func(format string, a ...any) (int, error) {
+			*printed = append(*printed, fmt.Sprintf(format, a...))
+			return 0, nil
+		},
+		Println: func(a ...any) (int, error) {
+			*printed = append(*printed, fmt.Sprint(a...))
+			return 0, nil
+		},
+		Now:   time.Now,
+		Sleep: func(time.Duration) {},
+	}
+}
+
+func TestStart_AlreadyRunning(t *testing.T) {
+	var printed []string
+	deps := testDeps(&printed)
+	deps.Running = func(string) (int, bool) { return 123, true }
+	deps.StartProcess = func(string, []string) (int, error) {
+		t.Fatal("should not start a second daemon")
+		return 0, nil
+	}
+
+	err := start(nil, dispatchers.NewParsedFlags(nil), deps)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"daemon already running (pid 123)\n"}, printed)
+}
+
+func TestStart_SpawnsForegroundProcess(t *testing.T) {
+	var printed []string
+	var gotArgs []string
+	deps := testDeps(&printed)
+	deps.Executable = func() (string, error) { return "/usr/local/bin/fp", nil }
+	deps.StartProcess = func(name string, args []string) (int, error) {
+		require.Equal(t, "/usr/local/bin/fp", name)
+		gotArgs = args
+		return 999, nil
+	}
+
+	err := start(nil, dispatchers.NewParsedFlags(nil), deps)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"daemon", "start", "--foreground"}, gotArgs)
+	require.Equal(t, []string{"daemon started (pid 999)\n"}, printed)
+}
+
+func TestStart_SpawnError(t *testing.T) {
+	var printed []string
+	deps := testDeps(&printed)
+	deps.Executable = func() (string, error) { return "fp", nil }
+	deps.StartProcess = func(string, []string) (int, error) { return 0, errors.New("boom") }
+
+	err := start(nil, dispatchers.NewParsedFlags(nil), deps)
+
+	require.ErrorContains(t, err, "could not start daemon")
+}
+
+func TestStop_NotRunningRemovesStalePidfile(t *testing.T) {
+	var printed []string
+	removed := false
+	deps := testDeps(&printed)
+	deps.RemovePID = func(string) error { removed = true; return nil }
+
+	err := stop(nil, dispatchers.NewParsedFlags(nil), deps)
+
+	require.NoError(t, err)
+	require.True(t, removed)
+	require.Equal(t, []string{"daemon is not running"}, printed)
+}
+
+func TestStop_SignalsAndWaits(t *testing.T) {
+	var printed []string
+	var signaled syscall.Signal
+	calls := 0
+	deps := testDeps(&printed)
+	deps.Running = func(string) (int, bool) {
+		calls++
+		return 55, calls < 3 // exits after the second poll
+	}
+	deps.Signal = func(pid int, sig syscall.Signal) error {
+		require.Equal(t, 55, pid)
+		signaled = sig
+		return nil
+	}
+
+	err := stop(nil, dispatchers.NewParsedFlags(nil), deps)
+
+	require.NoError(t, err)
+	require.Equal(t, syscall.SIGTERM, signaled)
+	require.Equal(t, []string{"daemon stopped (pid 55)\n"}, printed)
+}
+
+func TestStop_Timeout(t *testing.T) {
+	var printed []string
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	deps := testDeps(&printed)
+	deps.Running = func(string) (int, bool) { return 55, true }
+	deps.Signal = func(int, syscall.Signal) error { return nil }
+	deps.Now = func() time.Time { return now }
+	deps.Sleep = func(d time.Duration) { now = now.Add(d) }
+
+	err := stop(nil, dispatchers.NewParsedFlags(nil), deps)
+
+	require.ErrorContains(t, err, "did not exit")
+}
+
+func TestStatus_JSON(t *testing.T) {
+	var printed []string
+	deps := testDeps(&printed)
+	deps.Running = func(string) (int, bool) { return 77, true }
+
+	err := status(nil, dispatchers.NewParsedFlags([]string{"--json"}), deps)
+
+	require.NoError(t, err)
+	require.Len(t, printed, 1)
+	require.Contains(t, printed[0], `"running": true`)
+	require.Contains(t, printed[0], `"pid": 77`)
+}
+
+func TestTick_ChecksUpdatesHourly(t *testing.T) {
+	exports, maintenance, checks := 0, 0, 0
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	deps := Deps{
+		ExportIfDue:      func() error { exports++; return nil },
+		MaintenanceIfDue: func() error { maintenance++; return nil },
+		CheckUpdate: func() *updateactions.CheckResult {
+			checks++
+			return &updateactions.CheckResult{}
+		},
+		Now: func() time.Time { return now },
+	}
+
+	var last time.Time
+	tick(deps, &last)
+	now = now.Add(daemon.TickInterval)
+	tick(deps, &last)
+	now = now.Add(updateCheckInterval)
+	tick(deps, &last)
+
+	require.Equal(t, 3, exports)
+	require.Equal(t, 3, maintenance)
+	require.Equal(t, 2, checks)
+}
+
+func TestTick_RunsEveryProfile(t *testing.T) {
+	var exported, maintained, sealed []string
+	deps := Deps{
+		Profiles:         func() ([]string, error) { return []string{"work"}, nil },
+		ExportIfDue:      func() error { exported = append(exported, paths.Profile()); return nil },
+		MaintenanceIfDue: func() error { maintained = append(maintained, paths.Profile()); return nil },
+		CheckUpdate:      func() *updateactions.CheckResult { return nil },
+		SealStore:        func() error { sealed = append(sealed, paths.Profile()); return nil },
+		Now:              time.Now,
+	}
+
+	var last time.Time
+	tick(deps, &last)
+
+	require.Equal(t, []string{"", "work"}, exported)
+	require.Equal(t, []string{"", "work"}, maintained)
+	require.Equal(t, []string{"", "work"}, sealed)
+	require.Empty(t, paths.Profile(), "the active profile is restored")
+}
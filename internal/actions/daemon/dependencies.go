@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	trackingactions "github.com/footprint-tools/cli/internal/actions/tracking"
+	updateactions "github.com/footprint-tools/cli/internal/actions/update"
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/daemon"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui"
+)
+
+type Deps struct {
+	// pidfile
+	PIDFilePath func() string
+	Running     func(string) (int, bool)
+	WritePID    func(string, int) error
+	RemovePID   func(string) error
+
+	// process
+	Executable   func() (string, error)
+	StartProcess func(string, []string) (int, error)
+	Signal       func(int, syscall.Signal) error
+	Getpid       func() int
+
+	// work, run for each config profile
+	Profiles         func() ([]string, error)
+	ExportIfDue      func() error
+	MaintenanceIfDue func() error
+	CheckUpdate      func() *updateactions.CheckResult
+	SealStore        func() error
+
+	// io
+	Printf  func(string, ...any) (int, error)
+	Println func(...any) (int, error)
+
+	// misc
+	Now   func() time.Time
+	Sleep func(time.Duration)
+}
+
+func DefaultDeps() Deps {
+	return Deps{
+		PIDFilePath: daemon.PIDFilePath,
+		Running:     daemon.Running,
+		WritePID:    daemon.WritePID,
+		RemovePID:   daemon.RemovePID,
+
+		Executable:   os.Executable,
+		StartProcess: startDetached,
+		Signal:       signalProcess,
+		Getpid:       os.Getpid,
+
+		Profiles:         config.Profiles,
+		ExportIfDue:      trackingactions.ExportIfDue,
+		MaintenanceIfDue: trackingactions.MaintenanceIfDue,
+		CheckUpdate:      updateactions.CheckForUpdate,
+		SealStore:        store.Seal,
+
+		Printf:  ui.Printf,
+		Println: ui.Println,
+
+		Now:   time.Now,
+		Sleep: time.Sleep,
+	}
+}
+
+// startDetached starts a process detached from the terminal (in its own
+// session, or without a console on Windows) and returns its pid without
+// waiting for it.
+func startDetached(name string, args []string) (int, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = nil
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+	cmd.SysProcAttr = detachedProcAttr()
+
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+	pid := cmd.Process.Pid
+	_ = cmd.Process.Release()
+	return pid, nil
+}
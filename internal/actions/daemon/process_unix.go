@@ -0,0 +1,14 @@
+//go:build !windows
+
+package daemon
+
+import "syscall"
+
+// signalProcess sends sig to the process pid.
+var signalProcess = syscall.Kill
+
+// detachedProcAttr starts a process in a new session, so it outlives the
+// terminal that started it.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
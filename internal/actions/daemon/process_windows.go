@@ -0,0 +1,29 @@
+package daemon
+
+import (
+	"os"
+	"syscall"
+)
+
+// Process creation flags from the Windows API
+const (
+	createNewProcessGroup = 0x00000200
+	detachedProcess       = 0x00000008
+)
+
+// signalProcess ends the process pid. Windows has no signals to ask a
+// process to exit, so any sig terminates it; the daemon's pidfile is left
+// behind and treated as stale.
+func signalProcess(pid int, _ syscall.Signal) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
+
+// detachedProcAttr starts a process without a console and out of the
+// console's process group, so closing the terminal doesn't end it.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: createNewProcessGroup | detachedProcess, HideWindow: true}
+}
@@ -0,0 +1,106 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/daemon"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/paths"
+)
+
+const (
+	// updateCheckInterval is how often the daemon checks for a new release
+	updateCheckInterval = time.Hour
+)
+
+// run executes the daemon loop in the current process until SIGINT/SIGTERM.
+func run(deps Deps) error {
+	pidPath := deps.PIDFilePath()
+	pid := deps.Getpid()
+
+	if err := deps.WritePID(pidPath, pid); err != nil {
+		return fmt.Errorf("could not write pidfile %s: %w", pidPath, err)
+	}
+	defer func() { _ = deps.RemovePID(pidPath) }()
+
+	log.Info("daemon: started (pid=%d)", pid)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var lastUpdateCheck time.Time
+	tick(deps, &lastUpdateCheck)
+
+	ticker := time.NewTicker(daemon.TickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("daemon: stopping (pid=%d)", pid)
+			return nil
+		case <-ticker.C:
+			tick(deps, &lastUpdateCheck)
+		}
+	}
+}
+
+// tick performs one round of daemon work: flush pending events if the export
+// interval has elapsed, run weekly maintenance when due, and check for
+// updates at most once per hour. Exports and maintenance run for every
+// config profile in turn, and an encrypted store is sealed after them.
+func tick(deps Deps, lastUpdateCheck *time.Time) {
+	active := paths.Profile()
+	for _, profile := range profilesToRun(deps) {
+		paths.SetProfile(profile)
+		if err := deps.ExportIfDue(); err != nil {
+			log.Error("daemon: export failed%s: %v", profileSuffix(profile), err)
+		}
+
+		if err := deps.MaintenanceIfDue(); err != nil {
+			log.Error("daemon: maintenance failed%s: %v", profileSuffix(profile), err)
+		}
+
+		if deps.SealStore != nil {
+			if err := deps.SealStore(); err != nil {
+				log.Error("daemon: could not seal the encrypted database%s: %v", profileSuffix(profile), err)
+			}
+		}
+	}
+	paths.SetProfile(active)
+
+	now := deps.Now()
+	if now.Sub(*lastUpdateCheck) < updateCheckInterval {
+		return
+	}
+	*lastUpdateCheck = now
+
+	if result := deps.CheckUpdate(); result != nil && result.UpdateAvailable {
+		log.Info("daemon: update available (current=%s, latest=%s)", result.CurrentVersion, result.LatestVersion)
+	}
+}
+
+// profilesToRun lists the default profile and every other one created.
+func profilesToRun(deps Deps) []string {
+	profiles := []string{""}
+	if deps.Profiles == nil {
+		return profiles
+	}
+	others, err := deps.Profiles()
+	if err != nil {
+		log.Warn("daemon: could not list profiles: %v", err)
+	}
+	return append(profiles, others...)
+}
+
+func profileSuffix(profile string) string {
+	if profile == "" {
+		return ""
+	}
+	return " (profile " + profile + ")"
+}
@@ -3,11 +3,16 @@ package help
 import (
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/help"
+	"github.com/footprint-tools/cli/internal/ui"
 )
 
 type Deps struct {
 	BuildTree func() *dispatchers.DispatchNode
 	AllTopics func() []*help.Topic
+	// Accessible reports whether the browser should be numbered menus
+	// instead of a TUI (see ui.IsAccessible), asked through NewPrompt.
+	Accessible func() bool
+	NewPrompt  func() *ui.LinearPrompt
 }
 
 // buildTreeFunc is set at runtime to avoid import cycles.
@@ -21,7 +26,9 @@ func SetBuildTreeFunc(f func() *dispatchers.DispatchNode) {
 
 func DefaultDeps() Deps {
 	return Deps{
-		BuildTree: buildTreeFunc,
-		AllTopics: help.AllTopics,
+		BuildTree:  buildTreeFunc,
+		AllTopics:  help.AllTopics,
+		Accessible: ui.IsAccessible,
+		NewPrompt:  ui.NewTerminalPrompt,
 	}
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/help"
+	"github.com/footprint-tools/cli/internal/ui/components"
 	"github.com/footprint-tools/cli/internal/ui/splitpanel"
 	"github.com/footprint-tools/cli/internal/ui/style"
 	"golang.org/x/term"
@@ -29,11 +30,16 @@ func Browser(args []string, flags *dispatchers.ParsedFlags) error {
 //
 
 func browser(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	if deps.Accessible != nil && deps.Accessible() {
+		return browserLinear(deps)
+	}
+
 	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
 		return errors.New("interactive help requires an interactive terminal")
 	}
 
 	root := deps.BuildTree()
+	root.LoadAll()
 	topics := deps.AllTopics()
 	items := buildSidebarItems(root, topics)
 
@@ -58,7 +64,7 @@ func browser(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 	}
 
 	p := tea.NewProgram(
-		m,
+		components.NewSizeGuard(m),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
@@ -0,0 +1,70 @@
+package help
+
+import (
+	"errors"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/ui"
+)
+
+// browserLinear is the help browser in accessible mode: a numbered list of
+// sections, then of the commands or guides in the one picked, whose help
+// is printed as 'fp help <name>' would. An empty answer goes back a level.
+func browserLinear(deps Deps) error {
+	root := deps.BuildTree()
+	root.LoadAll()
+	items := buildSidebarItems(root, deps.AllTopics())
+
+	// Split the sidebar into its sections
+	var sections []string
+	var entries [][]sidebarItem
+	for _, item := range items {
+		if item.IsCategory {
+			sections = append(sections, item.Name)
+			entries = append(entries, nil)
+			continue
+		}
+		if len(entries) > 0 {
+			entries[len(entries)-1] = append(entries[len(entries)-1], item)
+		}
+	}
+
+	p := deps.NewPrompt()
+	for {
+		s, err := p.Choose("Help sections:", sections)
+		if errors.Is(err, ui.ErrCancelled) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, len(entries[s]))
+		for i, item := range entries[s] {
+			names[i] = item.Name
+			if item.Node != nil && item.Node.Summary != "" {
+				names[i] += " - " + item.Node.Summary
+			} else if item.Topic != nil && item.Topic.Summary != "" {
+				names[i] += " - " + item.Topic.Summary
+			}
+		}
+		for {
+			i, err := p.Choose(sections[s]+":", names)
+			if errors.Is(err, ui.ErrCancelled) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+
+			item := entries[s][i]
+			show := dispatchers.HelpAction(item.Node, root)
+			if item.IsTopic {
+				show = dispatchers.TopicHelpAction(item.Topic)
+			}
+			if err := show(nil, dispatchers.NewParsedFlags(nil)); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/footprint-tools/cli/internal/paths"
+	"github.com/footprint-tools/cli/internal/ui"
 )
 
 type Deps struct {
@@ -15,6 +16,9 @@ type Deps struct {
 	WriteFile   func(string, []byte, os.FileMode) error
 	Stat        func(string) (os.FileInfo, error)
 	OpenFile    func(string, int, os.FileMode) (*os.File, error)
+	// Accessible reports whether -i should print the log as plain lines
+	// instead of opening the viewer (see ui.IsAccessible).
+	Accessible func() bool
 }
 
 func DefaultDeps() Deps {
@@ -26,5 +30,6 @@ func DefaultDeps() Deps {
 		WriteFile:   os.WriteFile,
 		Stat:        os.Stat,
 		OpenFile:    os.OpenFile,
+		Accessible:  ui.IsAccessible,
 	}
 }
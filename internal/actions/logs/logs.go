@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/log"
 	"github.com/footprint-tools/cli/internal/output"
 	"github.com/footprint-tools/cli/internal/ui/style"
 )
@@ -29,6 +30,11 @@ func view(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	jsonOutput := flags.Has("--json")
 	logPath := deps.LogFilePath()
 
+	minLevel, filterLevel, err := levelFlag(flags)
+	if err != nil {
+		return err
+	}
+
 	// Check if log file exists
 	info, err := deps.Stat(logPath)
 	if os.IsNotExist(err) {
@@ -65,8 +71,12 @@ func view(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		lines = lines[:len(lines)-1]
 	}
 
-	// Get limit from flags
-	limit := flags.Int("--limit", defaultLogLimit)
+	if filterLevel {
+		lines = linesAtLevel(lines, minLevel)
+	}
+
+	// Get limit from flags; --tail N is the same as -n N
+	limit := flags.Int("--tail", flags.Int("--limit", defaultLogLimit))
 	if limit <= 0 {
 		limit = defaultLogLimit
 	}
@@ -82,12 +92,55 @@ func view(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	}
 
 	for _, line := range lines[start:] {
-		_, _ = deps.Println(colorizeLogLine(line))
+		_, _ = deps.Println(renderLogLine(line))
 	}
 
 	return nil
 }
 
+// levelFlag reads --level, the least severe level to show. ok is false
+// when the flag isn't set.
+func levelFlag(flags *dispatchers.ParsedFlags) (level log.Level, ok bool, err error) {
+	name := flags.String("--level", "")
+	if name == "" {
+		return 0, false, nil
+	}
+	level, ok = log.ParseLevel(name)
+	if !ok {
+		return 0, false, fmt.Errorf("invalid --level '%s': expected debug, info, warn or error", name)
+	}
+	return level, true, nil
+}
+
+// linesAtLevel keeps the lines at minLevel or more severe. Lines without
+// a level are dropped.
+func linesAtLevel(lines []string, minLevel log.Level) []string {
+	var kept []string
+	for _, line := range lines {
+		if atLevel(line, minLevel) {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+func atLevel(line string, minLevel log.Level) bool {
+	level, ok := log.ParseLevel(parseLine(line).Level)
+	return ok && level >= minLevel
+}
+
+// renderLogLine lays out a log line for reading, as
+// "[2025-01-29 10:30:45] WARN export.go:42: message", colored by level.
+// Lines that aren't structured entries are shown as they are.
+func renderLogLine(line string) string {
+	entry, ok := log.ParseEntry(line)
+	if !ok {
+		return colorizeLogLine(line)
+	}
+	return colorizeLogLine(fmt.Sprintf("[%s] %s %s: %s",
+		entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Level, entry.Caller, entry.Message))
+}
+
 // logEntryRegex matches log lines like: [2025-01-29 10:30:45] INFO hooks/global.go:42: message
 var logEntryRegex = regexp.MustCompile(`^\[([^\]]+)\]\s+(DEBUG|INFO|WARN|ERROR)\s+([^:]+):(\d+):\s*(.*)$`)
 
@@ -107,6 +160,22 @@ func viewJSON(lines []string, deps Deps) error {
 			continue
 		}
 
+		if entry, ok := log.ParseEntry(line); ok {
+			location, lineNum := entry.Caller, 0
+			if file, num, found := strings.Cut(entry.Caller, ":"); found {
+				location = file
+				lineNum, _ = strconv.Atoi(num)
+			}
+			entries = append(entries, logEntry{
+				Timestamp: entry.Time.Format(time.RFC3339),
+				Level:     entry.Level.String(),
+				Location:  location,
+				Line:      lineNum,
+				Message:   entry.Message,
+			})
+			continue
+		}
+
 		matches := logEntryRegex.FindStringSubmatch(line)
 		if matches != nil {
 			lineNum, _ := strconv.Atoi(matches[4])
@@ -134,9 +203,14 @@ func Tail(args []string, flags *dispatchers.ParsedFlags) error {
 	return tail(args, flags, DefaultDeps())
 }
 
-func tail(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+func tail(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	logPath := deps.LogFilePath()
 
+	minLevel, filterLevel, err := levelFlag(flags)
+	if err != nil {
+		return err
+	}
+
 	file, err := deps.OpenFile(logPath, os.O_RDONLY|os.O_CREATE, 0600)
 	if err != nil {
 		return fmt.Errorf("open log file: %w", err)
@@ -191,8 +265,11 @@ func tail(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 				}
 			}
 
-			// Print the line (without extra newline since ReadString includes it)
-			_, _ = deps.Printf("%s\n", colorizeLogLine(strings.TrimSuffix(line, "\n")))
+			line = strings.TrimSuffix(line, "\n")
+			if filterLevel && !atLevel(line, minLevel) {
+				continue
+			}
+			_, _ = deps.Printf("%s\n", renderLogLine(line))
 		}
 	}
 }
@@ -7,6 +7,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/paths"
+	"github.com/footprint-tools/cli/internal/ui/components"
 	"golang.org/x/term"
 )
 
@@ -15,7 +16,12 @@ func Interactive(args []string, flags *dispatchers.ParsedFlags) error {
 	return interactive(args, flags, DefaultDeps())
 }
 
-func interactive(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+func interactive(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	// A screen reader follows the plain listing better than the viewer
+	if deps.Accessible != nil && deps.Accessible() {
+		return view(args, flags, deps)
+	}
+
 	// Check for terminal
 	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
 		return errors.New("interactive logs requires an interactive terminal")
@@ -31,7 +37,7 @@ func interactive(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 
 	// Run program
 	p := tea.NewProgram(
-		m,
+		components.NewSizeGuard(m),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
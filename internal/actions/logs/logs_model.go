@@ -7,6 +7,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/footprint-tools/cli/internal/log"
 	"github.com/footprint-tools/cli/internal/ui/components"
 	"github.com/footprint-tools/cli/internal/ui/style"
 )
@@ -588,6 +589,17 @@ func (m logsModel) sessionDuration() time.Duration {
 
 // parseLine parses a log line into its components
 func parseLine(raw string) LogLine {
+	if entry, ok := log.ParseEntry(raw); ok {
+		return LogLine{
+			Raw:        raw,
+			Timestamp:  entry.Time.Local().Format("2006-01-02 15:04:05"),
+			ParsedTime: entry.Time.Local(),
+			Level:      entry.Level.String(),
+			Caller:     entry.Caller,
+			Message:    entry.Message,
+		}
+	}
+
 	line := LogLine{Raw: raw}
 
 	// Try to parse timestamp, level, caller, and message
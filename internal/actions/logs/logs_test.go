@@ -3,6 +3,7 @@ package logs
 import (
 	"errors"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -629,3 +630,68 @@ func (m *mockFileInfo) Mode() os.FileMode  { return 0644 }
 func (m *mockFileInfo) ModTime() time.Time { return time.Time{} }
 func (m *mockFileInfo) IsDir() bool        { return false }
 func (m *mockFileInfo) Sys() any           { return nil }
+
+// =========== STRUCTURED ENTRY TESTS ===========
+
+const structuredLog = `time=2026-01-29T10:30:45Z level=DEBUG caller=record.go:10 msg="record: start"
+time=2026-01-29T10:30:46Z level=WARN caller=export.go:42 msg="export: push failed"
+[2024-01-15 10:30:45] ERROR store.go:123: written before entries were structured
+time=2026-01-29T10:30:47Z level=INFO caller=daemon.go:7 msg="daemon: idle"
+`
+
+func viewOutput(t *testing.T, content string, flags ...string) ([]string, error) {
+	t.Helper()
+	var printed []string
+	deps := Deps{
+		LogFilePath: func() string { return "/tmp/test.log" },
+		Stat: func(path string) (os.FileInfo, error) {
+			return &mockFileInfo{size: int64(len(content))}, nil
+		},
+		ReadFile: func(path string) ([]byte, error) {
+			return []byte(content), nil
+		},
+		Println: func(a ...any) (int, error) {
+			if s, ok := a[0].(string); ok {
+				printed = append(printed, s)
+			}
+			return 0, nil
+		},
+	}
+	err := view(nil, dispatchers.NewParsedFlags(flags), deps)
+	return printed, err
+}
+
+func TestView_Level(t *testing.T) {
+	printed, err := viewOutput(t, structuredLog, "--level=warn")
+	require.NoError(t, err)
+	require.Len(t, printed, 2)
+	require.Contains(t, printed[0], "WARN export.go:42: export: push failed")
+	require.Contains(t, printed[1], "written before entries were structured")
+
+	printed, err = viewOutput(t, structuredLog, "--level=info", "--tail=1")
+	require.NoError(t, err)
+	require.Len(t, printed, 1, "--tail counts the lines left after filtering")
+	require.Contains(t, printed[0], "daemon: idle")
+
+	_, err = viewOutput(t, structuredLog, "--level=loud")
+	require.ErrorContains(t, err, "invalid --level 'loud'")
+}
+
+func TestView_StructuredJSON(t *testing.T) {
+	printed, err := viewOutput(t, structuredLog, "--json", "--level=warn")
+	require.NoError(t, err)
+
+	out := strings.Join(printed, "\n")
+	require.Contains(t, out, `"timestamp": "2026-01-29T10:30:46Z"`)
+	require.Contains(t, out, `"location": "export.go"`)
+	require.Contains(t, out, `"line": 42`)
+	require.Contains(t, out, `"message": "export: push failed"`)
+}
+
+func TestParseLine_Structured(t *testing.T) {
+	line := parseLine(`time=2026-01-29T10:30:45Z level=ERROR caller=store.go:123 msg="something failed"`)
+	require.Equal(t, "ERROR", line.Level)
+	require.Equal(t, "store.go:123", line.Caller)
+	require.Equal(t, "something failed", line.Message)
+	require.False(t, line.ParsedTime.IsZero())
+}
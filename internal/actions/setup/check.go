@@ -1,9 +1,12 @@
 package setup
 
 import (
+	"slices"
+	"sort"
+
 	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/hooks"
 	"github.com/footprint-tools/cli/internal/output"
-	"github.com/footprint-tools/cli/internal/usage"
 )
 
 func Check(args []string, flags *dispatchers.ParsedFlags) error {
@@ -13,29 +16,30 @@ func Check(args []string, flags *dispatchers.ParsedFlags) error {
 func check(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	jsonOutput := flags.Has("--json")
 
-	root, err := deps.RepoRoot(".")
-	if err != nil {
-		return usage.NotInGitRepo()
-	}
-
-	hooksPath, err := deps.RepoHooksPath(root)
+	target, err := resolveTarget(".", deps)
 	if err != nil {
 		return err
 	}
+	root, hooksPath := target.root, target.hooksPath
 
-	status := deps.HooksStatus(hooksPath)
+	status := target.status(hooksPath)
+	outdated := hooks.Outdated(hooksPath)
 
 	if jsonOutput {
-		return checkJSON(root, hooksPath, status, deps)
+		return checkJSON(root, hooksPath, status, outdated, deps)
 	}
 
 	installed := 0
-	for hook, isInstalled := range status {
-		if isInstalled {
+	for _, hook := range sortedHooks(status) {
+		switch {
+		case !status[hook]:
+			_, _ = deps.Printf("%-14s - not installed\n", hook)
+		case slices.Contains(outdated, hook):
+			_, _ = deps.Printf("%-14s ! outdated\n", hook)
+			installed++
+		default:
 			_, _ = deps.Printf("%-14s ✓ installed\n", hook)
 			installed++
-		} else {
-			_, _ = deps.Printf("%-14s - not installed\n", hook)
 		}
 	}
 
@@ -47,42 +51,90 @@ func check(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	default:
 		_, _ = deps.Printf("\n%d/%d hooks installed\n", installed, len(status))
 	}
+	if len(outdated) > 0 {
+		_, _ = deps.Println("some hooks are outdated - run 'fp doctor --fix' to refresh them")
+	}
 
 	return nil
 }
 
-func checkJSON(repoRoot, hooksPath string, status map[string]bool, deps Deps) error {
+// sortedHooks returns the hook names in status in install order, so output
+// is stable between runs.
+func sortedHooks(status map[string]bool) []string {
+	names := make([]string, 0, len(status))
+	for hook := range status {
+		names = append(names, hook)
+	}
+	rank := func(hook string) int {
+		if i := slices.Index(hooks.ManagedHooks, hook); i >= 0 {
+			return i
+		}
+		return len(hooks.ManagedHooks)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if ri, rj := rank(names[i]), rank(names[j]); ri != rj {
+			return ri < rj
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func checkJSON(repoRoot, hooksPath string, status map[string]bool, outdated []string, deps Deps) error {
+	// ID, Status and Severity follow fp doctor --json so scripts can handle
+	// both the same way.
 	type hookStatus struct {
+		ID        string `json:"id"`
 		Name      string `json:"name"`
 		Installed bool   `json:"installed"`
+		Outdated  bool   `json:"outdated,omitempty"`
+		Status    string `json:"status"`
+		Severity  string `json:"severity"`
+		Hint      string `json:"hint,omitempty"`
 	}
 
 	type checkResult struct {
 		RepoPath       string       `json:"repo_path"`
 		HooksPath      string       `json:"hooks_path"`
+		Status         string       `json:"status"`
+		Severity       string       `json:"severity"`
 		Hooks          []hookStatus `json:"hooks"`
 		InstalledCount int          `json:"installed_count"`
 		TotalCount     int          `json:"total_count"`
 		AllInstalled   bool         `json:"all_installed"`
 	}
 
-	hooks := make([]hookStatus, 0, len(status))
-	installed := 0
-	for hook, isInstalled := range status {
-		hooks = append(hooks, hookStatus{Name: hook, Installed: isInstalled})
-		if isInstalled {
-			installed++
-		}
+	result := checkResult{
+		RepoPath:   repoRoot,
+		HooksPath:  hooksPath,
+		Status:     output.CheckPass,
+		Hooks:      make([]hookStatus, 0, len(status)),
+		TotalCount: len(status),
 	}
 
-	result := checkResult{
-		RepoPath:       repoRoot,
-		HooksPath:      hooksPath,
-		Hooks:          hooks,
-		InstalledCount: installed,
-		TotalCount:     len(status),
-		AllInstalled:   installed == len(status),
+	for _, hook := range sortedHooks(status) {
+		h := hookStatus{ID: "hooks." + hook, Name: hook, Installed: status[hook], Status: output.CheckPass}
+		switch {
+		case !h.Installed:
+			h.Status = output.CheckFail
+			h.Hint = "fp setup"
+			result.Status = output.CheckFail
+		case slices.Contains(outdated, hook):
+			h.Outdated = true
+			h.Status = output.CheckWarn
+			h.Hint = "fp doctor --fix"
+			if result.Status == output.CheckPass {
+				result.Status = output.CheckWarn
+			}
+		}
+		if h.Installed {
+			result.InstalledCount++
+		}
+		h.Severity = output.Severity(h.Status)
+		result.Hooks = append(result.Hooks, h)
 	}
+	result.AllInstalled = result.InstalledCount == result.TotalCount
+	result.Severity = output.Severity(result.Status)
 
 	return output.JSON(deps.Println, result)
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/footprint-tools/cli/internal/git"
 	"github.com/footprint-tools/cli/internal/hooks"
+	"github.com/footprint-tools/cli/internal/store"
 	"github.com/footprint-tools/cli/internal/ui"
 	"golang.org/x/term"
 )
@@ -13,13 +14,30 @@ import (
 type Deps struct {
 	// git
 	RepoRoot        func(string) (string, error)
+	BareRepoDir     func(string) (string, error)
 	RepoHooksPath   func(string) (string, error)
 	GlobalHooksPath func() (string, error)
 
 	// hooks
-	HooksStatus    func(string) map[string]bool
-	HooksInstall   func(string) error
-	HooksUninstall func(string) error
+	HooksStatus         func(string) map[string]bool
+	HooksInstall        func(string) error
+	HooksInstallChained func(string) error
+	HooksUninstall      func(string) error
+
+	// server hooks, for bare repositories
+	ServerHooksStatus         func(string) map[string]bool
+	ServerHooksInstall        func(string) error
+	ServerHooksInstallChained func(string) error
+	ServerHooksUninstall      func(string) error
+
+	// store
+	DBPath            func() string
+	InitStore         func(string) error
+	EncryptStore      func(passphrase string) error
+	UnlockStore       func() error
+	LockStore         func() error
+	StoreEncrypted    func() bool
+	KeychainAvailable func() bool
 
 	// io
 	Printf     func(string, ...any) (int, error)
@@ -27,17 +45,35 @@ type Deps struct {
 	Print      func(...any) (int, error)
 	Scanln     func(...any) (int, error)
 	IsStdinTTY func() bool
+	// ReadPassword reads a passphrase without echoing it
+	ReadPassword func(string) (string, error)
+	Getenv       func(string) string
 }
 
 func DefaultDeps() Deps {
 	return Deps{
 		RepoRoot:        git.RepoRoot,
+		BareRepoDir:     git.BareRepoDir,
 		RepoHooksPath:   git.RepoHooksPath,
 		GlobalHooksPath: git.GlobalHooksPath,
 
-		HooksStatus:    hooks.Status,
-		HooksInstall:   hooks.Install,
-		HooksUninstall: hooks.Uninstall,
+		HooksStatus:         hooks.Status,
+		HooksInstall:        hooks.Install,
+		HooksInstallChained: hooks.InstallChained,
+		HooksUninstall:      hooks.Uninstall,
+
+		ServerHooksStatus:         hooks.ServerStatus,
+		ServerHooksInstall:        hooks.InstallServer,
+		ServerHooksInstallChained: hooks.InstallServerChained,
+		ServerHooksUninstall:      hooks.UninstallServer,
+
+		DBPath:            store.DBPath,
+		InitStore:         initStore,
+		EncryptStore:      store.Encrypt,
+		UnlockStore:       store.Unlock,
+		LockStore:         store.Lock,
+		StoreEncrypted:    store.IsEncrypted,
+		KeychainAvailable: store.KeychainAvailable,
 
 		Printf:  ui.Printf,
 		Println: ui.Println,
@@ -46,5 +82,7 @@ func DefaultDeps() Deps {
 		IsStdinTTY: func() bool {
 			return term.IsTerminal(int(os.Stdin.Fd()))
 		},
+		ReadPassword: ui.ReadPassword,
+		Getenv:       os.Getenv,
 	}
 }
@@ -0,0 +1,128 @@
+package setup
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// minPassphraseLength is the shortest passphrase fp init --encrypt takes.
+const minPassphraseLength = 8
+
+// Init creates the database, encrypting it with --encrypt.
+func Init(args []string, flags *dispatchers.ParsedFlags) error {
+	return initStoreCommand(args, flags, DefaultDeps())
+}
+
+func initStoreCommand(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	if !flags.Has("--encrypt") {
+		if err := deps.InitStore(deps.DBPath()); err != nil {
+			return fmt.Errorf("could not create database: %w", err)
+		}
+		_, _ = deps.Printf("database ready at %s\n", deps.DBPath())
+		return nil
+	}
+
+	if deps.StoreEncrypted() {
+		_, _ = deps.Println("the database is already encrypted")
+		return nil
+	}
+
+	usePassphrase := flags.Has("--passphrase")
+	if !usePassphrase && !deps.KeychainAvailable() {
+		_, _ = deps.Println("no OS keychain available, using a passphrase instead")
+		usePassphrase = true
+	}
+
+	passphrase := ""
+	if usePassphrase {
+		var err error
+		if passphrase, err = readNewPassphrase(deps); err != nil {
+			return err
+		}
+	}
+
+	if err := deps.EncryptStore(passphrase); err != nil {
+		return fmt.Errorf("could not encrypt the database: %w", err)
+	}
+
+	if passphrase == "" {
+		_, _ = deps.Println("database encrypted, with its key in the OS keychain")
+	} else {
+		_, _ = deps.Println("database encrypted with your passphrase")
+		_, _ = deps.Printf("hooks can't record while it is locked: run 'fp unlock' after logging in, or set %s for them\n", store.PassphraseEnv)
+	}
+	_, _ = deps.Printf("fp works on an unlocked copy at %s; 'fp lock' removes it\n", deps.DBPath())
+	return nil
+}
+
+// readNewPassphrase takes the passphrase from FP_DB_PASSPHRASE, or asks
+// for it twice at the terminal.
+func readNewPassphrase(deps Deps) (string, error) {
+	passphrase := deps.Getenv(store.PassphraseEnv)
+	if passphrase == "" {
+		if !deps.IsStdinTTY() {
+			return "", fmt.Errorf("a passphrase is needed and stdin is not a terminal\nSet %s, or run interactively", store.PassphraseEnv)
+		}
+		var err error
+		if passphrase, err = deps.ReadPassword("New passphrase: "); err != nil {
+			return "", err
+		}
+		again, err := deps.ReadPassword("Repeat passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", errors.New("the passphrases don't match")
+		}
+	}
+	if len(passphrase) < minPassphraseLength {
+		return "", fmt.Errorf("the passphrase must be at least %d characters", minPassphraseLength)
+	}
+	return passphrase, nil
+}
+
+// Unlock decrypts an encrypted database so hooks can record to it.
+func Unlock(args []string, flags *dispatchers.ParsedFlags) error {
+	return unlock(args, flags, DefaultDeps())
+}
+
+func unlock(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	if !deps.StoreEncrypted() {
+		_, _ = deps.Println("the database is not encrypted")
+		return nil
+	}
+	if err := deps.UnlockStore(); err != nil {
+		return err
+	}
+	_, _ = deps.Println("database unlocked")
+	return nil
+}
+
+// Lock seals an encrypted database and removes its unlocked copy.
+func Lock(args []string, flags *dispatchers.ParsedFlags) error {
+	return lock(args, flags, DefaultDeps())
+}
+
+func lock(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	if !deps.StoreEncrypted() {
+		_, _ = deps.Println("the database is not encrypted")
+		return nil
+	}
+	if err := deps.LockStore(); err != nil {
+		return err
+	}
+	_, _ = deps.Println("database locked")
+	return nil
+}
+
+// initStore creates the database at path, or migrates it.
+func initStore(path string) error {
+	s, err := store.New(path)
+	if err != nil {
+		return err
+	}
+	return s.Close()
+}
@@ -0,0 +1,139 @@
+package setup
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+)
+
+// initTestDeps records what was printed and the passphrase the store was
+// encrypted with.
+func initTestDeps(printed *strings.Builder, encryptedWith *string) Deps {
+	encrypted := false
+	return Deps{
+		DBPath:    func() string { return "/data/store.db" },
+		InitStore: func(string) error { return nil },
+		EncryptStore: func(passphrase string) error {
+			*encryptedWith = passphrase
+			encrypted = true
+			return nil
+		},
+		StoreEncrypted:    func() bool { return encrypted },
+		KeychainAvailable: func() bool { return true },
+		Getenv:            func(string) string { return "" },
+		IsStdinTTY:        func() bool { return true },
+		Printf: func(format string, a ...any) (int, error) {
+			return fmt.Fprintf(printed, format, a...)
+		},
+		Println: func(a ...any) (int, error) {
+			return fmt.Fprintln(printed, a...)
+		},
+	}
+}
+
+func TestInit_CreatesDatabase(t *testing.T) {
+	var printed strings.Builder
+	var passphrase string
+	deps := initTestDeps(&printed, &passphrase)
+	created := ""
+	deps.InitStore = func(path string) error { created = path; return nil }
+
+	require.NoError(t, initStoreCommand(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, "/data/store.db", created)
+	require.Contains(t, printed.String(), "database ready at /data/store.db")
+}
+
+func TestInit_EncryptWithKeychain(t *testing.T) {
+	var printed strings.Builder
+	passphrase := "unset"
+	deps := initTestDeps(&printed, &passphrase)
+
+	flags := dispatchers.NewParsedFlags([]string{"--encrypt"})
+	require.NoError(t, initStoreCommand(nil, flags, deps))
+	require.Empty(t, passphrase, "the key goes in the keychain")
+	require.Contains(t, printed.String(), "OS keychain")
+
+	printed.Reset()
+	require.NoError(t, initStoreCommand(nil, flags, deps))
+	require.Contains(t, printed.String(), "already encrypted")
+}
+
+func TestInit_EncryptWithPassphrase(t *testing.T) {
+	tests := []struct {
+		name      string
+		flags     []string
+		keychain  bool
+		answers   []string
+		wantError string
+	}{
+		{name: "asked for", flags: []string{"--encrypt", "--passphrase"}, keychain: true, answers: []string{"correct horse", "correct horse"}},
+		{name: "no keychain", flags: []string{"--encrypt"}, answers: []string{"correct horse", "correct horse"}},
+		{name: "mismatch", flags: []string{"--encrypt", "--passphrase"}, answers: []string{"correct horse", "battery staple"}, wantError: "don't match"},
+		{name: "too short", flags: []string{"--encrypt", "--passphrase"}, answers: []string{"short", "short"}, wantError: "at least 8 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var printed strings.Builder
+			var passphrase string
+			deps := initTestDeps(&printed, &passphrase)
+			deps.KeychainAvailable = func() bool { return tt.keychain }
+			answers := tt.answers
+			deps.ReadPassword = func(string) (string, error) {
+				answer := answers[0]
+				answers = answers[1:]
+				return answer, nil
+			}
+
+			err := initStoreCommand(nil, dispatchers.NewParsedFlags(tt.flags), deps)
+			if tt.wantError != "" {
+				require.ErrorContains(t, err, tt.wantError)
+				require.Empty(t, passphrase, "nothing is encrypted")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "correct horse", passphrase)
+			require.Contains(t, printed.String(), "fp unlock")
+		})
+	}
+}
+
+func TestInit_PassphraseWithoutTerminal(t *testing.T) {
+	var printed strings.Builder
+	var passphrase string
+	deps := initTestDeps(&printed, &passphrase)
+	deps.IsStdinTTY = func() bool { return false }
+	flags := dispatchers.NewParsedFlags([]string{"--encrypt", "--passphrase"})
+
+	require.ErrorContains(t, initStoreCommand(nil, flags, deps), "stdin is not a terminal")
+
+	deps.Getenv = func(key string) string { return map[string]string{"FP_DB_PASSPHRASE": "from the env"}[key] }
+	require.NoError(t, initStoreCommand(nil, flags, deps))
+	require.Equal(t, "from the env", passphrase)
+}
+
+func TestLockUnlock(t *testing.T) {
+	var printed strings.Builder
+	var passphrase string
+	deps := initTestDeps(&printed, &passphrase)
+	var calls []string
+	deps.LockStore = func() error { calls = append(calls, "lock"); return nil }
+	deps.UnlockStore = func() error {
+		calls = append(calls, "unlock")
+		return errors.New("wrong passphrase for the encrypted database")
+	}
+
+	require.NoError(t, lock(nil, nil, deps))
+	require.NoError(t, unlock(nil, nil, deps))
+	require.Empty(t, calls, "nothing to do for a database that isn't encrypted")
+	require.Contains(t, printed.String(), "not encrypted")
+
+	deps.StoreEncrypted = func() bool { return true }
+	require.NoError(t, lock(nil, nil, deps))
+	require.ErrorContains(t, unlock(nil, nil, deps), "wrong passphrase")
+	require.Equal(t, []string{"lock", "unlock"}, calls)
+}
@@ -16,15 +16,25 @@ func Setup(args []string, flags *dispatchers.ParsedFlags) error {
 }
 
 func setup(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
-	if flags.Has("--core-hooks-path") {
+	if isGlobal(flags) {
+		if flags.Has("--chain") {
+			return usage.ConflictingFlags("--global", "--chain")
+		}
 		return setupGlobal(flags, deps)
 	}
 	return setupLocal(args, flags, deps)
 }
 
+// isGlobal reports whether --global, or its older name --core-hooks-path,
+// was given.
+func isGlobal(flags *dispatchers.ParsedFlags) bool {
+	return flags.Has("--global") || flags.Has("--core-hooks-path")
+}
+
 func setupLocal(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	force := flags.Has("--force")
 	dryRun := flags.Has("--dry-run")
+	chain := flags.Has("--chain")
 
 	// Determine target path
 	targetPath := "."
@@ -32,18 +42,14 @@ func setupLocal(args []string, flags *dispatchers.ParsedFlags, deps Deps) error
 		targetPath = args[0]
 	}
 
-	root, err := deps.RepoRoot(targetPath)
-	if err != nil {
-		return usage.NotInGitRepo()
-	}
-
-	hooksPath, err := deps.RepoHooksPath(root)
+	target, err := resolveTarget(targetPath, deps)
 	if err != nil {
 		return err
 	}
+	root, hooksPath, managed := target.root, target.hooksPath, target.hooks
 
 	// Check existing hooks before install
-	statusBefore := deps.HooksStatus(hooksPath)
+	statusBefore := target.status(hooksPath)
 	backedUp := 0
 	for _, installed := range statusBefore {
 		if installed {
@@ -54,13 +60,32 @@ func setupLocal(args []string, flags *dispatchers.ParsedFlags, deps Deps) error
 	if dryRun {
 		_, _ = deps.Println("dry-run: would install hooks to:")
 		_, _ = deps.Printf("  %s\n", hooksPath)
-		_, _ = deps.Printf("  hooks: %s\n", strings.Join(hooks.ManagedHooks, ", "))
-		if backedUp > 0 {
+		_, _ = deps.Printf("  hooks: %s\n", strings.Join(managed, ", "))
+		switch {
+		case backedUp > 0 && chain:
+			_, _ = deps.Printf("  %d existing hooks would be chained (they keep running before fp)\n", backedUp)
+		case backedUp > 0:
 			_, _ = deps.Printf("  %d existing hooks would be backed up\n", backedUp)
 		}
 		return nil
 	}
 
+	// Chaining keeps the existing hooks running, so there is nothing to confirm
+	if chain {
+		if err := target.installChained(hooksPath); err != nil {
+			return err
+		}
+		addRepoToStore(root)
+
+		if backedUp > 0 {
+			_, _ = deps.Printf("installed %d hooks (%d chained to existing hooks)\n", len(managed), backedUp)
+		} else {
+			_, _ = deps.Printf("installed %d hooks\n", len(managed))
+		}
+		_, _ = deps.Printf("  %s\n", strings.Join(managed, ", "))
+		return nil
+	}
+
 	if backedUp > 0 && !force {
 		// Check if stdin is a TTY - if not, require --force flag
 		if !deps.IsStdinTTY() {
@@ -68,7 +93,7 @@ func setupLocal(args []string, flags *dispatchers.ParsedFlags, deps Deps) error
 		}
 
 		_, _ = deps.Println("fp detected existing git hooks")
-		_, _ = deps.Println("they will be backed up and replaced")
+		_, _ = deps.Println("they will be backed up and replaced (use --chain to keep them running)")
 		_, _ = deps.Print("continue? [y/N]: ")
 
 		var resp string
@@ -78,7 +103,7 @@ func setupLocal(args []string, flags *dispatchers.ParsedFlags, deps Deps) error
 		}
 	}
 
-	if err := deps.HooksInstall(hooksPath); err != nil {
+	if err := target.install(hooksPath); err != nil {
 		return err
 	}
 
@@ -86,15 +111,61 @@ func setupLocal(args []string, flags *dispatchers.ParsedFlags, deps Deps) error
 	addRepoToStore(root)
 
 	if backedUp > 0 {
-		_, _ = deps.Printf("installed %d hooks (%d backed up)\n", len(hooks.ManagedHooks), backedUp)
+		_, _ = deps.Printf("installed %d hooks (%d backed up)\n", len(managed), backedUp)
 	} else {
-		_, _ = deps.Printf("installed %d hooks\n", len(hooks.ManagedHooks))
+		_, _ = deps.Printf("installed %d hooks\n", len(managed))
 	}
-	_, _ = deps.Printf("  %s\n", strings.Join(hooks.ManagedHooks, ", "))
+	_, _ = deps.Printf("  %s\n", strings.Join(managed, ", "))
 
 	return nil
 }
 
+// hookTarget is the repository setup and teardown act on, with the hooks
+// it takes: the managed hooks for a working tree, the server hooks for a
+// bare repository.
+type hookTarget struct {
+	root           string
+	hooksPath      string
+	hooks          []string
+	status         func(string) map[string]bool
+	install        func(string) error
+	installChained func(string) error
+	uninstall      func(string) error
+}
+
+// resolveTarget finds the repository at path, falling back to a bare
+// repository when there is no working tree, as on a git server.
+func resolveTarget(path string, deps Deps) (hookTarget, error) {
+	target := hookTarget{
+		hooks:          hooks.ManagedHooks,
+		status:         deps.HooksStatus,
+		install:        deps.HooksInstall,
+		installChained: deps.HooksInstallChained,
+		uninstall:      deps.HooksUninstall,
+	}
+
+	root, err := deps.RepoRoot(path)
+	if err != nil {
+		if deps.BareRepoDir == nil {
+			return hookTarget{}, usage.NotInGitRepo()
+		}
+		if root, err = deps.BareRepoDir(path); err != nil {
+			return hookTarget{}, usage.NotInGitRepo()
+		}
+		target.hooks = hooks.ServerHooks
+		target.status = deps.ServerHooksStatus
+		target.install = deps.ServerHooksInstall
+		target.installChained = deps.ServerHooksInstallChained
+		target.uninstall = deps.ServerHooksUninstall
+	}
+	target.root = root
+
+	if target.hooksPath, err = deps.RepoHooksPath(root); err != nil {
+		return hookTarget{}, err
+	}
+	return target, nil
+}
+
 func setupGlobal(flags *dispatchers.ParsedFlags, deps Deps) error {
 	force := flags.Has("--force")
 	dryRun := flags.Has("--dry-run")
@@ -129,7 +200,7 @@ func setupGlobal(flags *dispatchers.ParsedFlags, deps Deps) error {
 	_, _ = deps.Println("What this means:")
 	_, _ = deps.Println("  - Repos WITHOUT local core.hooksPath will use fp automatically")
 	_, _ = deps.Println("  - No need to run 'fp setup' in each of those repositories")
-	_, _ = deps.Println("  - Local .git/hooks/ directories will be IGNORED by Git")
+	_, _ = deps.Println("  - Hooks in a repo's .git/hooks/ keep running: fp's hooks call them")
 	_, _ = deps.Println("")
 	_, _ = deps.Println("⚠  LIMITATION:")
 	_, _ = deps.Println("  Repos with LOCAL core.hooksPath (Husky, etc.) will NOT be affected.")
@@ -146,13 +217,15 @@ func setupGlobal(flags *dispatchers.ParsedFlags, deps Deps) error {
 		if status.IsFpManaged {
 			_, _ = deps.Println("   The existing hooks appear to be fp hooks.")
 			_, _ = deps.Println("   This will reinstall/update them.")
-		} else if status.HasOtherHooks {
-			_, _ = deps.Println("   ⚠  EXISTING HOOKS WILL BE OVERWRITTEN:")
-			for _, h := range status.OtherHooks {
-				_, _ = deps.Printf("      - %s\n", h)
+		} else {
+			if status.HasOtherHooks {
+				_, _ = deps.Println("   ⚠  THESE HOOKS WILL STOP RUNNING:")
+				for _, h := range status.OtherHooks {
+					_, _ = deps.Printf("      - %s\n", h)
+				}
+				_, _ = deps.Println("")
 			}
-			_, _ = deps.Println("")
-			_, _ = deps.Println("   These hooks will be backed up but may stop working.")
+			_, _ = deps.Println("   fp remembers this path, and 'fp teardown --global' sets it back.")
 		}
 		_, _ = deps.Println("")
 	}
@@ -1,8 +1,10 @@
 package setup
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -65,6 +67,46 @@ func TestSetup_NotInGitRepo(t *testing.T) {
 	require.Contains(t, err.Error(), "git repo")
 }
 
+func TestSetup_BareRepo(t *testing.T) {
+	var installedPath string
+	var printedLines []string
+	deps := Deps{
+		RepoRoot: func(path string) (string, error) {
+			return "", errors.New("not a git repo")
+		},
+		BareRepoDir: func(path string) (string, error) {
+			return "/srv/git/repo.git", nil
+		},
+		RepoHooksPath: func(root string) (string, error) {
+			return root + "/hooks", nil
+		},
+		ServerHooksStatus: func(path string) map[string]bool {
+			return map[string]bool{"post-receive": false}
+		},
+		HooksInstall: func(path string) error {
+			t.Fatal("installed client hooks in a bare repository")
+			return nil
+		},
+		ServerHooksInstall: func(path string) error {
+			installedPath = path
+			return nil
+		},
+		Printf: func(format string, a ...any) (int, error) {
+			printedLines = append(printedLines, fmt.Sprintf(format, a...))
+			return 0, nil
+		},
+		Println: func(a ...any) (int, error) {
+			return 0, nil
+		},
+	}
+
+	err := setup([]string{}, dispatchers.NewParsedFlags([]string{}), deps)
+
+	require.NoError(t, err)
+	require.Equal(t, "/srv/git/repo.git/hooks", installedPath)
+	require.Equal(t, []string{"installed 1 hooks\n", "  post-receive\n"}, printedLines)
+}
+
 func TestSetup_ExistingHooksWithConfirmation(t *testing.T) {
 	var installedPath string
 	deps := Deps{
@@ -197,6 +239,103 @@ func TestSetup_ExistingHooksWithForce(t *testing.T) {
 	require.False(t, scanlnCalled, "should not prompt with --force")
 }
 
+func TestSetup_ChainKeepsExistingHooksWithoutPrompting(t *testing.T) {
+	var chainedPath string
+	var printed []string
+	deps := Deps{
+		RepoRoot: func(path string) (string, error) {
+			return "/path/to/repo", nil
+		},
+		RepoHooksPath: func(root string) (string, error) {
+			return "/path/to/repo/.git/hooks", nil
+		},
+		HooksStatus: func(path string) map[string]bool {
+			return map[string]bool{"post-commit": true, "pre-push": true}
+		},
+		HooksInstall: func(path string) error {
+			t.Fatal("plain install should not run with --chain")
+			return nil
+		},
+		HooksInstallChained: func(path string) error {
+			chainedPath = path
+			return nil
+		},
+		Printf: func(format string, a ...any) (int, error) {
+			printed = append(printed, fmt.Sprintf(format, a...))
+			return 0, nil
+		},
+		Scanln: func(a ...any) (int, error) {
+			t.Fatal("should not prompt with --chain")
+			return 0, nil
+		},
+		IsStdinTTY: func() bool { return false },
+	}
+
+	err := setup([]string{}, dispatchers.NewParsedFlags([]string{"--chain"}), deps)
+
+	require.NoError(t, err)
+	require.Equal(t, "/path/to/repo/.git/hooks", chainedPath)
+	require.Contains(t, printed[0], "2 chained to existing hooks")
+}
+
+func TestSetup_ChainDryRun(t *testing.T) {
+	var printed []string
+	deps := Deps{
+		RepoRoot: func(path string) (string, error) {
+			return "/path/to/repo", nil
+		},
+		RepoHooksPath: func(root string) (string, error) {
+			return "/path/to/repo/.git/hooks", nil
+		},
+		HooksStatus: func(path string) map[string]bool {
+			return map[string]bool{"post-commit": true}
+		},
+		Printf: func(format string, a ...any) (int, error) {
+			printed = append(printed, fmt.Sprintf(format, a...))
+			return 0, nil
+		},
+		Println: func(a ...any) (int, error) {
+			return 0, nil
+		},
+	}
+
+	err := setup([]string{}, dispatchers.NewParsedFlags([]string{"--chain", "--dry-run"}), deps)
+
+	require.NoError(t, err)
+	require.Contains(t, strings.Join(printed, ""), "1 existing hooks would be chained")
+}
+
+func TestSetup_ChainWithGlobal(t *testing.T) {
+	for _, flag := range []string{"--global", "--core-hooks-path"} {
+		err := setup([]string{}, dispatchers.NewParsedFlags([]string{"--chain", flag}), Deps{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot be used together")
+	}
+}
+
+func TestSetup_GlobalDryRun(t *testing.T) {
+	var printed []string
+	deps := Deps{
+		RepoRoot: func(path string) (string, error) {
+			t.Fatal("--global should not look for a repository")
+			return "", nil
+		},
+		Printf: func(format string, a ...any) (int, error) {
+			printed = append(printed, fmt.Sprintf(format, a...))
+			return 0, nil
+		},
+		Println: func(a ...any) (int, error) {
+			printed = append(printed, fmt.Sprintln(a...))
+			return 0, nil
+		},
+	}
+
+	err := setup([]string{}, dispatchers.NewParsedFlags([]string{"--global", "--dry-run"}), deps)
+
+	require.NoError(t, err)
+	require.Contains(t, strings.Join(printed, ""), "would install global hooks")
+}
+
 func TestSetup_RepoHooksPathError(t *testing.T) {
 	deps := Deps{
 		RepoRoot: func(path string) (string, error) {
@@ -295,6 +434,33 @@ func TestTeardown_NotInGitRepo(t *testing.T) {
 	require.Contains(t, err.Error(), "git repo")
 }
 
+func TestTeardown_BareRepo(t *testing.T) {
+	var uninstalledPath string
+	deps := Deps{
+		RepoRoot: func(path string) (string, error) {
+			return "", errors.New("not a git repo")
+		},
+		BareRepoDir: func(path string) (string, error) {
+			return "/srv/git/repo.git", nil
+		},
+		RepoHooksPath: func(root string) (string, error) {
+			return root + "/hooks", nil
+		},
+		ServerHooksUninstall: func(path string) error {
+			uninstalledPath = path
+			return nil
+		},
+		Println: func(a ...any) (int, error) {
+			return 0, nil
+		},
+	}
+
+	err := teardown([]string{}, dispatchers.NewParsedFlags([]string{"--force"}), deps)
+
+	require.NoError(t, err)
+	require.Equal(t, "/srv/git/repo.git/hooks", uninstalledPath)
+}
+
 func TestTeardown_Declined(t *testing.T) {
 	uninstallCalled := false
 	deps := Deps{
@@ -499,3 +665,41 @@ func containsStr(s, substr string) bool {
 	}
 	return false
 }
+
+func TestCheck_JSONIsOrderedWithSeverities(t *testing.T) {
+	var out strings.Builder
+	deps := Deps{
+		RepoRoot:      func(string) (string, error) { return "/path/to/repo", nil },
+		RepoHooksPath: func(string) (string, error) { return "/path/to/repo/.git/hooks", nil },
+		HooksStatus: func(string) map[string]bool {
+			return map[string]bool{"pre-push": false, "post-merge": true, "post-commit": true}
+		},
+		Println: func(a ...any) (int, error) { return fmt.Fprintln(&out, a...) },
+	}
+
+	err := check(nil, dispatchers.NewParsedFlags([]string{"--json"}), deps)
+	require.NoError(t, err)
+
+	var result struct {
+		Status   string `json:"status"`
+		Severity string `json:"severity"`
+		Hooks    []struct {
+			ID       string `json:"id"`
+			Status   string `json:"status"`
+			Severity string `json:"severity"`
+			Hint     string `json:"hint"`
+		} `json:"hooks"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &result))
+
+	require.Equal(t, "fail", result.Status)
+	require.Equal(t, "error", result.Severity)
+	require.Len(t, result.Hooks, 3)
+	require.Equal(t, "hooks.post-commit", result.Hooks[0].ID)
+	require.Equal(t, "hooks.post-merge", result.Hooks[1].ID)
+	require.Equal(t, "hooks.pre-push", result.Hooks[2].ID)
+	require.Equal(t, "pass", result.Hooks[0].Status)
+	require.Equal(t, "info", result.Hooks[0].Severity)
+	require.Equal(t, "fail", result.Hooks[2].Status)
+	require.Equal(t, "fp setup", result.Hooks[2].Hint)
+}
@@ -6,7 +6,6 @@ import (
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/hooks"
 	"github.com/footprint-tools/cli/internal/store"
-	"github.com/footprint-tools/cli/internal/usage"
 )
 
 func Teardown(args []string, flags *dispatchers.ParsedFlags) error {
@@ -14,7 +13,7 @@ func Teardown(args []string, flags *dispatchers.ParsedFlags) error {
 }
 
 func teardown(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
-	if flags.Has("--core-hooks-path") {
+	if isGlobal(flags) {
 		return teardownGlobal(flags, deps)
 	}
 	return teardownLocal(args, flags, deps)
@@ -30,15 +29,11 @@ func teardownLocal(args []string, flags *dispatchers.ParsedFlags, deps Deps) err
 		targetPath = args[0]
 	}
 
-	root, err := deps.RepoRoot(targetPath)
-	if err != nil {
-		return usage.NotInGitRepo()
-	}
-
-	hooksPath, err := deps.RepoHooksPath(root)
+	target, err := resolveTarget(targetPath, deps)
 	if err != nil {
 		return err
 	}
+	root, hooksPath := target.root, target.hooksPath
 
 	if dryRun {
 		_, _ = deps.Println("dry-run: would remove hooks from:")
@@ -64,7 +59,7 @@ func teardownLocal(args []string, flags *dispatchers.ParsedFlags, deps Deps) err
 		}
 	}
 
-	if err := deps.HooksUninstall(hooksPath); err != nil {
+	if err := target.uninstall(hooksPath); err != nil {
 		return err
 	}
 
@@ -86,16 +81,26 @@ func teardownGlobal(flags *dispatchers.ParsedFlags, deps Deps) error {
 		_, _ = deps.Println("No global hooks are configured (core.hooksPath is not set)")
 		return nil
 	}
+	previous := hooks.PreviousGlobalHooksPath(status.Path)
 
 	if dryRun {
 		_, _ = deps.Println("dry-run: would remove global hooks from:")
 		_, _ = deps.Printf("  %s\n", status.Path)
-		_, _ = deps.Println("  core.hooksPath would be unset")
+		if previous != "" {
+			_, _ = deps.Printf("  core.hooksPath would be set back to %s\n", previous)
+		} else {
+			_, _ = deps.Println("  core.hooksPath would be unset")
+		}
 		return nil
 	}
 
 	_, _ = deps.Println("")
-	_, _ = deps.Println("This will remove global fp hooks and unset core.hooksPath.")
+	if previous != "" {
+		_, _ = deps.Println("This will remove global fp hooks and set core.hooksPath back to")
+		_, _ = deps.Printf("%s, where it pointed before fp.\n", previous)
+	} else {
+		_, _ = deps.Println("This will remove global fp hooks and unset core.hooksPath.")
+	}
 	_, _ = deps.Println("")
 	_, _ = deps.Printf("Current hooks path: %s\n", status.Path)
 	_, _ = deps.Println("")
@@ -111,7 +116,9 @@ func teardownGlobal(flags *dispatchers.ParsedFlags, deps Deps) error {
 	}
 
 	_, _ = deps.Println("After removal:")
-	_, _ = deps.Println("  - Git will use local .git/hooks/ directories again")
+	if previous == "" {
+		_, _ = deps.Println("  - Git will use local .git/hooks/ directories again")
+	}
 	_, _ = deps.Println("  - New commits will NOT be tracked automatically")
 	_, _ = deps.Println("  - You'll need to run 'fp setup' in each repo to track it")
 	_, _ = deps.Println("")
@@ -139,7 +146,11 @@ func teardownGlobal(flags *dispatchers.ParsedFlags, deps Deps) error {
 
 	_, _ = deps.Println("")
 	_, _ = deps.Println("✓ Global hooks removed")
-	_, _ = deps.Println("  core.hooksPath has been unset")
+	if previous != "" {
+		_, _ = deps.Printf("  core.hooksPath is %s again\n", previous)
+	} else {
+		_, _ = deps.Println("  core.hooksPath has been unset")
+	}
 
 	return nil
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/paths"
+	"github.com/footprint-tools/cli/internal/ui"
 	"github.com/footprint-tools/cli/internal/ui/style"
 )
 
@@ -15,20 +17,46 @@ type Deps struct {
 	GetAll     func() (map[string]string, error)
 	Printf     func(string, ...any) (int, error)
 	Println    func(...any) (int, error)
-	ThemeNames []string
-	Themes     map[string]style.ColorConfig
+	// ColorEnabled reports whether output is styled (see style.Init).
+	ColorEnabled func() bool
+	ThemeNames   []string
+	Themes       map[string]style.ColorConfig
+	// ThemesDir is where custom theme files live (see style.LoadThemeFiles).
+	ThemesDir func() string
+	// Accessible reports whether the picker should be a numbered prompt
+	// instead of a TUI (see ui.IsAccessible), asked through NewPrompt.
+	Accessible func() bool
+	NewPrompt  func() *ui.LinearPrompt
 }
 
 func DefaultDeps() Deps {
 	return Deps{
-		ReadLines:  config.ReadLines,
-		WriteLines: config.WriteLines,
-		Set:        config.Set,
-		Get:        config.Get,
-		GetAll:     config.GetAll,
-		Printf:     fmt.Printf,
-		Println:    fmt.Println,
-		ThemeNames: style.ThemeNames, // All variants (dark/light) explicitly
-		Themes:     style.Themes,
+		ReadLines:    config.ReadLines,
+		WriteLines:   config.WriteLines,
+		Set:          config.Set,
+		Get:          config.Get,
+		GetAll:       config.GetAll,
+		Printf:       fmt.Printf,
+		Println:      fmt.Println,
+		ColorEnabled: style.Enabled,
+		ThemeNames:   style.AllThemeNames(), // All variants (dark/light) explicitly, then custom themes
+		Themes:       previewThemes(),
+		ThemesDir:    paths.ThemesDir,
+		Accessible:   ui.IsAccessible,
+		NewPrompt:    ui.NewTerminalPrompt,
 	}
 }
+
+// previewThemes returns the built-in and custom themes with the user's source_colors.*
+// overrides applied, so previews match what activity and watch will show.
+func previewThemes() map[string]style.ColorConfig {
+	cfg, err := config.GetAll()
+	if err != nil {
+		return style.Themes
+	}
+	themes := make(map[string]style.ColorConfig, len(style.Themes))
+	for name, theme := range style.Themes {
+		themes[name] = style.ApplySourceColors(theme, cfg)
+	}
+	return themes
+}
@@ -0,0 +1,37 @@
+package theme
+
+import (
+	"fmt"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+func Export(args []string, flags *dispatchers.ParsedFlags) error {
+	return exportTheme(args, flags, DefaultDeps())
+}
+
+// exportTheme prints a theme as a theme file, every color spelled out, so
+// it can be saved and shared with 'fp theme import'.
+func exportTheme(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) < 1 {
+		return usage.MissingArgument("theme")
+	}
+	if err := validateTheme(args[0], deps); err != nil {
+		return err
+	}
+
+	format := flags.String("--format", style.ThemeFormatTOML)
+	if format != style.ThemeFormatTOML && format != style.ThemeFormatJSON {
+		return fmt.Errorf("invalid format '%s': expected toml or json", format)
+	}
+
+	name := pickTheme(args[0], deps)
+	data, err := style.MarshalTheme(name, deps.Themes[name], format)
+	if err != nil {
+		return err
+	}
+	_, _ = deps.Printf("%s", data)
+	return nil
+}
@@ -0,0 +1,79 @@
+package theme
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+func Import(args []string, flags *dispatchers.ParsedFlags) error {
+	return importTheme(args, flags, DefaultDeps())
+}
+
+// importTheme checks a theme file and copies it into the themes directory,
+// where it is picked up as a custom theme. The theme is named after the
+// file unless --name is given.
+func importTheme(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) < 1 {
+		return usage.MissingArgument("file")
+	}
+	path := args[0]
+
+	format := style.ThemeFormat(path)
+	if format == "" {
+		return fmt.Errorf("%s is not a theme file: expected .toml or .json", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := style.ParseTheme(data, format); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	name := flags.String("--name", strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	if err := style.ValidateCustomThemeName(name); err != nil {
+		return err
+	}
+
+	dir := deps.ThemesDir()
+	existing := existingThemeFiles(dir, name)
+	if len(existing) > 0 && !flags.Has("--force") {
+		return fmt.Errorf("theme '%s' already exists (%s)\nUse --force to replace it, or --name to import it under another name", name, existing[0])
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	dest := filepath.Join(dir, name+"."+format)
+	if err := os.WriteFile(dest, data, 0600); err != nil {
+		return err
+	}
+	// A replaced theme may have been in the other format
+	for _, old := range existing {
+		if old != dest {
+			_ = os.Remove(old)
+		}
+	}
+
+	_, _ = deps.Printf("Imported theme %s (%s)\n", style.Success(name), dest)
+	_, _ = deps.Printf("Use 'fp theme set %s' to apply it\n", name)
+	return nil
+}
+
+// existingThemeFiles returns the files in dir that define the theme name.
+func existingThemeFiles(dir, name string) []string {
+	var files []string
+	for _, format := range []string{style.ThemeFormatTOML, style.ThemeFormatJSON} {
+		path := filepath.Join(dir, name+"."+format)
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
@@ -10,6 +10,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/ui"
 	"github.com/footprint-tools/cli/internal/ui/components"
 	"github.com/footprint-tools/cli/internal/ui/splitpanel"
 	"github.com/footprint-tools/cli/internal/ui/style"
@@ -30,15 +31,16 @@ func Interactive(args []string, flags *dispatchers.ParsedFlags) error {
 //
 
 func interactive(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	if deps.Accessible != nil && deps.Accessible() {
+		return interactiveLinear(deps)
+	}
+
 	// Hard guard: Bubble Tea REQUIRES a real terminal
 	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
 		return errors.New("theme picker requires an interactive terminal")
 	}
 
-	current, _ := deps.Get("theme")
-	if current == "" {
-		current = "default-dark"
-	}
+	current := currentTheme(deps)
 
 	cursor := 0
 	for i, name := range deps.ThemeNames {
@@ -57,7 +59,7 @@ func interactive(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 	}
 
 	p := tea.NewProgram(
-		m,
+		components.NewSizeGuard(components.NewHelpOverlay(m)),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(), // harmless, but stabilizes input
 	)
@@ -67,23 +69,10 @@ func interactive(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 		return err
 	}
 
-	fm := final.(model)
+	fm := final.(components.SizeGuard).Model.(components.HelpOverlay).Model.(model)
 
 	if fm.chosen != "" {
-		if fm.chosen == current {
-			_, _ = deps.Printf("\nTheme %s is already active\n", style.Info(fm.chosen))
-			return nil
-		}
-		lines, err := deps.ReadLines()
-		if err != nil {
-			return err
-		}
-		lines, _ = deps.Set(lines, "theme", fm.chosen)
-		if err := deps.WriteLines(lines); err != nil {
-			return err
-		}
-		_, _ = deps.Printf("\nTheme set to %s\n", style.Success(fm.chosen))
-		return nil
+		return applyChosenTheme(fm.chosen, current, deps)
 	}
 
 	if fm.cancelled {
@@ -93,6 +82,55 @@ func interactive(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 	return nil
 }
 
+// interactiveLinear is the picker in accessible mode: the themes as a
+// numbered list, the current one marked, and no preview.
+func interactiveLinear(deps Deps) error {
+	current := currentTheme(deps)
+	options := make([]string, len(deps.ThemeNames))
+	for i, name := range deps.ThemeNames {
+		options[i] = name
+		if name == current {
+			options[i] += " (current)"
+		}
+	}
+
+	i, err := deps.NewPrompt().Choose("Themes:", options)
+	if errors.Is(err, ui.ErrCancelled) {
+		_, _ = deps.Println("Cancelled")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return applyChosenTheme(deps.ThemeNames[i], current, deps)
+}
+
+func currentTheme(deps Deps) string {
+	current, _ := deps.Get("theme")
+	if current == "" {
+		current = "default-dark"
+	}
+	return current
+}
+
+// applyChosenTheme saves the theme picked, unless it is already active.
+func applyChosenTheme(chosen, current string, deps Deps) error {
+	if chosen == current {
+		_, _ = deps.Printf("\nTheme %s is already active\n", style.Info(chosen))
+		return nil
+	}
+	lines, err := deps.ReadLines()
+	if err != nil {
+		return err
+	}
+	lines, _ = deps.Set(lines, "theme", chosen)
+	if err := deps.WriteLines(lines); err != nil {
+		return err
+	}
+	_, _ = deps.Printf("\nTheme set to %s\n", style.Success(chosen))
+	return nil
+}
+
 //
 // Model
 //
@@ -342,6 +380,7 @@ func (m model) renderFooter() string {
 		key.NewBinding(key.WithKeys("j", "k"), key.WithHelp("jk", "nav")),
 		key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "select")),
 		key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+		key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "keys")),
 	}
 
 	footerStyle := lipgloss.NewStyle().
@@ -510,6 +549,12 @@ func buildDetailedPreview(name string, cfg style.ColorConfig, _ int) string {
 	b.WriteString(colorize("BACKFILL ", cfg.Color6) + mutedStyle.Render("• imported events"))
 	b.WriteString("\n")
 	b.WriteString(colorize("MANUAL ", cfg.Color7) + mutedStyle.Render("• manual records"))
+	b.WriteString("\n")
+	b.WriteString(colorize("BRANCH-CREATE ", cfg.Color8) + mutedStyle.Render("• new branches"))
+	b.WriteString("\n")
+	b.WriteString(colorize("BRANCH-DELETE ", cfg.Color9) + mutedStyle.Render("• deleted branches"))
+	b.WriteString("\n")
+	b.WriteString(colorize("STASH ", cfg.Color10) + mutedStyle.Render("• stash pushes"))
 	b.WriteString("\n\n")
 
 	// UI Examples section
@@ -675,6 +720,9 @@ func buildThemeDetailsLines(name string, cfg style.ColorConfig) []string {
 	lines = append(lines, source("PRE-PUSH", cfg.Color5))
 	lines = append(lines, source("BACKFILL", cfg.Color6))
 	lines = append(lines, source("MANUAL", cfg.Color7))
+	lines = append(lines, source("BRANCH-CREATE", cfg.Color8))
+	lines = append(lines, source("BRANCH-DELETE", cfg.Color9))
+	lines = append(lines, source("STASH", cfg.Color10))
 
 	return lines
 }
@@ -0,0 +1,56 @@
+package theme
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/footprint-tools/cli/internal/ui/components"
+)
+
+// keyMap is the keys of fp theme -i.
+type keyMap struct {
+	components.ListKeyMap
+	Apply  key.Binding
+	Scroll key.Binding
+	Top    key.Binding
+	Switch key.Binding
+	Quit   key.Binding
+}
+
+var keys = keyMap{
+	ListKeyMap: components.NewListKeyMap(),
+	Apply:      key.NewBinding(key.WithKeys("enter", " "), key.WithHelp("Enter/Space", "apply and quit")),
+	Scroll:     key.NewBinding(key.WithKeys("u", "d", "pgup", "pgdown"), key.WithHelp("u/d PgUp/PgDn", "scroll 5")),
+	Top:        key.NewBinding(key.WithKeys("g", "home"), key.WithHelp("g", "top")),
+	Switch:     key.NewBinding(key.WithKeys("tab", "h", "l"), key.WithHelp("Tab h/l", "switch between themes and preview")),
+	Quit:       key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("Esc/q", "quit without changing the theme")),
+}
+
+// HelpSections lists the keys of fp theme -i, the focused panel first.
+func (m model) HelpSections() []components.HelpSection {
+	themes := components.HelpSection{Title: "Themes", Bindings: []key.Binding{
+		components.Describe(keys.UpDown, "move, wrapping around"),
+		components.Describe(keys.Page, "move 5"),
+		keys.FirstLast,
+		keys.Apply,
+	}}
+	preview := components.HelpSection{Title: "Preview", Bindings: []key.Binding{
+		components.Describe(keys.UpDown, "scroll"),
+		keys.Scroll,
+		keys.Top,
+	}}
+	general := components.HelpSection{Title: "Everywhere", Bindings: []key.Binding{
+		keys.Switch,
+		keys.Help,
+		keys.Quit,
+	}}
+
+	if m.focusSidebar {
+		return []components.HelpSection{themes, preview, general}
+	}
+	return []components.HelpSection{preview, themes, general}
+}
+
+// CapturingInput is false: the theme picker has no text fields.
+func (m model) CapturingInput() bool {
+	return false
+}
@@ -17,6 +17,10 @@ func list(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 	}
 
 	_, _ = deps.Println("Available themes (* = current)\n")
+	if current == style.AutoTheme {
+		current = pickTheme(current, deps)
+		_, _ = deps.Printf("theme is auto, using %s for this terminal's background\n\n", current)
+	}
 
 	for _, name := range deps.ThemeNames {
 		marker := "  "
@@ -55,7 +59,10 @@ func renderColorPreview(cfg style.ColorConfig) string {
 		colorize("POST-MERGE ", cfg.Color4) +
 		colorize("PRE-PUSH ", cfg.Color5) +
 		colorize("BACKFILL ", cfg.Color6) +
-		colorize("MANUAL", cfg.Color7) +
+		colorize("MANUAL ", cfg.Color7) +
+		colorize("BRANCH-CREATE ", cfg.Color8) +
+		colorize("BRANCH-DELETE ", cfg.Color9) +
+		colorize("STASH", cfg.Color10) +
 		"   " +
 		colorize("UI-active ", cfg.UIActive) +
 		colorize("UI-dim", cfg.UIDim)
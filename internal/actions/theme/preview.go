@@ -0,0 +1,83 @@
+package theme
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+func Preview(args []string, flags *dispatchers.ParsedFlags) error {
+	return preview(args, flags, DefaultDeps())
+}
+
+// preview prints each color of a theme with its value. The swatches are
+// only drawn when colors are on: at a terminal, or with --force-color when
+// piped.
+func preview(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) < 1 {
+		return usage.MissingArgument("theme")
+	}
+	if err := validateTheme(args[0], deps); err != nil {
+		return err
+	}
+
+	name := pickTheme(args[0], deps)
+	cfg := deps.Themes[name]
+
+	_, _ = deps.Println(name)
+	for _, c := range themeColors(cfg) {
+		value := c.value
+		if value == "" {
+			value = "-"
+		}
+		line := fmt.Sprintf("  %-14s %s", c.label, value)
+		if deps.ColorEnabled() && c.value != "" {
+			line = fmt.Sprintf("  %-14s %-6s %s", c.label, value, colorSwatch(c.value))
+		}
+		_, _ = deps.Println(line)
+	}
+
+	return nil
+}
+
+type themeColor struct {
+	label string
+	value string
+}
+
+// themeColors lists the colors of a theme in the order 'fp theme -i' shows
+// them.
+func themeColors(cfg style.ColorConfig) []themeColor {
+	return []themeColor{
+		{"success", cfg.Success},
+		{"warning", cfg.Warning},
+		{"error", cfg.Error},
+		{"info", cfg.Info},
+		{"muted", cfg.Muted},
+		{"header", cfg.Header},
+		{"border", cfg.Border},
+		{"UI-active", cfg.UIActive},
+		{"UI-dim", cfg.UIDim},
+		{"POST-COMMIT", cfg.Color1},
+		{"POST-REWRITE", cfg.Color2},
+		{"POST-CHECKOUT", cfg.Color3},
+		{"POST-MERGE", cfg.Color4},
+		{"PRE-PUSH", cfg.Color5},
+		{"BACKFILL", cfg.Color6},
+		{"MANUAL", cfg.Color7},
+		{"BRANCH-CREATE", cfg.Color8},
+		{"BRANCH-DELETE", cfg.Color9},
+		{"STASH", cfg.Color10},
+	}
+}
+
+func colorSwatch(color string) string {
+	if color == "bold" {
+		return lipgloss.NewStyle().Bold(true).Render("sample")
+	}
+	return lipgloss.NewStyle().Background(lipgloss.Color(color)).Render("      ") + " " +
+		lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render("sample")
+}
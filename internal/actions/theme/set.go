@@ -2,6 +2,8 @@ package theme
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/ui/style"
@@ -18,16 +20,8 @@ func setTheme(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 	}
 
 	themeName := args[0]
-
-	// Validate theme exists
-	if _, ok := deps.Themes[themeName]; !ok {
-		_, _ = deps.Printf("%s unknown theme: %s\n", style.Error("error:"), themeName)
-		_, _ = deps.Println("")
-		_, _ = deps.Println("available themes:")
-		for _, name := range deps.ThemeNames {
-			_, _ = deps.Printf("  %s\n", name)
-		}
-		return fmt.Errorf("unknown theme: %s", themeName)
+	if err := validateTheme(themeName, deps); err != nil {
+		return err
 	}
 
 	lines, err := deps.ReadLines()
@@ -42,6 +36,29 @@ func setTheme(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 	}
 
 	_, _ = deps.Printf("theme set to %s\n", style.Success(themeName))
+	if themeName == style.AutoTheme {
+		_, _ = deps.Println("Set theme_dark and theme_light to choose the theme for each background")
+	}
 
 	return nil
 }
+
+// validateTheme accepts a theme variant (neon-dark), a base name (neon),
+// which picks its variant from the terminal background when colors load,
+// or auto. The error lists the themes, so scripts see them on stderr.
+func validateTheme(name string, deps Deps) error {
+	if _, ok := deps.Themes[name]; ok || slices.Contains(style.BaseThemeNames, name) || name == style.AutoTheme {
+		return nil
+	}
+	return fmt.Errorf("unknown theme: %s\n\navailable themes:\n  %s", name, strings.Join(deps.ThemeNames, "\n  "))
+}
+
+// pickTheme returns the theme name resolves to now, so auto shows the
+// theme it picks for this terminal.
+func pickTheme(name string, deps Deps) string {
+	var cfg map[string]string
+	if name == style.AutoTheme && deps.GetAll != nil {
+		cfg, _ = deps.GetAll()
+	}
+	return style.PickTheme(name, cfg)
+}
@@ -1,14 +1,19 @@
 package theme
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/require"
 
 	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/ui"
 	"github.com/footprint-tools/cli/internal/ui/style"
 )
 
@@ -156,6 +161,101 @@ func TestSet_UnknownTheme(t *testing.T) {
 	require.Contains(t, err.Error(), "unknown theme")
 }
 
+func TestSet_BaseName(t *testing.T) {
+	var written []string
+	deps := Deps{
+		ReadLines:  func() ([]string, error) { return []string{}, nil },
+		WriteLines: func(lines []string) error { written = lines; return nil },
+		Set: func(lines []string, key, value string) ([]string, bool) {
+			return append(lines, key+"="+value), false
+		},
+		Printf:     func(format string, a ...any) (int, error) { return 0, nil },
+		ThemeNames: []string{"neon-dark", "neon-light"},
+		Themes:     map[string]style.ColorConfig{"neon-dark": {}, "neon-light": {}},
+	}
+
+	require.NoError(t, setTheme([]string{"neon"}, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{"theme=neon"}, written)
+
+	err := setTheme([]string{"neon-dim"}, dispatchers.NewParsedFlags(nil), deps)
+	require.EqualError(t, err, "unknown theme: neon-dim\n\navailable themes:\n  neon-dark\n  neon-light")
+}
+
+// =========== PREVIEW TESTS ===========
+
+func previewDeps(printed *strings.Builder, colors bool) Deps {
+	return Deps{
+		Printf: func(format string, a ...any) (int, error) {
+			return fmt.Fprintf(printed, format, a...)
+		},
+		Println: func(a ...any) (int, error) {
+			return fmt.Fprintln(printed, a...)
+		},
+		ColorEnabled: func() bool { return colors },
+		ThemeNames:   []string{"ocean-dark"},
+		Themes: map[string]style.ColorConfig{
+			"ocean-dark": {Success: "10", Header: "bold", Color5: "33"},
+		},
+	}
+}
+
+func TestPreview_Piped(t *testing.T) {
+	var printed strings.Builder
+	err := preview([]string{"ocean-dark"}, dispatchers.NewParsedFlags(nil), previewDeps(&printed, false))
+
+	require.NoError(t, err)
+	out := printed.String()
+	require.True(t, strings.HasPrefix(out, "ocean-dark\n"))
+	require.Contains(t, out, "  success        10\n")
+	require.Contains(t, out, "  PRE-PUSH       33\n")
+	require.Contains(t, out, "  warning        -\n")
+	require.NotContains(t, out, "sample")
+}
+
+func TestPreview_AutoShowsThemeForBackground(t *testing.T) {
+	t.Setenv("FP_BACKGROUND", "dark")
+	var printed strings.Builder
+	deps := previewDeps(&printed, false)
+	deps.GetAll = func() (map[string]string, error) {
+		return map[string]string{"theme": "auto", "theme_dark": "ocean"}, nil
+	}
+
+	require.NoError(t, preview([]string{"auto"}, dispatchers.NewParsedFlags(nil), deps))
+	require.True(t, strings.HasPrefix(printed.String(), "ocean-dark\n"))
+}
+
+func TestSet_Auto(t *testing.T) {
+	var printed strings.Builder
+	var saved []string
+	deps := previewDeps(&printed, false)
+	deps.ReadLines = func() ([]string, error) { return nil, nil }
+	deps.Set = func(lines []string, key, value string) ([]string, bool) { return append(lines, key+"="+value), false }
+	deps.WriteLines = func(lines []string) error { saved = lines; return nil }
+
+	require.NoError(t, setTheme([]string{"auto"}, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []string{"theme=auto"}, saved)
+	require.Contains(t, printed.String(), "theme_dark and theme_light")
+}
+
+func TestPreview_ForceColor(t *testing.T) {
+	var printed strings.Builder
+	err := preview([]string{"ocean-dark"}, dispatchers.NewParsedFlags(nil), previewDeps(&printed, true))
+
+	require.NoError(t, err)
+	require.Contains(t, printed.String(), "sample")
+}
+
+func TestPreview_UnknownTheme(t *testing.T) {
+	var printed strings.Builder
+	err := preview([]string{"lagoon-dark"}, dispatchers.NewParsedFlags(nil), previewDeps(&printed, false))
+
+	require.ErrorContains(t, err, "unknown theme: lagoon-dark")
+	require.Empty(t, printed.String())
+
+	err = preview(nil, dispatchers.NewParsedFlags(nil), previewDeps(&printed, false))
+	require.ErrorContains(t, err, "theme")
+}
+
 func TestSet_ReadLinesError(t *testing.T) {
 	deps := Deps{
 		ReadLines: func() ([]string, error) {
@@ -210,6 +310,122 @@ func TestSet_WriteLinesError(t *testing.T) {
 	require.Contains(t, err.Error(), "write error")
 }
 
+// =========== EXPORT / IMPORT TESTS ===========
+
+func TestExport_TOMLAndJSON(t *testing.T) {
+	var printed strings.Builder
+	deps := previewDeps(&printed, false)
+
+	err := exportTheme([]string{"ocean-dark"}, dispatchers.NewParsedFlags(nil), deps)
+	require.NoError(t, err)
+	require.Contains(t, printed.String(), "# fp theme ocean-dark\n")
+	require.Contains(t, printed.String(), "success = \"10\"\n")
+	require.Contains(t, printed.String(), "pre_push = \"33\"\n")
+
+	printed.Reset()
+	err = exportTheme([]string{"ocean-dark"}, dispatchers.NewParsedFlags([]string{"--format", "json"}), deps)
+	require.NoError(t, err)
+	require.Contains(t, printed.String(), `"header": "bold"`)
+
+	err = exportTheme([]string{"ocean-dark"}, dispatchers.NewParsedFlags([]string{"--format=yaml"}), deps)
+	require.ErrorContains(t, err, "invalid format 'yaml'")
+	err = exportTheme([]string{"lagoon-dark"}, dispatchers.NewParsedFlags(nil), deps)
+	require.ErrorContains(t, err, "unknown theme")
+}
+
+func importDeps(printed *strings.Builder, dir string) Deps {
+	deps := previewDeps(printed, false)
+	deps.ThemesDir = func() string { return dir }
+	return deps
+}
+
+func TestImport_CopiesIntoThemesDir(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "dracula.toml")
+	require.NoError(t, os.WriteFile(src, []byte("base = \"neon-dark\"\nsuccess = 84\n"), 0600))
+	themesDir := filepath.Join(t.TempDir(), "themes")
+	var printed strings.Builder
+
+	err := importTheme([]string{src}, dispatchers.NewParsedFlags(nil), importDeps(&printed, themesDir))
+	require.NoError(t, err)
+	require.Contains(t, printed.String(), "fp theme set dracula")
+	data, err := os.ReadFile(filepath.Join(themesDir, "dracula.toml"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "success = 84")
+
+	err = importTheme([]string{src}, dispatchers.NewParsedFlags(nil), importDeps(&printed, themesDir))
+	require.ErrorContains(t, err, "already exists")
+
+	// --force replaces it, also when the new file has the other format
+	jsonSrc := filepath.Join(t.TempDir(), "x.json")
+	require.NoError(t, os.WriteFile(jsonSrc, []byte(`{"info": 51}`), 0600))
+	err = importTheme([]string{jsonSrc}, dispatchers.NewParsedFlags([]string{"--name", "dracula", "--force"}), importDeps(&printed, themesDir))
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(themesDir, "dracula.json"))
+	require.NoFileExists(t, filepath.Join(themesDir, "dracula.toml"))
+}
+
+func TestImport_Rejects(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+		return path
+	}
+	var printed strings.Builder
+	deps := importDeps(&printed, filepath.Join(dir, "themes"))
+	noFlags := dispatchers.NewParsedFlags(nil)
+
+	require.ErrorContains(t, importTheme([]string{write("a.txt", "")}, noFlags, deps), "expected .toml or .json")
+	require.ErrorContains(t, importTheme([]string{write("bad.toml", `success = "green"`)}, noFlags, deps), "success must be an ANSI color")
+	require.ErrorContains(t, importTheme([]string{write("neon-dark.toml", "")}, noFlags, deps), "built-in theme")
+	require.ErrorContains(t, importTheme([]string{write("ok.toml", "")}, dispatchers.NewParsedFlags([]string{"--name", "Bad Name"}), deps), "invalid theme name")
+	require.ErrorContains(t, importTheme(nil, noFlags, deps), "file")
+	require.NoDirExists(t, filepath.Join(dir, "themes"))
+}
+
+// =========== ACCESSIBLE PICKER TESTS ===========
+
+func TestInteractive_AccessiblePicksByNumber(t *testing.T) {
+	var saved []string
+	var prompts bytes.Buffer
+	deps := Deps{
+		Get:        func(string) (string, bool) { return "default-dark", true },
+		ReadLines:  func() ([]string, error) { return nil, nil },
+		Set:        func(lines []string, key, value string) ([]string, bool) { return append(lines, key+"="+value), false },
+		WriteLines: func(lines []string) error { saved = lines; return nil },
+		Printf:     func(string, ...any) (int, error) { return 0, nil },
+		Println:    func(...any) (int, error) { return 0, nil },
+		ThemeNames: []string{"default-dark", "ocean-dark"},
+		Accessible: func() bool { return true },
+		NewPrompt: func() *ui.LinearPrompt {
+			return ui.NewLinearPrompt(strings.NewReader("2\n"), &prompts)
+		},
+	}
+
+	err := interactive(nil, dispatchers.NewParsedFlags(nil), deps)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"theme=ocean-dark"}, saved)
+	require.Contains(t, prompts.String(), "1. default-dark (current)")
+}
+
+func TestInteractive_AccessibleCancel(t *testing.T) {
+	var printed []any
+	deps := Deps{
+		Get:        func(string) (string, bool) { return "", false },
+		Println:    func(a ...any) (int, error) { printed = append(printed, a...); return 0, nil },
+		WriteLines: func([]string) error { t.Fatal("nothing should be written"); return nil },
+		ThemeNames: []string{"default-dark"},
+		Accessible: func() bool { return true },
+		NewPrompt: func() *ui.LinearPrompt {
+			return ui.NewLinearPrompt(strings.NewReader("\n"), &bytes.Buffer{})
+		},
+	}
+
+	require.NoError(t, interactive(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, []any{"Cancelled"}, printed)
+}
+
 // =========== RENDER COLOR PREVIEW TESTS ===========
 
 func TestRenderColorPreview(t *testing.T) {
@@ -0,0 +1,163 @@
+package tracking
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui"
+)
+
+// isAccessible reports whether interactive views should give way to plain
+// text and numbered prompts (see ui.IsAccessible).
+func isAccessible(deps Deps) bool {
+	return deps.Accessible != nil && deps.Accessible()
+}
+
+// printHeatmapSummary is fp heatmap in accessible mode: the year's totals
+// and one line per month instead of a calendar grid.
+func printHeatmapSummary(rollups []store.DailyRollup, year int, repoID string, deps Deps) {
+	dayCounts := countEventsByDay(rollups, year, repoID)
+
+	scope := "all repositories"
+	if repoID != "" {
+		scope = repoID
+	}
+	total, busiestDay, busiest := 0, "", 0
+	for day, n := range dayCounts {
+		total += n
+		if n > busiest || (n == busiest && day < busiestDay) {
+			busiestDay, busiest = day, n
+		}
+	}
+	_, _ = deps.Printf("%d, %s: %d %s on %d %s\n", year, scope,
+		total, pluralize(total, "event", "events"), len(dayCounts), pluralize(len(dayCounts), "day", "days"))
+	if total == 0 {
+		return
+	}
+	if streak := longestStreak(dayCounts, year); streak > 1 {
+		_, _ = deps.Printf("Longest streak: %d days\n", streak)
+	}
+	if t, err := time.Parse(dayKeyLayout, busiestDay); err == nil {
+		_, _ = deps.Printf("Busiest day: %s, %d %s\n", t.Format("Monday January 2"), busiest, pluralize(busiest, "event", "events"))
+	}
+
+	var monthEvents, monthDays [12]int
+	for day, n := range dayCounts {
+		t, err := time.Parse(dayKeyLayout, day)
+		if err != nil {
+			continue
+		}
+		monthEvents[t.Month()-1] += n
+		monthDays[t.Month()-1]++
+	}
+	_, _ = deps.Println()
+	for i := range 12 {
+		if monthEvents[i] == 0 {
+			continue
+		}
+		_, _ = deps.Printf("%s: %d %s on %d %s\n", time.Month(i+1),
+			monthEvents[i], pluralize(monthEvents[i], "event", "events"), monthDays[i], pluralize(monthDays[i], "day", "days"))
+	}
+}
+
+// printTopSummary is fp top in accessible mode: today's totals, each
+// repository's events over the last days and the export state, printed
+// once instead of redrawn.
+func printTopSummary(snap topSnapshot, deps Deps) {
+	_, _ = deps.Printf("Today: %d %s, +%d -%d, in %d %s\n", snap.events, pluralize(snap.events, "event", "events"),
+		snap.insertions, snap.deletions, snap.activeRepos, pluralize(snap.activeRepos, "repository", "repositories"))
+
+	_, _ = deps.Println()
+	if len(snap.repos) == 0 {
+		_, _ = deps.Printf("No activity in the last %d days\n", topDays)
+	} else {
+		_, _ = deps.Printf("Last %d days:\n", topDays)
+		for _, r := range snap.repos {
+			_, _ = deps.Printf("  %s: %d %s, %d today\n", r.name, r.total, pluralize(r.total, "event", "events"), r.today())
+		}
+	}
+
+	_, _ = deps.Println()
+	for _, row := range topStatusRows(snap) {
+		_, _ = deps.Printf("%s: %s\n", row.label, row.value)
+	}
+}
+
+// Actions offered for a repository in the accessible repo manager.
+const (
+	repoActionInstall   = "Install hooks"
+	repoActionUninstall = "Remove hooks"
+	repoActionBack      = "Back"
+)
+
+// reposLinear is fp repos -i in accessible mode: a numbered list of the
+// repositories found, then installing or removing the hooks of the one
+// picked, until the list is left with an empty answer.
+func reposLinear(repos []RepoEntry, deps Deps) error {
+	m := newReposModel(repos)
+	p := deps.NewPrompt()
+
+	for {
+		options := make([]string, len(m.repos))
+		for i, r := range m.repos {
+			options[i] = fmt.Sprintf("%s (%s): %s", r.Name, r.Path, repoHookState(r))
+		}
+		i, err := p.Choose("Repositories:", options)
+		if errors.Is(err, ui.ErrCancelled) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		r := &m.repos[i]
+		var actions []string
+		switch {
+		case r.HasHooks:
+			actions = []string{repoActionUninstall, repoActionBack}
+		case r.Inspection.Status.CanInstall():
+			actions = []string{repoActionInstall, repoActionBack}
+		default:
+			p.Println(fmt.Sprintf("fp can't install hooks in %s: %s", r.Name, r.Inspection.Status))
+			continue
+		}
+		action, err := p.Choose(options[i], actions)
+		if errors.Is(err, ui.ErrCancelled) || (err == nil && actions[action] == repoActionBack) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		r.Selected = true
+		if actions[action] == repoActionInstall {
+			m.installSelected()
+		} else {
+			m.uninstallSelected()
+		}
+		r.Selected = false
+		p.Println(m.message)
+	}
+
+	if m.installed > 0 {
+		_, _ = deps.Printf("Installed hooks in %d repositories\n", m.installed)
+	}
+	if m.uninstalled > 0 {
+		_, _ = deps.Printf("Removed hooks from %d repositories\n", m.uninstalled)
+	}
+	return nil
+}
+
+// repoHookState describes r's hooks in words.
+func repoHookState(r RepoEntry) string {
+	switch {
+	case r.HasHooks:
+		return "hooks installed"
+	case r.Inspection.Status.CanInstall():
+		return "no hooks"
+	default:
+		return "blocked, " + r.Inspection.Status.String()
+	}
+}
@@ -2,12 +2,14 @@ package tracking
 
 import (
 	"bytes"
+	"database/sql"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/footprint-tools/cli/internal/dispatchers"
-	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
 	"github.com/footprint-tools/cli/internal/output"
 	"github.com/footprint-tools/cli/internal/store"
 )
@@ -18,14 +20,15 @@ func Activity(args []string, flags *dispatchers.ParsedFlags) error {
 
 func activity(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	// Handle interactive mode
-	if flags.Has("-i") || flags.Has("--interactive") {
+	// In accessible mode -i keeps the plain list a screen reader can follow
+	if (flags.Has("-i") || flags.Has("--interactive")) && !isAccessible(deps) {
 		return activityInteractive(flags, deps)
 	}
 
 	dbPath := deps.DBPath()
 	db, err := deps.OpenDB(dbPath)
 	if err != nil {
-		return fmt.Errorf("failed to open database at %s: %w\nHint: Run 'fp setup' to initialize tracking in this repository", dbPath, err)
+		return fmt.Errorf("failed to open database at %s: %w%s", dbPath, err, setupHint(err))
 	}
 	defer store.CloseDB(db)
 
@@ -35,6 +38,11 @@ func activity(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	jsonOutput := flags.Has("--json")
 	enrich := flags.Has("--enrich")
 
+	groupBy := flags.String("--group-by", "")
+	if groupBy != "" && !slices.Contains(activityGroupings, groupBy) {
+		return fmt.Errorf("invalid --group-by value '%s': valid values are %s", groupBy, strings.Join(activityGroupings, ", "))
+	}
+
 	if statusStr := flags.String("--status", ""); statusStr != "" {
 		status, ok := parseStatus(statusStr)
 		if !ok {
@@ -54,7 +62,7 @@ func activity(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	if sinceStr := flags.String("--since", ""); sinceStr != "" {
 		since := flags.Date("--since")
 		if since == nil {
-			return fmt.Errorf("invalid date '%s' for --since: expected format YYYY-MM-DD", sinceStr)
+			return fmt.Errorf("invalid date '%s' for --since: expected %s", sinceStr, dispatchers.DateFormats)
 		}
 		filter.Since = since
 	}
@@ -62,7 +70,7 @@ func activity(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	if untilStr := flags.String("--until", ""); untilStr != "" {
 		until := flags.Date("--until")
 		if until == nil {
-			return fmt.Errorf("invalid date '%s' for --until: expected format YYYY-MM-DD", untilStr)
+			return fmt.Errorf("invalid date '%s' for --until: expected %s", untilStr, dispatchers.DateFormats)
 		}
 		filter.Until = until
 	}
@@ -71,6 +79,22 @@ func activity(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		filter.RepoID = &repoID
 	}
 
+	if dir := flags.String("--path", ""); dir != "" {
+		filter.Path = &dir
+	}
+
+	if device := flags.String("--device", ""); device != "" {
+		filter.Device = &device
+	}
+
+	if err := projectFilter(db, flags, &filter); err != nil {
+		return err
+	}
+
+	if query := flags.String("--search", ""); strings.TrimSpace(query) != "" {
+		filter.Search = &query
+	}
+
 	// Validate and parse limit flag
 	if limitStr := flags.String("--limit", ""); limitStr != "" {
 		limit, err := strconv.Atoi(limitStr)
@@ -83,10 +107,39 @@ func activity(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		filter.Limit = limit
 	}
 
+	// Identities tag JSON events when the author is looked up anyway.
+	var identities *identityMatcher
+	mine := flags.Has("--mine")
+	if mine || enrich {
+		identities = loadIdentityMatcher()
+	}
+	if mine && identities == nil {
+		return errNoIdentities
+	}
+
+	// Other authors' events, merges, rewrites and feature branches are
+	// dropped after the query, so the limit applies afterwards too.
+	history := parseHistoryFilter(flags)
+	limit := filter.Limit
+	if mine || history.active() || flags.Has("--default-branch") {
+		filter.Limit = 0
+	}
+
 	events, err := deps.ListEvents(db, filter)
 	if err != nil {
 		return fmt.Errorf("failed to list events: %w", err)
 	}
+	if mine {
+		events = identities.Filter(events)
+	}
+	events = history.apply(events, newCommitMetaReader(db))
+	branches := loadDefaultBranches(db)
+	if flags.Has("--default-branch") {
+		events = branches.Filter(events)
+	}
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
 	if len(events) == 0 {
 		if jsonOutput {
 			output.JSONEmpty(deps.Println)
@@ -96,19 +149,39 @@ func activity(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		return nil
 	}
 
+	var fields eventFields
+	var metas *commitMetaReader
+	if enrich {
+		metas = newCommitMetaReader(db)
+		if jsonOutput {
+			fields = loadEventFields(db, events)
+		}
+	}
+
+	if groupBy != "" {
+		groups := groupEvents(events, groupBy)
+		if jsonOutput {
+			return outputGroupedEventsJSON(groups, metas, identities, fields, branches, deps)
+		}
+		deps.Pager(formatGroupedEvents(groups, metas, branches, oneline))
+		return nil
+	}
+
 	if jsonOutput {
-		return outputEventsJSON(events, enrich, deps)
+		return outputEventsJSON(events, metas, identities, fields, branches, deps)
 	}
 
 	var output bytes.Buffer
 
 	for _, event := range events {
-		if enrich {
-			meta := git.GetCommitMetadata(event.RepoPath, event.Commit)
-			output.WriteString(formatEventEnriched(event, meta, oneline))
+		if metas != nil {
+			output.WriteString(formatEventEnriched(event, metas.Of(event), oneline))
 		} else {
 			output.WriteString(formatEvent(event, oneline))
 		}
+		if !oneline {
+			output.WriteString(formatBase(event, branches))
+		}
 		output.WriteString("\n")
 	}
 
@@ -116,39 +189,102 @@ func activity(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	return nil
 }
 
-func outputEventsJSON(events []store.RepoEvent, enrich bool, deps Deps) error {
-	type jsonEvent struct {
-		ID        int64  `json:"id"`
-		RepoID    string `json:"repo_id"`
-		RepoPath  string `json:"repo_path"`
-		Commit    string `json:"commit"`
-		Branch    string `json:"branch"`
-		Timestamp string `json:"timestamp"`
-		Status    string `json:"status"`
-		Source    string `json:"source"`
-		Author    string `json:"author,omitempty"`
-		Message   string `json:"message,omitempty"`
+// jsonEvent is the JSON form of an event in fp activity --json.
+type jsonEvent struct {
+	SchemaVersion int `json:"schema_version"`
+
+	ID        int64  `json:"id"`
+	RepoID    string `json:"repo_id"`
+	RepoPath  string `json:"repo_path"`
+	Commit    string `json:"commit"`
+	Branch    string `json:"branch"`
+	Timestamp string `json:"timestamp"`
+	Status    string `json:"status"`
+	Source    string `json:"source"`
+	Cwd       string `json:"cwd,omitempty"`
+	Note      string `json:"note,omitempty"`
+	Tag       string `json:"tag,omitempty"`
+	Device    string `json:"device,omitempty"`
+	Author    string `json:"author,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Identity  string `json:"identity,omitempty"`
+
+	// DefaultBranch is the repository's default branch, and FeatureBranch
+	// is set when the event happened on another one
+	DefaultBranch string `json:"default_branch,omitempty"`
+	FeatureBranch bool   `json:"feature_branch,omitempty"`
+
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// toJSONEvent converts e for JSON output. When metas is set, the event
+// gets the author and subject of its commit; when identities is set, it is
+// tagged with the identity that authored it; fields adds what the
+// enrichers derived from its commit; branches adds its repository's
+// default branch.
+func toJSONEvent(e store.RepoEvent, metas *commitMetaReader, identities *identityMatcher, fields eventFields, branches *defaultBranches) jsonEvent {
+	je := jsonEvent{
+		SchemaVersion: output.SchemaVersion,
+
+		ID:        e.ID,
+		RepoID:    e.RepoID,
+		RepoPath:  e.RepoPath,
+		Commit:    e.Commit,
+		Branch:    e.Branch,
+		Timestamp: e.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+		Status:    e.Status.String(),
+		Source:    e.Source.String(),
+		Cwd:       e.Cwd,
+		Note:      e.Note,
+		Tag:       e.Tag,
+		Device:    e.Device,
+	}
+	if metas != nil {
+		meta := metas.Of(e)
+		je.Author = meta.AuthorName
+		je.Message = meta.Subject
 	}
+	if identities != nil {
+		je.Identity = identities.Of(e)
+	}
+	je.Fields = fields.of(e)
+	if branches != nil {
+		je.DefaultBranch = branches.Of(e)
+		je.FeatureBranch = hasBranch(e) && !branches.OnDefault(e)
+	}
+	return je
+}
 
+func outputEventsJSON(events []store.RepoEvent, metas *commitMetaReader, identities *identityMatcher, fields eventFields, branches *defaultBranches, deps Deps) error {
 	out := make([]jsonEvent, 0, len(events))
 	for _, e := range events {
-		je := jsonEvent{
-			ID:        e.ID,
-			RepoID:    e.RepoID,
-			RepoPath:  e.RepoPath,
-			Commit:    e.Commit,
-			Branch:    e.Branch,
-			Timestamp: e.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
-			Status:    e.Status.String(),
-			Source:    e.Source.String(),
-		}
-		if enrich {
-			meta := git.GetCommitMetadata(e.RepoPath, e.Commit)
-			je.Author = meta.AuthorName
-			je.Message = meta.Subject
-		}
-		out = append(out, je)
+		out = append(out, toJSONEvent(e, metas, identities, fields, branches))
 	}
 
 	return output.JSON(deps.Println, out)
 }
+
+// eventFields holds the enriched fields of commits, by repo id and commit.
+type eventFields map[string]map[string]string
+
+func (f eventFields) of(e store.RepoEvent) map[string]string {
+	return f[e.RepoID+"\x00"+e.Commit]
+}
+
+// loadEventFields reads the fields stored for the commits of events. A
+// commit without fields, or one that can't be read, is left out.
+func loadEventFields(db *sql.DB, events []store.RepoEvent) eventFields {
+	fields := make(eventFields)
+	for _, e := range events {
+		key := e.RepoID + "\x00" + e.Commit
+		if _, done := fields[key]; done {
+			continue
+		}
+		f, err := store.GetCommitFields(db, e.RepoID, e.Commit)
+		if err != nil {
+			log.Debug("activity: could not read fields of %.7s: %v", e.Commit, err)
+		}
+		fields[key] = f
+	}
+	return fields
+}
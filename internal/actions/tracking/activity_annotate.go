@@ -0,0 +1,273 @@
+package tracking
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/components"
+)
+
+// Fields of the annotate form, in Tab order.
+const (
+	annotateTag = iota
+	annotateNote
+	annotateProjects
+	annotateFields
+)
+
+// annotateFormHeight is how many rows of the events list the form takes:
+// a line per field and the key hint.
+const annotateFormHeight = annotateFields + 1
+
+// annotateLabelWidth fits the longest field label, "Projects:".
+const annotateLabelWidth = 10
+
+// annotationMark is put before the message of events with a tag or note.
+const annotationMark = "✎ "
+
+// annotation is what the annotate form changes: the tag and note of one
+// event (nil when left alone), and the projects its repository joins and
+// leaves. Projects group repositories, so those changes apply to every
+// event of the repository.
+type annotation struct {
+	EventID  int64
+	RepoID   string
+	RepoPath string
+	Tag      *string
+	Note     *string
+	Join     []string
+	Leave    []string
+}
+
+// annotateForm edits the event under the cursor of fp activity -i in
+// place: e opens it below the event's row, Enter saves every field at once.
+type annotateForm struct {
+	event    store.RepoEvent
+	note     string   // the note as shown; notes with several lines are shown on one
+	projects []string // the projects of the repository when the form opened
+	fields   [annotateFields]components.ThemedInput
+	focus    int
+}
+
+func newAnnotateForm(e store.RepoEvent, projects []string, width int) annotateForm {
+	f := annotateForm{
+		event:    e,
+		note:     strings.Join(strings.Fields(strings.ReplaceAll(e.Note, "\n", " ")), " "),
+		projects: projects,
+	}
+	placeholders := [annotateFields]string{
+		"e.g. pairing, client-x",
+		"What was this commit?",
+		"comma-separated, for the whole repository",
+	}
+	values := [annotateFields]string{e.Tag, f.note, strings.Join(projects, ", ")}
+	for i := range f.fields {
+		f.fields[i] = components.NewThemedInputWithPrompt(placeholders[i], "")
+		f.fields[i].SetWidth(max(10, width-annotateLabelWidth-6))
+		f.fields[i].SetValue(values[i])
+		f.fields[i].CursorEnd()
+	}
+	return f
+}
+
+// focusField moves the cursor to field i, wrapping around.
+func (f *annotateForm) focusField(i int) tea.Cmd {
+	f.fields[f.focus].Blur()
+	f.focus = (i + annotateFields) % annotateFields
+	return f.fields[f.focus].Focus()
+}
+
+// update passes msg to the focused field.
+func (f *annotateForm) update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	f.fields[f.focus], cmd = f.fields[f.focus].Update(msg)
+	return cmd
+}
+
+// result returns what the form changes, and whether it changes anything.
+// A note shown on one line that was left alone keeps its lines.
+func (f annotateForm) result() (annotation, bool) {
+	a := annotation{
+		EventID:  f.event.ID,
+		RepoID:   f.event.RepoID,
+		RepoPath: f.event.RepoPath,
+	}
+	if tag := strings.TrimSpace(f.fields[annotateTag].Value()); tag != f.event.Tag {
+		a.Tag = &tag
+	}
+	if note := strings.TrimSpace(f.fields[annotateNote].Value()); note != f.note {
+		a.Note = &note
+	}
+	projects := parseProjectNames(f.fields[annotateProjects].Value())
+	for _, p := range projects {
+		if !slices.Contains(f.projects, p) {
+			a.Join = append(a.Join, p)
+		}
+	}
+	for _, p := range f.projects {
+		if !slices.Contains(projects, p) {
+			a.Leave = append(a.Leave, p)
+		}
+	}
+	changed := a.Tag != nil || a.Note != nil || len(a.Join) > 0 || len(a.Leave) > 0
+	return a, changed
+}
+
+// parseProjectNames splits a comma-separated list of project names,
+// dropping blanks and repeats.
+func parseProjectNames(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// view renders the form, indented under the row of its event.
+func (f annotateForm) view(m activityModel) []string {
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Muted))
+	focusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.UIActive)).Bold(true)
+	labels := [annotateFields]string{"Tag:", "Note:", "Projects:"}
+
+	lines := make([]string, 0, annotateFormHeight)
+	for i, field := range f.fields {
+		label := labelStyle.Render(padRight(labels[i], annotateLabelWidth))
+		if i == f.focus {
+			label = focusStyle.Render(padRight(labels[i], annotateLabelWidth))
+		}
+		lines = append(lines, "    "+label+field.View())
+	}
+	return append(lines, "    "+labelStyle.Render("Enter saves · Tab next field · Esc cancels"))
+}
+
+// startAnnotate opens the annotate form for the event under the cursor.
+func (m *activityModel) startAnnotate() tea.Cmd {
+	filtered := m.filteredEvents()
+	if m.cursor >= len(filtered) {
+		return nil
+	}
+	if m.saveAnnotation == nil || config.IsReadOnly() {
+		m.annotateMessage = "Read-only mode: events can't be annotated"
+		return nil
+	}
+	event := filtered[m.cursor]
+
+	var projects []string
+	if m.repoProjects != nil {
+		var err error
+		if projects, err = m.repoProjects(event.RepoID); err != nil {
+			m.annotateMessage = "Could not read projects: " + err.Error()
+			return nil
+		}
+	}
+
+	_, eventsWidth, _ := m.calculateWidths()
+	m.annotate = newAnnotateForm(event, projects, eventsWidth)
+	m.annotating = true
+	m.annotateMessage = ""
+	return m.annotate.focusField(annotateTag)
+}
+
+// handleAnnotateKeys edits the annotate form. Tab and the arrows move
+// between fields, Enter (or Ctrl+S) saves and Esc throws the edit away.
+func (m activityModel) handleAnnotateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.annotating = false
+		m.annotateMessage = ""
+		return m, nil
+	case tea.KeyEnter, tea.KeyCtrlS:
+		m.annotating = false
+		m.commitAnnotation()
+		return m, nil
+	case tea.KeyTab, tea.KeyDown:
+		return m, m.annotate.focusField(m.annotate.focus + 1)
+	case tea.KeyShiftTab, tea.KeyUp:
+		return m, m.annotate.focusField(m.annotate.focus - 1)
+	}
+	return m, m.annotate.update(msg)
+}
+
+// commitAnnotation saves the annotate form and updates the loaded events
+// to match.
+func (m *activityModel) commitAnnotation() {
+	a, changed := m.annotate.result()
+	if !changed {
+		m.annotateMessage = ""
+		return
+	}
+	if err := m.saveAnnotation(a); err != nil {
+		m.annotateMessage = "Not saved: " + err.Error()
+		return
+	}
+
+	apply := func(e *store.RepoEvent) {
+		if a.Tag != nil {
+			e.Tag = *a.Tag
+		}
+		if a.Note != nil {
+			e.Note = *a.Note
+		}
+	}
+	for i := range m.events {
+		if m.events[i].ID == a.EventID {
+			apply(&m.events[i])
+		}
+	}
+	if m.drawerDetail != nil && m.drawerDetail.Event.ID == a.EventID {
+		apply(&m.drawerDetail.Event)
+	}
+	m.annotateMessage = "Annotation saved"
+}
+
+// saveAnnotation writes a to the store. Projects are checked first, so a
+// misspelled one saves nothing.
+func saveAnnotation(db *sql.DB, a annotation) error {
+	for _, name := range a.Join {
+		ok, err := store.ProjectExists(db, name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("no project named '%s' (create it with fp project create %s)", name, name)
+		}
+	}
+
+	if a.Tag != nil {
+		if err := store.SetEventTag(db, a.EventID, *a.Tag); err != nil {
+			return err
+		}
+	}
+	if a.Note != nil {
+		if err := store.SetEventNote(db, a.EventID, *a.Note); err != nil {
+			return err
+		}
+	}
+	for _, name := range a.Join {
+		if _, err := store.AddProjectRepo(db, name, a.RepoID, a.RepoPath); err != nil {
+			return err
+		}
+	}
+	for _, name := range a.Leave {
+		if _, err := store.RemoveProjectRepo(db, name, a.RepoID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isAnnotated reports whether e has a tag or a note, which the events
+// list marks with annotationMark.
+func isAnnotated(e store.RepoEvent) bool {
+	return e.Tag != "" || e.Note != ""
+}
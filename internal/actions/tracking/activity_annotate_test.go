@@ -0,0 +1,151 @@
+package tracking
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func annotateTestModel(t *testing.T) activityModel {
+	t.Helper()
+	events := []store.RepoEvent{
+		{ID: 7, RepoID: "github.com/user/repo", RepoPath: "/src/repo", Commit: "abc123", Branch: "main", Note: "first line\nsecond line", Timestamp: time.Now()},
+		{ID: 8, RepoID: "github.com/user/repo", RepoPath: "/src/repo", Commit: "def456", Branch: "main", Timestamp: time.Now()},
+	}
+	m := newActivityModel(events, map[string]git.CommitMetadata{"abc123": {Subject: "Fix login"}, "def456": {Subject: "Add tests"}})
+	m.width, m.height = 160, 30
+	m.repoProjects = func(string) ([]string, error) { return []string{"billing"}, nil }
+	return m
+}
+
+func TestActivityModel_Annotate(t *testing.T) {
+	setRedactionConfig(t)
+	m := annotateTestModel(t)
+
+	var saved []annotation
+	m.saveAnnotation = func(a annotation) error {
+		saved = append(saved, a)
+		return nil
+	}
+
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	require.True(t, m.annotating)
+	require.True(t, m.CapturingInput())
+	require.Contains(t, m.View(), "Projects:")
+
+	// Keys go to the form while editing, q included; Tab moves on
+	m = activityKeys(m, typeKeys("q-pairing")...)
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyTab})
+	m = activityKeys(m, typeKeys(", payments")...)
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	require.False(t, m.annotating)
+	require.Len(t, saved, 1)
+	require.Equal(t, int64(7), saved[0].EventID)
+	require.Equal(t, "q-pairing", *saved[0].Tag)
+	require.Nil(t, saved[0].Note, "a note left alone keeps its lines")
+	require.Equal(t, []string{"payments"}, saved[0].Join)
+	require.Empty(t, saved[0].Leave)
+	require.Equal(t, "q-pairing", m.events[0].Tag)
+	require.Equal(t, "first line\nsecond line", m.events[0].Note)
+	require.Equal(t, "Annotation saved", m.annotateMessage)
+
+	// Esc throws the edit away
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m = activityKeys(m, typeKeys("-more")...)
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	require.False(t, m.annotating)
+	require.Equal(t, "q-pairing", m.events[0].Tag)
+	require.Len(t, saved, 1)
+
+	// Saving without changes writes nothing
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")}, tea.KeyMsg{Type: tea.KeyEnter})
+	require.Len(t, saved, 1)
+}
+
+func TestActivityModel_AnnotateFails(t *testing.T) {
+	setRedactionConfig(t)
+	m := annotateTestModel(t)
+	m.saveAnnotation = func(annotation) error { return errors.New("database is locked") }
+
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	m = activityKeys(m, typeKeys("tag")...)
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	require.Empty(t, m.events[0].Tag)
+	require.Contains(t, m.annotateMessage, "database is locked")
+}
+
+func TestActivityModel_AnnotateReadOnly(t *testing.T) {
+	setRedactionConfig(t, "read_only", "true")
+	m := annotateTestModel(t)
+	m.saveAnnotation = func(annotation) error { t.Fatal("saved in read-only mode"); return nil }
+
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	require.False(t, m.annotating)
+	require.Contains(t, m.annotateMessage, "Read-only")
+}
+
+func TestActivityModel_AnnotationMark(t *testing.T) {
+	setRedactionConfig(t)
+	m := annotateTestModel(t)
+
+	marked := func(subject string) bool {
+		for _, line := range strings.Split(m.View(), "\n") {
+			if strings.Contains(line, subject) {
+				return strings.Contains(line, annotationMark+subject)
+			}
+		}
+		t.Fatalf("%q not in view", subject)
+		return false
+	}
+	require.True(t, marked("Fix login"), "events with a note are marked")
+	require.False(t, marked("Add tests"))
+
+	m.compact = compactMode{set: true, on: true}
+	require.True(t, marked("Fix login"))
+}
+
+func TestSaveAnnotation(t *testing.T) {
+	db, err := openDBFresh(filepath.Join(t.TempDir(), "store.db"))
+	require.NoError(t, err)
+	defer store.CloseDB(db)
+
+	require.NoError(t, store.InsertEvent(db, store.RepoEvent{RepoID: "github.com/user/repo", RepoPath: "/src/repo", Commit: "abc123", Branch: "main", Timestamp: time.Now(), Status: store.StatusPending, Source: store.SourcePostCommit}))
+	events, err := store.ListEvents(db, store.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	id := events[0].ID
+
+	require.NoError(t, store.CreateProject(db, "billing"))
+	require.NoError(t, store.CreateProject(db, "payments"))
+	_, err = store.AddProjectRepo(db, "billing", "github.com/user/repo", "/src/repo")
+	require.NoError(t, err)
+
+	tag, note := "pairing", "with Ana"
+	a := annotation{EventID: id, RepoID: "github.com/user/repo", RepoPath: "/src/repo", Tag: &tag, Note: &note, Join: []string{"payments"}, Leave: []string{"billing"}}
+	require.NoError(t, saveAnnotation(db, a))
+
+	events, err = store.ListEvents(db, store.EventFilter{})
+	require.NoError(t, err)
+	require.Equal(t, "pairing", events[0].Tag)
+	require.Equal(t, "with Ana", events[0].Note)
+	projects, err := store.RepoProjects(db, "github.com/user/repo")
+	require.NoError(t, err)
+	require.Equal(t, []string{"payments"}, projects)
+
+	// A project that doesn't exist saves nothing
+	other := "solo"
+	err = saveAnnotation(db, annotation{EventID: id, RepoID: "github.com/user/repo", Tag: &other, Join: []string{"nope"}})
+	require.ErrorContains(t, err, "no project named 'nope'")
+	events, err = store.ListEvents(db, store.EventFilter{})
+	require.NoError(t, err)
+	require.Equal(t, "pairing", events[0].Tag)
+}
@@ -0,0 +1,127 @@
+package tracking
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// activityGroupings are the values accepted by fp activity --group-by.
+var activityGroupings = []string{"repo", "branch", "day", "source"}
+
+// eventGroup is one section of grouped activity output.
+type eventGroup struct {
+	Key    string
+	RepoID string // set when grouping by branch, which is per repository
+	Label  string
+	Events []store.RepoEvent
+}
+
+// groupEvents splits events into groups. Days stay in the order the events
+// came in (newest first); the other groupings put the busiest group first so
+// the repository or branch that got the most attention leads.
+func groupEvents(events []store.RepoEvent, by string) []eventGroup {
+	var groups []*eventGroup
+	index := make(map[string]*eventGroup)
+
+	for _, e := range events {
+		var key, repoID, label string
+		switch by {
+		case "repo":
+			key, label = e.RepoID, e.RepoID
+		case "branch":
+			branch := e.Branch
+			if branch == "" {
+				branch = "(no branch)"
+			}
+			key, repoID = branch, e.RepoID
+			label = branch + " " + style.Muted(e.RepoID)
+		case "day":
+			key, label = e.Timestamp.Local().Format("2006-01-02"), format.Date(e.Timestamp.Local())
+		case "source":
+			key, label = e.Source.String(), formatSource(e.Source)
+		}
+
+		// Branch names repeat across repositories, so the repo is part of the id.
+		id := repoID + "\x00" + key
+
+		g, ok := index[id]
+		if !ok {
+			g = &eventGroup{Key: key, RepoID: repoID, Label: label}
+			index[id] = g
+			groups = append(groups, g)
+		}
+		g.Events = append(g.Events, e)
+	}
+
+	if by != "day" {
+		sort.SliceStable(groups, func(i, j int) bool {
+			return len(groups[i].Events) > len(groups[j].Events)
+		})
+	}
+
+	out := make([]eventGroup, len(groups))
+	for i, g := range groups {
+		out[i] = *g
+	}
+	return out
+}
+
+// formatGroupedEvents renders each group as a header with its subtotal,
+// followed by its events, and ends with the overall total. When metas is
+// set, events show the metadata of their commits; when branches is set,
+// events on feature branches name the default branch.
+func formatGroupedEvents(groups []eventGroup, metas *commitMetaReader, branches *defaultBranches, oneline bool) string {
+	var b bytes.Buffer
+	total := 0
+
+	for _, g := range groups {
+		n := len(g.Events)
+		total += n
+		fmt.Fprintf(&b, "%s %s\n", style.Header(g.Label), style.Muted(fmt.Sprintf("(%d %s)", n, pluralize(n, "event", "events"))))
+		for _, e := range g.Events {
+			if metas != nil {
+				b.WriteString(formatEventEnriched(e, metas.Of(e), oneline))
+			} else {
+				b.WriteString(formatEvent(e, oneline))
+			}
+			if !oneline {
+				b.WriteString(formatBase(e, branches))
+			}
+			b.WriteString("\n")
+		}
+		if oneline {
+			b.WriteString("\n")
+		}
+	}
+
+	fmt.Fprintf(&b, "%d %s in %d %s\n", total, pluralize(total, "event", "events"), len(groups), pluralize(len(groups), "group", "groups"))
+	return b.String()
+}
+
+// jsonGroup is the JSON form of a group in fp activity --group-by --json.
+type jsonGroup struct {
+	SchemaVersion int         `json:"schema_version"`
+	Key           string      `json:"key"`
+	RepoID        string      `json:"repo_id,omitempty"`
+	Count         int         `json:"count"`
+	Events        []jsonEvent `json:"events"`
+}
+
+func outputGroupedEventsJSON(groups []eventGroup, metas *commitMetaReader, identities *identityMatcher, fields eventFields, branches *defaultBranches, deps Deps) error {
+	out := make([]jsonGroup, 0, len(groups))
+	for _, g := range groups {
+		jg := jsonGroup{SchemaVersion: output.SchemaVersion, Key: g.Key, RepoID: g.RepoID, Count: len(g.Events), Events: make([]jsonEvent, 0, len(g.Events))}
+		for _, e := range g.Events {
+			jg.Events = append(jg.Events, toJSONEvent(e, metas, identities, fields, branches))
+		}
+		out = append(out, jg)
+	}
+
+	return output.JSON(deps.Println, out)
+}
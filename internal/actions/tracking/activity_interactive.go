@@ -8,8 +8,10 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/config"
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/format"
 	"github.com/footprint-tools/cli/internal/git"
@@ -20,68 +22,159 @@ import (
 	"golang.org/x/term"
 )
 
-
 func activityInteractive(_ *dispatchers.ParsedFlags, deps Deps) error {
 	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
 		return errors.New("interactive mode requires a terminal")
 	}
 
-	// Load data synchronously before starting TUI
+	// Read the first page before starting the TUI, and the rest as the
+	// cursor gets near it. The snapshot connection reads alongside a
+	// running export without waiting for it.
 	dbPath := deps.DBPath()
-	db, err := deps.OpenDB(dbPath)
+	db, err := deps.OpenSnapshot(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
 	defer store.CloseDB(db)
 
-	events, err := deps.ListEvents(db, store.EventFilter{})
+	counts, err := store.CountEvents(db)
+	if err != nil {
+		return fmt.Errorf("failed to count events: %w", err)
+	}
+	events, err := deps.ListEvents(db, store.EventFilter{Limit: activityPageSize})
 	if err != nil {
 		return fmt.Errorf("failed to list events: %w", err)
 	}
 
-	commitMeta := make(map[string]git.CommitMetadata)
-	for _, e := range events {
-		if _, exists := commitMeta[e.Commit]; !exists {
-			commitMeta[e.Commit] = git.GetCommitMetadata(e.RepoPath, e.Commit)
+	m := newActivityModel(events, make(map[string]git.CommitMetadata))
+	m.setCounts(counts)
+	m.exhausted = len(events) < activityPageSize
+	m.loadPage = func(before *store.EventCursor) ([]store.RepoEvent, error) {
+		return deps.ListEvents(db, store.EventFilter{Before: before, Limit: activityPageSize})
+	}
+	m.loadMeta = func(events []store.RepoEvent) map[string]git.CommitMetadata {
+		// A reader per batch, as batches load concurrently
+		return loadCommitMeta(newCommitMetaReader(db), events)
+	}
+	m.saveNote = func(id int64, note string) error {
+		// Notes are written through their own connection; the snapshot
+		// the view reads from is read-only.
+		wdb, err := deps.OpenDB(dbPath)
+		if err != nil {
+			return err
 		}
+		defer store.CloseDB(wdb)
+		return store.SetEventNote(wdb, id, note)
 	}
+	m.repoProjects = func(repoID string) ([]string, error) {
+		return store.RepoProjects(db, repoID)
+	}
+	m.saveAnnotation = func(a annotation) error {
+		wdb, err := deps.OpenDB(dbPath)
+		if err != nil {
+			return err
+		}
+		defer store.CloseDB(wdb)
+		return saveAnnotation(wdb, a)
+	}
+	m.loadPush = func(id int64) []store.PushRef {
+		// The drawer goes without the push details if they can't be read
+		refs, _ := store.ListPushRefs(db, []int64{id})
+		return refs[id]
+	}
+	m.searchEvents = func(query string) (map[int64]bool, error) {
+		found, err := deps.ListEvents(db, store.EventFilter{Search: &query})
+		if err != nil {
+			return nil, err
+		}
+		ids := make(map[int64]bool, len(found))
+		for _, e := range found {
+			ids[e.ID] = true
+		}
+		return ids, nil
+	}
+	m.export.exportWith(deps)
 
-	m := newActivityModel(events, commitMeta)
-
-	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	p := tea.NewProgram(components.NewSizeGuard(components.NewHelpOverlay(m)), tea.WithAltScreen(), tea.WithMouseCellMotion())
 	_, err = p.Run()
 	return err
 }
 
-
 // activityModel is the Bubble Tea model for interactive activity view
 type activityModel struct {
 	events     []store.RepoEvent
 	commitMeta map[string]git.CommitMetadata
 
-	// Stats
+	// Stats, for the whole store even while only some pages are loaded
+	total    int
 	bySource map[store.Source]int
 	byRepo   map[string]int
+	byDevice map[string]int
+
+	// Paging: events arrive a page at a time, newest first, and their
+	// commit metadata is read in the background behind a spinner
+	loadPage    func(before *store.EventCursor) ([]store.RepoEvent, error)
+	loadMeta    func(events []store.RepoEvent) map[string]git.CommitMetadata
+	exhausted   bool
+	loading     bool
+	loadErr     string
+	metaPending int
+	spinner     components.ThemedSpinner
+	spinning    bool
+
+	// loadPush reads the refs a pre-push event sent, for the drawer; may
+	// be nil
+	loadPush func(id int64) []store.PushRef
 
 	// UI dimensions
-	width  int
-	height int
+	width   int
+	height  int
+	compact compactMode
 
 	// State
 	cursor       int
 	eventScroll  int
 	filterQuery  string
 	filterSource store.Source // -1 means no filter
+	filterDevice string       // "" means no filter
+	filterRepo   string       // repo name, "" means no filter
 
 	// Focus: 0=events, 1=sidebar, 2=drawer
 	focusedPanel  int
 	sidebarScroll int
+	repoCursor    int // the repo under BY REPO that Enter filters on
 
 	// Drawer
 	drawerOpen     bool
 	drawerDetail   *EventDetail
 	drawerViewport components.ThemedViewport
 
+	// Note editing in the drawer
+	noteEditing bool
+	noteInput   components.ThemedTextArea
+	noteMessage string
+	saveNote    func(id int64, note string) error
+
+	// Annotating (e): the tag, note and projects form under the selected
+	// event
+	annotating      bool
+	annotate        annotateForm
+	annotateMessage string
+	repoProjects    func(repoID string) ([]string, error)
+	saveAnnotation  func(a annotation) error
+
+	// Message search (/), answered by the database's search index rather
+	// than the loaded events
+	searching     bool
+	searchInput   components.ThemedInput
+	searchQuery   string
+	searchHits    map[int64]bool
+	searchMessage string
+	searchEvents  func(query string) (map[int64]bool, error)
+
+	// e exports pending events without leaving the view
+	export tuiExport
+
 	// Styling
 	colors style.ColorConfig
 }
@@ -90,27 +183,46 @@ func newActivityModel(events []store.RepoEvent, commitMeta map[string]git.Commit
 	// Calculate stats
 	bySource := make(map[store.Source]int)
 	byRepo := make(map[string]int)
+	byDevice := make(map[string]int)
 	for _, e := range events {
 		bySource[e.Source]++
 		byRepo[filepath.Base(e.RepoPath)]++
+		byDevice[deviceLabel(e)]++
 	}
 
 	return activityModel{
 		events:         events,
 		commitMeta:     commitMeta,
+		total:          len(events),
+		exhausted:      true,
+		spinner:        components.NewThemedSpinner(),
 		bySource:       bySource,
 		byRepo:         byRepo,
+		byDevice:       byDevice,
 		filterSource:   -1,
 		colors:         style.GetColors(),
 		drawerViewport: components.NewThemedViewport(40, 20),
+		noteInput:      components.NewThemedTextArea("What was this commit?"),
+		searchInput:    components.NewThemedInputWithPrompt("words in commit messages and notes", "/ "),
+		export:         newTUIExport(),
 	}
 }
 
+// activityStartMsg starts background loading once the program runs.
+type activityStartMsg struct{}
+
 func (m activityModel) Init() tea.Cmd {
-	return nil
+	if m.loadPage == nil && m.loadMeta == nil {
+		return nil
+	}
+	return func() tea.Msg { return activityStartMsg{} }
 }
 
 func (m activityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if cmd, ok := m.export.update(msg); ok {
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -118,20 +230,87 @@ func (m activityModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		return m.handleKey(msg)
+		return afterInput(m.handleKey(msg))
 
 	case tea.MouseMsg:
-		return m.handleMouse(msg)
+		if m.CapturingInput() {
+			return m, nil
+		}
+		return afterInput(m.handleMouse(msg))
+
+	case activityStartMsg:
+		meta := m.fetchMeta(m.events)
+		return m, tea.Batch(meta, m.maybeLoadMore())
+
+	case activityPageMsg:
+		return m.handlePage(msg)
+
+	case activityMetaMsg:
+		return m.handleMeta(msg)
+
+	case spinner.TickMsg:
+		if !m.busy() {
+			m.spinning = false
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
 	}
 
+	if m.noteEditing {
+		var cmd tea.Cmd
+		m.noteInput, cmd = m.noteInput.Update(msg)
+		return m, cmd
+	}
+	if m.annotating {
+		return m, m.annotate.update(msg)
+	}
+	if m.searching {
+		var cmd tea.Cmd
+		m.searchInput, cmd = m.searchInput.Update(msg)
+		return m, cmd
+	}
 	return m, nil
 }
 
+// afterInput reads the next page when a key or click moved the cursor, or
+// changed the filters, close to the end of what is loaded.
+func afterInput(model tea.Model, cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	m, ok := model.(activityModel)
+	if !ok {
+		return model, cmd
+	}
+	more := m.maybeLoadMore()
+	return m, tea.Batch(cmd, more)
+}
+
 func (m activityModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Global keys
 	switch msg.Type {
 	case tea.KeyCtrlC:
 		return m, tea.Quit
+	case tea.KeyCtrlT:
+		m.compact.toggle(m.width)
+		return m, nil
+	}
+
+	if m.noteEditing {
+		return m.handleNoteKeys(msg)
+	}
+	if m.annotating {
+		return m.handleAnnotateKeys(msg)
+	}
+	if m.searching {
+		return m.handleSearchKeys(msg)
+	}
+
+	m.annotateMessage = ""
+	switch msg.Type {
+	case tea.KeyRunes:
+		if msg.String() == "E" {
+			return m, m.export.begin()
+		}
 	case tea.KeyTab:
 		if m.drawerOpen {
 			m.focusedPanel = (m.focusedPanel + 1) % 3
@@ -151,6 +330,129 @@ func (m activityModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m.handleEventsKeys(msg)
 }
 
+// handleNoteKeys edits the note of the event in the drawer. Enter adds a
+// line, Ctrl+S saves and Esc throws the edit away.
+func (m activityModel) handleNoteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.noteEditing = false
+		m.noteInput.Blur()
+		m.noteMessage = ""
+		return m, nil
+	case tea.KeyCtrlS:
+		m.noteEditing = false
+		m.noteInput.Blur()
+		m.commitNote(strings.TrimSpace(m.noteInput.Value()))
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.noteInput, cmd = m.noteInput.Update(msg)
+	return m, cmd
+}
+
+// handleSearchKeys edits the message search. Enter runs it, Esc leaves the
+// current results alone.
+func (m activityModel) handleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.searching = false
+		m.searchInput.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		m.searching = false
+		m.searchInput.Blur()
+		m.runSearch(strings.TrimSpace(m.searchInput.Value()))
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	return m, cmd
+}
+
+// startSearch opens the message search input.
+func (m *activityModel) startSearch() tea.Cmd {
+	if m.searchEvents == nil {
+		return nil
+	}
+	m.searching = true
+	m.searchMessage = ""
+	m.searchInput.SetValue(m.searchQuery)
+	m.searchInput.CursorEnd()
+	return m.searchInput.Focus()
+}
+
+// runSearch limits the list to events matching query; an empty query
+// shows everything again.
+func (m *activityModel) runSearch(query string) {
+	m.cursor = 0
+	m.eventScroll = 0
+	if query == "" {
+		m.clearSearch()
+		return
+	}
+	hits, err := m.searchEvents(query)
+	if err != nil {
+		m.searchMessage = "Search failed: " + err.Error()
+		return
+	}
+	m.searchQuery = query
+	m.searchHits = hits
+	m.searchMessage = ""
+}
+
+func (m *activityModel) clearSearch() {
+	m.searchQuery = ""
+	m.searchHits = nil
+	m.searchMessage = ""
+}
+
+// startNoteEdit opens the note editor for the event in the drawer.
+func (m *activityModel) startNoteEdit() tea.Cmd {
+	if m.drawerDetail == nil {
+		return nil
+	}
+	if m.saveNote == nil || config.IsReadOnly() {
+		m.noteMessage = "Read-only mode: notes can't be changed"
+		return nil
+	}
+	_, _, drawerWidth := m.calculateWidths()
+	m.noteInput.SetSize(max(10, drawerWidth-6), 4)
+	m.noteEditing = true
+	m.noteMessage = ""
+	m.noteInput.SetValue(m.drawerDetail.Event.Note)
+	return m.noteInput.Focus()
+}
+
+// commitNote saves note on the event in the drawer and updates the loaded
+// events to match.
+func (m *activityModel) commitNote(note string) {
+	if m.drawerDetail == nil {
+		return
+	}
+	id := m.drawerDetail.Event.ID
+	if note == m.drawerDetail.Event.Note {
+		return
+	}
+	if err := m.saveNote(id, note); err != nil {
+		m.noteMessage = "Note not saved: " + err.Error()
+		return
+	}
+
+	for i := range m.events {
+		if m.events[i].ID == id {
+			m.events[i].Note = note
+		}
+	}
+	m.drawerDetail.Event.Note = note
+	if note == "" {
+		m.noteMessage = "Note removed"
+	} else {
+		m.noteMessage = "Note saved"
+	}
+}
+
 func (m activityModel) handleDrawerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEsc:
@@ -192,6 +494,12 @@ func (m activityModel) handleDrawerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "G":
 		m.drawerViewport.GotoBottom()
 		return m, nil
+	case "n":
+		cmd := m.startNoteEdit()
+		return m, cmd
+	case "e":
+		m.focusedPanel = 0
+		return m, m.startAnnotate()
 	}
 	return m, nil
 }
@@ -203,13 +511,22 @@ func (m activityModel) handleSidebarKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.filterSource = -1
 			return m, nil
 		}
+		if m.filterRepo != "" {
+			m.setRepoFilter("")
+			return m, nil
+		}
 		m.focusedPanel = 0
 		return m, nil
 	case tea.KeyUp:
-		m.sidebarScroll = max(0, m.sidebarScroll-1)
+		m.moveRepoCursor(-1)
 		return m, nil
 	case tea.KeyDown:
-		m.sidebarScroll++
+		m.moveRepoCursor(1)
+		return m, nil
+	case tea.KeyEnter:
+		if repos := topRepos(m.byRepo); m.repoCursor < len(repos) {
+			m.toggleRepoFilter(repos[m.repoCursor].name)
+		}
 		return m, nil
 	}
 
@@ -217,16 +534,22 @@ func (m activityModel) handleSidebarKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "q":
 		return m, tea.Quit
 	case "j":
-		m.sidebarScroll++
+		m.moveRepoCursor(1)
 		return m, nil
 	case "k":
-		m.sidebarScroll = max(0, m.sidebarScroll-1)
+		m.moveRepoCursor(-1)
 		return m, nil
 	case "c":
 		m.filterSource = -1
+		m.filterDevice = ""
+		m.filterRepo = ""
 		m.filterQuery = ""
+		m.clearSearch()
+		return m, nil
+	case "d":
+		m.cycleDeviceFilter()
 		return m, nil
-	case "1", "2", "3", "4", "5", "6", "7":
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9", "0":
 		return m.toggleSourceFilter(msg.String())
 	}
 	return m, nil
@@ -239,6 +562,10 @@ func (m activityModel) handleEventsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.filterQuery = ""
 			return m, nil
 		}
+		if m.searchQuery != "" {
+			m.clearSearch()
+			return m, nil
+		}
 		if m.drawerOpen {
 			m.drawerOpen = false
 			m.drawerDetail = nil
@@ -286,20 +613,14 @@ func (m activityModel) handleEventsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m activityModel) toggleSourceFilter(key string) (tea.Model, tea.Cmd) {
-	sourceMap := map[string]store.Source{
-		"1": store.SourcePostCommit,
-		"2": store.SourcePostRewrite,
-		"3": store.SourcePostCheckout,
-		"4": store.SourcePostMerge,
-		"5": store.SourcePrePush,
-		"6": store.SourceManual,
-		"7": store.SourceBackfill,
-	}
-	if source, ok := sourceMap[key]; ok {
-		if m.filterSource == source {
+	for _, sf := range sourceFilters(m.colors) {
+		if sf.key != key {
+			continue
+		}
+		if m.filterSource == sf.source {
 			m.filterSource = -1
 		} else {
-			m.filterSource = source
+			m.filterSource = sf.source
 		}
 		m.cursor = 0
 		m.eventScroll = 0
@@ -307,6 +628,70 @@ func (m activityModel) toggleSourceFilter(key string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// stepSourceFilter moves the source filter delta lines down the sidebar.
+func (m *activityModel) stepSourceFilter(delta int) {
+	m.filterSource = stepSource(m.filterSource, delta)
+	m.cursor = 0
+	m.eventScroll = 0
+}
+
+// moveRepoCursor moves the cursor under BY REPO and scrolls the sidebar to
+// keep it in view. Without repos, it scrolls the sidebar instead.
+func (m *activityModel) moveRepoCursor(delta int) {
+	repos := topRepos(m.byRepo)
+	if len(repos) == 0 {
+		m.sidebarScroll = max(0, m.sidebarScrollPos()+delta)
+		return
+	}
+	m.repoCursor = max(0, min(m.repoCursor+delta, len(repos)-1))
+
+	line := repoLinesStart + m.repoCursor
+	visible := max(1, m.mainHeight()-2)
+	scroll := m.sidebarScrollPos()
+	if line < scroll {
+		scroll = line
+	}
+	if line >= scroll+visible {
+		scroll = line - visible + 1
+	}
+	m.sidebarScroll = scroll
+}
+
+// toggleRepoFilter shows only the events of repo, or all of them again if
+// repo is already the filter.
+func (m *activityModel) toggleRepoFilter(repo string) {
+	if m.filterRepo == repo {
+		repo = ""
+	}
+	m.setRepoFilter(repo)
+}
+
+func (m *activityModel) setRepoFilter(repo string) {
+	m.filterRepo = repo
+	m.cursor = 0
+	m.eventScroll = 0
+}
+
+// cycleDeviceFilter steps the device filter through the devices in the
+// sidebar, busiest first, and back to showing all of them.
+func (m *activityModel) cycleDeviceFilter() {
+	devices := sortedCounts(m.byDevice)
+	next := ""
+	for i, d := range devices {
+		if d.name == m.filterDevice || m.filterDevice == "" {
+			if m.filterDevice == "" {
+				next = d.name
+			} else if i+1 < len(devices) {
+				next = devices[i+1].name
+			}
+			break
+		}
+	}
+	m.filterDevice = next
+	m.cursor = 0
+	m.eventScroll = 0
+}
+
 func (m activityModel) handleRunes(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
 
@@ -332,8 +717,16 @@ func (m activityModel) handleRunes(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "c":
 		m.filterQuery = ""
 		m.filterSource = -1
+		m.filterDevice = ""
+		m.filterRepo = ""
+		m.clearSearch()
 		return m, nil
-	case "1", "2", "3", "4", "5", "6", "7":
+	case "/":
+		cmd := m.startSearch()
+		return m, cmd
+	case "e":
+		return m, m.startAnnotate()
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9", "0":
 		return m.toggleSourceFilter(key)
 	default:
 		if len(key) == 1 && key[0] >= 32 && key[0] < 127 {
@@ -359,6 +752,15 @@ func (m activityModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		switch {
 		case msg.X < statsWidth:
 			m.focusedPanel = 1
+			scroll := m.sidebarScrollPos()
+			if key, ok := sourceKeyAt(msg.Y, scroll); ok {
+				return m.toggleSourceFilter(key)
+			}
+			repos := topRepos(m.byRepo)
+			if i, ok := repoIndexAt(msg.Y, scroll, len(repos)); ok {
+				m.repoCursor = i
+				m.toggleRepoFilter(repos[i].name)
+			}
 		case msg.X < drawerStart:
 			m.focusedPanel = 0
 			headerHeight := 3
@@ -378,7 +780,11 @@ func (m activityModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	case tea.MouseButtonWheelUp:
 		switch {
 		case msg.X < statsWidth:
-			m.sidebarScroll = max(0, m.sidebarScroll-1)
+			if _, ok := sourceKeyAt(msg.Y, m.sidebarScrollPos()); ok {
+				m.stepSourceFilter(-1)
+				break
+			}
+			m.sidebarScroll = max(0, m.sidebarScrollPos()-1)
 		case msg.X < drawerStart:
 			m.moveCursor(-1)
 		case m.drawerOpen:
@@ -388,7 +794,11 @@ func (m activityModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	case tea.MouseButtonWheelDown:
 		switch {
 		case msg.X < statsWidth:
-			m.sidebarScroll++
+			if _, ok := sourceKeyAt(msg.Y, m.sidebarScrollPos()); ok {
+				m.stepSourceFilter(1)
+				break
+			}
+			m.sidebarScroll = m.sidebarScrollPos() + 1
 		case msg.X < drawerStart:
 			m.moveCursor(1)
 		case m.drawerOpen:
@@ -422,10 +832,14 @@ func (m *activityModel) updateDrawerDetail() {
 		Event: event,
 		Meta:  meta,
 	}
+	if event.Source == store.SourcePrePush && m.loadPush != nil {
+		m.drawerDetail.Push = m.loadPush(event.ID)
+	}
+	m.noteMessage = ""
 }
 
 func (m activityModel) filteredEvents() []store.RepoEvent {
-	if m.filterQuery == "" && m.filterSource == -1 {
+	if m.filterQuery == "" && m.filterSource == -1 && m.filterDevice == "" && m.filterRepo == "" && m.searchHits == nil {
 		return m.events
 	}
 
@@ -437,6 +851,18 @@ func (m activityModel) filteredEvents() []store.RepoEvent {
 			continue
 		}
 
+		if m.filterDevice != "" && deviceLabel(e) != m.filterDevice {
+			continue
+		}
+
+		if m.filterRepo != "" && filepath.Base(e.RepoPath) != m.filterRepo {
+			continue
+		}
+
+		if m.searchHits != nil && !m.searchHits[e.ID] {
+			continue
+		}
+
 		if query != "" && !m.matchesQuery(e, query) {
 			continue
 		}
@@ -453,6 +879,8 @@ func (m activityModel) matchesQuery(e store.RepoEvent, query string) bool {
 	return strings.Contains(repoName, query) ||
 		strings.Contains(strings.ToLower(e.Branch), query) ||
 		strings.Contains(strings.ToLower(e.Commit), query) ||
+		strings.Contains(strings.ToLower(e.Cwd), query) ||
+		strings.Contains(strings.ToLower(e.Note), query) ||
 		strings.Contains(strings.ToLower(meta.Subject), query)
 }
 
@@ -473,16 +901,14 @@ func (m activityModel) calculateWidths() (stats, events, drawer int) {
 	return
 }
 
-// View renders the activity TUI
-func (m activityModel) View() string {
-	if m.width == 0 || m.height == 0 {
-		return ""
-	}
-
+// mainHeight is the height of the panels, between header and footer.
+func (m activityModel) mainHeight() int {
 	headerHeight := 3
 	footerHeight := 2
-	mainHeight := max(1, m.height-headerHeight-footerHeight)
+	return max(1, m.height-headerHeight-footerHeight)
+}
 
+func (m activityModel) newLayout() *splitpanel.Layout {
 	cfg := splitpanel.Config{
 		SidebarWidthPercent: 0.20,
 		SidebarMinWidth:     18,
@@ -493,6 +919,23 @@ func (m activityModel) View() string {
 	layout := splitpanel.NewLayout(m.width, cfg, m.colors)
 	layout.SetFocusedPanel(m.focusedPanel)
 	layout.SetDrawerOpen(m.drawerOpen)
+	return layout
+}
+
+// sidebarScrollPos is how far the sidebar is scrolled as drawn;
+// sidebarScroll itself can run past the end.
+func (m activityModel) sidebarScrollPos() int {
+	return m.buildStatsPanel(m.newLayout(), m.mainHeight()).ScrollPos
+}
+
+// View renders the activity TUI
+func (m activityModel) View() string {
+	if m.width == 0 || m.height == 0 {
+		return ""
+	}
+
+	mainHeight := m.mainHeight()
+	layout := m.newLayout()
 
 	statsPanel := m.buildStatsPanel(layout, mainHeight)
 	eventsPanel := m.buildEventsPanel(layout, mainHeight)
@@ -509,7 +952,7 @@ func (m activityModel) View() string {
 
 	footer := m.renderFooter()
 
-	return lipgloss.JoinVertical(lipgloss.Left, header, main, footer)
+	return m.export.render(lipgloss.JoinVertical(lipgloss.Left, header, main, footer))
 }
 
 func (m activityModel) renderHeader() string {
@@ -523,7 +966,13 @@ func (m activityModel) renderHeader() string {
 	activeStyle := lipgloss.NewStyle().Foreground(uiActiveColor)
 
 	title := titleStyle.Render("fp activity")
-	count := mutedStyle.Render(" | ") + mutedStyle.Render("Total: ") + titleStyle.Render(formatCount(len(m.events)))
+	count := mutedStyle.Render(" | ") + mutedStyle.Render("Total: ") + titleStyle.Render(formatCount(m.total))
+	if m.busy() {
+		count += " " + m.spinner.View() + mutedStyle.Render("loading")
+	}
+	if m.loadErr != "" {
+		count += mutedStyle.Render(" | could not load more: " + m.loadErr)
+	}
 
 	filterStr := ""
 	if m.filterSource != -1 {
@@ -532,6 +981,15 @@ func (m activityModel) renderHeader() string {
 	if m.filterQuery != "" {
 		filterStr += mutedStyle.Render(" | Search: ") + mutedStyle.Render(m.filterQuery)
 	}
+	if m.filterRepo != "" {
+		filterStr += mutedStyle.Render(" | Repo: ") + mutedStyle.Render(m.filterRepo)
+	}
+	if m.filterDevice != "" {
+		filterStr += mutedStyle.Render(" | Device: ") + mutedStyle.Render(m.filterDevice)
+	}
+	if m.searchQuery != "" {
+		filterStr += mutedStyle.Render(" | Messages: ") + mutedStyle.Render(m.searchQuery)
+	}
 
 	filtered := m.filteredEvents()
 	if len(filtered) != len(m.events) {
@@ -542,8 +1000,12 @@ func (m activityModel) renderHeader() string {
 	positionStr := ""
 	if len(filtered) > 0 {
 		current := m.cursor + 1
-		total := len(filtered)
-		positionStr = mutedStyle.Render(" | ") + activeStyle.Render(fmt.Sprintf("%d", current)) + mutedStyle.Render("/") + mutedStyle.Render(fmt.Sprintf("%d", total))
+		total := fmt.Sprintf("%d", len(filtered))
+		if !m.exhausted {
+			// More events are in the store than loaded
+			total += "+"
+		}
+		positionStr = mutedStyle.Render(" | ") + activeStyle.Render(fmt.Sprintf("%d", current)) + mutedStyle.Render("/") + mutedStyle.Render(total)
 	}
 
 	headerContent := title + count + filterStr + positionStr
@@ -571,28 +1033,13 @@ func (m *activityModel) buildStatsPanel(layout *splitpanel.Layout, height int) s
 
 	lines = append(lines, headerStyle.Render("SUMMARY"))
 	lines = append(lines, "")
-	lines = append(lines, labelStyle.Render("Events: ")+valueStyle.Render(formatCount(len(m.events))))
+	lines = append(lines, labelStyle.Render("Events: ")+valueStyle.Render(formatCount(m.total)))
 	lines = append(lines, "")
 
 	lines = append(lines, headerStyle.Render("BY SOURCE"))
 	lines = append(lines, "")
 
-	sourceFilters := []struct {
-		key    string
-		source store.Source
-		name   string
-		color  string
-	}{
-		{"1", store.SourcePostCommit, "POST-COMMIT", colors.Color1},
-		{"2", store.SourcePostRewrite, "POST-REWRITE", colors.Color2},
-		{"3", store.SourcePostCheckout, "POST-CHECKOUT", colors.Color3},
-		{"4", store.SourcePostMerge, "POST-MERGE", colors.Color4},
-		{"5", store.SourcePrePush, "PRE-PUSH", colors.Color5},
-		{"6", store.SourceManual, "MANUAL", colors.Color7},
-		{"7", store.SourceBackfill, "BACKFILL", colors.Color6},
-	}
-
-	for _, sf := range sourceFilters {
+	for _, sf := range sourceFilters(colors) {
 		count := m.bySource[sf.source]
 		indicator := "  "
 		if m.filterSource == sf.source {
@@ -606,6 +1053,21 @@ func (m *activityModel) buildStatsPanel(layout *splitpanel.Layout, height int) s
 		lines = append(lines, indicator+keyStyle.Render(sf.key)+" "+sourceNameStyle.Render(sf.name)+" "+countDisplay)
 	}
 
+	if len(m.byRepo) > 0 {
+		cursorStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(colors.UIActive))
+		lines = append(lines, "")
+		lines = append(lines, headerStyle.Render("BY REPO"))
+		lines = append(lines, "")
+		lines = append(lines, repoBreakdownLines(topRepos(m.byRepo), m.filterRepo, m.repoCursor, m.focusedPanel == 1, layout.SidebarContentWidth(), valueStyle, labelStyle, cursorStyle)...)
+	}
+
+	if len(m.byDevice) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, headerStyle.Render("BY DEVICE")+" "+keyStyle.Render("d"))
+		lines = append(lines, "")
+		lines = append(lines, deviceBreakdownLines(m.byDevice, m.filterDevice, layout.SidebarContentWidth(), valueStyle, labelStyle)...)
+	}
+
 	// height is the panel height, subtract 2 for borders
 	visibleHeight := max(1, height-2)
 	scrollPos := m.sidebarScroll
@@ -638,6 +1100,9 @@ func (m *activityModel) buildEventsPanel(layout *splitpanel.Layout, height int)
 	// height is the panel height
 	// Subtract: 2 for panel borders, 2 for header + separator
 	visibleDataRows := max(1, height-4)
+	if m.annotating {
+		visibleDataRows = max(1, visibleDataRows-annotateFormHeight)
+	}
 
 	// Calculate scroll to keep cursor visible
 	scrollOffset := m.eventScroll
@@ -665,9 +1130,10 @@ func (m *activityModel) buildEventsPanel(layout *splitpanel.Layout, height int)
 
 	// Header row
 	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(headerColor)
+	compact := m.compact.active(m.width)
 	header := "  " +
-		padRight("DATE", 10) + " " +
-		padRight("TIME", 5) + " " +
+		padRight("DATE", dateColumnWidth()) + " " +
+		padRight("TIME", timeColumnWidth()) + " " +
 		padRight("SOURCE", 13) + " " +
 		padRight("REPO", 12) + " " +
 		padRight("BRANCH", 12) + " " +
@@ -675,6 +1141,9 @@ func (m *activityModel) buildEventsPanel(layout *splitpanel.Layout, height int)
 		padLeft("+", 6) + " " +
 		padLeft("-", 6) + " " +
 		"MESSAGE"
+	if compact {
+		header = "  " + padRight("WHEN", compactWhenWidth) + " " + padRight("REPO", compactRepoWidth) + " MESSAGE"
+	}
 	lines = append(lines, headerStyle.Render(header))
 
 	// Separator line
@@ -683,7 +1152,7 @@ func (m *activityModel) buildEventsPanel(layout *splitpanel.Layout, height int)
 
 	if len(filtered) == 0 {
 		emptyStyle := lipgloss.NewStyle().Foreground(mutedColor).Italic(true)
-		if m.filterQuery != "" || m.filterSource != -1 {
+		if m.filterQuery != "" || m.filterSource != -1 || m.filterDevice != "" || m.filterRepo != "" || m.searchHits != nil {
 			lines = append(lines, emptyStyle.Render("No matching events"))
 		} else {
 			lines = append(lines, emptyStyle.Render("No events recorded"))
@@ -693,8 +1162,22 @@ func (m *activityModel) buildEventsPanel(layout *splitpanel.Layout, height int)
 		for i := scrollOffset; i < endIdx; i++ {
 			event := filtered[i]
 			isAlternate := (i-scrollOffset)%2 == 1
-			line := m.formatEventLine(event, width, i == m.cursor, isAlternate)
+			var line string
+			if compact {
+				// The list spans days, so rows show the date too
+				when := padRight(format.DateTimeShort(event.Timestamp), compactWhenWidth)
+				subject := m.commitMeta[event.Commit].Subject
+				if isAnnotated(event) {
+					subject = annotationMark + subject
+				}
+				line = compactEventLine(when, event.RepoPath, subject, width, i == m.cursor, isAlternate, m.sourceColor(event.Source), m.colors)
+			} else {
+				line = m.formatEventLine(event, width, i == m.cursor, isAlternate)
+			}
 			lines = append(lines, line)
+			if m.annotating && event.ID == m.annotate.event.ID {
+				lines = append(lines, m.annotate.view(*m)...)
+			}
 		}
 	}
 
@@ -719,6 +1202,7 @@ func (m activityModel) formatEventLine(event store.RepoEvent, width int, selecte
 
 	dateStr := format.Date(event.Timestamp)
 	timeStr := format.Time(event.Timestamp)
+	dateWidth, timeWidth := dateColumnWidth(), timeColumnWidth()
 
 	repoName := filepath.Base(event.RepoPath)
 	if len(repoName) > 12 {
@@ -744,12 +1228,18 @@ func (m activityModel) formatEventLine(event store.RepoEvent, width int, selecte
 		delStr = "-" + formatCount(meta.Deletions)
 	}
 
-	fixedWidth := 80
+	fixedWidth := 65 + dateWidth + timeWidth
 	msgWidth := max(5, width-fixedWidth)
+	if isAnnotated(event) {
+		msgWidth = max(5, msgWidth-lipgloss.Width(annotationMark))
+	}
 	message := meta.Subject
 	if len(message) > msgWidth {
 		message = message[:msgWidth-3] + "..."
 	}
+	if isAnnotated(event) {
+		message = annotationMark + message
+	}
 
 	prefix := "  "
 	if selected {
@@ -796,8 +1286,8 @@ func (m activityModel) formatEventLine(event store.RepoEvent, width int, selecte
 			Background(sourceColor)
 
 		line := prefix +
-			padRight(dateStr, 10) + " " +
-			padRight(timeStr, 5) + " " +
+			padRight(dateStr, dateWidth) + " " +
+			padRight(timeStr, timeWidth) + " " +
 			padRight(source, 13) + " " +
 			padRight(repoName, 12) + " " +
 			padRight(branch, 12) + " " +
@@ -810,8 +1300,8 @@ func (m activityModel) formatEventLine(event store.RepoEvent, width int, selecte
 	}
 
 	line := prefix +
-		timeStyle.Render(padRight(dateStr, 10)) + " " +
-		timeStyle.Render(padRight(timeStr, 5)) + " " +
+		timeStyle.Render(padRight(dateStr, dateWidth)) + " " +
+		timeStyle.Render(padRight(timeStr, timeWidth)) + " " +
 		sourceStyle.Render(padRight(source, 13)) + " " +
 		repoStyle.Render(padRight(repoName, 12)) + " " +
 		branchStyle.Render(padRight(branch, 12)) + " " +
@@ -882,11 +1372,59 @@ func (m *activityModel) buildDrawerPanel(layout *splitpanel.Layout, height int)
 		lines = append(lines, sourceStyle.Render(sourceName(event.Source))+" "+labelStyle.Render("on")+" "+valueStyle.Render(event.Branch))
 		lines = append(lines, "")
 
+		if push := m.drawerDetail.Push; len(push) > 0 {
+			lines = append(lines, headerStyle.Render("PUSH"))
+			lines = append(lines, "")
+			to := push[0].Remote
+			if push[0].RemoteURL != "" && push[0].RemoteURL != to {
+				to += " " + push[0].RemoteURL
+			}
+			lines = append(lines, labelStyle.Render("To:      ")+valueStyle.Render(to))
+			for _, r := range push {
+				line := valueStyle.Render(describePushRef(r))
+				switch r.Outcome {
+				case store.PushPushed:
+					line += " " + addStyle.Render("pushed")
+				case store.PushRejected:
+					line += " " + delStyle.Render("rejected")
+				case store.PushPending:
+					line += " " + labelStyle.Render("waiting")
+				}
+				lines = append(lines, "  "+line)
+			}
+			lines = append(lines, "")
+		}
+
+		switch {
+		case m.noteEditing:
+			lines = append(lines, headerStyle.Render("NOTE"))
+			lines = append(lines, "")
+			lines = append(lines, strings.Split(m.noteInput.View(), "\n")...)
+			lines = append(lines, "")
+		case event.Note != "":
+			lines = append(lines, headerStyle.Render("NOTE"))
+			lines = append(lines, "")
+			for _, noteLine := range strings.Split(event.Note, "\n") {
+				wrapped := wrapTextSimple(noteLine, width-2)
+				for _, wl := range strings.Split(wrapped, "\n") {
+					lines = append(lines, valueStyle.Render(wl))
+				}
+			}
+			lines = append(lines, "")
+		}
+		if m.noteMessage != "" {
+			lines = append(lines, labelStyle.Render(m.noteMessage))
+			lines = append(lines, "")
+		}
+
 		lines = append(lines, headerStyle.Render("DETAILS"))
 		lines = append(lines, "")
 		lines = append(lines, labelStyle.Render("Commit:  ")+valueStyle.Render(event.Commit))
 		lines = append(lines, labelStyle.Render("Repo:    ")+valueStyle.Render(filepath.Base(event.RepoPath)))
 		lines = append(lines, labelStyle.Render("Branch:  ")+valueStyle.Render(event.Branch))
+		if event.Tag != "" {
+			lines = append(lines, labelStyle.Render("Tag:     ")+valueStyle.Render(event.Tag))
+		}
 		lines = append(lines, "")
 
 		if meta.AuthorName != "" {
@@ -906,6 +1444,9 @@ func (m *activityModel) buildDrawerPanel(layout *splitpanel.Layout, height int)
 
 		lines = append(lines, "")
 		lines = append(lines, labelStyle.Render("Path:    ")+labelStyle.Render(event.RepoPath))
+		if event.Cwd != "" {
+			lines = append(lines, labelStyle.Render("Dir:     ")+labelStyle.Render(event.Cwd))
+		}
 
 		if meta.Body != "" {
 			lines = append(lines, "")
@@ -955,10 +1496,29 @@ func (m activityModel) renderFooter() string {
 	tabBinding := key.NewBinding(key.WithKeys("tab"), key.WithHelp("Tab", "focus"))
 
 	switch {
+	case m.searching:
+		bindings = []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "search")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "cancel")),
+		}
+	case m.annotating:
+		bindings = []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "save")),
+			key.NewBinding(key.WithKeys("tab", "shift+tab"), key.WithHelp("Tab/Shift+Tab", "field")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "cancel")),
+		}
+	case m.noteEditing:
+		bindings = []key.Binding{
+			key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("Ctrl+S", "save note")),
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "new line")),
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "cancel")),
+		}
 	case m.focusedPanel == 2 && m.drawerOpen:
 		bindings = []key.Binding{
 			tabBinding,
 			key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "close")),
+			key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "note")),
+			key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "annotate")),
 			key.NewBinding(key.WithKeys("j", "k"), key.WithHelp("jk", "scroll")),
 			key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "top")),
 			key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "bottom")),
@@ -967,7 +1527,9 @@ func (m activityModel) renderFooter() string {
 		bindings = []key.Binding{
 			tabBinding,
 			key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
-			key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7"), key.WithHelp("1-7", "filter")),
+			key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9", "0"), key.WithHelp("0-9", "filter")),
+			key.NewBinding(key.WithKeys("j", "k", "enter"), key.WithHelp("jk/Enter", "repo")),
+			key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "device")),
 			key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear")),
 		}
 	default:
@@ -976,20 +1538,35 @@ func (m activityModel) renderFooter() string {
 			key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
 			key.NewBinding(key.WithKeys("j", "k"), key.WithHelp("jk", "nav")),
 			key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "detail")),
-			key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7"), key.WithHelp("1-7", "source")),
+			key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9", "0"), key.WithHelp("0-9", "source")),
+			key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "messages")),
+			key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "annotate")),
+			key.NewBinding(key.WithKeys("E"), key.WithHelp("E", "export")),
 		}
-		if m.filterQuery == "" {
+		if m.filterQuery == "" && m.searchQuery == "" {
 			bindings = append(bindings, key.NewBinding(key.WithKeys(""), key.WithHelp("type", "search")))
 		} else {
 			bindings = append(bindings, key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear")))
 		}
 	}
+	if !m.CapturingInput() {
+		bindings = append(bindings, key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "keys")))
+	}
 
 	footerStyle := lipgloss.NewStyle().
 		Width(m.width).
 		Padding(0, 1)
 
-	return footerStyle.Render(help.ShortHelpView(bindings))
+	footer := help.ShortHelpView(bindings)
+	switch {
+	case m.searching:
+		footer = m.searchInput.View() + "  " + footer
+	case m.annotateMessage != "":
+		footer = m.annotateMessage + "  " + footer
+	case m.searchMessage != "":
+		footer = m.searchMessage + "  " + footer
+	}
+	return footerStyle.Render(footer)
 }
 
 func (m activityModel) sourceColor(source store.Source) lipgloss.Color {
@@ -1009,6 +1586,12 @@ func (m activityModel) sourceColor(source store.Source) lipgloss.Color {
 		return lipgloss.Color(colors.Color6)
 	case store.SourceManual:
 		return lipgloss.Color(colors.Color7)
+	case store.SourceBranchCreate:
+		return lipgloss.Color(colors.Color8)
+	case store.SourceBranchDelete:
+		return lipgloss.Color(colors.Color9)
+	case store.SourceStash:
+		return lipgloss.Color(colors.Color10)
 	default:
 		return lipgloss.Color(colors.Muted)
 	}
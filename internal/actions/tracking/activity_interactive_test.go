@@ -0,0 +1,185 @@
+package tracking
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func activityKeys(m activityModel, msgs ...tea.KeyMsg) activityModel {
+	for _, msg := range msgs {
+		updated, _ := m.Update(msg)
+		m = updated.(activityModel)
+	}
+	return m
+}
+
+func typeKeys(s string) []tea.KeyMsg {
+	var msgs []tea.KeyMsg
+	for _, r := range s {
+		msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return msgs
+}
+
+func TestActivityModel_EditNote(t *testing.T) {
+	setRedactionConfig(t)
+	events := []store.RepoEvent{
+		{ID: 7, RepoID: "github.com/user/repo", RepoPath: "/src/repo", Commit: "abc123", Branch: "main", Timestamp: time.Now()},
+	}
+	m := newActivityModel(events, map[string]git.CommitMetadata{})
+
+	saved := map[int64]string{}
+	m.saveNote = func(id int64, note string) error {
+		saved[id] = note
+		return nil
+	}
+
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyEnter}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	require.True(t, m.noteEditing)
+
+	// Keys go to the note while editing, q included
+	m = activityKeys(m, typeKeys("q prod hotfix")...)
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	require.False(t, m.noteEditing)
+	require.Equal(t, "q prod hotfix", saved[7])
+	require.Equal(t, "q prod hotfix", m.events[0].Note)
+	require.Equal(t, "Note saved", m.noteMessage)
+
+	// Esc throws the edit away
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = activityKeys(m, typeKeys(" again")...)
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	require.False(t, m.noteEditing)
+	require.Equal(t, "q prod hotfix", m.events[0].Note)
+
+	// The note is searchable
+	m.filterQuery = "hotfix"
+	require.Len(t, m.filteredEvents(), 1)
+}
+
+func TestActivityModel_EditNoteFails(t *testing.T) {
+	setRedactionConfig(t)
+	events := []store.RepoEvent{{ID: 1, Commit: "abc123", Timestamp: time.Now()}}
+	m := newActivityModel(events, map[string]git.CommitMetadata{})
+	m.saveNote = func(int64, string) error { return errors.New("database is locked") }
+
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyEnter}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = activityKeys(m, typeKeys("note")...)
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	require.Empty(t, m.events[0].Note)
+	require.Contains(t, m.noteMessage, "database is locked")
+}
+
+func TestActivityModel_NoteReadOnly(t *testing.T) {
+	setRedactionConfig(t, "read_only", "true")
+	events := []store.RepoEvent{{ID: 1, Commit: "abc123", Timestamp: time.Now()}}
+	m := newActivityModel(events, map[string]git.CommitMetadata{})
+	m.saveNote = func(int64, string) error { t.Fatal("saved in read-only mode"); return nil }
+
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyEnter}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	require.False(t, m.noteEditing)
+	require.Contains(t, m.noteMessage, "Read-only")
+}
+
+func TestActivityModel_SearchMessages(t *testing.T) {
+	events := []store.RepoEvent{
+		{ID: 1, RepoPath: "/src/repo", Commit: "aaa", Branch: "main", Timestamp: time.Now()},
+		{ID: 2, RepoPath: "/src/repo", Commit: "bbb", Branch: "main", Timestamp: time.Now()},
+	}
+	m := newActivityModel(events, map[string]git.CommitMetadata{})
+
+	var queries []string
+	m.searchEvents = func(query string) (map[int64]bool, error) {
+		queries = append(queries, query)
+		return map[int64]bool{2: true}, nil
+	}
+
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	require.True(t, m.searching)
+
+	// Keys go to the search while it's open, q included
+	m = activityKeys(m, typeKeys("q login")...)
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	require.False(t, m.searching)
+	require.Equal(t, []string{"q login"}, queries)
+	require.Equal(t, "q login", m.searchQuery)
+	require.Len(t, m.filteredEvents(), 1)
+	require.Equal(t, int64(2), m.filteredEvents()[0].ID)
+
+	// Esc clears the search
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	require.Empty(t, m.searchQuery)
+	require.Len(t, m.filteredEvents(), 2)
+}
+
+func TestActivityModel_SearchFails(t *testing.T) {
+	m := newActivityModel([]store.RepoEvent{{ID: 1, Commit: "aaa", Timestamp: time.Now()}}, map[string]git.CommitMetadata{})
+	m.searchEvents = func(string) (map[int64]bool, error) { return nil, errors.New("disk I/O error") }
+
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m = activityKeys(m, typeKeys("login")...)
+	m = activityKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, "Search failed: disk I/O error", m.searchMessage)
+	require.Len(t, m.filteredEvents(), 1)
+}
+
+func TestActivityModel_DeviceFilter(t *testing.T) {
+	events := []store.RepoEvent{
+		{ID: 1, RepoPath: "/src/repo", Commit: "aaa", Timestamp: time.Now(), Device: "laptop"},
+		{ID: 2, RepoPath: "/src/repo", Commit: "bbb", Timestamp: time.Now(), Device: "laptop"},
+		{ID: 3, RepoPath: "/src/repo", Commit: "ccc", Timestamp: time.Now(), Device: "desktop"},
+		{ID: 4, RepoPath: "/src/repo", Commit: "ddd", Timestamp: time.Now()},
+	}
+	m := newActivityModel(events, map[string]git.CommitMetadata{})
+	require.Equal(t, map[string]int{"laptop": 2, "desktop": 1, unknownDevice: 1}, m.byDevice)
+
+	// d steps through devices busiest first, then back to all
+	m.cycleDeviceFilter()
+	require.Equal(t, "laptop", m.filterDevice)
+	require.Len(t, m.filteredEvents(), 2)
+
+	m.cycleDeviceFilter()
+	require.Equal(t, "desktop", m.filterDevice)
+	require.Len(t, m.filteredEvents(), 1)
+
+	m.cycleDeviceFilter()
+	require.Equal(t, unknownDevice, m.filterDevice)
+	require.Equal(t, "ddd", m.filteredEvents()[0].Commit)
+
+	m.cycleDeviceFilter()
+	require.Empty(t, m.filterDevice)
+	require.Len(t, m.filteredEvents(), 4)
+}
+
+func TestActivityModel_PushDetails(t *testing.T) {
+	setRedactionConfig(t)
+	sha := "abc1230000000000000000000000000000000000"
+	events := []store.RepoEvent{
+		{ID: 3, RepoID: "github.com/user/repo", RepoPath: "/src/repo", Commit: sha, Branch: "main", Source: store.SourcePrePush, Timestamp: time.Now()},
+	}
+	m := newActivityModel(events, map[string]git.CommitMetadata{})
+	m.loadPush = func(id int64) []store.PushRef {
+		require.Equal(t, int64(3), id)
+		return []store.PushRef{{
+			Remote: "origin", RemoteURL: "git@github.com:user/repo.git", RemoteRef: "refs/heads/main",
+			LocalSHA: sha, RemoteSHA: sha, Commits: 2, Outcome: store.PushPushed,
+		}}
+	}
+
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	m = activityKeys(updated.(activityModel), tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, m.drawerDetail)
+	require.Len(t, m.drawerDetail.Push, 1)
+
+	view := m.View()
+	require.Contains(t, view, "PUSH")
+	require.Contains(t, view, "origin git@github.com:user/repo.git")
+	require.Contains(t, view, "main +2 commits pushed")
+}
@@ -0,0 +1,108 @@
+package tracking
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/footprint-tools/cli/internal/ui/components"
+)
+
+// activityKeyMap is the keys of fp activity -i.
+type activityKeyMap struct {
+	components.ListKeyMap
+	Open      key.Binding
+	Annotate  key.Binding
+	Search    key.Binding
+	Backspace key.Binding
+	Sources   key.Binding
+	Clear     key.Binding
+	Device    key.Binding
+	Note      key.Binding
+	Escape    key.Binding
+	Close     key.Binding
+	Quit      key.Binding
+	Tab       key.Binding
+	Compact   key.Binding
+	Export    key.Binding
+}
+
+var activityBindings = activityKeyMap{
+	ListKeyMap: components.NewListKeyMap(),
+	Open:       key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "open details")),
+	Annotate:   key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "annotate: tag, note and projects (Enter saves, Esc cancels)")),
+	Search:     key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search commit messages and notes")),
+	Backspace:  key.NewBinding(key.WithKeys("backspace"), key.WithHelp("Backspace", "delete from the filter")),
+	Sources:    key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9", "0"), key.WithHelp("0-9", "show one source")),
+	Clear:      key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear filters and search")),
+	Device:     key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "next device")),
+	Note:       key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "edit note (Ctrl+S saves, Esc cancels)")),
+	Escape:     key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "clear filter, close details or quit")),
+	Close:      key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("Esc/q", "close")),
+	Quit:       key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+	Tab:        key.NewBinding(key.WithKeys("tab"), key.WithHelp("Tab", "next panel")),
+	Compact:    key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("Ctrl+T", "compact / full rows")),
+	Export:     key.NewBinding(key.WithKeys("E"), key.WithHelp("E", "export pending events now")),
+}
+
+// HelpSections lists the keys of fp activity -i, the focused panel first.
+func (m activityModel) HelpSections() []components.HelpSection {
+	k := activityBindings
+	events := components.HelpSection{Title: "Events", Bindings: []key.Binding{
+		k.UpDown,
+		components.Describe(k.Page, "move 10"),
+		k.FirstLast,
+		k.Open,
+		k.Annotate,
+		k.Search,
+		components.HelpKey("type", "filter by repo, branch or commit"),
+		k.Backspace,
+		k.Sources,
+		k.Clear,
+		k.Escape,
+		k.Quit,
+	}}
+	sidebar := components.HelpSection{Title: "Sidebar", Bindings: []key.Binding{
+		components.Describe(k.UpDown, "select a repo"),
+		components.Describe(k.Open, "toggle showing only the selected repo"),
+		k.Sources,
+		components.HelpKey("click/wheel", "toggle / step a source"),
+		components.HelpKey("click a repo", "toggle showing only it"),
+		k.Device,
+		k.Clear,
+		components.Describe(k.Escape, "clear source, repo or back to events"),
+		k.Quit,
+	}}
+	drawer := components.HelpSection{Title: "Details", Bindings: []key.Binding{
+		components.Describe(k.UpDown, "scroll"),
+		components.Describe(k.Page, "scroll 10"),
+		components.Describe(k.FirstLast, "top / bottom"),
+		k.Note,
+		components.Describe(k.Annotate, "annotate the event in the events list"),
+		k.Close,
+	}}
+	general := components.HelpSection{Title: "Everywhere", Bindings: []key.Binding{
+		k.Tab,
+		components.HelpKey("click/wheel", "focus, select and scroll"),
+		k.Compact,
+		k.Export,
+		k.Help,
+		k.ForceQuit,
+	}}
+
+	sections := []components.HelpSection{events, sidebar}
+	switch {
+	case m.drawerOpen && m.focusedPanel == 2:
+		sections = []components.HelpSection{drawer, events, sidebar}
+	case m.focusedPanel == 1:
+		sections = []components.HelpSection{sidebar, events}
+	}
+	if m.drawerOpen && m.focusedPanel != 2 {
+		sections = append(sections, drawer)
+	}
+	return append(sections, general)
+}
+
+// CapturingInput reports whether a search, note or annotation is being
+// typed.
+func (m activityModel) CapturingInput() bool {
+	return m.searching || m.noteEditing || m.annotating
+}
@@ -0,0 +1,153 @@
+package tracking
+
+import (
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+const (
+	// activityPageSize is how many events the interactive view reads at a
+	// time. The first page is read before the view opens, so it is kept
+	// small enough to feel instant on any store.
+	activityPageSize = 500
+	// activityPrefetchRows is how close the cursor gets to the last loaded
+	// event before the next page is read.
+	activityPrefetchRows = 100
+)
+
+// activityPageMsg delivers the next page of events, read in the background.
+type activityPageMsg struct {
+	events []store.RepoEvent
+	err    error
+}
+
+// activityMetaMsg delivers commit metadata read in the background.
+type activityMetaMsg struct {
+	meta map[string]git.CommitMetadata
+}
+
+// setCounts shows totals for the whole store in the summary and sidebar,
+// rather than for the pages loaded so far.
+func (m *activityModel) setCounts(counts store.EventCounts) {
+	m.total = counts.Total
+	m.bySource = counts.BySource
+	m.byRepo = make(map[string]int, len(counts.ByRepo))
+	for path, n := range counts.ByRepo {
+		m.byRepo[filepath.Base(path)] += n
+	}
+	m.byDevice = make(map[string]int, len(counts.ByDevice))
+	for device, n := range counts.ByDevice {
+		m.byDevice[deviceLabel(store.RepoEvent{Device: device})] += n
+	}
+}
+
+// busy reports whether events or their metadata are still being read.
+func (m activityModel) busy() bool {
+	return m.loading || m.metaPending > 0
+}
+
+// maybeLoadMore reads the next page once the cursor nears the end of the
+// events shown. With a filter that matches few of them, that keeps pages
+// coming until the view fills or the store runs out.
+func (m *activityModel) maybeLoadMore() tea.Cmd {
+	if m.loadPage == nil || m.exhausted || m.loading || len(m.events) == 0 {
+		return nil
+	}
+	if len(m.filteredEvents())-m.cursor > activityPrefetchRows {
+		return nil
+	}
+
+	m.loading = true
+	loadPage := m.loadPage
+	before := store.CursorAfter(m.events[len(m.events)-1])
+	fetch := func() tea.Msg {
+		events, err := loadPage(before)
+		return activityPageMsg{events: events, err: err}
+	}
+	return tea.Batch(fetch, m.startSpinner())
+}
+
+// fetchMeta reads the metadata of the commits in events that the view
+// doesn't have yet. Rows show without subjects until it arrives.
+func (m *activityModel) fetchMeta(events []store.RepoEvent) tea.Cmd {
+	if m.loadMeta == nil {
+		return nil
+	}
+	var missing []store.RepoEvent
+	seen := make(map[string]bool)
+	for _, e := range events {
+		if _, ok := m.commitMeta[e.Commit]; ok || seen[e.Commit] {
+			continue
+		}
+		seen[e.Commit] = true
+		missing = append(missing, e)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	m.metaPending++
+	loadMeta := m.loadMeta
+	fetch := func() tea.Msg {
+		return activityMetaMsg{meta: loadMeta(missing)}
+	}
+	return tea.Batch(fetch, m.startSpinner())
+}
+
+// startSpinner starts the spinner unless it is already turning. It stops
+// by itself once nothing is loading.
+func (m *activityModel) startSpinner() tea.Cmd {
+	if m.spinning {
+		return nil
+	}
+	m.spinning = true
+	return m.spinner.Tick
+}
+
+// handlePage adds a page of events and asks for their metadata, and for
+// another page if the view still needs one.
+func (m activityModel) handlePage(msg activityPageMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+	if msg.err != nil {
+		m.loadErr = msg.err.Error()
+		m.exhausted = true
+		return m, nil
+	}
+	m.events = append(m.events, msg.events...)
+	m.exhausted = len(msg.events) < activityPageSize
+	meta := m.fetchMeta(msg.events)
+	return m, tea.Batch(meta, m.maybeLoadMore())
+}
+
+// handleMeta merges metadata read in the background.
+func (m activityModel) handleMeta(msg activityMetaMsg) (tea.Model, tea.Cmd) {
+	m.metaPending--
+	for commit, meta := range msg.meta {
+		m.commitMeta[commit] = meta
+	}
+	if m.drawerDetail != nil {
+		if meta, ok := msg.meta[m.drawerDetail.Event.Commit]; ok {
+			detail := *m.drawerDetail
+			detail.Meta = meta
+			m.drawerDetail = &detail
+		}
+	}
+	return m, nil
+}
+
+// loadCommitMeta reads the metadata of each commit in events, from the
+// store where it is kept and from git otherwise.
+func loadCommitMeta(metas *commitMetaReader, events []store.RepoEvent) map[string]git.CommitMetadata {
+	meta := make(map[string]git.CommitMetadata, len(events))
+	metas.Prefetch(events)
+	for _, e := range events {
+		if _, ok := meta[e.Commit]; !ok {
+			meta[e.Commit] = metas.Of(e)
+		}
+	}
+	return meta
+}
@@ -0,0 +1,127 @@
+package tracking
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// runCmd runs cmd and any batch it returns, and collects the messages.
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, runCmd(c)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+// pagedEvents returns n events, newest first, and a loadPage that reads
+// them the way ListEvents does with Before and activityPageSize.
+func pagedEvents(n int) ([]store.RepoEvent, func(*store.EventCursor) ([]store.RepoEvent, error)) {
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	events := make([]store.RepoEvent, n)
+	for i := range events {
+		events[i] = store.RepoEvent{ID: int64(n - i), Commit: fmt.Sprintf("c%04d", n-i), Timestamp: start.Add(-time.Duration(i) * time.Minute)}
+	}
+	loadPage := func(before *store.EventCursor) ([]store.RepoEvent, error) {
+		for i, e := range events {
+			if e.ID < before.ID {
+				return events[i:min(i+activityPageSize, n)], nil
+			}
+		}
+		return nil, nil
+	}
+	return events, loadPage
+}
+
+func TestActivityModel_LoadsPagesNearTheEnd(t *testing.T) {
+	all, loadPage := pagedEvents(1200)
+	m := newActivityModel(all[:activityPageSize], map[string]git.CommitMetadata{})
+	m.setCounts(store.EventCounts{Total: len(all)})
+	m.exhausted = false
+	m.loadPage = loadPage
+
+	require.Nil(t, m.maybeLoadMore(), "far from the end of the loaded events")
+
+	m.cursor = activityPageSize - activityPrefetchRows
+	cmd := m.maybeLoadMore()
+	require.NotNil(t, cmd)
+	require.True(t, m.loading)
+	require.Nil(t, m.maybeLoadMore(), "one page at a time")
+
+	for _, want := range []struct {
+		loaded    int
+		exhausted bool
+	}{{1000, false}, {1200, true}} {
+		var page *activityPageMsg
+		for _, msg := range runCmd(cmd) {
+			if p, ok := msg.(activityPageMsg); ok {
+				page = &p
+			}
+		}
+		require.NotNil(t, page)
+
+		updated, _ := m.handlePage(*page)
+		m = updated.(activityModel)
+		require.Len(t, m.events, want.loaded)
+		require.Equal(t, want.exhausted, m.exhausted)
+
+		m.cursor = len(m.events) - 1
+		cmd = m.maybeLoadMore()
+	}
+	require.Nil(t, cmd, "nothing left to load")
+	require.Equal(t, all, m.events, "pages arrive in order, without gaps or repeats")
+	require.Equal(t, 1200, m.total)
+}
+
+func TestActivityModel_MetadataInBackground(t *testing.T) {
+	events := []store.RepoEvent{{ID: 2, Commit: "bbb"}, {ID: 1, Commit: "aaa"}, {ID: 3, Commit: "aaa"}}
+	m := newActivityModel(events, map[string]git.CommitMetadata{})
+	m.loadMeta = func(events []store.RepoEvent) map[string]git.CommitMetadata {
+		meta := map[string]git.CommitMetadata{}
+		for _, e := range events {
+			meta[e.Commit] = git.CommitMetadata{Subject: "subject of " + e.Commit}
+		}
+		return meta
+	}
+
+	updated, cmd := m.Update(activityStartMsg{})
+	m = updated.(activityModel)
+	require.True(t, m.busy())
+
+	for _, msg := range runCmd(cmd) {
+		if meta, ok := msg.(activityMetaMsg); ok {
+			require.Len(t, meta.meta, 2, "each commit is read once")
+			updated, _ = m.Update(meta)
+			m = updated.(activityModel)
+		}
+	}
+	require.False(t, m.busy())
+	require.Equal(t, "subject of aaa", m.commitMeta["aaa"].Subject)
+}
+
+func TestActivityModel_SetCounts(t *testing.T) {
+	m := newActivityModel(nil, map[string]git.CommitMetadata{})
+	m.setCounts(store.EventCounts{
+		Total:    5,
+		BySource: map[store.Source]int{store.SourcePostCommit: 5},
+		ByRepo:   map[string]int{"/code/app": 3, "/work/app": 1, "/code/lib": 1},
+		ByDevice: map[string]int{"laptop": 4, "": 1},
+	})
+	require.Equal(t, 5, m.total)
+	require.Equal(t, map[string]int{"app": 4, "lib": 1}, m.byRepo, "repos are shown by directory name")
+	require.Equal(t, map[string]int{"laptop": 4, unknownDevice: 1}, m.byDevice)
+}
@@ -2,9 +2,12 @@ package tracking
 
 import (
 	"database/sql"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/footprint-tools/cli/internal/config"
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/store"
 	_ "github.com/mattn/go-sqlite3"
@@ -410,3 +413,158 @@ func TestActivity_JSON_WithEvents(t *testing.T) {
 		t.Errorf("activity() JSON should contain commit, got %q", printedOutput)
 	}
 }
+
+func groupTestEvents() []store.RepoEvent {
+	day1 := time.Date(2025, 6, 3, 10, 0, 0, 0, time.Local)
+	day2 := time.Date(2025, 6, 2, 10, 0, 0, 0, time.Local)
+	return []store.RepoEvent{
+		{ID: 1, RepoID: "github.com/me/api", Branch: "main", Source: store.SourcePostCommit, Timestamp: day1},
+		{ID: 2, RepoID: "github.com/me/web", Branch: "main", Source: store.SourcePostCommit, Timestamp: day1},
+		{ID: 3, RepoID: "github.com/me/web", Branch: "feature", Source: store.SourcePrePush, Timestamp: day2},
+		{ID: 4, RepoID: "github.com/me/web", Branch: "main", Source: store.SourcePostCommit, Timestamp: day2},
+	}
+}
+
+func TestGroupEvents(t *testing.T) {
+	tests := []struct {
+		by     string
+		keys   []string
+		counts []int
+	}{
+		{"repo", []string{"github.com/me/web", "github.com/me/api"}, []int{3, 1}},
+		{"branch", []string{"main", "main", "feature"}, []int{2, 1, 1}},
+		{"day", []string{"2025-06-03", "2025-06-02"}, []int{2, 2}},
+		{"source", []string{"POST-COMMIT", "PRE-PUSH"}, []int{3, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			groups := groupEvents(groupTestEvents(), tt.by)
+			if len(groups) != len(tt.keys) {
+				t.Fatalf("groupEvents(%q) returned %d groups, want %d", tt.by, len(groups), len(tt.keys))
+			}
+			for i, g := range groups {
+				if g.Key != tt.keys[i] || len(g.Events) != tt.counts[i] {
+					t.Errorf("group %d = %q (%d events), want %q (%d events)", i, g.Key, len(g.Events), tt.keys[i], tt.counts[i])
+				}
+			}
+		})
+	}
+
+	branches := groupEvents(groupTestEvents(), "branch")
+	if branches[0].RepoID != "github.com/me/web" || branches[1].RepoID != "github.com/me/api" {
+		t.Errorf("branch groups should keep repositories apart, got %q and %q", branches[0].RepoID, branches[1].RepoID)
+	}
+}
+
+func TestActivity_GroupBy(t *testing.T) {
+	var pagerOutput string
+	deps := Deps{
+		DBPath: func() string { return ":memory:" },
+		OpenDB: func(path string) (*sql.DB, error) {
+			return sql.Open("sqlite3", path)
+		},
+		ListEvents: func(*sql.DB, store.EventFilter) ([]store.RepoEvent, error) {
+			return groupTestEvents(), nil
+		},
+		Pager:   func(s string) { pagerOutput = s },
+		Println: func(...any) (int, error) { return 0, nil },
+	}
+
+	flags := dispatchers.NewParsedFlags([]string{"--group-by=repo", "--oneline"})
+	if err := activity([]string{}, flags, deps); err != nil {
+		t.Fatalf("activity() unexpected error = %v", err)
+	}
+	if !strings.Contains(pagerOutput, "(3 events)") || !strings.Contains(pagerOutput, "(1 event)") {
+		t.Errorf("activity() output missing subtotals:\n%s", pagerOutput)
+	}
+	if !strings.Contains(pagerOutput, "4 events in 2 groups") {
+		t.Errorf("activity() output missing total:\n%s", pagerOutput)
+	}
+
+	flags = dispatchers.NewParsedFlags([]string{"--group-by=week"})
+	err := activity([]string{}, flags, deps)
+	if err == nil || !strings.Contains(err.Error(), "valid values are repo, branch, day, source") {
+		t.Errorf("activity() error = %v, want invalid --group-by error", err)
+	}
+}
+
+func TestActivity_Mine(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	lines, _ := config.ReadLines()
+	lines, _ = config.Set(lines, "identities", "Me@Work.com")
+	if err := config.WriteLines(lines); err != nil {
+		t.Fatal(err)
+	}
+
+	restore := commitAuthorEmail
+	defer func() { commitAuthorEmail = restore }()
+	commitAuthorEmail = func(_, commit string) string {
+		if commit == "mine" {
+			return "me@work.com"
+		}
+		return "someone@else.org"
+	}
+
+	var printed string
+	deps := Deps{
+		DBPath: func() string { return ":memory:" },
+		OpenDB: func(path string) (*sql.DB, error) {
+			return sql.Open("sqlite3", path)
+		},
+		ListEvents: func(_ *sql.DB, filter store.EventFilter) ([]store.RepoEvent, error) {
+			if filter.Limit != 0 {
+				t.Errorf("--mine should apply the limit after filtering, got SQL limit %d", filter.Limit)
+			}
+			return []store.RepoEvent{
+				{ID: 1, Commit: "theirs"},
+				{ID: 2, Commit: "mine"},
+				{ID: 3, Commit: "mine"},
+			}, nil
+		},
+		Pager: func(string) {},
+		Println: func(a ...any) (int, error) {
+			printed = a[0].(string)
+			return 0, nil
+		},
+	}
+
+	flags := dispatchers.NewParsedFlags([]string{"--mine", "--json", "--limit=1"})
+	if err := activity([]string{}, flags, deps); err != nil {
+		t.Fatalf("activity() unexpected error = %v", err)
+	}
+	if !strings.Contains(printed, `"id": 2`) || strings.Contains(printed, `"id": 1`) || strings.Contains(printed, `"id": 3`) {
+		t.Errorf("activity --mine --limit=1 returned the wrong events:\n%s", printed)
+	}
+	if !strings.Contains(printed, `"identity": "me@work.com"`) {
+		t.Errorf("activity --mine should tag events with the identity:\n%s", printed)
+	}
+}
+
+func TestLoadEventFields(t *testing.T) {
+	db, err := openDBFresh(filepath.Join(t.TempDir(), "store.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.CloseDB(db)
+
+	if err := store.SaveCommitFields(db, "github.com/user/repo", "abc123", map[string]string{"ticket.id": "PAY-42"}); err != nil {
+		t.Fatal(err)
+	}
+
+	enriched := store.RepoEvent{RepoID: "github.com/user/repo", Commit: "abc123"}
+	plain := store.RepoEvent{RepoID: "github.com/user/repo", Commit: "def456"}
+	fields := loadEventFields(db, []store.RepoEvent{enriched, plain, enriched})
+
+	if got := toJSONEvent(enriched, nil, nil, fields, nil).Fields["ticket.id"]; got != "PAY-42" {
+		t.Errorf("fields of enriched commit: ticket.id = %q, want PAY-42", got)
+	}
+	if got := toJSONEvent(plain, nil, nil, fields, nil).Fields; got != nil {
+		t.Errorf("fields of plain commit = %v, want none", got)
+	}
+	if got := toJSONEvent(enriched, nil, nil, nil, nil).Fields; got != nil {
+		t.Errorf("fields without --enrich = %v, want none", got)
+	}
+}
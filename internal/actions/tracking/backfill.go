@@ -1,11 +1,13 @@
 package tracking
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
 	"github.com/footprint-tools/cli/internal/output"
 	"github.com/footprint-tools/cli/internal/store"
 	"github.com/footprint-tools/cli/internal/usage"
@@ -17,6 +19,16 @@ func Backfill(args []string, flags *dispatchers.ParsedFlags) error {
 }
 
 func backfill(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	if flags.String("--from-github", "") != "" {
+		return backfillFromGitHub(args, flags, deps)
+	}
+	if flags.String("--author", "") != "" {
+		return fmt.Errorf("--author only applies to --from-github")
+	}
+	if flags.Has("--all") {
+		return backfillAll(args, flags, deps)
+	}
+
 	jsonOutput := flags.Has("--json")
 
 	if flags.Has("--dry-run") {
@@ -58,6 +70,67 @@ func setupBackfill(args []string, deps Deps) (repoID string, repoRoot string, er
 	return string(id), repoRoot, nil
 }
 
+// backfillListOptions builds the commit listing options from --since, --until and --limit.
+func backfillListOptions(flags *dispatchers.ParsedFlags) git.ListCommitsOptions {
+	return git.ListCommitsOptions{
+		Since: backfillDate(flags, "--since"),
+		Until: backfillDate(flags, "--until"),
+		Limit: flags.Int("--limit", 0),
+	}
+}
+
+// backfillDate returns a date flag for git log: as a day when fp can read
+// it, so "7d" and month names work like elsewhere, or as given for git to
+// interpret.
+func backfillDate(flags *dispatchers.ParsedFlags, name string) string {
+	if d := flags.Date(name); d != nil {
+		return d.Format("2006-01-02")
+	}
+	return flags.String(name, "")
+}
+
+// backfillBranch returns the branch to record for a commit, inferring it
+// from git unless an override was given.
+func backfillBranch(repoRoot, hash, override string) string {
+	if override != "" {
+		return override
+	}
+	if branch := git.GetBranchForCommit(repoRoot, hash); branch != "" {
+		return branch
+	}
+	return "unknown"
+}
+
+// newBackfillEvent builds the pending event recorded for an imported commit.
+func newBackfillEvent(repoID, repoRoot string, c git.HistoryCommit, branchOverride string) store.RepoEvent {
+	timestamp, err := time.Parse(time.RFC3339, c.AuthorDate)
+	if err != nil {
+		timestamp = time.Now().UTC()
+	}
+
+	return store.RepoEvent{
+		RepoID:    repoID,
+		RepoPath:  repoRoot,
+		Commit:    c.Hash,
+		Branch:    backfillBranch(repoRoot, c.Hash, branchOverride),
+		Timestamp: timestamp.UTC(),
+		Status:    store.StatusPending,
+		Source:    store.SourceBackfill,
+		Device:    deviceName(),
+	}
+}
+
+// saveBackfillText stores the subject of an imported commit for activity
+// search. Text recorded by the hooks, which includes the body, is kept.
+func saveBackfillText(db *sql.DB, repoID string, c git.HistoryCommit) {
+	if ok, err := store.HasCommitText(db, repoID, c.Hash); err != nil || ok {
+		return
+	}
+	if err := store.SaveCommitText(db, repoID, c.Hash, c.Subject, ""); err != nil {
+		log.Debug("backfill: could not store subject of %.7s: %v", c.Hash, err)
+	}
+}
+
 // doBackfillText performs the backfill and prints text output.
 func doBackfillText(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	repoID, repoRoot, err := setupBackfill(args, deps)
@@ -65,11 +138,7 @@ func doBackfillText(args []string, flags *dispatchers.ParsedFlags, deps Deps) er
 		return err
 	}
 
-	opts := git.ListCommitsOptions{
-		Since: flags.String("--since", ""),
-		Until: flags.String("--until", ""),
-		Limit: flags.Int("--limit", 0),
-	}
+	opts := backfillListOptions(flags)
 
 	commits, err := git.ListCommits(repoRoot, opts)
 	if err != nil {
@@ -92,32 +161,16 @@ func doBackfillText(args []string, flags *dispatchers.ParsedFlags, deps Deps) er
 	_ = deps.InitDB(db)
 
 	branchOverride := flags.String("--branch", "")
+	saveDefaultBranch(db, deps, repoRoot, repoID)
 
+	saveCommitMetas(db, deps, repoRoot, repoID, historyHashes(commits))
 	imported := 0
 	skipped := 0
 	for _, c := range commits {
-		branch := branchOverride
-		if branch == "" {
-			branch = git.GetBranchForCommit(repoRoot, c.Hash)
-			if branch == "" {
-				branch = "unknown"
-			}
-		}
-
-		timestamp, err := time.Parse(time.RFC3339, c.AuthorDate)
-		if err != nil {
-			timestamp = time.Now().UTC()
-		}
-
-		event := store.RepoEvent{
-			RepoID:    repoID,
-			RepoPath:  repoRoot,
-			Commit:    c.Hash,
-			Branch:    branch,
-			Timestamp: timestamp.UTC(),
-			Status:    store.StatusPending,
-			Source:    store.SourceBackfill,
-		}
+		event := newBackfillEvent(repoID, repoRoot, c, branchOverride)
+		saveBackfillText(db, repoID, c)
+		saveCommitMeta(db, deps, repoRoot, repoID, c.Hash)
+		saveCommitLanguages(db, deps, repoRoot, repoID, c.Hash)
 
 		if err := deps.InsertEvent(db, event); err == nil {
 			imported++
@@ -137,11 +190,7 @@ func doBackfillDryRun(args []string, flags *dispatchers.ParsedFlags, deps Deps)
 		return err
 	}
 
-	opts := git.ListCommitsOptions{
-		Since: flags.String("--since", ""),
-		Until: flags.String("--until", ""),
-		Limit: flags.Int("--limit", 0),
-	}
+	opts := backfillListOptions(flags)
 
 	commits, err := git.ListCommits(repoRoot, opts)
 	if err != nil {
@@ -154,13 +203,7 @@ func doBackfillDryRun(args []string, flags *dispatchers.ParsedFlags, deps Deps)
 	branchOverride := flags.String("--branch", "")
 
 	for _, c := range commits {
-		branch := branchOverride
-		if branch == "" {
-			branch = git.GetBranchForCommit(repoRoot, c.Hash)
-			if branch == "" {
-				branch = "unknown"
-			}
-		}
+		branch := backfillBranch(repoRoot, c.Hash, branchOverride)
 
 		// Truncate subject if too long
 		subject := c.Subject
@@ -181,11 +224,7 @@ func doBackfillDryRunJSON(args []string, flags *dispatchers.ParsedFlags, deps De
 		return err
 	}
 
-	opts := git.ListCommitsOptions{
-		Since: flags.String("--since", ""),
-		Until: flags.String("--until", ""),
-		Limit: flags.Int("--limit", 0),
-	}
+	opts := backfillListOptions(flags)
 
 	commits, err := git.ListCommits(repoRoot, opts)
 	if err != nil {
@@ -216,13 +255,7 @@ func doBackfillDryRunJSON(args []string, flags *dispatchers.ParsedFlags, deps De
 	}
 
 	for _, c := range commits {
-		branch := branchOverride
-		if branch == "" {
-			branch = git.GetBranchForCommit(repoRoot, c.Hash)
-			if branch == "" {
-				branch = "unknown"
-			}
-		}
+		branch := backfillBranch(repoRoot, c.Hash, branchOverride)
 
 		result.Commits = append(result.Commits, commitEntry{
 			Hash:       c.Hash,
@@ -242,11 +275,7 @@ func doBackfillJSON(args []string, flags *dispatchers.ParsedFlags, deps Deps) er
 		return err
 	}
 
-	opts := git.ListCommitsOptions{
-		Since: flags.String("--since", ""),
-		Until: flags.String("--until", ""),
-		Limit: flags.Int("--limit", 0),
-	}
+	opts := backfillListOptions(flags)
 
 	commits, err := git.ListCommits(repoRoot, opts)
 	if err != nil {
@@ -281,31 +310,16 @@ func doBackfillJSON(args []string, flags *dispatchers.ParsedFlags, deps Deps) er
 	_ = deps.InitDB(db)
 
 	branchOverride := flags.String("--branch", "")
+	saveDefaultBranch(db, deps, repoRoot, repoID)
+
+	saveCommitMetas(db, deps, repoRoot, repoID, historyHashes(commits))
 
 	// Insert each commit as an event
 	for _, c := range commits {
-		branch := branchOverride
-		if branch == "" {
-			branch = git.GetBranchForCommit(repoRoot, c.Hash)
-			if branch == "" {
-				branch = "unknown"
-			}
-		}
-
-		timestamp, err := time.Parse(time.RFC3339, c.AuthorDate)
-		if err != nil {
-			timestamp = time.Now().UTC()
-		}
-
-		event := store.RepoEvent{
-			RepoID:    repoID,
-			RepoPath:  repoRoot,
-			Commit:    c.Hash,
-			Branch:    branch,
-			Timestamp: timestamp.UTC(),
-			Status:    store.StatusPending,
-			Source:    store.SourceBackfill,
-		}
+		event := newBackfillEvent(repoID, repoRoot, c, branchOverride)
+		saveBackfillText(db, repoID, c)
+		saveCommitMeta(db, deps, repoRoot, repoID, c.Hash)
+		saveCommitLanguages(db, deps, repoRoot, repoID, c.Hash)
 
 		if err := deps.InsertEvent(db, event); err == nil {
 			result.Imported++
@@ -0,0 +1,179 @@
+package tracking
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// backfillRepoResult is the outcome of backfilling one tracked repository.
+type backfillRepoResult struct {
+	RepoID   string `json:"repo_id,omitempty"`
+	Path     string `json:"path"`
+	Found    int    `json:"found"`
+	Imported int    `json:"imported"`
+	Skipped  int    `json:"skipped"`
+	Error    string `json:"error,omitempty"`
+}
+
+// backfillAll imports history for every repository registered in the store.
+// --since, --until, --limit and --branch apply to each repository separately.
+func backfillAll(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) > 0 {
+		return errors.New("--all cannot be combined with a repository path")
+	}
+	if !deps.GitIsAvailable() {
+		return usage.GitNotInstalled()
+	}
+
+	jsonOutput := flags.Has("--json")
+	dryRun := flags.Has("--dry-run")
+
+	s, err := deps.OpenStore(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("could not open database: %w", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	repos, err := s.ListRepos()
+	if err != nil {
+		return fmt.Errorf("could not list tracked repositories: %w", err)
+	}
+
+	// repos_include/repos_exclude may rule out repositories tracked earlier
+	rules := config.LoadRepoRules()
+	allowed := repos[:0]
+	for _, r := range repos {
+		if rules.Allows(r.Path) {
+			allowed = append(allowed, r)
+		}
+	}
+	excluded := len(repos) - len(allowed)
+	repos = allowed
+	if excluded > 0 && !jsonOutput {
+		_, _ = deps.Printf("Skipping %d %s excluded by repos_include/repos_exclude\n", excluded, pluralize(excluded, "repository", "repositories"))
+	}
+
+	if len(repos) == 0 {
+		if jsonOutput {
+			output.JSONEmpty(deps.Println)
+			return nil
+		}
+		_, _ = deps.Println("No tracked repositories")
+		_, _ = deps.Println("Run 'fp setup' in a repo to install hooks")
+		return nil
+	}
+
+	if !dryRun {
+		_ = deps.InitDB(s.DB())
+	}
+
+	opts := backfillListOptions(flags)
+	branchOverride := flags.String("--branch", "")
+
+	results := make([]backfillRepoResult, 0, len(repos))
+	for i, r := range repos {
+		result := backfillRepo(s, r.Path, opts, branchOverride, dryRun, deps)
+		results = append(results, result)
+
+		if !jsonOutput {
+			printBackfillProgress(i+1, len(repos), result, dryRun, deps)
+		}
+	}
+
+	if jsonOutput {
+		return output.JSON(deps.Println, results)
+	}
+
+	var found, imported, skipped, failed int
+	for _, r := range results {
+		found += r.Found
+		imported += r.Imported
+		skipped += r.Skipped
+		if r.Error != "" {
+			failed++
+		}
+	}
+
+	_, _ = deps.Println()
+	if dryRun {
+		_, _ = deps.Printf("Would import up to %d commits from %d repositories\n", found, len(repos)-failed)
+	} else {
+		_, _ = deps.Printf("Imported %d commits (%d skipped) from %d repositories\n", imported, skipped, len(repos)-failed)
+	}
+	if failed > 0 {
+		_, _ = deps.Printf("%d repositories could not be read\n", failed)
+	}
+
+	return nil
+}
+
+// backfillRepo lists and (unless dryRun) imports commits for one repository.
+// Errors are recorded in the result so one broken repo doesn't stop the rest.
+func backfillRepo(s *store.Store, path string, opts git.ListCommitsOptions, branchOverride string, dryRun bool, deps Deps) backfillRepoResult {
+	result := backfillRepoResult{Path: path}
+
+	repoRoot, err := deps.RepoRoot(path)
+	if err != nil {
+		result.Error = "not a git repository"
+		return result
+	}
+	result.Path = repoRoot
+
+	remoteURL, _ := deps.OriginURL(repoRoot)
+	id, err := deps.DeriveID(remoteURL, repoRoot)
+	if err != nil {
+		result.Error = "could not determine repository id"
+		return result
+	}
+	result.RepoID = string(id)
+
+	commits, err := git.ListCommits(repoRoot, opts)
+	if err != nil {
+		result.Error = fmt.Sprintf("could not list commits: %v", err)
+		return result
+	}
+	result.Found = len(commits)
+
+	if dryRun {
+		return result
+	}
+
+	saveDefaultBranch(s.DB(), deps, repoRoot, result.RepoID)
+	saveCommitMetas(s.DB(), deps, repoRoot, result.RepoID, historyHashes(commits))
+	for _, c := range commits {
+		event := newBackfillEvent(result.RepoID, repoRoot, c, branchOverride)
+		saveBackfillText(s.DB(), result.RepoID, c)
+		saveCommitMeta(s.DB(), deps, repoRoot, result.RepoID, c.Hash)
+		saveCommitLanguages(s.DB(), deps, repoRoot, result.RepoID, c.Hash)
+		if err := deps.InsertEvent(s.DB(), event); err == nil {
+			result.Imported++
+		} else {
+			result.Skipped++
+		}
+	}
+
+	return result
+}
+
+func printBackfillProgress(n, total int, r backfillRepoResult, dryRun bool, deps Deps) {
+	name := r.RepoID
+	if name == "" {
+		name = r.Path
+	}
+
+	switch {
+	case r.Error != "":
+		_, _ = deps.Printf("[%d/%d] %s: %s\n", n, total, name, r.Error)
+	case dryRun:
+		_, _ = deps.Printf("[%d/%d] %s: %d commits found\n", n, total, name, r.Found)
+	default:
+		_, _ = deps.Printf("[%d/%d] %s: imported %d (%d skipped)\n", n, total, name, r.Imported, r.Skipped)
+	}
+}
@@ -0,0 +1,143 @@
+package tracking
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	repodomain "github.com/footprint-tools/cli/internal/repo"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func setupBackfillAll(t *testing.T, commits int) (dbPath string, repoPath string) {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "Test User")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	repoPath = t.TempDir()
+	runGit := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	runGit("init", "-q", "-b", "main")
+	for i := range commits {
+		runGit("commit", "-q", "--allow-empty", "-m", fmt.Sprintf("commit %d", i))
+	}
+	repoPath, err := filepath.EvalSymlinks(repoPath)
+	require.NoError(t, err)
+
+	dbPath = filepath.Join(t.TempDir(), "store.db")
+	s, err := store.New(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, s.AddRepo(repoPath))
+	require.NoError(t, s.AddRepo(filepath.Join(t.TempDir(), "gone")))
+	require.NoError(t, s.Close())
+
+	return dbPath, repoPath
+}
+
+func backfillAllDeps(dbPath string, printed *[]string) Deps {
+	return Deps{
+		GitIsAvailable: func() bool { return true },
+		RepoRoot:       git.RepoRoot,
+		OriginURL:      func(string) (string, error) { return "", nil },
+		DeriveID:       repodomain.DeriveID,
+		DBPath:         func() string { return dbPath },
+		OpenStore:      store.New,
+		InitDB:         store.Init,
+		InsertEvent:    store.InsertEvent,
+		Printf: func(format string, a ...any) (int, error) {
+			*printed = append(*printed, fmt.Sprintf(format, a...))
+			return 0, nil
+		},
+		Println: func(a ...any) (int, error) {
+			*printed = append(*printed, fmt.Sprint(a...))
+			return 0, nil
+		},
+	}
+}
+
+func TestBackfillAll_ImportsEachRepo(t *testing.T) {
+	dbPath, repoPath := setupBackfillAll(t, 3)
+	var printed []string
+
+	flags := dispatchers.NewParsedFlags([]string{"--all", "--limit", "2"})
+	err := backfill(nil, flags, backfillAllDeps(dbPath, &printed))
+	require.NoError(t, err)
+
+	out := strings.Join(printed, "")
+	require.Contains(t, out, "not a git repository")
+	require.Contains(t, out, "imported 2 (0 skipped)")
+	require.Contains(t, out, "1 repositories could not be read")
+
+	s, err := store.New(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	events, err := store.ListEvents(s.DB(), store.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, repoPath, events[0].RepoPath)
+	require.Equal(t, store.SourceBackfill, events[0].Source)
+
+	// Running again only adds the commits that are still missing
+	flags = dispatchers.NewParsedFlags([]string{"--all"})
+	require.NoError(t, backfill(nil, flags, backfillAllDeps(dbPath, &printed)))
+
+	events, err = store.ListEvents(s.DB(), store.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+}
+
+func TestBackfillAll_DryRunDoesNotInsert(t *testing.T) {
+	dbPath, _ := setupBackfillAll(t, 2)
+	var printed []string
+
+	flags := dispatchers.NewParsedFlags([]string{"--all", "--dry-run"})
+	require.NoError(t, backfill(nil, flags, backfillAllDeps(dbPath, &printed)))
+	require.Contains(t, strings.Join(printed, ""), "2 commits found")
+
+	s, err := store.New(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	events, err := store.ListEvents(s.DB(), store.EventFilter{})
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestBackfillAll_SkipsExcludedRepos(t *testing.T) {
+	dbPath, repoPath := setupBackfillAll(t, 2)
+	t.Setenv("HOME", t.TempDir())
+	lines, _ := config.Set(nil, "repos_exclude", repoPath)
+	require.NoError(t, config.WriteLines(lines))
+	var printed []string
+
+	flags := dispatchers.NewParsedFlags([]string{"--all"})
+	require.NoError(t, backfill(nil, flags, backfillAllDeps(dbPath, &printed)))
+	require.Contains(t, strings.Join(printed, ""), "Skipping 1 repository excluded")
+
+	s, err := store.New(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+
+	events, err := store.ListEvents(s.DB(), store.EventFilter{})
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestBackfillAll_RejectsPath(t *testing.T) {
+	flags := dispatchers.NewParsedFlags([]string{"--all"})
+	err := backfill([]string{"."}, flags, Deps{})
+	require.Error(t, err)
+}
@@ -0,0 +1,386 @@
+package tracking
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/repo"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+const (
+	// githubPageSize is the most commits the GitHub API returns per page.
+	githubPageSize = 100
+	// githubTimeout bounds a single request to the GitHub API.
+	githubTimeout = 30 * time.Second
+)
+
+// githubAPIURL is the GitHub REST API. Tests point it at a local server.
+var githubAPIURL = "https://api.github.com"
+
+// githubClient is used for all requests to the GitHub API.
+var githubClient = &http.Client{Timeout: githubTimeout}
+
+// Commit fields that mark an event imported from GitHub rather than from
+// local history, shown with fp activity --enrich --json.
+const (
+	fieldRemoteSource = "remote.source"
+	fieldRemoteURL    = "remote.url"
+)
+
+// errGitHubAuthorMe is returned for --author me without a token to find
+// out who "me" is.
+var errGitHubAuthorMe = errors.New("--author me needs a GitHub token: set github_token (fp config set github_token <token>) or GITHUB_TOKEN, or pass --author <login>")
+
+// githubCommit is one entry of GET /repos/{owner}/{repo}/commits.
+type githubCommit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+			Date  string `json:"date"`
+		} `json:"author"`
+		Committer struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"committer"`
+	} `json:"commit"`
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+}
+
+// historyCommit converts c to the form local backfill reads from git log.
+func (c githubCommit) historyCommit() git.HistoryCommit {
+	subject, _, _ := strings.Cut(c.Commit.Message, "\n")
+	return git.HistoryCommit{
+		Hash:        c.SHA,
+		AuthorName:  c.Commit.Author.Name,
+		AuthorEmail: c.Commit.Author.Email,
+		AuthorDate:  c.Commit.Author.Date,
+		Subject:     strings.TrimSpace(subject),
+	}
+}
+
+// backfillFromGitHub imports the commits of a GitHub repository through the
+// API, for when local history is gone: squashed, rebased away or lost with
+// an old clone. Events are recorded as backfill, like local imports, and
+// their commits carry remote.source=github and remote.url fields.
+func backfillFromGitHub(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	if flags.Has("--all") || len(args) > 0 {
+		return errors.New("--from-github takes owner/repo instead of a path or --all")
+	}
+	slug, err := githubSlug(flags.String("--from-github", ""))
+	if err != nil {
+		return err
+	}
+	query, err := githubCommitQuery(flags)
+	if err != nil {
+		return err
+	}
+
+	token := githubToken(deps)
+	author := flags.String("--author", "me")
+	if author == "me" {
+		if token == "" {
+			return errGitHubAuthorMe
+		}
+		var user struct {
+			Login string `json:"login"`
+		}
+		if err := githubGet("/user", token, &user); err != nil {
+			return fmt.Errorf("could not look up the token's user: %w", err)
+		}
+		author = user.Login
+	}
+	query.Set("author", author)
+
+	branch := flags.String("--branch", "")
+	if branch == "" {
+		var info struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if err := githubGet("/repos/"+slug, token, &info); err != nil {
+			return fmt.Errorf("could not read %s: %w", slug, err)
+		}
+		branch = info.DefaultBranch
+	}
+	query.Set("sha", branch)
+
+	commits, err := listGitHubCommits(slug, token, query, flags.Int("--limit", 0))
+	if err != nil {
+		return fmt.Errorf("could not list commits of %s: %w", slug, err)
+	}
+
+	id, err := repo.DeriveID("https://github.com/"+slug, "")
+	if err != nil {
+		return fmt.Errorf("invalid repository %s: %w", slug, err)
+	}
+	repoID := string(id)
+	repoRoot := localClone(repoID, deps)
+
+	if flags.Has("--dry-run") {
+		return githubDryRun(repoID, branch, author, commits, flags.Has("--json"), deps)
+	}
+
+	imported, skipped := 0, 0
+	if len(commits) > 0 {
+		db, err := deps.OpenDB(deps.DBPath())
+		if err != nil {
+			return fmt.Errorf("could not open database: %w", err)
+		}
+		defer func() { _ = db.Close() }()
+		_ = deps.InitDB(db)
+
+		if repoRoot != "" {
+			shas := make([]string, len(commits))
+			for i, c := range commits {
+				shas[i] = c.SHA
+			}
+			saveCommitMetas(db, deps, repoRoot, repoID, shas)
+		}
+		for _, c := range commits {
+			event := newBackfillEvent(repoID, repoRoot, c.historyCommit(), branch)
+			if err := deps.InsertEvent(db, event); err != nil {
+				skipped++
+				continue
+			}
+			imported++
+			saveGitHubCommit(db, deps, repoID, repoRoot, c)
+		}
+	}
+
+	if flags.Has("--json") {
+		return output.JSON(deps.Println, struct {
+			RepoID   string `json:"repo_id"`
+			Path     string `json:"path"`
+			Source   string `json:"source"`
+			Author   string `json:"author"`
+			Branch   string `json:"branch"`
+			Found    int    `json:"found"`
+			Imported int    `json:"imported"`
+			Skipped  int    `json:"skipped"`
+		}{repoID, repoRoot, "github", author, branch, len(commits), imported, skipped})
+	}
+	if len(commits) == 0 {
+		_, _ = deps.Printf("No commits by %s found on %s of %s\n", author, branch, repoID)
+		return nil
+	}
+	_, _ = deps.Printf("Imported %d commits by %s from %s (%d skipped)\n", imported, author, repoID, skipped)
+	return nil
+}
+
+// githubDryRun lists what backfillFromGitHub would import.
+func githubDryRun(repoID, branch, author string, commits []githubCommit, jsonOutput bool, deps Deps) error {
+	if jsonOutput {
+		type commitEntry struct {
+			Hash       string `json:"hash"`
+			Branch     string `json:"branch"`
+			AuthorDate string `json:"author_date"`
+			Subject    string `json:"subject"`
+			URL        string `json:"url"`
+		}
+		entries := make([]commitEntry, 0, len(commits))
+		for _, c := range commits {
+			h := c.historyCommit()
+			entries = append(entries, commitEntry{h.Hash, branch, h.AuthorDate, h.Subject, c.HTMLURL})
+		}
+		return output.JSON(deps.Println, struct {
+			RepoID  string        `json:"repo_id"`
+			Source  string        `json:"source"`
+			Author  string        `json:"author"`
+			Count   int           `json:"count"`
+			Commits []commitEntry `json:"commits"`
+		}{repoID, "github", author, len(commits), entries})
+	}
+
+	_, _ = deps.Printf("Repository: %s (from GitHub)\n", repoID)
+	_, _ = deps.Printf("Found %d commits by %s to import:\n\n", len(commits), author)
+	for _, c := range commits {
+		h := c.historyCommit()
+		subject := h.Subject
+		if len(subject) > 50 {
+			subject = subject[:47] + "..."
+		}
+		date := h.AuthorDate
+		if len(date) > 10 {
+			date = date[:10]
+		}
+		_, _ = deps.Printf("  %.7s %s %s \"%s\"\n", h.Hash, date, branch, subject)
+	}
+	return nil
+}
+
+// saveGitHubCommit stores the message and metadata of an imported commit,
+// and marks it as coming from GitHub. Metadata is read from the local
+// clone when it has the commit, which adds diff stats the commit list
+// doesn't include. All of it is extra, so failures are logged.
+func saveGitHubCommit(db *sql.DB, deps Deps, repoID, repoRoot string, c githubCommit) {
+	subject, body, _ := strings.Cut(c.Commit.Message, "\n")
+	subject, body = strings.TrimSpace(subject), strings.TrimSpace(body)
+	if ok, err := store.HasCommitText(db, repoID, c.SHA); err == nil && !ok {
+		if err := store.SaveCommitText(db, repoID, c.SHA, subject, body); err != nil {
+			log.Debug("backfill: could not store message of %.7s: %v", c.SHA, err)
+		}
+	}
+	if repoRoot != "" {
+		saveCommitMeta(db, deps, repoRoot, repoID, c.SHA)
+		saveCommitLanguages(db, deps, repoRoot, repoID, c.SHA)
+	}
+	if ok, err := store.HasCommitMeta(db, repoID, c.SHA); err == nil && !ok {
+		parents := make([]string, len(c.Parents))
+		for i, p := range c.Parents {
+			parents[i] = p.SHA
+		}
+		meta := git.CommitMetadata{
+			AuthoredAt:     c.Commit.Author.Date,
+			ParentCommits:  strings.Join(parents, " "),
+			AuthorName:     c.Commit.Author.Name,
+			AuthorEmail:    c.Commit.Author.Email,
+			CommitterName:  c.Commit.Committer.Name,
+			CommitterEmail: c.Commit.Committer.Email,
+			Subject:        subject,
+			Body:           body,
+		}
+		if err := store.SaveCommitMeta(db, repoID, c.SHA, meta); err != nil {
+			log.Debug("backfill: could not store metadata of %.7s: %v", c.SHA, err)
+		}
+	}
+	fields := map[string]string{fieldRemoteSource: "github"}
+	if c.HTMLURL != "" {
+		fields[fieldRemoteURL] = c.HTMLURL
+	}
+	if err := store.SaveCommitFields(db, repoID, c.SHA, fields); err != nil {
+		log.Debug("backfill: could not mark %.7s as imported from GitHub: %v", c.SHA, err)
+	}
+}
+
+// githubSlug validates an owner/repo argument. A github.com URL is
+// accepted too.
+func githubSlug(value string) (string, error) {
+	slug := strings.TrimSpace(value)
+	slug = strings.TrimPrefix(slug, "https://")
+	slug = strings.TrimPrefix(slug, "github.com/")
+	slug = strings.TrimSuffix(strings.TrimSuffix(slug, "/"), ".git")
+	owner, name, ok := strings.Cut(slug, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", fmt.Errorf("invalid repository '%s' for --from-github: expected owner/repo", value)
+	}
+	return slug, nil
+}
+
+// githubCommitQuery builds the since and until parameters of the commits
+// request from the backfill date flags.
+func githubCommitQuery(flags *dispatchers.ParsedFlags) (url.Values, error) {
+	query := url.Values{}
+	for _, name := range []string{"--since", "--until"} {
+		value := flags.String(name, "")
+		if value == "" {
+			continue
+		}
+		d := flags.Date(name)
+		if d == nil {
+			return nil, fmt.Errorf("invalid date '%s' for %s: expected %s", value, name, dispatchers.DateFormats)
+		}
+		query.Set(strings.TrimPrefix(name, "--"), d.UTC().Format(time.RFC3339))
+	}
+	return query, nil
+}
+
+// githubToken returns the token for the GitHub API: the github_token
+// config key, or GITHUB_TOKEN. Public repositories work without one, at a
+// lower rate limit.
+func githubToken(deps Deps) string {
+	if token, _ := config.Get("github_token"); strings.TrimSpace(token) != "" {
+		return strings.TrimSpace(token)
+	}
+	return strings.TrimSpace(deps.Getenv("GITHUB_TOKEN"))
+}
+
+// listGitHubCommits pages through the commits matching query, newest
+// first, stopping after limit when it is set.
+func listGitHubCommits(slug, token string, query url.Values, limit int) ([]githubCommit, error) {
+	query.Set("per_page", strconv.Itoa(githubPageSize))
+	var commits []githubCommit
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var batch []githubCommit
+		if err := githubGet("/repos/"+slug+"/commits?"+query.Encode(), token, &batch); err != nil {
+			return nil, err
+		}
+		commits = append(commits, batch...)
+		if limit > 0 && len(commits) >= limit {
+			return commits[:limit], nil
+		}
+		if len(batch) < githubPageSize {
+			return commits, nil
+		}
+	}
+}
+
+// githubGet decodes the JSON at path on the GitHub API into v.
+func githubGet(path, token string, v any) error {
+	req, err := http.NewRequest(http.MethodGet, githubAPIURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := githubClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized:
+			return errors.New("GitHub rejected the token (check github_token or GITHUB_TOKEN)")
+		case resp.StatusCode == http.StatusNotFound && token == "":
+			return errors.New("not found on GitHub (private repositories need github_token or GITHUB_TOKEN)")
+		case body.Message != "":
+			return fmt.Errorf("GitHub API returned %d: %s", resp.StatusCode, body.Message)
+		default:
+			return fmt.Errorf("GitHub API returned %d", resp.StatusCode)
+		}
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// localClone returns the root of the repository in the current directory
+// when it is the one being imported, so the events point at a clone that
+// has the commits. Otherwise it returns "".
+func localClone(repoID string, deps Deps) string {
+	root, err := deps.RepoRoot(".")
+	if err != nil {
+		return ""
+	}
+	remoteURL, _ := deps.OriginURL(root)
+	id, err := deps.DeriveID(remoteURL, root)
+	if err != nil || string(id) != repoID {
+		return ""
+	}
+	return root
+}
@@ -0,0 +1,135 @@
+package tracking
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// fakeGitHub serves the API calls backfill --from-github makes for me/app.
+func fakeGitHub(t *testing.T) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/user":
+			_, _ = w.Write([]byte(`{"login": "me"}`))
+		case "/repos/me/app":
+			_, _ = w.Write([]byte(`{"default_branch": "trunk"}`))
+		case "/repos/me/app/commits":
+			require.Equal(t, "me", r.URL.Query().Get("author"))
+			require.Equal(t, "trunk", r.URL.Query().Get("sha"))
+			require.Equal(t, "2026-03-01T00:00:00Z", r.URL.Query().Get("since"))
+			_ = json.NewEncoder(w).Encode([]map[string]any{
+				{"sha": "aaaaaaa1", "html_url": "https://github.com/me/app/commit/aaaaaaa1", "commit": map[string]any{
+					"message": "Fix login\n\nThe redirect looped.",
+					"author":  map[string]any{"name": "Me", "email": "me@example.com", "date": "2026-03-02T10:00:00Z"},
+				}},
+				{"sha": "bbbbbbb2", "html_url": "https://github.com/me/app/commit/bbbbbbb2", "commit": map[string]any{
+					"message": "Add importer",
+					"author":  map[string]any{"name": "Me", "email": "me@example.com", "date": "2026-03-01T09:00:00Z"},
+				}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	old := githubAPIURL
+	githubAPIURL = srv.URL
+	t.Cleanup(func() { githubAPIURL = old })
+}
+
+func githubTestDeps(t *testing.T, token string, out *strings.Builder) Deps {
+	t.Setenv("HOME", t.TempDir())
+	deps := statusTestDeps(filepath.Join(t.TempDir(), "store.db"), out)
+	deps.OpenDB = openDBFresh
+	deps.InitDB = store.Init
+	deps.InsertEvent = store.InsertEvent
+	deps.Getenv = func(key string) string {
+		if key == "GITHUB_TOKEN" {
+			return token
+		}
+		return ""
+	}
+	return deps
+}
+
+func TestBackfillFromGitHub(t *testing.T) {
+	fakeGitHub(t)
+	var out strings.Builder
+	deps := githubTestDeps(t, "secret", &out)
+
+	flags := dispatchers.NewParsedFlags([]string{"--from-github=me/app", "--since=2026-03-01"})
+	require.NoError(t, backfill(nil, flags, deps))
+	require.Contains(t, out.String(), "Imported 2 commits by me from github.com/me/app (0 skipped)")
+
+	db, err := deps.OpenDB(deps.DBPath())
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	events, err := store.ListEvents(db, store.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	for _, e := range events {
+		require.Equal(t, "github.com/me/app", e.RepoID)
+		require.Equal(t, "trunk", e.Branch)
+		require.Equal(t, store.SourceBackfill, e.Source)
+		require.Empty(t, e.RepoPath, "no local clone of the repository")
+	}
+
+	fields, err := store.GetCommitFields(db, "github.com/me/app", "aaaaaaa1")
+	require.NoError(t, err)
+	require.Equal(t, "github", fields[fieldRemoteSource])
+	require.Equal(t, "https://github.com/me/app/commit/aaaaaaa1", fields[fieldRemoteURL])
+
+	// Importing again skips what is already stored
+	out.Reset()
+	require.NoError(t, backfill(nil, flags, deps))
+	events, err = store.ListEvents(db, store.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+}
+
+func TestBackfillFromGitHub_DryRun(t *testing.T) {
+	fakeGitHub(t)
+	var out strings.Builder
+	deps := githubTestDeps(t, "secret", &out)
+
+	flags := dispatchers.NewParsedFlags([]string{"--from-github=github.com/me/app", "--since=2026-03-01", "--dry-run", "--limit=1"})
+	require.NoError(t, backfill(nil, flags, deps))
+	require.Contains(t, out.String(), "Found 1 commits by me to import")
+	require.Contains(t, out.String(), `aaaaaaa 2026-03-02 trunk "Fix login"`)
+}
+
+func TestBackfillFromGitHub_AuthorMeNeedsToken(t *testing.T) {
+	var out strings.Builder
+	deps := githubTestDeps(t, "", &out)
+
+	err := backfill(nil, dispatchers.NewParsedFlags([]string{"--from-github=me/app"}), deps)
+	require.ErrorIs(t, err, errGitHubAuthorMe)
+}
+
+func TestGitHubSlug(t *testing.T) {
+	for _, in := range []string{"me/app", "github.com/me/app", "https://github.com/me/app.git"} {
+		slug, err := githubSlug(in)
+		require.NoError(t, err, in)
+		require.Equal(t, "me/app", slug)
+	}
+	for _, in := range []string{"app", "me/", "me/app/tree/main"} {
+		_, err := githubSlug(in)
+		require.Error(t, err, in)
+	}
+}
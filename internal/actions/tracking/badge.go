@@ -0,0 +1,152 @@
+package tracking
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+const (
+	badgeMetricCommits      = "commits"
+	badgeMetricLastActivity = "last-activity"
+
+	// badgeCharWidth approximates the width of one 11px Verdana glyph, the
+	// font shields.io badges use. Badges are sized from it so no font
+	// metrics are needed.
+	badgeCharWidth = 7
+	badgePadding   = 10
+
+	badgeColorActive = "#4c1"
+	badgeColorIdle   = "#9f9f9f"
+)
+
+// Badge handles the `fp badge` command.
+func Badge(args []string, flags *dispatchers.ParsedFlags) error {
+	return badge(args, flags, DefaultDeps())
+}
+
+// badge writes a shields-style SVG for one repository, computed from the
+// local store, so it can be committed next to a README.
+func badge(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	metric := flags.String("--metric", badgeMetricCommits)
+	if metric != badgeMetricCommits && metric != badgeMetricLastActivity {
+		return fmt.Errorf("unknown badge metric '%s'\nHint: use --metric commits or --metric last-activity", metric)
+	}
+
+	repoID, err := badgeRepoID(args, deps)
+	if err != nil {
+		return err
+	}
+
+	db, err := deps.OpenDB(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer store.CloseDB(db)
+
+	now := deps.Now()
+	var label, value, color string
+	switch metric {
+	case badgeMetricCommits:
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		events, err := deps.ListEvents(db, store.EventFilter{RepoID: &repoID, Since: &monthStart})
+		if err != nil {
+			return fmt.Errorf("failed to list events: %w", err)
+		}
+		n := countCommits(events)
+		label, value, color = "commits this month", fmt.Sprint(n), badgeColorActive
+		if n == 0 {
+			color = badgeColorIdle
+		}
+	case badgeMetricLastActivity:
+		events, err := deps.ListEvents(db, store.EventFilter{RepoID: &repoID, Limit: 1})
+		if err != nil {
+			return fmt.Errorf("failed to list events: %w", err)
+		}
+		label, value, color = "last activity", "never", badgeColorIdle
+		if len(events) > 0 {
+			value = events[0].Timestamp.Local().Format(dayKeyLayout)
+			color = badgeColorActive
+		}
+	}
+
+	svg := renderBadge(label, value, color)
+
+	out := flags.String("--out", "")
+	if out == "" {
+		_, _ = deps.Printf("%s", svg)
+		return nil
+	}
+	if err := os.WriteFile(out, []byte(svg), 0644); err != nil {
+		return fmt.Errorf("could not write badge: %w", err)
+	}
+	_, _ = deps.Printf("Wrote %s badge for %s to %s (%s)\n", label, repoID, out, value)
+	return nil
+}
+
+// badgeRepoID accepts a repository path (default: the current directory) or
+// a repository id as stored in the database.
+func badgeRepoID(args []string, deps Deps) (string, error) {
+	if len(args) > 0 {
+		if info, err := os.Stat(args[0]); err != nil || !info.IsDir() {
+			return args[0], nil
+		}
+	}
+
+	path, err := resolvePath(args)
+	if err != nil {
+		return "", fmt.Errorf("invalid path: %w", err)
+	}
+	repoRoot, err := deps.RepoRoot(path)
+	if err != nil {
+		return "", fmt.Errorf("%s is not a git repository\nHint: pass a repository id instead, see 'fp repos list'", path)
+	}
+	remoteURL, _ := deps.OriginURL(repoRoot)
+	id, err := deps.DeriveID(remoteURL, repoRoot)
+	if err != nil {
+		return "", fmt.Errorf("could not derive repo id: %w", err)
+	}
+	return string(id), nil
+}
+
+// countCommits counts distinct commits among events that record one.
+func countCommits(events []store.RepoEvent) int {
+	seen := make(map[string]bool)
+	for _, e := range events {
+		switch e.Source {
+		case store.SourcePostCommit, store.SourceBackfill, store.SourceManual:
+			seen[e.Commit] = true
+		}
+	}
+	return len(seen)
+}
+
+// renderBadge draws a flat two-part badge in the shields.io layout.
+func renderBadge(label, value, color string) string {
+	labelWidth := len(label)*badgeCharWidth + badgePadding
+	valueWidth := len(value)*badgeCharWidth + badgePadding
+	width := labelWidth + valueWidth
+	label, value = html.EscapeString(label), html.EscapeString(value)
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">
+<title>%[4]s: %[5]s</title>
+<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)">
+<rect width="%[2]d" height="20" fill="#555"/>
+<rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/>
+<rect width="%[1]d" height="20" fill="url(#s)"/>
+</g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="%[7]d" y="15" fill="#010101" fill-opacity=".3">%[4]s</text>
+<text x="%[7]d" y="14">%[4]s</text>
+<text x="%[8]d" y="15" fill="#010101" fill-opacity=".3">%[5]s</text>
+<text x="%[8]d" y="14">%[5]s</text>
+</g>
+</svg>
+`, width, labelWidth, valueWidth, label, value, color, labelWidth/2, labelWidth+valueWidth/2)
+}
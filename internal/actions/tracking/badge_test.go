@@ -0,0 +1,90 @@
+package tracking
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func badgeTestDeps(events []store.RepoEvent, filter *store.EventFilter, printed *string) Deps {
+	return Deps{
+		DBPath: func() string { return ":memory:" },
+		OpenDB: func(path string) (*sql.DB, error) { return sql.Open("sqlite3", path) },
+		ListEvents: func(_ *sql.DB, f store.EventFilter) ([]store.RepoEvent, error) {
+			*filter = f
+			return events, nil
+		},
+		Now: func() time.Time { return time.Date(2025, 3, 20, 12, 0, 0, 0, time.Local) },
+		Printf: func(format string, a ...any) (int, error) {
+			*printed = format
+			if len(a) > 0 {
+				if s, ok := a[0].(string); ok && format == "%s" {
+					*printed = s
+				}
+			}
+			return 0, nil
+		},
+	}
+}
+
+func TestBadge_CommitsThisMonth(t *testing.T) {
+	var filter store.EventFilter
+	var printed string
+	deps := badgeTestDeps(reportTestEvents(), &filter, &printed)
+	out := filepath.Join(t.TempDir(), "badge.svg")
+
+	err := badge([]string{"github.com/a/one"}, dispatchers.NewParsedFlags([]string{"--out=" + out}), deps)
+	require.NoError(t, err)
+
+	require.Equal(t, "github.com/a/one", *filter.RepoID)
+	require.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local), *filter.Since)
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	svg := string(data)
+	require.Contains(t, svg, "<svg")
+	require.Contains(t, svg, "commits this month: 3")
+	require.Contains(t, svg, badgeColorActive)
+}
+
+func TestBadge_LastActivityToStdout(t *testing.T) {
+	var filter store.EventFilter
+	var printed string
+	deps := badgeTestDeps(reportTestEvents()[:1], &filter, &printed)
+
+	err := badge([]string{"github.com/a/one"}, dispatchers.NewParsedFlags([]string{"--metric=last-activity"}), deps)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, filter.Limit)
+	require.Contains(t, printed, "last activity: 2025-03-12")
+}
+
+func TestBadge_NoActivityIsGray(t *testing.T) {
+	var filter store.EventFilter
+	var printed string
+	deps := badgeTestDeps(nil, &filter, &printed)
+
+	err := badge([]string{"github.com/a/none"}, dispatchers.NewParsedFlags(nil), deps)
+	require.NoError(t, err)
+	require.Contains(t, printed, "commits this month: 0")
+	require.Contains(t, printed, badgeColorIdle)
+}
+
+func TestBadge_UnknownMetric(t *testing.T) {
+	err := badge(nil, dispatchers.NewParsedFlags([]string{"--metric=stars"}), Deps{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--metric commits")
+}
+
+func TestRenderBadge_EscapesText(t *testing.T) {
+	svg := renderBadge("a<b", "1&2", badgeColorActive)
+	require.Contains(t, svg, "a&lt;b")
+	require.Contains(t, svg, "1&amp;2")
+	require.NotContains(t, svg, "a<b")
+}
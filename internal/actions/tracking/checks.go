@@ -0,0 +1,275 @@
+package tracking
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/hooks"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// maxFixesPerCheck caps how many per-repository commands a check suggests.
+const maxFixesPerCheck = 3
+
+// setupCheck is the outcome of one setup step, with the commands that
+// would fix it. fp status shows these as an onboarding checklist.
+type setupCheck struct {
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	OK       bool     `json:"ok"`
+	Optional bool     `json:"optional,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+	Fix      []string `json:"fix,omitempty"`
+
+	// stale are the hooks directories checkHooksUpToDate found out of
+	// date, for fp doctor --fix to refresh
+	stale []string
+}
+
+// setupEnv is what the checks look at, gathered once per run.
+type setupEnv struct {
+	gitAvailable bool
+	store        *store.Store
+	storeErr     error
+	repos        []store.RegisteredRepo
+	global       hooks.GlobalHooksStatus
+	currentRepo  string // repository containing the working directory, if any
+}
+
+// newSetupEnv gathers what the checks look at. The caller closes it.
+func newSetupEnv(deps Deps) setupEnv {
+	env := setupEnv{gitAvailable: deps.GitIsAvailable()}
+
+	env.store, env.storeErr = deps.OpenStore(deps.DBPath())
+	if env.storeErr == nil {
+		env.repos, _ = env.store.ListRepos()
+	}
+	if env.gitAvailable {
+		env.global = hooks.CheckGlobalHooksStatus()
+		if root, err := deps.RepoRoot("."); err == nil {
+			env.currentRepo = root
+		}
+	}
+	return env
+}
+
+func (env setupEnv) close() {
+	if env.store != nil {
+		_ = env.store.Close()
+	}
+}
+
+// runSetupChecks runs every setup check in checklist order.
+func runSetupChecks(deps Deps) []setupCheck {
+	env := newSetupEnv(deps)
+	defer env.close()
+
+	checks := []func(setupEnv) setupCheck{
+		checkGit,
+		checkDatabase,
+		checkTrackedRepos,
+		checkCurrentRepo,
+		checkHooksUpToDate,
+		checkEventsRecorded,
+		checkExportRemote,
+	}
+
+	results := make([]setupCheck, 0, len(checks))
+	for _, check := range checks {
+		if result := check(env); result.ID != "" {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+func checkGit(env setupEnv) setupCheck {
+	if env.gitAvailable {
+		return setupCheck{ID: "git", Title: "git is installed", OK: true}
+	}
+	return setupCheck{
+		ID:     "git",
+		Title:  "git is not installed",
+		Detail: "fp records git activity and needs git in your PATH",
+		Fix:    []string{"Install git: https://git-scm.com/downloads"},
+	}
+}
+
+func checkDatabase(env setupEnv) setupCheck {
+	if env.storeErr == nil {
+		return setupCheck{ID: "database", Title: "Database is readable", OK: true}
+	}
+	if errors.Is(env.storeErr, store.ErrLocked) {
+		return setupCheck{
+			ID:     "database",
+			Title:  "Database is encrypted and locked",
+			Detail: "hooks can't record until it is unlocked",
+			Fix:    []string{"fp unlock"},
+		}
+	}
+	return setupCheck{
+		ID:     "database",
+		Title:  "Database can't be opened",
+		Detail: env.storeErr.Error(),
+		Fix:    []string{"fp logs"},
+	}
+}
+
+func checkTrackedRepos(env setupEnv) setupCheck {
+	if env.global.IsFpManaged {
+		return setupCheck{ID: "repos", Title: "Global hooks track every repository", OK: true}
+	}
+	if n := len(env.repos); n > 0 {
+		return setupCheck{ID: "repos", Title: fmt.Sprintf("%d %s tracked", n, pluralize(n, "repository", "repositories")), OK: true}
+	}
+	return setupCheck{
+		ID:     "repos",
+		Title:  "No repositories tracked yet",
+		Detail: "Install hooks in a repository to start recording",
+		Fix:    []string{"cd <repo> && fp setup", "fp repos scan --root ~/code"},
+	}
+}
+
+// checkCurrentRepo only applies inside a git repository that global hooks don't cover.
+func checkCurrentRepo(env setupEnv) setupCheck {
+	if env.currentRepo == "" || env.global.IsFpManaged {
+		return setupCheck{}
+	}
+	hooksPath, err := git.RepoHooksPath(env.currentRepo)
+	if err != nil {
+		return setupCheck{}
+	}
+
+	missing := 0
+	for _, installed := range hooks.Status(hooksPath) {
+		if !installed {
+			missing++
+		}
+	}
+	name := filepath.Base(env.currentRepo)
+	if missing == 0 {
+		return setupCheck{ID: "current_repo", Title: fmt.Sprintf("This repository (%s) is tracked", name), OK: true}
+	}
+	return setupCheck{
+		ID:     "current_repo",
+		Title:  fmt.Sprintf("This repository (%s) is not tracked", name),
+		Detail: fmt.Sprintf("%d of %d hooks are missing", missing, len(hooks.ManagedHooks)),
+		Fix:    []string{"fp setup"},
+	}
+}
+
+// checkHooksUpToDate looks at the global hooks, the current repository
+// and every tracked one.
+func checkHooksUpToDate(env setupEnv) setupCheck {
+	var stale, staleDirs, fixes []string
+
+	if env.global.IsFpManaged {
+		if len(hooks.Outdated(env.global.Path)) > 0 {
+			stale = append(stale, "global hooks")
+			staleDirs = append(staleDirs, env.global.Path)
+			fixes = append(fixes, "fp setup --global --force")
+		}
+	}
+	repoPaths := make([]string, 0, len(env.repos)+1)
+	if env.currentRepo != "" {
+		repoPaths = append(repoPaths, env.currentRepo)
+	}
+	for _, r := range env.repos {
+		if r.Path != env.currentRepo {
+			repoPaths = append(repoPaths, r.Path)
+		}
+	}
+	for _, repoPath := range repoPaths {
+		hooksPath, err := git.RepoHooksPath(repoPath)
+		if err != nil || slices.Contains(staleDirs, hooksPath) || (env.global.IsFpManaged && hooksPath == env.global.Path) {
+			continue
+		}
+		if len(hooks.Outdated(hooksPath)) == 0 {
+			continue
+		}
+		stale = append(stale, repoPath)
+		staleDirs = append(staleDirs, hooksPath)
+		if len(fixes) < maxFixesPerCheck {
+			fixes = append(fixes, "fp setup --force "+shellArg(repoPath))
+		}
+	}
+
+	if len(stale) == 0 {
+		if len(env.repos) == 0 && !env.global.IsFpManaged {
+			return setupCheck{}
+		}
+		return setupCheck{ID: "hooks", Title: "Hooks are up to date", OK: true}
+	}
+	if len(stale) > len(fixes) {
+		fixes = append(fixes, "fp repos -i")
+	}
+	return setupCheck{
+		ID:     "hooks",
+		Title:  fmt.Sprintf("Hooks are out of date in %d %s", len(stale), pluralize(len(stale), "place", "places")),
+		Detail: "They were installed by an older fp or point to a moved binary",
+		Fix:    fixes,
+		stale:  staleDirs,
+	}
+}
+
+func checkEventsRecorded(env setupEnv) setupCheck {
+	if env.storeErr != nil {
+		return setupCheck{}
+	}
+	counts, err := env.store.CountByStatus()
+	if err != nil {
+		return setupCheck{}
+	}
+	var total int64
+	for _, n := range counts {
+		total += n
+	}
+	if total > 0 {
+		return setupCheck{
+			ID:    "events",
+			Title: fmt.Sprintf("%d events recorded (%d pending export)", total, counts[store.StatusPending]),
+			OK:    true,
+		}
+	}
+	return setupCheck{
+		ID:     "events",
+		Title:  "No activity recorded yet",
+		Detail: "Make a commit in a tracked repository, or import past commits",
+		Fix:    []string{"fp backfill --all"},
+	}
+}
+
+func checkExportRemote(env setupEnv) setupCheck {
+	remote, _ := config.Get("export_remote")
+	sinks, _ := config.Get("export_sinks")
+	if strings.TrimSpace(remote) != "" || httpExportURL() != "" || strings.TrimSpace(sinks) != "" {
+		return setupCheck{ID: "export_remote", Title: "Exports are backed up off this machine", OK: true, Optional: true}
+	}
+	return setupCheck{
+		ID:       "export_remote",
+		Title:    "Exports stay on this machine",
+		Optional: true,
+		Detail:   "Push exports to a private git repository to keep a backup",
+		Fix:      []string{"fp config set export_remote git@github.com:you/my-activity.git"},
+	}
+}
+
+// shellArg quotes a path for a suggested command if it contains spaces.
+func shellArg(s string) string {
+	if strings.ContainsAny(s, " \t'\"") {
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	}
+	return s
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
@@ -0,0 +1,215 @@
+package tracking
+
+import (
+	"database/sql"
+
+	"github.com/footprint-tools/cli/internal/enrich"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// commitMetaReader returns commit metadata for display and export. The
+// metadata fp record and backfill stored is used first, and git is only
+// run for commits without it, each at most once. A nil reader runs git for
+// every call, as fp did before metadata was stored.
+//
+// A reader is not safe for concurrent use.
+type commitMetaReader struct {
+	db   *sql.DB
+	read func(repoPath, commit string) git.CommitMetadata
+	// readBatch reads many commits of one repository at once for
+	// Prefetch; nil leaves every commit to read
+	readBatch func(repoPath string, commits []string) (map[string]git.CommitMetadata, error)
+	// fill stores what was read from git, so the next run finds it. Only
+	// set on writable connections.
+	fill bool
+	seen map[string]git.CommitMetadata // by repo_id and commit
+}
+
+func newCommitMetaReader(db *sql.DB) *commitMetaReader {
+	return &commitMetaReader{
+		db:        db,
+		read:      git.GetCommitMetadata,
+		readBatch: git.GetCommitMetadataBatch,
+		seen:      make(map[string]git.CommitMetadata),
+	}
+}
+
+// Of returns the metadata of the commit of e.
+func (r *commitMetaReader) Of(e store.RepoEvent) git.CommitMetadata {
+	return r.At(e.RepoID, e.RepoPath, e.Commit)
+}
+
+// At returns the metadata of commit in the repository repoID, reading it
+// from the clone at repoPath when none is stored.
+func (r *commitMetaReader) At(repoID, repoPath, commit string) git.CommitMetadata {
+	if r == nil {
+		return git.GetCommitMetadata(repoPath, commit)
+	}
+
+	key := repoID + "\x00" + commit
+	if meta, ok := r.seen[key]; ok {
+		return meta
+	}
+
+	var (
+		meta git.CommitMetadata
+		ok   bool
+		err  error
+	)
+	if r.db != nil {
+		meta, ok, err = store.GetCommitMeta(r.db, repoID, commit)
+		if err != nil {
+			log.Debug("metadata: could not read stored metadata of %.7s: %v", commit, err)
+		}
+	}
+	if !ok {
+		meta = r.read(repoPath, commit)
+		if r.fill && meta.AuthoredAt != "" {
+			if err := store.SaveCommitMeta(r.db, repoID, commit, meta); err != nil {
+				log.Debug("metadata: could not store metadata of %.7s: %v", commit, err)
+			}
+		}
+	}
+	r.seen[key] = meta
+	return meta
+}
+
+// Prefetch reads the metadata of the commits of events that isn't stored
+// with one git log per repository (see git.GetCommitMetadataBatch), so
+// the Of and At calls that follow don't run git for each commit. Commits
+// git doesn't have are remembered as having no metadata.
+func (r *commitMetaReader) Prefetch(events []store.RepoEvent) {
+	if r == nil || r.readBatch == nil {
+		return
+	}
+
+	type repo struct{ id, path string }
+	var repos []repo
+	missing := make(map[repo][]string)
+	for _, e := range events {
+		if e.RepoPath == "" || e.Commit == "" {
+			continue
+		}
+		key := e.RepoID + "\x00" + e.Commit
+		if _, ok := r.seen[key]; ok {
+			continue
+		}
+		if r.db != nil {
+			if meta, ok, err := store.GetCommitMeta(r.db, e.RepoID, e.Commit); err == nil && ok {
+				r.seen[key] = meta
+				continue
+			}
+		}
+		rp := repo{id: e.RepoID, path: e.RepoPath}
+		if _, ok := missing[rp]; !ok {
+			repos = append(repos, rp)
+		}
+		missing[rp] = append(missing[rp], e.Commit)
+	}
+
+	for _, rp := range repos {
+		metas, err := r.readBatch(rp.path, missing[rp])
+		if err != nil {
+			log.Debug("metadata: could not read commits of %s: %v", rp.path, err)
+			continue
+		}
+		for _, commit := range missing[rp] {
+			key := rp.id + "\x00" + commit
+			if _, ok := r.seen[key]; ok {
+				continue
+			}
+			meta := metas[commit]
+			if r.fill && meta.AuthoredAt != "" {
+				if err := store.SaveCommitMeta(r.db, rp.id, commit, meta); err != nil {
+					log.Debug("metadata: could not store metadata of %.7s: %v", commit, err)
+				}
+			}
+			r.seen[key] = meta
+		}
+	}
+}
+
+// saveCommitMetas stores the metadata of commits of one repository that
+// isn't stored yet, read with git.GetCommitMetadataBatch rather than
+// commit by commit. saveCommitMeta picks up any it missed.
+func saveCommitMetas(db *sql.DB, deps Deps, repoRoot, repoID string, commits []string) {
+	if deps.CommitMetadataBatch == nil {
+		return
+	}
+	var missing []string
+	for _, c := range commits {
+		if ok, err := store.HasCommitMeta(db, repoID, c); err == nil && !ok {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	metas, err := deps.CommitMetadataBatch(repoRoot, missing)
+	if err != nil {
+		log.Debug("metadata: could not read commits of %s: %v", repoRoot, err)
+		return
+	}
+	for _, c := range missing {
+		meta, ok := metas[c]
+		if !ok || meta.AuthoredAt == "" {
+			continue
+		}
+		if err := store.SaveCommitMeta(db, repoID, c, meta); err != nil {
+			log.Warn("metadata: could not store metadata of %.7s: %v", c, err)
+		}
+	}
+}
+
+// historyHashes returns the hashes of commits.
+func historyHashes(commits []git.HistoryCommit) []string {
+	hashes := make([]string, len(commits))
+	for i, c := range commits {
+		hashes[i] = c.Hash
+	}
+	return hashes
+}
+
+// saveCommitMeta stores the metadata of commit, read from the clone at
+// repoRoot, unless it is already stored. Like the commit text, it only
+// saves work later, so failures are logged.
+func saveCommitMeta(db *sql.DB, deps Deps, repoRoot, repoID, commit string) {
+	if deps.CommitMetadata == nil || commit == "" {
+		return
+	}
+	if ok, err := store.HasCommitMeta(db, repoID, commit); err != nil || ok {
+		return
+	}
+	meta := deps.CommitMetadata(repoRoot, commit)
+	if meta.AuthoredAt == "" {
+		// git couldn't read the commit; leave it to be read when shown
+		log.Debug("metadata: could not read metadata of %.7s", commit)
+		return
+	}
+	if err := store.SaveCommitMeta(db, repoID, commit, meta); err != nil {
+		log.Warn("metadata: could not store metadata of %.7s: %v", commit, err)
+	}
+}
+
+// saveCommitLanguages stores the lines commit changed per language, read
+// from the clone at repoRoot, unless they are already stored. They only
+// feed fp stats --by-language, so failures are logged.
+func saveCommitLanguages(db *sql.DB, deps Deps, repoRoot, repoID, commit string) {
+	if deps.CommitFileStats == nil || commit == "" {
+		return
+	}
+	if ok, err := store.HasCommitLanguages(db, repoID, commit); err != nil || ok {
+		return
+	}
+	stats, err := deps.CommitFileStats(repoRoot, commit)
+	if err != nil {
+		log.Debug("metadata: could not read file stats of %.7s: %v", commit, err)
+		return
+	}
+	if err := store.SaveCommitLanguages(db, repoID, commit, enrich.LinesByLanguage(stats)); err != nil {
+		log.Warn("metadata: could not store languages of %.7s: %v", commit, err)
+	}
+}
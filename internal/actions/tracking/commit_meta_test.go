@@ -0,0 +1,139 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/testutil"
+)
+
+func TestCommitMetaReader_PrefersStored(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	require.NoError(t, store.SaveCommitMeta(db, "github.com/user/repo", "aaa", git.CommitMetadata{Subject: "stored"}))
+
+	var reads []string
+	metas := newCommitMetaReader(db)
+	metas.read = func(_, commit string) git.CommitMetadata {
+		reads = append(reads, commit)
+		return git.CommitMetadata{Subject: "from git", AuthoredAt: "2026-03-02T10:00:00Z"}
+	}
+
+	stored := store.RepoEvent{RepoID: "github.com/user/repo", RepoPath: "/code/repo", Commit: "aaa"}
+	missing := store.RepoEvent{RepoID: "github.com/user/repo", RepoPath: "/code/repo", Commit: "bbb"}
+
+	require.Equal(t, "stored", metas.Of(stored).Subject)
+	require.Equal(t, "from git", metas.Of(missing).Subject)
+	require.Equal(t, "from git", metas.Of(missing).Subject)
+	require.Equal(t, []string{"bbb"}, reads, "git runs once, only for commits without stored metadata")
+
+	ok, err := store.HasCommitMeta(db, "github.com/user/repo", "bbb")
+	require.NoError(t, err)
+	require.False(t, ok, "readers only store what they read when asked to")
+
+	metas = newCommitMetaReader(db)
+	metas.read = func(_, _ string) git.CommitMetadata {
+		return git.CommitMetadata{Subject: "from git", AuthoredAt: "2026-03-02T10:00:00Z"}
+	}
+	metas.fill = true
+	metas.Of(missing)
+	ok, err = store.HasCommitMeta(db, "github.com/user/repo", "bbb")
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestSaveCommitMeta(t *testing.T) {
+	db := testutil.NewTestDB(t)
+
+	calls := 0
+	deps := Deps{CommitMetadata: func(repoPath, commit string) git.CommitMetadata {
+		calls++
+		if commit == "unreadable" {
+			return git.CommitMetadata{}
+		}
+		return git.CommitMetadata{Subject: "Fix login", AuthoredAt: "2026-03-02T10:00:00Z"}
+	}}
+
+	saveCommitMeta(db, deps, "/code/repo", "github.com/user/repo", "aaa")
+	saveCommitMeta(db, deps, "/code/repo", "github.com/user/repo", "aaa")
+	require.Equal(t, 1, calls, "stored metadata isn't read again")
+
+	meta, ok, err := store.GetCommitMeta(db, "github.com/user/repo", "aaa")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "Fix login", meta.Subject)
+
+	saveCommitMeta(db, deps, "/code/repo", "github.com/user/repo", "unreadable")
+	ok, err = store.HasCommitMeta(db, "github.com/user/repo", "unreadable")
+	require.NoError(t, err)
+	require.False(t, ok, "commits git can't read are left to be read when shown")
+
+	// Without a reader, as in tests of fp record, nothing is stored
+	saveCommitMeta(db, Deps{}, "/code/repo", "github.com/user/repo", "bbb")
+	ok, err = store.HasCommitMeta(db, "github.com/user/repo", "bbb")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestCommitMetaReader_Prefetch(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	require.NoError(t, store.SaveCommitMeta(db, "github.com/user/repo", "aaa", git.CommitMetadata{Subject: "stored"}))
+
+	var batches [][]string
+	metas := newCommitMetaReader(db)
+	metas.fill = true
+	metas.read = func(_, commit string) git.CommitMetadata {
+		t.Fatalf("read %s one by one after prefetching", commit)
+		return git.CommitMetadata{}
+	}
+	metas.readBatch = func(repoPath string, commits []string) (map[string]git.CommitMetadata, error) {
+		batches = append(batches, commits)
+		return map[string]git.CommitMetadata{
+			"bbb": {Subject: "from git", AuthoredAt: "2026-03-02T10:00:00Z"},
+		}, nil
+	}
+
+	events := []store.RepoEvent{
+		{RepoID: "github.com/user/repo", RepoPath: "/code/repo", Commit: "aaa"},
+		{RepoID: "github.com/user/repo", RepoPath: "/code/repo", Commit: "bbb"},
+		{RepoID: "github.com/user/repo", RepoPath: "/code/repo", Commit: "gone"},
+		{RepoID: "github.com/user/other", RepoPath: "", Commit: "ccc"},
+	}
+	metas.Prefetch(events)
+	require.Equal(t, [][]string{{"bbb", "gone"}}, batches, "one batch per repository, without stored commits")
+
+	require.Equal(t, "stored", metas.Of(events[0]).Subject)
+	require.Equal(t, "from git", metas.Of(events[1]).Subject)
+	require.Empty(t, metas.Of(events[2]).Subject, "commits git doesn't have aren't read again")
+
+	ok, err := store.HasCommitMeta(db, "github.com/user/repo", "bbb")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	metas.Prefetch(events)
+	require.Len(t, batches, 1, "prefetched commits aren't read again")
+}
+
+func TestSaveCommitMetas(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	require.NoError(t, store.SaveCommitMeta(db, "github.com/user/repo", "aaa", git.CommitMetadata{Subject: "stored"}))
+
+	var asked []string
+	deps := Deps{CommitMetadataBatch: func(repoPath string, commits []string) (map[string]git.CommitMetadata, error) {
+		asked = commits
+		return map[string]git.CommitMetadata{"bbb": {Subject: "Fix login", AuthoredAt: "2026-03-02T10:00:00Z"}}, nil
+	}}
+
+	saveCommitMetas(db, deps, "/code/repo", "github.com/user/repo", []string{"aaa", "bbb", "ccc"})
+	require.Equal(t, []string{"bbb", "ccc"}, asked)
+
+	meta, ok, err := store.GetCommitMeta(db, "github.com/user/repo", "bbb")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "Fix login", meta.Subject)
+	ok, err = store.HasCommitMeta(db, "github.com/user/repo", "ccc")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
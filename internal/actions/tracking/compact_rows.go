@@ -0,0 +1,79 @@
+package tracking
+
+import (
+	"path/filepath"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// compactWidth is the terminal width below which fp activity -i and fp
+// watch show compact rows: when, repo and subject, which is what stays
+// readable on half a laptop screen.
+const compactWidth = 100
+
+const (
+	// compactRepoWidth is the width of the repo column in compact rows.
+	compactRepoWidth = 14
+	// compactWhenWidth fits the date and time of fp activity rows in
+	// either clock, as in "01/23 3:04 PM".
+	compactWhenWidth = 13
+)
+
+// compactMode chooses between full and compact event rows. Rows follow the
+// terminal width until Ctrl+T picks one, which then sticks through resizes.
+type compactMode struct {
+	set bool
+	on  bool
+}
+
+// active reports whether rows are compact in a terminal width wide.
+func (c compactMode) active(width int) bool {
+	if c.set {
+		return c.on
+	}
+	return width < compactWidth
+}
+
+// toggle switches to the other row format.
+func (c *compactMode) toggle(width int) {
+	c.on = !c.active(width)
+	c.set = true
+}
+
+// compactEventLine renders a compact event row: when, repo and subject in
+// width columns. The selected row is drawn on accent, the color of its
+// source, as in full rows.
+func compactEventLine(when, repoPath, subject string, width int, selected, dim bool, accent lipgloss.Color, colors style.ColorConfig) string {
+	repo := filepath.Base(repoPath)
+	if len(repo) > compactRepoWidth {
+		repo = repo[:compactRepoWidth-3] + "..."
+	}
+
+	// 2 for the prefix, and a space after when and repo
+	msgWidth := max(5, width-2-len(when)-1-compactRepoWidth-1)
+	if len(subject) > msgWidth {
+		subject = subject[:msgWidth-3] + "..."
+	}
+
+	if selected {
+		line := "▸ " + when + " " + padRight(repo, compactRepoWidth) + " " + subject
+		return lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("0")).
+			Background(accent).
+			Render(line)
+	}
+
+	mutedColor := lipgloss.Color(colors.Muted)
+	if dim {
+		mutedColor = lipgloss.Color(colors.UIDim)
+	}
+	mutedStyle := lipgloss.NewStyle().Foreground(mutedColor)
+	repoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Info))
+	return "  " +
+		mutedStyle.Render(when) + " " +
+		repoStyle.Render(padRight(repo, compactRepoWidth)) + " " +
+		mutedStyle.Render(subject)
+}
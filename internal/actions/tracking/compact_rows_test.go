@@ -0,0 +1,54 @@
+package tracking
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/text"
+)
+
+func TestCompactMode(t *testing.T) {
+	var c compactMode
+	require.True(t, c.active(80), "narrow terminals get compact rows")
+	require.False(t, c.active(140))
+
+	c.toggle(140)
+	require.True(t, c.active(140))
+	require.True(t, c.active(80), "once toggled, the choice sticks through resizes")
+
+	c.toggle(80)
+	require.False(t, c.active(80))
+}
+
+func TestActivityModel_CompactRows(t *testing.T) {
+	events := []store.RepoEvent{{
+		ID: 1, Commit: "abc1234567", RepoPath: "/code/checkout-service", Branch: "feature/payments",
+		Source: store.SourcePostCommit, Timestamp: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+	}}
+	meta := map[string]git.CommitMetadata{"abc1234567": {Subject: "Retry declined cards"}}
+
+	view := func(width int, keys ...tea.KeyType) string {
+		var model tea.Model = newActivityModel(events, meta)
+		model, _ = model.Update(tea.WindowSizeMsg{Width: width, Height: 30})
+		for _, k := range keys {
+			model, _ = model.Update(tea.KeyMsg{Type: k})
+		}
+		return text.StripANSI(model.View())
+	}
+
+	compact := view(90)
+	require.Contains(t, compact, "WHEN")
+	require.Contains(t, compact, "Retry declined cards", "the subject survives on a narrow terminal")
+	require.NotContains(t, compact, "feature/...", "the branch column is left out")
+
+	full := view(160)
+	require.Contains(t, full, "BRANCH")
+	require.True(t, strings.Contains(view(160, tea.KeyCtrlT), "WHEN"), "Ctrl+T switches to compact rows")
+	require.Contains(t, view(90, tea.KeyCtrlT), "BRANCH", "and back")
+}
@@ -0,0 +1,142 @@
+package tracking
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// defaultBranchTTL is how long a stored default branch is trusted before
+// fp record reads it from git again; origin/HEAD rarely moves.
+const defaultBranchTTL = 24 * time.Hour
+
+// saveDefaultBranch stores the default branch of the repository at
+// repoRoot, unless one stored less than defaultBranchTTL ago. Like the
+// commit metadata it only adds detail, so failures are logged.
+func saveDefaultBranch(db *sql.DB, deps Deps, repoRoot, repoID string) {
+	if deps.DefaultBranch == nil || repoID == "" {
+		return
+	}
+	now := deps.Now()
+	if _, checked, ok, err := store.GetDefaultBranch(db, repoID); err != nil || (ok && now.Sub(checked) < defaultBranchTTL) {
+		return
+	}
+	branch, err := deps.DefaultBranch(repoRoot)
+	if err != nil {
+		log.Debug("record: could not read the default branch of %s: %v", repoID, err)
+		return
+	}
+	if err := store.SaveDefaultBranch(db, repoID, branch, now); err != nil {
+		log.Warn("record: could not store the default branch of %s: %v", repoID, err)
+	}
+}
+
+// defaultBranches tells whether events happened on their repository's
+// default branch. Branches fp record and backfill stored are used first;
+// repositories without one are read from git once, and when git can't
+// tell either, main and master count as default.
+type defaultBranches struct {
+	stored map[string]string // by repo id
+	read   func(repoPath string) (string, error)
+	seen   map[string]string // read from git, by repo id; "" when unknown
+}
+
+// loadDefaultBranches reads the stored default branches. A store that
+// can't be read leaves every repository to git.
+func loadDefaultBranches(db *sql.DB) *defaultBranches {
+	stored, err := store.DefaultBranches(db)
+	if err != nil {
+		log.Debug("branches: could not read stored default branches: %v", err)
+	}
+	return &defaultBranches{
+		stored: stored,
+		read:   git.DefaultBranch,
+		seen:   make(map[string]string),
+	}
+}
+
+// Of returns the default branch of e's repository, or "" if unknown.
+func (b *defaultBranches) Of(e store.RepoEvent) string {
+	if branch, ok := b.stored[e.RepoID]; ok {
+		return branch
+	}
+	branch, ok := b.seen[e.RepoID]
+	if !ok {
+		if e.RepoPath != "" {
+			branch, _ = b.read(e.RepoPath)
+		}
+		b.seen[e.RepoID] = branch
+	}
+	return branch
+}
+
+// OnDefault reports whether e happened on its repository's default
+// branch. Events without a branch (detached HEAD) are on none.
+func (b *defaultBranches) OnDefault(e store.RepoEvent) bool {
+	if !hasBranch(e) {
+		return false
+	}
+	if def := b.Of(e); def != "" {
+		return e.Branch == def
+	}
+	return e.Branch == "main" || e.Branch == "master"
+}
+
+// Filter returns the events on their repository's default branch.
+func (b *defaultBranches) Filter(events []store.RepoEvent) []store.RepoEvent {
+	kept := make([]store.RepoEvent, 0, len(events))
+	for _, e := range events {
+		if b.OnDefault(e) {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+// branchShare counts the events with a branch by whether it was their
+// repository's default branch.
+func (b *defaultBranches) branchShare(events []store.RepoEvent) (onDefault, onFeature int) {
+	for _, e := range events {
+		switch {
+		case !hasBranch(e):
+		case b.OnDefault(e):
+			onDefault++
+		default:
+			onFeature++
+		}
+	}
+	return onDefault, onFeature
+}
+
+func hasBranch(e store.RepoEvent) bool {
+	return e.Branch != "" && e.Branch != "HEAD"
+}
+
+// formatBase is the line of the multi-line activity formats naming the
+// default branch an event on a feature branch diverges from, or "" for
+// events on the default branch or without a known one.
+func formatBase(e store.RepoEvent, branches *defaultBranches) string {
+	if branches == nil || !hasBranch(e) || branches.OnDefault(e) {
+		return ""
+	}
+	def := branches.Of(e)
+	if def == "" {
+		return ""
+	}
+	return style.Muted("Base: ") + def + "\n"
+}
+
+// featureShare formats how much of the events with a branch happened on
+// feature branches, as "80%", or "" when none have a branch.
+func featureShare(onDefault, onFeature int) string {
+	total := onDefault + onFeature
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%%", 100*float64(onFeature)/float64(total))
+}
@@ -0,0 +1,120 @@
+package tracking
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveDefaultBranch_RefreshesOncePerDay(t *testing.T) {
+	db := newTestStore(t).DB()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	reads := 0
+	branch := "main"
+	deps := Deps{
+		Now:           func() time.Time { return now },
+		DefaultBranch: func(string) (string, error) { reads++; return branch, nil },
+	}
+
+	saveDefaultBranch(db, deps, "/r", "local:/r")
+	branch = "trunk"
+	now = now.Add(time.Hour)
+	saveDefaultBranch(db, deps, "/r", "local:/r")
+
+	stored, _, _, err := store.GetDefaultBranch(db, "local:/r")
+	require.NoError(t, err)
+	require.Equal(t, "main", stored)
+	require.Equal(t, 1, reads, "a branch read an hour ago is trusted")
+
+	now = now.Add(defaultBranchTTL)
+	saveDefaultBranch(db, deps, "/r", "local:/r")
+	stored, _, _, err = store.GetDefaultBranch(db, "local:/r")
+	require.NoError(t, err)
+	require.Equal(t, "trunk", stored)
+}
+
+func TestSaveDefaultBranch_GitFailureStoresNothing(t *testing.T) {
+	db := newTestStore(t).DB()
+	deps := Deps{
+		Now:           time.Now,
+		DefaultBranch: func(string) (string, error) { return "", errors.New("no default branch") },
+	}
+
+	saveDefaultBranch(db, deps, "/r", "local:/r")
+	saveDefaultBranch(db, Deps{Now: time.Now}, "/r", "local:/r")
+
+	_, _, ok, err := store.GetDefaultBranch(db, "local:/r")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestDefaultBranches(t *testing.T) {
+	db := newTestStore(t).DB()
+	require.NoError(t, store.SaveDefaultBranch(db, "github.com/me/app", "develop", time.Now()))
+
+	branches := loadDefaultBranches(db)
+	reads := 0
+	branches.read = func(repoPath string) (string, error) {
+		reads++
+		if repoPath == "/code/lib" {
+			return "trunk", nil
+		}
+		return "", errors.New("no default branch")
+	}
+	ev := func(repoID, repoPath, branch string) store.RepoEvent {
+		return store.RepoEvent{RepoID: repoID, RepoPath: repoPath, Branch: branch}
+	}
+
+	events := []store.RepoEvent{
+		ev("github.com/me/app", "/code/app", "develop"),
+		ev("github.com/me/app", "/code/app", "main"),
+		ev("github.com/me/lib", "/code/lib", "trunk"),
+		ev("github.com/me/lib", "/code/lib", "fix-login"),
+		ev("local:/gone", "/gone", "master"),
+		ev("local:/gone", "/gone", "spike"),
+		ev("github.com/me/app", "/code/app", "HEAD"),
+	}
+
+	kept := branches.Filter(events)
+	require.Equal(t, []store.RepoEvent{events[0], events[2], events[4]}, kept, "main and master are the default when git can't tell")
+	require.Equal(t, 2, reads, "each repository without a stored branch is read once")
+
+	onDefault, onFeature := branches.branchShare(events)
+	require.Equal(t, 3, onDefault)
+	require.Equal(t, 3, onFeature, "events without a branch are on neither")
+	require.Equal(t, "50%", featureShare(onDefault, onFeature))
+	require.Empty(t, featureShare(0, 0))
+
+	require.Empty(t, formatBase(events[0], branches))
+	require.Contains(t, formatBase(events[3], branches), "trunk")
+	require.Empty(t, formatBase(events[5], branches), "no line without a known default branch")
+}
+
+func TestStats_FeatureBranchShare(t *testing.T) {
+	db := newTestStore(t).DB()
+	require.NoError(t, store.SaveDefaultBranch(db, "github.com/me/app", "main", time.Now()))
+
+	now := time.Date(2026, 3, 4, 18, 0, 0, 0, time.Local)
+	var events []store.RepoEvent
+	for i, branch := range []string{"main", "feature", "feature", "feature", "feature"} {
+		events = append(events, store.RepoEvent{
+			RepoID: "github.com/me/app", RepoPath: "/code/app", Commit: "c" + string(rune('a'+i)),
+			Branch: branch, Timestamp: now.Add(-time.Duration(i) * time.Hour),
+		})
+	}
+
+	var printed strings.Builder
+	deps := statsTestDeps(&printed)
+	deps.Now = func() time.Time { return now }
+	deps.OpenDB = func(string) (*sql.DB, error) { return db, nil }
+	deps.ListEvents = func(*sql.DB, store.EventFilter) ([]store.RepoEvent, error) { return events, nil }
+
+	require.NoError(t, stats(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.Contains(t, printed.String(), "80% of events on feature branches, 4 of 5")
+}
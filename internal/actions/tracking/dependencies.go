@@ -2,11 +2,14 @@ package tracking
 
 import (
 	"database/sql"
+	"io"
 	"os"
 	"time"
 
 	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/enrich"
 	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
 	repodomain "github.com/footprint-tools/cli/internal/repo"
 	"github.com/footprint-tools/cli/internal/store"
 	"github.com/footprint-tools/cli/internal/ui"
@@ -16,6 +19,7 @@ type Deps struct {
 	// git
 	GitIsAvailable func() bool
 	RepoRoot       func(string) (string, error)
+	BareRepoDir    func(string) (string, error)
 	OriginURL      func(string) (string, error)
 	ListRemotes    func(string) ([]string, error)
 	GetRemoteURL   func(string, string) (string, error)
@@ -23,6 +27,20 @@ type Deps struct {
 	CurrentBranch  func() (string, error)
 	CommitMessage  func() (string, error)
 	CommitAuthor   func() (string, error)
+	CommitText     func(repoPath, commit string) (string, string, error)
+	CommitMetadata func(repoPath, commit string) git.CommitMetadata
+	// CommitMetadataBatch reads many commits at once; nil reads them one
+	// by one with CommitMetadata
+	CommitMetadataBatch func(repoPath string, commits []string) (map[string]git.CommitMetadata, error)
+	CommitFileStats     func(repoPath, commit string) ([]git.FileStat, error)
+	DefaultBranch       func(repoPath string) (string, error)
+	BranchTips          func(repoPath string) (map[string]string, error)
+	// CountPushCommits counts the commits a push sends; nil leaves them
+	// uncounted
+	CountPushCommits func(repoPath, localSHA, remoteSHA, remote string) (int, bool)
+
+	// enrichment
+	Enrichers func() enrich.Pipeline
 
 	// repo
 	DeriveID func(string, string) (repodomain.RepoID, error)
@@ -31,15 +49,22 @@ type Deps struct {
 	DBPath       func() string
 	OpenDB       func(string) (*sql.DB, error)
 	OpenStore    func(string) (*store.Store, error)
+	OpenSnapshot func(string) (*sql.DB, error)
 	InitDB       func(*sql.DB) error
 	InsertEvent  func(*sql.DB, store.RepoEvent) error
 	ListEvents   func(*sql.DB, store.EventFilter) ([]store.RepoEvent, error)
 	MarkOrphaned func(repoID repodomain.RepoID) (int64, error)
 
 	// io
+	Stdin   io.Reader
 	Printf  func(string, ...any) (int, error)
 	Println func(...any) (int, error)
 	Pager   func(string)
+	// Accessible reports whether interactive views should be numbered
+	// prompts and plain text instead of a TUI (see ui.IsAccessible), asked
+	// through NewPrompt.
+	Accessible func() bool
+	NewPrompt  func() *ui.LinearPrompt
 
 	// misc
 	Now    func() time.Time
@@ -50,34 +75,61 @@ type Deps struct {
 	HasRemote      func(string) bool
 	PullExportRepo func(string) error
 	PushExportRepo func(string) error
+	// PushExportMirrors pushes to the export_mirrors remotes; nil skips them
+	PushExportMirrors func(string) []remotePush
+
+	// metas is set by doExportWork for the sinks, so they use the commit
+	// metadata in the store; nil reads it from git.
+	metas *commitMetaReader
+	// pushes is set by doExportWork for the sinks: the pushed refs of the
+	// pre-push events being exported, by event ID.
+	pushes map[int64][]store.PushRef
+	// progress is told each step of an export run from a TUI, to show
+	// while it works; nil reports nothing.
+	progress func(step string)
 }
 
 func DefaultDeps() Deps {
 	return Deps{
-		GitIsAvailable: git.IsAvailable,
-		RepoRoot:       git.RepoRoot,
-		OriginURL:      git.OriginURL,
-		ListRemotes:    git.ListRemotes,
-		GetRemoteURL:   git.GetRemoteURL,
-		HeadCommit:     git.HeadCommit,
-		CurrentBranch:  git.CurrentBranch,
-		CommitMessage:  git.CommitMessage,
-		CommitAuthor:   git.CommitAuthor,
+		GitIsAvailable:      git.IsAvailable,
+		RepoRoot:            git.RepoRoot,
+		BareRepoDir:         git.BareRepoDir,
+		OriginURL:           git.OriginURL,
+		ListRemotes:         git.ListRemotes,
+		GetRemoteURL:        git.GetRemoteURL,
+		HeadCommit:          git.HeadCommit,
+		CurrentBranch:       git.CurrentBranch,
+		CommitMessage:       git.CommitMessage,
+		CommitAuthor:        git.CommitAuthor,
+		CommitText:          git.CommitText,
+		CommitMetadata:      git.GetCommitMetadata,
+		CommitMetadataBatch: git.GetCommitMetadataBatch,
+		CommitFileStats:     git.CommitFileStats,
+		DefaultBranch:       git.DefaultBranch,
+		BranchTips:          git.BranchTips,
+		CountPushCommits:    git.CountPushCommits,
+
+		Enrichers: enrich.FromConfig,
 
 		DeriveID: repodomain.DeriveID,
 
 		DBPath:       store.DBPath,
 		OpenDB:       openDBFresh,
 		OpenStore:    store.New,
+		OpenSnapshot: openSnapshot,
 		InitDB:       store.Init,
 		InsertEvent:  store.InsertEvent,
 		ListEvents:   store.ListEvents,
 		MarkOrphaned: markOrphanedWrapper,
 
+		Stdin:   os.Stdin,
 		Printf:  ui.Printf,
 		Println: ui.Println,
 		Pager:   ui.Pager,
 
+		Accessible: ui.IsAccessible,
+		NewPrompt:  ui.NewTerminalPrompt,
+
 		Now:    time.Now,
 		Getenv: os.Getenv,
 
@@ -85,6 +137,8 @@ func DefaultDeps() Deps {
 		HasRemote:      hasRemote,
 		PullExportRepo: pullExportRepo,
 		PushExportRepo: pushExportRepo,
+
+		PushExportMirrors: pushExportMirrors,
 	}
 }
 
@@ -100,6 +154,18 @@ func openDBFresh(path string) (*sql.DB, error) {
 	return s.DB(), nil
 }
 
+// openSnapshot opens a read-only connection for the interactive views,
+// falling back to a regular one if the database can't be opened read-only
+// (e.g. the directory is not writable and the WAL index doesn't exist yet).
+func openSnapshot(path string) (*sql.DB, error) {
+	db, err := store.OpenSnapshot(path)
+	if err != nil {
+		log.Debug("store: read-only snapshot unavailable, using a regular connection: %v", err)
+		return openDBFresh(path)
+	}
+	return db, nil
+}
+
 // markOrphanedWrapper opens the database, marks events as orphaned, and closes.
 func markOrphanedWrapper(repoID repodomain.RepoID) (int64, error) {
 	s, err := store.New(store.DBPath())
@@ -0,0 +1,62 @@
+package tracking
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// unknownDevice labels events recorded before devices were stored.
+const unknownDevice = "unknown"
+
+// deviceLabel is the device an event is shown under in the device panels.
+func deviceLabel(e store.RepoEvent) string {
+	if e.Device == "" {
+		return unknownDevice
+	}
+	return e.Device
+}
+
+// namedCount is a device or repo with its number of events.
+type namedCount struct {
+	name  string
+	count int
+}
+
+// sortedCounts orders devices or repos by event count, busiest first, then
+// by name so the panels don't reshuffle between renders.
+func sortedCounts(counts map[string]int) []namedCount {
+	sorted := make([]namedCount, 0, len(counts))
+	for name, count := range counts {
+		sorted = append(sorted, namedCount{name, count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].count != sorted[j].count {
+			return sorted[i].count > sorted[j].count
+		}
+		return sorted[i].name < sorted[j].name
+	})
+	return sorted
+}
+
+// deviceBreakdownLines renders one line per device with its event count,
+// marking selected the way the source filters mark theirs.
+func deviceBreakdownLines(counts map[string]int, selected string, width int, valueStyle, labelStyle lipgloss.Style) []string {
+	var lines []string
+	for _, d := range sortedCounts(counts) {
+		indicator := "  "
+		if selected != "" && d.name == selected {
+			indicator = "> "
+		}
+		countStr := fmt.Sprintf(" %d", d.count)
+		maxNameWidth := width - len(countStr) - 2 // -2 for indent
+		name := d.name
+		if maxNameWidth > 3 && len(name) > maxNameWidth {
+			name = name[:maxNameWidth-3] + "..."
+		}
+		lines = append(lines, indicator+valueStyle.Render(name)+labelStyle.Render(countStr))
+	}
+	return lines
+}
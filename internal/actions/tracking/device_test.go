@@ -0,0 +1,27 @@
+package tracking
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestDeviceName(t *testing.T) {
+	setRedactionConfig(t)
+	require.Equal(t, getHostname(), deviceName())
+
+	setRedactionConfig(t, "device_name", "  work-laptop ")
+	require.Equal(t, "work-laptop", deviceName())
+}
+
+func TestDeviceLabel(t *testing.T) {
+	require.Equal(t, "laptop", deviceLabel(store.RepoEvent{Device: "laptop"}))
+	require.Equal(t, unknownDevice, deviceLabel(store.RepoEvent{}))
+}
+
+func TestSortedDeviceCounts(t *testing.T) {
+	got := sortedCounts(map[string]int{"desktop": 2, "laptop": 5, "ci": 2})
+	require.Equal(t, []namedCount{{"laptop", 5}, {"ci", 2}, {"desktop", 2}}, got)
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package tracking
+
+import "syscall"
+
+// availableDiskSpace returns the bytes free for this user on the
+// filesystem holding dir.
+func availableDiskSpace(dir string) (int64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	// Available blocks * block size
+	return int64(stat.Bavail) * int64(stat.Bsize), nil
+}
@@ -0,0 +1,441 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/daemon"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/hooks"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+const (
+	// remoteCheckTimeout bounds how long doctor waits on git ls-remote.
+	remoteCheckTimeout = 10 * time.Second
+
+	// staleIndexLockAge is how old .git/index.lock in the export repo must be
+	// before doctor treats it as left behind by a crashed git process.
+	staleIndexLockAge = 10 * time.Minute
+)
+
+type doctorStatus string
+
+const (
+	doctorPass doctorStatus = output.CheckPass
+	doctorWarn doctorStatus = output.CheckWarn
+	doctorFail doctorStatus = output.CheckFail
+)
+
+// doctorResult is the outcome of one doctor check. ID is stable for scripts
+// consuming --json; Name is what the text output shows. Hint is the command
+// that fixes the problem by hand; fix is set when --fix can remediate it.
+type doctorResult struct {
+	ID       string       `json:"id"`
+	Name     string       `json:"name"`
+	Status   doctorStatus `json:"status"`
+	Severity string       `json:"severity"`
+	Detail   string       `json:"detail"`
+	Hint     string       `json:"hint,omitempty"`
+	Fixable  bool         `json:"fixable"`
+	Fixed    bool         `json:"fixed,omitempty"`
+
+	fix func() error
+}
+
+// doctorResultJSON is a doctor result as fp doctor --json prints it.
+type doctorResultJSON struct {
+	SchemaVersion int `json:"schema_version"`
+	doctorResult
+}
+
+func doctorJSON(results []doctorResult) []doctorResultJSON {
+	out := make([]doctorResultJSON, len(results))
+	for i, r := range results {
+		out[i] = doctorResultJSON{SchemaVersion: output.SchemaVersion, doctorResult: r}
+	}
+	return out
+}
+
+// Doctor handles the `fp doctor` command.
+func Doctor(args []string, flags *dispatchers.ParsedFlags) error {
+	return doctor(args, flags, DefaultDeps())
+}
+
+// doctor runs every diagnostic check and, with --fix, remediates the
+// problems that can be fixed without user input.
+func doctor(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	fix := flags.Has("--fix")
+	jsonOutput := flags.Has("--json")
+
+	if fix && config.IsReadOnly() {
+		return usage.ReadOnly("fp doctor --fix")
+	}
+
+	results := runDoctorChecks(deps)
+	for i := range results {
+		r := &results[i]
+		r.Severity = output.Severity(string(r.Status))
+		r.Fixable = r.Status != doctorPass && r.fix != nil
+	}
+
+	if fix {
+		for i := range results {
+			r := &results[i]
+			if r.Status == doctorPass || r.fix == nil {
+				continue
+			}
+			if err := r.fix(); err != nil {
+				r.Detail = fmt.Sprintf("%s (fix failed: %v)", r.Detail, err)
+				continue
+			}
+			r.Fixed = true
+		}
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Status == doctorFail && !r.Fixed {
+			failed++
+		}
+	}
+
+	if jsonOutput {
+		if err := output.JSON(deps.Println, doctorJSON(results)); err != nil {
+			return err
+		}
+	} else {
+		printDoctorResults(results, fix, deps)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("fp doctor found %d %s", failed, pluralize(failed, "problem", "problems"))
+	}
+	return nil
+}
+
+func printDoctorResults(results []doctorResult, fixRan bool, deps Deps) {
+	width := 0
+	for _, r := range results {
+		width = max(width, len(r.Name))
+	}
+
+	counts := map[doctorStatus]int{}
+	fixable := 0
+	for _, r := range results {
+		marker := style.Success("✓")
+		switch {
+		case r.Fixed:
+			marker = style.Success("✓")
+		case r.Status == doctorWarn:
+			marker = style.Warning("!")
+		case r.Status == doctorFail:
+			marker = style.Error("✗")
+		}
+
+		detail := r.Detail
+		if r.Fixed {
+			detail += style.Success(" (fixed)")
+		}
+		_, _ = deps.Printf("%s %-*s  %s\n", marker, width, r.Name, detail)
+
+		if r.Status != doctorPass && !r.Fixed {
+			if r.fix != nil && !fixRan {
+				fixable++
+			}
+			if r.Hint != "" {
+				_, _ = deps.Printf("  %-*s  %s %s\n", width, "", style.Muted("→"), style.Info(r.Hint))
+			}
+		}
+
+		status := r.Status
+		if r.Fixed {
+			status = doctorPass
+		}
+		counts[status]++
+	}
+
+	_, _ = deps.Printf("\n%d passed, %d %s, %d failed\n",
+		counts[doctorPass], counts[doctorWarn], pluralize(counts[doctorWarn], "warning", "warnings"), counts[doctorFail])
+	if fixable > 0 {
+		_, _ = deps.Printf("Run 'fp doctor --fix' to fix %d %s automatically\n", fixable, pluralize(fixable, "issue", "issues"))
+	}
+}
+
+// runDoctorChecks runs the checks in display order. git, hooks and the
+// database are the setup checks fp setup --check runs too.
+func runDoctorChecks(deps Deps) []doctorResult {
+	env := newSetupEnv(deps)
+	defer env.close()
+
+	results := []doctorResult{doctorFromSetup(checkGit(env), doctorFail)}
+	if env.gitAvailable {
+		if hooksCheck := checkHooksUpToDate(env); hooksCheck.ID != "" {
+			results = append(results, doctorHooks(hooksCheck))
+		}
+	}
+	results = append(results, doctorDatabase(env, deps.DBPath()))
+
+	exportRepo := deps.GetExportRepo()
+	results = append(results, doctorExportRepo(exportRepo, deps))
+	if env.gitAvailable {
+		results = append(results, doctorRemote(exportRepo, deps))
+	}
+
+	results = append(results, doctorConfig(), doctorLockfiles(exportRepo))
+	return results
+}
+
+// doctorFromSetup reports a setup check, with status when it fails.
+func doctorFromSetup(c setupCheck, status doctorStatus) doctorResult {
+	r := doctorResult{ID: c.ID, Name: c.ID, Status: doctorPass, Detail: c.Title}
+	if c.OK {
+		return r
+	}
+	r.Status = status
+	if c.Detail != "" {
+		r.Detail += ": " + c.Detail
+	}
+	r.Hint = strings.Join(c.Fix, "; ")
+	return r
+}
+
+// doctorHooks reports checkHooksUpToDate as a warning that --fix repairs
+// by refreshing the stale hooks.
+func doctorHooks(c setupCheck) doctorResult {
+	r := doctorFromSetup(c, doctorWarn)
+	if c.OK {
+		return r
+	}
+	r.fix = func() error {
+		var errs []error
+		for _, path := range c.stale {
+			if _, err := hooks.Refresh(path); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+	return r
+}
+
+// doctorDatabase reports checkDatabase and, once the database opens, runs
+// SQLite's integrity check on it.
+func doctorDatabase(env setupEnv, dbPath string) doctorResult {
+	r := doctorFromSetup(checkDatabase(env), doctorFail)
+	if r.Status != doctorPass {
+		return r
+	}
+
+	problems, err := env.store.IntegrityCheck()
+	if err != nil {
+		r.Status = doctorFail
+		r.Detail = fmt.Sprintf("integrity check failed: %v", err)
+		return r
+	}
+	if len(problems) > 0 {
+		r.Status = doctorFail
+		r.Detail = fmt.Sprintf("integrity check reported: %s", problems[0])
+		r.Hint = fmt.Sprintf("Restore %s from a backup, or move it aside and run 'fp backfill --all'", dbPath)
+		return r
+	}
+	r.Detail = "integrity check ok"
+	return r
+}
+
+func doctorExportRepo(exportRepo string, deps Deps) doctorResult {
+	r := doctorResult{ID: "export_repo", Name: "export repo"}
+
+	state := diagnoseExportRepo(exportRepo)
+	switch state {
+	case exportRepoClean:
+		r.Status = doctorPass
+		r.Detail = exportRepo
+		return r
+	case exportRepoMissing:
+		// Created on the first export, so this is only worth a warning
+		r.Status = doctorWarn
+		r.Detail = fmt.Sprintf("%s is not initialized yet", exportRepo)
+		r.Hint = "fp export --now"
+	default:
+		r.Status = doctorFail
+		r.Detail = fmt.Sprintf("%s: %s", exportRepo, state)
+		r.Hint = "fp export repair"
+	}
+	r.fix = func() error {
+		return repairExportRepoState(exportRepo, state, deps)
+	}
+	return r
+}
+
+// doctorRemote checks that the export remote answers, without fetching.
+func doctorRemote(exportRepo string, deps Deps) doctorResult {
+	r := doctorResult{ID: "export_remote", Name: "remote"}
+
+	if diagnoseExportRepo(exportRepo) == exportRepoMissing || !deps.HasRemote(exportRepo) {
+		r.Status = doctorPass
+		r.Detail = "no export remote configured"
+		return r
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteCheckTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--heads", "origin")
+	cmd.Dir = exportRepo
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.Status = doctorWarn
+		kind, reason := classifyPushFailure(string(out))
+		if ctx.Err() != nil {
+			kind, reason = pushFailureNetwork, fmt.Sprintf("timed out after %s", remoteCheckTimeout)
+		} else if reason == "" {
+			reason = err.Error()
+		}
+		r.Detail = "origin is not reachable: " + reason
+		r.Hint = fmt.Sprintf("cd %s && git remote -v", shellArg(exportRepo))
+		if kind != pushFailureUnknown {
+			r.Hint = pushFailureHint(kind, remoteURL(exportRepo, primaryRemote))
+		}
+		return r
+	}
+
+	r.Status = doctorPass
+	r.Detail = "origin is reachable"
+	return r
+}
+
+// doctorConfig checks that ~/.fprc parses and that known keys hold values
+// fp can use.
+func doctorConfig() doctorResult {
+	r := doctorResult{ID: "config", Name: "config"}
+
+	lines, err := config.ReadLines()
+	if err != nil {
+		r.Status = doctorFail
+		r.Detail = fmt.Sprintf("could not read config: %v", err)
+		return r
+	}
+	cfg, err := config.Parse(lines)
+	if err != nil {
+		r.Status = doctorFail
+		r.Detail = fmt.Sprintf("~/.fprc: %v", err)
+		r.Hint = "Fix the line with 'fp config edit', or remove ~/.fprc to start over"
+		return r
+	}
+
+	var problems []string
+	var unknown []string
+	for key := range cfg {
+		if !domain.IsValidConfigKey(key) {
+			unknown = append(unknown, key)
+		}
+	}
+	slices.Sort(unknown)
+	for _, key := range unknown {
+		problems = append(problems, fmt.Sprintf("unknown key '%s'", key))
+	}
+
+	if theme := cfg["theme"]; theme != "" && theme != style.AutoTheme && !isKnownTheme(theme) {
+		problems = append(problems, fmt.Sprintf("unknown theme '%s'", theme))
+	}
+	for _, key := range []string{"theme_dark", "theme_light"} {
+		if theme := cfg[key]; theme != "" && !isKnownTheme(theme) {
+			problems = append(problems, fmt.Sprintf("unknown theme '%s' in %s", theme, key))
+		}
+	}
+	keys := make([]string, 0, len(cfg))
+	for key := range cfg {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if err := domain.ValidateConfigValue(key, cfg[key]); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if format, err := resolveExportFormat(""); err != nil {
+		problems = append(problems, firstLine(err.Error()))
+	} else if _, err := resolveExportSinks(format, false); err != nil {
+		problems = append(problems, firstLine(err.Error()))
+	}
+
+	if len(problems) == 0 {
+		r.Status = doctorPass
+		r.Detail = "~/.fprc is valid"
+		return r
+	}
+
+	r.Status = doctorWarn
+	r.Detail = strings.Join(problems, "; ")
+	r.Hint = "fp config list"
+	if len(unknown) > 0 {
+		r.Hint = fmt.Sprintf("fp config unset %s", unknown[0])
+	}
+	return r
+}
+
+func isKnownTheme(name string) bool {
+	return slices.Contains(style.BaseThemeNames, name) || slices.Contains(style.AllThemeNames(), name)
+}
+
+// doctorLockfiles finds lock and pid files left behind by processes that
+// died: the config lock, git's index.lock in the export repo, and the
+// daemon pidfile.
+func doctorLockfiles(exportRepo string) doctorResult {
+	r := doctorResult{ID: "lockfiles", Name: "lockfiles"}
+
+	var stale []string
+	if path, ok := config.StaleLock(); ok {
+		stale = append(stale, path)
+	}
+	indexLock := filepath.Join(exportRepo, ".git", "index.lock")
+	if info, err := os.Stat(indexLock); err == nil && time.Since(info.ModTime()) > staleIndexLockAge {
+		stale = append(stale, indexLock)
+	}
+	pidPath := daemon.PIDFilePath()
+	if _, err := daemon.ReadPID(pidPath); err == nil {
+		if _, running := daemon.Running(pidPath); !running {
+			stale = append(stale, pidPath)
+		}
+	}
+
+	if len(stale) == 0 {
+		r.Status = doctorPass
+		r.Detail = "no stale lockfiles"
+		return r
+	}
+
+	r.Status = doctorWarn
+	r.Detail = "stale: " + strings.Join(stale, ", ")
+	r.Hint = "rm " + shellArg(stale[0])
+	r.fix = func() error {
+		var errs []error
+		for _, path := range stale {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+	return r
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
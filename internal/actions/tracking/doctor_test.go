@@ -0,0 +1,129 @@
+package tracking
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func doctorTestDeps(t *testing.T, out *strings.Builder) (Deps, string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	exportRepo := filepath.Join(t.TempDir(), "export")
+	deps := statusTestDeps(filepath.Join(t.TempDir(), "store.db"), out)
+	deps.RepoRoot = func(string) (string, error) { return "", errors.New("not a git repository") }
+	deps.GetExportRepo = func() string { return exportRepo }
+	deps.HasRemote = func(string) bool { return false }
+	return deps, home
+}
+
+func findDoctorResult(t *testing.T, results []doctorResult, name string) doctorResult {
+	t.Helper()
+	for _, r := range results {
+		if r.Name == name {
+			return r
+		}
+	}
+	t.Fatalf("no %q check in results", name)
+	return doctorResult{}
+}
+
+func TestDoctor_FreshInstall(t *testing.T) {
+	var out strings.Builder
+	deps, _ := doctorTestDeps(t, &out)
+
+	err := doctor(nil, dispatchers.NewParsedFlags(nil), deps)
+	require.NoError(t, err, "warnings alone don't fail doctor")
+
+	text := out.String()
+	require.Contains(t, text, "integrity check ok")
+	require.Contains(t, text, "is not initialized yet")
+	require.Contains(t, text, "Run 'fp doctor --fix' to fix 1 issue automatically")
+}
+
+func TestDoctor_FixRepairsAndRemovesStaleLocks(t *testing.T) {
+	var out strings.Builder
+	deps, home := doctorTestDeps(t, &out)
+
+	lockPath := filepath.Join(home, ".fprc.lock")
+	require.NoError(t, os.WriteFile(lockPath, []byte("123"), 0600))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(lockPath, old, old))
+
+	err := doctor(nil, dispatchers.NewParsedFlags([]string{"--fix", "--json"}), deps)
+	require.NoError(t, err)
+
+	var results []doctorResult
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &results))
+
+	require.True(t, findDoctorResult(t, results, "export repo").Fixed)
+	require.True(t, findDoctorResult(t, results, "lockfiles").Fixed)
+	require.NoFileExists(t, lockPath)
+	require.DirExists(t, filepath.Join(deps.GetExportRepo(), ".git"))
+}
+
+func TestDoctor_JSONHasStableIDsAndSeverities(t *testing.T) {
+	var out strings.Builder
+	deps, _ := doctorTestDeps(t, &out)
+
+	_ = doctor(nil, dispatchers.NewParsedFlags([]string{"--json"}), deps)
+
+	var results []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &results))
+
+	ids := make([]string, 0, len(results))
+	for _, r := range results {
+		ids = append(ids, r["id"].(string))
+		require.Contains(t, []string{"info", "warning", "error"}, r["severity"])
+		require.Contains(t, r, "fixable")
+	}
+	require.Subset(t, ids, []string{"git", "database", "export_repo", "config", "lockfiles"})
+
+	byID := make(map[string]map[string]any)
+	for _, r := range results {
+		byID[r["id"].(string)] = r
+	}
+	// A missing export repo is fixable by --fix
+	require.Equal(t, true, byID["export_repo"]["fixable"])
+	require.NotEqual(t, "info", byID["export_repo"]["severity"])
+}
+
+func TestDoctor_ConfigProblems(t *testing.T) {
+	var out strings.Builder
+	deps, home := doctorTestDeps(t, &out)
+
+	config := "theme=nope\ntheme_dark=neon-dusk\ntheme_light=ocean\nexport_interval_sec=soon\ntypo_key=1\n"
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".fprc"), []byte(config), 0600))
+
+	results := runDoctorChecks(deps)
+	r := findDoctorResult(t, results, "config")
+	require.Equal(t, doctorWarn, r.Status)
+	require.Contains(t, r.Detail, "unknown key 'typo_key'")
+	require.Contains(t, r.Detail, "unknown theme 'nope'")
+	require.Contains(t, r.Detail, "unknown theme 'neon-dusk' in theme_dark")
+	require.NotContains(t, r.Detail, "theme_light")
+	require.Contains(t, r.Detail, "export_interval_sec must be a non-negative number")
+	require.Equal(t, "fp config unset typo_key", r.Hint)
+}
+
+func TestDoctor_DatabaseFailureExitsNonZero(t *testing.T) {
+	var out strings.Builder
+	deps, _ := doctorTestDeps(t, &out)
+	deps.OpenStore = func(string) (*store.Store, error) { return nil, errors.New("disk I/O error") }
+
+	err := doctor(nil, dispatchers.NewParsedFlags(nil), deps)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "fp doctor found 1 problem")
+	require.Contains(t, out.String(), "disk I/O error")
+}
@@ -1,19 +1,22 @@
 package tracking
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"database/sql"
 	"encoding/csv"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/footprint-tools/cli/internal/config"
@@ -22,6 +25,7 @@ import (
 	"github.com/footprint-tools/cli/internal/log"
 	"github.com/footprint-tools/cli/internal/output"
 	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/usage"
 	"github.com/google/uuid"
 )
 
@@ -53,6 +57,11 @@ var csvHeader = []string{
 	"insertions",
 	"deletions",
 	"device",
+	"note",
+	"tag",
+	"push_remote",
+	"push_refs",
+	"source",
 }
 
 // Export handles the manual `fp export` command.
@@ -66,6 +75,21 @@ func export(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	openDir := flags.Has("--open")
 	jsonOutput := flags.Has("--json")
 
+	format, err := resolveExportFormat(flags.String("--format", ""))
+	if err != nil {
+		return err
+	}
+
+	sinks, err := resolveExportSinks(format, false)
+	if err != nil {
+		return err
+	}
+	// The stdout sink owns standard output, so status lines are dropped
+	toStdout := hasSink(sinks, "stdout")
+	if toStdout && jsonOutput {
+		return fmt.Errorf("--json can't be used with the stdout export sink")
+	}
+
 	exportRepo := getExportRepo()
 
 	// Handle --open flag
@@ -73,18 +97,46 @@ func export(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		return openInFileManager(exportRepo)
 	}
 
+	if flags.Has("--verify-remote") {
+		return exportVerifyRemote(deps.GetExportRepo(), jsonOutput, deps)
+	}
+
+	if flags.Has("--squash") {
+		return exportSquash(deps.GetExportRepo(), dryRun, jsonOutput, deps)
+	}
+
+	snapshot := flags.String("--sqlite", "")
+	if snapshot == "" && flags.Has("--sqlite") {
+		return errSnapshotPath
+	}
+	if snapshot == "" {
+		if err := checkSnapshotFlags(flags); err != nil {
+			return err
+		}
+	}
+
 	dbPath := deps.DBPath()
 	db, err := deps.OpenDB(dbPath)
 	if err != nil {
-		return fmt.Errorf("could not open database at %s: %w\nHint: Run 'fp setup' to initialize tracking in this repository", dbPath, err)
+		return fmt.Errorf("could not open database at %s: %w%s", dbPath, err, setupHint(err))
 	}
 	defer store.CloseDB(db)
 
 	_ = deps.InitDB(db)
 
-	events, err := store.GetPendingEvents(db)
+	// A snapshot is a copy for others to query, not a delivery: it leaves
+	// pending events pending
+	if snapshot != "" {
+		return exportSnapshot(db, snapshot, flags, deps)
+	}
+
+	events, err := selectPendingEvents(db, pendingSelection{
+		mine:    flags.Has("--mine"),
+		preview: dryRun || config.IsReadOnly(),
+		history: parseHistoryFilter(flags),
+	})
 	if err != nil {
-		return fmt.Errorf("could not get pending events: %w", err)
+		return err
 	}
 
 	if len(events) == 0 {
@@ -118,6 +170,10 @@ func export(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		return nil
 	}
 
+	if config.IsReadOnly() {
+		return usage.ReadOnly("fp export")
+	}
+
 	if !force {
 		if !shouldExport(deps) {
 			if jsonOutput {
@@ -128,17 +184,23 @@ func export(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		}
 	}
 
-	if !jsonOutput {
+	if !jsonOutput && !toStdout {
 		_, _ = deps.Printf("Processing %d events...\n", len(events))
 	}
 
-	count, pushed, err := doExportWork(db, events, deps)
+	outcome, err := doExportWork(db, events, sinks, deps)
 	if err != nil {
 		return err
 	}
+	count, pushed := outcome.Written, outcome.Pushed
+
+	destination := sinkDestinations(sinks)
 
+	if toStdout {
+		return nil
+	}
 	if jsonOutput {
-		return exportResultJSON(count, exportRepo, pushed, deps)
+		return exportResultJSON(count, destination, pushed, outcome.Remotes, deps)
 	}
 
 	if count == 0 {
@@ -146,10 +208,11 @@ func export(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		return nil
 	}
 
-	_, _ = deps.Printf("Exported %d events to %s\n", count, exportRepo)
-	if pushed {
-		_, _ = deps.Println("Pushed to remote")
+	_, _ = deps.Printf("Exported %d events to %s\n", count, destination)
+	if !hasSink(sinks, "git") {
+		return nil
 	}
+	printRemotePushes(outcome.Remotes, deps)
 	_, _ = deps.Println("View with: fp export --open")
 
 	return nil
@@ -189,75 +252,92 @@ func exportDryRunJSON(events []store.RepoEvent, deps Deps) error {
 	return output.JSON(deps.Println, result)
 }
 
-func exportResultJSON(count int, exportPath string, pushed bool, deps Deps) error {
+func exportResultJSON(count int, exportPath string, pushed bool, remotes []remotePush, deps Deps) error {
 	type exportResult struct {
-		EventsExported int    `json:"events_exported"`
-		ExportPath     string `json:"export_path"`
-		Pushed         bool   `json:"pushed"`
+		EventsExported int          `json:"events_exported"`
+		ExportPath     string       `json:"export_path"`
+		Pushed         bool         `json:"pushed"`
+		Remotes        []remotePush `json:"remotes,omitempty"`
 	}
 
 	result := exportResult{
 		EventsExported: count,
 		ExportPath:     exportPath,
 		Pushed:         pushed,
+		Remotes:        remotes,
 	}
 
 	return output.JSON(deps.Println, result)
 }
 
-// doExportWork performs the core export workflow: export events to CSV, commit, update DB.
-func doExportWork(db *sql.DB, events []store.RepoEvent, deps Deps) (int, bool, error) {
-	exportRepo := deps.GetExportRepo()
-
-	if err := ensureExportRepo(exportRepo); err != nil {
-		return 0, false, fmt.Errorf("could not initialize export repo: %w", err)
-	}
+// exportOutcome is what doExportWork did with a batch of events.
+type exportOutcome struct {
+	// Written counts events written by the sinks
+	Written int
+	// Pushed reports whether a sink sent events off this machine
+	Pushed bool
+	// Remotes has the outcome of each push of the export repo
+	Remotes []remotePush
+}
 
-	// Check for incomplete merge/rebase state before proceeding
-	if err := checkGitState(exportRepo); err != nil {
-		return 0, false, err
+// doExportWork sends events to every sink and marks events exported once all
+// sinks have accepted them. A failing sink doesn't stop the others: events
+// it didn't take stay pending and only it receives them on the next run.
+func doExportWork(db *sql.DB, events []store.RepoEvent, sinks []exportSink, deps Deps) (exportOutcome, error) {
+	var out exportOutcome
+	if len(sinks) == 0 {
+		return out, nil
 	}
 
-	// Sync with remote before writing (offline mode: continue if pull fails)
-	if deps.HasRemote(exportRepo) {
-		if err := deps.PullExportRepo(exportRepo); err != nil {
-			log.Warn("export: could not sync with remote, continuing offline: %v", err)
-		}
-	}
+	var errs []error
 
-	exportedIDs, exportedFiles, err := exportAllEvents(exportRepo, events, deps)
-	if err != nil {
-		return 0, false, fmt.Errorf("could not export events: %w", err)
-	}
+	// Sinks read commit metadata from the store, and store what they had
+	// to read from git, so the next export doesn't read it again
+	deps.metas = newCommitMetaReader(db)
+	deps.metas.fill = true
+	deps.metas.Prefetch(events)
+	deps.pushes = loadExportPushes(db, events)
 
-	if len(exportedFiles) == 0 {
-		return 0, false, nil
-	}
+	for _, sink := range sinks {
+		pending, err := undeliveredEvents(db, events, sink.Name())
+		if err != nil {
+			return exportOutcome{}, fmt.Errorf("could not read export deliveries: %w", err)
+		}
+		if len(pending) == 0 {
+			continue
+		}
 
-	if err := commitExportChanges(exportRepo, exportedFiles); err != nil {
-		return 0, false, fmt.Errorf("could not commit export: %w", err)
-	}
+		exportStep(deps, "Exporting %d %s to %s", len(pending), pluralize(len(pending), "event", "events"), sink.Name())
+		result, err := sink.Export(pending, deps)
+		out.Written = max(out.Written, result.Written)
+		out.Pushed = out.Pushed || result.Pushed
+		out.Remotes = append(out.Remotes, result.Remotes...)
 
-	// Try to push if remote exists
-	pushed := false
-	hasRemote := deps.HasRemote(exportRepo)
-	if hasRemote {
-		if err := deps.PushExportRepo(exportRepo); err != nil {
-			// Push failed - don't mark events as exported so they'll be retried
-			// Return count of locally exported events but pushed=false
-			log.Warn("export: failed to push to remote, events will remain pending: %v", err)
-			return len(exportedIDs), false, nil
+		if markErr := store.MarkDelivered(db, result.Delivered, sink.Name()); markErr != nil {
+			log.Error("export: failed to record %s deliveries, events will be retried: %v", sink.Name(), markErr)
+			errs = append(errs, fmt.Errorf("could not record %s deliveries: %w", sink.Name(), markErr))
+			continue
+		}
+		if err != nil {
+			log.Warn("export: %s sink failed, its events remain pending: %v", sink.Name(), err)
+			errs = append(errs, err)
 		}
-		pushed = true
 	}
 
-	// Only mark as exported after successful push (or if no remote)
+	// Only mark as exported once every sink has the event.
 	// Note: If this fails, events remain PENDING and will be retried.
-	// The CSV deduplication logic prevents duplicate entries on re-export,
+	// Each sink deduplicates (CSV by repo:commit, others by delivery records),
 	// making the system eventually consistent without requiring transactions.
-	if err := store.UpdateEventStatuses(db, exportedIDs, store.StatusExported); err != nil {
+	complete, err := fullyDeliveredIDs(db, events, sinks)
+	if err != nil {
+		return exportOutcome{}, fmt.Errorf("could not read export deliveries: %w", err)
+	}
+	if err := store.UpdateEventStatuses(db, complete, store.StatusExported); err != nil {
 		log.Error("export: failed to update event statuses, events will be retried: %v", err)
-		return 0, false, fmt.Errorf("could not update event statuses: %w", err)
+		return exportOutcome{}, fmt.Errorf("could not update event statuses: %w", err)
+	}
+	if err := store.ClearDeliveries(db, complete); err != nil {
+		log.Warn("export: failed to clear delivery records: %v", err)
 	}
 
 	// Clean up orphaned events (from untracked repos)
@@ -267,21 +347,162 @@ func doExportWork(db *sql.DB, events []store.RepoEvent, deps Deps) (int, bool, e
 		log.Info("export: deleted %d orphaned events", deleted)
 	}
 
+	if len(errs) > 0 {
+		return out, errors.Join(errs...)
+	}
+
 	_ = saveExportLast(deps.Now().Unix())
 
-	return len(exportedIDs), pushed, nil
+	return out, nil
+}
+
+// exportStep tells deps.progress, if set, what the export is doing now.
+func exportStep(deps Deps, format string, args ...any) {
+	if deps.progress != nil {
+		deps.progress(fmt.Sprintf(format, args...))
+	}
+}
+
+// undeliveredEvents filters out events the sink has already accepted.
+func undeliveredEvents(db *sql.DB, events []store.RepoEvent, sink string) ([]store.RepoEvent, error) {
+	delivered, err := store.DeliveredTo(db, eventIDs(events), sink)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]store.RepoEvent, 0, len(events))
+	for _, e := range events {
+		if !delivered[e.ID] {
+			pending = append(pending, e)
+		}
+	}
+	return pending, nil
+}
+
+// fullyDeliveredIDs returns the events every sink has accepted.
+func fullyDeliveredIDs(db *sql.DB, events []store.RepoEvent, sinks []exportSink) ([]int64, error) {
+	ids := eventIDs(events)
+	counts := make(map[int64]int, len(ids))
+	for _, sink := range sinks {
+		delivered, err := store.DeliveredTo(db, ids, sink.Name())
+		if err != nil {
+			return nil, err
+		}
+		for id := range delivered {
+			counts[id]++
+		}
+	}
+
+	var complete []int64
+	for _, id := range ids {
+		if counts[id] == len(sinks) {
+			complete = append(complete, id)
+		}
+	}
+	return complete, nil
+}
+
+func eventIDs(events []store.RepoEvent) []int64 {
+	ids := make([]int64, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+// skipEvents marks the events keep rejects as skipped, so they are never
+// exported, and returns the ones it accepts. why completes the log message.
+func skipEvents(db *sql.DB, events []store.RepoEvent, keep func(store.RepoEvent) bool, why string) []store.RepoEvent {
+	kept := make([]store.RepoEvent, 0, len(events))
+	var skipped []int64
+	for _, e := range events {
+		if keep(e) {
+			kept = append(kept, e)
+		} else {
+			skipped = append(skipped, e.ID)
+		}
+	}
+	if len(skipped) == 0 {
+		return events
+	}
+
+	if err := store.UpdateEventStatuses(db, skipped, store.StatusSkipped); err != nil {
+		log.Warn("export: could not mark %d events %s as skipped: %v", len(skipped), why, err)
+	} else {
+		log.Debug("export: skipped %d events %s", len(skipped), why)
+	}
+	return kept
+}
+
+// pendingSelection says which pending events an export takes.
+type pendingSelection struct {
+	// mine keeps only the user's own commits, as export_mine_only does
+	mine bool
+	// preview leaves the events it drops pending instead of marking them
+	// skipped, for --dry-run and read-only mode
+	preview bool
+	history historyFilter
+}
+
+// selectPendingEvents returns the pending events to export: the user's
+// own if asked, less those of excluded repositories and the history sel
+// drops. Unless previewing, dropped events are marked skipped so later
+// exports don't look at them again.
+func selectPendingEvents(db *sql.DB, sel pendingSelection) ([]store.RepoEvent, error) {
+	events, err := store.GetPendingEvents(db)
+	if err != nil {
+		return nil, fmt.Errorf("could not get pending events: %w", err)
+	}
+
+	if sel.mine || config.ExportMineOnly() {
+		identities := loadIdentityMatcher()
+		if identities == nil {
+			return nil, errNoIdentities
+		}
+		if sel.preview {
+			events = identities.Filter(events)
+		} else {
+			events = skipForeignEvents(db, events, identities)
+		}
+	}
+
+	redact := loadExportRedaction()
+	if sel.preview {
+		events = slices.DeleteFunc(events, func(e store.RepoEvent) bool { return redact.Excludes(e.RepoID) })
+		return sel.history.apply(events, newCommitMetaReader(db)), nil
+	}
+	events = skipExcludedRepos(db, events, redact)
+	return sel.history.skip(db, events), nil
+}
+
+// resolveAutoExportSinks returns the sinks of an export fp starts on its
+// own, in the configured format. The stdout sink is left out.
+func resolveAutoExportSinks() ([]exportSink, error) {
+	format, err := resolveExportFormat("")
+	if err != nil {
+		return nil, err
+	}
+	return resolveExportSinks(format, true)
 }
 
 // maybeExport checks if it's time to export and does so if needed.
 func maybeExport(db *sql.DB, deps Deps) {
+	if config.IsReadOnly() {
+		log.Debug("export: read-only mode, skipping auto-export")
+		return
+	}
 	if !shouldExport(deps) {
 		log.Debug("export: interval not reached, skipping auto-export")
 		return
 	}
 
-	events, err := store.GetPendingEvents(db)
+	events, err := selectPendingEvents(db, pendingSelection{})
+	if errors.Is(err, errNoIdentities) {
+		log.Warn("export: export_mine_only is set but identities is empty, skipping auto-export")
+		return
+	}
 	if err != nil {
-		log.Error("export: failed to get pending events: %v", err)
+		log.Error("export: %v", err)
 		return
 	}
 	if len(events) == 0 {
@@ -289,13 +510,20 @@ func maybeExport(db *sql.DB, deps Deps) {
 		return
 	}
 
+	sinks, err := resolveAutoExportSinks()
+	if err != nil {
+		log.Warn("export: %v, using git and %s", err, defaultExportFormat)
+		sinks = []exportSink{gitCSVSink{format: exportFormats[defaultExportFormat]}}
+	}
+
 	log.Debug("export: auto-exporting %d pending events", len(events))
 
-	count, _, err := doExportWork(db, events, deps)
+	outcome, err := doExportWork(db, events, sinks, deps)
 	if err != nil {
 		log.Error("export: %v", err)
 		return
 	}
+	count := outcome.Written
 
 	if count == 0 {
 		log.Debug("export: no files were exported")
@@ -305,8 +533,24 @@ func maybeExport(db *sql.DB, deps Deps) {
 	log.Info("export: auto-exported %d events", count)
 }
 
+// ExportIfDue exports pending events if the export interval has elapsed.
+// Used by the daemon, which takes over scheduling from the hooks while running.
+func ExportIfDue() error {
+	deps := DefaultDeps()
+
+	db, err := deps.OpenDB(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("could not open database: %w", err)
+	}
+	defer store.CloseDB(db)
+
+	maybeExport(db, deps)
+	return nil
+}
+
 // exportAllEvents exports all events to a flat CSV structure with year-based rotation.
 // Uses map-based deduplication: new records replace existing ones with same repo:commit.
+// Large files get the new rows appended instead, see appendCSVRecords.
 // Returns the IDs of exported events and the files that were modified.
 func exportAllEvents(exportRepo string, events []store.RepoEvent, deps Deps) ([]int64, []string, error) {
 	// Build a map of repo paths for metadata enrichment
@@ -328,35 +572,59 @@ func exportAllEvents(exportRepo string, events []store.RepoEvent, deps Deps) ([]
 
 	var exportedIDs []int64
 	var modifiedFiles []string
+	redact := loadExportRedaction()
+	columns := exportColumns()
+	now := deps.Now()
+	state := loadCompactionState(exportRepo)
+	defer state.save(exportRepo)
+	index := loadExportIndex(exportRepo)
+	defer index.save(exportRepo)
 
 	// Process each CSV file
 	for csvPath, fileEvents := range eventsByFile {
-		// Load existing records into map (repo:commit -> record)
-		records, err := loadCSVRecords(csvPath)
-		if err != nil {
-			return nil, nil, fmt.Errorf("could not load existing CSV %s: %w", csvPath, err)
-		}
+		relPath, _ := filepath.Rel(exportRepo, csvPath)
 
-		// Add/replace with new events
+		newRecords := make([][]string, 0, len(fileEvents))
 		for _, e := range fileEvents {
 			var meta git.CommitMetadata
 			if repoPath, ok := repoPaths[e.RepoID]; ok {
-				meta = git.GetCommitMetadata(repoPath, e.Commit)
+				meta = deps.metas.At(e.RepoID, repoPath, e.Commit)
 			}
+			newRecords = append(newRecords, buildRecord(e, meta, deps.pushes[e.ID], redact))
+			exportedIDs = append(exportedIDs, e.ID)
+		}
 
-			record := buildRecord(e, meta)
-			key := e.RepoID + ":" + e.Commit
-			records[key] = record
+		// Large files only get the new rows, until compaction is due
+		if !state.due(relPath, now) && csvAppendable(csvPath, columns) {
+			before, _ := stampOf(csvPath)
+			if err := appendCSVRecords(csvPath, newRecords, columns); err != nil {
+				index.rewritten(relPath)
+				return nil, nil, fmt.Errorf("could not append to %s: %w", csvPath, err)
+			}
+			state.appended(relPath, len(newRecords), now)
+			index.appended(exportRepo, relPath, before)
+			modifiedFiles = append(modifiedFiles, relPath)
+			continue
+		}
 
-			exportedIDs = append(exportedIDs, e.ID)
+		// Load existing records into map (repo:commit -> record)
+		records, err := loadCSVRecords(csvPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not load existing CSV %s: %w", csvPath, err)
+		}
+
+		// Add/replace with new events
+		for _, record := range newRecords {
+			records[recordKey(record, columns)] = record
 		}
 
 		// Write all records sorted by authored_at
 		if err := writeCSVSorted(csvPath, records); err != nil {
 			return nil, nil, fmt.Errorf("could not write %s: %w", csvPath, err)
 		}
+		delete(state, relPath)
+		index.rewritten(relPath)
 
-		relPath, _ := filepath.Rel(exportRepo, csvPath)
 		modifiedFiles = append(modifiedFiles, relPath)
 	}
 
@@ -375,86 +643,93 @@ func getCSVPath(exportRepo string, eventTime time.Time, currentYear int) string
 	return filepath.Join(exportRepo, csvName)
 }
 
-// findColumnIndices returns the indices of repo_id and commit_hash columns from the header.
-// Returns -1 for either if not found.
-func findColumnIndices(header []string) (repoIdx, commitIdx int) {
-	repoIdx, commitIdx = -1, -1
-	for i, col := range header {
-		switch col {
-		case "repo_id":
-			repoIdx = i
-		case "commit_hash":
-			commitIdx = i
-		}
-	}
-	return repoIdx, commitIdx
-}
-
-// getDefaultColumnIndices returns the indices of repo_id and commit_hash from the canonical csvHeader.
-// This ensures fallback indices are always in sync with the schema definition.
-func getDefaultColumnIndices() (repoIdx, commitIdx int) {
-	return findColumnIndices(csvHeader)
-}
-
 // loadCSVRecords loads existing CSV into a map keyed by repo:commit.
-// Returns an error if the file exists but cannot be parsed (to prevent data loss).
+// Returns an error if the file exists but cannot be parsed (to prevent data loss),
+// except for an export CSV cut off mid-row, see recoverTruncatedCSV.
 func loadCSVRecords(csvPath string) (map[string][]string, error) {
 	records := make(map[string][]string)
 
-	file, err := os.Open(csvPath)
+	data, err := os.ReadFile(csvPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return records, nil // File doesn't exist yet, return empty map
 		}
 		return nil, fmt.Errorf("open CSV: %w", err)
 	}
-	defer func() { _ = file.Close() }()
 
-	r := csv.NewReader(file)
-	lines, err := r.ReadAll()
+	lines, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
 	if err != nil {
-		return nil, fmt.Errorf("parse CSV: %w", err)
+		recovered, ok := recoverTruncatedCSV(csvPath, data)
+		if !ok {
+			return nil, fmt.Errorf("parse CSV: %w", err)
+		}
+		lines = recovered
 	}
 
 	if len(lines) == 0 {
 		return records, nil
 	}
 
-	// Parse header to find column indices
-	repoIdx, commitIdx := findColumnIndices(lines[0])
-	if repoIdx < 0 || commitIdx < 0 {
-		log.Warn("export: CSV header missing repo/commit columns, using default indices")
-		repoIdx, commitIdx = getDefaultColumnIndices()
+	addCSVRecords(lines, records, exportColumns())
+	return records, nil
+}
+
+// recoverTruncatedCSV reads back an export CSV whose last row was cut off,
+// as a crash or full disk can leave from older versions that wrote in
+// place. It only applies when the file doesn't end in a newline, the header
+// is an export header and everything up to the last newline parses; the
+// partial row is dropped and the original kept as <file>.truncated.
+func recoverTruncatedCSV(csvPath string, data []byte) ([][]string, bool) {
+	if len(data) == 0 || data[len(data)-1] == '\n' {
+		return nil, false
+	}
+	cut := bytes.LastIndexByte(data, '\n')
+	if cut < 0 {
+		return nil, false
+	}
+	lines, err := csv.NewReader(bytes.NewReader(data[:cut+1])).ReadAll()
+	if err != nil || len(lines) == 0 || !slices.Contains(lines[0], "commit_hash") {
+		return nil, false
 	}
 
-	// Parse records (skip header)
-	maxIdx := max(repoIdx, commitIdx)
+	backup := csvPath + ".truncated"
+	if err := os.WriteFile(backup, data, 0600); err != nil {
+		log.Warn("export: could not keep a copy of truncated %s: %v", csvPath, err)
+	}
+	log.Warn("export: %s ends in a partial row, dropped %d bytes (original kept as %s)",
+		filepath.Base(csvPath), len(data)-cut-1, filepath.Base(backup))
+	return lines, true
+}
+
+// addCSVRecords adds the rows of a parsed CSV (header first) to records,
+// read by column name so any export_columns ordering loads back, and
+// keyed by repo:commit. Later rows replace earlier ones with the same key.
+func addCSVRecords(lines [][]string, records map[string][]string, columns []string) {
+	header := lines[0]
+	named := slices.Contains(header, "commit_hash") || slices.Contains(header, "repo_id")
+	if !named {
+		log.Warn("export: CSV header missing repo/commit columns, using default column order")
+	}
+	minFields := slices.Index(csvHeader, "commit_hash") + 1
 
 	for i, line := range lines[1:] {
-		if len(line) <= maxIdx {
-			log.Warn("export: skipping malformed CSV line %d (expected at least %d columns)", i+2, maxIdx+1)
+		if !named && len(line) < minFields {
+			log.Warn("export: skipping malformed CSV line %d (expected at least %d columns)", i+2, minFields)
 			continue
 		}
-		key := line[repoIdx] + ":" + line[commitIdx]
-		records[key] = line
+		record := toCanonicalRecord(header, line)
+		if record[slices.Index(csvHeader, "commit_hash")] == "" {
+			log.Warn("export: skipping CSV line %d without a commit hash", i+2)
+			continue
+		}
+		records[recordKey(record, columns)] = record
 	}
-
-	return records, nil
 }
 
-// buildRecord creates a CSV record from an event and its metadata.
-func buildRecord(e store.RepoEvent, meta git.CommitMetadata) []string {
-	// Normalize message: replace newlines with spaces, remove carriage returns
-	message := strings.TrimSpace(strings.Map(func(r rune) rune {
-		switch r {
-		case '\n':
-			return ' '
-		case '\r':
-			return -1 // delete
-		default:
-			return r
-		}
-	}, meta.Subject))
+// buildRecord creates a CSV record from an event, its metadata and the refs
+// it pushed, if it is a push, with the redaction settings applied.
+func buildRecord(e store.RepoEvent, meta git.CommitMetadata, push []store.PushRef, redact exportRedaction) []string {
+	message := singleLine(meta.Subject)
 
 	// Use event timestamp as fallback if git metadata not available
 	timestamp := meta.AuthoredAt
@@ -477,6 +752,14 @@ func buildRecord(e store.RepoEvent, meta git.CommitMetadata) []string {
 	// Convert space-separated parents to comma-separated
 	parentHashes := strings.ReplaceAll(meta.ParentCommits, " ", ",")
 
+	// Events recorded before devices were stored came from this machine
+	device := e.Device
+	if device == "" {
+		device = deviceName()
+	}
+
+	pushRemote, pushed := pushColumns(push)
+
 	return []string{
 		generateEventID(),
 		eventType,
@@ -485,18 +768,38 @@ func buildRecord(e store.RepoEvent, meta git.CommitMetadata) []string {
 		repoName,
 		generateAuthorID(meta.AuthorEmail),
 		meta.AuthorName,
-		meta.AuthorEmail,
+		redact.email(meta.AuthorEmail),
 		e.Branch,
 		e.Commit,
 		parentHashes,
-		message,
+		redact.message(message),
 		strconv.Itoa(meta.FilesChanged),
 		strconv.Itoa(meta.Insertions),
 		strconv.Itoa(meta.Deletions),
-		getHostname(),
+		device,
+		redact.message(singleLine(e.Note)),
+		redact.message(e.Tag),
+		pushRemote,
+		pushed,
+		strings.ToLower(e.Source.String()),
 	}
 }
 
+// singleLine joins text onto one line for a CSV field: newlines become
+// spaces and carriage returns are removed.
+func singleLine(s string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		switch r {
+		case '\n':
+			return ' '
+		case '\r':
+			return -1 // delete
+		default:
+			return r
+		}
+	}, s))
+}
+
 // generateEventID creates a unique UUID for each event.
 func generateEventID() string {
 	return uuid.New().String()
@@ -511,12 +814,10 @@ func generateAuthorID(email string) string {
 	return hex.EncodeToString(hash[:8]) // 16 hex chars
 }
 
-// writeCSVSorted writes all records to CSV, sorted by timestamp (column 2).
-// Uses atomic write pattern: write to temp file, then rename to prevent data loss.
-func writeCSVSorted(csvPath string, records map[string][]string) error {
+// sortedRecords returns records ordered by timestamp (column 2).
+func sortedRecords(records map[string][]string) [][]string {
 	const timestampCol = 2 // Index of timestamp column in schema
 
-	// Collect and sort records by timestamp
 	lines := make([][]string, 0, len(records))
 	for _, record := range records {
 		lines = append(lines, record)
@@ -528,6 +829,14 @@ func writeCSVSorted(csvPath string, records map[string][]string) error {
 		}
 		return lines[i][timestampCol] < lines[j][timestampCol] // timestamp is RFC3339, sorts correctly
 	})
+	return lines
+}
+
+// writeCSVSorted writes all records to CSV, sorted by timestamp (column 2),
+// with the columns picked by export_columns. Records are in csvHeader order.
+// The file is replaced atomically, see writeFileAtomic.
+func writeCSVSorted(csvPath string, records map[string][]string) error {
+	lines := sortedRecords(records)
 
 	// Check available disk space before writing
 	// Estimate ~200 bytes per record (generous estimate for CSV row)
@@ -536,72 +845,29 @@ func writeCSVSorted(csvPath string, records map[string][]string) error {
 		return fmt.Errorf("insufficient disk space: %w", err)
 	}
 
-	// Write to a temporary file first (atomic write pattern)
-	tempPath := csvPath + ".tmp"
-	file, err := os.OpenFile(tempPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
-	if err != nil {
-		return err
-	}
-
-	w := csv.NewWriter(file)
-	if err := w.Write(csvHeader); err != nil {
-		_ = file.Close()
-		_ = os.Remove(tempPath)
-		return err
-	}
-	expectedFields := len(csvHeader)
-	for _, line := range lines {
-		if len(line) != expectedFields {
-			log.Warn("export: record has %d fields, expected %d, skipping", len(line), expectedFields)
-			continue
+	columns := exportColumns()
+	return writeFileAtomic(csvPath, func(out io.Writer) error {
+		w := csv.NewWriter(out)
+		if err := w.Write(columns); err != nil {
+			return err
 		}
-		if err := w.Write(line); err != nil {
-			_ = file.Close()
-			_ = os.Remove(tempPath)
+		w.Flush()
+		if err := w.Error(); err != nil {
 			return err
 		}
-	}
-	w.Flush()
-	if err := w.Error(); err != nil {
-		_ = file.Close()
-		_ = os.Remove(tempPath)
-		return err
-	}
-
-	// Sync to ensure data is written to disk
-	if err := file.Sync(); err != nil {
-		_ = file.Close()
-		_ = os.Remove(tempPath)
-		return fmt.Errorf("sync CSV file: %w", err)
-	}
-
-	// Close the temp file
-	if err := file.Close(); err != nil {
-		_ = os.Remove(tempPath)
-		return fmt.Errorf("close CSV file: %w", err)
-	}
-
-	// Atomic rename: replaces destination if it exists
-	if err := os.Rename(tempPath, csvPath); err != nil {
-		_ = os.Remove(tempPath)
-		return fmt.Errorf("rename CSV file: %w", err)
-	}
-
-	return nil
+		return writeCSVRows(out, lines, columns)
+	})
 }
 
 // checkDiskSpace verifies there's enough space to write the estimated bytes.
 func checkDiskSpace(dir string, requiredBytes int64) error {
-	var stat syscall.Statfs_t
-	if err := syscall.Statfs(dir, &stat); err != nil {
+	available, err := availableDiskSpace(dir)
+	if err != nil {
 		// If we can't check, proceed anyway (might be a virtual filesystem)
 		log.Debug("export: could not check disk space for %s: %v", dir, err)
 		return nil
 	}
 
-	// Available space = available blocks * block size
-	available := int64(stat.Bavail) * int64(stat.Bsize)
-
 	// Require at least 2x the estimated size for safety margin
 	if available < requiredBytes*2 {
 		return fmt.Errorf("need %d bytes, only %d available", requiredBytes*2, available)
@@ -610,6 +876,16 @@ func checkDiskSpace(dir string, requiredBytes int64) error {
 	return nil
 }
 
+// deviceName names this machine on the events it records: the
+// device_name config key, so machines can be told apart by something
+// friendlier than (or steadier than) their hostname, or the hostname.
+func deviceName() string {
+	if name, _ := config.Get("device_name"); strings.TrimSpace(name) != "" {
+		return strings.TrimSpace(name)
+	}
+	return getHostname()
+}
+
 // getHostname returns the machine hostname or empty string if unavailable.
 func getHostname() string {
 	hostname, err := os.Hostname()
@@ -619,18 +895,27 @@ func getHostname() string {
 	return hostname
 }
 
+// shouldExport reports whether the export interval has passed since the
+// last export.
 func shouldExport(deps Deps) bool {
+	interval, last := exportTiming()
+	return !deps.Now().Before(last.Add(interval))
+}
+
+// exportTiming reads export_interval_sec and export_last. Values that don't
+// parse count as 0, so a broken config exports rather than never does.
+func exportTiming() (interval time.Duration, last time.Time) {
 	intervalStr, _ := config.Get("export_interval_sec")
 	lastExportStr, _ := config.Get("export_last")
 
 	// Parse interval with default of 0 (always export if not configured)
-	interval := 0
+	seconds := 0
 	if intervalStr != "" {
 		var err error
-		interval, err = strconv.Atoi(intervalStr)
+		seconds, err = strconv.Atoi(intervalStr)
 		if err != nil {
 			log.Warn("export: invalid export_interval_sec config value '%s', using 0", intervalStr)
-			interval = 0
+			seconds = 0
 		}
 	}
 
@@ -645,8 +930,7 @@ func shouldExport(deps Deps) bool {
 		}
 	}
 
-	now := deps.Now().Unix()
-	return (now - lastExport) >= int64(interval)
+	return time.Duration(seconds) * time.Second, time.Unix(lastExport, 0)
 }
 
 func getExportRepo() string {
@@ -677,20 +961,20 @@ func checkGitState(exportRepo string) error {
 
 	// Check for merge in progress
 	if _, err := os.Stat(filepath.Join(gitDir, "MERGE_HEAD")); err == nil {
-		return fmt.Errorf("export repo has incomplete merge; resolve with: cd %s && git merge --abort (or complete the merge)", exportRepo)
+		return fmt.Errorf("export repo has incomplete merge; run 'fp export repair' (or: cd %s && git merge --abort)", exportRepo)
 	}
 
 	// Check for rebase in progress
 	rebaseDirs := []string{"rebase-merge", "rebase-apply"}
 	for _, dir := range rebaseDirs {
 		if _, err := os.Stat(filepath.Join(gitDir, dir)); err == nil {
-			return fmt.Errorf("export repo has incomplete rebase; resolve with: cd %s && git rebase --abort", exportRepo)
+			return fmt.Errorf("export repo has incomplete rebase; run 'fp export repair' (or: cd %s && git rebase --abort)", exportRepo)
 		}
 	}
 
 	// Check for cherry-pick in progress
 	if _, err := os.Stat(filepath.Join(gitDir, "CHERRY_PICK_HEAD")); err == nil {
-		return fmt.Errorf("export repo has incomplete cherry-pick; resolve with: cd %s && git cherry-pick --abort", exportRepo)
+		return fmt.Errorf("export repo has incomplete cherry-pick; run 'fp export repair' (or: cd %s && git cherry-pick --abort)", exportRepo)
 	}
 
 	return nil
@@ -916,6 +1200,25 @@ func resolveCSVConflicts(exportRepo string) error {
 		if file == "" {
 			continue
 		}
+		if file == exportReadmeName {
+			// Regenerated by the next export, so either side will do
+			if err := runGitInDir(exportRepo, "checkout", "--theirs", "--", file); err != nil {
+				return fmt.Errorf("could not resolve %s: %w", file, err)
+			}
+			if err := runGitInDir(exportRepo, "add", file); err != nil {
+				return fmt.Errorf("could not stage %s: %w", file, err)
+			}
+			continue
+		}
+		if isEncryptedExport(file) && strings.HasSuffix(strings.TrimSuffix(file, filepath.Ext(file)), ".csv") {
+			if err := resolveEncryptedCSVFile(exportRepo, file, exportEncryptionFromConfig()); err != nil {
+				return fmt.Errorf("could not resolve %s: %w", file, err)
+			}
+			if err := runGitInDir(exportRepo, "add", file); err != nil {
+				return fmt.Errorf("could not stage %s: %w", file, err)
+			}
+			continue
+		}
 		if !strings.HasSuffix(file, ".csv") {
 			return fmt.Errorf("non-CSV conflict in %s, manual resolution required", file)
 		}
@@ -988,48 +1291,11 @@ func parseCSVIntoMap(content string, records map[string][]string) {
 		return
 	}
 
-	// Parse header to find column indices
-	repoIdx, commitIdx := findColumnIndices(lines[0])
-	if repoIdx < 0 || commitIdx < 0 {
-		log.Warn("export: CSV header missing repo/commit columns, using default indices")
-		repoIdx, commitIdx = getDefaultColumnIndices()
-	}
-
-	maxIdx := max(repoIdx, commitIdx)
-
-	for _, line := range lines[1:] {
-		if len(line) <= maxIdx {
-			continue // skip malformed lines
-		}
-		key := line[repoIdx] + ":" + line[commitIdx]
-		records[key] = line
-	}
+	addCSVRecords(lines, records, exportColumns())
 }
 
-// pushExportRepo pushes the export repository to its remote with retry logic.
+// pushExportRepo pushes the export repository to its primary remote with
+// retry logic.
 func pushExportRepo(exportRepo string) error {
-	var lastErr error
-	backoff := initialBackoff
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		cmd := exec.Command("git", "push", "-u", "origin", "HEAD")
-		cmd.Dir = exportRepo
-		if err := cmd.Run(); err != nil {
-			lastErr = err
-			if attempt < maxRetries {
-				log.Debug("export: push attempt %d failed, retrying in %v: %v", attempt, backoff, err)
-				time.Sleep(backoff)
-				// Exponential backoff with cap
-				backoff *= 2
-				if backoff > maxBackoff {
-					backoff = maxBackoff
-				}
-				continue
-			}
-		} else {
-			return nil // Success
-		}
-	}
-
-	return fmt.Errorf("push failed after %d attempts: %w", maxRetries, lastErr)
+	return pushToRemote(exportRepo, primaryRemote)
 }
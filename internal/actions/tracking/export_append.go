@@ -0,0 +1,199 @@
+package tracking
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+const (
+	// csvCompactRows is how many rows can be appended to a CSV before the
+	// next export rewrites it sorted and deduplicated.
+	csvCompactRows = 5000
+
+	// csvCompactInterval is the longest appended rows wait for compaction.
+	csvCompactInterval = 7 * 24 * time.Hour
+
+	// compactionStateName holds the append counts, in the export repo's
+	// .git directory so it is never committed.
+	compactionStateName = "fp-compaction.json"
+)
+
+// csvAppendMinBytes is the size from which exports append to a CSV instead
+// of rewriting it. Smaller files are cheap to rewrite and stay sorted.
+var csvAppendMinBytes int64 = 4 << 20
+
+// csvAppends tracks the rows appended to one CSV since it was last
+// compacted.
+type csvAppends struct {
+	Rows  int   `json:"rows"`
+	Since int64 `json:"since"` // unix time of the first append
+}
+
+// compactionState maps CSV paths, relative to the export repo, to their
+// pending appends.
+type compactionState map[string]csvAppends
+
+func compactionStatePath(exportRepo string) string {
+	return filepath.Join(exportRepo, ".git", compactionStateName)
+}
+
+// loadCompactionState reads the append counts. A missing or unreadable
+// file means nothing is pending, which at worst delays a compaction.
+func loadCompactionState(exportRepo string) compactionState {
+	state := make(compactionState)
+	data, err := os.ReadFile(compactionStatePath(exportRepo))
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		log.Warn("export: ignoring unreadable %s: %v", compactionStateName, err)
+		return make(compactionState)
+	}
+	return state
+}
+
+func (s compactionState) save(exportRepo string) {
+	if len(s) == 0 {
+		_ = os.Remove(compactionStatePath(exportRepo))
+		return
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(compactionStatePath(exportRepo), data, 0600); err != nil {
+		log.Warn("export: could not save %s: %v", compactionStateName, err)
+	}
+}
+
+// due reports whether rel has waited long enough, or collected enough
+// appended rows, to be compacted.
+func (s compactionState) due(rel string, now time.Time) bool {
+	a, ok := s[rel]
+	if !ok || a.Rows == 0 {
+		return false
+	}
+	return a.Rows >= csvCompactRows || now.Sub(time.Unix(a.Since, 0)) >= csvCompactInterval
+}
+
+func (s compactionState) appended(rel string, rows int, now time.Time) {
+	a := s[rel]
+	if a.Rows == 0 {
+		a.Since = now.Unix()
+	}
+	a.Rows += rows
+	s[rel] = a
+}
+
+// csvAppendable reports whether new rows can be appended to csvPath: it is
+// large enough to be worth it, has the header export_columns asks for and
+// ends with a complete row.
+func csvAppendable(csvPath string, columns []string) bool {
+	file, err := os.Open(csvPath)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil || info.Size() < csvAppendMinBytes {
+		return false
+	}
+
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, info.Size()-1); err != nil || last[0] != '\n' {
+		return false
+	}
+
+	header, err := csv.NewReader(bufio.NewReader(file)).Read()
+	if err != nil {
+		return false
+	}
+	return slices.Equal(header, columns)
+}
+
+// appendCSVRecords appends records to csvPath, ordered by timestamp. The
+// file may then hold rows out of order, or several rows for one commit;
+// readers keep the last, and compaction rewrites the file with
+// writeCSVSorted.
+func appendCSVRecords(csvPath string, records [][]string, columns []string) error {
+	const timestampCol = 2
+	sort.SliceStable(records, func(i, j int) bool { return records[i][timestampCol] < records[j][timestampCol] })
+
+	if err := checkDiskSpace(filepath.Dir(csvPath), int64(len(records)*200)); err != nil {
+		return fmt.Errorf("insufficient disk space: %w", err)
+	}
+
+	file, err := os.OpenFile(csvPath, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if err := writeCSVRows(file, records, columns); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("sync CSV file: %w", err)
+	}
+	return file.Close()
+}
+
+// writeCSVRows writes records, in csvHeader order, with the columns picked
+// by export_columns.
+func writeCSVRows(out io.Writer, records [][]string, columns []string) error {
+	w := csv.NewWriter(out)
+	// Records from before a column was added are shorter; the missing
+	// columns are written empty.
+	expectedFields := len(csvHeader)
+	for _, record := range records {
+		if len(record) > expectedFields {
+			log.Warn("export: record has %d fields, expected at most %d, skipping", len(record), expectedFields)
+			continue
+		}
+		if err := w.Write(projectRecord(record, columns)); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// compactExportCSVs rewrites the CSVs in the export repo that have
+// appended rows, sorted and deduplicated. Returns the relative paths of the
+// files rewritten.
+func compactExportCSVs(exportRepo string) ([]string, error) {
+	state := loadCompactionState(exportRepo)
+	index := loadExportIndex(exportRepo)
+	defer index.save(exportRepo)
+	var compacted []string
+	for _, rel := range slices.Sorted(maps.Keys(state)) {
+		if state[rel].Rows == 0 {
+			continue
+		}
+		csvPath := filepath.Join(exportRepo, rel)
+		records, err := loadCSVRecords(csvPath)
+		if err != nil {
+			return compacted, fmt.Errorf("could not load %s: %w", rel, err)
+		}
+		if err := writeCSVSorted(csvPath, records); err != nil {
+			return compacted, fmt.Errorf("could not write %s: %w", rel, err)
+		}
+		delete(state, rel)
+		state.save(exportRepo)
+		index.rewritten(rel)
+		compacted = append(compacted, rel)
+	}
+	return compacted, nil
+}
@@ -0,0 +1,122 @@
+package tracking
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// appendExportDir returns an export repo where every CSV is appended to.
+func appendExportDir(t *testing.T) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	saved := csvAppendMinBytes
+	csvAppendMinBytes = 0
+	t.Cleanup(func() { csvAppendMinBytes = saved })
+
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0700))
+	return dir
+}
+
+func appendTestEvent(commit string, day int, note string) store.RepoEvent {
+	return store.RepoEvent{
+		RepoID: "github.com/user/repo", Commit: commit, Branch: "main", Note: note,
+		Timestamp: time.Date(2025, 3, day, 10, 0, 0, 0, time.UTC),
+		Source:    store.SourcePostCommit,
+	}
+}
+
+func csvLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestExportAllEvents_AppendsToLargeFiles(t *testing.T) {
+	dir := appendExportDir(t)
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	deps := Deps{Now: func() time.Time { return now }}
+	csvPath := filepath.Join(dir, "commits.csv")
+
+	_, _, err := exportAllEvents(dir, []store.RepoEvent{appendTestEvent("bbb", 2, ""), appendTestEvent("ccc", 3, "")}, deps)
+	require.NoError(t, err)
+	require.Len(t, csvLines(t, csvPath), 3)
+
+	// A new older commit and a note on an exported one only add rows
+	_, files, err := exportAllEvents(dir, []store.RepoEvent{appendTestEvent("aaa", 1, ""), appendTestEvent("ccc", 3, "reviewed")}, deps)
+	require.NoError(t, err)
+	require.Equal(t, []string{"commits.csv"}, files)
+	lines := csvLines(t, csvPath)
+	require.Len(t, lines, 5)
+	require.Contains(t, lines[3], "aaa")
+	require.Equal(t, csvAppends{Rows: 2, Since: now.Unix()}, loadCompactionState(dir)["commits.csv"])
+
+	// Readers keep the latest row for a commit
+	records, err := loadCSVRecords(csvPath)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Contains(t, records["github.com/user/repo:ccc"], "reviewed")
+
+	// Compaction sorts and deduplicates
+	compacted, err := compactExportCSVs(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"commits.csv"}, compacted)
+	lines = csvLines(t, csvPath)
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[1], "aaa")
+	require.Contains(t, lines[3], "reviewed")
+	require.Empty(t, loadCompactionState(dir))
+}
+
+func TestExportAllEvents_CompactsWhenDue(t *testing.T) {
+	dir := appendExportDir(t)
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	deps := Deps{Now: func() time.Time { return now }}
+	csvPath := filepath.Join(dir, "commits.csv")
+
+	_, _, err := exportAllEvents(dir, []store.RepoEvent{appendTestEvent("bbb", 2, "")}, deps)
+	require.NoError(t, err)
+	_, _, err = exportAllEvents(dir, []store.RepoEvent{appendTestEvent("aaa", 1, "")}, deps)
+	require.NoError(t, err)
+	require.Contains(t, csvLines(t, csvPath)[2], "aaa", "appended")
+
+	// A week after the first append, the next export rewrites the file
+	now = now.Add(csvCompactInterval)
+	_, _, err = exportAllEvents(dir, []store.RepoEvent{appendTestEvent("ccc", 3, "")}, deps)
+	require.NoError(t, err)
+	lines := csvLines(t, csvPath)
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[1], "aaa")
+	require.Contains(t, lines[3], "ccc")
+	require.Empty(t, loadCompactionState(dir))
+}
+
+func TestCSVAppendable(t *testing.T) {
+	dir := appendExportDir(t)
+	columns := exportColumns()
+	path := filepath.Join(dir, "commits.csv")
+
+	require.False(t, csvAppendable(path, columns), "missing file")
+
+	header := strings.Join(columns, ",") + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(header+"row\n"), 0600))
+	require.True(t, csvAppendable(path, columns))
+
+	require.NoError(t, os.WriteFile(path, []byte(header+"partial"), 0600))
+	require.False(t, csvAppendable(path, columns), "ends in a partial row")
+
+	require.NoError(t, os.WriteFile(path, []byte("timestamp,commit_hash\nrow\n"), 0600))
+	require.False(t, csvAppendable(path, columns), "other columns")
+
+	csvAppendMinBytes = 1 << 20
+	require.NoError(t, os.WriteFile(path, []byte(header+"row\n"), 0600))
+	require.False(t, csvAppendable(path, columns), "small files are rewritten")
+}
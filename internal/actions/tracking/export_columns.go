@@ -0,0 +1,95 @@
+package tracking
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// exportColumns returns the CSV columns to write, in order, from the
+// export_columns key. Unknown names are dropped with a warning; commit_hash
+// is always kept because rows are deduplicated by it. Without the key, every
+// column of csvHeader is written.
+func exportColumns() []string {
+	value, _ := config.Get("export_columns")
+	if strings.TrimSpace(value) == "" {
+		return csvHeader
+	}
+
+	var columns []string
+	for _, part := range strings.Split(value, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		switch {
+		case name == "":
+		case !slices.Contains(csvHeader, name):
+			log.Warn("export: unknown column '%s' in export_columns, ignoring it (valid columns are %s)", name, strings.Join(csvHeader, ", "))
+		case !slices.Contains(columns, name):
+			columns = append(columns, name)
+		}
+	}
+
+	if len(columns) == 0 {
+		log.Warn("export: export_columns has no valid columns, writing all of them")
+		return csvHeader
+	}
+	if !slices.Contains(columns, "commit_hash") {
+		log.Warn("export: export_columns must include commit_hash, adding it")
+		columns = append(columns, "commit_hash")
+	}
+	return columns
+}
+
+// toCanonicalRecord reorders a row read from a CSV with the given header into
+// csvHeader order, leaving columns the file doesn't have empty. Files whose
+// header names neither repo_id nor commit_hash predate named columns and are
+// taken to be in csvHeader order already.
+func toCanonicalRecord(header, row []string) []string {
+	if !slices.Contains(header, "commit_hash") && !slices.Contains(header, "repo_id") {
+		// Columns added since (such as note) are left empty
+		for len(row) < len(csvHeader) {
+			row = append(row, "")
+		}
+		return row
+	}
+
+	record := make([]string, len(csvHeader))
+	for i, col := range header {
+		if i >= len(row) {
+			break
+		}
+		if j := slices.Index(csvHeader, strings.TrimSpace(col)); j >= 0 {
+			record[j] = row[i]
+		}
+	}
+	return record
+}
+
+// projectRecord picks the given columns out of a record in csvHeader order.
+func projectRecord(record, columns []string) []string {
+	out := make([]string, len(columns))
+	for i, col := range columns {
+		if j := slices.Index(csvHeader, col); j >= 0 && j < len(record) {
+			out[i] = record[j]
+		}
+	}
+	return out
+}
+
+// recordKey returns the repo:commit key a record is deduplicated by. When
+// the written columns leave out repo_id, the repository name stands in for
+// it so rows read back from the file get the same key as new ones.
+func recordKey(record, columns []string) string {
+	repoCol := "repo_id"
+	if !slices.Contains(columns, repoCol) && slices.Contains(columns, "repo_name") {
+		repoCol = "repo_name"
+	}
+	field := func(col string) string {
+		if i := slices.Index(csvHeader, col); i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+	return field(repoCol) + ":" + field("commit_hash")
+}
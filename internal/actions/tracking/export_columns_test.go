@@ -0,0 +1,66 @@
+package tracking
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportColumns(t *testing.T) {
+	setRedactionConfig(t)
+	require.Equal(t, csvHeader, exportColumns())
+
+	setRedactionConfig(t, "export_columns", "Timestamp, repo_name,bogus,commit_hash,message,timestamp")
+	require.Equal(t, []string{"timestamp", "repo_name", "commit_hash", "message"}, exportColumns())
+
+	setRedactionConfig(t, "export_columns", "timestamp,message")
+	require.Equal(t, []string{"timestamp", "message", "commit_hash"}, exportColumns(), "commit_hash is always kept")
+
+	setRedactionConfig(t, "export_columns", "nope")
+	require.Equal(t, csvHeader, exportColumns())
+}
+
+func TestWriteCSVSorted_CustomColumnsRoundTrip(t *testing.T) {
+	setRedactionConfig(t, "export_columns", "timestamp,repo_name,commit_hash,message")
+	path := filepath.Join(t.TempDir(), "commits.csv")
+
+	first := repairRecord("aaa")
+	require.NoError(t, writeCSVSorted(path, map[string][]string{recordKey(first, exportColumns()): first}))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Equal(t, []string{"timestamp,repo_name,commit_hash,message", "2025-01-01T00:00:00Z,repo,aaa,msg"}, lines)
+
+	// Reading back by header name lines the row up with csvHeader again
+	records, err := loadCSVRecords(path)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	record := records["repo:aaa"]
+	require.Equal(t, "aaa", record[colCommitHash])
+	require.Equal(t, "msg", record[colMessage])
+	require.Empty(t, record[colBranch], "columns left out of the file stay empty")
+
+	// A new export of the same commit replaces the row instead of adding one
+	updated := repairRecord("aaa")
+	updated[colMessage] = "new"
+	records[recordKey(updated, exportColumns())] = updated
+	require.Len(t, records, 1)
+}
+
+func TestLoadCSVRecords_AnyColumnOrder(t *testing.T) {
+	setRedactionConfig(t)
+	path := filepath.Join(t.TempDir(), "commits.csv")
+	content := "commit_hash,branch,repo_id,timestamp\nabc123,main,github.com/user/repo,2024-01-15T10:30:00Z\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	records, err := loadCSVRecords(path)
+	require.NoError(t, err)
+	record := records["github.com/user/repo:abc123"]
+	require.Len(t, record, len(csvHeader))
+	require.Equal(t, "main", record[colBranch])
+	require.Equal(t, "2024-01-15T10:30:00Z", record[colTimestamp])
+}
@@ -0,0 +1,284 @@
+package tracking
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// encryptedExportExts are the suffixes of encrypted export files:
+// commits.csv.age or commits.csv.gpg.
+var encryptedExportExts = []string{".age", ".gpg"}
+
+// exportEncryption encrypts the files of the export repository before they
+// are committed, so a shared remote only sees ciphertext. The plaintext
+// files stay in the local repository, ignored by git, because every export
+// merges into them.
+type exportEncryption struct {
+	// Recipient is an age recipient (age1..., or an SSH public key) or
+	// anything gpg accepts for --recipient.
+	Recipient string
+	// Identity is the age identity file used to decrypt; gpg decrypts
+	// with the keys in its keyring.
+	Identity string
+}
+
+// exportEncryptionFromConfig returns the configured encryption, or nil when
+// export_encrypt_recipient is not set.
+func exportEncryptionFromConfig() *exportEncryption {
+	recipient, _ := config.Get("export_encrypt_recipient")
+	recipient = strings.TrimSpace(recipient)
+	if recipient == "" {
+		return nil
+	}
+	identity, _ := config.Get("export_encrypt_identity")
+	return &exportEncryption{Recipient: recipient, Identity: strings.TrimSpace(identity)}
+}
+
+func (e exportEncryption) usesAge() bool {
+	return strings.HasPrefix(e.Recipient, "age1") || strings.HasPrefix(e.Recipient, "ssh-")
+}
+
+// Extension is the suffix added to encrypted files.
+func (e exportEncryption) Extension() string {
+	if e.usesAge() {
+		return ".age"
+	}
+	return ".gpg"
+}
+
+// encryptFile writes rel (relative to exportRepo) encrypted next to it and
+// returns the relative path of the encrypted file.
+func (e exportEncryption) encryptFile(exportRepo, rel string) (string, error) {
+	plain, err := os.Open(filepath.Join(exportRepo, rel))
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = plain.Close() }()
+
+	var cmd *exec.Cmd
+	if e.usesAge() {
+		cmd = exec.Command("age", "--encrypt", "--recipient", e.Recipient)
+	} else {
+		cmd = exec.Command("gpg", "--batch", "--yes", "--quiet", "--trust-model", "always",
+			"--encrypt", "--recipient", e.Recipient)
+	}
+
+	encRel := rel + e.Extension()
+	err = writeFileAtomic(filepath.Join(exportRepo, encRel), func(w io.Writer) error {
+		return runCrypto(cmd, plain, w)
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not encrypt %s: %w", rel, err)
+	}
+	return encRel, nil
+}
+
+// encryptExportFiles encrypts the written files and returns what to commit
+// instead: the encrypted files and the .gitignore that keeps the plaintext
+// out.
+func encryptExportFiles(exportRepo string, files []string, enc *exportEncryption) ([]string, error) {
+	gitignore, err := ignorePlaintextExports(exportRepo)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := []string{gitignore}
+	for _, rel := range files {
+		encRel, err := enc.encryptFile(exportRepo, rel)
+		if err != nil {
+			return nil, err
+		}
+		encrypted = append(encrypted, encRel)
+	}
+	return encrypted, nil
+}
+
+// decryptExport decrypts the contents of an encrypted export file, picking
+// age or gpg by the file's extension. identity is the age identity file.
+func decryptExport(name string, data []byte, identity string) ([]byte, error) {
+	var cmd *exec.Cmd
+	switch filepath.Ext(name) {
+	case ".age":
+		if identity == "" {
+			return nil, fmt.Errorf("%s is encrypted with age and export_encrypt_identity is not set\nHint: fp config set export_encrypt_identity ~/.config/age/keys.txt", filepath.Base(name))
+		}
+		cmd = exec.Command("age", "--decrypt", "--identity", expandHome(identity))
+	case ".gpg":
+		cmd = exec.Command("gpg", "--batch", "--quiet", "--decrypt")
+	default:
+		return nil, fmt.Errorf("%s is not an encrypted export", filepath.Base(name))
+	}
+
+	var out bytes.Buffer
+	if err := runCrypto(cmd, bytes.NewReader(data), &out); err != nil {
+		return nil, fmt.Errorf("could not decrypt %s: %w", filepath.Base(name), err)
+	}
+	return out.Bytes(), nil
+}
+
+// decryptExportFile reads and decrypts an encrypted export file.
+func decryptExportFile(path, identity string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decryptExport(path, data, identity)
+}
+
+// runCrypto runs age or gpg as a filter from in to out, turning a missing
+// binary and its stderr into readable errors.
+func runCrypto(cmd *exec.Cmd, in io.Reader, out io.Writer) error {
+	var stderr bytes.Buffer
+	cmd.Stdin = in
+	cmd.Stdout = out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return fmt.Errorf("%s is not installed or not in PATH", filepath.Base(cmd.Path))
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %s", filepath.Base(cmd.Path), msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// isEncryptedExport reports whether name is an encrypted export file.
+func isEncryptedExport(name string) bool {
+	for _, ext := range encryptedExportExts {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// exportGitignore keeps plaintext exports out of git while encryption is on.
+const exportGitignore = `# Written by fp: export_encrypt_recipient is set, so only the encrypted
+# files are committed.
+*.csv
+*.jsonl
+*.parquet
+*.ics
+*.truncated
+README.md
+`
+
+// ignorePlaintextExports writes the export repository's .gitignore and
+// stops tracking plaintext exports committed before encryption was turned
+// on (they stay in the history). The README is kept local too, as it names
+// devices. Returns the .gitignore path to commit.
+func ignorePlaintextExports(exportRepo string) (string, error) {
+	const name = ".gitignore"
+	path := filepath.Join(exportRepo, name)
+	if current, err := os.ReadFile(path); err != nil || string(current) != exportGitignore {
+		if err := os.WriteFile(path, []byte(exportGitignore), 0644); err != nil {
+			return "", err
+		}
+	}
+	if err := runGitInDir(exportRepo, "rm", "--cached", "--quiet", "--ignore-unmatch", "--",
+		"*.csv", "*.jsonl", "*.parquet", "*.ics", exportReadmeName); err != nil {
+		return "", fmt.Errorf("could not untrack plaintext exports: %w", err)
+	}
+	return name, nil
+}
+
+// syncEncryptedExports merges encrypted CSVs, such as those pulled from
+// another machine, into their local plaintext copies so the next write
+// keeps their records. A file that can't be decrypted stops the export:
+// writing without its records would drop them from the remote.
+func syncEncryptedExports(exportRepo string, enc *exportEncryption) error {
+	index := loadExportIndex(exportRepo)
+	for _, ext := range encryptedExportExts {
+		paths, err := filepath.Glob(filepath.Join(exportRepo, "commits*.csv"+ext))
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			// Neither copy changed since this machine encrypted it: nothing
+			// to merge
+			entry := index[strings.TrimSuffix(filepath.Base(path), ext)]
+			encStamp, _ := stampOf(path)
+			plainStamp, plainFound := stampOf(strings.TrimSuffix(path, ext))
+			if entry.Encrypted != nil && *entry.Encrypted == encStamp && plainFound && entry.Appended == nil && entry.Stamp == plainStamp {
+				continue
+			}
+			data, err := decryptExportFile(path, enc.Identity)
+			if err != nil {
+				return err
+			}
+			csvPath := strings.TrimSuffix(path, ext)
+			records, err := loadCSVRecords(csvPath)
+			if err != nil {
+				return fmt.Errorf("could not load %s: %w", filepath.Base(csvPath), err)
+			}
+			before := len(records)
+			remote := make(map[string][]string)
+			parseCSVIntoMap(string(data), remote)
+			for key, record := range remote {
+				if _, ok := records[key]; !ok {
+					records[key] = record
+				}
+			}
+			if len(records) == before {
+				continue
+			}
+			log.Debug("export: merged %d records from %s", len(records)-before, filepath.Base(path))
+			if err := writeCSVSorted(csvPath, records); err != nil {
+				return fmt.Errorf("could not write %s: %w", filepath.Base(csvPath), err)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveEncryptedCSVFile resolves a conflicted encrypted CSV like
+// resolveCSVFile: both sides are decrypted and combined, and the result is
+// written as plaintext and encrypted again.
+func resolveEncryptedCSVFile(exportRepo, file string, enc *exportEncryption) error {
+	ext := filepath.Ext(file)
+	if enc == nil || enc.Extension() != ext {
+		return fmt.Errorf("%s can't be re-encrypted with export_encrypt_recipient, manual resolution required", file)
+	}
+	records := make(map[string][]string)
+	for _, stage := range []string{":2:", ":3:"} {
+		showCmd := exec.Command("git", "show", stage+file)
+		showCmd.Dir = exportRepo
+		data, err := showCmd.Output()
+		if err != nil {
+			log.Warn("export: could not get stage %s of %s during conflict resolution: %v", stage, file, err)
+			continue
+		}
+		plain, err := decryptExport(file, data, enc.Identity)
+		if err != nil {
+			return err
+		}
+		parseCSVIntoMap(string(plain), records)
+	}
+
+	csvRel := strings.TrimSuffix(file, ext)
+	if err := writeCSVSorted(filepath.Join(exportRepo, csvRel), records); err != nil {
+		return err
+	}
+	_, err := enc.encryptFile(exportRepo, csvRel)
+	return err
+}
+
+// expandHome expands a leading ~/ to the home directory.
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
@@ -0,0 +1,141 @@
+package tracking
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// fakeAge puts an age stand-in on PATH: base64 instead of encryption, and
+// decryption fails without an existing identity file.
+func fakeAge(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	script := `#!/bin/sh
+case "$1" in
+--decrypt)
+	[ -f "$3" ] || { echo "identity $3 not found" >&2; exit 1; }
+	base64 -d ;;
+*)
+	base64 ;;
+esac
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "age"), []byte(script), 0755))
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// encryptedExportSetup configures encryption with the fake age and returns
+// the export repo.
+func encryptedExportSetup(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, k := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(k, "fp")
+	}
+	for _, k := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(k, "fp@example.com")
+	}
+	fakeAge(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(home, "key.txt"), []byte("AGE-SECRET-KEY-1TEST\n"), 0600))
+	var lines []string
+	lines, _ = config.Set(lines, "export_encrypt_recipient", "age1test")
+	lines, _ = config.Set(lines, "export_encrypt_identity", "~/key.txt")
+	require.NoError(t, config.WriteLines(lines))
+
+	return filepath.Join(t.TempDir(), "exports")
+}
+
+func exportEncryptedEvent(t *testing.T, exportDir, commit string) {
+	t.Helper()
+	db, err := openDBFresh(filepath.Join(t.TempDir(), "store.db"))
+	require.NoError(t, err)
+	defer store.CloseDB(db)
+	require.NoError(t, store.InsertEvent(db, store.RepoEvent{
+		RepoID: "github.com/user/repo", RepoPath: "/nonexistent/repo", Commit: commit, Branch: "main",
+		Timestamp: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC),
+		Status:    store.StatusPending, Source: store.SourcePostCommit,
+	}))
+	events, err := store.GetPendingEvents(db)
+	require.NoError(t, err)
+
+	deps := Deps{
+		Now:           func() time.Time { return time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC) },
+		GetExportRepo: func() string { return exportDir },
+		HasRemote:     func(string) bool { return false },
+	}
+	outcome, err := doExportWork(db, events, []exportSink{gitCSVSink{format: csvExportFormat{}}}, deps)
+	count := outcome.Written
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestExportEncryption_Extension(t *testing.T) {
+	require.Equal(t, ".age", exportEncryption{Recipient: "age1qyqszqgpqyqszqgp"}.Extension())
+	require.Equal(t, ".age", exportEncryption{Recipient: "ssh-ed25519 AAAAC3Nza"}.Extension())
+	require.Equal(t, ".gpg", exportEncryption{Recipient: "me@example.com"}.Extension())
+}
+
+func TestGitCSVSink_Encrypted(t *testing.T) {
+	exportDir := encryptedExportSetup(t)
+
+	exportEncryptedEvent(t, exportDir, "abc123")
+
+	encrypted, err := os.ReadFile(filepath.Join(exportDir, "commits.csv.age"))
+	require.NoError(t, err)
+	require.NotContains(t, string(encrypted), "github.com/user/repo")
+
+	out, err := exec.Command("git", "-C", exportDir, "ls-files").Output()
+	require.NoError(t, err)
+	require.Equal(t, []string{".gitignore", "commits.csv.age"}, strings.Fields(string(out)))
+
+	// Without the plaintext, as in a fresh clone, the next export merges
+	// the encrypted records back in
+	require.NoError(t, os.Remove(filepath.Join(exportDir, "commits.csv")))
+	exportEncryptedEvent(t, exportDir, "def456")
+
+	plain, err := decryptExportFile(filepath.Join(exportDir, "commits.csv.age"), "~/key.txt")
+	require.NoError(t, err)
+	records := make(map[string][]string)
+	parseCSVIntoMap(string(plain), records)
+	require.Len(t, records, 2)
+	require.Contains(t, records, "github.com/user/repo:abc123")
+	require.Contains(t, records, "github.com/user/repo:def456")
+}
+
+func TestImport_EncryptedExport(t *testing.T) {
+	exportDir := encryptedExportSetup(t)
+	exportEncryptedEvent(t, exportDir, "abc123")
+
+	// The plaintext is preferred while it is there
+	files, err := importFiles(exportDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(exportDir, "commits.csv")}, files)
+
+	require.NoError(t, os.Remove(filepath.Join(exportDir, "commits.csv")))
+	files, err = importFiles(exportDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(exportDir, "commits.csv.age")}, files)
+
+	events, invalid, err := readImportFile(files[0])
+	require.NoError(t, err)
+	require.Zero(t, invalid)
+	require.Len(t, events, 1)
+	require.Equal(t, "abc123", events[0].Commit)
+
+	// Decrypting needs the identity
+	var lines []string
+	lines, _ = config.Set(lines, "export_encrypt_recipient", "age1test")
+	require.NoError(t, config.WriteLines(lines))
+	_, _, err = readImportFile(files[0])
+	require.ErrorContains(t, err, "export_encrypt_identity is not set")
+}
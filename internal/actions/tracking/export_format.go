@@ -0,0 +1,316 @@
+package tracking
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/parquet"
+)
+
+const defaultExportFormat = "csv"
+
+// exportFormat writes export records in a specific file format.
+// CSV is always written since it is the format used for merging and
+// conflict resolution; other formats are derived from it after each export.
+type exportFormat interface {
+	Name() string
+	Extension() string
+	Write(w io.Writer, header []string, rows [][]string) error
+}
+
+var exportFormats = map[string]exportFormat{
+	"csv":       csvExportFormat{},
+	"jsonl":     jsonlExportFormat{},
+	"parquet":   parquetExportFormat{},
+	"ics":       icsExportFormat{},
+	"ics-daily": icsExportFormat{daily: true},
+}
+
+// numericColumns are written as numbers by formats that support typed values.
+var numericColumns = map[string]bool{
+	"files_changed": true,
+	"insertions":    true,
+	"deletions":     true,
+}
+
+// exportFormatNames returns the supported format names, sorted.
+func exportFormatNames() []string {
+	names := make([]string, 0, len(exportFormats))
+	for name := range exportFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveExportFormat returns the format selected by the --format flag value,
+// falling back to the export_format config key.
+func resolveExportFormat(flagValue string) (exportFormat, error) {
+	name := strings.ToLower(strings.TrimSpace(flagValue))
+	if name == "" {
+		name, _ = config.Get("export_format")
+		name = strings.ToLower(strings.TrimSpace(name))
+	}
+	if name == "" {
+		name = defaultExportFormat
+	}
+
+	f, ok := exportFormats[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown export format %q (valid: %s)", name, strings.Join(exportFormatNames(), ", "))
+	}
+	return f, nil
+}
+
+// appendableExportFormat is a format whose files can take more rows at
+// the end, as the CSV can: readers keep the last row for each commit.
+type appendableExportFormat interface {
+	exportFormat
+	Append(w io.Writer, header []string, rows [][]string) error
+}
+
+// writeDerivedExports brings the given CSV files (relative to exportRepo)
+// up to date in the selected format. Rows appended to a CSV are appended to
+// the derived file too when the format allows it; otherwise the file is
+// written from the deduplicated CSV records. Returns the relative paths of
+// the files written.
+func writeDerivedExports(exportRepo string, csvFiles []string, f exportFormat, index exportIndex) ([]string, error) {
+	if f.Name() == defaultExportFormat {
+		return nil, nil
+	}
+
+	var written []string
+	for _, rel := range csvFiles {
+		changes, err := index.refresh(exportRepo, rel)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", rel, err)
+		}
+
+		outRel := strings.TrimSuffix(rel, ".csv") + f.Extension()
+		outPath := filepath.Join(exportRepo, outRel)
+		entry := index[rel]
+		current, _ := stampOf(outPath)
+		upToDate := entry.Derived != nil && entry.Derived.Ext == f.Extension() && entry.Derived.Stamp == current
+
+		appendable, canAppend := f.(appendableExportFormat)
+		switch {
+		case upToDate && changes.appended == nil && changes.records == nil:
+			continue
+		case upToDate && changes.appended != nil && canAppend:
+			if err := appendDerivedExport(outPath, appendable, changes.header, changes.appended); err != nil {
+				return nil, fmt.Errorf("could not append to %s: %w", outRel, err)
+			}
+			log.Debug("export: appended %d records to %s", len(changes.appended), outRel)
+		default:
+			records := changes.records
+			if records == nil {
+				if records, err = loadCSVRecords(filepath.Join(exportRepo, rel)); err != nil {
+					return nil, fmt.Errorf("could not read %s: %w", rel, err)
+				}
+			}
+			columns := exportColumns()
+			rows := sortedRecords(records)
+			for i, record := range rows {
+				rows[i] = projectRecord(record, columns)
+			}
+			err = writeFileAtomic(outPath, func(w io.Writer) error {
+				return f.Write(w, columns, rows)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("could not write %s: %w", outRel, err)
+			}
+			log.Debug("export: wrote %s (%d records)", outRel, len(rows))
+		}
+
+		stamp, _ := stampOf(outPath)
+		entry.Derived = &derivedStamp{Ext: f.Extension(), Stamp: stamp}
+		index[rel] = entry
+		written = append(written, outRel)
+	}
+	return written, nil
+}
+
+func appendDerivedExport(path string, f appendableExportFormat, header []string, rows [][]string) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if err := f.Append(file, header, rows); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// readCSVRows reads a CSV file and returns its header and data rows.
+func readCSVRows(path string) ([]string, [][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	lines, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(lines) == 0 {
+		return exportColumns(), nil, nil
+	}
+	return lines[0], lines[1:], nil
+}
+
+// writeFileAtomic writes a file via a synced temp file in the same
+// directory and a rename, so a crash leaves either the old file or the new
+// one, never a truncated mix.
+func writeFileAtomic(path string, write func(io.Writer) error) error {
+	dir := filepath.Dir(path)
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tempPath := file.Name()
+
+	if err := write(file); err != nil {
+		_ = file.Close()
+		_ = os.Remove(tempPath)
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("sync %s: %w", filepath.Base(path), err)
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// syncDir flushes a directory entry so a rename into it survives a crash.
+// Failures are only logged: some filesystems don't support it.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		log.Debug("export: could not open %s to sync: %v", dir, err)
+		return
+	}
+	defer func() { _ = d.Close() }()
+	if err := d.Sync(); err != nil {
+		log.Debug("export: could not sync %s: %v", dir, err)
+	}
+}
+
+type csvExportFormat struct{}
+
+func (csvExportFormat) Name() string      { return "csv" }
+func (csvExportFormat) Extension() string { return ".csv" }
+
+func (csvExportFormat) Write(w io.Writer, header []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// jsonlExportFormat writes one JSON object per line, keeping column order.
+type jsonlExportFormat struct{}
+
+func (jsonlExportFormat) Name() string      { return "jsonl" }
+func (jsonlExportFormat) Extension() string { return ".jsonl" }
+
+// Append adds rows to a JSONL file; lines have no header to repeat.
+func (f jsonlExportFormat) Append(w io.Writer, header []string, rows [][]string) error {
+	return f.Write(w, header, rows)
+}
+
+func (jsonlExportFormat) Write(w io.Writer, header []string, rows [][]string) error {
+	var buf []byte
+	for _, row := range rows {
+		buf = appendJSONRecord(buf[:0], header, row)
+		buf = append(buf, '\n')
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendJSONRecord appends row as a JSON object keyed by header, in column
+// order. Numeric columns are written as numbers.
+func appendJSONRecord(buf []byte, header []string, row []string) []byte {
+	b := bytes.NewBuffer(buf)
+	b.WriteByte('{')
+	for i, col := range header {
+		if i >= len(row) {
+			break
+		}
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(col)
+		b.Write(key)
+		b.WriteByte(':')
+
+		if n, err := strconv.ParseInt(row[i], 10, 64); err == nil && numericColumns[col] {
+			b.WriteString(strconv.FormatInt(n, 10))
+			continue
+		}
+		value, _ := json.Marshal(row[i])
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes()
+}
+
+// parquetExportFormat writes a single row group parquet file.
+type parquetExportFormat struct{}
+
+func (parquetExportFormat) Name() string      { return "parquet" }
+func (parquetExportFormat) Extension() string { return ".parquet" }
+
+func (parquetExportFormat) Write(w io.Writer, header []string, rows [][]string) error {
+	columns := make([]parquet.Column, len(header))
+	for i, col := range header {
+		columns[i] = parquet.Column{Name: col, Type: parquet.String}
+		if numericColumns[col] {
+			columns[i].Type = parquet.Int64
+		}
+	}
+
+	// Skip malformed rows rather than failing the whole file
+	valid := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		if len(row) != len(header) {
+			log.Warn("export: record has %d fields, expected %d, skipping", len(row), len(header))
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	return parquet.Write(w, columns, valid)
+}
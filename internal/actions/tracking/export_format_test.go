@@ -0,0 +1,86 @@
+package tracking
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveExportFormat(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	f, err := resolveExportFormat("")
+	require.NoError(t, err)
+	require.Equal(t, "csv", f.Name())
+
+	f, err = resolveExportFormat("JSONL")
+	require.NoError(t, err)
+	require.Equal(t, "jsonl", f.Name())
+
+	_, err = resolveExportFormat("xml")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "csv, ics, ics-daily, jsonl, parquet")
+}
+
+func TestWriteDerivedExports_CSVIsNoop(t *testing.T) {
+	files, err := writeDerivedExports(t.TempDir(), []string{"commits.csv"}, csvExportFormat{}, make(exportIndex))
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestWriteDerivedExports_JSONL(t *testing.T) {
+	dir := t.TempDir()
+	exportDir := filepath.Join(dir, "export")
+	require.NoError(t, ensureExportRepo(exportDir))
+
+	events := []store.RepoEvent{
+		{ID: 1, RepoID: "github.com/user/repo", Commit: "abc123", Branch: "main",
+			Timestamp: time.Date(2025, 6, 15, 10, 0, 0, 0, time.UTC), Source: store.SourcePostCommit},
+	}
+	deps := Deps{Now: func() time.Time { return time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC) }}
+
+	_, csvFiles, err := exportAllEvents(exportDir, events, deps)
+	require.NoError(t, err)
+
+	files, err := writeDerivedExports(exportDir, csvFiles, jsonlExportFormat{}, make(exportIndex))
+	require.NoError(t, err)
+	require.Equal(t, []string{"commits.jsonl"}, files)
+
+	file, err := os.Open(filepath.Join(exportDir, "commits.jsonl"))
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	require.True(t, scanner.Scan())
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+	require.Equal(t, "abc123", record["commit_hash"])
+	require.Equal(t, "github.com/user/repo", record["repo_id"])
+	require.Equal(t, float64(0), record["insertions"])
+	require.False(t, scanner.Scan())
+}
+
+func TestWriteDerivedExports_Parquet(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "commits-2024.csv")
+	records := map[string][]string{
+		"repo:abc": {"id1", "commit", "2024-01-01T00:00:00Z", "repo", "repo", "", "", "", "main", "abc", "", "msg", "2", "10", "3", "host"},
+	}
+	require.NoError(t, writeCSVSorted(csvPath, records))
+
+	files, err := writeDerivedExports(dir, []string{"commits-2024.csv"}, parquetExportFormat{}, make(exportIndex))
+	require.NoError(t, err)
+	require.Equal(t, []string{"commits-2024.parquet"}, files)
+
+	data, err := os.ReadFile(filepath.Join(dir, "commits-2024.parquet"))
+	require.NoError(t, err)
+	require.Equal(t, "PAR1", string(data[:4]))
+	require.Equal(t, "PAR1", string(data[len(data)-4:]))
+}
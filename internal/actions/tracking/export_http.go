@@ -0,0 +1,238 @@
+package tracking
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/app"
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+const (
+	// httpExportBatchSize is the maximum number of events sent per request.
+	httpExportBatchSize = 500
+	// httpExportTimeout bounds a single request to the export endpoint.
+	httpExportTimeout = 30 * time.Second
+	// httpExportSchema identifies the payload layout for the receiving service.
+	httpExportSchema = "footprint.events.v1"
+)
+
+// httpExportBackoff is the delay before the first retry. Doubles per attempt up to maxBackoff.
+var httpExportBackoff = initialBackoff
+
+// httpExportClient is used for all requests to the export endpoint.
+var httpExportClient = &http.Client{Timeout: httpExportTimeout}
+
+// httpExportPayload is the JSON body POSTed to export_http_url.
+// Each event has the same fields as a row in commits.csv.
+type httpExportPayload struct {
+	Schema string            `json:"schema"`
+	Events []json.RawMessage `json:"events"`
+}
+
+// httpExportURL returns the configured HTTP export endpoint, or "" if unset.
+func httpExportURL() string {
+	value, _ := config.Get("export_http_url")
+	return strings.TrimSpace(value)
+}
+
+// validateHTTPExportURL requires HTTPS, except for loopback addresses
+// where plain HTTP is allowed for local development.
+func validateHTTPExportURL(endpoint string) error {
+	return validateEndpointURL("export_http_url", endpoint)
+}
+
+// validateEndpointURL checks the URL stored in config key against the same
+// rules as validateHTTPExportURL.
+func validateEndpointURL(key, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid %s '%s': expected https://host/path", key, endpoint)
+	}
+
+	switch u.Scheme {
+	case "https":
+		return nil
+	case "http":
+		host := u.Hostname()
+		if host == "localhost" {
+			return nil
+		}
+		if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+			return nil
+		}
+		return fmt.Errorf("invalid %s '%s': only https is allowed for non-local hosts", key, endpoint)
+	default:
+		return fmt.Errorf("invalid %s '%s': expected https://host/path", key, endpoint)
+	}
+}
+
+// httpSink POSTs events to export_http_url in batches. Each batch counts
+// as delivered as soon as the endpoint accepts it, so a failure part way
+// through only leaves the remaining batches pending.
+type httpSink struct {
+	endpoint string
+}
+
+func (httpSink) Name() string { return "http" }
+
+func (s httpSink) Destination() string { return redactURL(s.endpoint) }
+
+func (s httpSink) Export(events []store.RepoEvent, deps Deps) (sinkResult, error) {
+	if err := validateHTTPExportURL(s.endpoint); err != nil {
+		return sinkResult{}, err
+	}
+	token, _ := config.Get("export_http_token")
+
+	// Build a map of repo paths for metadata enrichment
+	repoPaths := make(map[string]string)
+	for _, e := range events {
+		if e.RepoPath != "" {
+			repoPaths[e.RepoID] = e.RepoPath
+		}
+	}
+
+	redact := loadExportRedaction()
+	result := sinkResult{}
+	for start := 0; start < len(events); start += httpExportBatchSize {
+		batch := events[start:min(start+httpExportBatchSize, len(events))]
+
+		payload := httpExportPayload{Schema: httpExportSchema, Events: make([]json.RawMessage, 0, len(batch))}
+		ids := make([]int64, 0, len(batch))
+		for _, e := range batch {
+			var meta git.CommitMetadata
+			if repoPath, ok := repoPaths[e.RepoID]; ok {
+				meta = deps.metas.At(e.RepoID, repoPath, e.Commit)
+			}
+			payload.Events = append(payload.Events, appendJSONRecord(nil, csvHeader, buildRecord(e, meta, deps.pushes[e.ID], redact)))
+			ids = append(ids, e.ID)
+		}
+
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return result, fmt.Errorf("could not encode events: %w", err)
+		}
+
+		if err := postWithRetry(s.endpoint, token, body); err != nil {
+			log.Warn("export: HTTP export failed, %d events remain pending: %v", len(events)-result.Written, err)
+			return result, err
+		}
+
+		result.Delivered = append(result.Delivered, ids...)
+		result.Written += len(batch)
+	}
+
+	result.Pushed = true
+	return result, nil
+}
+
+// errPermanent marks HTTP failures that retrying won't fix (4xx other than 429).
+var errPermanent = errors.New("request rejected")
+
+// postWithRetry POSTs body to endpoint, retrying network errors, 429 and 5xx
+// responses with exponential backoff.
+func postWithRetry(endpoint, token string, body []byte) error {
+	return sendWithRetry(endpoint, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req, nil
+	})
+}
+
+// sendWithRetry sends the request built by newRequest, retrying network
+// errors, 429 and 5xx responses with exponential backoff. newRequest is
+// called once per attempt so each attempt gets a fresh body.
+func sendWithRetry(endpoint string, newRequest func() (*http.Request, error)) error {
+	var lastErr error
+	backoff := httpExportBackoff
+
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		retryAfter, err := sendRequest(newRequest)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if errors.Is(err, errPermanent) || attempt == maxRetries {
+			break
+		}
+
+		wait := backoff
+		if retryAfter > 0 {
+			wait = min(retryAfter, maxBackoff)
+		}
+		log.Debug("export: HTTP attempt %d failed, retrying in %v: %v", attempt, wait, err)
+		time.Sleep(wait)
+		backoff = min(backoff*2, maxBackoff)
+	}
+
+	return fmt.Errorf("HTTP export to %s failed: %w", redactURL(endpoint), lastErr)
+}
+
+// sendRequest sends one request. On a 429 or 503 it also returns the
+// server's Retry-After delay, if any.
+func sendRequest(newRequest func() (*http.Request, error)) (time.Duration, error) {
+	req, err := newRequest()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", errPermanent, err)
+	}
+	req.Header.Set("User-Agent", "fp/"+app.Version)
+
+	resp, err := httpExportClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return 0, nil
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	statusErr := fmt.Errorf("server returned %s", resp.Status)
+	if msg := strings.TrimSpace(string(detail)); msg != "" {
+		statusErr = fmt.Errorf("server returned %s: %s", resp.Status, msg)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable:
+		var retryAfter time.Duration
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			retryAfter = time.Duration(secs) * time.Second
+		}
+		return retryAfter, statusErr
+	case resp.StatusCode >= 500:
+		return 0, statusErr
+	default:
+		return 0, fmt.Errorf("%w: %v", errPermanent, statusErr)
+	}
+}
+
+// redactURL strips credentials and query parameters before a URL is shown or logged.
+func redactURL(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	u.User = nil
+	u.RawQuery = ""
+	return u.String()
+}
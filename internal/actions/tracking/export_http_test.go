@@ -0,0 +1,142 @@
+package tracking
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func setupHTTPExport(t *testing.T, endpoint string, events int) *store.Store {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	lines, _ := config.Set(nil, "export_http_url", endpoint)
+	lines, _ = config.Set(lines, "export_http_token", "secret-token")
+	require.NoError(t, config.WriteLines(lines))
+
+	old := httpExportBackoff
+	httpExportBackoff = time.Millisecond
+	t.Cleanup(func() { httpExportBackoff = old })
+
+	s, err := store.New(filepath.Join(t.TempDir(), "store.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	for i := range events {
+		require.NoError(t, store.InsertEvent(s.DB(), store.RepoEvent{
+			RepoID:    "github.com/test/repo",
+			Commit:    "commit" + string(rune('a'+i)),
+			Branch:    "main",
+			Timestamp: time.Date(2025, 1, 1, 0, 0, i, 0, time.UTC),
+			Status:    store.StatusPending,
+			Source:    store.SourcePostCommit,
+		}))
+	}
+	return s
+}
+
+// resolveTestSinks resolves the configured sinks as an automatic export would.
+func resolveTestSinks(t *testing.T) []exportSink {
+	t.Helper()
+	sinks, err := resolveExportSinks(csvExportFormat{}, true)
+	require.NoError(t, err)
+	return sinks
+}
+
+func TestValidateHTTPExportURL(t *testing.T) {
+	require.NoError(t, validateHTTPExportURL("https://footprints.example.com/ingest"))
+	require.NoError(t, validateHTTPExportURL("http://localhost:8080/ingest"))
+	require.NoError(t, validateHTTPExportURL("http://127.0.0.1:8080/ingest"))
+	require.Error(t, validateHTTPExportURL("http://footprints.example.com/ingest"))
+	require.Error(t, validateHTTPExportURL("ftp://example.com"))
+	require.Error(t, validateHTTPExportURL("not a url"))
+}
+
+func TestDoExportWork_HTTP_PostsEventsAndMarksExported(t *testing.T) {
+	var received httpExportPayload
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	s := setupHTTPExport(t, server.URL+"/ingest", 2)
+	events, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+
+	outcome, err := doExportWork(s.DB(), events, resolveTestSinks(t), Deps{Now: time.Now})
+	count, pushed := outcome.Written, outcome.Pushed
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.True(t, pushed)
+
+	require.Equal(t, "Bearer secret-token", auth)
+	require.Equal(t, httpExportSchema, received.Schema)
+	require.Len(t, received.Events, 2)
+
+	var first map[string]any
+	require.NoError(t, json.Unmarshal(received.Events[0], &first))
+	require.Equal(t, "github.com/test/repo", first["repo_id"])
+
+	pending, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
+
+func TestDoExportWork_HTTP_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	s := setupHTTPExport(t, server.URL, 1)
+	events, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+
+	outcome, err := doExportWork(s.DB(), events, resolveTestSinks(t), Deps{Now: time.Now})
+	count := outcome.Written
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestDoExportWork_HTTP_ClientErrorLeavesEventsPending(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "bad token", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	s := setupHTTPExport(t, server.URL, 1)
+	events, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+
+	outcome, err := doExportWork(s.DB(), events, resolveTestSinks(t), Deps{Now: time.Now})
+	count, pushed := outcome.Written, outcome.Pushed
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bad token")
+	require.Zero(t, count)
+	require.False(t, pushed)
+	require.Equal(t, int32(1), calls.Load(), "4xx responses should not be retried")
+
+	pending, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+}
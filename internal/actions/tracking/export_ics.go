@@ -0,0 +1,226 @@
+package tracking
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// icsCommitLength is how long a commit's calendar entry lasts, ending
+	// at the commit: commits are instants, but calendars need a block.
+	icsCommitLength = 15 * time.Minute
+	// icsLineOctets is the longest content line RFC 5545 allows before
+	// it has to be folded.
+	icsLineOctets = 75
+	icsTimeFormat = "20060102T150405Z"
+)
+
+// icsExportFormat writes an iCalendar file to overlay activity on Google
+// Calendar or Outlook. With daily set, each day with commits is one entry
+// spanning its first to last commit; otherwise every commit is its own.
+// Entries are transparent, so they don't show as busy.
+type icsExportFormat struct {
+	daily bool
+}
+
+func (f icsExportFormat) Name() string {
+	if f.daily {
+		return "ics-daily"
+	}
+	return "ics"
+}
+
+func (icsExportFormat) Extension() string { return ".ics" }
+
+// icsCommit is the part of an export row a calendar entry shows.
+type icsCommit struct {
+	key        string
+	at         time.Time
+	repo       string
+	branch     string
+	commit     string
+	message    string
+	insertions int
+	deletions  int
+}
+
+func (f icsExportFormat) Write(w io.Writer, header []string, rows [][]string) error {
+	commits := icsCommits(header, rows)
+
+	bw := bufio.NewWriter(w)
+	writeICSLine(bw, "BEGIN:VCALENDAR")
+	writeICSLine(bw, "VERSION:2.0")
+	writeICSLine(bw, "PRODID:-//footprint-tools//fp//EN")
+	writeICSLine(bw, "CALSCALE:GREGORIAN")
+	writeICSLine(bw, "X-WR-CALNAME:"+icsEscape("Footprint activity"))
+	if f.daily {
+		for _, day := range icsDays(commits) {
+			writeICSDay(bw, day)
+		}
+	} else {
+		for _, c := range commits {
+			writeICSCommit(bw, c)
+		}
+	}
+	writeICSLine(bw, "END:VCALENDAR")
+	return bw.Flush()
+}
+
+// icsCommits reads the rows into commits, oldest first. Rows without a
+// readable timestamp, such as when export_columns leaves it out, have no
+// place on a calendar and are skipped.
+func icsCommits(header []string, rows [][]string) []icsCommit {
+	field := func(row []string, col string) string {
+		if i := slices.Index(header, col); i >= 0 && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	commits := make([]icsCommit, 0, len(rows))
+	for _, row := range rows {
+		at, err := time.Parse(time.RFC3339, field(row, "timestamp"))
+		if err != nil {
+			continue
+		}
+		repo := field(row, "repo_name")
+		if repo == "" {
+			repo = field(row, "repo_id")
+		}
+		key := field(row, "repo_id")
+		if key == "" {
+			key = repo
+		}
+		c := icsCommit{
+			key:     key + ":" + field(row, "commit_hash"),
+			at:      at,
+			repo:    repo,
+			branch:  field(row, "branch"),
+			commit:  field(row, "commit_hash"),
+			message: field(row, "message"),
+		}
+		c.insertions, _ = strconv.Atoi(field(row, "insertions"))
+		c.deletions, _ = strconv.Atoi(field(row, "deletions"))
+		commits = append(commits, c)
+	}
+	sort.SliceStable(commits, func(i, j int) bool { return commits[i].at.Before(commits[j].at) })
+	return commits
+}
+
+func writeICSCommit(w *bufio.Writer, c icsCommit) {
+	summary := c.repo
+	if c.message != "" {
+		summary += ": " + c.message
+	}
+	var details []string
+	if c.branch != "" {
+		details = append(details, "Branch: "+c.branch)
+	}
+	if c.commit != "" {
+		details = append(details, "Commit: "+c.commit)
+	}
+	details = append(details, fmt.Sprintf("Lines: +%d -%d", c.insertions, c.deletions))
+
+	sum := sha1.Sum([]byte(c.key))
+	writeICSEvent(w, hex.EncodeToString(sum[:8]), c.at.Add(-icsCommitLength), c.at, summary, strings.Join(details, "\n"))
+}
+
+// icsDay is the commits made on one local calendar day.
+type icsDay struct {
+	date    string
+	commits []icsCommit
+}
+
+// icsDays groups commits, oldest first, by the local day they were made.
+func icsDays(commits []icsCommit) []icsDay {
+	var days []icsDay
+	for _, c := range commits {
+		date := c.at.Local().Format(time.DateOnly)
+		if len(days) == 0 || days[len(days)-1].date != date {
+			days = append(days, icsDay{date: date})
+		}
+		days[len(days)-1].commits = append(days[len(days)-1].commits, c)
+	}
+	return days
+}
+
+func writeICSDay(w *bufio.Writer, day icsDay) {
+	type repoTotal struct {
+		name                           string
+		commits, insertions, deletions int
+	}
+	var repos []*repoTotal
+	byName := make(map[string]*repoTotal)
+	for _, c := range day.commits {
+		r := byName[c.repo]
+		if r == nil {
+			r = &repoTotal{name: c.repo}
+			byName[c.repo] = r
+			repos = append(repos, r)
+		}
+		r.commits++
+		r.insertions += c.insertions
+		r.deletions += c.deletions
+	}
+
+	names := make([]string, len(repos))
+	details := make([]string, len(repos))
+	for i, r := range repos {
+		names[i] = r.name
+		details[i] = fmt.Sprintf("%s: %d %s, +%d -%d", r.name, r.commits, pluralize(r.commits, "commit", "commits"), r.insertions, r.deletions)
+	}
+	summary := fmt.Sprintf("%d %s in %s", len(day.commits), pluralize(len(day.commits), "commit", "commits"), strings.Join(names, ", "))
+
+	first, last := day.commits[0].at, day.commits[len(day.commits)-1].at
+	writeICSEvent(w, "day-"+strings.ReplaceAll(day.date, "-", ""), first.Add(-icsCommitLength), last, summary, strings.Join(details, "\n"))
+}
+
+// writeICSEvent writes one VEVENT. DTSTAMP is the start rather than the
+// time of writing, so regenerating the file leaves unchanged entries alone.
+func writeICSEvent(w *bufio.Writer, uid string, start, end time.Time, summary, description string) {
+	writeICSLine(w, "BEGIN:VEVENT")
+	writeICSLine(w, "UID:"+uid+"@footprint")
+	writeICSLine(w, "DTSTAMP:"+start.UTC().Format(icsTimeFormat))
+	writeICSLine(w, "DTSTART:"+start.UTC().Format(icsTimeFormat))
+	writeICSLine(w, "DTEND:"+end.UTC().Format(icsTimeFormat))
+	writeICSLine(w, "SUMMARY:"+icsEscape(summary))
+	writeICSLine(w, "DESCRIPTION:"+icsEscape(description))
+	writeICSLine(w, "TRANSP:TRANSPARENT")
+	writeICSLine(w, "END:VEVENT")
+}
+
+// writeICSLine writes a content line ending in CRLF, folded so no line is
+// longer than icsLineOctets, without splitting a UTF-8 character.
+func writeICSLine(w *bufio.Writer, line string) {
+	limit := icsLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		_, _ = w.WriteString(line[:cut])
+		_, _ = w.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts
+		limit = icsLineOctets - 1
+	}
+	_, _ = w.WriteString(line)
+	_, _ = w.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// icsEscape escapes a TEXT value.
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,89 @@
+package tracking
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func icsTestRows() ([]string, [][]string) {
+	header := []string{"timestamp", "repo_id", "repo_name", "branch", "commit_hash", "message", "insertions", "deletions"}
+	rows := [][]string{
+		{"2025-06-15T10:00:00Z", "github.com/me/app", "app", "main", "bbb", "Fix parser; handle commas, too", "5", "1"},
+		{"2025-06-15T09:00:00Z", "github.com/me/app", "app", "main", "aaa", "Add parser", "20", "0"},
+		{"2025-06-15T11:30:00Z", "github.com/me/lib", "lib", "dev", "ccc", "Bump", "1", "1"},
+		{"not a time", "github.com/me/lib", "lib", "dev", "ddd", "Skipped", "1", "1"},
+	}
+	return header, rows
+}
+
+func TestICSExportFormat_PerCommit(t *testing.T) {
+	header, rows := icsTestRows()
+	var b strings.Builder
+	require.NoError(t, icsExportFormat{}.Write(&b, header, rows))
+	out := b.String()
+
+	require.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	require.Equal(t, 3, strings.Count(out, "BEGIN:VEVENT"))
+
+	// Oldest first, ending at the commit
+	first := strings.Index(out, "SUMMARY:app: Add parser")
+	require.Positive(t, first)
+	require.Less(t, first, strings.Index(out, "SUMMARY:app: Fix parser"))
+	require.Contains(t, out, "DTSTART:20250615T084500Z\r\nDTEND:20250615T090000Z")
+	require.Contains(t, out, `SUMMARY:app: Fix parser\; handle commas\, too`)
+	require.Contains(t, out, `DESCRIPTION:Branch: main\nCommit: aaa\nLines: +20 -0`)
+	require.Contains(t, out, "TRANSP:TRANSPARENT")
+	require.NotContains(t, out, "Skipped")
+
+	// Ids stay the same when the file is written again
+	var again strings.Builder
+	require.NoError(t, icsExportFormat{}.Write(&again, header, rows))
+	require.Equal(t, out, again.String())
+}
+
+func TestICSExportFormat_Daily(t *testing.T) {
+	// Days are local, so pin them down
+	local := time.Local
+	time.Local = time.UTC
+	t.Cleanup(func() { time.Local = local })
+
+	header, rows := icsTestRows()
+	rows = append(rows, []string{"2025-06-16T08:00:00Z", "github.com/me/app", "app", "main", "eee", "Next day", "2", "2"})
+
+	var b strings.Builder
+	require.NoError(t, icsExportFormat{daily: true}.Write(&b, header, rows))
+	out := b.String()
+
+	require.Equal(t, 2, strings.Count(out, "BEGIN:VEVENT"))
+	require.Contains(t, out, "UID:day-20250615@footprint")
+	require.Contains(t, out, `SUMMARY:3 commits in app\, lib`)
+	require.Contains(t, out, "DTSTART:20250615T084500Z\r\nDTEND:20250615T113000Z")
+	require.Contains(t, out, `DESCRIPTION:app: 2 commits\, +25 -1\nlib: 1 commit\, +1 -1`)
+	require.Contains(t, out, "SUMMARY:1 commit in app")
+}
+
+func TestWriteICSLine_Folds(t *testing.T) {
+	var b strings.Builder
+	line := "SUMMARY:" + strings.Repeat("é", 100)
+	w := bufio.NewWriter(&b)
+	writeICSLine(w, line)
+	require.NoError(t, w.Flush())
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	require.Greater(t, len(lines), 1)
+	var joined strings.Builder
+	for i, l := range lines {
+		require.LessOrEqual(t, len(l), icsLineOctets)
+		if i > 0 {
+			require.True(t, strings.HasPrefix(l, " "))
+			l = l[1:]
+		}
+		joined.WriteString(l)
+	}
+	require.Equal(t, line, joined.String())
+}
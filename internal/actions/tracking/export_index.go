@@ -0,0 +1,326 @@
+package tracking
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+const (
+	// exportIndexName holds a summary of each export CSV, in the export
+	// repo's .git directory so it is never committed.
+	exportIndexName = "fp-export-index.json"
+
+	// exportKeysDir holds, per CSV, hashes of the repo:commit keys in it,
+	// so appended rows can be told apart from rows replacing older ones.
+	exportKeysDir = "fp-export-keys"
+)
+
+// fileStamp tells whether a file changed since fp last looked at it: any
+// other write, such as a rewrite or a git pull, changes its size or
+// modification time.
+type fileStamp struct {
+	Size    int64 `json:"size"`
+	ModTime int64 `json:"mtime"` // unix nanoseconds
+}
+
+func stampOf(path string) (fileStamp, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, false
+	}
+	return fileStamp{Size: info.Size(), ModTime: info.ModTime().UnixNano()}, true
+}
+
+// derivedStamp is the derived file (see exportFormat) written from a CSV.
+type derivedStamp struct {
+	Ext   string    `json:"ext"`
+	Stamp fileStamp `json:"stamp"`
+}
+
+// csvIndex summarizes an export CSV as of Stamp, so exports that append to
+// it update the README and the derived file from the new rows only.
+type csvIndex struct {
+	Stamp fileStamp `json:"stamp"`
+	// Appended is the file after the rows exports appended past
+	// Stamp.Size, until the summary catches up with them.
+	Appended *fileStamp `json:"appended,omitempty"`
+
+	Events  int       `json:"events"`
+	First   time.Time `json:"first"`
+	Last    time.Time `json:"last"`
+	Repos   []string  `json:"repos,omitempty"`
+	Devices []string  `json:"devices,omitempty"`
+
+	Derived *derivedStamp `json:"derived,omitempty"`
+	// Encrypted is the encrypted copy as fp last wrote it.
+	Encrypted *fileStamp `json:"encrypted,omitempty"`
+}
+
+// exportIndex maps CSV paths, relative to the export repo, to their
+// summaries. Entries are dropped when fp rewrites a file; other changes
+// show in the stamp and make the next export read the file again.
+type exportIndex map[string]csvIndex
+
+func exportIndexPath(exportRepo string) string {
+	return filepath.Join(exportRepo, ".git", exportIndexName)
+}
+
+// loadExportIndex reads the summaries. A missing or unreadable file only
+// means the CSVs are read in full once more.
+func loadExportIndex(exportRepo string) exportIndex {
+	index := make(exportIndex)
+	data, err := os.ReadFile(exportIndexPath(exportRepo))
+	if err != nil {
+		return index
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		log.Warn("export: ignoring unreadable %s: %v", exportIndexName, err)
+		return make(exportIndex)
+	}
+	return index
+}
+
+func (x exportIndex) save(exportRepo string) {
+	if len(x) == 0 {
+		_ = os.Remove(exportIndexPath(exportRepo))
+		return
+	}
+	data, err := json.Marshal(x)
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(exportIndexPath(exportRepo), data, 0600); err != nil {
+		log.Warn("export: could not save %s: %v", exportIndexName, err)
+	}
+}
+
+// appended records that an export appended rows to rel, which looked like
+// before until then. The entry stays usable only if nothing else wrote the
+// file since the index last saw it.
+func (x exportIndex) appended(exportRepo, rel string, before fileStamp) {
+	entry, ok := x[rel]
+	if !ok {
+		return
+	}
+	expected := entry.Stamp
+	if entry.Appended != nil {
+		expected = *entry.Appended
+	}
+	after, found := stampOf(filepath.Join(exportRepo, rel))
+	if before != expected || !found {
+		delete(x, rel)
+		return
+	}
+	entry.Appended = &after
+	x[rel] = entry
+}
+
+// rewritten drops the summary of rel after fp rewrote the file.
+func (x exportIndex) rewritten(rel string) {
+	if entry, ok := x[rel]; ok {
+		x[rel] = csvIndex{Encrypted: entry.Encrypted}
+	}
+}
+
+// encrypted records the encrypted copy of rel (rel plus ext) fp just
+// wrote, so syncEncryptedExports can skip decrypting it until it changes.
+func (x exportIndex) encrypted(exportRepo, rel, ext string) {
+	stamp, ok := stampOf(filepath.Join(exportRepo, rel+ext))
+	if !ok {
+		return
+	}
+	entry := x[rel]
+	entry.Encrypted = &stamp
+	x[rel] = entry
+}
+
+// csvChanges is what changed in a CSV since the index last saw it: the
+// rows appended to it, or, when it had to be read in full, all of it.
+type csvChanges struct {
+	header   []string
+	appended [][]string          // rows appended, as written to the file
+	records  map[string][]string // every record by key, when read in full
+}
+
+// refresh brings the entry for rel up to date with the file, reading only
+// the rows appended since when it can, and returns what changed.
+func (x exportIndex) refresh(exportRepo, rel string) (csvChanges, error) {
+	path := filepath.Join(exportRepo, rel)
+	stamp, _ := stampOf(path)
+	entry, ok := x[rel]
+
+	if ok && entry.Appended == nil && entry.Stamp == stamp {
+		return csvChanges{}, nil
+	}
+	if ok && entry.Appended != nil && *entry.Appended == stamp {
+		changes, err := x.refreshAppended(exportRepo, rel, entry, stamp)
+		if err == nil {
+			return changes, nil
+		}
+		log.Debug("export: reading all of %s: %v", rel, err)
+	}
+
+	records, err := loadCSVRecords(path)
+	if err != nil {
+		return csvChanges{}, err
+	}
+	fresh := csvIndex{Stamp: stamp, Encrypted: entry.Encrypted}
+	keys := make(map[uint64]bool, len(records))
+	for _, key := range slices.Sorted(maps.Keys(records)) {
+		fresh.add(key, records[key], keys)
+	}
+	x[rel] = fresh
+	saveExportKeys(exportRepo, rel, keys)
+	return csvChanges{header: exportColumns(), records: records}, nil
+}
+
+// refreshAppended adds the rows appended past entry.Stamp.Size to the
+// entry. A row counts as a new event unless its key is already in the
+// file, since readers keep the last row for each key.
+func (x exportIndex) refreshAppended(exportRepo, rel string, entry csvIndex, stamp fileStamp) (csvChanges, error) {
+	keys, err := loadExportKeys(exportRepo, rel)
+	if err != nil {
+		return csvChanges{}, err
+	}
+	header, rows, err := readCSVFrom(filepath.Join(exportRepo, rel), entry.Stamp.Size)
+	if err != nil {
+		return csvChanges{}, err
+	}
+
+	columns := exportColumns()
+	for _, row := range rows {
+		record := toCanonicalRecord(header, row)
+		entry.add(recordKey(record, columns), record, keys)
+	}
+	entry.Stamp = stamp
+	entry.Appended = nil
+	x[rel] = entry
+	saveExportKeys(exportRepo, rel, keys)
+	return csvChanges{header: header, appended: rows}, nil
+}
+
+// add counts record, stored under key, into the summary. Rows whose
+// timestamp can't be parsed still count, but don't move the date range.
+func (e *csvIndex) add(key string, record []string, keys map[uint64]bool) {
+	if h := keyHash(key); !keys[h] {
+		keys[h] = true
+		e.Events++
+	}
+	// Repository ids can contain colons (local:/path), hashes can't
+	e.Repos = insertSorted(e.Repos, key[:max(0, strings.LastIndex(key, ":"))])
+	if device := record[slices.Index(csvHeader, "device")]; device != "" {
+		e.Devices = insertSorted(e.Devices, device)
+	}
+	t, err := time.Parse(time.RFC3339, record[slices.Index(csvHeader, "timestamp")])
+	if err != nil {
+		return
+	}
+	if e.First.IsZero() || t.Before(e.First) {
+		e.First = t
+	}
+	if t.After(e.Last) {
+		e.Last = t
+	}
+}
+
+func insertSorted(values []string, v string) []string {
+	i, found := slices.BinarySearch(values, v)
+	if found {
+		return values
+	}
+	return slices.Insert(values, i, v)
+}
+
+func keyHash(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum64()
+}
+
+func exportKeysPath(exportRepo, rel string) string {
+	return filepath.Join(exportRepo, ".git", exportKeysDir, filepath.Base(rel)+".keys")
+}
+
+func loadExportKeys(exportRepo, rel string) (map[uint64]bool, error) {
+	data, err := os.ReadFile(exportKeysPath(exportRepo, rel))
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%8 != 0 {
+		return nil, fmt.Errorf("%s is truncated", filepath.Base(exportKeysPath(exportRepo, rel)))
+	}
+	keys := make(map[uint64]bool, len(data)/8)
+	for i := 0; i < len(data); i += 8 {
+		keys[binary.LittleEndian.Uint64(data[i:])] = true
+	}
+	return keys, nil
+}
+
+// saveExportKeys writes the key hashes for rel. If that fails the next
+// append reads the file in full, so it is only logged.
+func saveExportKeys(exportRepo, rel string, keys map[uint64]bool) {
+	hashes := make([]uint64, 0, len(keys))
+	for h := range keys {
+		hashes = append(hashes, h)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+
+	path := exportKeysPath(exportRepo, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		log.Debug("export: could not save keys for %s: %v", rel, err)
+		return
+	}
+	err := writeFileAtomic(path, func(w io.Writer) error {
+		buf := make([]byte, 8)
+		bw := bufio.NewWriter(w)
+		for _, h := range hashes {
+			binary.LittleEndian.PutUint64(buf, h)
+			if _, err := bw.Write(buf); err != nil {
+				return err
+			}
+		}
+		return bw.Flush()
+	})
+	if err != nil {
+		log.Debug("export: could not save keys for %s: %v", rel, err)
+	}
+}
+
+// readCSVFrom returns the header of a CSV file and the rows from offset
+// on, which must be the start of a row.
+func readCSVFrom(path string, offset int64) ([]string, [][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	header, err := csv.NewReader(bufio.NewReader(file)).Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read header: %w", err)
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, nil, err
+	}
+	r := csv.NewReader(bufio.NewReader(file))
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read appended rows: %w", err)
+	}
+	return header, rows, nil
+}
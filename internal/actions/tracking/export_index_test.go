@@ -0,0 +1,95 @@
+package tracking
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// exportDerived writes the derived JSONL files as an export commit would.
+func exportDerived(t *testing.T, dir string, files []string) exportIndex {
+	t.Helper()
+	index := loadExportIndex(dir)
+	written, err := writeDerivedExports(dir, files, jsonlExportFormat{}, index)
+	require.NoError(t, err)
+	require.Equal(t, []string{"commits.jsonl"}, written)
+	index.save(dir)
+	return index
+}
+
+func TestWriteDerivedExports_AppendsWithCSV(t *testing.T) {
+	dir := appendExportDir(t)
+	deps := Deps{Now: func() time.Time { return time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC) }}
+	jsonlPath := filepath.Join(dir, "commits.jsonl")
+
+	_, files, err := exportAllEvents(dir, []store.RepoEvent{appendTestEvent("bbb", 2, ""), appendTestEvent("ccc", 3, "")}, deps)
+	require.NoError(t, err)
+	index := exportDerived(t, dir, files)
+	require.Len(t, csvLines(t, jsonlPath), 2)
+	require.Equal(t, 2, index["commits.csv"].Events)
+
+	// Appended CSV rows are appended to the JSONL file, and a row for an
+	// exported commit doesn't count as a new event
+	_, files, err = exportAllEvents(dir, []store.RepoEvent{appendTestEvent("aaa", 1, ""), appendTestEvent("ccc", 3, "reviewed")}, deps)
+	require.NoError(t, err)
+	require.NotNil(t, loadExportIndex(dir)["commits.csv"].Appended)
+	index = exportDerived(t, dir, files)
+	lines := csvLines(t, jsonlPath)
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[3], `"note":"reviewed"`)
+
+	entry := index["commits.csv"]
+	require.Nil(t, entry.Appended)
+	require.Equal(t, 3, entry.Events)
+	require.Equal(t, "2025-03-01", entry.First.Format(time.DateOnly))
+	require.Equal(t, []string{"github.com/user/repo"}, entry.Repos)
+
+	summary, err := summarizeExport(dir, index)
+	require.NoError(t, err)
+	require.Equal(t, 3, summary.Events)
+
+	// Compaction rewrites the JSONL file deduplicated too
+	compacted, err := compactExportCSVs(dir)
+	require.NoError(t, err)
+	exportDerived(t, dir, compacted)
+	lines = csvLines(t, jsonlPath)
+	require.Len(t, lines, 3)
+	require.Contains(t, lines[0], "aaa")
+	require.Contains(t, lines[2], `"note":"reviewed"`)
+}
+
+func TestExportIndex_NoticesOtherWrites(t *testing.T) {
+	dir := appendExportDir(t)
+	deps := Deps{Now: func() time.Time { return time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC) }}
+	csvPath := filepath.Join(dir, "commits.csv")
+
+	_, files, err := exportAllEvents(dir, []store.RepoEvent{appendTestEvent("bbb", 2, "")}, deps)
+	require.NoError(t, err)
+	exportDerived(t, dir, files)
+
+	// Unchanged files are not read again
+	index := loadExportIndex(dir)
+	changes, err := index.refresh(dir, "commits.csv")
+	require.NoError(t, err)
+	require.Nil(t, changes.appended)
+	require.Nil(t, changes.records)
+
+	// A file rewritten behind the index's back, as a git pull does, is read
+	// in full
+	records, err := loadCSVRecords(csvPath)
+	require.NoError(t, err)
+	other := appendTestEvent("ddd", 4, "")
+	records["github.com/user/repo:ddd"] = buildRecord(other, git.CommitMetadata{}, nil, loadExportRedaction())
+	require.NoError(t, writeCSVSorted(csvPath, records))
+
+	changes, err = index.refresh(dir, "commits.csv")
+	require.NoError(t, err)
+	require.Len(t, changes.records, 2)
+	require.Equal(t, 2, index["commits.csv"].Events)
+	require.Nil(t, index["commits.csv"].Derived, "derived file must be rewritten")
+}
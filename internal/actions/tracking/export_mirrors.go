@@ -0,0 +1,193 @@
+package tracking
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// primaryRemote is the export repo's remote set by export_remote. An export
+// counts as pushed once it reaches this remote, whatever the mirrors do.
+const primaryRemote = "origin"
+
+// exportMirror is an extra remote the export repo is pushed to, such as a
+// self-hosted Gitea next to GitHub.
+type exportMirror struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// mirrorNamePattern is what mirror names may look like: they name git
+// remotes in the export repo.
+var mirrorNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// remotePush is the outcome of pushing the export repo to one remote.
+type remotePush struct {
+	Remote  string `json:"remote"`
+	Primary bool   `json:"primary"`
+	Pushed  bool   `json:"pushed"`
+	Error   string `json:"error,omitempty"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// printRemotePushes reports how the push to each remote went. With only
+// the primary remote, a successful push reads as before.
+func printRemotePushes(remotes []remotePush, deps Deps) {
+	for _, r := range remotes {
+		name := r.Remote
+		if !r.Primary {
+			name += " (mirror)"
+		} else if len(remotes) == 1 && r.Pushed {
+			_, _ = deps.Println("Pushed to remote")
+			continue
+		}
+		if r.Pushed {
+			_, _ = deps.Printf("Pushed to %s\n", name)
+		} else {
+			_, _ = deps.Printf("Push to %s failed: %s\n", name, r.Error)
+			if r.Hint != "" {
+				_, _ = deps.Printf("  Hint: %s\n", r.Hint)
+			}
+		}
+	}
+}
+
+// parseExportMirrors reads export_mirrors: comma-separated name=url pairs.
+// Malformed entries are logged and skipped.
+func parseExportMirrors(value string) []exportMirror {
+	var mirrors []exportMirror
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, url, _ := strings.Cut(part, "=")
+		name, url = strings.TrimSpace(name), strings.TrimSpace(url)
+		if validateMirrorName(name) != nil || url == "" || seen[name] {
+			log.Warn("export: ignoring export_mirrors entry '%s'", part)
+			continue
+		}
+		seen[name] = true
+		mirrors = append(mirrors, exportMirror{Name: name, URL: url})
+	}
+	return mirrors
+}
+
+// formatExportMirrors writes mirrors back as an export_mirrors value.
+func formatExportMirrors(mirrors []exportMirror) string {
+	parts := make([]string, 0, len(mirrors))
+	for _, m := range mirrors {
+		parts = append(parts, m.Name+"="+m.URL)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// validateMirrorName checks name can name a mirror.
+func validateMirrorName(name string) error {
+	if name == primaryRemote {
+		return fmt.Errorf("'%s' is the primary remote, set it with 'fp config set export_remote <url>'", primaryRemote)
+	}
+	if !mirrorNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid mirror name '%s': use letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// loadExportMirrors returns the mirrors configured in export_mirrors.
+func loadExportMirrors() []exportMirror {
+	value, _ := config.Get("export_mirrors")
+	return parseExportMirrors(value)
+}
+
+// syncMirrorRemotes makes the export repo's git remotes match mirrors,
+// adding the missing ones and updating changed URLs. Remotes that are no
+// longer mirrors are left alone; 'fp export remotes remove' deletes them.
+func syncMirrorRemotes(exportRepo string, mirrors []exportMirror) error {
+	for _, m := range mirrors {
+		cmd := exec.Command("git", "remote", "get-url", m.Name)
+		cmd.Dir = exportRepo
+		out, err := cmd.Output()
+		switch {
+		case err != nil:
+			err = runGitInDir(exportRepo, "remote", "add", m.Name, m.URL)
+		case strings.TrimSpace(string(out)) != m.URL:
+			err = runGitInDir(exportRepo, "remote", "set-url", m.Name, m.URL)
+		}
+		if err != nil {
+			return fmt.Errorf("could not configure mirror %s: %w", m.Name, err)
+		}
+	}
+	return nil
+}
+
+// pushExportMirrors pushes the export repo to every mirror. A mirror that
+// fails doesn't stop the others and doesn't keep events pending: they are
+// already on the primary remote, and the mirror catches up on the next
+// push since it always gets the whole branch.
+func pushExportMirrors(exportRepo string) []remotePush {
+	mirrors := loadExportMirrors()
+	if len(mirrors) == 0 {
+		return nil
+	}
+	if err := syncMirrorRemotes(exportRepo, mirrors); err != nil {
+		log.Warn("export: %v", err)
+	}
+
+	results := make([]remotePush, 0, len(mirrors))
+	for _, m := range mirrors {
+		result := remotePush{Remote: m.Name}
+		if err := pushToRemote(exportRepo, m.Name); err != nil {
+			log.Warn("export: failed to push to mirror %s: %v", m.Name, err)
+			result.Error = err.Error()
+			result.Hint = pushErrorHint(err)
+		} else {
+			result.Pushed = true
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// pushToRemote pushes HEAD of the export repo to remote, retrying with
+// exponential backoff when the failure might be temporary. Failures are
+// returned as a *pushError saying what went wrong and how to fix it.
+func pushToRemote(exportRepo, remote string) error {
+	var lastErr error
+	backoff := initialBackoff
+
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		cmd := exec.Command("git", "push", "-u", remote, "HEAD")
+		if remote != primaryRemote {
+			// Only the primary remote is the branch's upstream
+			cmd = exec.Command("git", "push", remote, "HEAD")
+		}
+		cmd.Dir = exportRepo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			pe := newPushError(remote, remoteURL(exportRepo, remote), string(out), err, attempt)
+			lastErr = pe
+			if !pe.Kind.retryable() {
+				return pe
+			}
+			if attempt < maxRetries {
+				log.Debug("export: push to %s attempt %d failed, retrying in %v: %v", remote, attempt, backoff, err)
+				time.Sleep(backoff)
+				// Exponential backoff with cap
+				backoff *= 2
+				if backoff > maxBackoff {
+					backoff = maxBackoff
+				}
+				continue
+			}
+		} else {
+			return nil // Success
+		}
+	}
+
+	return lastErr
+}
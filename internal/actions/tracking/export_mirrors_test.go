@@ -0,0 +1,183 @@
+package tracking
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestParseExportMirrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []exportMirror
+	}{
+		{"empty", "", nil},
+		{"one", "gitea=https://git.example.com/me/a.git", []exportMirror{{Name: "gitea", URL: "https://git.example.com/me/a.git"}}},
+		{
+			"several with spaces",
+			" gitea = ssh://git@git.example.com/a.git , backup=/srv/backup.git ",
+			[]exportMirror{{Name: "gitea", URL: "ssh://git@git.example.com/a.git"}, {Name: "backup", URL: "/srv/backup.git"}},
+		},
+		{"skips origin", "origin=x, gitea=y", []exportMirror{{Name: "gitea", URL: "y"}}},
+		{"skips malformed", "noequals, bad name=x, empty=, ok=y", []exportMirror{{Name: "ok", URL: "y"}}},
+		{"skips duplicates", "a=x, a=y", []exportMirror{{Name: "a", URL: "x"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, parseExportMirrors(tt.value))
+		})
+	}
+}
+
+func TestFormatExportMirrors_RoundTrips(t *testing.T) {
+	mirrors := []exportMirror{{Name: "gitea", URL: "https://git.example.com/a.git"}, {Name: "backup", URL: "/srv/b.git"}}
+	require.Equal(t, mirrors, parseExportMirrors(formatExportMirrors(mirrors)))
+}
+
+func TestValidateMirrorName(t *testing.T) {
+	require.NoError(t, validateMirrorName("gitea"))
+	require.NoError(t, validateMirrorName("self-hosted_2"))
+	require.ErrorContains(t, validateMirrorName("origin"), "primary remote")
+	require.Error(t, validateMirrorName(""))
+	require.Error(t, validateMirrorName("-x"))
+	require.Error(t, validateMirrorName("a b"))
+}
+
+func TestPrintRemotePushes(t *testing.T) {
+	render := func(remotes []remotePush) string {
+		var out strings.Builder
+		deps := Deps{
+			Println: func(a ...any) (int, error) { return fmt.Fprintln(&out, a...) },
+			Printf:  func(format string, a ...any) (int, error) { return fmt.Fprintf(&out, format, a...) },
+		}
+		printRemotePushes(remotes, deps)
+		return out.String()
+	}
+
+	require.Equal(t, "Pushed to remote\n", render([]remotePush{{Remote: "origin", Primary: true, Pushed: true}}))
+	require.Equal(t, "Pushed to origin\nPush to gitea (mirror) failed: timeout\n", render([]remotePush{
+		{Remote: "origin", Primary: true, Pushed: true},
+		{Remote: "gitea", Error: "timeout"},
+	}))
+	require.Equal(t, "Push to origin failed: denied\nPushed to gitea (mirror)\n", render([]remotePush{
+		{Remote: "origin", Primary: true, Error: "denied"},
+		{Remote: "gitea", Pushed: true},
+	}))
+	require.Equal(t, "Push to origin failed: authentication failed\n  Hint: load your key\n", render([]remotePush{
+		{Remote: "origin", Primary: true, Error: "authentication failed", Hint: "load your key"},
+	}))
+}
+
+// mirrorSinkExport runs the git sink on one event with the primary push
+// returning primaryErr and a mirror that always fails.
+func mirrorSinkExport(t *testing.T, primaryErr error) sinkResult {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	exportDir := filepath.Join(t.TempDir(), "export")
+	require.NoError(t, ensureExportRepo(exportDir))
+	for _, kv := range [][]string{{"user.name", "Test User"}, {"user.email", "test@example.com"}} {
+		cmd := exec.Command("git", "config", kv[0], kv[1])
+		cmd.Dir = exportDir
+		require.NoError(t, cmd.Run())
+	}
+
+	mirrorsPushed := false
+	deps := Deps{
+		Now:            func() time.Time { return time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC) },
+		GetExportRepo:  func() string { return exportDir },
+		HasRemote:      func(string) bool { return true },
+		PullExportRepo: func(string) error { return nil },
+		PushExportRepo: func(string) error { return primaryErr },
+		PushExportMirrors: func(string) []remotePush {
+			mirrorsPushed = true
+			return []remotePush{{Remote: "gitea", Error: "connection refused"}}
+		},
+	}
+
+	events := []store.RepoEvent{{
+		ID: 1, RepoID: "github.com/user/repo", Commit: "abc123", Branch: "main",
+		Timestamp: time.Date(2025, 6, 15, 10, 0, 0, 0, time.UTC), Source: store.SourcePostCommit,
+	}}
+	result, err := gitCSVSink{format: csvExportFormat{}}.Export(events, deps)
+	require.NoError(t, err)
+	require.True(t, mirrorsPushed, "mirrors should be pushed whatever the primary did")
+	return result
+}
+
+func TestGitCSVSink_PushedWhenPrimarySucceedsAndMirrorFails(t *testing.T) {
+	result := mirrorSinkExport(t, nil)
+
+	require.True(t, result.Pushed)
+	require.Equal(t, []int64{1}, result.Delivered)
+	require.Equal(t, []remotePush{
+		{Remote: "origin", Primary: true, Pushed: true},
+		{Remote: "gitea", Error: "connection refused"},
+	}, result.Remotes)
+}
+
+func TestGitCSVSink_PendingWhenPrimaryFails(t *testing.T) {
+	result := mirrorSinkExport(t, errors.New("denied"))
+
+	require.False(t, result.Pushed)
+	require.Empty(t, result.Delivered, "events stay pending until the primary has them")
+	require.Len(t, result.Remotes, 2)
+	require.Equal(t, "denied", result.Remotes[0].Error)
+}
+
+func TestExportRemotes_AddListRemove(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	exportDir := filepath.Join(t.TempDir(), "export")
+	require.NoError(t, ensureExportRepo(exportDir))
+	lines, err := config.ReadLines()
+	require.NoError(t, err)
+	lines, _ = config.Set(lines, "export_path", exportDir)
+	require.NoError(t, config.WriteLines(lines))
+
+	var out strings.Builder
+	deps := Deps{
+		Println: func(a ...any) (int, error) { return fmt.Fprintln(&out, a...) },
+		Printf:  func(format string, a ...any) (int, error) { return fmt.Fprintf(&out, format, a...) },
+	}
+	noFlags := dispatchers.NewParsedFlags(nil)
+
+	mirrorURL := filepath.Join(t.TempDir(), "mirror.git")
+	require.NoError(t, exportRemotesAdd([]string{"gitea", mirrorURL}, noFlags, deps))
+	require.ErrorContains(t, exportRemotesAdd([]string{"gitea", "other"}, noFlags, deps), "already exists")
+	require.ErrorContains(t, exportRemotesAdd([]string{"origin", "other"}, noFlags, deps), "primary remote")
+
+	value, _ := config.Get("export_mirrors")
+	require.Equal(t, "gitea="+mirrorURL, value)
+	cmd := exec.Command("git", "remote", "get-url", "gitea")
+	cmd.Dir = exportDir
+	got, err := cmd.Output()
+	require.NoError(t, err)
+	require.Equal(t, mirrorURL, strings.TrimSpace(string(got)))
+
+	out.Reset()
+	require.NoError(t, exportRemotesList(nil, noFlags, deps))
+	require.Contains(t, out.String(), "gitea        mirror   "+mirrorURL)
+	require.Contains(t, out.String(), "never pushed")
+
+	require.NoError(t, exportRemotesRemove([]string{"gitea"}, noFlags, deps))
+	require.ErrorContains(t, exportRemotesRemove([]string{"gitea"}, noFlags, deps), "no mirror")
+	value, _ = config.Get("export_mirrors")
+	require.Empty(t, value)
+	cmd = exec.Command("git", "remote", "get-url", "gitea")
+	cmd.Dir = exportDir
+	require.Error(t, cmd.Run())
+}
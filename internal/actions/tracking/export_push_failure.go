@@ -0,0 +1,289 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/output"
+)
+
+// pushFailure is why git couldn't push the export repo, read from what git
+// printed.
+type pushFailure int
+
+const (
+	pushFailureUnknown pushFailure = iota
+	pushFailureAuth
+	pushFailureHostKey
+	pushFailureNotFound
+	pushFailureRejected
+	pushFailureNetwork
+)
+
+func (f pushFailure) String() string {
+	switch f {
+	case pushFailureAuth:
+		return "authentication failed"
+	case pushFailureHostKey:
+		return "host key not trusted"
+	case pushFailureNotFound:
+		return "repository not found"
+	case pushFailureRejected:
+		return "rejected by the remote"
+	case pushFailureNetwork:
+		return "remote unreachable"
+	default:
+		return "git error"
+	}
+}
+
+// retryable reports whether trying again could help. Credentials,
+// host keys and missing repositories don't fix themselves a few seconds
+// later.
+func (f pushFailure) retryable() bool {
+	return f == pushFailureNetwork || f == pushFailureUnknown
+}
+
+// pushFailurePatterns are matched against git's output, lowercased, in
+// order: auth comes before network because ssh ends an auth failure with
+// "could not read from remote repository" too.
+var pushFailurePatterns = []struct {
+	kind     pushFailure
+	patterns []string
+}{
+	{pushFailureHostKey, []string{"host key verification failed", "remote host identification has changed"}},
+	{pushFailureAuth, []string{
+		"permission denied (publickey", "authentication failed", "could not read username",
+		"could not read password", "invalid username or password", "http basic: access denied",
+		"returned error: 401", "returned error: 403", "password authentication was removed",
+		"terminal prompts disabled", "denied to",
+	}},
+	{pushFailureNotFound, []string{"repository not found", "does not appear to be a git repository", "returned error: 404", "project you were looking for could not be found"}},
+	{pushFailureRejected, []string{"[rejected]", "non-fast-forward", "fetch first", "stale info", "pre-receive hook declined", "protected branch", "[remote rejected]"}},
+	{pushFailureNetwork, []string{
+		"could not resolve host", "could not resolve hostname", "connection refused", "connection timed out",
+		"operation timed out", "network is unreachable", "no route to host", "failed to connect",
+		"connection reset", "remote end hung up", "early eof", "ssl", "could not read from remote repository",
+	}},
+}
+
+// classifyPushFailure reads why a push failed from git's output and picks
+// the line that says so.
+func classifyPushFailure(gitOutput string) (pushFailure, string) {
+	lines := strings.Split(strings.TrimSpace(gitOutput), "\n")
+	for _, p := range pushFailurePatterns {
+		for _, line := range lines {
+			lower := strings.ToLower(line)
+			for _, pattern := range p.patterns {
+				if strings.Contains(lower, pattern) {
+					return p.kind, strings.TrimSpace(line)
+				}
+			}
+		}
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			return pushFailureUnknown, line
+		}
+	}
+	return pushFailureUnknown, ""
+}
+
+// pushError is a failed push of the export repo, with what to do about it.
+type pushError struct {
+	Remote   string
+	Kind     pushFailure
+	Detail   string
+	Attempts int
+	Hint     string
+}
+
+func (e *pushError) Error() string {
+	msg := e.Kind.String()
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.Attempts > 1 {
+		msg += fmt.Sprintf(" (after %d attempts)", e.Attempts)
+	}
+	return msg
+}
+
+// newPushError classifies git's output for a push to remote at rawURL.
+func newPushError(remote, rawURL, gitOutput string, err error, attempts int) *pushError {
+	kind, detail := classifyPushFailure(gitOutput)
+	if detail == "" {
+		detail = err.Error()
+	}
+	return &pushError{
+		Remote:   remote,
+		Kind:     kind,
+		Detail:   detail,
+		Attempts: attempts,
+		Hint:     pushFailureHint(kind, rawURL),
+	}
+}
+
+// pushErrorHint returns the remediation for err if it is a push failure.
+func pushErrorHint(err error) string {
+	var pe *pushError
+	if errors.As(err, &pe) {
+		return pe.Hint
+	}
+	return ""
+}
+
+// pushFailureHint says how to fix a failed push to rawURL, told apart by
+// whether it goes over SSH or HTTPS.
+func pushFailureHint(kind pushFailure, rawURL string) string {
+	host, ssh := remoteHost(rawURL)
+	switch kind {
+	case pushFailureAuth:
+		if ssh {
+			return fmt.Sprintf("Check your SSH key is loaded (ssh-add -l) and added to %s, then test it with 'ssh -T git@%s'. Exports run in the background, so the key must work without a passphrase prompt", host, host)
+		}
+		return fmt.Sprintf("Store a token with write access for %s in a credential helper (git config --global credential.helper store, or your OS keychain). Exports run in the background and can't prompt for a password", host)
+	case pushFailureHostKey:
+		return fmt.Sprintf("Connect once by hand to check and accept %s's host key: ssh -T git@%s", host, host)
+	case pushFailureNotFound:
+		return fmt.Sprintf("Check the repository %s exists and your account can see it, or fix the URL (fp config set export_remote, or fp export remotes for a mirror)", rawURL)
+	case pushFailureRejected:
+		return "The remote has commits the export repo doesn't. Run 'fp export --now' to pull and retry, or 'fp export repair' if that fails"
+	case pushFailureNetwork:
+		return "Check your connection. Events stay pending and are pushed with the next export"
+	default:
+		return "Run 'fp export --verify-remote' to test the connection and credentials"
+	}
+}
+
+// remoteURL is the URL of remote in the export repo, or "" if it has none.
+func remoteURL(exportRepo, remote string) string {
+	out, err := gitOutputInDir(exportRepo, "remote", "get-url", remote)
+	if err != nil {
+		return ""
+	}
+	return out
+}
+
+// remoteHost returns the host of a git URL and whether it is reached over
+// SSH, either as ssh://host/path or as scp-like user@host:path.
+func remoteHost(rawURL string) (host string, ssh bool) {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return u.Hostname(), u.Scheme == "ssh" || u.Scheme == "git+ssh"
+	}
+	if at := strings.Index(rawURL, "@"); at >= 0 {
+		if colon := strings.Index(rawURL[at:], ":"); colon > 0 {
+			return rawURL[at+1 : at+colon], true
+		}
+	}
+	if before, _, ok := strings.Cut(rawURL, ":"); ok && !strings.Contains(before, "/") {
+		return before, true
+	}
+	return rawURL, false
+}
+
+// remoteCheck is the outcome of fp export --verify-remote for one remote.
+type remoteCheck struct {
+	Remote  string `json:"remote"`
+	URL     string `json:"url"`
+	Primary bool   `json:"primary"`
+	Fetch   bool   `json:"fetch"`
+	Push    bool   `json:"push"`
+	Failure string `json:"failure,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// exportVerifyRemote checks that every export remote can be read and
+// pushed to with the credentials background exports use, without
+// exporting anything: git ls-remote, then git push --dry-run.
+func exportVerifyRemote(exportRepo string, jsonOutput bool, deps Deps) error {
+	if diagnoseExportRepo(exportRepo) == exportRepoMissing || !hasRemote(exportRepo) {
+		return fmt.Errorf("no export remote configured\nHint: Run 'fp config set export_remote <url>'")
+	}
+
+	checks := []remoteCheck{{Remote: primaryRemote, URL: remoteURL(exportRepo, primaryRemote), Primary: true}}
+	for _, m := range loadExportMirrors() {
+		checks = append(checks, remoteCheck{Remote: m.Name, URL: m.URL})
+	}
+	hasCommits := countExportCommits(exportRepo) > 0
+
+	failed := 0
+	for i := range checks {
+		checks[i] = verifyRemote(exportRepo, checks[i], hasCommits)
+		if checks[i].Error != "" {
+			failed++
+		}
+	}
+
+	if jsonOutput {
+		if err := output.JSON(deps.Println, checks); err != nil {
+			return err
+		}
+	} else {
+		for _, c := range checks {
+			name := c.Remote
+			if !c.Primary {
+				name += " (mirror)"
+			}
+			switch {
+			case c.Error != "":
+				_, _ = deps.Printf("%s %s: %s\n", name, c.URL, c.Error)
+				_, _ = deps.Printf("  Hint: %s\n", c.Hint)
+			case c.Push:
+				_, _ = deps.Printf("%s %s: can fetch and push\n", name, c.URL)
+			default:
+				_, _ = deps.Printf("%s %s: can fetch (push not checked: nothing exported yet)\n", name, c.URL)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d export remotes failed the check", failed, len(checks))
+	}
+	return nil
+}
+
+// verifyRemote runs the checks for one remote with prompts turned off, as
+// in a background export.
+func verifyRemote(exportRepo string, c remoteCheck, hasCommits bool) remoteCheck {
+	run := func(args ...string) (string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), remoteCheckTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = exportRepo
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		out, err := cmd.CombinedOutput()
+		if ctx.Err() != nil {
+			return fmt.Sprintf("connection timed out after %s", remoteCheckTimeout), ctx.Err()
+		}
+		return string(out), err
+	}
+	fail := func(out string, err error) remoteCheck {
+		pe := newPushError(c.Remote, c.URL, out, err, 1)
+		c.Failure = pe.Kind.String()
+		c.Error = pe.Error()
+		c.Hint = pe.Hint
+		return c
+	}
+
+	// By URL, as mirrors only become git remotes with the first push
+	if out, err := run("ls-remote", "--heads", c.URL); err != nil {
+		return fail(out, err)
+	}
+	c.Fetch = true
+
+	if !hasCommits {
+		return c
+	}
+	if out, err := run("push", "--dry-run", "--porcelain", c.URL, "HEAD"); err != nil {
+		return fail(out, err)
+	}
+	c.Push = true
+	return c
+}
@@ -0,0 +1,93 @@
+package tracking
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+)
+
+func TestClassifyPushFailure(t *testing.T) {
+	tests := []struct {
+		output string
+		kind   pushFailure
+		detail string
+	}{
+		{"git@github.com: Permission denied (publickey).\nfatal: Could not read from remote repository.", pushFailureAuth, "git@github.com: Permission denied (publickey)."},
+		{"fatal: could not read Username for 'https://github.com': terminal prompts disabled", pushFailureAuth, "fatal: could not read Username for 'https://github.com': terminal prompts disabled"},
+		{"remote: Permission to me/x.git denied to other.\nfatal: unable to access 'https://github.com/me/x.git/': The requested URL returned error: 403", pushFailureAuth, "remote: Permission to me/x.git denied to other."},
+		{"Host key verification failed.\nfatal: Could not read from remote repository.", pushFailureHostKey, "Host key verification failed."},
+		{"ERROR: Repository not found.\nfatal: Could not read from remote repository.", pushFailureNotFound, "ERROR: Repository not found."},
+		{"To github.com:me/x.git\n ! [rejected]        main -> main (fetch first)\nerror: failed to push some refs", pushFailureRejected, "! [rejected]        main -> main (fetch first)"},
+		{"ssh: Could not resolve hostname github.com: Name or service not known\nfatal: Could not read from remote repository.", pushFailureNetwork, "ssh: Could not resolve hostname github.com: Name or service not known"},
+		{"fatal: something new\n", pushFailureUnknown, "fatal: something new"},
+		{"", pushFailureUnknown, ""},
+	}
+	for _, tt := range tests {
+		kind, detail := classifyPushFailure(tt.output)
+		require.Equal(t, tt.kind, kind, tt.output)
+		require.Equal(t, tt.detail, detail, tt.output)
+	}
+}
+
+func TestRemoteHost(t *testing.T) {
+	tests := []struct {
+		url  string
+		host string
+		ssh  bool
+	}{
+		{"git@github.com:me/x.git", "github.com", true},
+		{"ssh://git@gitea.local:2222/me/x.git", "gitea.local", true},
+		{"https://github.com/me/x.git", "github.com", false},
+		{"gitlab.com:me/x.git", "gitlab.com", true},
+	}
+	for _, tt := range tests {
+		host, ssh := remoteHost(tt.url)
+		require.Equal(t, tt.host, host, tt.url)
+		require.Equal(t, tt.ssh, ssh, tt.url)
+	}
+
+	require.Contains(t, pushFailureHint(pushFailureAuth, "git@github.com:me/x.git"), "ssh -T git@github.com")
+	require.Contains(t, pushFailureHint(pushFailureAuth, "https://github.com/me/x.git"), "credential helper")
+}
+
+func TestPushToRemote_MissingRepoIsNotRetried(t *testing.T) {
+	exportDir, _ := setupSquashRepo(t, 1)
+	missing := filepath.Join(t.TempDir(), "gone.git")
+	require.NoError(t, runGitInDir(exportDir, "remote", "set-url", "origin", missing))
+
+	err := pushExportRepo(exportDir)
+	var pe *pushError
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, pushFailureNotFound, pe.Kind)
+	require.Equal(t, 1, pe.Attempts)
+	require.Contains(t, pe.Hint, missing)
+}
+
+func TestExportVerifyRemote(t *testing.T) {
+	exportDir, remoteDir := setupSquashRepo(t, 1)
+	var printed []string
+	deps := repairDeps(exportDir, &printed)
+
+	require.NoError(t, export(nil, dispatchers.NewParsedFlags([]string{"--verify-remote"}), deps))
+	require.Contains(t, strings.Join(printed, ""), "origin "+remoteDir+": can fetch and push")
+
+	require.NoError(t, os.RemoveAll(remoteDir))
+	printed = nil
+	err := export(nil, dispatchers.NewParsedFlags([]string{"--verify-remote"}), deps)
+	require.ErrorContains(t, err, "1 of 1 export remotes failed")
+	out := strings.Join(printed, "")
+	require.Contains(t, out, "repository not found")
+	require.Contains(t, out, "Hint: Check the repository")
+}
+
+func TestExportVerifyRemote_NoRemote(t *testing.T) {
+	exportDir := setupRepairRepo(t)
+	var printed []string
+	err := exportVerifyRemote(exportDir, false, repairDeps(exportDir, &printed))
+	require.ErrorContains(t, err, "no export remote configured")
+}
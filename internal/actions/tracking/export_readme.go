@@ -0,0 +1,144 @@
+package tracking
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// exportReadmeName is the summary export writes at the top of the export
+// repo, so the backup explains itself to whoever opens it.
+const exportReadmeName = "README.md"
+
+// exportSummary describes the exported dataset, read back from its CSV files.
+type exportSummary struct {
+	Files   []exportFileSummary
+	Events  int
+	First   time.Time
+	Last    time.Time
+	Repos   int
+	Devices []string
+	Columns []string
+}
+
+type exportFileSummary struct {
+	Name   string
+	Events int
+}
+
+// summarizeExport sums up every CSV in the export repo from the index,
+// which reads only what changed in each file since the last export.
+func summarizeExport(exportRepo string, index exportIndex) (exportSummary, error) {
+	summary := exportSummary{Columns: exportColumns()}
+
+	paths, err := filepath.Glob(filepath.Join(exportRepo, "commits*.csv"))
+	if err != nil {
+		return summary, err
+	}
+	sort.Strings(paths)
+
+	repos := make(map[string]bool)
+	devices := make(map[string]bool)
+	for _, path := range paths {
+		rel := filepath.Base(path)
+		if _, err := index.refresh(exportRepo, rel); err != nil {
+			return summary, fmt.Errorf("could not read %s: %w", rel, err)
+		}
+		entry := index[rel]
+		summary.Files = append(summary.Files, exportFileSummary{Name: rel, Events: entry.Events})
+		summary.Events += entry.Events
+
+		for _, repo := range entry.Repos {
+			repos[repo] = true
+		}
+		for _, device := range entry.Devices {
+			devices[device] = true
+		}
+		if !entry.First.IsZero() && (summary.First.IsZero() || entry.First.Before(summary.First)) {
+			summary.First = entry.First
+		}
+		if entry.Last.After(summary.Last) {
+			summary.Last = entry.Last
+		}
+	}
+
+	summary.Repos = len(repos)
+	for device := range devices {
+		summary.Devices = append(summary.Devices, device)
+	}
+	sort.Strings(summary.Devices)
+	return summary, nil
+}
+
+// writeExportReadme regenerates README.md in the export repo from the CSV
+// files there and returns its path relative to the repo.
+func writeExportReadme(exportRepo string, index exportIndex, now time.Time) (string, error) {
+	summary, err := summarizeExport(exportRepo, index)
+	if err != nil {
+		return "", err
+	}
+
+	err = writeFileAtomic(filepath.Join(exportRepo, exportReadmeName), func(w io.Writer) error {
+		_, err := io.WriteString(w, renderExportReadme(summary, now))
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return exportReadmeName, nil
+}
+
+// renderExportReadme formats the summary as Markdown.
+func renderExportReadme(s exportSummary, now time.Time) string {
+	var b strings.Builder
+
+	b.WriteString("# Git activity export\n\n")
+	b.WriteString("This repository is a backup of git activity recorded by fp\n")
+	b.WriteString("(https://github.com/footprint-tools/cli). Each row of the CSV files is one\n")
+	b.WriteString("event (a commit, merge, push, checkout and so on) from a tracked repository.\n\n")
+	b.WriteString("`fp export` regenerates this file on every export, so edits to it are lost.\n\n")
+
+	dateRange := "none yet"
+	if !s.First.IsZero() {
+		dateRange = s.First.UTC().Format("2006-01-02") + " to " + s.Last.UTC().Format("2006-01-02")
+	}
+	devices := "unknown"
+	if len(s.Devices) > 0 {
+		devices = strings.Join(s.Devices, ", ")
+	}
+
+	b.WriteString("| | |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Events | %d |\n", s.Events)
+	fmt.Fprintf(&b, "| Date range | %s |\n", dateRange)
+	fmt.Fprintf(&b, "| Repositories | %d |\n", s.Repos)
+	fmt.Fprintf(&b, "| Devices | %s |\n", devices)
+	fmt.Fprintf(&b, "| Schema | %s |\n", httpExportSchema)
+	fmt.Fprintf(&b, "| Last export | %s |\n\n", now.UTC().Format("2006-01-02 15:04 UTC"))
+
+	if len(s.Files) > 0 {
+		b.WriteString("## Files\n\n")
+		for _, f := range s.Files {
+			noun := "events"
+			if f.Events == 1 {
+				noun = "event"
+			}
+			fmt.Fprintf(&b, "- `%s`: %d %s\n", f.Name, f.Events, noun)
+		}
+		fmt.Fprintf(&b, "\n`%s` holds the current year; earlier years move to `commits-<year>.csv`.\n\n", activeCSVName)
+	}
+
+	b.WriteString("## Columns\n\n")
+	for i, col := range s.Columns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "`%s`", col)
+	}
+	b.WriteString("\n\nTimestamps are RFC 3339. Rows are unique per repository and commit.\n")
+	b.WriteString("Load them into fp on another machine with `fp import <path to this repo>`.\n")
+
+	return b.String()
+}
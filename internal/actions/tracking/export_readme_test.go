@@ -0,0 +1,60 @@
+package tracking
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteExportReadme(t *testing.T) {
+	setRedactionConfig(t)
+	dir := t.TempDir()
+
+	current := repairRecord("aaa")
+	current[colRepoID] = "local:/src/tool"
+	current[colTimestamp] = "2025-03-02T10:00:00Z"
+	other := repairRecord("bbb")
+	other[colRepoID] = "github.com/user/app"
+	other[colDevice] = "laptop"
+	other[colTimestamp] = "2025-05-01T09:00:00+02:00"
+	require.NoError(t, writeCSVSorted(filepath.Join(dir, "commits.csv"), map[string][]string{"a": current, "b": other}))
+
+	old := repairRecord("ccc")
+	old[colRepoID] = "github.com/user/app"
+	old[colTimestamp] = "2024-11-20T08:00:00Z"
+	require.NoError(t, writeCSVSorted(filepath.Join(dir, "commits-2024.csv"), map[string][]string{"c": old}))
+
+	now := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+	name, err := writeExportReadme(dir, make(exportIndex), now)
+	require.NoError(t, err)
+	require.Equal(t, "README.md", name)
+
+	content, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	require.NoError(t, err)
+	readme := string(content)
+	require.Contains(t, readme, "| Events | 3 |")
+	require.Contains(t, readme, "| Date range | 2024-11-20 to 2025-05-01 |")
+	require.Contains(t, readme, "| Repositories | 2 |")
+	require.Contains(t, readme, "| Devices | host, laptop |")
+	require.Contains(t, readme, "| Schema | footprint.events.v1 |")
+	require.Contains(t, readme, "| Last export | 2025-06-01 12:30 UTC |")
+	require.Contains(t, readme, "- `commits-2024.csv`: 1 event")
+	require.Contains(t, readme, "- `commits.csv`: 2 events")
+	require.Contains(t, readme, "`event_id`, `event_type`")
+}
+
+func TestWriteExportReadme_Empty(t *testing.T) {
+	setRedactionConfig(t)
+	dir := t.TempDir()
+
+	_, err := writeExportReadme(dir, make(exportIndex), time.Now())
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "| Date range | none yet |")
+	require.NotContains(t, string(content), "## Files")
+}
@@ -0,0 +1,109 @@
+package tracking
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"path"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// Values for export_redact_messages.
+const (
+	redactMessagesDrop = "drop"
+	redactMessagesHash = "hash"
+)
+
+// exportRedaction is the privacy filter applied to every exported record,
+// from the export_redact_messages, export_redact_emails and
+// export_exclude_repos keys.
+type exportRedaction struct {
+	Messages     string // "", redactMessagesDrop or redactMessagesHash
+	Emails       bool
+	ExcludeRepos []string
+}
+
+// loadExportRedaction reads the redaction settings. An unrecognized
+// export_redact_messages value drops messages rather than leaking them.
+func loadExportRedaction() exportRedaction {
+	var r exportRedaction
+
+	messages, _ := config.Get("export_redact_messages")
+	switch strings.ToLower(strings.TrimSpace(messages)) {
+	case "", "false", "0", "no", "off":
+	case "true", "1", "yes", "on", redactMessagesDrop:
+		r.Messages = redactMessagesDrop
+	case redactMessagesHash:
+		r.Messages = redactMessagesHash
+	default:
+		log.Warn("export: invalid export_redact_messages '%s', dropping messages", messages)
+		r.Messages = redactMessagesDrop
+	}
+
+	emails, _ := config.Get("export_redact_emails")
+	switch strings.ToLower(strings.TrimSpace(emails)) {
+	case "true", "1", "yes", "on":
+		r.Emails = true
+	}
+
+	repos, _ := config.Get("export_exclude_repos")
+	for _, part := range strings.Split(repos, ",") {
+		if p := strings.TrimSpace(part); p != "" {
+			r.ExcludeRepos = append(r.ExcludeRepos, p)
+		}
+	}
+
+	return r
+}
+
+// Excludes reports whether events from repoID must never be exported.
+// Patterns match whole repository ids, with * matching within a segment.
+func (r exportRedaction) Excludes(repoID string) bool {
+	for _, pattern := range r.ExcludeRepos {
+		if pattern == repoID {
+			return true
+		}
+		if ok, _ := path.Match(pattern, repoID); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// message returns the commit message as it may be exported.
+func (r exportRedaction) message(message string) string {
+	switch r.Messages {
+	case redactMessagesDrop:
+		return ""
+	case redactMessagesHash:
+		if message == "" {
+			return ""
+		}
+		hash := sha256.Sum256([]byte(message))
+		return "sha256:" + hex.EncodeToString(hash[:8])
+	default:
+		return message
+	}
+}
+
+// email returns the author email as it may be exported. author_id is still
+// derived from the real address, so activity stays attributable.
+func (r exportRedaction) email(email string) string {
+	if r.Emails {
+		return ""
+	}
+	return email
+}
+
+// skipExcludedRepos marks pending events from excluded repositories as
+// skipped, so they are never exported, and returns the rest.
+func skipExcludedRepos(db *sql.DB, events []store.RepoEvent, r exportRedaction) []store.RepoEvent {
+	if len(r.ExcludeRepos) == 0 {
+		return events
+	}
+	return skipEvents(db, events, func(e store.RepoEvent) bool { return !r.Excludes(e.RepoID) }, "from excluded repositories")
+}
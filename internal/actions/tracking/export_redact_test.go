@@ -0,0 +1,108 @@
+package tracking
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func setRedactionConfig(t *testing.T, kv ...string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	lines, err := config.ReadLines()
+	require.NoError(t, err)
+	for i := 0; i < len(kv); i += 2 {
+		lines, _ = config.Set(lines, kv[i], kv[i+1])
+	}
+	require.NoError(t, config.WriteLines(lines))
+}
+
+func TestLoadExportRedaction(t *testing.T) {
+	setRedactionConfig(t,
+		"export_redact_messages", "hash",
+		"export_redact_emails", "true",
+		"export_exclude_repos", "github.com/acme/*, local:/home/me/secret",
+	)
+
+	r := loadExportRedaction()
+	require.Equal(t, redactMessagesHash, r.Messages)
+	require.True(t, r.Emails)
+	require.True(t, r.Excludes("github.com/acme/payroll"))
+	require.True(t, r.Excludes("local:/home/me/secret"))
+	require.False(t, r.Excludes("github.com/acme/payroll/sub"), "* stays within one segment")
+	require.False(t, r.Excludes("github.com/me/app"))
+}
+
+func TestLoadExportRedaction_InvalidMessagesValueDrops(t *testing.T) {
+	setRedactionConfig(t, "export_redact_messages", "maybe")
+	require.Equal(t, redactMessagesDrop, loadExportRedaction().Messages)
+}
+
+func TestBuildRecord_Redaction(t *testing.T) {
+	event := store.RepoEvent{RepoID: "github.com/me/app", Commit: "abc", Timestamp: time.Now()}
+	meta := git.CommitMetadata{AuthorEmail: "me@work.com", Subject: "Fix payroll rounding"}
+	plain := buildRecord(event, meta, nil, exportRedaction{})
+
+	dropped := buildRecord(event, meta, nil, exportRedaction{Messages: redactMessagesDrop, Emails: true})
+	require.Empty(t, dropped[colMessage])
+	require.Empty(t, dropped[colAuthorEmail])
+	require.Equal(t, plain[colAuthorID], dropped[colAuthorID], "author_id still identifies the author")
+
+	hashed := buildRecord(event, meta, nil, exportRedaction{Messages: redactMessagesHash})
+	require.Regexp(t, `^sha256:[0-9a-f]{16}$`, hashed[colMessage])
+	require.Equal(t, hashed[colMessage], buildRecord(event, meta, nil, exportRedaction{Messages: redactMessagesHash})[colMessage])
+	require.Equal(t, "me@work.com", hashed[colAuthorEmail])
+}
+
+func TestSkipExcludedRepos(t *testing.T) {
+	db := newTestStore(t).DB()
+	for _, repo := range []string{"github.com/me/app", "github.com/acme/payroll"} {
+		require.NoError(t, store.InsertEvent(db, store.RepoEvent{
+			RepoID: repo, RepoPath: "/r", Commit: "aaaaaaa", Branch: "main",
+			Timestamp: time.Now(), Status: store.StatusPending, Source: store.SourcePostCommit,
+		}))
+	}
+	events, err := store.GetPendingEvents(db)
+	require.NoError(t, err)
+
+	kept := skipExcludedRepos(db, events, exportRedaction{ExcludeRepos: []string{"github.com/acme/*"}})
+	require.Len(t, kept, 1)
+	require.Equal(t, "github.com/me/app", kept[0].RepoID)
+
+	pending, err := store.GetPendingEvents(db)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+}
+
+func TestSelectPendingEvents_ExcludedRepos(t *testing.T) {
+	setRedactionConfig(t, "export_exclude_repos", "github.com/acme/*")
+	db := newTestStore(t).DB()
+	day := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	for _, repoID := range []string{"github.com/me/app", "github.com/acme/payroll"} {
+		require.NoError(t, store.InsertEvent(db, store.RepoEvent{RepoID: repoID, Commit: "aaaaaaa", Branch: "main", Timestamp: day, Status: store.StatusPending, Source: store.SourcePostCommit}))
+	}
+
+	// A preview leaves the excluded event pending
+	events, err := selectPendingEvents(db, pendingSelection{preview: true})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	pending, err := store.GetPendingEvents(db)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+
+	events, err = selectPendingEvents(db, pendingSelection{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "github.com/me/app", events[0].RepoID)
+	pending, err = store.GetPendingEvents(db)
+	require.NoError(t, err)
+	require.Len(t, pending, 1, "the excluded event is marked skipped")
+}
@@ -0,0 +1,202 @@
+package tracking
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// exportRemoteStatus is one remote of the export repo as listed by
+// fp export remotes list.
+type exportRemoteStatus struct {
+	SchemaVersion int    `json:"schema_version"`
+	Name          string `json:"name"`
+	URL           string `json:"url"`
+	Primary       bool   `json:"primary"`
+	Pushed        bool   `json:"pushed"`
+	Unpushed      int    `json:"unpushed"`
+}
+
+// ExportRemotesList handles `fp export remotes list`.
+func ExportRemotesList(args []string, flags *dispatchers.ParsedFlags) error {
+	return exportRemotesList(args, flags, DefaultDeps())
+}
+
+func exportRemotesList(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	exportRepo := getExportRepo()
+	primaryURL, _ := config.Get("export_remote")
+
+	remotes := []exportRemoteStatus{{Name: primaryRemote, URL: primaryURL, Primary: true}}
+	for _, m := range loadExportMirrors() {
+		remotes = append(remotes, exportRemoteStatus{Name: m.Name, URL: m.URL})
+	}
+	branch := exportBranch(exportRepo)
+	for i := range remotes {
+		remotes[i].SchemaVersion = output.SchemaVersion
+		remotes[i].Unpushed, remotes[i].Pushed = unpushedCommits(exportRepo, remotes[i].Name, branch)
+	}
+
+	if flags.Has("--json") {
+		return output.JSON(deps.Println, remotes)
+	}
+
+	for _, r := range remotes {
+		url := r.URL
+		if url == "" {
+			url = "(not set)"
+		}
+		role := "mirror"
+		if r.Primary {
+			role = "primary"
+		}
+		_, _ = deps.Printf("%-12s %-8s %s\n", r.Name, role, url)
+		if r.URL == "" {
+			continue
+		}
+		switch {
+		case !r.Pushed:
+			_, _ = deps.Println("             never pushed")
+		case r.Unpushed == 0:
+			_, _ = deps.Println("             up to date")
+		case r.Unpushed == 1:
+			_, _ = deps.Println("             1 commit not pushed")
+		default:
+			_, _ = deps.Printf("             %d commits not pushed\n", r.Unpushed)
+		}
+	}
+	return nil
+}
+
+// ExportRemotesAdd handles `fp export remotes add <name> <url>`.
+func ExportRemotesAdd(args []string, flags *dispatchers.ParsedFlags) error {
+	return exportRemotesAdd(args, flags, DefaultDeps())
+}
+
+func exportRemotesAdd(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) < 1 {
+		return usage.MissingArgument("name")
+	}
+	if len(args) < 2 {
+		return usage.MissingArgument("url")
+	}
+	name, url := args[0], strings.TrimSpace(args[1])
+	if err := validateMirrorName(name); err != nil {
+		return fmt.Errorf("fp: %w", err)
+	}
+	if url == "" || strings.Contains(url, ",") {
+		return fmt.Errorf("fp: invalid mirror URL '%s'", url)
+	}
+
+	mirrors := loadExportMirrors()
+	for _, m := range mirrors {
+		if m.Name == name {
+			return fmt.Errorf("fp: mirror '%s' already exists (%s), remove it first", name, m.URL)
+		}
+	}
+	mirrors = append(mirrors, exportMirror{Name: name, URL: url})
+	if err := saveExportMirrors(mirrors); err != nil {
+		return err
+	}
+
+	if exportRepo := getExportRepo(); diagnoseExportRepo(exportRepo) != exportRepoMissing {
+		if err := syncMirrorRemotes(exportRepo, mirrors); err != nil {
+			return err
+		}
+	}
+	_, _ = deps.Printf("Added mirror %s (%s)\n", name, url)
+	_, _ = deps.Println("The next export pushes to it too.")
+	return nil
+}
+
+// ExportRemotesRemove handles `fp export remotes remove <name>`.
+func ExportRemotesRemove(args []string, flags *dispatchers.ParsedFlags) error {
+	return exportRemotesRemove(args, flags, DefaultDeps())
+}
+
+func exportRemotesRemove(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	if len(args) < 1 {
+		return usage.MissingArgument("name")
+	}
+	name := args[0]
+	if name == primaryRemote {
+		return fmt.Errorf("fp: '%s' is the primary remote, unset it with 'fp config unset export_remote'", primaryRemote)
+	}
+
+	mirrors := loadExportMirrors()
+	kept := mirrors[:0:0]
+	for _, m := range mirrors {
+		if m.Name != name {
+			kept = append(kept, m)
+		}
+	}
+	if len(kept) == len(mirrors) {
+		return fmt.Errorf("fp: no mirror named '%s'", name)
+	}
+	if err := saveExportMirrors(kept); err != nil {
+		return err
+	}
+
+	if exportRepo := getExportRepo(); diagnoseExportRepo(exportRepo) != exportRepoMissing {
+		// The git remote may already be gone; the config is what counts
+		_ = runGitInDir(exportRepo, "remote", "remove", name)
+	}
+	_, _ = deps.Printf("Removed mirror %s\n", name)
+	return nil
+}
+
+// saveExportMirrors writes mirrors to export_mirrors, unsetting it when
+// there are none left.
+func saveExportMirrors(mirrors []exportMirror) error {
+	lines, err := config.ReadLines()
+	if err != nil {
+		return err
+	}
+	if len(mirrors) == 0 {
+		lines, _ = config.Unset(lines, "export_mirrors")
+	} else {
+		lines, _ = config.Set(lines, "export_mirrors", formatExportMirrors(mirrors))
+	}
+	return config.WriteLines(lines)
+}
+
+// exportBranch is the branch checked out in the export repo, or "" when
+// there is none yet.
+func exportBranch(exportRepo string) string {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = exportRepo
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	branch := strings.TrimSpace(string(out))
+	if branch == "HEAD" {
+		return ""
+	}
+	return branch
+}
+
+// unpushedCommits counts the export repo's commits that remote doesn't have
+// yet, as of the last push or fetch. pushed is false when branch was never
+// pushed to remote.
+func unpushedCommits(exportRepo, remote, branch string) (count int, pushed bool) {
+	if branch == "" {
+		return 0, false
+	}
+	cmd := exec.Command("git", "rev-list", "--count", remote+"/"+branch+"..HEAD")
+	cmd.Dir = exportRepo
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, false
+	}
+	count, err = strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return 0, false
+	}
+	return count, true
+}
@@ -0,0 +1,348 @@
+package tracking
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// exportRepoState describes what is wrong with the export repository, if anything.
+type exportRepoState int
+
+const (
+	exportRepoClean exportRepoState = iota
+	exportRepoMissing
+	exportRepoMerging
+	exportRepoRebasing
+	exportRepoCherryPicking
+	exportRepoDetached
+)
+
+func (s exportRepoState) String() string {
+	switch s {
+	case exportRepoClean:
+		return "clean"
+	case exportRepoMissing:
+		return "not initialized"
+	case exportRepoMerging:
+		return "merge in progress"
+	case exportRepoRebasing:
+		return "rebase in progress"
+	case exportRepoCherryPicking:
+		return "cherry-pick in progress"
+	case exportRepoDetached:
+		return "detached HEAD"
+	default:
+		return "unknown"
+	}
+}
+
+// repairStep returns a short description of what repair will do for the state.
+func (s exportRepoState) repairStep() string {
+	switch s {
+	case exportRepoMissing:
+		return "initialize the export repository"
+	case exportRepoMerging:
+		return "re-run CSV conflict resolution, or abort the merge if that fails"
+	case exportRepoRebasing:
+		return "abort the rebase (git rebase --abort)"
+	case exportRepoCherryPicking:
+		return "abort the cherry-pick (git cherry-pick --abort)"
+	case exportRepoDetached:
+		return "check out a branch and carry over the CSVs from the detached HEAD"
+	default:
+		return "nothing"
+	}
+}
+
+// ExportRepair handles `fp export repair`.
+func ExportRepair(args []string, flags *dispatchers.ParsedFlags) error {
+	return exportRepair(args, flags, DefaultDeps())
+}
+
+func exportRepair(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	exportRepo := deps.GetExportRepo()
+	dryRun := flags.Has("--dry-run")
+
+	if flags.Has("--force-reclone") {
+		if dryRun {
+			_, _ = deps.Printf("Would move %s aside, re-create it from the remote and restore local CSVs\n", exportRepo)
+			return nil
+		}
+		return recloneExportRepo(exportRepo, deps)
+	}
+
+	state := diagnoseExportRepo(exportRepo)
+	_, _ = deps.Printf("Export repo: %s\n", exportRepo)
+	_, _ = deps.Printf("State: %s\n", state)
+
+	if state == exportRepoClean {
+		_, _ = deps.Println("Nothing to repair")
+		return nil
+	}
+
+	if dryRun {
+		_, _ = deps.Printf("Would %s\n", state.repairStep())
+		return nil
+	}
+
+	if err := repairExportRepoState(exportRepo, state, deps); err != nil {
+		return fmt.Errorf("could not repair export repo: %w\nHint: Run 'fp export repair --force-reclone' to start over (local CSVs are kept)", err)
+	}
+
+	if after := diagnoseExportRepo(exportRepo); after != exportRepoClean {
+		return fmt.Errorf("export repo still in state %q after repair\nHint: Run 'fp export repair --force-reclone' to start over (local CSVs are kept)", after)
+	}
+
+	log.Info("export: repaired export repo (was %s)", state)
+	_, _ = deps.Println("Export repo repaired. Run 'fp export --now' to retry the export.")
+	return nil
+}
+
+// repairExportRepoState applies the repair step for state.
+func repairExportRepoState(exportRepo string, state exportRepoState, deps Deps) error {
+	switch state {
+	case exportRepoMissing:
+		return ensureExportRepo(exportRepo)
+	case exportRepoMerging:
+		return repairMerge(exportRepo, deps)
+	case exportRepoRebasing:
+		return runGitInDir(exportRepo, "rebase", "--abort")
+	case exportRepoCherryPicking:
+		return runGitInDir(exportRepo, "cherry-pick", "--abort")
+	case exportRepoDetached:
+		return reattachHead(exportRepo, deps)
+	}
+	return nil
+}
+
+// diagnoseExportRepo inspects the export repository and returns its state.
+// In-progress operations take precedence over a detached HEAD, since a
+// rebase always detaches HEAD while it runs.
+func diagnoseExportRepo(exportRepo string) exportRepoState {
+	gitDir := filepath.Join(exportRepo, ".git")
+	if _, err := os.Stat(gitDir); err != nil {
+		return exportRepoMissing
+	}
+
+	if _, err := os.Stat(filepath.Join(gitDir, "MERGE_HEAD")); err == nil {
+		return exportRepoMerging
+	}
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		if _, err := os.Stat(filepath.Join(gitDir, dir)); err == nil {
+			return exportRepoRebasing
+		}
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "CHERRY_PICK_HEAD")); err == nil {
+		return exportRepoCherryPicking
+	}
+
+	cmd := exec.Command("git", "symbolic-ref", "-q", "HEAD")
+	cmd.Dir = exportRepo
+	if err := cmd.Run(); err != nil {
+		return exportRepoDetached
+	}
+
+	return exportRepoClean
+}
+
+// repairMerge retries automatic CSV conflict resolution and completes the
+// merge. If resolution fails, the merge is aborted so exports can resume.
+func repairMerge(exportRepo string, deps Deps) error {
+	_, _ = deps.Println("Re-running CSV conflict resolution...")
+	if err := resolveCSVConflicts(exportRepo); err != nil {
+		_, _ = deps.Printf("Resolution failed (%v), aborting merge\n", err)
+		return runGitInDir(exportRepo, "merge", "--abort")
+	}
+	return runGitInDir(exportRepo, "commit", "--no-edit")
+}
+
+// reattachHead moves a detached HEAD back onto a branch, carrying over any
+// records that only exist in the detached working tree.
+func reattachHead(exportRepo string, deps Deps) error {
+	enc := exportEncryptionFromConfig()
+	snapshot := snapshotCSVs(exportRepo)
+
+	branch := pickExportBranch(exportRepo)
+	if branch == "" {
+		_, _ = deps.Println("No local branch found, creating 'main' at the current commit")
+		if err := runGitInDir(exportRepo, "checkout", "-b", "main"); err != nil {
+			return err
+		}
+	} else {
+		_, _ = deps.Printf("Checking out branch '%s'\n", branch)
+		if err := runGitInDir(exportRepo, "checkout", "-f", branch); err != nil {
+			return err
+		}
+	}
+
+	if err := prepareEncryptedRepair(exportRepo, enc); err != nil {
+		return err
+	}
+	files, err := restoreCSVs(exportRepo, snapshot)
+	if err != nil {
+		return err
+	}
+	return commitRestoredCSVs(exportRepo, files, enc, deps)
+}
+
+// recloneExportRepo moves the export repo aside and re-creates it, cloning
+// from the configured remote when there is one. Records from the old CSVs
+// are merged into the new checkout so nothing exported locally is lost.
+func recloneExportRepo(exportRepo string, deps Deps) error {
+	enc := exportEncryptionFromConfig()
+	if enc != nil {
+		// Records only in the encrypted files, say without a plaintext copy,
+		// go into the snapshot too
+		if err := syncEncryptedExports(exportRepo, enc); err != nil {
+			log.Warn("export: repair could not decrypt the old exports: %v", err)
+		}
+	}
+	snapshot := snapshotCSVs(exportRepo)
+
+	remoteURL := ""
+	if _, err := os.Stat(filepath.Join(exportRepo, ".git")); err == nil {
+		cmd := exec.Command("git", "remote", "get-url", "origin")
+		cmd.Dir = exportRepo
+		if out, err := cmd.Output(); err == nil {
+			remoteURL = strings.TrimSpace(string(out))
+		}
+	}
+
+	backupPath := ""
+	if _, err := os.Stat(exportRepo); err == nil {
+		backupPath = fmt.Sprintf("%s.broken-%d", exportRepo, deps.Now().Unix())
+		if err := os.Rename(exportRepo, backupPath); err != nil {
+			return fmt.Errorf("could not move export repo aside: %w", err)
+		}
+		_, _ = deps.Printf("Moved old export repo to %s\n", backupPath)
+	}
+
+	if remoteURL != "" {
+		_, _ = deps.Printf("Cloning %s...\n", remoteURL)
+		if err := os.MkdirAll(filepath.Dir(exportRepo), 0700); err != nil {
+			return err
+		}
+		cmd := exec.Command("git", "clone", remoteURL, exportRepo)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git clone failed: %w\n%s", err, strings.TrimSpace(string(out)))
+		}
+	} else if err := ensureExportRepo(exportRepo); err != nil {
+		return fmt.Errorf("could not initialize export repo: %w", err)
+	}
+
+	if err := prepareEncryptedRepair(exportRepo, enc); err != nil {
+		return err
+	}
+	files, err := restoreCSVs(exportRepo, snapshot)
+	if err != nil {
+		return err
+	}
+	if err := commitRestoredCSVs(exportRepo, files, enc, deps); err != nil {
+		return err
+	}
+
+	log.Info("export: re-created export repo (backup=%s)", backupPath)
+	_, _ = deps.Printf("Export repo re-created with %d CSV files restored\n", len(files))
+	return nil
+}
+
+// prepareEncryptedRepair readies a checkout for restoring CSVs while
+// export_encrypt_recipient is set: plaintext exports are ignored, and the
+// records of the encrypted files, such as those just cloned, are merged
+// into their plaintext copies first. Does nothing when enc is nil.
+func prepareEncryptedRepair(exportRepo string, enc *exportEncryption) error {
+	if enc == nil {
+		return nil
+	}
+	if _, err := ignorePlaintextExports(exportRepo); err != nil {
+		return err
+	}
+	return syncEncryptedExports(exportRepo, enc)
+}
+
+// commitRestoredCSVs commits the CSVs repair restored. With encryption on
+// they go through commitExportFiles like any export, so only the encrypted
+// files are committed.
+func commitRestoredCSVs(exportRepo string, files []string, enc *exportEncryption, deps Deps) error {
+	if enc == nil {
+		return commitExportChanges(exportRepo, files)
+	}
+	format, err := resolveExportFormat("")
+	if err != nil {
+		return err
+	}
+	return commitExportFiles(exportRepo, files, format, deps.Now())
+}
+
+// pickExportBranch returns the local branch to return to, preferring main and master.
+func pickExportBranch(exportRepo string) string {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname:short)", "refs/heads")
+	cmd.Dir = exportRepo
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+
+	branches := strings.Fields(string(out))
+	for _, preferred := range []string{"main", "master"} {
+		for _, b := range branches {
+			if b == preferred {
+				return b
+			}
+		}
+	}
+	if len(branches) > 0 {
+		return branches[0]
+	}
+	return ""
+}
+
+// snapshotCSVs loads every CSV in the export repo root, keyed by file name.
+// Files that can't be parsed (e.g. containing conflict markers) are skipped.
+func snapshotCSVs(exportRepo string) map[string]map[string][]string {
+	snapshot := make(map[string]map[string][]string)
+
+	paths, _ := filepath.Glob(filepath.Join(exportRepo, "*.csv"))
+	for _, path := range paths {
+		records, err := loadCSVRecords(path)
+		if err != nil {
+			log.Warn("export: repair could not read %s, skipping: %v", path, err)
+			continue
+		}
+		snapshot[filepath.Base(path)] = records
+	}
+	return snapshot
+}
+
+// restoreCSVs merges snapshot records into the CSVs of the export repo.
+// Snapshot records win for duplicate repo:commit keys. Returns the files written.
+func restoreCSVs(exportRepo string, snapshot map[string]map[string][]string) ([]string, error) {
+	names := make([]string, 0, len(snapshot))
+	for name := range snapshot {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(exportRepo, name)
+		records, err := loadCSVRecords(path)
+		if err != nil {
+			log.Warn("export: repair replacing unreadable %s: %v", path, err)
+			records = make(map[string][]string)
+		}
+		for key, record := range snapshot[name] {
+			records[key] = record
+		}
+		if err := writeCSVSorted(path, records); err != nil {
+			return nil, fmt.Errorf("could not restore %s: %w", name, err)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,167 @@
+package tracking
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRepairRepo(t *testing.T) string {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "Test User")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	exportDir := filepath.Join(t.TempDir(), "export")
+	require.NoError(t, ensureExportRepo(exportDir))
+	return exportDir
+}
+
+func repairRecord(commit string) []string {
+	return []string{"id-" + commit, "commit", "2025-01-01T00:00:00Z", "repo", "repo", "", "", "", "main", commit, "", "msg", "1", "1", "0", "host"}
+}
+
+func repairDeps(exportDir string, printed *[]string) Deps {
+	return Deps{
+		GetExportRepo: func() string { return exportDir },
+		Now:           func() time.Time { return time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC) },
+		Printf: func(format string, a ...any) (int, error) {
+			*printed = append(*printed, fmt.Sprintf(format, a...))
+			return 0, nil
+		},
+		Println: func(a ...any) (int, error) {
+			*printed = append(*printed, fmt.Sprint(a...))
+			return 0, nil
+		},
+	}
+}
+
+func TestDiagnoseExportRepo_States(t *testing.T) {
+	require.Equal(t, exportRepoMissing, diagnoseExportRepo(filepath.Join(t.TempDir(), "none")))
+
+	exportDir := setupRepairRepo(t)
+	require.Equal(t, exportRepoClean, diagnoseExportRepo(exportDir))
+
+	mergeHead := filepath.Join(exportDir, ".git", "MERGE_HEAD")
+	require.NoError(t, os.WriteFile(mergeHead, []byte("0000000000000000000000000000000000000000\n"), 0600))
+	require.Equal(t, exportRepoMerging, diagnoseExportRepo(exportDir))
+}
+
+func TestExportRepair_CleanRepo(t *testing.T) {
+	exportDir := setupRepairRepo(t)
+	var printed []string
+
+	err := exportRepair(nil, dispatchers.NewParsedFlags(nil), repairDeps(exportDir, &printed))
+
+	require.NoError(t, err)
+	require.Contains(t, printed, "Nothing to repair")
+}
+
+func TestExportRepair_DetachedHeadKeepsRecords(t *testing.T) {
+	exportDir := setupRepairRepo(t)
+	csvPath := filepath.Join(exportDir, "commits.csv")
+
+	require.NoError(t, writeCSVSorted(csvPath, map[string][]string{"repo:aaa": repairRecord("aaa")}))
+	require.NoError(t, commitExportChanges(exportDir, []string{"commits.csv"}))
+	require.NoError(t, runGitInDir(exportDir, "checkout", "--detach"))
+
+	// A record written while detached must survive the repair
+	require.NoError(t, writeCSVSorted(csvPath, map[string][]string{
+		"repo:aaa": repairRecord("aaa"),
+		"repo:bbb": repairRecord("bbb"),
+	}))
+	require.Equal(t, exportRepoDetached, diagnoseExportRepo(exportDir))
+
+	var printed []string
+	err := exportRepair(nil, dispatchers.NewParsedFlags(nil), repairDeps(exportDir, &printed))
+
+	require.NoError(t, err)
+	require.Equal(t, exportRepoClean, diagnoseExportRepo(exportDir))
+
+	records, err := loadCSVRecords(csvPath)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+}
+
+func TestExportRepair_DryRunDoesNotChangeRepo(t *testing.T) {
+	exportDir := setupRepairRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(exportDir, "a.csv"), []byte("x"), 0600))
+	require.NoError(t, commitExportChanges(exportDir, []string{"a.csv"}))
+	require.NoError(t, runGitInDir(exportDir, "checkout", "--detach"))
+
+	var printed []string
+	err := exportRepair(nil, dispatchers.NewParsedFlags([]string{"--dry-run"}), repairDeps(exportDir, &printed))
+
+	require.NoError(t, err)
+	require.Equal(t, exportRepoDetached, diagnoseExportRepo(exportDir))
+}
+
+func TestExportRepair_Encrypted(t *testing.T) {
+	exportDir := encryptedExportSetup(t)
+	exportEncryptedEvent(t, exportDir, "abc123")
+	csvPath := filepath.Join(exportDir, "commits.csv")
+
+	trackedFiles := func() []string {
+		out, err := exec.Command("git", "-C", exportDir, "ls-files").Output()
+		require.NoError(t, err)
+		return strings.Fields(string(out))
+	}
+	encryptedRecords := func() map[string][]string {
+		plain, err := decryptExportFile(csvPath+".age", "~/key.txt")
+		require.NoError(t, err)
+		records := make(map[string][]string)
+		parseCSVIntoMap(string(plain), records)
+		return records
+	}
+
+	// A record written while detached is committed encrypted
+	require.NoError(t, runGitInDir(exportDir, "checkout", "--detach"))
+	records, err := loadCSVRecords(csvPath)
+	require.NoError(t, err)
+	records["repo:bbb"] = repairRecord("bbb")
+	require.NoError(t, writeCSVSorted(csvPath, records))
+
+	var printed []string
+	require.NoError(t, exportRepair(nil, dispatchers.NewParsedFlags(nil), repairDeps(exportDir, &printed)))
+	require.Equal(t, exportRepoClean, diagnoseExportRepo(exportDir))
+	require.Equal(t, []string{".gitignore", "commits.csv.age"}, trackedFiles())
+	require.Len(t, encryptedRecords(), 2)
+
+	// Re-created without a remote, the plaintext stays out of git
+	require.NoError(t, exportRepair(nil, dispatchers.NewParsedFlags([]string{"--force-reclone"}), repairDeps(exportDir, &printed)))
+	require.Equal(t, []string{".gitignore", "commits.csv.age"}, trackedFiles())
+	require.Len(t, encryptedRecords(), 2)
+}
+
+func TestExportRepair_ForceRecloneWithoutRemote(t *testing.T) {
+	exportDir := setupRepairRepo(t)
+	csvPath := filepath.Join(exportDir, "commits.csv")
+	require.NoError(t, writeCSVSorted(csvPath, map[string][]string{"repo:aaa": repairRecord("aaa")}))
+
+	var printed []string
+	err := exportRepair(nil, dispatchers.NewParsedFlags([]string{"--force-reclone"}), repairDeps(exportDir, &printed))
+
+	require.NoError(t, err)
+
+	records, err := loadCSVRecords(csvPath)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	// Old repo is kept aside
+	backups, _ := filepath.Glob(exportDir + ".broken-*")
+	require.Len(t, backups, 1)
+
+	cmd := exec.Command("git", "log", "--oneline")
+	cmd.Dir = exportDir
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	require.Contains(t, string(out), "Export 1 files")
+}
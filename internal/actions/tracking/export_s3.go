@@ -0,0 +1,226 @@
+package tracking
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+const (
+	// s3DefaultRegion is used when export_s3_region is unset.
+	s3DefaultRegion = "us-east-1"
+	// s3SigningAlgorithm is the AWS Signature Version 4 algorithm name.
+	s3SigningAlgorithm = "AWS4-HMAC-SHA256"
+)
+
+// s3Sink uploads each export run as one JSON Lines object. Credentials come
+// from the standard AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN environment variables.
+type s3Sink struct {
+	bucket   string
+	region   string
+	prefix   string
+	endpoint string // optional S3-compatible endpoint, path-style addressing
+}
+
+// newS3Sink builds the s3 sink from export_s3_* config.
+func newS3Sink() (s3Sink, error) {
+	bucket, _ := config.Get("export_s3_bucket")
+	region, _ := config.Get("export_s3_region")
+	prefix, _ := config.Get("export_s3_prefix")
+	endpoint, _ := config.Get("export_s3_endpoint")
+
+	sink := s3Sink{
+		bucket:   strings.TrimSpace(bucket),
+		region:   strings.TrimSpace(region),
+		prefix:   strings.Trim(strings.TrimSpace(prefix), "/"),
+		endpoint: strings.TrimRight(strings.TrimSpace(endpoint), "/"),
+	}
+	if sink.bucket == "" {
+		return s3Sink{}, fmt.Errorf("export sink 's3' needs export_s3_bucket\nHint: Run 'fp config set export_s3_bucket <bucket>'")
+	}
+	if sink.region == "" {
+		sink.region = s3DefaultRegion
+	}
+	if sink.endpoint != "" {
+		if err := validateEndpointURL("export_s3_endpoint", sink.endpoint); err != nil {
+			return s3Sink{}, err
+		}
+	}
+	return sink, nil
+}
+
+func (s3Sink) Name() string { return "s3" }
+
+func (s s3Sink) Destination() string {
+	if s.prefix == "" {
+		return "s3://" + s.bucket
+	}
+	return "s3://" + s.bucket + "/" + s.prefix
+}
+
+func (s s3Sink) Export(events []store.RepoEvent, deps Deps) (sinkResult, error) {
+	accessKey := deps.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := deps.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return sinkResult{}, fmt.Errorf("S3 export needs AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY in the environment")
+	}
+	sessionToken := deps.Getenv("AWS_SESSION_TOKEN")
+
+	sorted := append([]store.RepoEvent(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	redact := loadExportRedaction()
+	var body []byte
+	ids := make([]int64, 0, len(sorted))
+	for _, e := range sorted {
+		var meta git.CommitMetadata
+		if e.RepoPath != "" {
+			meta = deps.metas.Of(e)
+		}
+		body = appendJSONRecord(body, csvHeader, buildRecord(e, meta, deps.pushes[e.ID], redact))
+		body = append(body, '\n')
+		ids = append(ids, e.ID)
+	}
+
+	now := deps.Now().UTC()
+	target := s.objectURL(s.objectKey(now))
+
+	err := sendWithRetry(target, func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPut, target, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-ndjson")
+		signS3Request(req, body, accessKey, secretKey, sessionToken, s.region, now)
+		return req, nil
+	})
+	if err != nil {
+		log.Warn("export: S3 upload failed, %d events remain pending: %v", len(ids), err)
+		return sinkResult{}, err
+	}
+
+	return sinkResult{Delivered: ids, Written: len(ids), Pushed: true}, nil
+}
+
+// objectKey names the object for an export run: prefix/YYYY/MM/DD/<nanos>-<host>.jsonl.
+// The host keeps uploads from different machines apart.
+func (s s3Sink) objectKey(now time.Time) string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	name := fmt.Sprintf("%s/%d-%s.jsonl", now.Format("2006/01/02"), now.UnixNano(), host)
+	if s.prefix == "" {
+		return name
+	}
+	return s.prefix + "/" + name
+}
+
+// objectURL returns the URL for key, using virtual-hosted style for AWS
+// and path style for custom endpoints.
+func (s s3Sink) objectURL(key string) string {
+	if s.endpoint != "" {
+		return s.endpoint + "/" + s3EscapePath(s.bucket+"/"+key)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, s3EscapePath(key))
+}
+
+// signS3Request adds AWS Signature Version 4 headers to req.
+func signS3Request(req *http.Request, body []byte, accessKey, secretKey, sessionToken, region string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if sessionToken != "" {
+		headers["x-amz-security-token"] = sessionToken
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{s3SigningAlgorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+	signature := hex.EncodeToString(hmacSHA256(s3SigningKey(secretKey, date, region, "s3"), stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgorithm, accessKey, scope, signedHeaders, signature))
+}
+
+// s3SigningKey derives the SigV4 signing key for a date, region and service.
+func s3SigningKey(secretKey, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// s3EscapePath URI-encodes an object path the way SigV4 expects: every byte
+// except unreserved characters and slashes is percent-encoded.
+func s3EscapePath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,118 @@
+package tracking
+
+import (
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/daemon"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/output"
+)
+
+// Who performs the next automatic export.
+const (
+	exportRunnerDaemon = "daemon"
+	exportRunnerHooks  = "hooks"
+	exportRunnerNone   = "none"
+)
+
+// exportSchedule is when the next automatic export happens, worked out the
+// way maybeExport decides it.
+type exportSchedule struct {
+	SchemaVersion int        `json:"schema_version"`
+	IntervalSec   int        `json:"interval_sec"`
+	LastExport    *time.Time `json:"last_export,omitempty"`
+	NextExport    time.Time  `json:"next_export"`
+	Due           bool       `json:"due"`
+	Runner        string     `json:"runner"`
+}
+
+// ExportSchedule handles `fp export schedule`.
+func ExportSchedule(args []string, flags *dispatchers.ParsedFlags) error {
+	return exportScheduleCmd(args, flags, DefaultDeps())
+}
+
+func exportScheduleCmd(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	s := loadExportSchedule(deps.Now(), daemon.IsRunning(), config.IsReadOnly())
+	if flags.Has("--json") {
+		return output.JSON(deps.Println, s)
+	}
+
+	interval := time.Duration(s.IntervalSec) * time.Second
+	if s.IntervalSec <= 0 {
+		_, _ = deps.Println("Interval:    none, every new event is exported (export_interval_sec = 0)")
+	} else {
+		_, _ = deps.Printf("Interval:    every %s (export_interval_sec = %d)\n", shortDuration(interval), s.IntervalSec)
+	}
+
+	now := deps.Now()
+	if s.LastExport == nil {
+		_, _ = deps.Println("Last export: never")
+	} else {
+		_, _ = deps.Printf("Last export: %s (%s ago)\n", s.LastExport.Local().Format("2006-01-02 15:04"), shortDuration(now.Sub(*s.LastExport)))
+	}
+
+	switch {
+	case s.Runner == exportRunnerNone:
+		_, _ = deps.Println("Next export: off in read-only mode")
+	case s.Due:
+		_, _ = deps.Println("Next export: due now")
+	default:
+		_, _ = deps.Printf("Next export: %s (in %s)\n", s.NextExport.Local().Format("2006-01-02 15:04"), shortDuration(s.NextExport.Sub(now)))
+	}
+
+	switch s.Runner {
+	case exportRunnerDaemon:
+		_, _ = deps.Printf("Run by:      the daemon, which checks every %s\n", shortDuration(daemon.TickInterval))
+	case exportRunnerHooks:
+		_, _ = deps.Println("Run by:      the git hooks, at the first event recorded once it is due")
+		_, _ = deps.Println("             (start 'fp daemon start' to export without waiting for a commit)")
+	default:
+		_, _ = deps.Println("Run by:      nothing, fp is read-only (read_only = true)")
+	}
+	return nil
+}
+
+// loadExportSchedule combines export_interval_sec and export_last with
+// what runs exports: the daemon while it runs, else the hooks through
+// fp record. Read-only mode turns automatic exports off.
+func loadExportSchedule(now time.Time, daemonRunning, readOnly bool) exportSchedule {
+	interval, last := exportTiming()
+	s := exportSchedule{
+		SchemaVersion: output.SchemaVersion,
+		IntervalSec:   int(interval / time.Second),
+		NextExport:    last.Add(interval).UTC(),
+		Runner:        exportRunnerHooks,
+	}
+	if last.Unix() > 0 {
+		t := last.UTC()
+		s.LastExport = &t
+	}
+	s.Due = !now.Before(s.NextExport)
+	if s.Due {
+		s.NextExport = now.UTC().Truncate(time.Second)
+	}
+
+	switch {
+	case readOnly:
+		s.Runner = exportRunnerNone
+	case daemonRunning:
+		s.Runner = exportRunnerDaemon
+	}
+	return s
+}
+
+// shortDuration formats d to the minute, without the zero units
+// time.Duration prints: "1h", "1h30m", "45m". Under a minute is "<1m".
+func shortDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Minute {
+		return "<1m"
+	}
+	s := strings.TrimSuffix(d.String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,62 @@
+package tracking
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+)
+
+func TestLoadExportSchedule(t *testing.T) {
+	last := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	setRedactionConfig(t, "export_interval_sec", "5400", "export_last", "1772445600")
+
+	s := loadExportSchedule(last.Add(time.Hour), false, false)
+	require.Equal(t, 5400, s.IntervalSec)
+	require.Equal(t, last, *s.LastExport)
+	require.Equal(t, last.Add(90*time.Minute), s.NextExport)
+	require.False(t, s.Due)
+	require.Equal(t, exportRunnerHooks, s.Runner)
+
+	now := last.Add(2 * time.Hour)
+	s = loadExportSchedule(now, true, false)
+	require.True(t, s.Due)
+	require.Equal(t, now, s.NextExport, "an overdue export is due now")
+	require.Equal(t, exportRunnerDaemon, s.Runner)
+
+	require.Equal(t, exportRunnerNone, loadExportSchedule(now, true, true).Runner, "read-only mode doesn't export")
+}
+
+func TestLoadExportSchedule_NeverExported(t *testing.T) {
+	setRedactionConfig(t, "export_interval_sec", "3600")
+
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	s := loadExportSchedule(now, false, false)
+	require.Nil(t, s.LastExport)
+	require.True(t, s.Due)
+}
+
+func TestExportScheduleCmd(t *testing.T) {
+	setRedactionConfig(t, "export_interval_sec", "3600", "export_last", "1772445600")
+
+	var out strings.Builder
+	deps := statusTestDeps("", &out)
+	deps.Now = func() time.Time { return time.Unix(1772445600, 0).Add(20 * time.Minute) }
+	require.NoError(t, exportScheduleCmd(nil, dispatchers.NewParsedFlags(nil), deps))
+
+	text := out.String()
+	require.Contains(t, text, "every 1h (export_interval_sec = 3600)")
+	require.Contains(t, text, "(20m ago)")
+	require.Contains(t, text, "(in 40m)")
+}
+
+func TestShortDuration(t *testing.T) {
+	require.Equal(t, "<1m", shortDuration(20*time.Second))
+	require.Equal(t, "45m", shortDuration(45*time.Minute))
+	require.Equal(t, "1h", shortDuration(time.Hour))
+	require.Equal(t, "1h30m", shortDuration(90*time.Minute))
+	require.Equal(t, "26h", shortDuration(26*time.Hour))
+}
@@ -0,0 +1,273 @@
+package tracking
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// exportSink is a destination for exported events.
+type exportSink interface {
+	// Name identifies the sink in export_sinks and delivery records.
+	Name() string
+	// Destination is shown to the user after an export (a path or URL).
+	Destination() string
+	// Export sends events to the sink. On error, result.Delivered still
+	// lists any events that were accepted before the failure.
+	Export(events []store.RepoEvent, deps Deps) (sinkResult, error)
+}
+
+// sinkResult describes what a sink did with a batch of events.
+type sinkResult struct {
+	// Delivered are the events the sink has durably accepted
+	Delivered []int64
+	// Written counts events written, even if not delivered yet
+	// (e.g. committed to the export repo but not pushed)
+	Written int
+	// Pushed reports whether the events left this machine
+	// (git pushed to its remote, or an upload succeeded)
+	Pushed bool
+	// Remotes has the outcome of each git push, the primary remote first
+	Remotes []remotePush
+}
+
+// exportSinkNames lists the sink names accepted in export_sinks.
+func exportSinkNames() []string {
+	return []string{"git", "http", "s3", "stdout"}
+}
+
+// resolveExportSinks builds the sinks listed in export_sinks. When
+// export_sinks is unset, events go to the HTTP endpoint if export_http_url
+// is set and to the git export repo otherwise. Automatic exports (hooks,
+// daemon) skip the stdout sink since nobody is reading their output.
+func resolveExportSinks(format exportFormat, automatic bool) ([]exportSink, error) {
+	value, _ := config.Get("export_sinks")
+	names := parseSinkList(value)
+	if len(names) == 0 {
+		if httpExportURL() != "" {
+			names = []string{"http"}
+		} else {
+			names = []string{"git"}
+		}
+	}
+
+	var sinks []exportSink
+	for _, name := range names {
+		switch name {
+		case "git":
+			sinks = append(sinks, gitCSVSink{format: format})
+		case "http":
+			endpoint := httpExportURL()
+			if endpoint == "" {
+				return nil, fmt.Errorf("export sink 'http' needs export_http_url\nHint: Run 'fp config set export_http_url https://...'")
+			}
+			if err := validateHTTPExportURL(endpoint); err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, httpSink{endpoint: endpoint})
+		case "s3":
+			sink, err := newS3Sink()
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case "stdout":
+			if !automatic {
+				sinks = append(sinks, stdoutSink{})
+			}
+		default:
+			return nil, fmt.Errorf("invalid export sink '%s': expected one of %s", name, strings.Join(exportSinkNames(), ", "))
+		}
+	}
+	return sinks, nil
+}
+
+// parseSinkList splits a comma-separated sink list, dropping blanks and duplicates.
+func parseSinkList(value string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, part := range strings.Split(value, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// sinkDestinations joins the destinations of sinks for display.
+func sinkDestinations(sinks []exportSink) string {
+	dests := make([]string, 0, len(sinks))
+	for _, s := range sinks {
+		dests = append(dests, s.Destination())
+	}
+	return strings.Join(dests, ", ")
+}
+
+// hasSink reports whether sinks include the named sink.
+func hasSink(sinks []exportSink, name string) bool {
+	for _, s := range sinks {
+		if s.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// gitCSVSink writes events to CSV files in the export repo (plus the
+// selected format, if different), commits, and pushes if there is a remote.
+type gitCSVSink struct {
+	format exportFormat
+}
+
+func (gitCSVSink) Name() string { return "git" }
+
+func (gitCSVSink) Destination() string { return getExportRepo() }
+
+func (s gitCSVSink) Export(events []store.RepoEvent, deps Deps) (sinkResult, error) {
+	exportRepo := deps.GetExportRepo()
+
+	if err := ensureExportRepo(exportRepo); err != nil {
+		return sinkResult{}, fmt.Errorf("could not initialize export repo: %w", err)
+	}
+
+	// Check for incomplete merge/rebase state before proceeding
+	if err := checkGitState(exportRepo); err != nil {
+		return sinkResult{}, err
+	}
+
+	// Sync with remote before writing (offline mode: continue if pull fails)
+	if deps.HasRemote(exportRepo) {
+		exportStep(deps, "Pulling the export repo")
+		if err := deps.PullExportRepo(exportRepo); err != nil {
+			log.Warn("export: could not sync with remote, continuing offline: %v", err)
+		}
+	}
+
+	enc := exportEncryptionFromConfig()
+	if enc != nil {
+		if err := syncEncryptedExports(exportRepo, enc); err != nil {
+			return sinkResult{}, err
+		}
+	}
+
+	exportedIDs, exportedFiles, err := exportAllEvents(exportRepo, events, deps)
+	if err != nil {
+		return sinkResult{}, fmt.Errorf("could not export events: %w", err)
+	}
+
+	if len(exportedFiles) == 0 {
+		return sinkResult{}, nil
+	}
+
+	exportStep(deps, "Committing %d %s", len(exportedFiles), pluralize(len(exportedFiles), "file", "files"))
+	if err := commitExportFiles(exportRepo, exportedFiles, s.format, deps.Now()); err != nil {
+		return sinkResult{}, err
+	}
+
+	result := sinkResult{Written: len(exportedIDs)}
+
+	// Try to push if remote exists, then to the mirrors whatever happened
+	primaryFailed := false
+	if deps.HasRemote(exportRepo) {
+		primary := remotePush{Remote: primaryRemote, Primary: true}
+		exportStep(deps, "Pushing to %s", primaryRemote)
+		if err := deps.PushExportRepo(exportRepo); err != nil {
+			// Push failed - don't report events as delivered so they'll be retried
+			log.Warn("export: failed to push to remote, events will remain pending: %v", err)
+			primary.Error = err.Error()
+			primary.Hint = pushErrorHint(err)
+			primaryFailed = true
+		} else {
+			primary.Pushed = true
+			result.Pushed = true
+		}
+		result.Remotes = append(result.Remotes, primary)
+	}
+	if deps.PushExportMirrors != nil {
+		result.Remotes = append(result.Remotes, deps.PushExportMirrors(exportRepo)...)
+	}
+	if primaryFailed {
+		return result, nil
+	}
+
+	// Only delivered after successful push (or if no remote)
+	result.Delivered = exportedIDs
+	return result, nil
+}
+
+// commitExportFiles commits CSV files written to the export repo, together
+// with their copies in format, the README and, with export_encrypt_recipient
+// set, encrypted in place of the plaintext.
+func commitExportFiles(exportRepo string, csvFiles []string, format exportFormat, now time.Time) error {
+	if format == nil {
+		format = exportFormats[defaultExportFormat]
+	}
+	index := loadExportIndex(exportRepo)
+	defer index.save(exportRepo)
+
+	files := slices.Clone(csvFiles)
+	derivedFiles, err := writeDerivedExports(exportRepo, csvFiles, format, index)
+	if err != nil {
+		return fmt.Errorf("could not export events as %s: %w", format.Name(), err)
+	}
+	files = append(files, derivedFiles...)
+
+	enc := exportEncryptionFromConfig()
+	if readme, err := writeExportReadme(exportRepo, index, now); err != nil {
+		log.Warn("export: could not update %s: %v", exportReadmeName, err)
+	} else if enc == nil {
+		files = append(files, readme)
+	}
+
+	if enc != nil {
+		if files, err = encryptExportFiles(exportRepo, files, enc); err != nil {
+			return err
+		}
+		for _, rel := range csvFiles {
+			index.encrypted(exportRepo, rel, enc.Extension())
+		}
+	}
+
+	if err := commitExportChanges(exportRepo, files); err != nil {
+		return fmt.Errorf("could not commit export: %w", err)
+	}
+	return nil
+}
+
+// stdoutSink prints events as JSON lines, for piping `fp export` into other tools.
+type stdoutSink struct{}
+
+func (stdoutSink) Name() string        { return "stdout" }
+func (stdoutSink) Destination() string { return "stdout" }
+
+func (stdoutSink) Export(events []store.RepoEvent, deps Deps) (sinkResult, error) {
+	sorted := append([]store.RepoEvent(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	redact := loadExportRedaction()
+	result := sinkResult{}
+	for _, e := range sorted {
+		var meta git.CommitMetadata
+		if e.RepoPath != "" {
+			meta = deps.metas.Of(e)
+		}
+		if _, err := deps.Println(string(appendJSONRecord(nil, csvHeader, buildRecord(e, meta, deps.pushes[e.ID], redact)))); err != nil {
+			return result, err
+		}
+		result.Delivered = append(result.Delivered, e.ID)
+		result.Written++
+	}
+	return result, nil
+}
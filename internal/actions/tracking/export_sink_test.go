@@ -0,0 +1,233 @@
+package tracking
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSink records the events it receives and fails while fail is set.
+type fakeSink struct {
+	name     string
+	fail     bool
+	received *[][]int64
+}
+
+func (f fakeSink) Name() string        { return f.name }
+func (f fakeSink) Destination() string { return f.name }
+
+func (f fakeSink) Export(events []store.RepoEvent, _ Deps) (sinkResult, error) {
+	ids := eventIDs(events)
+	*f.received = append(*f.received, ids)
+	if f.fail {
+		return sinkResult{}, errors.New(f.name + " unavailable")
+	}
+	return sinkResult{Delivered: ids, Written: len(ids)}, nil
+}
+
+func setupSinkStore(t *testing.T, events int) *store.Store {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	s, err := store.New(filepath.Join(t.TempDir(), "store.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	for i := range events {
+		require.NoError(t, store.InsertEvent(s.DB(), store.RepoEvent{
+			RepoID:    "github.com/test/repo",
+			Commit:    "commit" + string(rune('a'+i)),
+			Branch:    "main",
+			Timestamp: time.Date(2025, 1, 1, 0, 0, i, 0, time.UTC),
+			Status:    store.StatusPending,
+			Source:    store.SourcePostCommit,
+		}))
+	}
+	return s
+}
+
+func TestDoExportWork_FailingSinkDoesNotBlockOthers(t *testing.T) {
+	s := setupSinkStore(t, 2)
+	events, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+
+	var gitCalls, httpCalls [][]int64
+	sinks := []exportSink{
+		fakeSink{name: "git", received: &gitCalls},
+		fakeSink{name: "http", fail: true, received: &httpCalls},
+	}
+
+	outcome, err := doExportWork(s.DB(), events, sinks, Deps{Now: time.Now})
+	count := outcome.Written
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "http unavailable")
+	require.Equal(t, 2, count, "git sink should still have written events")
+
+	// Events stay pending until every sink has them
+	pending, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+
+	delivered, err := store.DeliveredTo(s.DB(), eventIDs(events), "git")
+	require.NoError(t, err)
+	require.Len(t, delivered, 2)
+
+	// Next run: only the failed sink is retried
+	sinks[1] = fakeSink{name: "http", received: &httpCalls}
+	_, err = doExportWork(s.DB(), pending, sinks, Deps{Now: time.Now})
+	require.NoError(t, err)
+	require.Len(t, gitCalls, 1, "git should not receive events it already has")
+	require.Len(t, httpCalls, 2)
+
+	pending, err = store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+	require.Empty(t, pending)
+
+	delivered, err = store.DeliveredTo(s.DB(), eventIDs(events), "git")
+	require.NoError(t, err)
+	require.Empty(t, delivered, "delivery records should be cleared once exported")
+}
+
+func TestDoExportWork_NoSinks(t *testing.T) {
+	s := setupSinkStore(t, 1)
+	events, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+
+	outcome, err := doExportWork(s.DB(), events, nil, Deps{Now: time.Now})
+	count := outcome.Written
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	pending, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+}
+
+func TestResolveExportSinks(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    map[string]string
+		automatic bool
+		want      []string
+		wantErr   string
+	}{
+		{name: "default", want: []string{"git"}},
+		{name: "http url implies http", config: map[string]string{"export_http_url": "https://example.com/in"}, want: []string{"http"}},
+		{name: "list", config: map[string]string{"export_sinks": "git, http,git", "export_http_url": "https://example.com/in"}, want: []string{"git", "http"}},
+		{name: "stdout", config: map[string]string{"export_sinks": "stdout"}, want: []string{"stdout"}},
+		{name: "automatic skips stdout", config: map[string]string{"export_sinks": "git,stdout"}, automatic: true, want: []string{"git"}},
+		{name: "s3", config: map[string]string{"export_sinks": "s3", "export_s3_bucket": "footprints"}, want: []string{"s3"}},
+		{name: "unknown", config: map[string]string{"export_sinks": "git,ftp"}, wantErr: "invalid export sink 'ftp'"},
+		{name: "http without url", config: map[string]string{"export_sinks": "http"}, wantErr: "export_http_url\nHint:"},
+		{name: "s3 without bucket", config: map[string]string{"export_sinks": "s3"}, wantErr: "export_s3_bucket"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			var lines []string
+			for k, v := range tt.config {
+				lines, _ = config.Set(lines, k, v)
+			}
+			require.NoError(t, config.WriteLines(lines))
+
+			sinks, err := resolveExportSinks(csvExportFormat{}, tt.automatic)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var names []string
+			for _, s := range sinks {
+				names = append(names, s.Name())
+			}
+			require.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestStdoutSink_PrintsJSONLines(t *testing.T) {
+	s := setupSinkStore(t, 2)
+	events, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+
+	var lines []string
+	deps := Deps{Println: func(a ...any) (int, error) {
+		lines = append(lines, a[0].(string))
+		return 0, nil
+	}}
+
+	result, err := stdoutSink{}.Export(events, deps)
+	require.NoError(t, err)
+	require.Len(t, result.Delivered, 2)
+	require.Len(t, lines, 2)
+
+	var first map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.Equal(t, "commita", first["commit_hash"])
+}
+
+func TestS3SigningKey(t *testing.T) {
+	// Example from the AWS Signature Version 4 documentation
+	key := s3SigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20150830", "us-east-1", "iam")
+	require.Equal(t, "c4afb1cc5771d871763a393e44b703571b55cc28424d1a5e86da6ed3c154a4b9", hex.EncodeToString(key))
+}
+
+func TestS3Sink_UploadsSignedObject(t *testing.T) {
+	var path, auth, contentHash string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		contentHash = r.Header.Get("X-Amz-Content-Sha256")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	s := setupSinkStore(t, 2)
+	events, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+
+	env := map[string]string{"AWS_ACCESS_KEY_ID": "AKIDEXAMPLE", "AWS_SECRET_ACCESS_KEY": "secret"}
+	deps := Deps{
+		Now:    func() time.Time { return time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC) },
+		Getenv: func(k string) string { return env[k] },
+	}
+
+	sink := s3Sink{bucket: "footprints", region: "eu-west-1", prefix: "team", endpoint: server.URL}
+	result, err := sink.Export(events, deps)
+	require.NoError(t, err)
+	require.Len(t, result.Delivered, 2)
+
+	require.True(t, strings.HasPrefix(path, "/footprints/team/2025/03/04/"), path)
+	require.True(t, strings.HasSuffix(path, ".jsonl"), path)
+	require.Contains(t, auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20250304/eu-west-1/s3/aws4_request")
+	require.Contains(t, auth, "SignedHeaders=host;x-amz-content-sha256;x-amz-date")
+	require.Equal(t, sha256Hex(body), contentHash)
+	require.Equal(t, 2, strings.Count(string(body), "\n"))
+}
+
+func TestS3Sink_MissingCredentials(t *testing.T) {
+	s := setupSinkStore(t, 1)
+	events, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+
+	deps := Deps{Now: time.Now, Getenv: func(string) string { return "" }}
+	_, err = s3Sink{bucket: "footprints", region: s3DefaultRegion}.Export(events, deps)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "AWS_ACCESS_KEY_ID")
+}
@@ -0,0 +1,233 @@
+package tracking
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/app"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// snapshotSchemaVersion is stored as the snapshot's user_version, so
+// readers can tell layouts apart if the table changes.
+const snapshotSchemaVersion = 1
+
+// snapshotIntColumns are the CSV columns stored as integers in a snapshot.
+var snapshotIntColumns = []string{"files_changed", "insertions", "deletions"}
+
+// snapshotIndexedColumns get an index each when they are exported.
+var snapshotIndexedColumns = []string{"timestamp", "repo_id", "commit_hash"}
+
+// snapshotFilterFlags only apply to fp export --sqlite.
+var snapshotFilterFlags = []string{"--since", "--until", "--repo"}
+
+// errSnapshotPath is returned when --sqlite is given without a file.
+var errSnapshotPath = errors.New("--sqlite needs a file to write, e.g. --sqlite footprint-snapshot.db")
+
+// exportSnapshot writes the events matching the filter flags to a new
+// SQLite file at path: one row per commit in the columns of the CSV export,
+// with the same redaction applied. Unlike a regular export it takes every
+// event, not just pending ones, and marks nothing as exported.
+func exportSnapshot(db *sql.DB, path string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	jsonOutput := flags.Has("--json")
+
+	var filter store.EventFilter
+	if sinceStr := flags.String("--since", ""); sinceStr != "" {
+		if filter.Since = flags.Date("--since"); filter.Since == nil {
+			return fmt.Errorf("invalid date '%s' for --since: expected %s", sinceStr, dispatchers.DateFormats)
+		}
+	}
+	if untilStr := flags.String("--until", ""); untilStr != "" {
+		if filter.Until = flags.Date("--until"); filter.Until == nil {
+			return fmt.Errorf("invalid date '%s' for --until: expected %s", untilStr, dispatchers.DateFormats)
+		}
+	}
+	if repoID := flags.String("--repo", ""); repoID != "" {
+		filter.RepoID = &repoID
+	}
+
+	events, err := store.ListEvents(db, filter)
+	if err != nil {
+		return fmt.Errorf("could not list events: %w", err)
+	}
+	if flags.Has("--mine") {
+		identities := loadIdentityMatcher()
+		if identities == nil {
+			return errNoIdentities
+		}
+		events = identities.Filter(events)
+	}
+	redact := loadExportRedaction()
+	events = slices.DeleteFunc(events, func(e store.RepoEvent) bool { return redact.Excludes(e.RepoID) })
+
+	columns := exportColumns()
+	metas := newCommitMetaReader(db)
+	metas.Prefetch(events)
+	pushes := loadExportPushes(db, events)
+	records := make(map[string][]string, len(events))
+	for _, e := range events {
+		var meta git.CommitMetadata
+		if e.RepoPath != "" {
+			meta = metas.Of(e)
+		}
+		record := buildRecord(e, meta, pushes[e.ID], redact)
+		records[recordKey(record, columns)] = record
+	}
+	rows := make([][]string, 0, len(records))
+	for _, record := range records {
+		rows = append(rows, record)
+	}
+	sortRecordsByTime(rows)
+
+	if err := writeSnapshot(path, columns, rows, deps.Now()); err != nil {
+		return fmt.Errorf("could not write snapshot %s: %w", path, err)
+	}
+
+	if jsonOutput {
+		return exportResultJSON(len(rows), path, false, nil, deps)
+	}
+	_, _ = deps.Printf("Wrote %d %s to %s\n", len(rows), pluralize(len(rows), "commit", "commits"), path)
+	return nil
+}
+
+// checkSnapshotFlags rejects the snapshot filters when --sqlite isn't set,
+// rather than exporting everything pending as if they weren't there.
+func checkSnapshotFlags(flags *dispatchers.ParsedFlags) error {
+	for _, name := range snapshotFilterFlags {
+		if flags.String(name, "") != "" {
+			return fmt.Errorf("%s only applies to snapshots: use it with --sqlite <file>", name)
+		}
+	}
+	return nil
+}
+
+// sortRecordsByTime orders records by timestamp, then commit, as the CSV
+// files are.
+func sortRecordsByTime(rows [][]string) {
+	ts := slices.Index(csvHeader, "timestamp")
+	commit := slices.Index(csvHeader, "commit_hash")
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i][ts] != rows[j][ts] {
+			return rows[i][ts] < rows[j][ts]
+		}
+		return rows[i][commit] < rows[j][commit]
+	})
+}
+
+// writeSnapshot builds the snapshot in a temp file next to path, compacts
+// it, and moves it into place read-only. An existing file at path is
+// replaced.
+func writeSnapshot(path string, columns []string, rows [][]string, now time.Time) error {
+	dir := filepath.Dir(path)
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tempPath := file.Name()
+	_ = file.Close()
+
+	if err := fillSnapshot(tempPath, columns, rows, now); err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+	if err := os.Chmod(tempPath, 0o444); err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+func fillSnapshot(path string, columns []string, rows [][]string, now time.Time) (err error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := db.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	defs := make([]string, len(columns))
+	for i, col := range columns {
+		kind := "TEXT"
+		if slices.Contains(snapshotIntColumns, col) {
+			kind = "INTEGER"
+		}
+		defs[i] = col + " " + kind
+	}
+	stmts := []string{
+		"CREATE TABLE events (" + strings.Join(defs, ", ") + ")",
+		"CREATE TABLE snapshot (key TEXT PRIMARY KEY, value TEXT NOT NULL)",
+	}
+	for _, col := range snapshotIndexedColumns {
+		if slices.Contains(columns, col) {
+			stmts = append(stmts, fmt.Sprintf("CREATE INDEX idx_events_%s ON events(%s)", col, col))
+		}
+	}
+	stmts = append(stmts, "PRAGMA user_version = "+strconv.Itoa(snapshotSchemaVersion))
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	insert, err := tx.Prepare("INSERT INTO events (" + strings.Join(columns, ", ") + ") VALUES (" +
+		strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")")
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	for _, row := range rows {
+		values := make([]any, len(columns))
+		for i, field := range projectRecord(row, columns) {
+			values[i] = field
+			if slices.Contains(snapshotIntColumns, columns[i]) {
+				values[i], _ = strconv.Atoi(field)
+			}
+		}
+		if _, err := insert.Exec(values...); err != nil {
+			_ = insert.Close()
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	_ = insert.Close()
+
+	meta := [][2]string{
+		{"created_at", now.UTC().Format(time.RFC3339)},
+		{"fp_version", app.Version},
+		{"rows", strconv.Itoa(len(rows))},
+	}
+	for _, kv := range meta {
+		if _, err := tx.Exec("INSERT INTO snapshot (key, value) VALUES (?, ?)", kv[0], kv[1]); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	_, err = db.Exec("VACUUM")
+	return err
+}
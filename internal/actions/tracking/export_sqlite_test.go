@@ -0,0 +1,72 @@
+package tracking
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestExportSnapshot(t *testing.T) {
+	setRedactionConfig(t, "export_exclude_repos", "github.com/acme/*")
+	db := newTestStore(t).DB()
+	day := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	for _, e := range []store.RepoEvent{
+		{RepoID: "github.com/me/app", Commit: "aaaaaaa", Branch: "main", Timestamp: day, Source: store.SourcePostCommit},
+		{RepoID: "github.com/me/app", Commit: "aaaaaaa", Branch: "main", Timestamp: day.Add(time.Minute), Source: store.SourcePrePush},
+		{RepoID: "github.com/me/app", Commit: "bbbbbbb", Branch: "main", Timestamp: day.AddDate(0, 0, -10), Source: store.SourcePostCommit},
+		{RepoID: "github.com/acme/payroll", Commit: "ccccccc", Branch: "main", Timestamp: day, Source: store.SourcePostCommit},
+	} {
+		e.Status = store.StatusPending
+		require.NoError(t, store.InsertEvent(db, e))
+	}
+
+	var out strings.Builder
+	deps := statusTestDeps("", &out)
+	deps.Now = func() time.Time { return day }
+	path := filepath.Join(t.TempDir(), "snapshot.db")
+
+	flags := dispatchers.NewParsedFlags([]string{"--since=2026-03-01"})
+	require.NoError(t, exportSnapshot(db, path, flags, deps))
+	require.Contains(t, out.String(), "Wrote 1 commit to "+path)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o444), info.Mode().Perm())
+
+	snap, err := sql.Open("sqlite3", path+"?mode=ro")
+	require.NoError(t, err)
+	defer func() { _ = snap.Close() }()
+
+	var repo, commit string
+	var files int
+	require.NoError(t, snap.QueryRow("SELECT repo_id, commit_hash, files_changed FROM events").Scan(&repo, &commit, &files))
+	require.Equal(t, "github.com/me/app", repo)
+	require.Equal(t, "aaaaaaa", commit, "one row per commit, events before --since left out")
+
+	var indexes int
+	require.NoError(t, snap.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND tbl_name = 'events'").Scan(&indexes))
+	require.Equal(t, len(snapshotIndexedColumns), indexes)
+
+	var created string
+	require.NoError(t, snap.QueryRow("SELECT value FROM snapshot WHERE key = 'created_at'").Scan(&created))
+	require.Equal(t, "2026-03-02T10:00:00Z", created)
+
+	pending, err := store.GetPendingEvents(db)
+	require.NoError(t, err)
+	require.Len(t, pending, 4, "a snapshot doesn't count as an export")
+}
+
+func TestCheckSnapshotFlags(t *testing.T) {
+	require.NoError(t, checkSnapshotFlags(dispatchers.NewParsedFlags([]string{"--now"})))
+
+	err := checkSnapshotFlags(dispatchers.NewParsedFlags([]string{"--repo=github.com/me/app"}))
+	require.ErrorContains(t, err, "--repo only applies to snapshots")
+}
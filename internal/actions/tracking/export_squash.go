@@ -0,0 +1,228 @@
+package tracking
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// squashResult is the outcome of rewriting the export repo's history into
+// a single snapshot commit.
+type squashResult struct {
+	Commits  int          `json:"commits"`
+	Snapshot string       `json:"snapshot,omitempty"`
+	Remotes  []remotePush `json:"remotes,omitempty"`
+}
+
+// exportSquash handles fp export --squash.
+func exportSquash(exportRepo string, dryRun, jsonOutput bool, deps Deps) error {
+	if state := diagnoseExportRepo(exportRepo); state != exportRepoClean {
+		if state == exportRepoMissing {
+			return fmt.Errorf("no export repository at %s yet", exportRepo)
+		}
+		return fmt.Errorf("export repo has %s; run 'fp export repair'", state)
+	}
+
+	if dryRun {
+		commits := countExportCommits(exportRepo)
+		if jsonOutput {
+			return output.JSON(deps.Println, squashResult{Commits: commits})
+		}
+		if commits <= 1 {
+			_, _ = deps.Println("Export history is already a single commit")
+			return nil
+		}
+		_, _ = deps.Printf("Would squash %d commits into one snapshot commit", commits)
+		if hasRemote(exportRepo) {
+			_, _ = deps.Printf(" and force-push it to %s", strings.Join(squashRemotes(exportRepo), ", "))
+		}
+		_, _ = deps.Println()
+		return nil
+	}
+
+	if config.IsReadOnly() {
+		return usage.ReadOnly("fp export --squash")
+	}
+
+	result, err := squashExportRepo(exportRepo, deps.Now())
+	if jsonOutput {
+		if err != nil {
+			return output.JSONError(deps.Println, "squash_failed", err.Error())
+		}
+		return output.JSON(deps.Println, result)
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.Snapshot == "" {
+		_, _ = deps.Println("Export history is already a single commit")
+		return nil
+	}
+	_, _ = deps.Printf("Squashed %d commits into snapshot %.7s\n", result.Commits, result.Snapshot)
+	printRemotePushes(result.Remotes, deps)
+	return nil
+}
+
+// squashExportRepo replaces the export branch's history with one commit
+// holding its current files, so the sync repo stops growing by a commit
+// per export. With a remote, it first pulls so no other machine's export
+// is lost, then force-pushes with a lease on what it pulled and checks the
+// remote now points at the snapshot. If the primary remote doesn't take
+// it, the local branch is put back. Mirrors get the snapshot too; one
+// that fails catches up on a later squash.
+func squashExportRepo(exportRepo string, now time.Time) (squashResult, error) {
+	branch := exportBranch(exportRepo)
+	if branch == "" {
+		return squashResult{}, fmt.Errorf("export repo has no commits to squash")
+	}
+
+	remote := hasRemote(exportRepo)
+	if remote {
+		if err := pullExportRepo(exportRepo); err != nil {
+			return squashResult{}, fmt.Errorf("could not pull before squashing: %w", err)
+		}
+	}
+
+	result := squashResult{Commits: countExportCommits(exportRepo)}
+	if result.Commits <= 1 {
+		return result, nil
+	}
+
+	old, err := gitOutputInDir(exportRepo, "rev-parse", "HEAD")
+	if err != nil {
+		return squashResult{}, err
+	}
+	lease, _ := gitOutputInDir(exportRepo, "rev-parse", "--verify", "--quiet", "refs/remotes/"+primaryRemote+"/"+branch)
+
+	msg := fmt.Sprintf("Snapshot of %d export commits as of %s", result.Commits, now.UTC().Format("2006-01-02"))
+	snapshot, err := gitOutputInDir(exportRepo, "commit-tree", "HEAD^{tree}", "-m", msg)
+	if err != nil {
+		return squashResult{}, err
+	}
+	// Only move the branch if nothing committed to it in the meantime
+	if err := runGitInDir(exportRepo, "update-ref", "refs/heads/"+branch, snapshot, old); err != nil {
+		return squashResult{}, err
+	}
+	result.Snapshot = snapshot
+
+	if remote {
+		push := remotePush{Remote: primaryRemote, Primary: true}
+		if err := forcePushSnapshot(exportRepo, primaryRemote, branch, snapshot, lease); err != nil {
+			if resetErr := runGitInDir(exportRepo, "update-ref", "refs/heads/"+branch, old, snapshot); resetErr != nil {
+				log.Error("export: could not restore %s after failed squash: %v", branch, resetErr)
+			}
+			return squashResult{}, fmt.Errorf("remote didn't take the squashed history, local history kept: %w", err)
+		}
+		push.Pushed = true
+		result.Remotes = append(result.Remotes, push)
+
+		for _, m := range loadExportMirrors() {
+			push := remotePush{Remote: m.Name}
+			mirrorLease, _ := remoteBranchHead(exportRepo, m.Name, branch)
+			if err := forcePushSnapshot(exportRepo, m.Name, branch, snapshot, mirrorLease); err != nil {
+				log.Warn("export: failed to push squashed history to mirror %s: %v", m.Name, err)
+				push.Error = err.Error()
+			} else {
+				push.Pushed = true
+			}
+			result.Remotes = append(result.Remotes, push)
+		}
+	}
+
+	// Drop the old commits locally as well
+	_ = runGitInDir(exportRepo, "reflog", "expire", "--expire=now", "--all")
+	_ = runGitInDir(exportRepo, "gc", "--prune=now", "--quiet")
+
+	log.Info("export: squashed %d commits into %.7s", result.Commits, snapshot)
+	return result, nil
+}
+
+// forcePushSnapshot force-pushes snapshot to branch on remote, as long as
+// the remote branch is still at lease ("" when it shouldn't exist), then
+// reads it back to check the remote kept it.
+func forcePushSnapshot(exportRepo, remote, branch, snapshot, lease string) error {
+	ref := "refs/heads/" + branch
+	if err := runGitInDir(exportRepo, "push", "--force-with-lease="+ref+":"+lease, remote, snapshot+":"+ref); err != nil {
+		return err
+	}
+	head, err := remoteBranchHead(exportRepo, remote, branch)
+	if err != nil {
+		return fmt.Errorf("could not verify push: %w", err)
+	}
+	if head != snapshot {
+		return fmt.Errorf("%s has %.7s after the push, expected %.7s", remote, head, snapshot)
+	}
+	return nil
+}
+
+// remoteBranchHead asks remote which commit branch points to, or "" when
+// it has no such branch.
+func remoteBranchHead(exportRepo, remote, branch string) (string, error) {
+	out, err := gitOutputInDir(exportRepo, "ls-remote", remote, "refs/heads/"+branch)
+	if err != nil {
+		return "", err
+	}
+	head, _, _ := strings.Cut(out, "\t")
+	return head, nil
+}
+
+// squashRemotes names the remotes a squash force-pushes to.
+func squashRemotes(exportRepo string) []string {
+	names := []string{primaryRemote}
+	for _, m := range loadExportMirrors() {
+		names = append(names, m.Name)
+	}
+	return names
+}
+
+// countExportCommits counts the commits on the export repo's HEAD.
+func countExportCommits(exportRepo string) int {
+	out, err := gitOutputInDir(exportRepo, "rev-list", "--count", "HEAD")
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(out)
+	return n
+}
+
+// oldestExportCommit is when the first commit on the export repo's HEAD
+// was made, or the zero time when there are none.
+func oldestExportCommit(exportRepo string) time.Time {
+	out, err := gitOutputInDir(exportRepo, "log", "--max-parents=0", "--format=%ct", "HEAD")
+	if err != nil {
+		return time.Time{}
+	}
+	var oldest time.Time
+	for _, line := range strings.Fields(out) {
+		ts, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			continue
+		}
+		if t := time.Unix(ts, 0); oldest.IsZero() || t.Before(oldest) {
+			oldest = t
+		}
+	}
+	return oldest
+}
+
+// gitOutputInDir runs git in dir and returns its trimmed standard output.
+func gitOutputInDir(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %w\n%s", args[0], err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,113 @@
+package tracking
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+)
+
+// setupSquashRepo creates an export repo with commits commits, pushed to
+// a bare origin, and returns both paths.
+func setupSquashRepo(t *testing.T, commits int) (exportDir, remoteDir string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	exportDir = setupRepairRepo(t)
+
+	remoteDir = filepath.Join(t.TempDir(), "remote.git")
+	require.NoError(t, runGitInDir(t.TempDir(), "init", "--bare", remoteDir))
+	require.NoError(t, runGitInDir(exportDir, "remote", "add", "origin", remoteDir))
+
+	for i := range commits {
+		path := filepath.Join(exportDir, "commits.csv")
+		require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("row\n", i+1)), 0600))
+		require.NoError(t, commitExportChanges(exportDir, []string{"commits.csv"}))
+	}
+	require.NoError(t, pushExportRepo(exportDir))
+	return exportDir, remoteDir
+}
+
+func TestExportSquash_RewritesAndPushes(t *testing.T) {
+	exportDir, remoteDir := setupSquashRepo(t, 3)
+	var printed []string
+	deps := repairDeps(exportDir, &printed)
+
+	require.NoError(t, export(nil, dispatchers.NewParsedFlags([]string{"--squash"}), deps))
+	out := strings.Join(printed, "")
+	require.Contains(t, out, "Squashed 3 commits into snapshot")
+	require.Contains(t, out, "Pushed to remote")
+
+	require.Equal(t, 1, countExportCommits(exportDir))
+	head, err := gitOutputInDir(exportDir, "rev-parse", "HEAD")
+	require.NoError(t, err)
+	remoteHead, err := gitOutputInDir(remoteDir, "rev-parse", "HEAD")
+	require.NoError(t, err)
+	require.Equal(t, head, remoteHead)
+	require.Equal(t, 1, countExportCommits(remoteDir))
+
+	data, err := os.ReadFile(filepath.Join(exportDir, "commits.csv"))
+	require.NoError(t, err)
+	require.Equal(t, "row\nrow\nrow\n", string(data))
+
+	// A second squash has nothing left to do
+	printed = nil
+	require.NoError(t, export(nil, dispatchers.NewParsedFlags([]string{"--squash"}), deps))
+	require.Contains(t, strings.Join(printed, ""), "already a single commit")
+}
+
+func TestExportSquash_DryRun(t *testing.T) {
+	exportDir, _ := setupSquashRepo(t, 2)
+	var printed []string
+	deps := repairDeps(exportDir, &printed)
+
+	require.NoError(t, export(nil, dispatchers.NewParsedFlags([]string{"--squash", "--dry-run"}), deps))
+	require.Contains(t, strings.Join(printed, ""), "Would squash 2 commits into one snapshot commit and force-push it to origin")
+	require.Equal(t, 2, countExportCommits(exportDir))
+}
+
+func TestForcePushSnapshot_StaleLeaseKeepsRemote(t *testing.T) {
+	exportDir, remoteDir := setupSquashRepo(t, 2)
+	before, err := gitOutputInDir(remoteDir, "rev-parse", "HEAD")
+	require.NoError(t, err)
+	snapshot, err := gitOutputInDir(exportDir, "commit-tree", "HEAD^{tree}", "-m", "snapshot")
+	require.NoError(t, err)
+
+	branch := exportBranch(exportDir)
+	err = forcePushSnapshot(exportDir, primaryRemote, branch, snapshot, strings.Repeat("0", 39)+"1")
+	require.Error(t, err)
+
+	after, err := gitOutputInDir(remoteDir, "rev-parse", "HEAD")
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+}
+
+func TestSquashExportIfDue(t *testing.T) {
+	exportDir, _ := setupSquashRepo(t, 2)
+	var printed []string
+	deps := repairDeps(exportDir, &printed)
+	deps.Now = time.Now
+
+	summary, err := squashExportIfDue(nil, deps)
+	require.NoError(t, err)
+	require.Contains(t, summary, "export_squash_days not set")
+
+	lines, _ := config.Set(nil, "export_squash_days", "30")
+	require.NoError(t, config.WriteLines(lines))
+	summary, err = squashExportIfDue(nil, deps)
+	require.NoError(t, err)
+	require.Equal(t, "history is less than 30 days old", summary)
+
+	deps.Now = func() time.Time { return time.Now().AddDate(0, 0, 31) }
+	summary, err = squashExportIfDue(nil, deps)
+	require.NoError(t, err)
+	require.Contains(t, summary, "squashed 2 commits")
+	require.Equal(t, 1, countExportCommits(exportDir))
+}
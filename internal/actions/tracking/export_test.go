@@ -32,6 +32,11 @@ const (
 	colInsertions   = 13
 	colDeletions    = 14
 	colDevice       = 15
+	colNote         = 16
+	colTag          = 17
+	colPushRemote   = 18
+	colPushRefs     = 19
+	colSource       = 20
 )
 
 func TestGetCSVPath_CurrentYear(t *testing.T) {
@@ -115,9 +120,9 @@ func TestBuildRecord_CreatesCorrectFormat(t *testing.T) {
 		Subject:        "Fix bug",
 	}
 
-	record := buildRecord(event, meta)
+	record := buildRecord(event, meta, nil, exportRedaction{})
 
-	require.Len(t, record, 16)
+	require.Len(t, record, 21)
 	require.NotEmpty(t, record[colEventID])                        // UUID generated
 	require.Equal(t, "commit", record[colEventType])               // event_type
 	require.Equal(t, "2024-01-15T10:30:00Z", record[colTimestamp]) // timestamp
@@ -137,12 +142,63 @@ func TestBuildRecord_SanitizesNewlines(t *testing.T) {
 		Subject: "Line 1\nLine 2\rLine 3",
 	}
 
-	record := buildRecord(event, meta)
+	record := buildRecord(event, meta, nil, exportRedaction{})
 
 	// \n becomes space, \r is removed
 	require.Equal(t, "Line 1 Line 2Line 3", record[colMessage])
 }
 
+func TestBuildRecord_Note(t *testing.T) {
+	event := store.RepoEvent{
+		Timestamp: time.Now().UTC(),
+		Note:      "prod hotfix\nfor the login outage",
+	}
+
+	record := buildRecord(event, git.CommitMetadata{}, nil, exportRedaction{})
+	require.Equal(t, "prod hotfix for the login outage", record[colNote])
+
+	record = buildRecord(event, git.CommitMetadata{}, nil, exportRedaction{Messages: redactMessagesDrop})
+	require.Empty(t, record[colNote], "notes are redacted like messages")
+}
+
+func TestBuildRecord_Tag(t *testing.T) {
+	event := store.RepoEvent{Timestamp: time.Now().UTC(), Tag: "client-x"}
+
+	record := buildRecord(event, git.CommitMetadata{}, nil, exportRedaction{})
+	require.Len(t, record, len(csvHeader))
+	require.Equal(t, "client-x", record[colTag])
+
+	record = buildRecord(event, git.CommitMetadata{}, nil, exportRedaction{Messages: redactMessagesDrop})
+	require.Empty(t, record[colTag], "tags are redacted like notes")
+}
+
+func TestBuildRecord_Device(t *testing.T) {
+	setRedactionConfig(t, "device_name", "work-laptop")
+
+	record := buildRecord(store.RepoEvent{Timestamp: time.Now().UTC(), Device: "desktop"}, git.CommitMetadata{}, nil, exportRedaction{})
+	require.Equal(t, "desktop", record[colDevice], "the device the event was recorded on wins")
+
+	record = buildRecord(store.RepoEvent{Timestamp: time.Now().UTC()}, git.CommitMetadata{}, nil, exportRedaction{})
+	require.Equal(t, "work-laptop", record[colDevice], "older events came from this machine")
+}
+
+func TestBuildRecord_Push(t *testing.T) {
+	event := store.RepoEvent{Timestamp: time.Now().UTC(), Source: store.SourcePrePush}
+	push := []store.PushRef{
+		{RemoteURL: "git@github.com:u/app.git", RemoteRef: "refs/heads/main", RemoteSHA: "abc", Commits: 2},
+		{RemoteURL: "git@github.com:u/app.git", RemoteRef: "refs/tags/v1", RemoteSHA: "0000000000000000000000000000000000000000", Commits: -1},
+	}
+
+	record := buildRecord(event, git.CommitMetadata{}, push, exportRedaction{})
+	require.Equal(t, "git@github.com:u/app.git", record[colPushRemote])
+	require.Equal(t, "main +2 commits, tag v1 (new)", record[colPushRefs])
+	require.Equal(t, "pre-push", record[colSource])
+
+	record = buildRecord(store.RepoEvent{Timestamp: time.Now().UTC()}, git.CommitMetadata{}, nil, exportRedaction{})
+	require.Empty(t, record[colPushRemote])
+	require.Empty(t, record[colPushRefs])
+}
+
 func TestWriteCSVSorted_CreatesFileWithHeader(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "test.csv")
@@ -688,7 +744,8 @@ func TestDoExportWork_OfflineMode_ContinuesWhenPullFails(t *testing.T) {
 	}
 
 	// Execute
-	count, pushed, err := doExportWork(db, events, deps)
+	outcome, err := doExportWork(db, events, []exportSink{gitCSVSink{format: csvExportFormat{}}}, deps)
+	count, pushed := outcome.Written, outcome.Pushed
 
 	// Verify: export succeeded despite pull failure
 	require.NoError(t, err, "export should succeed even when pull fails")
@@ -836,6 +893,35 @@ func TestLoadCSVRecords_InvalidCSV(t *testing.T) {
 	require.Nil(t, records)
 }
 
+func TestLoadCSVRecords_TruncatedRow(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "commits.csv")
+
+	// The last row was cut off inside a quoted message
+	content := `event_id,event_type,timestamp,repo_id,repo_name,author_id,author_name,author_email,branch,commit_hash,parent_hashes,message,files_changed,insertions,deletions,device
+uuid1,commit,2024-01-15T10:30:00Z,github.com/user/repo,repo,auth1,John,john@example.com,main,abc123,parent1,Fix bug,3,10,5,machine1
+uuid2,commit,2024-01-16T10:30:00Z,github.com/user/repo,repo,auth1,John,john@example.com,main,def456,abc123,"Add, then`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	records, err := loadCSVRecords(path)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Contains(t, records, "github.com/user/repo:abc123")
+
+	backup, err := os.ReadFile(path + ".truncated")
+	require.NoError(t, err)
+	require.Equal(t, content, string(backup))
+
+	// Writing the records back leaves a complete file and no temp files
+	require.NoError(t, writeCSVSorted(path, records))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	records, err = loadCSVRecords(path)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+}
+
 func TestLoadCSVRecords_MissingColumns(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "short.csv")
@@ -0,0 +1,190 @@
+package tracking
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/components"
+)
+
+// exportToastTime is how long the result of an export started with E stays
+// on screen.
+const exportToastTime = 6 * time.Second
+
+// errExportReadOnly is shown instead of exporting in read-only mode.
+var errExportReadOnly = errors.New("export is disabled in read-only mode")
+
+// exportStepMsg reports a step of an export started from a TUI. ch brings
+// the next message.
+type exportStepMsg struct {
+	step string
+	ch   <-chan tea.Msg
+}
+
+// exportDoneMsg ends an export started from a TUI.
+type exportDoneMsg struct {
+	pending     int
+	destination string
+	outcome     exportOutcome
+	err         error
+}
+
+// tuiExport is the E key of fp activity -i and fp repos -i: it exports the
+// pending events, as fp export --now does, while the view stays open, and
+// shows the steps and the push result in a toast.
+type tuiExport struct {
+	// start runs the export in the background; nil when the view can't
+	// export (tests build models without deps)
+	start   func() tea.Cmd
+	running bool
+	toast   components.ThemedToast
+}
+
+func newTUIExport() tuiExport {
+	return tuiExport{toast: components.NewThemedToast()}
+}
+
+// exportWith makes E export with deps.
+func (x *tuiExport) exportWith(deps Deps) {
+	x.start = func() tea.Cmd { return startExport(deps) }
+}
+
+// begin starts an export, unless one is already running.
+func (x *tuiExport) begin() tea.Cmd {
+	if x.start == nil || x.running {
+		return nil
+	}
+	x.running = true
+	x.toast.Show("Exporting pending events...", components.ToastInfo)
+	return x.start()
+}
+
+// update handles the messages of a running export and of its toast, and
+// reports whether msg was one of them.
+func (x *tuiExport) update(msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case exportStepMsg:
+		x.toast.Show(msg.step+"...", components.ToastInfo)
+		return waitExport(msg.ch), true
+	case exportDoneMsg:
+		x.running = false
+		text, kind := msg.summary()
+		return x.toast.Flash(text, kind, exportToastTime), true
+	case components.ToastExpiredMsg:
+		x.toast = x.toast.Update(msg)
+		return nil, true
+	}
+	return nil, false
+}
+
+// render draws the toast, if any, over view.
+func (x tuiExport) render(view string) string {
+	return x.toast.Render(view)
+}
+
+// startExport exports in the background and returns the command that
+// delivers its steps, then its result.
+func startExport(deps Deps) tea.Cmd {
+	ch := make(chan tea.Msg, 8)
+	deps.progress = func(step string) {
+		// Steps are only news: drop them rather than hold up the export
+		select {
+		case ch <- exportStepMsg{step: step}:
+		default:
+		}
+	}
+	go func() {
+		defer close(ch)
+		ch <- exportPendingNow(deps)
+	}()
+	return waitExport(ch)
+}
+
+// waitExport waits for the next message of an export.
+func waitExport(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-ch
+		if !ok {
+			return nil
+		}
+		if step, ok := msg.(exportStepMsg); ok {
+			step.ch = ch
+			return step
+		}
+		return msg
+	}
+}
+
+// exportPendingNow exports pending events to the configured sinks, like
+// fp export --now, without printing anything.
+func exportPendingNow(deps Deps) exportDoneMsg {
+	if config.IsReadOnly() {
+		return exportDoneMsg{err: errExportReadOnly}
+	}
+
+	db, err := deps.OpenDB(deps.DBPath())
+	if err != nil {
+		return exportDoneMsg{err: fmt.Errorf("could not open database: %w", err)}
+	}
+	defer store.CloseDB(db)
+	_ = deps.InitDB(db)
+
+	events, err := selectPendingEvents(db, pendingSelection{})
+	if err != nil {
+		return exportDoneMsg{err: err}
+	}
+	if len(events) == 0 {
+		return exportDoneMsg{}
+	}
+
+	// The stdout sink is left out: the TUI owns standard output
+	sinks, err := resolveAutoExportSinks()
+	if err != nil {
+		return exportDoneMsg{err: err}
+	}
+
+	outcome, err := doExportWork(db, events, sinks, deps)
+	return exportDoneMsg{
+		pending:     len(events),
+		destination: sinkDestinations(sinks),
+		outcome:     outcome,
+		err:         err,
+	}
+}
+
+// summary is the toast text for the end of an export, and its color.
+func (d exportDoneMsg) summary() (string, components.ToastKind) {
+	switch {
+	case d.err != nil && d.outcome.Written == 0:
+		return "Export failed: " + singleLine(d.err.Error()), components.ToastError
+	case d.pending == 0:
+		return "No pending events to export", components.ToastInfo
+	case d.outcome.Written == 0:
+		return "No events were exported", components.ToastInfo
+	}
+
+	kind := components.ToastSuccess
+	text := fmt.Sprintf("Exported %d %s to %s", d.outcome.Written, pluralize(d.outcome.Written, "event", "events"), d.destination)
+	for _, r := range d.outcome.Remotes {
+		name := r.Remote
+		if !r.Primary {
+			name += " (mirror)"
+		}
+		if r.Pushed {
+			text += "\nPushed to " + name
+		} else {
+			text += fmt.Sprintf("\nPush to %s failed: %s", name, singleLine(r.Error))
+			kind = components.ToastError
+		}
+	}
+	if d.err != nil {
+		text += "\nSome sinks failed: " + singleLine(d.err.Error())
+		kind = components.ToastError
+	}
+	return text, kind
+}
@@ -0,0 +1,131 @@
+package tracking
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/components"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoExportWork_ReportsSteps(t *testing.T) {
+	s := setupSinkStore(t, 2)
+	events, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+
+	var calls [][]int64
+	var steps []string
+	deps := Deps{Now: time.Now, progress: func(step string) { steps = append(steps, step) }}
+
+	_, err = doExportWork(s.DB(), events, []exportSink{fakeSink{name: "http", received: &calls}}, deps)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"Exporting 2 events to http"}, steps)
+}
+
+func TestExportDoneSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		done exportDoneMsg
+		want string
+		kind components.ToastKind
+	}{
+		{"nothing pending", exportDoneMsg{}, "No pending events to export", components.ToastInfo},
+		{"failed", exportDoneMsg{pending: 3, err: errors.New("no export repo\nat all")}, "Export failed: no export repo at all", components.ToastError},
+		{
+			"pushed",
+			exportDoneMsg{pending: 1, destination: "~/.fp/export", outcome: exportOutcome{Written: 1, Pushed: true, Remotes: []remotePush{{Remote: "origin", Primary: true, Pushed: true}}}},
+			"Exported 1 event to ~/.fp/export\nPushed to origin",
+			components.ToastSuccess,
+		},
+		{
+			"mirror failed",
+			exportDoneMsg{pending: 2, destination: "~/.fp/export", outcome: exportOutcome{Written: 2, Remotes: []remotePush{
+				{Remote: "origin", Primary: true, Pushed: true},
+				{Remote: "backup", Error: "rejected"},
+			}}},
+			"Exported 2 events to ~/.fp/export\nPushed to origin\nPush to backup (mirror) failed: rejected",
+			components.ToastError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, kind := tt.done.summary()
+			require.Equal(t, tt.want, text)
+			require.Equal(t, tt.kind, kind)
+		})
+	}
+}
+
+func TestTUIExport_StepsThenResult(t *testing.T) {
+	started := 0
+	x := newTUIExport()
+	x.start = func() tea.Cmd {
+		started++
+		return func() tea.Msg { return nil }
+	}
+
+	require.NotNil(t, x.begin())
+	require.Nil(t, x.begin(), "a running export is not started again")
+	require.Equal(t, 1, started)
+	require.Contains(t, x.toast.View(), "Exporting pending events...")
+
+	ch := make(chan tea.Msg)
+	cmd, ok := x.update(exportStepMsg{step: "Pushing to origin", ch: ch})
+	require.True(t, ok)
+	require.NotNil(t, cmd, "the next step is waited for")
+	require.Contains(t, x.toast.View(), "Pushing to origin...")
+
+	cmd, ok = x.update(exportDoneMsg{})
+	require.True(t, ok)
+	require.NotNil(t, cmd, "the result is hidden after a while")
+	require.False(t, x.running)
+	require.Contains(t, x.toast.View(), "No pending events to export")
+
+	_, ok = x.update(tea.KeyMsg{})
+	require.False(t, ok)
+}
+
+func TestWaitExport_SetsChannelOnSteps(t *testing.T) {
+	ch := make(chan tea.Msg, 2)
+	ch <- exportStepMsg{step: "Pulling the export repo"}
+	ch <- exportDoneMsg{pending: 1}
+	close(ch)
+
+	step, ok := waitExport(ch)().(exportStepMsg)
+	require.True(t, ok)
+	require.NotNil(t, step.ch)
+	require.Equal(t, exportDoneMsg{pending: 1}, waitExport(step.ch)())
+	require.Nil(t, waitExport(ch)())
+}
+
+func TestActivityModel_ExportKey(t *testing.T) {
+	m := newActivityModel(nil, nil)
+	m.width, m.height = 120, 30
+
+	// Without deps E does nothing
+	next, cmd := m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}})
+	require.Nil(t, cmd)
+	require.Empty(t, next.(activityModel).filterQuery, "E is a key, not filter text")
+
+	m.export.start = func() tea.Cmd { return func() tea.Msg { return nil } }
+	next, cmd = m.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}})
+	require.NotNil(t, cmd)
+	view := next.(activityModel).View()
+	require.True(t, strings.Contains(view, "Exporting pending events..."))
+}
+
+func TestReposModel_ExportKey(t *testing.T) {
+	m := newReposModel(nil)
+	m.export.start = func() tea.Cmd { return func() tea.Msg { return nil } }
+
+	// repos -i has no annotate, so export keeps e
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	require.NotNil(t, cmd)
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'E'}})
+	require.Nil(t, cmd)
+}
@@ -2,6 +2,8 @@ package tracking
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/footprint-tools/cli/internal/format"
 	"github.com/footprint-tools/cli/internal/git"
@@ -22,6 +24,20 @@ var sourceStylers = map[store.Source]func(string) string{
 	store.SourcePrePush:      style.Color5,
 	store.SourceBackfill:     style.Color6,
 	store.SourceManual:       style.Color7,
+	store.SourceBranchCreate: style.Color8,
+	store.SourceBranchDelete: style.Color9,
+	store.SourceStash:        style.Color10,
+}
+
+// dateColumnWidth and timeColumnWidth fit the dates and times of the event
+// rows of fp activity -i and fp watch, in any date_format and time_format,
+// and their headings.
+func dateColumnWidth() int {
+	return max(len("DATE"), format.DateWidth())
+}
+
+func timeColumnWidth() int {
+	return max(len("TIME"), format.TimeWidth())
 }
 
 // formatEvent formats a single event for display.
@@ -39,15 +55,59 @@ func formatEvent(e store.RepoEvent, oneline bool) string {
 
 	// Multi-line format
 	return fmt.Sprintf(
-		"%s %s %s %s\n%s\n",
+		"%s %s %s %s\n%s\n%s",
 		formatSource(e.Source),
 		style.Header(fmt.Sprintf("%.7s", e.Commit)),
 		e.Branch,
 		style.Muted(e.RepoID),
 		style.Muted(format.Full(e.Timestamp)),
+		formatTag(e.Tag)+formatNote(e.Note),
 	)
 }
 
+// formatTag formats an event tag as a line for the multi-line formats, or
+// returns "" when there is no tag.
+func formatTag(tag string) string {
+	if tag == "" {
+		return ""
+	}
+	return style.Muted("Tag:  ") + tag + "\n"
+}
+
+// formatNote formats an event note as an indented block for the multi-line
+// formats, or returns "" when there is no note.
+func formatNote(note string) string {
+	if note == "" {
+		return ""
+	}
+	var b strings.Builder
+	for i, line := range strings.Split(note, "\n") {
+		if i == 0 {
+			b.WriteString(style.Muted("Note: ") + line + "\n")
+		} else {
+			b.WriteString("      " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// formatEventPlain formats an event as one uncolored, tab-separated line
+// (timestamp, source, commit, repository, branch and, when meta is given,
+// the commit subject) for other tools to parse.
+func formatEventPlain(e store.RepoEvent, meta *git.CommitMetadata) string {
+	fields := []string{
+		e.Timestamp.UTC().Format(time.RFC3339),
+		e.Source.String(),
+		e.Commit,
+		e.RepoID,
+		e.Branch,
+	}
+	if meta != nil {
+		fields = append(fields, strings.ReplaceAll(meta.Subject, "\t", " "))
+	}
+	return strings.Join(fields, "\t")
+}
+
 func formatSource(source store.Source) string {
 	if styler, ok := sourceStylers[source]; ok {
 		return styler(source.String())
@@ -74,7 +134,7 @@ func formatEventEnriched(e store.RepoEvent, meta git.CommitMetadata, oneline boo
 	}
 
 	// Multiline enriched
-	return fmt.Sprintf("%s %s %s %s\n%s\n%s <%s>\n\n    %s\n",
+	return fmt.Sprintf("%s %s %s %s\n%s\n%s <%s>\n\n    %s\n%s",
 		formatSource(e.Source),
 		style.Header(fmt.Sprintf("%.7s", e.Commit)),
 		e.Branch,
@@ -83,5 +143,6 @@ func formatEventEnriched(e store.RepoEvent, meta git.CommitMetadata, oneline boo
 		meta.AuthorName,
 		meta.AuthorEmail,
 		meta.Subject,
+		formatTag(e.Tag)+formatNote(e.Note),
 	)
 }
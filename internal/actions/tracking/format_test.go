@@ -1,6 +1,8 @@
 package tracking
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -146,3 +148,37 @@ func TestFormatEventEnriched_Multiline(t *testing.T) {
 	require.Contains(t, output, "Jan")
 	require.Contains(t, output, "15")
 }
+
+func TestFormatEventPlain(t *testing.T) {
+	event := store.RepoEvent{
+		RepoID:    "github.com/test/repo",
+		Commit:    "abc1234567890",
+		Branch:    "main",
+		Source:    store.SourcePostCommit,
+		Timestamp: time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("x", 3600)),
+	}
+
+	require.Equal(t, "2026-03-04T04:06:07Z\tPOST-COMMIT\tabc1234567890\tgithub.com/test/repo\tmain", formatEventPlain(event, nil))
+
+	meta := git.CommitMetadata{Subject: "fix:\tthe thing"}
+	require.Equal(t, "2026-03-04T04:06:07Z\tPOST-COMMIT\tabc1234567890\tgithub.com/test/repo\tmain\tfix: the thing", formatEventPlain(event, &meta))
+}
+
+func TestWriteWatchEvent(t *testing.T) {
+	event := store.RepoEvent{ID: 7, RepoID: "github.com/test/repo", Commit: "abc123", Branch: "main", Source: store.SourceManual, Timestamp: time.Now()}
+
+	var lines []string
+	println := func(a ...any) (int, error) {
+		lines = append(lines, fmt.Sprint(a...))
+		return 0, nil
+	}
+
+	require.NoError(t, writeWatchEvent(event, false, true, nil, false, println))
+	require.NoError(t, writeWatchEvent(event, true, true, nil, false, println))
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], "\tMANUAL\tabc123\t")
+	require.Contains(t, lines[1], `"id":7`)
+
+	broken := func(...any) (int, error) { return 0, errors.New("broken pipe") }
+	require.Error(t, writeWatchEvent(event, false, true, nil, false, broken))
+}
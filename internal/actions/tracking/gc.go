@@ -0,0 +1,131 @@
+package tracking
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/paths"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// gcTasks are the maintenance steps that free disk space, in the order fp
+// gc runs them. Deleting rows comes before vacuum so their pages are
+// reclaimed in the same run.
+var gcTasks = []maintenanceTask{
+	{name: "prune", run: pruneExpiredEvents},
+	{name: "orphans", run: cleanOrphanedEvents},
+	{name: "meta", run: cleanUnusedCommitMeta},
+	{name: "rollups", run: rebuildDailyRollups},
+	{name: "vacuum", run: vacuumDatabase},
+	{name: "logs", run: truncateLog},
+}
+
+// gcReport is the outcome of fp gc: what each task did and the space the
+// database and log files took before and after.
+type gcReport struct {
+	Tasks          []maintenanceResult `json:"tasks"`
+	BytesBefore    int64               `json:"bytes_before"`
+	BytesAfter     int64               `json:"bytes_after"`
+	BytesReclaimed int64               `json:"bytes_reclaimed"`
+}
+
+// GC deletes what fp no longer needs and compacts the database.
+func GC(args []string, flags *dispatchers.ParsedFlags) error {
+	return gc(args, flags, DefaultDeps())
+}
+
+func gc(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	s, err := deps.OpenStore(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("could not open database: %w", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	start := deps.Now()
+	report := gcReport{BytesBefore: gcFootprint(deps)}
+
+	failed := 0
+	for _, task := range gcTasks {
+		summary, err := task.run(s, deps)
+		result := maintenanceResult{Task: task.name, Summary: summary}
+		if err != nil {
+			log.Warn("gc: %s failed: %v", task.name, err)
+			result.Error = err.Error()
+			failed++
+		}
+		report.Tasks = append(report.Tasks, result)
+	}
+
+	report.BytesAfter = gcFootprint(deps)
+	report.BytesReclaimed = max(0, report.BytesBefore-report.BytesAfter)
+
+	if flags.Has("--json") {
+		if err := output.JSON(deps.Println, report); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range report.Tasks {
+			if r.Error != "" {
+				_, _ = deps.Printf("  %-8s FAILED: %s\n", r.Task, r.Error)
+			} else {
+				_, _ = deps.Printf("  %-8s %s\n", r.Task, r.Summary)
+			}
+		}
+		_, _ = deps.Printf("\nReclaimed %s (%s -> %s) in %s\n",
+			formatBytes(report.BytesReclaimed), formatBytes(report.BytesBefore), formatBytes(report.BytesAfter),
+			deps.Now().Sub(start).Round(time.Millisecond))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d gc tasks failed", failed, len(report.Tasks))
+	}
+	return nil
+}
+
+// gcFootprint is the space taken by the database and the log files.
+func gcFootprint(deps Deps) int64 {
+	logPath := paths.LogFilePath()
+	return fileSize(deps.DBPath()) + fileSize(logPath) + fileSize(logPath+".1")
+}
+
+// cleanUnusedCommitMeta deletes stored metadata of commits that no event
+// refers to any more.
+func cleanUnusedCommitMeta(s *store.Store, _ Deps) (string, error) {
+	deleted, err := store.DeleteUnusedCommitMeta(s.DB())
+	if err != nil {
+		return "", err
+	}
+	if _, err := store.DeleteUnusedCommitLanguages(s.DB()); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("removed metadata of %d commits without events", deleted), nil
+}
+
+// rebuildDailyRollups recounts the per-day totals of the calendar views
+// from the events, which also moves days written in another time zone.
+func rebuildDailyRollups(s *store.Store, _ Deps) (string, error) {
+	n, err := store.RebuildDailyRollups(s.DB())
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("recounted %d daily %s", n, pluralize(int(n), "total", "totals")), nil
+}
+
+// truncateLog moves the log aside whatever its size, replacing the
+// previous rotation, so only the latest log is kept.
+func truncateLog(_ *store.Store, _ Deps) (string, error) {
+	logPath := paths.LogFilePath()
+	size := fileSize(logPath)
+	rotated, err := log.Rotate(logPath, 0)
+	if err != nil {
+		return "", err
+	}
+	if !rotated {
+		return "log is empty", nil
+	}
+	return fmt.Sprintf("moved %s of %s to %s", formatBytes(size), filepath.Base(logPath), filepath.Base(logPath)+".1"), nil
+}
@@ -0,0 +1,55 @@
+package tracking
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/paths"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestGC(t *testing.T) {
+	var printed []string
+	deps, dbPath := maintenanceDeps(t, &printed)
+
+	lines, _ := config.Set(nil, "retention_days", "30")
+	require.NoError(t, config.WriteLines(lines))
+
+	s, err := store.New(dbPath)
+	require.NoError(t, err)
+	old := time.Now().AddDate(0, 0, -60).UTC()
+	require.NoError(t, store.InsertEvent(s.DB(), store.RepoEvent{RepoID: "r", Commit: "a", Branch: "main", Timestamp: old, Status: store.StatusExported, Source: store.SourcePostCommit}))
+	require.NoError(t, store.InsertEvent(s.DB(), store.RepoEvent{RepoID: "r", Commit: "b", Branch: "main", Timestamp: old, Status: store.StatusPending, Source: store.SourcePostCommit}))
+	require.NoError(t, store.SaveCommitMeta(s.DB(), "r", "a", git.CommitMetadata{Subject: "pruned"}))
+	require.NoError(t, store.SaveCommitMeta(s.DB(), "r", "b", git.CommitMetadata{Subject: "pending"}))
+	require.NoError(t, s.Close())
+
+	logPath := paths.LogFilePath()
+	require.NoError(t, os.MkdirAll(filepath.Dir(logPath), 0700))
+	require.NoError(t, os.WriteFile(logPath+".1", []byte(strings.Repeat("old\n", 1000)), 0600))
+	require.NoError(t, os.WriteFile(logPath, []byte("recent\n"), 0600))
+
+	require.NoError(t, gc(nil, dispatchers.NewParsedFlags([]string{"--json"}), deps))
+
+	var report gcReport
+	require.NoError(t, json.Unmarshal([]byte(strings.Join(printed, "")), &report))
+	require.Len(t, report.Tasks, len(gcTasks))
+	require.Equal(t, "removed 1 exported events older than 30 days", report.Tasks[0].Summary)
+	require.Equal(t, "removed metadata of 1 commits without events", report.Tasks[2].Summary)
+	require.Positive(t, report.BytesReclaimed, "the previous rotation is replaced")
+	require.Equal(t, report.BytesBefore-report.BytesAfter, report.BytesReclaimed)
+
+	rotated, err := os.ReadFile(logPath + ".1")
+	require.NoError(t, err)
+	require.Equal(t, "recent\n", string(rotated))
+	require.Zero(t, fileSize(logPath))
+}
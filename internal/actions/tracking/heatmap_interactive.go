@@ -0,0 +1,493 @@
+package tracking
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/components"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"golang.org/x/term"
+)
+
+const dayKeyLayout = "2006-01-02"
+
+// Heatmap runs the interactive contribution calendar.
+func Heatmap(args []string, flags *dispatchers.ParsedFlags) error {
+	return heatmap(args, flags, DefaultDeps())
+}
+
+func heatmap(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	year := deps.Now().Year()
+	if yearStr := flags.String("--year", ""); yearStr != "" {
+		y, err := strconv.Atoi(yearStr)
+		if err != nil || y < 1970 || y > 9999 {
+			return fmt.Errorf("invalid year '%s': expected format YYYY", yearStr)
+		}
+		year = y
+	}
+
+	accessible := isAccessible(deps)
+	if !accessible && (!term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd()))) {
+		return errors.New("heatmap requires an interactive terminal")
+	}
+
+	db, err := deps.OpenSnapshot(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer store.CloseDB(db)
+
+	// The calendar only needs counts per day; events are read a day at a
+	// time, when one is opened
+	rollups, err := store.ListDailyRollups(db, store.RollupFilter{})
+	if err != nil {
+		return fmt.Errorf("failed to read daily counts: %w", err)
+	}
+
+	if accessible {
+		printHeatmapSummary(rollups, year, flags.String("--repo", ""), deps)
+		return nil
+	}
+
+	m := newHeatmapModel(rollups, year, deps.Now())
+	metas := newCommitMetaReader(db)
+	m.loadMeta = metas.Of
+	m.prefetchMeta = metas.Prefetch
+	m.listDay = func(day time.Time) ([]store.RepoEvent, error) {
+		end := day.AddDate(0, 0, 1).Add(-time.Second)
+		return deps.ListEvents(db, store.EventFilter{Since: &day, Until: &end})
+	}
+	m.listLanguages = func(day time.Time) (map[string][]store.LanguageLines, error) {
+		end := day.AddDate(0, 0, 1).Add(-time.Second)
+		return store.ListCommitLanguages(db, &day, &end)
+	}
+	if repo := flags.String("--repo", ""); repo != "" {
+		m.filterRepo = repo
+		m.recount()
+	}
+
+	p := tea.NewProgram(components.NewSizeGuard(m), tea.WithAltScreen())
+	_, err = p.Run()
+	return err
+}
+
+// heatmapRepo is a repository entry in the heatmap sidebar.
+type heatmapRepo struct {
+	id    string
+	name  string
+	count int
+}
+
+// heatmapModel is the Bubble Tea model for the contribution calendar.
+type heatmapModel struct {
+	rollups []store.DailyRollup
+
+	// The events of the selected day and their commit metadata, loaded
+	// when the day is opened in the drawer
+	day        []store.RepoEvent
+	listDay    func(day time.Time) ([]store.RepoEvent, error)
+	commitMeta map[string]git.CommitMetadata
+	loadMeta   func(store.RepoEvent) git.CommitMetadata
+	// prefetchMeta reads the metadata of a day's events at once; may be nil
+	prefetchMeta func([]store.RepoEvent)
+	// The lines the day's commits changed per language, busiest first
+	languages     []languageTotal
+	listLanguages func(day time.Time) (map[string][]store.LanguageLines, error)
+
+	// Calendar state
+	year     int
+	minYear  int
+	maxYear  int
+	selected time.Time
+
+	// Per-day counts for the current year and repo filter
+	dayCounts map[string]int
+	maxCount  int
+
+	// Repo filter
+	repos      []heatmapRepo
+	repoCursor int
+	filterRepo string // "" means all repos
+
+	// UI dimensions
+	width  int
+	height int
+
+	// Focus: 0=calendar, 1=sidebar, 2=drawer
+	focusedPanel int
+
+	// Drawer
+	drawerOpen     bool
+	drawerViewport components.ThemedViewport
+
+	// Styling
+	colors style.ColorConfig
+}
+
+func newHeatmapModel(rollups []store.DailyRollup, year int, now time.Time) heatmapModel {
+	minYear, maxYear := year, year
+	for _, r := range rollups {
+		y := rollupYear(r)
+		minYear = min(minYear, y)
+		maxYear = max(maxYear, y)
+	}
+
+	m := heatmapModel{
+		rollups:        rollups,
+		commitMeta:     make(map[string]git.CommitMetadata),
+		loadMeta:       func(e store.RepoEvent) git.CommitMetadata { return git.GetCommitMetadata(e.RepoPath, e.Commit) },
+		year:           year,
+		minYear:        minYear,
+		maxYear:        maxYear,
+		colors:         style.GetColors(),
+		drawerViewport: components.NewThemedViewport(40, 20),
+	}
+
+	// Start on today when viewing the current year, otherwise on Jan 1
+	if now.Year() == year {
+		m.selected = time.Date(year, now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	} else {
+		m.selected = time.Date(year, 1, 1, 0, 0, 0, 0, time.Local)
+	}
+
+	m.recount()
+	return m
+}
+
+// recount rebuilds day counts and the repo list for the current year and filter.
+func (m *heatmapModel) recount() {
+	m.dayCounts = countEventsByDay(m.rollups, m.year, m.filterRepo)
+	m.maxCount = 0
+	for _, c := range m.dayCounts {
+		m.maxCount = max(m.maxCount, c)
+	}
+
+	byRepo := make(map[string]*heatmapRepo)
+	for _, day := range m.rollups {
+		if rollupYear(day) != m.year {
+			continue
+		}
+		r, ok := byRepo[day.RepoID]
+		if !ok {
+			name := filepath.Base(day.RepoPath)
+			if name == "" || name == "." {
+				name = day.RepoID
+			}
+			r = &heatmapRepo{id: day.RepoID, name: name}
+			byRepo[day.RepoID] = r
+		}
+		r.count += day.Events
+	}
+
+	m.repos = make([]heatmapRepo, 0, len(byRepo))
+	for _, r := range byRepo {
+		m.repos = append(m.repos, *r)
+	}
+	sort.Slice(m.repos, func(i, j int) bool {
+		if m.repos[i].count != m.repos[j].count {
+			return m.repos[i].count > m.repos[j].count
+		}
+		return m.repos[i].name < m.repos[j].name
+	})
+	// Cursor index 0 is "All repos"
+	m.repoCursor = max(0, min(m.repoCursor, len(m.repos)))
+}
+
+// countEventsByDay counts events per local day (YYYY-MM-DD) in year.
+// If repoID is non-empty, only events for that repository are counted.
+func countEventsByDay(rollups []store.DailyRollup, year int, repoID string) map[string]int {
+	counts := make(map[string]int)
+	for _, r := range rollups {
+		if rollupYear(r) != year {
+			continue
+		}
+		if repoID != "" && r.RepoID != repoID {
+			continue
+		}
+		counts[r.Day] += r.Events
+	}
+	return counts
+}
+
+// rollupYear returns the year of r's day.
+func rollupYear(r store.DailyRollup) int {
+	y, _ := strconv.Atoi(strings.SplitN(r.Day, "-", 2)[0])
+	return y
+}
+
+// intensityLevel maps a day count to a 0-4 level relative to the busiest day.
+func intensityLevel(count, maxCount int) int {
+	if count <= 0 || maxCount <= 0 {
+		return 0
+	}
+	level := (count*4 + maxCount - 1) / maxCount // ceil(4*count/max)
+	return max(1, min(4, level))
+}
+
+// calendarStart returns the first day of the week (see week_start) on or
+// before Jan 1 of year.
+func calendarStart(year int) time.Time {
+	return format.StartOfWeek(time.Date(year, 1, 1, 0, 0, 0, 0, time.Local))
+}
+
+// weekIndex returns the calendar column for day within its year.
+func weekIndex(day time.Time) int {
+	start := calendarStart(day.Year())
+	d := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	return int(d.Sub(start).Hours()/24+0.5) / 7
+}
+
+// longestStreak returns the longest run of consecutive active days in year.
+func longestStreak(dayCounts map[string]int, year int) int {
+	best, current := 0, 0
+	for d := time.Date(year, 1, 1, 0, 0, 0, 0, time.Local); d.Year() == year; d = d.AddDate(0, 0, 1) {
+		if dayCounts[d.Format(dayKeyLayout)] > 0 {
+			current++
+			best = max(best, current)
+		} else {
+			current = 0
+		}
+	}
+	return best
+}
+
+// dayEvents returns the selected day's events (respecting the repo filter), newest first.
+func (m heatmapModel) dayEvents() []store.RepoEvent {
+	key := m.selected.Format(dayKeyLayout)
+	var events []store.RepoEvent
+	for _, e := range m.day {
+		if e.Timestamp.Local().Format(dayKeyLayout) != key {
+			continue
+		}
+		if m.filterRepo != "" && e.RepoID != m.filterRepo {
+			continue
+		}
+		events = append(events, e)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Timestamp.After(events[j].Timestamp)
+	})
+	return events
+}
+
+func (m heatmapModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m heatmapModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+
+	return m, nil
+}
+
+func (m heatmapModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyTab:
+		if m.drawerOpen {
+			m.focusedPanel = (m.focusedPanel + 1) % 3
+		} else {
+			m.focusedPanel = (m.focusedPanel + 1) % 2
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "[":
+		m.changeYear(-1)
+		return m, nil
+	case "]":
+		m.changeYear(1)
+		return m, nil
+	}
+
+	if m.drawerOpen && m.focusedPanel == 2 {
+		return m.handleDrawerKeys(msg)
+	}
+	if m.focusedPanel == 1 {
+		return m.handleSidebarKeys(msg)
+	}
+	return m.handleCalendarKeys(msg)
+}
+
+func (m heatmapModel) handleCalendarKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc":
+		if m.drawerOpen {
+			m.closeDrawer()
+			return m, nil
+		}
+		return m, tea.Quit
+	case "left", "h":
+		m.moveSelection(-7)
+	case "right", "l":
+		m.moveSelection(7)
+	case "up", "k":
+		m.moveSelection(-1)
+	case "down", "j":
+		m.moveSelection(1)
+	case "g":
+		m.selected = time.Date(m.year, 1, 1, 0, 0, 0, 0, time.Local)
+	case "G":
+		m.selected = time.Date(m.year, 12, 31, 0, 0, 0, 0, time.Local)
+	case "enter":
+		m.openDrawer()
+	case "c":
+		m.filterRepo = ""
+		m.recount()
+	}
+	return m, nil
+}
+
+func (m heatmapModel) handleSidebarKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc":
+		if m.filterRepo != "" {
+			m.filterRepo = ""
+			m.recount()
+			return m, nil
+		}
+		m.focusedPanel = 0
+	case "up", "k":
+		m.repoCursor = max(0, m.repoCursor-1)
+	case "down", "j":
+		m.repoCursor = min(len(m.repos), m.repoCursor+1)
+	case "enter", " ":
+		if m.repoCursor == 0 {
+			m.filterRepo = ""
+		} else {
+			id := m.repos[m.repoCursor-1].id
+			if m.filterRepo == id {
+				m.filterRepo = ""
+			} else {
+				m.filterRepo = id
+			}
+		}
+		m.recount()
+	case "c":
+		m.filterRepo = ""
+		m.recount()
+	}
+	return m, nil
+}
+
+func (m heatmapModel) handleDrawerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.closeDrawer()
+	case "up", "k":
+		m.drawerViewport.LineUp(1)
+	case "down", "j":
+		m.drawerViewport.LineDown(1)
+	case "pgup":
+		m.drawerViewport.LineUp(10)
+	case "pgdown":
+		m.drawerViewport.LineDown(10)
+	case "g":
+		m.drawerViewport.GotoTop()
+	case "G":
+		m.drawerViewport.GotoBottom()
+	}
+	return m, nil
+}
+
+// moveSelection moves the selected day by delta days, staying within the year.
+func (m *heatmapModel) moveSelection(delta int) {
+	next := m.selected.AddDate(0, 0, delta)
+	if next.Year() != m.year {
+		return
+	}
+	m.selected = next
+	if m.drawerOpen {
+		m.loadDay()
+		m.drawerViewport.GotoTop()
+	}
+}
+
+// changeYear switches the calendar to another year that has data.
+func (m *heatmapModel) changeYear(delta int) {
+	year := m.year + delta
+	if year < m.minYear || year > m.maxYear {
+		return
+	}
+	m.year = year
+
+	// Keep the same month/day where possible
+	day := min(m.selected.Day(), daysIn(m.selected.Month(), year))
+	m.selected = time.Date(year, m.selected.Month(), day, 0, 0, 0, 0, time.Local)
+	m.recount()
+	if m.drawerOpen {
+		m.loadDay()
+	}
+}
+
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.Local).Day()
+}
+
+func (m *heatmapModel) openDrawer() {
+	m.drawerOpen = true
+	m.focusedPanel = 2
+	m.drawerViewport.GotoTop()
+	m.loadDay()
+}
+
+func (m *heatmapModel) closeDrawer() {
+	m.drawerOpen = false
+	m.drawerViewport.GotoTop()
+	m.focusedPanel = 0
+}
+
+// loadDay reads the selected day's events and fetches their commit
+// metadata and languages.
+func (m *heatmapModel) loadDay() {
+	m.day = nil
+	m.languages = nil
+	if m.listDay != nil {
+		events, err := m.listDay(m.selected)
+		if err != nil {
+			log.Debug("heatmap: could not read the events of %s: %v", m.selected.Format(dayKeyLayout), err)
+		}
+		m.day = events
+	}
+	if m.prefetchMeta != nil {
+		m.prefetchMeta(m.dayEvents())
+	}
+	for _, e := range m.dayEvents() {
+		if _, ok := m.commitMeta[e.Commit]; !ok {
+			m.commitMeta[e.Commit] = m.loadMeta(e)
+		}
+	}
+	if m.listLanguages != nil {
+		byCommit, err := m.listLanguages(m.selected)
+		if err != nil {
+			log.Debug("heatmap: could not read the languages of %s: %v", m.selected.Format(dayKeyLayout), err)
+		}
+		m.languages, _, _ = languageTotals(m.dayEvents(), byCommit)
+	}
+}
@@ -0,0 +1,249 @@
+package tracking
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func heatmapEvent(repoID string, t time.Time) store.RepoEvent {
+	return store.RepoEvent{
+		RepoID:    repoID,
+		RepoPath:  "/src/" + repoID,
+		Commit:    "abc1234567890",
+		Branch:    "main",
+		Source:    store.SourcePostCommit,
+		Timestamp: t,
+	}
+}
+
+func heatmapDay(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 12, 0, 0, 0, time.Local)
+}
+
+// heatmapRollups counts events per day, repository and source, as the
+// store's daily rollups do.
+func heatmapRollups(events ...store.RepoEvent) []store.DailyRollup {
+	var rollups []store.DailyRollup
+	index := make(map[string]int)
+	for _, e := range events {
+		day := e.Timestamp.Local().Format(dayKeyLayout)
+		key := fmt.Sprint(day, e.RepoID, e.Source)
+		i, ok := index[key]
+		if !ok {
+			i = len(rollups)
+			index[key] = i
+			rollups = append(rollups, store.DailyRollup{Day: day, RepoID: e.RepoID, RepoPath: e.RepoPath, Source: e.Source})
+		}
+		rollups[i].Events++
+	}
+	return rollups
+}
+
+func TestCountEventsByDay(t *testing.T) {
+	events := heatmapRollups(
+		heatmapEvent("a", heatmapDay(2024, 3, 1)),
+		heatmapEvent("a", heatmapDay(2024, 3, 1)),
+		heatmapEvent("b", heatmapDay(2024, 3, 1)),
+		heatmapEvent("a", heatmapDay(2024, 3, 2)),
+		heatmapEvent("a", heatmapDay(2023, 3, 1)),
+	)
+
+	counts := countEventsByDay(events, 2024, "")
+	require.Equal(t, map[string]int{"2024-03-01": 3, "2024-03-02": 1}, counts)
+
+	counts = countEventsByDay(events, 2024, "b")
+	require.Equal(t, map[string]int{"2024-03-01": 1}, counts)
+}
+
+func TestIntensityLevel(t *testing.T) {
+	require.Equal(t, 0, intensityLevel(0, 10))
+	require.Equal(t, 0, intensityLevel(5, 0))
+	require.Equal(t, 1, intensityLevel(1, 10))
+	require.Equal(t, 2, intensityLevel(5, 10))
+	require.Equal(t, 4, intensityLevel(10, 10))
+	require.Equal(t, 4, intensityLevel(20, 10))
+}
+
+func TestCalendarStartAndWeekIndex(t *testing.T) {
+	setRedactionConfig(t, "week_start", "sunday")
+
+	// Jan 1 2024 is a Monday, so the calendar starts on Sunday Dec 31 2023
+	start := calendarStart(2024)
+	require.Equal(t, time.Sunday, start.Weekday())
+	require.Equal(t, 2023, start.Year())
+	require.Equal(t, 31, start.Day())
+
+	require.Equal(t, 0, weekIndex(heatmapDay(2024, 1, 1)))
+	require.Equal(t, 0, weekIndex(heatmapDay(2024, 1, 6)))
+	require.Equal(t, 1, weekIndex(heatmapDay(2024, 1, 7)))
+	require.Equal(t, 52, weekIndex(heatmapDay(2024, 12, 31)))
+}
+
+func TestCalendarStart_FollowsWeekStart(t *testing.T) {
+	setRedactionConfig(t)
+
+	// Weeks start on Monday by default, and Jan 1 2024 is one
+	start := calendarStart(2024)
+	require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), start)
+	require.Equal(t, 0, weekIndex(heatmapDay(2024, 1, 7)))
+	require.Equal(t, 1, weekIndex(heatmapDay(2024, 1, 8)))
+
+	setRedactionConfig(t, "week_start", "saturday")
+	start = calendarStart(2024)
+	require.Equal(t, time.Date(2023, 12, 30, 0, 0, 0, 0, time.Local), start)
+	require.Equal(t, 1, weekIndex(heatmapDay(2024, 1, 6)))
+}
+
+func TestLongestStreak(t *testing.T) {
+	counts := map[string]int{
+		"2024-01-01": 1,
+		"2024-01-02": 2,
+		"2024-01-04": 1,
+		"2024-01-05": 1,
+		"2024-01-06": 1,
+	}
+	require.Equal(t, 3, longestStreak(counts, 2024))
+	require.Equal(t, 0, longestStreak(counts, 2023))
+}
+
+func TestHeatmapModel_YearNavigation(t *testing.T) {
+	events := heatmapRollups(
+		heatmapEvent("a", heatmapDay(2023, 6, 1)),
+		heatmapEvent("a", heatmapDay(2024, 6, 1)),
+	)
+	m := newHeatmapModel(events, 2024, heatmapDay(2024, 2, 29))
+	require.Equal(t, 2024, m.year)
+	require.Equal(t, 29, m.selected.Day())
+
+	// Feb 29 does not exist in 2023
+	m.changeYear(-1)
+	require.Equal(t, 2023, m.year)
+	require.Equal(t, time.February, m.selected.Month())
+	require.Equal(t, 28, m.selected.Day())
+	require.Equal(t, 1, m.dayCounts["2023-06-01"])
+
+	// No data before 2023
+	m.changeYear(-1)
+	require.Equal(t, 2023, m.year)
+}
+
+func TestHeatmapModel_MoveSelectionStaysInYear(t *testing.T) {
+	m := newHeatmapModel(nil, 2024, heatmapDay(2024, 1, 2))
+
+	m.moveSelection(-7)
+	require.Equal(t, 2, m.selected.Day())
+
+	m.moveSelection(-1)
+	require.Equal(t, 1, m.selected.Day())
+}
+
+func TestHeatmapModel_SidebarFilter(t *testing.T) {
+	events := heatmapRollups(
+		heatmapEvent("a", heatmapDay(2024, 3, 1)),
+		heatmapEvent("a", heatmapDay(2024, 3, 1)),
+		heatmapEvent("b", heatmapDay(2024, 3, 1)),
+	)
+	m := newHeatmapModel(events, 2024, heatmapDay(2024, 3, 1))
+	require.Len(t, m.repos, 2)
+	require.Equal(t, "a", m.repos[0].id)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(heatmapModel)
+
+	require.Equal(t, "a", m.filterRepo)
+	require.Equal(t, 2, m.dayCounts["2024-03-01"])
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = updated.(heatmapModel)
+	require.Empty(t, m.filterRepo)
+	require.Equal(t, 3, m.dayCounts["2024-03-01"])
+}
+
+func TestHeatmapModel_DrawerLoadsDayMetadata(t *testing.T) {
+	events := []store.RepoEvent{heatmapEvent("a", heatmapDay(2024, 3, 1))}
+	m := newHeatmapModel(heatmapRollups(events...), 2024, heatmapDay(2024, 3, 1))
+
+	// Events are read when the day is opened
+	var listed []time.Time
+	m.listDay = func(day time.Time) ([]store.RepoEvent, error) {
+		listed = append(listed, day)
+		return events, nil
+	}
+	require.Empty(t, m.dayEvents())
+
+	calls := 0
+	m.loadMeta = func(store.RepoEvent) git.CommitMetadata {
+		calls++
+		return git.CommitMetadata{Subject: "Fix things"}
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(heatmapModel)
+
+	require.True(t, m.drawerOpen)
+	require.Equal(t, 2, m.focusedPanel)
+	require.Equal(t, []time.Time{time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)}, listed)
+	require.Len(t, m.dayEvents(), 1)
+	require.Equal(t, 1, calls)
+	require.Equal(t, "Fix things", m.commitMeta["abc1234567890"].Subject)
+
+	m.width, m.height = 120, 30
+	require.Contains(t, m.View(), "Fix things")
+}
+
+func TestHeatmapModel_DrawerShowsLanguages(t *testing.T) {
+	events := []store.RepoEvent{heatmapEvent("a", heatmapDay(2024, 3, 1))}
+	m := newHeatmapModel(heatmapRollups(events...), 2024, heatmapDay(2024, 3, 1))
+	m.listDay = func(time.Time) ([]store.RepoEvent, error) { return events, nil }
+	m.loadMeta = func(store.RepoEvent) git.CommitMetadata { return git.CommitMetadata{} }
+	m.listLanguages = func(time.Time) (map[string][]store.LanguageLines, error) {
+		return map[string][]store.LanguageLines{
+			store.CommitKey(events[0].RepoID, events[0].Commit): {{Language: "Go", Insertions: 12, Deletions: 4}},
+		}, nil
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(heatmapModel)
+	m.width, m.height = 120, 30
+	view := m.View()
+	require.Contains(t, view, "LANGUAGES")
+	require.Regexp(t, `Go\s+\+12 -4`, view)
+}
+
+func TestPrintHeatmapSummary(t *testing.T) {
+	events := heatmapRollups(
+		heatmapEvent("a", heatmapDay(2025, time.March, 3)),
+		heatmapEvent("a", heatmapDay(2025, time.March, 4)),
+		heatmapEvent("b", heatmapDay(2025, time.March, 4)),
+		heatmapEvent("a", heatmapDay(2025, time.July, 1)),
+		heatmapEvent("a", heatmapDay(2024, time.July, 1)),
+	)
+	var out strings.Builder
+	deps := Deps{
+		Printf:  func(format string, a ...any) (int, error) { return fmt.Fprintf(&out, format, a...) },
+		Println: func(a ...any) (int, error) { return fmt.Fprintln(&out, a...) },
+	}
+
+	printHeatmapSummary(events, 2025, "", deps)
+
+	require.Equal(t, "2025, all repositories: 4 events on 3 days\n"+
+		"Longest streak: 2 days\n"+
+		"Busiest day: Tuesday March 4, 2 events\n"+
+		"\n"+
+		"March: 3 events on 2 days\n"+
+		"July: 1 event on 1 day\n", out.String())
+
+	out.Reset()
+	printHeatmapSummary(events, 2025, "b", deps)
+	require.Contains(t, out.String(), "2025, b: 1 event on 1 day\n")
+}
@@ -0,0 +1,344 @@
+package tracking
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/components"
+	"github.com/footprint-tools/cli/internal/ui/splitpanel"
+)
+
+// heatmapGlyphs are the cell glyphs for intensity levels 0-4.
+var heatmapGlyphs = []string{"·", "░", "▒", "▓", "█"}
+
+// View implements tea.Model
+func (m heatmapModel) View() string {
+	if m.width == 0 || m.height == 0 {
+		return ""
+	}
+
+	headerHeight := 3
+	footerHeight := 2
+	mainHeight := max(1, m.height-headerHeight-footerHeight)
+
+	cfg := splitpanel.Config{
+		SidebarWidthPercent: 0.20,
+		SidebarMinWidth:     18,
+		SidebarMaxWidth:     26,
+		HasDrawer:           true,
+		DrawerWidthPercent:  0.35,
+	}
+	layout := splitpanel.NewLayout(m.width, cfg, m.colors)
+	layout.SetFocusedPanel(m.focusedPanel)
+	layout.SetDrawerOpen(m.drawerOpen)
+
+	sidebar := m.buildReposPanel(layout, mainHeight)
+	calendar := m.buildCalendarPanel(layout)
+
+	var main string
+	if m.drawerOpen {
+		drawer := m.buildDayDrawer(layout, mainHeight)
+		main = layout.RenderWithDrawer(sidebar, calendar, &drawer, mainHeight)
+	} else {
+		main = layout.Render(sidebar, calendar, mainHeight)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, m.renderHeader(), main, m.renderFooter())
+}
+
+func (m heatmapModel) renderHeader() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.colors.Info))
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Muted))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Warning))
+
+	total := 0
+	for _, c := range m.dayCounts {
+		total += c
+	}
+
+	content := titleStyle.Render("fp heatmap") +
+		mutedStyle.Render(" | ") + titleStyle.Render(fmt.Sprintf("%d", m.year)) +
+		mutedStyle.Render(" | Events: ") + titleStyle.Render(formatCount(total))
+
+	if m.filterRepo != "" {
+		content += mutedStyle.Render(" | Repo: ") + warnStyle.Render(m.repoName(m.filterRepo))
+	}
+
+	count := m.dayCounts[m.selected.Format(dayKeyLayout)]
+	content += mutedStyle.Render(" | ") + mutedStyle.Render(m.selected.Format("Mon ")+format.DateShort(m.selected)+": ") +
+		titleStyle.Render(formatCount(count))
+
+	return lipgloss.NewStyle().Width(m.width).Padding(0, 1).Render(content)
+}
+
+func (m heatmapModel) repoName(id string) string {
+	for _, r := range m.repos {
+		if r.id == id {
+			return r.name
+		}
+	}
+	return id
+}
+
+func (m heatmapModel) buildReposPanel(layout *splitpanel.Layout, height int) splitpanel.Panel {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.colors.Header))
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Muted))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Info))
+	activeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.UIActive)).Bold(true)
+
+	width := layout.SidebarContentWidth()
+	lines := []string{headerStyle.Render("REPOS"), ""}
+
+	entries := append([]heatmapRepo{{name: "All repos"}}, m.repos...)
+	for i, r := range entries {
+		indicator := "  "
+		if r.id == m.filterRepo {
+			indicator = "> "
+		}
+
+		name := r.name
+		countStr := ""
+		if i > 0 {
+			countStr = formatCount(r.count)
+		}
+		maxName := max(4, width-len(indicator)-len(countStr)-1)
+		if len(name) > maxName {
+			name = name[:maxName-3] + "..."
+		}
+
+		nameRendered := labelStyle.Render(name)
+		if i == m.repoCursor && m.focusedPanel == 1 {
+			nameRendered = activeStyle.Render(name)
+		} else if r.id == m.filterRepo {
+			nameRendered = valueStyle.Render(name)
+		}
+		lines = append(lines, indicator+nameRendered+" "+valueStyle.Render(countStr))
+	}
+
+	// Keep the cursor visible
+	visible := max(1, height-2)
+	scroll := max(0, m.repoCursor+2-visible+1)
+	scroll = min(scroll, max(0, len(lines)-visible))
+	end := min(len(lines), scroll+visible)
+
+	return splitpanel.Panel{
+		Lines:      lines[scroll:end],
+		ScrollPos:  scroll,
+		TotalItems: len(lines),
+	}
+}
+
+func (m heatmapModel) buildCalendarPanel(layout *splitpanel.Layout) splitpanel.Panel {
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Muted))
+	cellStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Success))
+	selectedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color(m.colors.UIActive))
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.colors.Header))
+
+	const labelWidth = 4
+	width := layout.MainContentWidth()
+	start := calendarStart(m.year)
+	totalWeeks := weekIndex(time.Date(m.year, 12, 31, 0, 0, 0, 0, time.Local)) + 1
+
+	// Use two-column cells when they fit, otherwise one
+	cellWidth := 2
+	if labelWidth+totalWeeks*cellWidth > width {
+		cellWidth = 1
+	}
+
+	// Scroll horizontally so the selected week stays visible
+	visibleWeeks := max(1, min(totalWeeks, (width-labelWidth)/cellWidth))
+	firstWeek := 0
+	if sel := weekIndex(m.selected); sel >= visibleWeeks {
+		firstWeek = sel - visibleWeeks + 1
+	}
+
+	var lines []string
+
+	// Month labels
+	monthRow := []rune(strings.Repeat(" ", labelWidth+visibleWeeks*cellWidth))
+	for month := time.January; month <= time.December; month++ {
+		col := weekIndex(time.Date(m.year, month, 1, 0, 0, 0, 0, time.Local)) - firstWeek
+		if col < 0 || col >= visibleWeeks {
+			continue
+		}
+		pos := labelWidth + col*cellWidth
+		for i, r := range month.String()[:3] {
+			if pos+i < len(monthRow) {
+				monthRow[pos+i] = r
+			}
+		}
+	}
+	lines = append(lines, mutedStyle.Render(string(monthRow)))
+
+	// One row per weekday, from week_start; Mon, Wed and Fri are labeled
+	for weekday := 0; weekday < 7; weekday++ {
+		var b strings.Builder
+		label := ""
+		switch wd := start.AddDate(0, 0, weekday).Weekday(); wd {
+		case time.Monday, time.Wednesday, time.Friday:
+			label = wd.String()[:3]
+		}
+		b.WriteString(mutedStyle.Render(padRight(label, labelWidth)))
+
+		for w := firstWeek; w < firstWeek+visibleWeeks; w++ {
+			day := start.AddDate(0, 0, w*7+weekday)
+			if day.Year() != m.year {
+				b.WriteString(strings.Repeat(" ", cellWidth))
+				continue
+			}
+
+			level := intensityLevel(m.dayCounts[day.Format(dayKeyLayout)], m.maxCount)
+			cell := padRight(heatmapGlyphs[level], cellWidth)
+			switch {
+			case day.Equal(m.selected):
+				b.WriteString(selectedStyle.Render(cell))
+			case level == 0:
+				b.WriteString(mutedStyle.Render(cell))
+			default:
+				b.WriteString(cellStyle.Render(cell))
+			}
+		}
+		lines = append(lines, b.String())
+	}
+
+	// Legend
+	legend := mutedStyle.Render("Less ")
+	for i, g := range heatmapGlyphs {
+		if i == 0 {
+			legend += mutedStyle.Render(g) + " "
+		} else {
+			legend += cellStyle.Render(g) + " "
+		}
+	}
+	lines = append(lines, "", padRight("", labelWidth)+legend+mutedStyle.Render("More"))
+
+	// Year summary
+	activeDays := 0
+	busiestDay, busiestCount := "", 0
+	for day, c := range m.dayCounts {
+		if c > 0 {
+			activeDays++
+		}
+		if c > busiestCount || (c == busiestCount && day < busiestDay) {
+			busiestDay, busiestCount = day, c
+		}
+	}
+
+	lines = append(lines, "", headerStyle.Render("SUMMARY"), "")
+	lines = append(lines, mutedStyle.Render("Active days:    ")+formatCount(activeDays))
+	lines = append(lines, mutedStyle.Render("Longest streak: ")+formatCount(longestStreak(m.dayCounts, m.year))+" days")
+	if busiestCount > 0 {
+		if t, err := time.ParseInLocation(dayKeyLayout, busiestDay, time.Local); err == nil {
+			lines = append(lines, mutedStyle.Render("Busiest day:    ")+format.Date(t)+" ("+formatCount(busiestCount)+")")
+		}
+	}
+
+	return splitpanel.Panel{
+		Lines:      lines,
+		TotalItems: len(lines),
+	}
+}
+
+// heatmapLanguages is how many of a day's languages its drawer lists.
+const heatmapLanguages = 5
+
+func (m *heatmapModel) buildDayDrawer(layout *splitpanel.Layout, height int) splitpanel.Panel {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.colors.Header))
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Muted))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Info))
+
+	width := layout.DrawerContentWidth()
+	events := m.dayEvents()
+
+	lines := []string{headerStyle.Render(strings.ToUpper(m.selected.Format("Monday, ") + format.DateLong(m.selected))), ""}
+	if len(events) == 0 {
+		lines = append(lines, labelStyle.Render("No activity"))
+	}
+
+	if len(m.languages) > 0 {
+		langWidth := 0
+		for _, l := range m.languages[:min(len(m.languages), heatmapLanguages)] {
+			langWidth = max(langWidth, len(l.Language))
+		}
+		lines = append(lines, headerStyle.Render("LANGUAGES"))
+		for _, l := range m.languages[:min(len(m.languages), heatmapLanguages)] {
+			lines = append(lines, labelStyle.Render(fmt.Sprintf("%-*s ", langWidth, l.Language))+
+				valueStyle.Render(fmt.Sprintf("+%d -%d", l.Insertions, l.Deletions)))
+		}
+		lines = append(lines, "")
+	}
+
+	for _, e := range events {
+		meta := m.commitMeta[e.Commit]
+		sourceStyle := lipgloss.NewStyle().Foreground(m.sourceColor(e.Source)).Bold(true)
+
+		lines = append(lines,
+			labelStyle.Render(format.Time(e.Timestamp))+" "+
+				sourceStyle.Render(sourceName(e.Source))+" "+
+				valueStyle.Render(fmt.Sprintf("%.7s", e.Commit)))
+		lines = append(lines, labelStyle.Render(filepath.Base(e.RepoPath)+" · "+e.Branch))
+		if meta.Subject != "" {
+			for _, l := range strings.Split(wrapTextSimple(meta.Subject, width-2), "\n") {
+				lines = append(lines, valueStyle.Render(l))
+			}
+		}
+		lines = append(lines, "")
+	}
+
+	visibleHeight := max(1, height-2)
+	m.drawerViewport.SetSize(width, visibleHeight)
+	m.drawerViewport.SetContent(strings.Join(lines, "\n"))
+
+	scrollPos := m.drawerViewport.YOffset()
+	startIdx := min(scrollPos, len(lines))
+	endIdx := min(startIdx+visibleHeight, len(lines))
+
+	return splitpanel.Panel{
+		Lines:      lines[startIdx:endIdx],
+		ScrollPos:  scrollPos,
+		TotalItems: len(lines),
+	}
+}
+
+func (m heatmapModel) sourceColor(source store.Source) lipgloss.Color {
+	return activityModel{colors: m.colors}.sourceColor(source)
+}
+
+func (m heatmapModel) renderFooter() string {
+	help := components.NewThemedHelp()
+	tabBinding := key.NewBinding(key.WithKeys("tab"), key.WithHelp("Tab", "focus"))
+
+	var bindings []key.Binding
+	switch {
+	case m.focusedPanel == 2 && m.drawerOpen:
+		bindings = []key.Binding{
+			tabBinding,
+			key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "close")),
+			key.NewBinding(key.WithKeys("j", "k"), key.WithHelp("jk", "scroll")),
+		}
+	case m.focusedPanel == 1:
+		bindings = []key.Binding{
+			tabBinding,
+			key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+			key.NewBinding(key.WithKeys("j", "k"), key.WithHelp("jk", "nav")),
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "filter")),
+			key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear")),
+		}
+	default:
+		bindings = []key.Binding{
+			tabBinding,
+			key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+			key.NewBinding(key.WithKeys("h", "j", "k", "l"), key.WithHelp("hjkl", "move")),
+			key.NewBinding(key.WithKeys("[", "]"), key.WithHelp("[ ]", "year")),
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "day")),
+		}
+	}
+
+	return lipgloss.NewStyle().Width(m.width).Padding(0, 1).Render(help.ShortHelpView(bindings))
+}
@@ -1,6 +1,7 @@
 package tracking
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -8,6 +9,15 @@ import (
 	"github.com/footprint-tools/cli/internal/store"
 )
 
+// setupHint follows an error opening the database, pointing at fp setup
+// unless the database is only locked.
+func setupHint(err error) string {
+	if errors.Is(err, store.ErrLocked) {
+		return ""
+	}
+	return "\nHint: Run 'fp setup' to initialize tracking in this repository"
+}
+
 var statusMap = map[string]store.Status{
 	"pending":  store.StatusPending,
 	"exported": store.StatusExported,
@@ -23,6 +33,9 @@ var sourceMap = map[string]store.Source{
 	"pre-push":      store.SourcePrePush,
 	"manual":        store.SourceManual,
 	"backfill":      store.SourceBackfill,
+	"branch-create": store.SourceBranchCreate,
+	"branch-delete": store.SourceBranchDelete,
+	"stash":         store.SourceStash,
 }
 
 func resolvePath(args []string) (string, error) {
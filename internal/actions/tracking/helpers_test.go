@@ -120,6 +120,9 @@ func TestParseSource_Valid(t *testing.T) {
 		{"MANUAL", store.SourceManual},
 		{"backfill", store.SourceBackfill},
 		{"BACKFILL", store.SourceBackfill},
+		{"branch-create", store.SourceBranchCreate},
+		{"branch-delete", store.SourceBranchDelete},
+		{"stash", store.SourceStash},
 	}
 
 	for _, tt := range tests {
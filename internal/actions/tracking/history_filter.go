@@ -0,0 +1,155 @@
+package tracking
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// Swapped out in tests.
+var (
+	commitPatchIDs     = git.PatchIDs
+	firstParentCommits = git.FirstParentCommits
+)
+
+// historyFilter drops history churn from events, so counts reflect the
+// work done rather than how it was merged or rewritten. It is set by
+// --no-merges, --first-parent and --dedupe-rewrites.
+type historyFilter struct {
+	// noMerges drops merge commits and post-merge events (pulls)
+	noMerges bool
+	// firstParent drops commits that are not on the first-parent history
+	// of their branch: those a merge brought in from another branch
+	firstParent bool
+	// dedupeRewrites counts each change once: events of commits with the
+	// same patch-id, as after a rebase or an amend of the message, collapse
+	// into the newest
+	dedupeRewrites bool
+}
+
+func parseHistoryFilter(flags *dispatchers.ParsedFlags) historyFilter {
+	return historyFilter{
+		noMerges:       flags.Has("--no-merges"),
+		firstParent:    flags.Has("--first-parent"),
+		dedupeRewrites: flags.Has("--dedupe-rewrites"),
+	}
+}
+
+// active reports whether h drops anything.
+func (h historyFilter) active() bool {
+	return h.noMerges || h.firstParent || h.dedupeRewrites
+}
+
+// apply returns the events h keeps, in their order. Parents are read
+// through metas; events whose repository can't be read are kept.
+func (h historyFilter) apply(events []store.RepoEvent, metas *commitMetaReader) []store.RepoEvent {
+	if !h.active() {
+		return events
+	}
+
+	kept := make([]store.RepoEvent, 0, len(events))
+	onBranch := make(map[string]map[string]bool) // by repo path and branch
+	for _, e := range events {
+		if h.noMerges && isMergeEvent(e, metas) {
+			continue
+		}
+		if h.firstParent && !onFirstParent(e, onBranch) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	if h.dedupeRewrites {
+		kept = dedupeRewrites(kept)
+	}
+	return kept
+}
+
+// skip marks the pending events h drops as skipped, so they are never
+// exported, and returns the rest.
+func (h historyFilter) skip(db *sql.DB, events []store.RepoEvent) []store.RepoEvent {
+	if !h.active() {
+		return events
+	}
+	keep := make(map[int64]bool, len(events))
+	for _, e := range h.apply(events, newCommitMetaReader(db)) {
+		keep[e.ID] = true
+	}
+	return skipEvents(db, events, func(e store.RepoEvent) bool { return keep[e.ID] }, "as merges or rewrites")
+}
+
+// isMergeEvent reports whether e records a merge: a post-merge event, or
+// a commit with more than one parent.
+func isMergeEvent(e store.RepoEvent, metas *commitMetaReader) bool {
+	if e.Source == store.SourcePostMerge {
+		return true
+	}
+	return len(strings.Fields(metas.Of(e).ParentCommits)) > 1
+}
+
+// onFirstParent reports whether the commit of e is on the first-parent
+// history of its branch. Histories are read once per branch into cache;
+// when a branch can't be read (deleted, or the clone is gone) its events
+// are kept.
+func onFirstParent(e store.RepoEvent, cache map[string]map[string]bool) bool {
+	if e.Branch == "" || e.Branch == "HEAD" || e.Commit == "" {
+		return true
+	}
+	key := e.RepoPath + "\x00" + e.Branch
+	commits, ok := cache[key]
+	if !ok {
+		var err error
+		commits, err = firstParentCommits(e.RepoPath, e.Branch)
+		if err != nil {
+			log.Debug("history: could not read the history of %s in %s: %v", e.Branch, e.RepoPath, err)
+		}
+		cache[key] = commits
+	}
+	return commits == nil || commits[e.Commit]
+}
+
+// dedupeRewrites keeps the newest event of each change in a repository.
+// A change is a patch-id, or the commit itself when it has none (merges,
+// empty commits, clones that are gone).
+func dedupeRewrites(events []store.RepoEvent) []store.RepoEvent {
+	byRepo := make(map[string][]string) // commits by repo path
+	for _, e := range events {
+		byRepo[e.RepoPath] = append(byRepo[e.RepoPath], e.Commit)
+	}
+	patchIDs := make(map[string]string) // by repo path and commit
+	for repoPath, commits := range byRepo {
+		ids, err := commitPatchIDs(repoPath, commits)
+		if err != nil {
+			log.Debug("history: could not read patch-ids in %s: %v", repoPath, err)
+			continue
+		}
+		for commit, id := range ids {
+			patchIDs[repoPath+"\x00"+commit] = id
+		}
+	}
+
+	changeOf := func(e store.RepoEvent) string {
+		if id, ok := patchIDs[e.RepoPath+"\x00"+e.Commit]; ok {
+			return e.RepoID + "\x00patch\x00" + id
+		}
+		return e.RepoID + "\x00commit\x00" + e.Commit
+	}
+	newest := make(map[string]int) // index into events, by change
+	for i, e := range events {
+		change := changeOf(e)
+		if j, seen := newest[change]; !seen || e.Timestamp.After(events[j].Timestamp) {
+			newest[change] = i
+		}
+	}
+
+	kept := make([]store.RepoEvent, 0, len(newest))
+	for i, e := range events {
+		if newest[changeOf(e)] == i {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
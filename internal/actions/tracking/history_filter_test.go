@@ -0,0 +1,122 @@
+package tracking
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeHistory swaps the git reads of historyFilter: parents by commit,
+// first-parent histories by branch and patch-ids by commit.
+func fakeHistory(t *testing.T, parents map[string]string, branches map[string][]string, patches map[string]string) *commitMetaReader {
+	t.Helper()
+
+	restorePatches, restoreFirst := commitPatchIDs, firstParentCommits
+	t.Cleanup(func() { commitPatchIDs, firstParentCommits = restorePatches, restoreFirst })
+	commitPatchIDs = func(_ string, commits []string) (map[string]string, error) {
+		ids := make(map[string]string)
+		for _, c := range commits {
+			if id, ok := patches[c]; ok {
+				ids[c] = id
+			}
+		}
+		return ids, nil
+	}
+	firstParentCommits = func(_, branch string) (map[string]bool, error) {
+		commits, ok := branches[branch]
+		if !ok {
+			return nil, errors.New("no such branch")
+		}
+		set := make(map[string]bool)
+		for _, c := range commits {
+			set[c] = true
+		}
+		return set, nil
+	}
+
+	return &commitMetaReader{
+		read: func(_, commit string) git.CommitMetadata {
+			return git.CommitMetadata{ParentCommits: parents[commit]}
+		},
+		seen: make(map[string]git.CommitMetadata),
+	}
+}
+
+func historyEvent(id int64, commit, branch string, source store.Source, minute int) store.RepoEvent {
+	return store.RepoEvent{
+		ID: id, RepoID: "local:/r", RepoPath: "/r", Commit: commit, Branch: branch,
+		Source: source, Timestamp: time.Date(2025, 3, 1, 9, minute, 0, 0, time.UTC),
+	}
+}
+
+func commitsOf(events []store.RepoEvent) []string {
+	var commits []string
+	for _, e := range events {
+		commits = append(commits, e.Commit)
+	}
+	return commits
+}
+
+func TestHistoryFilter_NoMerges(t *testing.T) {
+	metas := fakeHistory(t, map[string]string{"merge1": "aaa bbb", "work1": "aaa"}, nil, nil)
+	events := []store.RepoEvent{
+		historyEvent(1, "work1", "main", store.SourcePostCommit, 1),
+		historyEvent(2, "merge1", "main", store.SourceBackfill, 2),
+		historyEvent(3, "pulled", "main", store.SourcePostMerge, 3),
+	}
+
+	kept := historyFilter{noMerges: true}.apply(events, metas)
+
+	require.Equal(t, []string{"work1"}, commitsOf(kept))
+}
+
+func TestHistoryFilter_FirstParent(t *testing.T) {
+	metas := fakeHistory(t, nil, map[string][]string{"main": {"mine", "merge1"}}, nil)
+	events := []store.RepoEvent{
+		historyEvent(1, "mine", "main", store.SourcePostCommit, 1),
+		historyEvent(2, "theirs", "main", store.SourceBackfill, 2),
+		historyEvent(3, "merge1", "main", store.SourcePostMerge, 3),
+		historyEvent(4, "old", "deleted-branch", store.SourcePostCommit, 4),
+	}
+
+	kept := historyFilter{firstParent: true}.apply(events, metas)
+
+	require.Equal(t, []string{"mine", "merge1", "old"}, commitsOf(kept), "events of branches that can't be read are kept")
+}
+
+func TestHistoryFilter_DedupeRewritesKeepsNewest(t *testing.T) {
+	metas := fakeHistory(t, nil, nil, map[string]string{"before": "p1", "after": "p1", "other": "p2"})
+	events := []store.RepoEvent{
+		historyEvent(1, "before", "feature", store.SourcePostCommit, 1),
+		historyEvent(2, "other", "feature", store.SourcePostCommit, 2),
+		historyEvent(3, "after", "feature", store.SourcePostRewrite, 3),
+		historyEvent(4, "after", "feature", store.SourcePrePush, 4),
+		historyEvent(5, "merge1", "main", store.SourcePostMerge, 5),
+	}
+
+	kept := historyFilter{dedupeRewrites: true}.apply(events, metas)
+
+	require.Equal(t, []string{"other", "after", "merge1"}, commitsOf(kept))
+	require.Equal(t, int64(4), kept[1].ID)
+}
+
+func TestHistoryFilter_SkipMarksDroppedEvents(t *testing.T) {
+	db := newTestStore(t).DB()
+	fakeHistory(t, nil, nil, nil)
+
+	require.NoError(t, store.InsertEvent(db, historyEvent(0, "aaaaaaa", "main", store.SourcePostCommit, 1)))
+	require.NoError(t, store.InsertEvent(db, historyEvent(0, "bbbbbbb", "main", store.SourcePostMerge, 2)))
+	events, err := store.GetPendingEvents(db)
+	require.NoError(t, err)
+
+	kept := historyFilter{noMerges: true}.skip(db, events)
+	require.Equal(t, []string{"aaaaaaa"}, commitsOf(kept))
+
+	pending, err := store.GetPendingEvents(db)
+	require.NoError(t, err)
+	require.Len(t, pending, 1, "the merge should be skipped")
+}
@@ -0,0 +1,169 @@
+package tracking
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/hooks"
+	"github.com/footprint-tools/cli/internal/output"
+)
+
+// Outcomes of fp hooks upgrade on one repository.
+const (
+	batchUpgraded     = "upgraded"
+	batchWouldUpgrade = "would upgrade"
+	batchUpToDate     = "up to date"
+)
+
+// HooksUpgrade rewrites fp hooks written by an older fp with the current
+// script.
+func HooksUpgrade(args []string, flags *dispatchers.ParsedFlags) error {
+	return hooksUpgrade(args, flags, DefaultDeps())
+}
+
+// hooksUpgrade upgrades the hooks of the current repository, or with --all
+// of every tracked repository and every one found under --root, plus the
+// global hooks directory when fp manages it. Hooks fp didn't install are
+// never touched.
+func hooksUpgrade(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	jsonOutput := flags.Has("--json")
+	dryRun := flags.Has("--dry-run")
+
+	var dirs []upgradeTarget
+	if flags.Has("--all") {
+		root := flags.String("--root", ".")
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			return fmt.Errorf("invalid path %s: %w", root, err)
+		}
+		if !jsonOutput {
+			_, _ = deps.Printf("Scanning for git repositories in %s...\n", absRoot)
+		}
+		if dirs, err = allUpgradeTargets(deps, absRoot, flags.Int("--depth", 25)); err != nil {
+			return err
+		}
+	} else {
+		repoRoot, err := deps.RepoRoot(".")
+		if err != nil {
+			return errors.New("not in a git repository (use --all to upgrade every repository)")
+		}
+		hooksPath, err := git.RepoHooksPath(repoRoot)
+		if err != nil {
+			return fmt.Errorf("could not find hooks directory: %w", err)
+		}
+		if !hasFpHooks(hooksPath) {
+			return fmt.Errorf("fp hooks are not installed in %s (run 'fp setup')", repoRoot)
+		}
+		dirs = []upgradeTarget{{path: repoRoot, hooksPath: hooksPath}}
+	}
+
+	results := make([]batchResult, 0, len(dirs))
+	failed := 0
+	for _, dir := range dirs {
+		res := upgradeHooks(dir, dryRun)
+		if res.Result == batchFailed {
+			failed++
+		}
+		results = append(results, res)
+	}
+
+	if jsonOutput {
+		if err := output.JSON(deps.Println, results); err != nil {
+			return err
+		}
+	} else {
+		printBatchSummary(results, deps)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d %s failed", failed, pluralize(failed, "repository", "repositories"))
+	}
+	return nil
+}
+
+// upgradeTarget is a hooks directory to upgrade and the repository, or
+// label, it is reported under.
+type upgradeTarget struct {
+	path      string
+	hooksPath string
+}
+
+// allUpgradeTargets returns the global hooks directory if fp manages it,
+// then every tracked or scanned repository with fp hooks of its own.
+// Repositories that use the global hooks are covered by its row.
+func allUpgradeTargets(deps Deps, root string, maxDepth int) ([]upgradeTarget, error) {
+	var targets []upgradeTarget
+	seen := make(map[string]bool)
+
+	if global := hooks.CheckGlobalHooksStatus(); global.IsFpManaged {
+		targets = append(targets, upgradeTarget{path: "global hooks (" + global.Path + ")", hooksPath: global.Path})
+		seen[filepath.Clean(global.Path)] = true
+	}
+
+	var paths []string
+	if s, err := deps.OpenStore(deps.DBPath()); err == nil {
+		paths, _ = s.ListRepoPaths()
+		_ = s.Close()
+	}
+	scanned, err := scanForRepos(root, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range scanned {
+		paths = append(paths, r.Path)
+	}
+
+	for _, path := range paths {
+		hooksPath, err := git.RepoHooksPath(path)
+		if err != nil || seen[filepath.Clean(hooksPath)] {
+			continue
+		}
+		seen[filepath.Clean(hooksPath)] = true
+		if hasFpHooks(hooksPath) {
+			targets = append(targets, upgradeTarget{path: path, hooksPath: hooksPath})
+		}
+	}
+	return targets, nil
+}
+
+// hasFpHooks reports whether any managed hook in hooksPath is fp's.
+func hasFpHooks(hooksPath string) bool {
+	for _, state := range hooks.States(hooksPath) {
+		if state == hooks.HookCurrent || state == hooks.HookOutdated {
+			return true
+		}
+	}
+	return false
+}
+
+func upgradeHooks(dir upgradeTarget, dryRun bool) batchResult {
+	res := batchResult{Path: dir.path}
+
+	if dryRun {
+		outdated := hooks.Outdated(dir.hooksPath)
+		if len(outdated) == 0 {
+			res.Result = batchUpToDate
+			return res
+		}
+		res.Result = batchWouldUpgrade
+		res.Detail = strings.Join(outdated, ", ")
+		return res
+	}
+
+	upgraded, err := hooks.Refresh(dir.hooksPath)
+	switch {
+	case err != nil:
+		res.Result = batchFailed
+		res.Detail = err.Error()
+	case len(upgraded) == 0:
+		res.Result = batchUpToDate
+	default:
+		res.Result = batchUpgraded
+		res.Detail = strings.Join(upgraded, ", ")
+	}
+	return res
+}
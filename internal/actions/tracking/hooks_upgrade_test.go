@@ -0,0 +1,69 @@
+package tracking
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/hooks"
+)
+
+// installOldHooks installs fp hooks in repo and rewrites post-commit the
+// way an older fp did.
+func installOldHooks(t *testing.T, repo string) {
+	t.Helper()
+	hooksPath := filepath.Join(repo, ".git", "hooks")
+	require.NoError(t, hooks.Install(hooksPath))
+	require.NoError(t, os.WriteFile(filepath.Join(hooksPath, "post-commit"), []byte("#!/bin/sh\nfp record post-commit\n"), 0o755))
+}
+
+func TestHooksUpgrade_All(t *testing.T) {
+	root := batchTestRoot(t, "old", "fresh", "untracked")
+	installOldHooks(t, filepath.Join(root, "old"))
+	require.NoError(t, hooks.Install(filepath.Join(root, "fresh", ".git", "hooks")))
+	foreign := filepath.Join(root, "untracked", ".git", "hooks", "post-commit")
+	require.NoError(t, os.WriteFile(foreign, []byte("#!/bin/sh\necho mine\n"), 0o755))
+
+	var out strings.Builder
+	deps := batchTestDeps(t, &out)
+
+	flags := dispatchers.NewParsedFlags([]string{"--all", "--root=" + root, "--dry-run", "--json"})
+	require.NoError(t, hooksUpgrade(nil, flags, deps))
+	var results []batchResult
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &results))
+	require.Equal(t, []batchResult{
+		{Path: filepath.Join(root, "fresh"), Result: batchUpToDate},
+		{Path: filepath.Join(root, "old"), Result: batchWouldUpgrade, Detail: "post-commit"},
+	}, results, "repos without fp hooks are not listed")
+	require.Equal(t, hooks.HookOutdated, hooks.States(filepath.Join(root, "old", ".git", "hooks"))["post-commit"], "--dry-run changes nothing")
+
+	out.Reset()
+	require.NoError(t, hooksUpgrade(nil, dispatchers.NewParsedFlags([]string{"--all", "--root=" + root}), deps))
+	require.Contains(t, out.String(), "up to date: 1, upgraded: 1")
+	require.Empty(t, hooks.Outdated(filepath.Join(root, "old", ".git", "hooks")))
+
+	data, err := os.ReadFile(foreign)
+	require.NoError(t, err)
+	require.Equal(t, "#!/bin/sh\necho mine\n", string(data), "hooks fp didn't install are left alone")
+}
+
+func TestHooksUpgrade_CurrentRepo(t *testing.T) {
+	root := batchTestRoot(t, "repo")
+	repo := filepath.Join(root, "repo")
+
+	var out strings.Builder
+	deps := batchTestDeps(t, &out)
+	deps.RepoRoot = func(string) (string, error) { return repo, nil }
+
+	err := hooksUpgrade(nil, dispatchers.NewParsedFlags(nil), deps)
+	require.ErrorContains(t, err, "fp hooks are not installed")
+
+	installOldHooks(t, repo)
+	require.NoError(t, hooksUpgrade(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.Contains(t, out.String(), "upgraded (post-commit)")
+}
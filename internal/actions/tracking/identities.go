@@ -0,0 +1,82 @@
+package tracking
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// commitAuthorEmail is swapped out in tests.
+var commitAuthorEmail = git.CommitAuthorEmail
+
+var errNoIdentities = errors.New("--mine needs your author emails\nHint: fp config set identities you@work.com,you@home.dev")
+
+// identityMatcher tells which of the configured identities authored an
+// event's commit. Authors are read from git once per commit.
+type identityMatcher struct {
+	emails  []string
+	authors map[string]string
+}
+
+func newIdentityMatcher(emails []string) *identityMatcher {
+	return &identityMatcher{
+		emails:  emails,
+		authors: make(map[string]string),
+	}
+}
+
+// loadIdentityMatcher returns a matcher for the identities key, or nil when
+// none are configured.
+func loadIdentityMatcher() *identityMatcher {
+	emails := config.Identities()
+	if len(emails) == 0 {
+		return nil
+	}
+	return newIdentityMatcher(emails)
+}
+
+// author returns the lowercased author email of e's commit, or "" if the
+// commit can't be read.
+func (m *identityMatcher) author(e store.RepoEvent) string {
+	key := e.RepoPath + "\x00" + e.Commit
+	author, ok := m.authors[key]
+	if !ok {
+		author = strings.ToLower(commitAuthorEmail(e.RepoPath, e.Commit))
+		m.authors[key] = author
+	}
+	return author
+}
+
+// Of returns the identity that authored e, or "" if the author is someone
+// else or the commit can't be read.
+func (m *identityMatcher) Of(e store.RepoEvent) string {
+	if author := m.author(e); slices.Contains(m.emails, author) {
+		return author
+	}
+	return ""
+}
+
+// Filter returns the events authored by one of the identities.
+func (m *identityMatcher) Filter(events []store.RepoEvent) []store.RepoEvent {
+	mine := make([]store.RepoEvent, 0, len(events))
+	for _, e := range events {
+		if m.Of(e) != "" {
+			mine = append(mine, e)
+		}
+	}
+	return mine
+}
+
+// skipForeignEvents marks pending events authored by someone else as skipped,
+// so they are never exported, and returns the ones authored by one of the
+// identities. Events whose commit can't be read, such as one in a
+// repository that moved, are neither: they stay pending until it can.
+func skipForeignEvents(db *sql.DB, events []store.RepoEvent, m *identityMatcher) []store.RepoEvent {
+	known := skipEvents(db, events, func(e store.RepoEvent) bool { return m.author(e) == "" || m.Of(e) != "" }, "by other authors")
+	return slices.DeleteFunc(known, func(e store.RepoEvent) bool { return m.Of(e) == "" })
+}
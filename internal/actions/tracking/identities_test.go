@@ -0,0 +1,44 @@
+package tracking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkipForeignEvents(t *testing.T) {
+	db := newTestStore(t).DB()
+
+	restore := commitAuthorEmail
+	t.Cleanup(func() { commitAuthorEmail = restore })
+	commitAuthorEmail = func(_, commit string) string {
+		switch commit {
+		case "aaaaaaa":
+			return "ME@home.dev"
+		case "ccccccc":
+			return "" // can't be read
+		}
+		return "other@example.com"
+	}
+
+	for _, commit := range []string{"aaaaaaa", "bbbbbbb", "ccccccc"} {
+		require.NoError(t, store.InsertEvent(db, store.RepoEvent{
+			RepoID: "local:/r", RepoPath: "/r", Commit: commit, Branch: "main",
+			Timestamp: time.Now(), Status: store.StatusPending, Source: store.SourcePostCommit,
+		}))
+	}
+	events, err := store.GetPendingEvents(db)
+	require.NoError(t, err)
+
+	mine := skipForeignEvents(db, events, newIdentityMatcher([]string{"me@home.dev"}))
+	require.Len(t, mine, 1)
+	require.Equal(t, "aaaaaaa", mine[0].Commit)
+
+	pending, err := store.GetPendingEvents(db)
+	require.NoError(t, err)
+	require.Len(t, pending, 2, "the other author's event should be skipped")
+	commits := []string{pending[0].Commit, pending[1].Commit}
+	require.ElementsMatch(t, []string{"aaaaaaa", "ccccccc"}, commits, "an unreadable commit stays pending")
+}
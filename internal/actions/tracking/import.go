@@ -0,0 +1,306 @@
+package tracking
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// importResult summarizes an import run.
+type importResult struct {
+	Files    []string `json:"files"`
+	Found    int      `json:"found"`
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped"`
+	Invalid  int      `json:"invalid"`
+}
+
+// Import handles the `fp import` command.
+func Import(args []string, flags *dispatchers.ParsedFlags) error {
+	return importEvents(args, flags, DefaultDeps())
+}
+
+// importEvents merges events exported on another machine into the local
+// store. Events already stored for the same repo_id and commit_hash are
+// skipped, so importing the same file twice is harmless. Imported events
+// were exported already, by the machine that recorded them, so they are
+// stored as exported unless --pending asks for them to be exported again.
+func importEvents(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	jsonOutput := flags.Has("--json")
+	dryRun := flags.Has("--dry-run")
+	status := store.StatusExported
+	if flags.Has("--pending") {
+		status = store.StatusPending
+	}
+
+	files, err := importFiles(args[0])
+	if err != nil {
+		return err
+	}
+
+	result := importResult{Files: files}
+	var events []store.RepoEvent
+	for _, file := range files {
+		fileEvents, invalid, err := readImportFile(file)
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", file, err)
+		}
+		for i := range fileEvents {
+			fileEvents[i].Status = status
+		}
+		events = append(events, fileEvents...)
+		result.Invalid += invalid
+	}
+	result.Found = len(events)
+
+	if !dryRun && len(events) > 0 {
+		dbPath := deps.DBPath()
+		db, err := deps.OpenDB(dbPath)
+		if err != nil {
+			return fmt.Errorf("could not open database at %s: %w", dbPath, err)
+		}
+		defer store.CloseDB(db)
+
+		if err := deps.InitDB(db); err != nil {
+			return fmt.Errorf("could not initialize database: %w", err)
+		}
+
+		result.Imported, err = store.ImportEvents(db, events)
+		if err != nil {
+			return fmt.Errorf("could not import events: %w", err)
+		}
+		result.Skipped = result.Found - result.Imported
+	}
+
+	if jsonOutput {
+		return output.JSON(deps.Println, result)
+	}
+
+	if dryRun {
+		_, _ = deps.Printf("Would import up to %d events from %d files (already stored commits are skipped)\n", result.Found, len(files))
+	} else {
+		_, _ = deps.Printf("Imported %d events from %d files (%d already present)\n", result.Imported, len(files), result.Skipped)
+		if status == store.StatusPending && result.Imported > 0 {
+			_, _ = deps.Println("They are pending and go out with the next export")
+		}
+	}
+	if result.Invalid > 0 {
+		_, _ = deps.Printf("Ignored %d rows without a repo_id, commit_hash or valid timestamp\n", result.Invalid)
+	}
+	return nil
+}
+
+// importFiles resolves the import argument to the files to read. A
+// directory (such as an export repository) yields its CSV files, plus
+// JSONL files that have no CSV sibling.
+func importFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+
+	if !info.IsDir() {
+		switch strings.ToLower(filepath.Ext(importPlainName(path))) {
+		case ".csv", ".jsonl":
+			return []string{path}, nil
+		default:
+			return nil, fmt.Errorf("invalid import file '%s': expected .csv or .jsonl, optionally encrypted (.age, .gpg)", path)
+		}
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+
+	// Encrypted files are only read when their plaintext isn't there, as
+	// in an export repository cloned from an encrypted remote
+	plain := make(map[string]bool)
+	for _, entry := range entries {
+		plain[entry.Name()] = !isEncryptedExport(entry.Name())
+	}
+
+	csvStems := make(map[string]bool)
+	var csvFiles, jsonlFiles []string
+	for _, entry := range entries {
+		name := entry.Name()
+		plainName := importPlainName(name)
+		if entry.IsDir() || (plainName != name && plain[plainName]) {
+			continue
+		}
+		stem := strings.TrimSuffix(plainName, filepath.Ext(plainName))
+		switch strings.ToLower(filepath.Ext(plainName)) {
+		case ".csv":
+			csvStems[stem] = true
+			csvFiles = append(csvFiles, name)
+		case ".jsonl":
+			jsonlFiles = append(jsonlFiles, name)
+		}
+	}
+
+	files := make([]string, 0, len(csvFiles)+len(jsonlFiles))
+	for _, name := range csvFiles {
+		files = append(files, filepath.Join(path, name))
+	}
+	for _, name := range jsonlFiles {
+		plainName := importPlainName(name)
+		if !csvStems[strings.TrimSuffix(plainName, filepath.Ext(plainName))] {
+			files = append(files, filepath.Join(path, name))
+		}
+	}
+	sort.Strings(files)
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .csv or .jsonl files in %s", path)
+	}
+	return files, nil
+}
+
+// importPlainName returns the name of an import file without its
+// encryption suffix: commits.csv for commits.csv.age.
+func importPlainName(name string) string {
+	if isEncryptedExport(name) {
+		return strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
+
+// readImportFile parses an exported CSV or JSONL file into events,
+// decrypting it first if it is encrypted (see export_encrypt_recipient).
+// Returns the events and the number of rows that couldn't be used.
+func readImportFile(path string) ([]store.RepoEvent, int, error) {
+	var data []byte
+	var err error
+	if isEncryptedExport(path) {
+		identity, _ := config.Get("export_encrypt_identity")
+		data, err = decryptExportFile(path, strings.TrimSpace(identity))
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if strings.EqualFold(filepath.Ext(importPlainName(path)), ".jsonl") {
+		return readImportJSONL(bytes.NewReader(data))
+	}
+	return readImportCSV(bytes.NewReader(data))
+}
+
+func readImportCSV(r io.Reader) ([]store.RepoEvent, int, error) {
+	lines, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(lines) == 0 {
+		return nil, 0, nil
+	}
+	header, rows := lines[0], lines[1:]
+
+	columns := make(map[string]int, len(header))
+	for i, col := range header {
+		columns[strings.TrimSpace(col)] = i
+	}
+	for _, required := range []string{"repo_id", "commit_hash", "timestamp"} {
+		if _, ok := columns[required]; !ok {
+			return nil, 0, fmt.Errorf("missing column '%s' (not an fp export?)", required)
+		}
+	}
+
+	var events []store.RepoEvent
+	invalid := 0
+	for _, row := range rows {
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return row[i]
+		}
+		e, ok := importedEvent(field)
+		if !ok {
+			invalid++
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, invalid, nil
+}
+
+func readImportJSONL(r io.Reader) ([]store.RepoEvent, int, error) {
+	var events []store.RepoEvent
+	invalid := 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			invalid++
+			continue
+		}
+		field := func(name string) string {
+			switch v := record[name].(type) {
+			case string:
+				return v
+			case nil:
+				return ""
+			default:
+				return fmt.Sprint(v)
+			}
+		}
+		e, ok := importedEvent(field)
+		if !ok {
+			invalid++
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, invalid, scanner.Err()
+}
+
+// importedEvent builds a pending event from an export record. It keeps the
+// source the record names; records from exports that predate the source
+// column are stored as backfill events, like history imported from git.
+func importedEvent(field func(string) string) (store.RepoEvent, bool) {
+	repoID := strings.TrimSpace(field("repo_id"))
+	commit := strings.TrimSpace(field("commit_hash"))
+	timestamp, err := time.Parse(time.RFC3339, strings.TrimSpace(field("timestamp")))
+	if repoID == "" || commit == "" || err != nil {
+		return store.RepoEvent{}, false
+	}
+	source, ok := domain.ParseEventSource(strings.TrimSpace(field("source")))
+	if !ok {
+		source = store.SourceBackfill
+	}
+
+	return store.RepoEvent{
+		RepoID:    repoID,
+		Commit:    commit,
+		Branch:    strings.TrimSpace(field("branch")),
+		Timestamp: timestamp,
+		Status:    store.StatusPending,
+		Source:    source,
+		Note:      field("note"),
+		Tag:       strings.TrimSpace(field("tag")),
+		Device:    strings.TrimSpace(field("device")),
+	}, true
+}
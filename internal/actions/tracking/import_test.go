@@ -0,0 +1,180 @@
+package tracking
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func importDeps(dbPath string, printed *[]string) Deps {
+	return Deps{
+		DBPath: func() string { return dbPath },
+		OpenDB: openDBFresh,
+		InitDB: store.Init,
+		Printf: func(format string, a ...any) (int, error) {
+			*printed = append(*printed, fmt.Sprintf(format, a...))
+			return 0, nil
+		},
+		Println: func(a ...any) (int, error) {
+			*printed = append(*printed, fmt.Sprint(a...))
+			return 0, nil
+		},
+	}
+}
+
+func writeImportCSV(t *testing.T, path string, rows ...[]string) {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString(strings.Join(csvHeader, ",") + "\n")
+	for _, row := range rows {
+		b.WriteString(strings.Join(row, ",") + "\n")
+	}
+	require.NoError(t, os.WriteFile(path, []byte(b.String()), 0600))
+}
+
+func importRow(repoID, commit, timestamp string) []string {
+	row := make([]string, len(csvHeader))
+	for i, col := range csvHeader {
+		switch col {
+		case "repo_id":
+			row[i] = repoID
+		case "commit_hash":
+			row[i] = commit
+		case "timestamp":
+			row[i] = timestamp
+		case "branch":
+			row[i] = "main"
+		}
+	}
+	return row
+}
+
+func TestImport_CSVDeduplicates(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "commits.csv")
+	writeImportCSV(t, file,
+		importRow("github.com/user/repo", "aaa", "2024-01-15T10:00:00Z"),
+		importRow("github.com/user/repo", "bbb", "2024-01-16T10:00:00Z"),
+		importRow("github.com/user/repo", "aaa", "2024-01-15T10:00:00Z"),
+		importRow("", "ccc", "2024-01-17T10:00:00Z"),
+	)
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+
+	var printed []string
+	err := importEvents([]string{file}, dispatchers.NewParsedFlags(nil), importDeps(dbPath, &printed))
+	require.NoError(t, err)
+	require.Contains(t, printed[0], "Imported 2 events from 1 files (1 already present)")
+	require.Contains(t, printed[1], "Ignored 1 rows")
+
+	printed = nil
+	err = importEvents([]string{file}, dispatchers.NewParsedFlags(nil), importDeps(dbPath, &printed))
+	require.NoError(t, err)
+	require.Contains(t, printed[0], "Imported 0 events")
+
+	s, err := store.New(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+	events, err := store.ListEvents(s.DB(), store.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, store.SourceBackfill, events[0].Source, "rows without a source")
+	require.Equal(t, store.StatusExported, events[0].Status, "imported events were exported where they came from")
+}
+
+func TestImport_KeepsSourceAndPending(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "commits.csv")
+	merge := importRow("github.com/user/repo", "aaa", "2024-01-15T10:00:00Z")
+	merge[slices.Index(csvHeader, "source")] = "post-merge"
+	writeImportCSV(t, file, merge)
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(file), "watch.jsonl"),
+		[]byte(`{"repo_id":"github.com/user/repo","commit":"bbb","commit_hash":"bbb","timestamp":"2024-01-16T10:00:00Z","source":"PRE-PUSH"}`+"\n"), 0600))
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+
+	var printed []string
+	flags := dispatchers.NewParsedFlags([]string{"--pending"})
+	require.NoError(t, importEvents([]string{file}, flags, importDeps(dbPath, &printed)))
+	require.NoError(t, importEvents([]string{filepath.Join(filepath.Dir(file), "watch.jsonl")}, flags, importDeps(dbPath, &printed)))
+
+	s, err := store.New(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+	events, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	sources := []store.Source{events[0].Source, events[1].Source}
+	require.ElementsMatch(t, []store.Source{store.SourcePostMerge, store.SourcePrePush}, sources)
+}
+
+func TestImport_DirectoryPrefersCSV(t *testing.T) {
+	dir := t.TempDir()
+	writeImportCSV(t, filepath.Join(dir, "commits.csv"), importRow("github.com/user/repo", "aaa", "2024-01-15T10:00:00Z"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "commits.jsonl"), []byte(`{"repo_id":"x","commit_hash":"zzz","timestamp":"2024-01-15T10:00:00Z"}`+"\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "laptop.jsonl"),
+		[]byte(`{"repo_id":"github.com/user/other","commit_hash":"ddd","timestamp":"2024-02-01T09:00:00Z","files_changed":3}`+"\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("notes"), 0600))
+
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+	var printed []string
+	err := importEvents([]string{dir}, dispatchers.NewParsedFlags([]string{"--json"}), importDeps(dbPath, &printed))
+	require.NoError(t, err)
+
+	var result importResult
+	require.NoError(t, json.Unmarshal([]byte(printed[0]), &result))
+	require.Len(t, result.Files, 2, "commits.jsonl duplicates commits.csv")
+	require.Equal(t, 2, result.Imported)
+}
+
+func TestImport_DryRunDoesNotWrite(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "commits.csv")
+	writeImportCSV(t, file, importRow("github.com/user/repo", "aaa", "2024-01-15T10:00:00Z"))
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+
+	var printed []string
+	err := importEvents([]string{file}, dispatchers.NewParsedFlags([]string{"--dry-run"}), importDeps(dbPath, &printed))
+	require.NoError(t, err)
+	require.Contains(t, printed[0], "Would import up to 1 events")
+	require.NoFileExists(t, dbPath)
+}
+
+func TestImport_RejectsOtherFiles(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "notes.txt")
+	require.NoError(t, os.WriteFile(file, []byte("hi"), 0600))
+
+	err := importEvents([]string{file}, dispatchers.NewParsedFlags(nil), Deps{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expected .csv or .jsonl")
+
+	csvFile := filepath.Join(t.TempDir(), "other.csv")
+	require.NoError(t, os.WriteFile(csvFile, []byte("a,b\n1,2\n"), 0600))
+	err = importEvents([]string{csvFile}, dispatchers.NewParsedFlags(nil), Deps{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing column 'repo_id'")
+}
+
+func TestImport_KeepsDevice(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "commits.csv")
+	row := importRow("github.com/user/repo", "aaa", "2024-01-15T10:00:00Z")
+	row[slices.Index(csvHeader, "device")] = "desktop"
+	writeImportCSV(t, file, row)
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+
+	var printed []string
+	require.NoError(t, importEvents([]string{file}, dispatchers.NewParsedFlags(nil), importDeps(dbPath, &printed)))
+
+	s, err := store.New(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+	events, err := store.ListEvents(s.DB(), store.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "desktop", events[0].Device)
+}
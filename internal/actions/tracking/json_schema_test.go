@@ -0,0 +1,109 @@
+package tracking
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// These tests pin the keys of fp's --json output. Scripts depend on them:
+// a failure here means a key was renamed or removed. If that's intended,
+// bump output.SchemaVersion, note it in the data help topic, and update
+// the lists below. New keys only need adding to the lists.
+
+// jsonKeys marshals v and returns the keys of the resulting object.
+func jsonKeys(t *testing.T, v any) []string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	var obj map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &obj))
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedKeys(keys ...string) []string {
+	sort.Strings(keys)
+	return keys
+}
+
+var eventSchemaKeys = []string{
+	"schema_version", "id", "repo_id", "repo_path", "commit", "branch", "timestamp",
+	"status", "source", "cwd", "note", "tag", "device", "author", "message",
+}
+
+// fullEvent has every optional field set, so omitempty keys show up.
+func fullEvent() store.RepoEvent {
+	return store.RepoEvent{
+		ID: 1, RepoID: "github.com/me/app", RepoPath: "/code/app", Commit: "abc", Branch: "main",
+		Timestamp: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC), Status: store.StatusPending,
+		Source: store.SourcePostCommit, Cwd: "cmd", Note: "n", Tag: "t", Device: "laptop",
+	}
+}
+
+func TestJSONSchema_Activity(t *testing.T) {
+	je := toJSONEvent(fullEvent(), nil, nil, nil, nil)
+	require.Equal(t, output.SchemaVersion, je.SchemaVersion)
+	je.Author, je.Message, je.Identity = "a", "m", "work"
+	je.Fields = map[string]string{"ticket.id": "PROJ-1"}
+	je.DefaultBranch, je.FeatureBranch = "main", true
+	require.Equal(t, sortedKeys(append(eventSchemaKeys, "identity", "fields", "default_branch", "feature_branch")...), jsonKeys(t, je))
+
+	g := jsonGroup{SchemaVersion: output.SchemaVersion, Key: "k", RepoID: "r", Count: 1, Events: []jsonEvent{je}}
+	require.Equal(t, sortedKeys("schema_version", "key", "repo_id", "count", "events"), jsonKeys(t, g))
+}
+
+func TestJSONSchema_Watch(t *testing.T) {
+	var line string
+	capture := func(a ...any) (int, error) { line = a[0].(string); return 0, nil }
+	require.NoError(t, outputEventJSON(fullEvent(), nil, capture))
+
+	var obj map[string]any
+	require.NoError(t, json.Unmarshal([]byte(line), &obj))
+	require.EqualValues(t, output.SchemaVersion, obj["schema_version"])
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	require.Equal(t, sortedKeys("schema_version", "id", "repo_id", "repo_path", "commit", "branch",
+		"timestamp", "status", "source", "cwd", "note", "tag", "device"), keys)
+}
+
+func TestJSONSchema_Status(t *testing.T) {
+	report := statusReport{
+		SchemaVersion: output.SchemaVersion,
+		Checks:        []setupCheck{{ID: "db", Optional: true, Detail: "d", Fix: []string{"fp setup"}}},
+		LastExport:    new(time.Time),
+		Repos:         []repoHealth{{Path: "/code/app", RepoID: "r", Branch: "main", HooksPath: "h", LastEvent: &repoLastEvent{}, Error: "e"}},
+	}
+	require.Equal(t, sortedKeys("schema_version", "ready", "checks", "last_export", "repos"), jsonKeys(t, report))
+	require.Equal(t, sortedKeys("id", "title", "ok", "optional", "detail", "fix"), jsonKeys(t, report.Checks[0]))
+	require.Equal(t, sortedKeys("path", "repo_id", "branch", "hooks_path", "hooks", "hooks_current",
+		"last_event", "pending", "error"), jsonKeys(t, report.Repos[0]))
+}
+
+func TestJSONSchema_Stats(t *testing.T) {
+	report := statsReport{SchemaVersion: output.SchemaVersion, Project: "p", Threshold: 3, Days: []focusDay{{Over: true}}}
+	require.Equal(t, sortedKeys("schema_version", "project", "since", "until", "events",
+		"average_context_switches", "context_switch_threshold", "days_over_threshold", "days",
+		"default_branch_events", "feature_branch_events"), jsonKeys(t, report))
+	require.Equal(t, sortedKeys("date", "events", "repos", "context_switches", "over_threshold"), jsonKeys(t, report.Days[0]))
+}
+
+func TestJSONSchema_Doctor(t *testing.T) {
+	results := doctorJSON([]doctorResult{{ID: "db", Hint: "h", Fixed: true}})
+	require.Equal(t, output.SchemaVersion, results[0].SchemaVersion)
+	require.Equal(t, sortedKeys("schema_version", "id", "name", "status", "severity", "detail",
+		"hint", "fixable", "fixed"), jsonKeys(t, results[0]))
+}
@@ -23,18 +23,26 @@ func Log(args []string, flags *dispatchers.ParsedFlags) error {
 	if (flags.Has("--interactive") || flags.Has("-i")) && flags.Has("--json") {
 		return usage.ConflictingFlags("--interactive", "--json")
 	}
-	// Route to interactive mode if --interactive or -i flag is present
-	if flags.Has("--interactive") || flags.Has("-i") {
-		return WatchInteractive(args, flags)
+	if (flags.Has("--interactive") || flags.Has("-i")) && flags.Has("--plain") {
+		return usage.ConflictingFlags("--interactive", "--plain")
 	}
-	return logCmd(args, flags, DefaultDeps())
+	if (flags.Has("--interactive") || flags.Has("-i")) && flags.String("--root", "") != "" {
+		return usage.ConflictingFlags("--interactive", "--root")
+	}
+	// Route to interactive mode if --interactive or -i flag is present,
+	// except in accessible mode, where the stream of plain lines is kept
+	deps := DefaultDeps()
+	if (flags.Has("--interactive") || flags.Has("-i")) && !isAccessible(deps) {
+		return watchInteractive(args, flags, deps)
+	}
+	return logCmd(args, flags, deps)
 }
 
 func logCmd(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	dbPath := deps.DBPath()
 	db, err := deps.OpenDB(dbPath)
 	if err != nil {
-		return fmt.Errorf("failed to open database at %s: %w\nHint: Run 'fp setup' to initialize tracking in this repository", dbPath, err)
+		return fmt.Errorf("failed to open database at %s: %w%s", dbPath, err, setupHint(err))
 	}
 	defer store.CloseDB(db)
 
@@ -46,6 +54,7 @@ func logCmd(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	// Parse display flags
 	oneline := flags.Has("--oneline")
 	jsonOutput := flags.Has("--json")
+	plain := flags.Has("--plain")
 	enrich := flags.Has("--enrich")
 
 	// Parse filter flags
@@ -71,6 +80,10 @@ func logCmd(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		filter.RepoID = &repoID
 	}
 
+	if dir := flags.String("--path", ""); dir != "" {
+		filter.Path = &dir
+	}
+
 	// Get current max ID as starting point (we only want new events)
 	lastID, err := store.GetMaxEventID(db)
 	if err != nil {
@@ -78,7 +91,29 @@ func logCmd(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		lastID = 0
 	}
 
-	fmt.Fprintln(os.Stderr, "Watching for new events... (Ctrl+C to stop)")
+	// With --root, also watch the directory tree for repositories and
+	// branches the hooks don't see
+	var workspace *workspaceWatcher
+	if dir := flags.String("--root", ""); dir != "" {
+		root, err := workspaceRoot(dir)
+		if err != nil {
+			return err
+		}
+		if workspace, err = newWorkspaceWatcher(root, flags.Int("--depth", 25), deps.Now); err != nil {
+			return err
+		}
+		if !plain {
+			untracked := workspace.untracked()
+			fmt.Fprintf(os.Stderr, "Watching %d %s under %s (%d without fp hooks)\n",
+				len(workspace.repos), pluralize(len(workspace.repos), "repository", "repositories"), root, len(untracked))
+		}
+	}
+
+	// Plain output is meant for pipes and status bars, so it stays silent
+	// apart from the events themselves.
+	if !plain {
+		fmt.Fprintln(os.Stderr, "Watching for new events... (Ctrl+C to stop)")
+	}
 
 	// Setup signal handling for clean shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -97,29 +132,61 @@ func logCmd(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		}
 	}()
 
+	bell := streamBell(plain, jsonOutput)
+
 	// Polling loop
 	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
+	var workspaceTick <-chan time.Time
+	if workspace != nil {
+		workspaceTicker := time.NewTicker(workspacePollInterval)
+		defer workspaceTicker.Stop()
+		workspaceTick = workspaceTicker.C
+	}
+
+	// scanDone is set while a rescan of the root is running; a tick that
+	// arrives meanwhile is dropped rather than queued.
+	var scanDone <-chan workspaceScan
+
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
+		case <-workspaceTick:
+			if scanDone == nil {
+				scanDone = workspace.pollAsync()
+			}
+		case scan := <-scanDone:
+			scanDone = nil
+			if scan.err != nil {
+				continue
+			}
+			if len(scan.signals) > 0 && bell != nil {
+				bell()
+			}
+			for _, s := range scan.signals {
+				if err := writeWorkspaceSignal(s, jsonOutput, plain, oneline, deps.Println); err != nil {
+					return nil
+				}
+			}
 		case <-ticker.C:
 			events, err := store.ListEventsSinceFiltered(db, lastID, filter)
 			if err != nil {
 				continue
 			}
+			if len(events) > 0 && bell != nil {
+				bell()
+			}
 
+			var metas *commitMetaReader
+			if enrich {
+				metas = newCommitMetaReader(db)
+			}
 			for _, event := range events {
-				switch {
-				case jsonOutput:
-					outputEventJSON(event, enrich)
-				case enrich:
-					meta := git.GetCommitMetadata(event.RepoPath, event.Commit)
-					_, _ = fmt.Fprintln(os.Stdout, formatEventEnriched(event, meta, oneline))
-				default:
-					_, _ = fmt.Fprintln(os.Stdout, formatEvent(event, oneline))
+				if err := writeWatchEvent(event, jsonOutput, plain, metas, oneline, deps.Println); err != nil {
+					// Whoever was reading (head, a closed pipe) is gone.
+					return nil
 				}
 				// Note: int64 overflow is not a practical concern (max ~9 quintillion).
 				// A negative ID would indicate database corruption.
@@ -131,8 +198,33 @@ func logCmd(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	}
 }
 
-func outputEventJSON(e store.RepoEvent, enrich bool) {
+// writeWatchEvent prints one streamed event in the requested format, with
+// the metadata of its commit when metas is set.
+func writeWatchEvent(e store.RepoEvent, jsonOutput, plain bool, metas *commitMetaReader, oneline bool, println func(...any) (int, error)) error {
+	var meta *git.CommitMetadata
+	if metas != nil {
+		m := metas.Of(e)
+		meta = &m
+	}
+
+	var err error
+	switch {
+	case jsonOutput:
+		err = outputEventJSON(e, meta, println)
+	case plain:
+		_, err = println(formatEventPlain(e, meta))
+	case meta != nil:
+		_, err = println(formatEventEnriched(e, *meta, oneline))
+	default:
+		_, err = println(formatEvent(e, oneline))
+	}
+	return err
+}
+
+func outputEventJSON(e store.RepoEvent, meta *git.CommitMetadata, println func(...any) (int, error)) error {
 	type jsonEvent struct {
+		SchemaVersion int `json:"schema_version"`
+
 		ID        int64  `json:"id"`
 		RepoID    string `json:"repo_id"`
 		RepoPath  string `json:"repo_path"`
@@ -141,11 +233,17 @@ func outputEventJSON(e store.RepoEvent, enrich bool) {
 		Timestamp string `json:"timestamp"`
 		Status    string `json:"status"`
 		Source    string `json:"source"`
+		Cwd       string `json:"cwd,omitempty"`
+		Note      string `json:"note,omitempty"`
+		Tag       string `json:"tag,omitempty"`
+		Device    string `json:"device,omitempty"`
 		Author    string `json:"author,omitempty"`
 		Message   string `json:"message,omitempty"`
 	}
 
 	je := jsonEvent{
+		SchemaVersion: output.SchemaVersion,
+
 		ID:        e.ID,
 		RepoID:    e.RepoID,
 		RepoPath:  e.RepoPath,
@@ -154,12 +252,15 @@ func outputEventJSON(e store.RepoEvent, enrich bool) {
 		Timestamp: e.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
 		Status:    e.Status.String(),
 		Source:    e.Source.String(),
+		Cwd:       e.Cwd,
+		Note:      e.Note,
+		Tag:       e.Tag,
+		Device:    e.Device,
 	}
-	if enrich {
-		meta := git.GetCommitMetadata(e.RepoPath, e.Commit)
+	if meta != nil {
 		je.Author = meta.AuthorName
 		je.Message = meta.Subject
 	}
 
-	_ = output.JSONLine(fmt.Println, je)
+	return output.JSONLine(println, je)
 }
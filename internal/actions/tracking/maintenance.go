@@ -0,0 +1,319 @@
+package tracking
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/paths"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+const (
+	// maintenanceInterval is how often the daemon runs maintenance.
+	maintenanceInterval = 7 * 24 * time.Hour
+	// maxLogBytes is the log size above which maintenance rotates the log.
+	maxLogBytes = 10 << 20
+)
+
+// maintenanceTask is one housekeeping step run by `fp maintenance`.
+// run returns a one-line summary of what it did.
+type maintenanceTask struct {
+	name string
+	run  func(s *store.Store, deps Deps) (string, error)
+}
+
+// maintenanceTasks run in order. Pruning and orphan cleanup come before
+// the metadata trim, so the commits they leave without events go too, and
+// all three come before vacuum so the space they free is reclaimed in the
+// same run.
+var maintenanceTasks = []maintenanceTask{
+	{name: "prune", run: pruneExpiredEvents},
+	{name: "orphans", run: cleanOrphanedEvents},
+	{name: "meta", run: cleanUnusedCommitMeta},
+	{name: "pushes", run: settleAllPushes},
+	{name: "vacuum", run: vacuumDatabase},
+	{name: "logs", run: rotateLog},
+	{name: "compact", run: compactExport},
+	{name: "squash", run: squashExportIfDue},
+	{name: "export", run: verifyExportRepo},
+}
+
+// maintenanceResult is the outcome of one maintenance task.
+type maintenanceResult struct {
+	Task    string `json:"task"`
+	Summary string `json:"summary,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Maintenance runs all housekeeping tasks and prints a summary.
+func Maintenance(args []string, flags *dispatchers.ParsedFlags) error {
+	return maintenance(args, flags, DefaultDeps())
+}
+
+func maintenance(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	s, err := deps.OpenStore(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("could not open database: %w", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	start := deps.Now()
+	results := runMaintenance(s, deps)
+	_ = saveMaintenanceLast(deps.Now().Unix())
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+
+	if flags.Has("--json") {
+		if err := output.JSON(deps.Println, results); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range results {
+			if r.Error != "" {
+				_, _ = deps.Printf("  %-8s FAILED: %s\n", r.Task, r.Error)
+			} else {
+				_, _ = deps.Printf("  %-8s %s\n", r.Task, r.Summary)
+			}
+		}
+		_, _ = deps.Printf("\nMaintenance finished in %s\n", deps.Now().Sub(start).Round(time.Millisecond))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d maintenance tasks failed", failed, len(results))
+	}
+	return nil
+}
+
+// runMaintenance runs every task, continuing past failures.
+func runMaintenance(s *store.Store, deps Deps) []maintenanceResult {
+	results := make([]maintenanceResult, 0, len(maintenanceTasks))
+	for _, task := range maintenanceTasks {
+		summary, err := task.run(s, deps)
+		result := maintenanceResult{Task: task.name, Summary: summary}
+		if err != nil {
+			log.Warn("maintenance: %s failed: %v", task.name, err)
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// MaintenanceIfDue runs maintenance if a week has passed since the last run.
+// Used by the daemon; results go to the log.
+func MaintenanceIfDue() error {
+	if config.IsReadOnly() {
+		return nil
+	}
+	deps := DefaultDeps()
+
+	lastStr, _ := config.Get("maintenance_last")
+	last, _ := strconv.ParseInt(lastStr, 10, 64)
+	if deps.Now().Sub(time.Unix(last, 0)) < maintenanceInterval {
+		return nil
+	}
+
+	s, err := deps.OpenStore(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("could not open database: %w", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	for _, r := range runMaintenance(s, deps) {
+		if r.Error == "" {
+			log.Info("maintenance: %s: %s", r.Task, r.Summary)
+		}
+	}
+	return saveMaintenanceLast(deps.Now().Unix())
+}
+
+func saveMaintenanceLast(timestamp int64) error {
+	lines, err := config.ReadLines()
+	if err != nil {
+		return err
+	}
+
+	lines, _ = config.Set(lines, "maintenance_last", strconv.FormatInt(timestamp, 10))
+	return config.WriteLines(lines)
+}
+
+// pruneExpiredEvents deletes exported events older than retention_days.
+func pruneExpiredEvents(s *store.Store, deps Deps) (string, error) {
+	value, _ := config.Get("retention_days")
+	if value == "" || value == "0" {
+		return "skipped (retention_days not set)", nil
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		return "", fmt.Errorf("invalid retention_days '%s': expected a number of days", value)
+	}
+
+	cutoff := deps.Now().AddDate(0, 0, -days)
+	deleted, err := s.PruneExported(cutoff)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("removed %d exported events older than %d days", deleted, days), nil
+}
+
+// cleanOrphanedEvents deletes events whose repository no longer exists.
+func cleanOrphanedEvents(s *store.Store, _ Deps) (string, error) {
+	deleted, err := s.DeleteOrphaned()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("removed %d orphaned events", deleted), nil
+}
+
+// vacuumDatabase compacts the database file.
+func vacuumDatabase(s *store.Store, deps Deps) (string, error) {
+	before := fileSize(deps.DBPath())
+	if err := s.Vacuum(); err != nil {
+		return "", err
+	}
+	after := fileSize(deps.DBPath())
+
+	return fmt.Sprintf("database is %s (reclaimed %s)", formatBytes(after), formatBytes(max(0, before-after))), nil
+}
+
+// rotateLog moves the log aside once it grows past maxLogBytes.
+func rotateLog(_ *store.Store, _ Deps) (string, error) {
+	logPath := paths.LogFilePath()
+	rotated, err := log.Rotate(logPath, maxLogBytes)
+	if err != nil {
+		return "", err
+	}
+	if rotated {
+		return fmt.Sprintf("rotated %s", filepath.Base(logPath)), nil
+	}
+	return fmt.Sprintf("%s is %s, no rotation needed", filepath.Base(logPath), formatBytes(fileSize(logPath))), nil
+}
+
+// compactExport rewrites the export CSVs that exports have appended to
+// (see appendCSVRecords) and commits them. They are pushed with the next
+// export.
+func compactExport(_ *store.Store, deps Deps) (string, error) {
+	exportRepo := deps.GetExportRepo()
+	if diagnoseExportRepo(exportRepo) != exportRepoClean {
+		return "nothing to compact", nil
+	}
+
+	files, err := compactExportCSVs(exportRepo)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "no appended rows to compact", nil
+	}
+	format, err := resolveExportFormat("")
+	if err != nil {
+		return "", err
+	}
+	if err := commitExportFiles(exportRepo, files, format, deps.Now()); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("compacted %s", strings.Join(files, ", ")), nil
+}
+
+// squashExportIfDue squashes the export repo's history into one snapshot
+// commit once its oldest commit is more than export_squash_days old, as
+// fp export --squash does.
+func squashExportIfDue(_ *store.Store, deps Deps) (string, error) {
+	value, _ := config.Get("export_squash_days")
+	if value == "" || value == "0" {
+		return "skipped (export_squash_days not set)", nil
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		return "", fmt.Errorf("invalid export_squash_days '%s': expected a number of days", value)
+	}
+
+	exportRepo := deps.GetExportRepo()
+	if diagnoseExportRepo(exportRepo) != exportRepoClean {
+		return "nothing to squash", nil
+	}
+	oldest := oldestExportCommit(exportRepo)
+	if oldest.IsZero() || deps.Now().Sub(oldest) < time.Duration(days)*24*time.Hour {
+		return fmt.Sprintf("history is less than %d days old", days), nil
+	}
+
+	result, err := squashExportRepo(exportRepo, deps.Now())
+	if err != nil {
+		return "", err
+	}
+	if result.Snapshot == "" {
+		return "history is already a single commit", nil
+	}
+	return fmt.Sprintf("squashed %d commits into %.7s", result.Commits, result.Snapshot), nil
+}
+
+// verifyExportRepo checks the export repo is in a usable state and that
+// every CSV in it parses with the expected number of columns.
+func verifyExportRepo(_ *store.Store, deps Deps) (string, error) {
+	exportRepo := deps.GetExportRepo()
+
+	state := diagnoseExportRepo(exportRepo)
+	if state == exportRepoMissing {
+		return "no export repository yet", nil
+	}
+	if state != exportRepoClean {
+		return "", fmt.Errorf("export repo has %s; run 'fp export repair'", state)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(exportRepo, "*.csv"))
+	rows := 0
+	var broken []string
+	for _, path := range files {
+		header, records, err := readCSVRows(path)
+		if err != nil {
+			broken = append(broken, filepath.Base(path))
+			continue
+		}
+		for _, record := range records {
+			if len(record) != len(header) {
+				broken = append(broken, filepath.Base(path))
+				break
+			}
+		}
+		rows += len(records)
+	}
+
+	if len(broken) > 0 {
+		return "", fmt.Errorf("unreadable CSV files: %s; run 'fp export repair'", strings.Join(broken, ", "))
+	}
+	return fmt.Sprintf("%d files, %d records OK", len(files), rows), nil
+}
+
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// formatBytes renders a byte count as B, KB or MB.
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
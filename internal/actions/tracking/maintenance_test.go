@@ -0,0 +1,93 @@
+package tracking
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func maintenanceDeps(t *testing.T, printed *[]string) (Deps, string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+	return Deps{
+		DBPath:        func() string { return dbPath },
+		OpenStore:     store.New,
+		GetExportRepo: func() string { return filepath.Join(home, "exports") },
+		Now:           time.Now,
+		Printf: func(format string, a ...any) (int, error) {
+			*printed = append(*printed, fmt.Sprintf(format, a...))
+			return 0, nil
+		},
+		Println: func(a ...any) (int, error) {
+			*printed = append(*printed, fmt.Sprint(a...))
+			return 0, nil
+		},
+	}, dbPath
+}
+
+func TestMaintenance_RunsAllTasks(t *testing.T) {
+	var printed []string
+	deps, dbPath := maintenanceDeps(t, &printed)
+
+	lines, _ := config.Set(nil, "retention_days", "30")
+	require.NoError(t, config.WriteLines(lines))
+
+	s, err := store.New(dbPath)
+	require.NoError(t, err)
+	old := time.Now().AddDate(0, 0, -60).UTC()
+	require.NoError(t, store.InsertEvent(s.DB(), store.RepoEvent{RepoID: "r", Commit: "a", Branch: "main", Timestamp: old, Status: store.StatusExported, Source: store.SourcePostCommit}))
+	require.NoError(t, store.InsertEvent(s.DB(), store.RepoEvent{RepoID: "r", Commit: "b", Branch: "main", Timestamp: old, Status: store.StatusPending, Source: store.SourcePostCommit}))
+	require.NoError(t, store.InsertEvent(s.DB(), store.RepoEvent{RepoID: "r", Commit: "c", Branch: "main", Timestamp: old, Status: store.StatusOrphaned, Source: store.SourcePostCommit}))
+	for _, commit := range []string{"a", "b", "c"} {
+		require.NoError(t, store.SaveCommitMeta(s.DB(), "r", commit, git.CommitMetadata{Subject: commit}))
+	}
+	require.NoError(t, s.Close())
+
+	err = maintenance(nil, dispatchers.NewParsedFlags(nil), deps)
+	require.NoError(t, err)
+
+	out := strings.Join(printed, "")
+	require.Contains(t, out, "removed 1 exported events older than 30 days")
+	require.Contains(t, out, "removed 1 orphaned events")
+	require.Contains(t, out, "removed metadata of 2 commits without events", "pruned and orphaned commits lose their metadata")
+	require.Contains(t, out, "vacuum")
+	require.Contains(t, out, "no export repository yet")
+
+	last, _ := config.Get("maintenance_last")
+	require.NotEqual(t, "0", last)
+}
+
+func TestMaintenance_ReportsBrokenExport(t *testing.T) {
+	var printed []string
+	deps, _ := maintenanceDeps(t, &printed)
+	t.Setenv("GIT_AUTHOR_NAME", "Test User")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test User")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	exportDir := deps.GetExportRepo()
+	require.NoError(t, ensureExportRepo(exportDir))
+	require.NoError(t, os.WriteFile(filepath.Join(exportDir, "commits.csv"), []byte("a,b\n1,2,3\n"), 0600))
+
+	err := maintenance(nil, dispatchers.NewParsedFlags([]string{"--json"}), deps)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "1 of 9 maintenance tasks failed")
+
+	out := strings.Join(printed, "")
+	require.Contains(t, out, `"task": "export"`)
+	require.Contains(t, out, "commits.csv")
+	require.Contains(t, out, "skipped (retention_days not set)")
+}
@@ -0,0 +1,163 @@
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/enrich"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// onEventJSON is what on_event_exec reads on stdin: the fields fp watch
+// --json prints, less the id, which isn't known at insert time.
+type onEventJSON struct {
+	SchemaVersion int `json:"schema_version"`
+
+	RepoID    string `json:"repo_id"`
+	RepoPath  string `json:"repo_path"`
+	Commit    string `json:"commit"`
+	Branch    string `json:"branch"`
+	Timestamp string `json:"timestamp"`
+	Status    string `json:"status"`
+	Source    string `json:"source"`
+	Cwd       string `json:"cwd,omitempty"`
+	Note      string `json:"note,omitempty"`
+	Tag       string `json:"tag,omitempty"`
+	Device    string `json:"device,omitempty"`
+}
+
+// Config keys of the on_event scripts: on_event_exec and its filter, and
+// on_event_exec.<name> with on_event_filter.<name> for more of them.
+const (
+	onEventExecKey   = "on_event_exec"
+	onEventFilterKey = "on_event_filter"
+)
+
+// onEventHook is a command fp record starts for the events its filter
+// selects.
+type onEventHook struct {
+	Name    string // "" for on_event_exec itself
+	Command string
+	Filter  domain.EventSelector
+}
+
+// key is the config key the hook's command is set with.
+func (h onEventHook) key() string {
+	if h.Name == "" {
+		return onEventExecKey
+	}
+	return onEventExecKey + "." + h.Name
+}
+
+// onEventHooks returns the configured on_event scripts.
+func onEventHooks() []onEventHook {
+	all, _ := config.GetAll()
+	return parseOnEventHooks(all)
+}
+
+// parseOnEventHooks builds the hooks from on_event_exec and each
+// on_event_exec.<name>, on_event_exec first and the others by name. A hook
+// whose filter doesn't parse is skipped with a warning rather than run for
+// every event.
+func parseOnEventHooks(cfg map[string]string) []onEventHook {
+	var names []string
+	for key, command := range cfg {
+		if name, ok := strings.CutPrefix(key, onEventExecKey+"."); ok && name != "" && strings.TrimSpace(command) != "" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	if strings.TrimSpace(cfg[onEventExecKey]) != "" {
+		names = append([]string{""}, names...)
+	}
+
+	hooks := make([]onEventHook, 0, len(names))
+	for _, name := range names {
+		h := onEventHook{Name: name}
+		filterKey := onEventFilterKey
+		if name != "" {
+			filterKey += "." + name
+		}
+		h.Command = strings.TrimSpace(cfg[h.key()])
+		filter, err := domain.ParseEventSelector(cfg[filterKey])
+		if err != nil {
+			log.Warn("record: not running %s, %s is invalid: %v", h.key(), filterKey, err)
+			continue
+		}
+		h.Filter = filter
+		hooks = append(hooks, h)
+	}
+	return hooks
+}
+
+// runOnEvent starts each hook's command once for each stored event its
+// filter selects, with the event as JSON on stdin. fp record runs from git
+// hooks, so it doesn't wait for the commands: they run on after it exits,
+// and their output goes nowhere. Like enrichers they are extras, so
+// failures to start one are only logged.
+func runOnEvent(hooks []onEventHook, events []store.RepoEvent) {
+	for _, e := range events {
+		for _, h := range hooks {
+			if !h.Filter.Matches(e.RepoID, e.Branch, e.Source) {
+				log.Debug("record: %s filtered out %s (commit=%.7s)", h.key(), e.Source, e.Commit)
+				continue
+			}
+			if err := startOnEvent(h.Command, e); err != nil {
+				log.Warn("record: could not run %s: %v (commit=%.7s)", h.key(), err, e.Commit)
+			}
+		}
+	}
+}
+
+func startOnEvent(command string, e store.RepoEvent) error {
+	input, err := json.Marshal(onEventJSON{
+		SchemaVersion: output.SchemaVersion,
+
+		RepoID:    e.RepoID,
+		RepoPath:  e.RepoPath,
+		Commit:    e.Commit,
+		Branch:    e.Branch,
+		Timestamp: e.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+		Status:    e.Status.String(),
+		Source:    e.Source.String(),
+		Cwd:       e.Cwd,
+		Note:      e.Note,
+		Tag:       e.Tag,
+		Device:    e.Device,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Fill a pipe before starting the command rather than copying into it
+	// from a goroutine, which would die with fp before the command read
+	// its input. An event is far smaller than a pipe's buffer.
+	stdin, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stdin.Close() }()
+	_, err = w.Write(append(input, '\n'))
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	cmd := enrich.ShellCommand(context.Background(), command)
+	cmd.Dir = e.RepoPath
+	cmd.Stdin = stdin
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	log.Debug("record: started on_event command (pid=%d, commit=%.7s)", cmd.Process.Pid, e.Commit)
+	return cmd.Process.Release()
+}
@@ -0,0 +1,110 @@
+package tracking
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestRunOnEvent(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "event.json")
+	script := filepath.Join(dir, "notify.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\ncat > "+out+".tmp && mv "+out+".tmp "+out+"\n"), 0o755))
+
+	runOnEvent([]onEventHook{{Command: script}}, []store.RepoEvent{{
+		RepoID:    "github.com/me/app",
+		RepoPath:  dir,
+		Commit:    "abcdef0123456789",
+		Branch:    "main",
+		Timestamp: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+		Status:    store.StatusPending,
+		Source:    store.SourcePostCommit,
+		Tag:       "release",
+	}})
+
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(out)
+		return err == nil
+	}, 5*time.Second, 20*time.Millisecond, "the command runs in the background with the event on stdin")
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Equal(t, "github.com/me/app", got["repo_id"])
+	require.Equal(t, "abcdef0123456789", got["commit"])
+	require.Equal(t, "2026-03-02T10:00:00Z", got["timestamp"])
+	require.Equal(t, "release", got["tag"])
+	require.NotContains(t, got, "note")
+}
+
+func TestRunOnEvent_StartFailureIsLogged(t *testing.T) {
+	// A command that can't start, in a directory that doesn't exist, must
+	// not panic or fail the record.
+	runOnEvent([]onEventHook{{Command: "true"}}, []store.RepoEvent{{RepoPath: filepath.Join(t.TempDir(), "gone")}})
+	runOnEvent(nil, []store.RepoEvent{{}})
+}
+
+func TestRunOnEvent_Filter(t *testing.T) {
+	dir := t.TempDir()
+	log := filepath.Join(dir, "fired.log")
+	hook := func(name, filter string) onEventHook {
+		sel, err := domain.ParseEventSelector(filter)
+		require.NoError(t, err)
+		return onEventHook{Name: name, Command: "echo " + name + " >> " + log, Filter: sel}
+	}
+	hooks := []onEventHook{
+		hook("all", ""),
+		hook("commits", "source:post-commit"),
+		hook("acme", "repo:github.com/acme/* !branch:wip/*"),
+	}
+
+	runOnEvent(hooks, []store.RepoEvent{
+		{RepoID: "github.com/acme/app", RepoPath: dir, Branch: "main", Source: store.SourcePostCheckout},
+		{RepoID: "github.com/me/app", RepoPath: dir, Branch: "main", Source: store.SourcePostCommit},
+		{RepoID: "github.com/acme/app", RepoPath: dir, Branch: "wip/x", Source: store.SourcePostCommit},
+	})
+
+	// all x3, commits x2, acme x1
+	require.Eventually(t, func() bool {
+		data, _ := os.ReadFile(log)
+		return strings.Count(string(data), "\n") == 6
+	}, 5*time.Second, 20*time.Millisecond)
+	data, err := os.ReadFile(log)
+	require.NoError(t, err)
+	require.Equal(t, 3, strings.Count(string(data), "all\n"))
+	require.Equal(t, 2, strings.Count(string(data), "commits\n"))
+	require.Equal(t, 1, strings.Count(string(data), "acme\n"))
+}
+
+func TestParseOnEventHooks(t *testing.T) {
+	hooks := parseOnEventHooks(map[string]string{
+		"on_event_exec":          "notify-send fp",
+		"on_event_filter":        "!source:post-checkout",
+		"on_event_exec.slack":    "~/bin/slack.sh",
+		"on_event_filter.slack":  "repo:github.com/acme/*",
+		"on_event_exec.broken":   "x",
+		"on_event_filter.broken": "colour:red",
+		"on_event_exec.blank":    " ",
+		"on_event_filter.orphan": "source:stash",
+	})
+
+	require.Len(t, hooks, 2)
+	require.Equal(t, "on_event_exec", hooks[0].key())
+	require.Equal(t, "notify-send fp", hooks[0].Command)
+	require.False(t, hooks[0].Filter.Matches("r", "main", store.SourcePostCheckout))
+	require.Equal(t, "on_event_exec.slack", hooks[1].key())
+	require.True(t, hooks[1].Filter.Matches("github.com/acme/app", "main", store.SourcePostCheckout))
+	require.False(t, hooks[1].Filter.Matches("github.com/me/app", "main", store.SourcePostCommit))
+
+	require.Empty(t, parseOnEventHooks(map[string]string{"on_event_filter": "source:stash"}))
+}
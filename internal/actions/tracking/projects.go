@@ -0,0 +1,319 @@
+package tracking
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// ProjectCreate handles `fp project create <name>`.
+func ProjectCreate(args []string, flags *dispatchers.ParsedFlags) error {
+	return projectCreate(args, flags, DefaultDeps())
+}
+
+// ProjectDelete handles `fp project delete <name>`.
+func ProjectDelete(args []string, flags *dispatchers.ParsedFlags) error {
+	return projectDelete(args, flags, DefaultDeps())
+}
+
+// ProjectAddRepo handles `fp project add-repo <name> [<path>...]`.
+func ProjectAddRepo(args []string, flags *dispatchers.ParsedFlags) error {
+	return projectAddRepo(args, flags, DefaultDeps())
+}
+
+// ProjectRemoveRepo handles `fp project remove-repo <name> <path|id>...`.
+func ProjectRemoveRepo(args []string, flags *dispatchers.ParsedFlags) error {
+	return projectRemoveRepo(args, flags, DefaultDeps())
+}
+
+// ProjectList handles `fp project list`.
+func ProjectList(args []string, flags *dispatchers.ParsedFlags) error {
+	return projectList(args, flags, DefaultDeps())
+}
+
+func projectCreate(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	name, err := projectNameArg(args)
+	if err != nil {
+		return err
+	}
+	db, err := openProjectDB(deps)
+	if err != nil {
+		return err
+	}
+	defer store.CloseDB(db)
+
+	if err := store.CreateProject(db, name); err != nil {
+		return err
+	}
+	_, _ = deps.Printf("Created project %s\n", name)
+	_, _ = deps.Printf("Add repositories with 'fp project add-repo %s <path>'\n", name)
+	return nil
+}
+
+func projectDelete(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	name, err := projectNameArg(args)
+	if err != nil {
+		return err
+	}
+	db, err := openProjectDB(deps)
+	if err != nil {
+		return err
+	}
+	defer store.CloseDB(db)
+
+	if err := store.DeleteProject(db, name); err != nil {
+		return err
+	}
+	_, _ = deps.Printf("Deleted project %s (its events are kept)\n", name)
+	return nil
+}
+
+// projectAddRepo adds repositories to a project: the one in the working
+// directory when no path is given, otherwise each path, or the id of a
+// repository fp has events for (for clones that aren't on this machine).
+func projectAddRepo(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	name, err := projectNameArg(args)
+	if err != nil {
+		return err
+	}
+	targets := args[1:]
+	if len(targets) == 0 {
+		targets = []string{"."}
+	}
+
+	db, err := openProjectDB(deps)
+	if err != nil {
+		return err
+	}
+	defer store.CloseDB(db)
+
+	for _, target := range targets {
+		repoID, repoPath, err := resolveProjectRepo(db, deps, target, true)
+		if err != nil {
+			return err
+		}
+		added, err := store.AddProjectRepo(db, name, repoID, repoPath)
+		if err != nil {
+			return err
+		}
+		if added {
+			_, _ = deps.Printf("Added %s to %s\n", repoID, name)
+		} else {
+			_, _ = deps.Printf("%s is already in %s\n", repoID, name)
+		}
+	}
+	return nil
+}
+
+func projectRemoveRepo(args []string, _ *dispatchers.ParsedFlags, deps Deps) error {
+	name, err := projectNameArg(args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 2 {
+		return usage.MissingArgument("repo")
+	}
+
+	db, err := openProjectDB(deps)
+	if err != nil {
+		return err
+	}
+	defer store.CloseDB(db)
+
+	for _, target := range args[1:] {
+		repoID, _, err := resolveProjectRepo(db, deps, target, false)
+		if err != nil {
+			return err
+		}
+		removed, err := store.RemoveProjectRepo(db, name, repoID)
+		if err != nil {
+			return err
+		}
+		if removed {
+			_, _ = deps.Printf("Removed %s from %s\n", repoID, name)
+		} else {
+			_, _ = deps.Printf("%s is not in %s\n", repoID, name)
+		}
+	}
+	return nil
+}
+
+// resolveProjectRepo turns a path or repository id into the repo_id
+// events are recorded under, and the repository root for a path. A target
+// that isn't an existing directory is taken as an id; with known set, only
+// ids fp has seen are accepted, to catch typos.
+func resolveProjectRepo(db *sql.DB, deps Deps, target string, known bool) (string, string, error) {
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		root, err := deps.RepoRoot(target)
+		if err != nil {
+			return "", "", fmt.Errorf("%s is not in a git repository", target)
+		}
+		remoteURL, _ := deps.OriginURL(root)
+		repoID, err := deps.DeriveID(remoteURL, root)
+		if err != nil {
+			return "", "", fmt.Errorf("could not derive repo id for %s: %w", root, err)
+		}
+		return string(repoID), root, nil
+	}
+
+	if known {
+		ids, err := store.ListRepoIDs(db)
+		if err != nil {
+			return "", "", err
+		}
+		if !slices.Contains(ids, target) {
+			return "", "", fmt.Errorf("%s is neither a directory nor a known repository id\nHint: 'fp activity' shows the ids of repositories with events", target)
+		}
+	}
+	return target, "", nil
+}
+
+// projectSummary is a project with its activity in the listed period.
+type projectSummary struct {
+	Name       string   `json:"name"`
+	Repos      []string `json:"repos"`
+	Events     int      `json:"events"`
+	ActiveDays int      `json:"active_days"`
+	LastEvent  string   `json:"last_event,omitempty"`
+}
+
+// projectList prints each project with its repositories and activity:
+// events, days with events and the latest event, within --since/--until
+// when given.
+func projectList(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	var since, until *time.Time
+	if sinceStr := flags.String("--since", ""); sinceStr != "" {
+		if since = flags.Date("--since"); since == nil {
+			return fmt.Errorf("invalid date '%s' for --since: expected %s", sinceStr, dispatchers.DateFormats)
+		}
+	}
+	if untilStr := flags.String("--until", ""); untilStr != "" {
+		if until = flags.Date("--until"); until == nil {
+			return fmt.Errorf("invalid date '%s' for --until: expected %s", untilStr, dispatchers.DateFormats)
+		}
+	}
+
+	db, err := openProjectDB(deps)
+	if err != nil {
+		return err
+	}
+	defer store.CloseDB(db)
+
+	projects, err := store.ListProjects(db)
+	if err != nil {
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	summaries := make([]projectSummary, 0, len(projects))
+	for _, p := range projects {
+		name := p.Name
+		events, err := deps.ListEvents(db, store.EventFilter{Project: &name, Since: since, Until: until})
+		if err != nil {
+			return fmt.Errorf("failed to list events: %w", err)
+		}
+		summaries = append(summaries, summarizeProject(p, events))
+	}
+
+	if flags.Has("--json") {
+		return output.JSON(deps.Println, summaries)
+	}
+
+	if len(summaries) == 0 {
+		_, _ = deps.Println("no projects")
+		_, _ = deps.Println("create one with 'fp project create <name>'")
+		return nil
+	}
+
+	for i, s := range summaries {
+		if i > 0 {
+			_, _ = deps.Println()
+		}
+		line := fmt.Sprintf("%s  %d %s on %d %s", style.Header(s.Name),
+			s.Events, pluralize(s.Events, "event", "events"),
+			s.ActiveDays, pluralize(s.ActiveDays, "day", "days"))
+		if s.LastEvent != "" {
+			last, _ := time.Parse(time.RFC3339, s.LastEvent)
+			line += style.Muted(", last " + format.Date(last.Local()))
+		}
+		_, _ = deps.Println(line)
+		if len(s.Repos) == 0 {
+			_, _ = deps.Println(style.Muted("  no repositories"))
+		}
+		for _, repoID := range s.Repos {
+			_, _ = deps.Println("  " + repoID)
+		}
+	}
+	return nil
+}
+
+// summarizeProject counts the events of a project and the local days they
+// fall on.
+func summarizeProject(p store.Project, events []store.RepoEvent) projectSummary {
+	s := projectSummary{Name: p.Name, Repos: make([]string, 0, len(p.Repos)), Events: len(events)}
+	for _, r := range p.Repos {
+		s.Repos = append(s.Repos, r.RepoID)
+	}
+
+	days := make(map[string]bool)
+	var last time.Time
+	for _, e := range events {
+		days[e.Timestamp.Local().Format(dayKeyLayout)] = true
+		if e.Timestamp.After(last) {
+			last = e.Timestamp
+		}
+	}
+	s.ActiveDays = len(days)
+	if !last.IsZero() {
+		s.LastEvent = last.UTC().Format(time.RFC3339)
+	}
+	return s
+}
+
+// projectNameArg returns the project name, the first argument.
+func projectNameArg(args []string) (string, error) {
+	if len(args) == 0 || strings.TrimSpace(args[0]) == "" {
+		return "", usage.MissingArgument("name")
+	}
+	return strings.TrimSpace(args[0]), nil
+}
+
+func openProjectDB(deps Deps) (*sql.DB, error) {
+	dbPath := deps.DBPath()
+	db, err := deps.OpenDB(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database at %s: %w", dbPath, err)
+	}
+	if err := deps.InitDB(db); err != nil {
+		store.CloseDB(db)
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	return db, nil
+}
+
+// projectFilter sets filter.Project from --project, checking the project
+// exists, so a typo doesn't read as a project without activity.
+func projectFilter(db *sql.DB, flags *dispatchers.ParsedFlags, filter *store.EventFilter) error {
+	name := strings.TrimSpace(flags.String("--project", ""))
+	if name == "" {
+		return nil
+	}
+	ok, err := store.ProjectExists(db, name)
+	if err != nil {
+		return fmt.Errorf("failed to read projects: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("no project named '%s'\nHint: 'fp project list' shows your projects", name)
+	}
+	filter.Project = &name
+	return nil
+}
@@ -0,0 +1,119 @@
+package tracking
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/repo"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// projectDeps returns deps on a fresh database holding one event in each
+// of three repositories. Every directory resolves to github.com/acme/api.
+func projectDeps(t *testing.T, printed *[]string) Deps {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+	deps := importDeps(dbPath, printed)
+	deps.ListEvents = store.ListEvents
+	deps.Now = func() time.Time { return time.Date(2026, 3, 5, 12, 0, 0, 0, time.Local) }
+	deps.RepoRoot = func(path string) (string, error) { return path, nil }
+	deps.OriginURL = func(string) (string, error) { return "git@github.com:acme/api.git", nil }
+	deps.DeriveID = func(string, string) (repo.RepoID, error) { return "github.com/acme/api", nil }
+
+	db, err := openDBFresh(dbPath)
+	require.NoError(t, err)
+	defer store.CloseDB(db)
+	for i, repoID := range []string{"github.com/acme/api", "github.com/acme/web", "github.com/me/dotfiles"} {
+		require.NoError(t, store.InsertEvent(db, store.RepoEvent{
+			RepoID: repoID, Commit: repoID, Branch: "main",
+			Timestamp: time.Date(2026, 3, 2+i, 10, 0, 0, 0, time.UTC),
+			Status:    store.StatusPending, Source: store.SourcePostCommit,
+		}))
+	}
+	return deps
+}
+
+func noFlags() *dispatchers.ParsedFlags { return dispatchers.NewParsedFlags(nil) }
+
+func TestProjectCommands(t *testing.T) {
+	var printed []string
+	deps := projectDeps(t, &printed)
+
+	require.NoError(t, projectCreate([]string{"acme"}, noFlags(), deps))
+	require.Error(t, projectCreate([]string{"acme"}, noFlags(), deps))
+
+	// A directory resolves to its repository's id; ids need events
+	require.NoError(t, projectAddRepo([]string{"acme", t.TempDir(), "github.com/acme/web"}, noFlags(), deps))
+	require.Contains(t, printed, "Added github.com/acme/api to acme\n")
+	require.Contains(t, printed, "Added github.com/acme/web to acme\n")
+	err := projectAddRepo([]string{"acme", "github.com/acme/typo"}, noFlags(), deps)
+	require.ErrorContains(t, err, "neither a directory nor a known repository id")
+	require.Error(t, projectAddRepo([]string{"nope", "github.com/acme/web"}, noFlags(), deps))
+
+	printed = nil
+	require.NoError(t, projectList(nil, dispatchers.NewParsedFlags([]string{"--json"}), deps))
+	var summaries []projectSummary
+	require.NoError(t, json.Unmarshal([]byte(strings.Join(printed, "")), &summaries))
+	require.Equal(t, []projectSummary{{
+		Name:       "acme",
+		Repos:      []string{"github.com/acme/api", "github.com/acme/web"},
+		Events:     2,
+		ActiveDays: 2,
+		LastEvent:  "2026-03-03T10:00:00Z",
+	}}, summaries)
+
+	// --since narrows the counts
+	printed = nil
+	require.NoError(t, projectList(nil, dispatchers.NewParsedFlags([]string{"--json", "--since=2026-03-03"}), deps))
+	require.NoError(t, json.Unmarshal([]byte(strings.Join(printed, "")), &summaries))
+	require.Equal(t, 1, summaries[0].Events)
+
+	require.NoError(t, projectRemoveRepo([]string{"acme", "github.com/acme/web"}, noFlags(), deps))
+	require.NoError(t, projectDelete([]string{"acme"}, noFlags(), deps))
+	printed = nil
+	require.NoError(t, projectList(nil, noFlags(), deps))
+	require.Equal(t, "no projects", printed[0])
+}
+
+func TestActivity_ProjectFilter(t *testing.T) {
+	var printed []string
+	deps := projectDeps(t, &printed)
+	require.NoError(t, projectCreate([]string{"acme"}, noFlags(), deps))
+	require.NoError(t, projectAddRepo([]string{"acme", "github.com/acme/api", "github.com/acme/web"}, noFlags(), deps))
+
+	printed = nil
+	require.NoError(t, activity(nil, dispatchers.NewParsedFlags([]string{"--json", "--project=acme"}), deps))
+	var events []jsonEvent
+	require.NoError(t, json.Unmarshal([]byte(strings.Join(printed, "")), &events))
+	require.Len(t, events, 2)
+	for _, e := range events {
+		require.True(t, strings.HasPrefix(e.RepoID, "github.com/acme/"), e.RepoID)
+	}
+
+	err := activity(nil, dispatchers.NewParsedFlags([]string{"--json", "--project=acme-typo"}), deps)
+	require.ErrorContains(t, err, "no project named 'acme-typo'")
+}
+
+func TestStats_ProjectFilter(t *testing.T) {
+	var printed []string
+	deps := projectDeps(t, &printed)
+	require.NoError(t, projectCreate([]string{"acme"}, noFlags(), deps))
+	require.NoError(t, projectAddRepo([]string{"acme", "github.com/acme/web"}, noFlags(), deps))
+
+	printed = nil
+	flags := dispatchers.NewParsedFlags([]string{"--json", "--project=acme", "--since=2026-03-01"})
+	require.NoError(t, stats(nil, flags, deps))
+	var result struct {
+		Project string `json:"project"`
+		Events  int    `json:"events"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(strings.Join(printed, "")), &result))
+	require.Equal(t, "acme", result.Project)
+	require.Equal(t, 1, result.Events)
+}
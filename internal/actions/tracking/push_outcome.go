@@ -0,0 +1,222 @@
+package tracking
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// pushOutcomeWait is how long a push may go unconfirmed before fp counts
+// it as rejected. A push that goes through moves the remote-tracking ref
+// as soon as the remote accepts it.
+const pushOutcomeWait = 10 * time.Minute
+
+// pushCommitCounter counts the commits a push of localSHA brings to a
+// remote ref at remoteSHA; see git.CountPushCommits.
+type pushCommitCounter func(localSHA, remoteSHA string) (int, bool)
+
+// pushRefs parses the "<local ref> <local sha> <remote ref> <remote sha>"
+// lines pre-push gets on stdin into the refs being pushed to remote at
+// remoteURL, counting each one's new commits with count, which may be nil.
+// Only branches pushed to a named remote get a remote-tracking ref to
+// settle their outcome against; deletions, tags and pushes to a URL are
+// kept without one.
+func pushRefs(r io.Reader, remote, remoteURL string, count pushCommitCounter) []store.PushRef {
+	if r == nil || remote == "" {
+		return nil
+	}
+
+	var refs []store.PushRef
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		ref := store.PushRef{
+			Remote:    remote,
+			RemoteURL: remoteURL,
+			LocalRef:  fields[0],
+			LocalSHA:  fields[1],
+			RemoteRef: fields[2],
+			RemoteSHA: fields[3],
+			Commits:   -1,
+		}
+		branch, isBranch := strings.CutPrefix(ref.RemoteRef, "refs/heads/")
+		switch {
+		case isZeroOID(ref.LocalSHA):
+			ref.Commits = 0
+		case count != nil:
+			if n, ok := count(ref.LocalSHA, ref.RemoteSHA); ok {
+				ref.Commits = n
+			}
+		}
+		if isBranch && !isZeroOID(ref.LocalSHA) && isRemoteName(remote) {
+			ref.TrackingRef = "refs/remotes/" + remote + "/" + branch
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// pushRemoteURL is where a push goes, without credentials: the URL git
+// passes pre-push, or for hooks installed before fp passed it on, the
+// remote's configured URL.
+func pushRemoteURL(deps Deps, repoRoot, remote, rawURL string) string {
+	if rawURL == "" {
+		if !isRemoteName(remote) {
+			rawURL = remote
+		} else if deps.GetRemoteURL != nil {
+			rawURL, _ = deps.GetRemoteURL(repoRoot, remote)
+		}
+	}
+	return redactRemoteURL(rawURL)
+}
+
+// redactRemoteURL drops credentials from a remote URL: the password, and
+// over HTTP(S) the user name too, which is often a token. scp-like URLs
+// (git@host:path) and paths have none to drop.
+func redactRemoteURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || u.User == nil {
+		return rawURL
+	}
+	if u.Scheme == "ssh" || u.Scheme == "git+ssh" {
+		u.User = url.User(u.User.Username())
+	} else {
+		u.User = nil
+	}
+	return u.String()
+}
+
+// describePushRef is a pushed ref for people: its short name with how many
+// commits it sent, "(new)" when the remote didn't have it yet, or
+// "deleted".
+func describePushRef(r store.PushRef) string {
+	name := r.RemoteRef
+	for _, prefix := range []string{"refs/heads/", "refs/tags/"} {
+		if short, ok := strings.CutPrefix(name, prefix); ok {
+			name = short
+			break
+		}
+	}
+	if r.Deleted() {
+		return name + " deleted"
+	}
+	if strings.HasPrefix(r.RemoteRef, "refs/tags/") {
+		name = "tag " + name
+	}
+	if r.Commits >= 0 {
+		name += fmt.Sprintf(" +%d %s", r.Commits, pluralize(r.Commits, "commit", "commits"))
+	}
+	if isZeroOID(r.RemoteSHA) {
+		name += " (new)"
+	}
+	return name
+}
+
+// loadExportPushes reads the pushed refs of the pre-push events among
+// events, for the push columns of the export. A failed read leaves the
+// columns empty rather than holding up the export.
+func loadExportPushes(db *sql.DB, events []store.RepoEvent) map[int64][]store.PushRef {
+	var ids []int64
+	for _, e := range events {
+		if e.Source == store.SourcePrePush {
+			ids = append(ids, e.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	pushes, err := store.ListPushRefs(db, ids)
+	if err != nil {
+		log.Warn("export: could not read pushed refs: %v", err)
+		return nil
+	}
+	return pushes
+}
+
+// pushColumns is the push_remote and push_refs columns of an export
+// record for refs.
+func pushColumns(refs []store.PushRef) (remote, described string) {
+	names := make([]string, 0, len(refs))
+	for _, r := range refs {
+		if remote == "" {
+			remote = r.RemoteURL
+		}
+		names = append(names, describePushRef(r))
+	}
+	return remote, strings.Join(names, ", ")
+}
+
+// isRemoteName reports whether remote, as git passes it to pre-push, is
+// the name of a configured remote rather than a URL or path.
+func isRemoteName(remote string) bool {
+	return remote != "" && !strings.ContainsAny(remote, `:\`) &&
+		!strings.HasPrefix(remote, "/") && !strings.HasPrefix(remote, ".")
+}
+
+// settlePushes resolves the pending pushes of the repository at repoPath,
+// or of every repository when repoPath is "". A push is pushed once its
+// remote-tracking ref contains the pushed commit, and rejected when it
+// still doesn't after pushOutcomeWait. Pushes of repositories that are no
+// longer on disk stay pending.
+func settlePushes(db *sql.DB, repoPath string, now time.Time) (pushed, rejected int, err error) {
+	refs, err := store.PendingPushRefs(db, repoPath)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, r := range refs {
+		if _, err := os.Stat(r.RepoPath); r.RepoPath == "" || err != nil {
+			continue
+		}
+		outcome := ""
+		switch {
+		case git.IsAncestor(r.RepoPath, r.LocalSHA, r.TrackingRef):
+			outcome = store.PushPushed
+			pushed++
+		case now.Sub(r.Timestamp) > pushOutcomeWait:
+			outcome = store.PushRejected
+			rejected++
+		default:
+			continue
+		}
+		if err := store.SetPushOutcome(db, r.EventID, r.RemoteRef, outcome); err != nil {
+			return pushed, rejected, err
+		}
+		log.Debug("push: %s of %.7s to %s %s", r.RemoteRef, r.LocalSHA, r.Remote, outcome)
+	}
+	return pushed, rejected, nil
+}
+
+// settleRepoPushes settles the pending pushes of one repository from a
+// hook, where failures are only logged.
+func settleRepoPushes(db *sql.DB, repoPath string, now time.Time) {
+	if _, _, err := settlePushes(db, repoPath, now); err != nil {
+		log.Warn("record: could not settle pushes: %v (repo=%s)", err, repoPath)
+	}
+}
+
+// settleAllPushes is the maintenance task that settles pushes no later
+// hook did, such as the last push before a repository went quiet.
+func settleAllPushes(s *store.Store, deps Deps) (string, error) {
+	pushed, rejected, err := settlePushes(s.DB(), "", deps.Now())
+	if err != nil {
+		return "", err
+	}
+	if pushed+rejected == 0 {
+		return "no pushes to settle", nil
+	}
+	return fmt.Sprintf("settled %d %s (%d pushed, %d rejected)",
+		pushed+rejected, pluralize(pushed+rejected, "push", "pushes"), pushed, rejected), nil
+}
@@ -0,0 +1,143 @@
+package tracking
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/footprint-tools/cli/internal/ui/text"
+)
+
+// queryMaxCellWidth caps column width in table output. Use --json for full values.
+const queryMaxCellWidth = 60
+
+// Query handles the `fp query` command.
+func Query(args []string, flags *dispatchers.ParsedFlags) error {
+	return query(args, flags, DefaultDeps())
+}
+
+// query runs ad-hoc SQL against a read-only connection to the store.
+// The query can't change anything or block the hooks from recording.
+func query(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	jsonOutput := flags.Has("--json")
+
+	// Allow unquoted queries: fp query SELECT count(*) FROM events
+	sqlText := strings.TrimSpace(strings.Join(args, " "))
+
+	dbPath := deps.DBPath()
+	db, err := store.OpenReadOnly(dbPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no database at %s yet\nHint: Run 'fp setup' in a repository and make a commit", dbPath)
+		}
+		return fmt.Errorf("could not open database at %s: %w", dbPath, err)
+	}
+	defer func() { _ = db.Close() }()
+
+	result, err := store.Query(db, sqlText)
+	if err != nil {
+		if strings.Contains(err.Error(), "readonly") || strings.Contains(err.Error(), "query_only") {
+			return fmt.Errorf("query failed: %w\nfp query is read-only", err)
+		}
+		return fmt.Errorf("query failed: %w", err)
+	}
+
+	if jsonOutput {
+		return queryResultJSON(result, deps)
+	}
+
+	if len(result.Rows) == 0 {
+		_, _ = deps.Println("no rows")
+		return nil
+	}
+
+	deps.Pager(formatQueryTable(result))
+	return nil
+}
+
+// queryResultJSON prints rows as an array of objects keyed by column name,
+// keeping the column order of the query.
+func queryResultJSON(result store.QueryResult, deps Deps) error {
+	rows := make([]json.RawMessage, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		var b bytes.Buffer
+		b.WriteByte('{')
+		for i, col := range result.Columns {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			key, _ := json.Marshal(col)
+			value, err := json.Marshal(row[i])
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			b.Write(key)
+			b.WriteByte(':')
+			b.Write(value)
+		}
+		b.WriteByte('}')
+		rows = append(rows, b.Bytes())
+	}
+	return output.JSON(deps.Println, rows)
+}
+
+// formatQueryTable renders rows as aligned columns with a header line.
+func formatQueryTable(result store.QueryResult) string {
+	cells := make([][]string, len(result.Rows))
+	widths := make([]int, len(result.Columns))
+	for i, col := range result.Columns {
+		widths[i] = lipgloss.Width(col)
+	}
+	for r, row := range result.Rows {
+		cells[r] = make([]string, len(row))
+		for i, v := range row {
+			cell := queryCell(v)
+			cells[r][i] = cell
+			widths[i] = max(widths[i], lipgloss.Width(cell))
+		}
+	}
+
+	var b strings.Builder
+	writeRow := func(values []string, render func(string) string) {
+		for i, v := range values {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			padded := v
+			if i < len(values)-1 {
+				padded += strings.Repeat(" ", widths[i]-lipgloss.Width(v))
+			}
+			b.WriteString(render(padded))
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(result.Columns, style.Header)
+	for _, row := range cells {
+		writeRow(row, func(s string) string { return s })
+	}
+
+	noun := "rows"
+	if len(result.Rows) == 1 {
+		noun = "row"
+	}
+	b.WriteString(style.Muted(fmt.Sprintf("(%d %s)", len(result.Rows), noun)))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// queryCell formats one value for table output.
+func queryCell(v any) string {
+	if v == nil {
+		return "NULL"
+	}
+	cell := strings.ReplaceAll(fmt.Sprint(v), "\n", " ")
+	return text.TruncateWithEllipsis(cell, queryMaxCellWidth)
+}
@@ -0,0 +1,83 @@
+package tracking
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func setupQueryDB(t *testing.T) string {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+	s, err := store.New(dbPath)
+	require.NoError(t, err)
+	for i, repo := range []string{"github.com/a/one", "github.com/a/one", "github.com/b/two"} {
+		require.NoError(t, store.InsertEvent(s.DB(), store.RepoEvent{
+			RepoID:    repo,
+			Commit:    "commit" + string(rune('a'+i)),
+			Branch:    "main",
+			Timestamp: time.Date(2024, 1, 15, 12, i, 0, 0, time.UTC),
+			Status:    store.StatusPending,
+			Source:    store.SourcePostCommit,
+		}))
+	}
+	require.NoError(t, s.Close())
+	return dbPath
+}
+
+func TestQuery_Table(t *testing.T) {
+	dbPath := setupQueryDB(t)
+
+	var paged string
+	deps := Deps{
+		DBPath: func() string { return dbPath },
+		Pager:  func(s string) { paged = s },
+	}
+
+	err := query([]string{"SELECT repo_id, count(*) AS commits FROM events GROUP BY repo_id ORDER BY repo_id"}, dispatchers.NewParsedFlags(nil), deps)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(paged), "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, "repo_id           commits", lines[0])
+	require.Equal(t, "github.com/a/one  2", lines[1])
+	require.Equal(t, "(2 rows)", lines[3])
+}
+
+func TestQuery_JSON(t *testing.T) {
+	dbPath := setupQueryDB(t)
+
+	var printed string
+	deps := Deps{
+		DBPath: func() string { return dbPath },
+		Println: func(a ...any) (int, error) {
+			printed = a[0].(string)
+			return 0, nil
+		},
+	}
+
+	err := query([]string{"SELECT", "commit_hash, source FROM events ORDER BY id LIMIT 1"}, dispatchers.NewParsedFlags([]string{"--json"}), deps)
+	require.NoError(t, err)
+	require.JSONEq(t, `[{"commit_hash":"commita","source":"post-commit"}]`, printed)
+}
+
+func TestQuery_RejectsWrites(t *testing.T) {
+	dbPath := setupQueryDB(t)
+	deps := Deps{DBPath: func() string { return dbPath }}
+
+	err := query([]string{"DELETE FROM repo_events"}, dispatchers.NewParsedFlags(nil), deps)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "read-only")
+
+	s, err := store.New(dbPath)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+	events, err := store.GetPendingEvents(s.DB())
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+}
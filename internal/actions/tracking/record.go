@@ -1,8 +1,22 @@
 package tracking
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/daemon"
 	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/enrich"
 	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/paths"
 	"github.com/footprint-tools/cli/internal/store"
 )
 
@@ -12,7 +26,10 @@ func Record(args []string, flags *dispatchers.ParsedFlags) error {
 
 func record(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	verbose := flags.Has("--verbose")
-	manual := flags.Has("--manual")
+	tag := strings.Join(strings.Fields(flags.String("--tag", "")), " ")
+	note := strings.TrimSpace(flags.String("--note", ""))
+	// Annotating an event only makes sense by hand
+	manual := flags.Has("--manual") || tag != "" || note != ""
 
 	// Show note when running manually (no FP_SOURCE env var)
 	isFromHook := deps.Getenv("FP_SOURCE") != ""
@@ -25,6 +42,15 @@ func record(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	// Show errors when running manually or with --verbose
 	showErrors := verbose || manual
 
+	// Hooks keep calling record in read-only mode; stay silent so commits aren't noisy
+	if config.IsReadOnly() {
+		log.Debug("record: read-only mode, not recording")
+		if showErrors {
+			_, _ = deps.Println("read-only mode: event not recorded")
+		}
+		return nil
+	}
+
 	if !deps.GitIsAvailable() {
 		log.Error("record: git not available in PATH")
 		if showErrors {
@@ -33,7 +59,14 @@ func record(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		return nil
 	}
 
+	// post-receive runs in the bare repository of a git server, which has
+	// no working tree, HEAD of its own or current branch to read
+	serverSide := deps.Getenv("FP_SOURCE") == "post-receive"
+
 	repoRoot, err := deps.RepoRoot(".")
+	if err != nil && serverSide && deps.BareRepoDir != nil {
+		repoRoot, err = deps.BareRepoDir(".")
+	}
 	if err != nil {
 		log.Debug("record: not in a git repository: %v", err)
 		if showErrors {
@@ -42,7 +75,51 @@ func record(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		return nil
 	}
 
+	// Hooks don't say which profile they record for: a repository claimed
+	// by a profile's repos_include records into that profile, unless one
+	// was picked with --profile or FP_PROFILE
+	if paths.Profile() == "" {
+		if profile := config.ProfileForRepo(repoRoot); profile != "" {
+			log.Debug("record: %s belongs to profile %s", repoRoot, profile)
+			paths.SetProfile(profile)
+		}
+	}
+
+	// fp commits to its export repository itself. Under global hooks those
+	// commits would be recorded, exported and committed again, forever.
+	if exportPath, _ := config.Get("export_path"); isSameDir(repoRoot, exportPath) {
+		log.Debug("record: %s is the export repository, not recording", repoRoot)
+		return nil
+	}
+
+	if !config.RepoAllowed(repoRoot) {
+		log.Debug("record: %s excluded by repos_include/repos_exclude", repoRoot)
+		if showErrors {
+			_, _ = deps.Println("repository excluded by repos_include/repos_exclude: event not recorded")
+		}
+		return nil
+	}
+
+	// Each lookup starts a git process, which dominates the time a hook
+	// spends in fp record; run them side by side rather than one by one.
+	var (
+		commit, branch string
+		headErr        error
+		wg             sync.WaitGroup
+	)
+	if !serverSide {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			commit, headErr = deps.HeadCommit()
+		}()
+		go func() {
+			defer wg.Done()
+			branch, _ = deps.CurrentBranch()
+		}()
+	}
 	remoteURL, _ := deps.OriginURL(repoRoot)
+	wg.Wait()
 
 	repoID, err := deps.DeriveID(remoteURL, repoRoot)
 	if err != nil {
@@ -53,16 +130,14 @@ func record(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		return nil
 	}
 
-	commit, err := deps.HeadCommit()
-	if err != nil {
-		log.Error("record: could not read HEAD commit in %s: %v", repoRoot, err)
+	if headErr != nil {
+		log.Error("record: could not read HEAD commit in %s: %v", repoRoot, headErr)
 		if showErrors {
 			_, _ = deps.Println("could not read HEAD commit")
 		}
 		return nil
 	}
 
-	branch, _ := deps.CurrentBranch()
 	log.Debug("record: repo=%s, commit=%.7s, branch=%s, path=%s", repoID, commit, branch, repoRoot)
 
 	db, err := deps.OpenDB(deps.DBPath())
@@ -86,46 +161,225 @@ func record(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	}
 
 	source := resolveSource(deps)
+	events := []store.RepoEvent{{
+		Commit: commit,
+		Branch: branch,
+		Source: source,
+	}}
+	var pushed []store.PushRef
+	switch deps.Getenv("FP_SOURCE") {
+	case "reference-transaction":
+		var input []byte
+		if deps.Stdin != nil {
+			input, _ = io.ReadAll(deps.Stdin)
+		}
+		events = refTransactionEvents(bytes.NewReader(input), branch)
+		events = slices.DeleteFunc(events, func(e store.RepoEvent) bool {
+			return e.Source == store.SourceBranchCreate && isInitialCommit(deps, repoRoot, e.Branch, e.Commit)
+		})
+		if bytes.Contains(input, []byte(" refs/remotes/")) {
+			settleRepoPushes(db, repoRoot, deps.Now())
+		}
+		if len(events) == 0 {
+			log.Debug("record: no branch or stash updates in reference transaction")
+			return nil
+		}
+	case "pre-push":
+		remote := deps.Getenv("FP_PUSH_REMOTE")
+		var count pushCommitCounter
+		if deps.CountPushCommits != nil {
+			// Only a named remote has remote-tracking refs to count against
+			trackingRemote := ""
+			if isRemoteName(remote) {
+				trackingRemote = remote
+			}
+			count = func(localSHA, remoteSHA string) (int, bool) {
+				return deps.CountPushCommits(repoRoot, localSHA, remoteSHA, trackingRemote)
+			}
+		}
+		pushed = pushRefs(deps.Stdin, remote, pushRemoteURL(deps, repoRoot, remote, deps.Getenv("FP_PUSH_URL")), count)
+	case "post-receive":
+		events = postReceiveEvents(deps.Stdin)
+		if len(events) == 0 {
+			log.Debug("record: no branch updates in push")
+			return nil
+		}
+	}
 
-	err = deps.InsertEvent(db, store.RepoEvent{
-		RepoID:    string(repoID),
-		RepoPath:  repoRoot,
-		Commit:    commit,
-		Branch:    branch,
-		Timestamp: deps.Now().UTC(),
-		Status:    store.StatusPending,
-		Source:    source,
-	})
+	cwd := recordCwd(deps, repoRoot)
+	device := deviceName()
 
-	if err != nil {
-		// Critical error: failed to record event
-		log.Error("fp record: failed to insert event: %v (repo=%s, commit=%.7s, source=%s)", err, repoID, commit, source.String())
-	} else {
-		log.Info("record: event saved (repo=%s, commit=%.7s, source=%s)", repoID, commit, source.String())
+	var stored []store.RepoEvent
+	indexed := make(map[string]bool)
+	var pipeline func() enrich.Pipeline
+	if deps.Enrichers != nil {
+		pipeline = sync.OnceValue(deps.Enrichers)
 	}
+	for _, event := range events {
+		event.RepoID = string(repoID)
+		event.RepoPath = repoRoot
+		event.Cwd = cwd
+		event.Device = device
+		event.Timestamp = deps.Now().UTC()
+		event.Status = store.StatusPending
+		event.Tag = tag
+		event.Note = note
+
+		err = deps.InsertEvent(db, event)
 
-	if showErrors {
 		if err != nil {
-			_, _ = deps.Printf("failed to record event: %v\n", err)
+			// Critical error: failed to record event
+			log.Error("fp record: failed to insert event: %v (repo=%s, commit=%.7s, source=%s)", err, repoID, event.Commit, event.Source.String())
 		} else {
-			_, _ = deps.Printf(
-				"recorded %.7s on %s (%s) [%s]\n",
-				commit,
-				branch,
-				repoID,
-				source.String(),
-			)
+			stored = append(stored, event)
+			log.Info("record: event saved (repo=%s, commit=%.7s, source=%s)", repoID, event.Commit, event.Source.String())
+			if event.Source == store.SourcePrePush {
+				if err := store.SavePushRefs(db, event, pushed); err != nil {
+					log.Warn("record: could not save pushed refs: %v (repo=%s)", err, repoID)
+				}
+			}
+			if !indexed[event.Commit] {
+				indexed[event.Commit] = true
+				saveCommitText(db, deps, repoRoot, event.RepoID, event.Commit)
+				saveCommitMeta(db, deps, repoRoot, event.RepoID, event.Commit)
+				saveCommitLanguages(db, deps, repoRoot, event.RepoID, event.Commit)
+				enrichCommit(db, pipeline, event)
+			}
 		}
+
+		if showErrors {
+			if err != nil {
+				_, _ = deps.Printf("failed to record event: %v\n", err)
+			} else {
+				_, _ = deps.Printf(
+					"recorded %.7s on %s (%s) [%s]\n",
+					event.Commit,
+					event.Branch,
+					repoID,
+					event.Source.String(),
+				)
+				if tag != "" {
+					_, _ = deps.Printf("tagged %s\n", tag)
+				}
+			}
+		}
+	}
+
+	if len(stored) > 0 {
+		saveDefaultBranch(db, deps, repoRoot, stored[0].RepoID)
 	}
 
-	// Check if we should auto-export
-	if err == nil {
-		maybeExport(db, deps)
+	// Any later hook settles a push still waiting for its outcome; the
+	// reference transaction that moves remote-tracking refs already has
+	if source != store.SourcePrePush && deps.Getenv("FP_SOURCE") != "reference-transaction" {
+		settleRepoPushes(db, repoRoot, deps.Now())
+	}
+
+	runOnEvent(onEventHooks(), stored)
+
+	// Check if we should auto-export (the daemon handles it when running)
+	if len(stored) > 0 {
+		if daemon.IsRunning() {
+			log.Debug("record: daemon running, skipping hook-time export")
+		} else {
+			maybeExport(db, deps)
+		}
 	}
 
 	return nil
 }
 
+// saveCommitText stores the subject and body of commit for activity
+// search, unless an earlier event already did. Search is a convenience, so
+// failures are only logged.
+func saveCommitText(db *sql.DB, deps Deps, repoRoot, repoID, commit string) {
+	if deps.CommitText == nil || commit == "" {
+		return
+	}
+	if ok, err := store.HasCommitText(db, repoID, commit); err != nil || ok {
+		return
+	}
+	subject, body, err := deps.CommitText(repoRoot, commit)
+	if err != nil {
+		log.Debug("record: could not read message of %.7s: %v", commit, err)
+		return
+	}
+	if err := store.SaveCommitText(db, repoID, commit, subject, body); err != nil {
+		log.Warn("record: could not store message of %.7s for search: %v", commit, err)
+	}
+}
+
+// enrichCommit runs the enrichers on the first event recorded for a
+// commit and stores the fields they derive. pipeline builds the pipeline
+// on first use, so events for commits already enriched don't read the
+// config. Enrichers are extras: their failures are logged, never returned.
+func enrichCommit(db *sql.DB, pipeline func() enrich.Pipeline, event store.RepoEvent) {
+	if pipeline == nil || event.Commit == "" {
+		return
+	}
+	if ok, err := store.HasCommitFields(db, event.RepoID, event.Commit); err != nil || ok {
+		return
+	}
+	p := pipeline()
+	if p.Empty() {
+		return
+	}
+
+	fields, failures := p.Run(context.Background(), enrich.Event{
+		RepoID:    event.RepoID,
+		RepoPath:  event.RepoPath,
+		Commit:    event.Commit,
+		Branch:    event.Branch,
+		Source:    event.Source.String(),
+		Timestamp: event.Timestamp,
+	})
+	for _, f := range failures {
+		log.Warn("record: %v (commit=%.7s)", f, event.Commit)
+	}
+	if err := store.SaveCommitFields(db, event.RepoID, event.Commit, fields); err != nil {
+		log.Warn("record: could not store enriched fields of %.7s: %v", event.Commit, err)
+	}
+}
+
+// recordCwd returns where the git command behind the event was run,
+// relative to repoRoot, or "" for the root itself. Git runs hooks from the
+// repository root, so neither the working directory nor $PWD say anything
+// there; git passes the directory it was started from in $GIT_PREFIX
+// instead. Run by hand, fp record reads $PWD.
+func recordCwd(deps Deps, repoRoot string) string {
+	if prefix := strings.TrimSuffix(deps.Getenv("GIT_PREFIX"), "/"); prefix != "" {
+		if !filepath.IsLocal(filepath.FromSlash(prefix)) {
+			return ""
+		}
+		return prefix
+	}
+	return relativeCwd(deps.Getenv("PWD"), repoRoot)
+}
+
+// relativeCwd returns pwd relative to repoRoot, or "" for the root itself.
+// Anything outside the repository is dropped.
+func relativeCwd(pwd, repoRoot string) string {
+	if pwd == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(repoRoot, pwd)
+	if err != nil || !filepath.IsLocal(rel) {
+		// $PWD may go through a symlink that git resolved (/var on macOS)
+		resolved, rerr := filepath.EvalSymlinks(pwd)
+		if rerr != nil {
+			return ""
+		}
+		rel, err = filepath.Rel(repoRoot, resolved)
+		if err != nil || !filepath.IsLocal(rel) {
+			return ""
+		}
+	}
+	if rel == "." {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
 func resolveSource(deps Deps) store.Source {
 	switch deps.Getenv("FP_SOURCE") {
 	case "post-commit":
@@ -136,9 +390,119 @@ func resolveSource(deps Deps) store.Source {
 		return store.SourcePostCheckout
 	case "post-merge":
 		return store.SourcePostMerge
-	case "pre-push":
+	case "pre-push", "post-receive":
+		// A push received by a server is the same push seen from the
+		// other end; it has no remote-tracking ref to settle against
 		return store.SourcePrePush
+	case "branch-create":
+		return store.SourceBranchCreate
+	case "branch-delete":
+		return store.SourceBranchDelete
+	case "stash":
+		return store.SourceStash
 	default:
 		return store.SourceManual
 	}
 }
+
+// refTransactionEvents parses the "<old> <new> <ref>" lines the
+// reference-transaction hook passes on stdin. Branch creation and deletion are
+// recorded against the branch they touch; stash pushes against the current
+// branch. Dropping the last stash entry deletes refs/stash and is ignored.
+func refTransactionEvents(r io.Reader, currentBranch string) []store.RepoEvent {
+	if r == nil {
+		return nil
+	}
+
+	var events []store.RepoEvent
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		oldValue, newValue, ref := fields[0], fields[1], fields[2]
+
+		switch {
+		case ref == "refs/stash":
+			if !isZeroOID(newValue) {
+				events = append(events, store.RepoEvent{Commit: newValue, Branch: currentBranch, Source: store.SourceStash})
+			}
+		case strings.HasPrefix(ref, "refs/heads/"):
+			name := strings.TrimPrefix(ref, "refs/heads/")
+			switch {
+			case isZeroOID(oldValue) && !isZeroOID(newValue):
+				events = append(events, store.RepoEvent{Commit: newValue, Branch: name, Source: store.SourceBranchCreate})
+			case !isZeroOID(oldValue) && isZeroOID(newValue):
+				events = append(events, store.RepoEvent{Commit: oldValue, Branch: name, Source: store.SourceBranchDelete})
+			}
+		}
+	}
+	return events
+}
+
+// isInitialCommit reports whether commit is the first commit of the
+// repository: it has no parents and branch is the only branch. Git creates
+// the branch along with it, which isn't a branch anyone made.
+func isInitialCommit(deps Deps, repoRoot, branch, commit string) bool {
+	if deps.BranchTips == nil || deps.CommitMetadata == nil {
+		return false
+	}
+	tips, err := deps.BranchTips(repoRoot)
+	if err != nil {
+		return false
+	}
+	for name := range tips {
+		if name != branch {
+			return false
+		}
+	}
+	// An unreadable commit comes back empty, parents included
+	meta := deps.CommitMetadata(repoRoot, commit)
+	return meta.AuthoredAt != "" && meta.ParentCommits == ""
+}
+
+// postReceiveEvents parses the "<old> <new> <ref>" lines post-receive gets
+// on stdin into a push event for each branch a push updated. Deleted
+// branches and tags are left out.
+func postReceiveEvents(r io.Reader) []store.RepoEvent {
+	if r == nil {
+		return nil
+	}
+
+	var events []store.RepoEvent
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		newValue, ref := fields[1], fields[2]
+		branch, ok := strings.CutPrefix(ref, "refs/heads/")
+		if !ok || isZeroOID(newValue) {
+			continue
+		}
+		events = append(events, store.RepoEvent{Commit: newValue, Branch: branch, Source: store.SourcePrePush})
+	}
+	return events
+}
+
+// isZeroOID reports whether an object id is git's all-zero placeholder.
+func isZeroOID(oid string) bool {
+	return oid != "" && strings.Trim(oid, "0") == ""
+}
+
+// isSameDir reports whether a and b name the same directory, following
+// symlinks when both exist.
+func isSameDir(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if ra, err := filepath.EvalSymlinks(a); err == nil {
+		a = ra
+	}
+	if rb, err := filepath.EvalSymlinks(b); err == nil {
+		b = rb
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
@@ -1,14 +1,23 @@
 package tracking
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/footprint-tools/cli/internal/config"
 	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/enrich"
+	"github.com/footprint-tools/cli/internal/git"
 	"github.com/footprint-tools/cli/internal/repo"
 	"github.com/footprint-tools/cli/internal/store"
 )
@@ -19,8 +28,13 @@ func TestRecord_SuccessFromHook(t *testing.T) {
 
 	deps := Deps{
 		Getenv: func(key string) string {
-			if key == "FP_SOURCE" {
+			switch key {
+			case "FP_SOURCE":
 				return "post-commit"
+			case "GIT_PREFIX":
+				return "cmd/fp/"
+			case "PWD":
+				return "/path/to/repo"
 			}
 			return ""
 		},
@@ -75,6 +89,7 @@ func TestRecord_SuccessFromHook(t *testing.T) {
 	require.Equal(t, store.StatusPending, insertedEvent.Status)
 	require.Equal(t, store.SourcePostCommit, insertedEvent.Source)
 	require.Equal(t, fixedNow.UTC(), insertedEvent.Timestamp)
+	require.Equal(t, "cmd/fp", insertedEvent.Cwd)
 }
 
 func TestRecord_SuccessWithManualFlag(t *testing.T) {
@@ -133,6 +148,55 @@ func TestRecord_SuccessWithManualFlag(t *testing.T) {
 	require.Contains(t, capturedPrintf, "recorded")
 }
 
+func TestRecord_TagAndNote(t *testing.T) {
+	var inserted []store.RepoEvent
+	var printed []string
+
+	deps := Deps{
+		Getenv:         func(string) string { return "" },
+		GitIsAvailable: func() bool { return true },
+		RepoRoot:       func(string) (string, error) { return "/path/to/repo", nil },
+		OriginURL:      func(string) (string, error) { return "https://github.com/user/repo.git", nil },
+		DeriveID: func(string, string) (repo.RepoID, error) {
+			return "github.com/user/repo", nil
+		},
+		HeadCommit:    func() (string, error) { return "abc123", nil },
+		CurrentBranch: func() (string, error) { return "main", nil },
+		DBPath:        func() string { return ":memory:" },
+		OpenDB: func(string) (*sql.DB, error) {
+			return sql.Open("sqlite3", ":memory:")
+		},
+		InitDB: func(*sql.DB) error { return nil },
+		InsertEvent: func(_ *sql.DB, e store.RepoEvent) error {
+			inserted = append(inserted, e)
+			return nil
+		},
+		Now: time.Now,
+		Println: func(a ...any) (int, error) {
+			printed = append(printed, fmt.Sprint(a...))
+			return 0, nil
+		},
+		Printf: func(format string, a ...any) (int, error) {
+			printed = append(printed, fmt.Sprintf(format, a...))
+			return 0, nil
+		},
+	}
+
+	flags := dispatchers.NewParsedFlags([]string{"--tag=  client   x ", "--note= Estimate for billing \n"})
+	require.NoError(t, record(nil, flags, deps))
+
+	require.Len(t, inserted, 1)
+	require.Equal(t, store.SourceManual, inserted[0].Source)
+	require.Equal(t, "client x", inserted[0].Tag)
+	require.Equal(t, "Estimate for billing", inserted[0].Note)
+
+	// Annotating counts as manual use: no hook note, and a confirmation
+	output := strings.Join(printed, "")
+	require.NotContains(t, output, "usually executed automatically")
+	require.Contains(t, output, "recorded abc123")
+	require.Contains(t, output, "tagged client x")
+}
+
 func TestRecord_SuccessWithVerboseFlag(t *testing.T) {
 	var capturedPrintf string
 
@@ -455,6 +519,7 @@ func TestResolveSource(t *testing.T) {
 		{"post-checkout", "post-checkout", store.SourcePostCheckout},
 		{"post-merge", "post-merge", store.SourcePostMerge},
 		{"pre-push", "pre-push", store.SourcePrePush},
+		{"stash", "stash", store.SourceStash},
 		{"empty defaults to manual", "", store.SourceManual},
 		{"unknown defaults to manual", "unknown-hook", store.SourceManual},
 	}
@@ -475,3 +540,371 @@ func TestResolveSource(t *testing.T) {
 		})
 	}
 }
+
+func TestRecord_ReadOnlySkips(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	lines, _ := config.Set(nil, "read_only", "true")
+	require.NoError(t, config.WriteLines(lines))
+
+	var printed []string
+	deps := Deps{
+		Getenv: func(string) string { return "" },
+		Println: func(a ...any) (int, error) {
+			printed = append(printed, a[0].(string))
+			return 0, nil
+		},
+		GitIsAvailable: func() bool {
+			t.Fatal("record should stop before touching git")
+			return false
+		},
+	}
+
+	err := record(nil, dispatchers.NewParsedFlags([]string{"--manual"}), deps)
+	require.NoError(t, err)
+	require.Contains(t, printed, "read-only mode: event not recorded")
+}
+
+func TestRecord_ReferenceTransaction(t *testing.T) {
+	zero := strings.Repeat("0", 40)
+	oldSHA := strings.Repeat("a", 40)
+	newSHA := strings.Repeat("b", 40)
+	stdin := strings.Join([]string{
+		zero + " " + newSHA + " refs/heads/feature",
+		oldSHA + " " + zero + " refs/heads/old",
+		oldSHA + " " + newSHA + " refs/heads/main",
+		zero + " " + newSHA + " refs/stash",
+		newSHA + " " + zero + " refs/stash",
+		zero + " " + newSHA + " refs/tags/v1",
+	}, "\n") + "\n"
+
+	var inserted []store.RepoEvent
+	deps := Deps{
+		Getenv: func(key string) string {
+			if key == "FP_SOURCE" {
+				return "reference-transaction"
+			}
+			return ""
+		},
+		Stdin:          strings.NewReader(stdin),
+		GitIsAvailable: func() bool { return true },
+		RepoRoot:       func(string) (string, error) { return "/path/to/repo", nil },
+		OriginURL:      func(string) (string, error) { return "", nil },
+		DeriveID:       func(string, string) (repo.RepoID, error) { return "local:/path/to/repo", nil },
+		HeadCommit:     func() (string, error) { return "abc123", nil },
+		CurrentBranch:  func() (string, error) { return "main", nil },
+		DBPath:         func() string { return ":memory:" },
+		OpenDB:         func(string) (*sql.DB, error) { return sql.Open("sqlite3", ":memory:") },
+		InitDB:         func(*sql.DB) error { return nil },
+		InsertEvent: func(_ *sql.DB, e store.RepoEvent) error {
+			inserted = append(inserted, e)
+			return nil
+		},
+		Now:     time.Now,
+		Println: func(...any) (int, error) { return 0, nil },
+		Printf:  func(string, ...any) (int, error) { return 0, nil },
+	}
+
+	err := record(nil, dispatchers.NewParsedFlags(nil), deps)
+	require.NoError(t, err)
+	require.Len(t, inserted, 3)
+
+	require.Equal(t, store.SourceBranchCreate, inserted[0].Source)
+	require.Equal(t, newSHA, inserted[0].Commit)
+	require.Equal(t, "feature", inserted[0].Branch)
+
+	require.Equal(t, store.SourceBranchDelete, inserted[1].Source)
+	require.Equal(t, oldSHA, inserted[1].Commit)
+	require.Equal(t, "old", inserted[1].Branch)
+
+	require.Equal(t, store.SourceStash, inserted[2].Source)
+	require.Equal(t, newSHA, inserted[2].Commit)
+	require.Equal(t, "main", inserted[2].Branch)
+
+	for _, e := range inserted {
+		require.Equal(t, "local:/path/to/repo", e.RepoID)
+		require.Equal(t, store.StatusPending, e.Status)
+	}
+}
+
+func TestRecord_ReferenceTransaction_InitialCommit(t *testing.T) {
+	zero := strings.Repeat("0", 40)
+	root := strings.Repeat("a", 40)
+	child := strings.Repeat("b", 40)
+
+	tests := []struct {
+		name    string
+		stdin   string
+		tips    map[string]string
+		parents string
+		want    int
+	}{
+		{name: "first commit", stdin: zero + " " + root + " refs/heads/main\n", tips: map[string]string{"main": root}, want: 0},
+		{name: "branch from a root commit", stdin: zero + " " + root + " refs/heads/feature\n", tips: map[string]string{"main": root, "feature": root}, want: 1},
+		{name: "only branch, renamed", stdin: zero + " " + child + " refs/heads/trunk\n", tips: map[string]string{"trunk": child}, parents: root, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inserted []store.RepoEvent
+			deps := Deps{
+				Getenv: func(key string) string {
+					if key == "FP_SOURCE" {
+						return "reference-transaction"
+					}
+					return ""
+				},
+				Stdin:          strings.NewReader(tt.stdin),
+				GitIsAvailable: func() bool { return true },
+				RepoRoot:       func(string) (string, error) { return "/path/to/repo", nil },
+				OriginURL:      func(string) (string, error) { return "", nil },
+				DeriveID:       func(string, string) (repo.RepoID, error) { return "local:/path/to/repo", nil },
+				HeadCommit:     func() (string, error) { return root, nil },
+				CurrentBranch:  func() (string, error) { return "main", nil },
+				BranchTips:     func(string) (map[string]string, error) { return tt.tips, nil },
+				CommitMetadata: func(string, string) git.CommitMetadata {
+					return git.CommitMetadata{AuthoredAt: "2026-03-02T10:00:00Z", ParentCommits: tt.parents}
+				},
+				DBPath: func() string { return ":memory:" },
+				OpenDB: func(string) (*sql.DB, error) { return sql.Open("sqlite3", ":memory:") },
+				InitDB: func(*sql.DB) error { return nil },
+				InsertEvent: func(_ *sql.DB, e store.RepoEvent) error {
+					inserted = append(inserted, e)
+					return nil
+				},
+				Now:     time.Now,
+				Println: func(...any) (int, error) { return 0, nil },
+				Printf:  func(string, ...any) (int, error) { return 0, nil },
+			}
+
+			require.NoError(t, record(nil, dispatchers.NewParsedFlags(nil), deps))
+			require.Len(t, inserted, tt.want)
+		})
+	}
+}
+
+func TestRecord_StoresCommitText(t *testing.T) {
+	// Keep the hook-time export from running
+	setRedactionConfig(t, "export_interval_sec", "86400", "export_last", strconv.FormatInt(time.Now().Unix(), 10), "device_name", "work-laptop")
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+
+	lookups := 0
+	source := "post-commit"
+	deps := Deps{
+		Getenv:         func(key string) string { return map[string]string{"FP_SOURCE": source}[key] },
+		GitIsAvailable: func() bool { return true },
+		RepoRoot:       func(string) (string, error) { return "/path/to/repo", nil },
+		OriginURL:      func(string) (string, error) { return "", nil },
+		DeriveID:       func(string, string) (repo.RepoID, error) { return "github.com/user/repo", nil },
+		HeadCommit:     func() (string, error) { return "abc123", nil },
+		CurrentBranch:  func() (string, error) { return "main", nil },
+		CommitText: func(repoRoot, commit string) (string, string, error) {
+			lookups++
+			require.Equal(t, "/path/to/repo", repoRoot)
+			return "Fix login redirect", "The session cookie was dropped.", nil
+		},
+		DBPath:      func() string { return dbPath },
+		OpenDB:      openDBFresh,
+		InitDB:      func(*sql.DB) error { return nil },
+		InsertEvent: store.InsertEvent,
+		Now:         time.Now,
+		Println:     func(...any) (int, error) { return 0, nil },
+		Printf:      func(string, ...any) (int, error) { return 0, nil },
+	}
+
+	require.NoError(t, record(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, 1, lookups)
+
+	// A later event for the same commit doesn't ask git again
+	source = "pre-push"
+	require.NoError(t, record(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, 1, lookups)
+
+	db, err := openDBFresh(dbPath)
+	require.NoError(t, err)
+	defer store.CloseDB(db)
+
+	query := "cookie"
+	events, err := store.ListEvents(db, store.EventFilter{Search: &query})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	for _, e := range events {
+		require.Equal(t, "work-laptop", e.Device)
+	}
+}
+
+func TestRefTransactionEvents_IgnoresUnrelatedRefs(t *testing.T) {
+	events := refTransactionEvents(strings.NewReader("garbage\n1 2 refs/heads/main\n"), "main")
+	require.Empty(t, events)
+	require.Nil(t, refTransactionEvents(nil, "main"))
+}
+
+func TestPostReceiveEvents(t *testing.T) {
+	zero := strings.Repeat("0", 40)
+	stdin := zero + " aaa111 refs/heads/feature\n" +
+		"bbb222 ccc333 refs/heads/main\n" +
+		"ddd444 " + zero + " refs/heads/old\n" +
+		zero + " eee555 refs/tags/v1.0\n" +
+		"garbage\n"
+
+	require.Equal(t, []store.RepoEvent{
+		{Commit: "aaa111", Branch: "feature", Source: store.SourcePrePush},
+		{Commit: "ccc333", Branch: "main", Source: store.SourcePrePush},
+	}, postReceiveEvents(strings.NewReader(stdin)))
+	require.Nil(t, postReceiveEvents(nil))
+}
+
+func TestRecord_PostReceive(t *testing.T) {
+	var inserted []store.RepoEvent
+	deps := Deps{
+		Getenv: func(key string) string {
+			if key == "FP_SOURCE" {
+				return "post-receive"
+			}
+			return ""
+		},
+		Stdin:          strings.NewReader("bbb222 ccc333 refs/heads/main\n"),
+		GitIsAvailable: func() bool { return true },
+		RepoRoot:       func(string) (string, error) { return "", errors.New("not a working tree") },
+		BareRepoDir:    func(string) (string, error) { return "/srv/git/repo.git", nil },
+		OriginURL:      func(string) (string, error) { return "", nil },
+		DeriveID: func(_, root string) (repo.RepoID, error) {
+			return repo.RepoID("local:" + root), nil
+		},
+		HeadCommit: func() (string, error) {
+			t.Fatal("read HEAD in a bare repository")
+			return "", nil
+		},
+		CurrentBranch: func() (string, error) {
+			t.Fatal("read the current branch in a bare repository")
+			return "", nil
+		},
+		DBPath: func() string { return ":memory:" },
+		OpenDB: func(string) (*sql.DB, error) { return sql.Open("sqlite3", ":memory:") },
+		InitDB: func(*sql.DB) error { return nil },
+		InsertEvent: func(_ *sql.DB, e store.RepoEvent) error {
+			inserted = append(inserted, e)
+			return nil
+		},
+		Now:     time.Now,
+		Println: func(...any) (int, error) { return 0, nil },
+		Printf:  func(string, ...any) (int, error) { return 0, nil },
+	}
+
+	require.NoError(t, record(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.Len(t, inserted, 1)
+	require.Equal(t, "local:/srv/git/repo.git", inserted[0].RepoID)
+	require.Equal(t, "/srv/git/repo.git", inserted[0].RepoPath)
+	require.Equal(t, "ccc333", inserted[0].Commit)
+	require.Equal(t, "main", inserted[0].Branch)
+	require.Equal(t, store.SourcePrePush, inserted[0].Source)
+}
+
+func TestRelativeCwd(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "services", "api"), 0o755))
+
+	require.Equal(t, "services/api", relativeCwd(filepath.Join(root, "services", "api"), root))
+	require.Equal(t, "", relativeCwd(root, root))
+	require.Equal(t, "", relativeCwd("", root))
+	require.Equal(t, "", relativeCwd(filepath.Dir(root), root))
+
+	// $PWD through a symlink still resolves inside the repository
+	link := filepath.Join(t.TempDir(), "link")
+	require.NoError(t, os.Symlink(root, link))
+	require.Equal(t, "services/api", relativeCwd(filepath.Join(link, "services", "api"), root))
+}
+
+func TestRecordCwd(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "services", "api"), 0o755))
+	env := map[string]string{}
+	deps := Deps{Getenv: func(key string) string { return env[key] }}
+
+	// Hooks: git starts them from the root with $PWD at the root too
+	env["PWD"] = root
+	env["GIT_PREFIX"] = "services/api/"
+	require.Equal(t, "services/api", recordCwd(deps, root))
+	env["GIT_PREFIX"] = ""
+	require.Equal(t, "", recordCwd(deps, root))
+	env["GIT_PREFIX"] = "../elsewhere/"
+	require.Equal(t, "", recordCwd(deps, root))
+
+	// Run by hand from a subdirectory
+	env["GIT_PREFIX"] = ""
+	env["PWD"] = filepath.Join(root, "services")
+	require.Equal(t, "services", recordCwd(deps, root))
+}
+
+// stubEnricher returns fields, or fails with err.
+type stubEnricher struct {
+	name   string
+	fields map[string]string
+	err    error
+	calls  *int
+}
+
+func (s stubEnricher) Name() string { return s.name }
+
+func (s stubEnricher) Enrich(context.Context, enrich.Event) (map[string]string, error) {
+	*s.calls++
+	return s.fields, s.err
+}
+
+func TestRecord_EnrichesNewCommits(t *testing.T) {
+	setRedactionConfig(t, "export_interval_sec", "86400", "export_last", strconv.FormatInt(time.Now().Unix(), 10))
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+
+	builds, calls := 0, 0
+	source := "post-commit"
+	deps := Deps{
+		Getenv:         func(key string) string { return map[string]string{"FP_SOURCE": source}[key] },
+		GitIsAvailable: func() bool { return true },
+		RepoRoot:       func(string) (string, error) { return "/path/to/repo", nil },
+		OriginURL:      func(string) (string, error) { return "", nil },
+		DeriveID:       func(string, string) (repo.RepoID, error) { return "github.com/user/repo", nil },
+		HeadCommit:     func() (string, error) { return "abc123", nil },
+		CurrentBranch:  func() (string, error) { return "PAY-42-refunds", nil },
+		Enrichers: func() enrich.Pipeline {
+			builds++
+			return enrich.Pipeline{Enrichers: []enrich.Enricher{
+				stubEnricher{name: "broken", err: errors.New("no network"), calls: &calls},
+				stubEnricher{name: "ticket", fields: map[string]string{"id": "PAY-42"}, calls: &calls},
+			}}
+		},
+		DBPath:      func() string { return dbPath },
+		OpenDB:      openDBFresh,
+		InitDB:      func(*sql.DB) error { return nil },
+		InsertEvent: store.InsertEvent,
+		Now:         time.Now,
+		Println:     func(...any) (int, error) { return 0, nil },
+		Printf:      func(string, ...any) (int, error) { return 0, nil },
+	}
+
+	require.NoError(t, record(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, 1, builds)
+	require.Equal(t, 2, calls, "a failing enricher doesn't stop the next")
+
+	// A later event for the same commit isn't enriched again
+	source = "pre-push"
+	require.NoError(t, record(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.Equal(t, 2, calls)
+	require.Equal(t, 1, builds)
+
+	db, err := openDBFresh(dbPath)
+	require.NoError(t, err)
+	defer store.CloseDB(db)
+
+	fields, err := store.GetCommitFields(db, "github.com/user/repo", "abc123")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"ticket.id": "PAY-42"}, fields)
+}
+
+func TestIsSameDir(t *testing.T) {
+	dir := t.TempDir()
+	link := filepath.Join(t.TempDir(), "link")
+	require.NoError(t, os.Symlink(dir, link))
+
+	require.True(t, isSameDir(dir, dir+"/"))
+	require.True(t, isSameDir(link, dir))
+	require.False(t, isSameDir(dir, filepath.Join(dir, "sub")))
+	require.False(t, isSameDir(dir, ""))
+}
@@ -0,0 +1,272 @@
+package tracking
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// replayDevice is the device name replayed events are stored under.
+const replayDevice = "fp-replay"
+
+// replayRepoPrefix starts the repository ID of every replayed event. It
+// keys them apart from real events, so replaying a recording of real
+// activity never updates the events it was made from, and --clean only
+// removes what replays stored.
+const replayRepoPrefix = "fp-replay/"
+
+// maxReplayGap caps the wait between two replayed events, so a recording
+// with hours between commits doesn't stall a demo.
+const maxReplayGap = 5 * time.Second
+
+// defaultReplayCount is how many synthetic events a replay without --file
+// generates.
+const defaultReplayCount = 30
+
+// Replay handles the `fp dev replay` command.
+func Replay(args []string, flags *dispatchers.ParsedFlags) error {
+	return replay(args, flags, DefaultDeps())
+}
+
+// replay feeds recorded or synthetic events into the store, spaced out as
+// they happened divided by --speed, so fp watch and the other views can be
+// shown and tested against a realistic stream. Events are stored as they
+// are replayed, with the current time, under their own repository IDs and
+// as skipped, so exports never send them anywhere.
+func replay(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	dbPath := deps.DBPath()
+	db, err := deps.OpenDB(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database at %s: %w", dbPath, err)
+	}
+	defer store.CloseDB(db)
+
+	if err := deps.InitDB(db); err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	if flags.Has("--clean") {
+		n, err := store.DeleteEventsByRepoPrefix(db, replayRepoPrefix)
+		if err != nil {
+			return fmt.Errorf("failed to remove replayed events: %w", err)
+		}
+		_, _ = deps.Printf("Removed %d replayed events\n", n)
+		return nil
+	}
+
+	speed, err := parseReplaySpeed(flags.String("--speed", "10x"))
+	if err != nil {
+		return err
+	}
+
+	var events []store.RepoEvent
+	if file := flags.String("--file", ""); file != "" {
+		var invalid int
+		events, invalid, err = readReplayFile(file)
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", file, err)
+		}
+		if invalid > 0 {
+			_, _ = deps.Printf("Ignoring %d lines without a repo_id, commit or valid timestamp\n", invalid)
+		}
+		if len(events) == 0 {
+			return fmt.Errorf("no events to replay in %s", file)
+		}
+	} else {
+		count := flags.Int("--count", defaultReplayCount)
+		if count <= 0 {
+			return fmt.Errorf("invalid --count %d: must be a positive number", count)
+		}
+		events = syntheticEvents(rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0)), time.Now(), count)
+	}
+
+	_, _ = deps.Printf("Replaying %d events at %gx into %s (Ctrl+C to stop)\n", len(events), speed, dbPath)
+	_, _ = deps.Printf("They are stored under %s and never exported; remove them with 'fp dev replay --clean'\n", replayRepoPrefix)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		select {
+		case <-sigCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	n, err := replayEvents(ctx, db, events, speed, sleepContext, func(e store.RepoEvent) {
+		_, _ = deps.Println(formatEvent(e, true))
+	})
+	if err != nil {
+		return err
+	}
+	_, _ = deps.Printf("Replayed %d of %d events\n", n, len(events))
+	return nil
+}
+
+// replayEvents stores events one by one, waiting between them for the
+// time that separated them divided by speed, at most maxReplayGap. It
+// stops early when ctx is cancelled and returns how many were stored.
+func replayEvents(ctx context.Context, db *sql.DB, events []store.RepoEvent, speed float64,
+	sleep func(context.Context, time.Duration) bool, replayed func(store.RepoEvent)) (int, error) {
+	for i, e := range events {
+		if i > 0 {
+			gap := time.Duration(float64(e.Timestamp.Sub(events[i-1].Timestamp)) / speed)
+			if !sleep(ctx, min(max(gap, 0), maxReplayGap)) {
+				return i, nil
+			}
+		}
+
+		if !strings.HasPrefix(e.RepoID, replayRepoPrefix) {
+			e.RepoID = replayRepoPrefix + e.RepoID
+		}
+		e.Timestamp = time.Now().UTC()
+		e.Status = store.StatusSkipped
+		e.Device = replayDevice
+		if err := store.InsertEvent(db, e); err != nil {
+			return i, fmt.Errorf("failed to store event: %w", err)
+		}
+		replayed(e)
+	}
+	return len(events), nil
+}
+
+// sleepContext waits for d, returning false if ctx is cancelled first.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// parseReplaySpeed reads a speed like "10x", "10" or "0.5x".
+func parseReplaySpeed(value string) (float64, error) {
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(strings.ToLower(value)), "x"), 64)
+	if err != nil || speed <= 0 {
+		return 0, fmt.Errorf("invalid --speed '%s': use a positive multiplier like 10x", value)
+	}
+	return speed, nil
+}
+
+// readReplayFile reads events from JSON lines as printed by
+// 'fp watch --json' or 'fp activity --json' (one object per line), or an
+// export's JSONL, which names the commit commit_hash. Events come back in
+// time order, with the number of lines that couldn't be used.
+func readReplayFile(path string) ([]store.RepoEvent, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var events []store.RepoEvent
+	invalid := 0
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var record struct {
+			RepoID     string `json:"repo_id"`
+			RepoPath   string `json:"repo_path"`
+			Commit     string `json:"commit"`
+			CommitHash string `json:"commit_hash"`
+			Branch     string `json:"branch"`
+			Timestamp  string `json:"timestamp"`
+			Source     string `json:"source"`
+			Cwd        string `json:"cwd"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			invalid++
+			continue
+		}
+		if record.Commit == "" {
+			record.Commit = record.CommitHash
+		}
+		timestamp, err := time.Parse(time.RFC3339, record.Timestamp)
+		if record.RepoID == "" || record.Commit == "" || err != nil {
+			invalid++
+			continue
+		}
+		source, ok := domain.ParseEventSource(record.Source)
+		if !ok {
+			source = store.SourcePostCommit
+		}
+		events = append(events, store.RepoEvent{
+			RepoID:    record.RepoID,
+			RepoPath:  record.RepoPath,
+			Commit:    record.Commit,
+			Branch:    record.Branch,
+			Timestamp: timestamp,
+			Source:    source,
+			Cwd:       record.Cwd,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events, invalid, nil
+}
+
+// syntheticEvents makes up count events for a few demo repositories,
+// roughly the way a working session looks: mostly commits, with branch
+// switches, merges and pushes in between, seconds to minutes apart.
+func syntheticEvents(r *rand.Rand, start time.Time, count int) []store.RepoEvent {
+	repos := []string{"api", "web", "docs"}
+	branches := []string{"main", "feature/login", "fix/PROJ-42"}
+	sources := []store.Source{
+		store.SourcePostCommit, store.SourcePostCommit, store.SourcePostCommit,
+		store.SourcePostCommit, store.SourcePostCheckout, store.SourcePostMerge,
+		store.SourcePrePush, store.SourceBranchCreate,
+	}
+
+	events := make([]store.RepoEvent, 0, count)
+	t := start
+	repo := repos[0]
+	for i := 0; i < count; i++ {
+		// Stay in the same repository for a while before switching
+		if r.IntN(4) == 0 {
+			repo = repos[r.IntN(len(repos))]
+		}
+		events = append(events, store.RepoEvent{
+			RepoID:    "github.com/demo/" + repo,
+			RepoPath:  "/demo/" + repo,
+			Commit:    fmt.Sprintf("%016x%016x%08x", r.Uint64(), r.Uint64(), r.Uint32()),
+			Branch:    branches[r.IntN(len(branches))],
+			Timestamp: t,
+			Source:    sources[r.IntN(len(sources))],
+		})
+		t = t.Add(time.Duration(5+r.IntN(85)) * time.Second)
+	}
+	return events
+}
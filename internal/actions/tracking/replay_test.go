@@ -0,0 +1,155 @@
+package tracking
+
+import (
+	"context"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestParseReplaySpeed(t *testing.T) {
+	for value, want := range map[string]float64{"10x": 10, "10": 10, "0.5x": 0.5, " 2X ": 2} {
+		got, err := parseReplaySpeed(value)
+		require.NoError(t, err, value)
+		require.Equal(t, want, got, value)
+	}
+	for _, value := range []string{"", "x", "fast", "0x", "-2x"} {
+		_, err := parseReplaySpeed(value)
+		require.Error(t, err, value)
+	}
+}
+
+func TestReadReplayFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "demo.jsonl")
+	lines := []string{
+		// fp watch --json, out of order
+		`{"id":2,"repo_id":"github.com/a/b","repo_path":"/src/b","commit":"bbb","branch":"main","timestamp":"2026-01-01T10:05:00Z","source":"PRE-PUSH"}`,
+		`{"id":1,"repo_id":"github.com/a/b","repo_path":"/src/b","commit":"aaa","branch":"main","timestamp":"2026-01-01T10:00:00Z","source":"POST-COMMIT"}`,
+		// export JSONL names the commit commit_hash
+		`{"repo_id":"github.com/a/c","commit_hash":"ccc","timestamp":"2026-01-01T10:10:00Z"}`,
+		`{"repo_id":"github.com/a/c","timestamp":"2026-01-01T10:11:00Z"}`,
+		`not json`,
+		``,
+	}
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600))
+
+	events, invalid, err := readReplayFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, invalid)
+	require.Len(t, events, 3)
+
+	require.Equal(t, "aaa", events[0].Commit)
+	require.Equal(t, store.SourcePostCommit, events[0].Source)
+	require.Equal(t, "/src/b", events[0].RepoPath)
+	require.Equal(t, "bbb", events[1].Commit)
+	require.Equal(t, store.SourcePrePush, events[1].Source)
+	require.Equal(t, "ccc", events[2].Commit)
+}
+
+func TestSyntheticEvents(t *testing.T) {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	events := syntheticEvents(rand.New(rand.NewPCG(1, 2)), start, 50)
+
+	require.Len(t, events, 50)
+	require.Equal(t, start, events[0].Timestamp)
+	commits := make(map[string]bool)
+	for i, e := range events {
+		require.True(t, strings.HasPrefix(e.RepoID, "github.com/demo/"))
+		require.Len(t, e.Commit, 40)
+		commits[e.Commit] = true
+		if i > 0 {
+			require.True(t, e.Timestamp.After(events[i-1].Timestamp))
+		}
+	}
+	require.Len(t, commits, 50)
+}
+
+func TestReplayEvents_PacesAndMarksEvents(t *testing.T) {
+	db, err := openDBFresh(filepath.Join(t.TempDir(), "store.db"))
+	require.NoError(t, err)
+	defer store.CloseDB(db)
+
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	events := []store.RepoEvent{
+		{RepoID: "r", Commit: "a", Timestamp: start, Status: store.StatusExported},
+		{RepoID: "r", Commit: "b", Timestamp: start.Add(20 * time.Second)},
+		{RepoID: "r", Commit: "c", Timestamp: start.Add(2 * time.Hour)},
+	}
+
+	var waits []time.Duration
+	sleep := func(_ context.Context, d time.Duration) bool {
+		waits = append(waits, d)
+		return true
+	}
+	var replayed []string
+	n, err := replayEvents(context.Background(), db, events, 10, sleep, func(e store.RepoEvent) {
+		replayed = append(replayed, e.Commit)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Equal(t, []string{"a", "b", "c"}, replayed)
+	require.Equal(t, []time.Duration{2 * time.Second, maxReplayGap}, waits)
+
+	stored, err := store.ListEvents(db, store.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, stored, 3)
+	for _, e := range stored {
+		require.Equal(t, replayDevice, e.Device)
+		require.Equal(t, "fp-replay/r", e.RepoID)
+		require.Equal(t, store.StatusSkipped, e.Status, "replayed events are never exported")
+		require.True(t, e.Timestamp.After(start))
+	}
+
+	// Cancelling stops between events
+	stop := func(context.Context, time.Duration) bool { return false }
+	n, err = replayEvents(context.Background(), db, events, 10, stop, func(store.RepoEvent) {})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
+
+func TestReplayEvents_LeavesRealEventsAlone(t *testing.T) {
+	db, err := openDBFresh(filepath.Join(t.TempDir(), "store.db"))
+	require.NoError(t, err)
+	defer store.CloseDB(db)
+
+	recorded := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	real := store.RepoEvent{RepoID: "github.com/a/b", Commit: "aaa", Branch: "main", Timestamp: recorded,
+		Status: store.StatusExported, Source: store.SourcePostCommit, Device: "laptop"}
+	require.NoError(t, store.InsertEvent(db, real))
+
+	// A recording of that same event, as fp watch --json prints it
+	_, err = replayEvents(context.Background(), db, []store.RepoEvent{real}, 10, sleepContext, func(store.RepoEvent) {})
+	require.NoError(t, err)
+
+	n, err := store.DeleteEventsByRepoPrefix(db, replayRepoPrefix)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	stored, err := store.ListEvents(db, store.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	require.Equal(t, "laptop", stored[0].Device)
+	require.Equal(t, store.StatusExported, stored[0].Status)
+	require.True(t, stored[0].Timestamp.Equal(recorded))
+}
+
+func TestReplay_SyntheticThenClean(t *testing.T) {
+	var printed []string
+	deps := importDeps(filepath.Join(t.TempDir(), "store.db"), &printed)
+
+	flags := dispatchers.NewParsedFlags([]string{"--count=3", "--speed=100000x"})
+	require.NoError(t, replay(nil, flags, deps))
+	require.Contains(t, strings.Join(printed, "\n"), "Replayed 3 of 3 events")
+
+	printed = nil
+	require.NoError(t, replay(nil, dispatchers.NewParsedFlags([]string{"--clean"}), deps))
+	require.Equal(t, []string{"Removed 3 replayed events\n"}, printed)
+}
@@ -0,0 +1,454 @@
+package tracking
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+//go:embed templates/report.html templates/report_week.html
+var reportTemplates embed.FS
+
+const (
+	// reportCellSize and reportCellGap size the calendar squares, in px.
+	reportCellSize = 11
+	reportCellGap  = 2
+
+	// reportCalendarLeft and reportCalendarTop leave room for the weekday
+	// and month labels.
+	reportCalendarLeft = 28
+	reportCalendarTop  = 16
+
+	// reportRecentEvents is how many events each repository section lists.
+	reportRecentEvents = 20
+
+	// reportFocusDays is how many days over the context switch threshold
+	// the report calls out.
+	reportFocusDays = 10
+)
+
+// Report handles the `fp report` command.
+func Report(args []string, flags *dispatchers.ParsedFlags) error {
+	return report(args, flags, DefaultDeps())
+}
+
+// report renders a self-contained HTML report: everything (CSS, JS, charts)
+// is inlined so the file can be shared and opened without fp or a server.
+// With --week it prints a weekly summary instead, see reportWeek.
+func report(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	if flags.Has("--week") {
+		return reportWeek(flags, deps)
+	}
+	out := flags.String("--html", "")
+	if out == "" {
+		return fmt.Errorf("fp report needs an output\nHint: fp report --html out/, or fp report --week for this week's summary")
+	}
+
+	now := deps.Now()
+	until := now
+	if untilStr := flags.String("--until", ""); untilStr != "" {
+		d := flags.Date("--until")
+		if d == nil {
+			return fmt.Errorf("invalid date '%s' for --until: expected %s", untilStr, dispatchers.DateFormats)
+		}
+		until = *d
+	}
+	since := until.AddDate(-1, 0, 1)
+	if sinceStr := flags.String("--since", ""); sinceStr != "" {
+		d := flags.Date("--since")
+		if d == nil {
+			return fmt.Errorf("invalid date '%s' for --since: expected %s", sinceStr, dispatchers.DateFormats)
+		}
+		since = *d
+	}
+	if since.After(until) {
+		return fmt.Errorf("--since %s is after --until %s", since.Format(dayKeyLayout), until.Format(dayKeyLayout))
+	}
+
+	db, err := deps.OpenDB(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer store.CloseDB(db)
+
+	filter := store.EventFilter{Since: &since, Until: &until}
+	if repoID := flags.String("--repo", ""); repoID != "" {
+		filter.RepoID = &repoID
+	}
+	events, err := deps.ListEvents(db, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+	if flags.Has("--mine") {
+		identities := loadIdentityMatcher()
+		if identities == nil {
+			return errNoIdentities
+		}
+		events = identities.Filter(events)
+	}
+	branches := loadDefaultBranches(db)
+	if flags.Has("--default-branch") {
+		events = branches.Filter(events)
+	}
+
+	data := buildReportData(events, since, until, now, reportColors())
+	addReportFocus(&data, events, contextSwitchThreshold())
+	data.FeatureShare = featureShare(branches.branchShare(events))
+
+	var buf bytes.Buffer
+	tmpl, err := template.ParseFS(reportTemplates, "templates/report.html")
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+
+	path := out
+	if !strings.EqualFold(filepath.Ext(out), ".html") {
+		if err := os.MkdirAll(out, 0755); err != nil {
+			return fmt.Errorf("could not create %s: %w", out, err)
+		}
+		path = filepath.Join(out, "index.html")
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("could not write report: %w", err)
+	}
+
+	_, _ = deps.Printf("Wrote report for %d events to %s\n", len(events), path)
+	return nil
+}
+
+// reportData is what templates/report.html renders.
+type reportData struct {
+	Generated string
+	Range     string
+
+	Total         int
+	RepoCount     int
+	ActiveDays    int
+	LongestStreak int
+
+	// Context switches between repositories
+	SwitchesPerDay  string
+	SwitchThreshold int
+	DaysOver        int
+	FocusDays       []focusDay // the worst days over the threshold
+
+	// Share of events on feature branches, "" when none have a branch
+	FeatureShare string
+
+	Calendar reportCalendar
+	Sources  []reportBar
+	Weekdays []reportBar
+	Hours    []reportBar
+	Repos    []reportRepo
+
+	Accent string
+}
+
+type reportCalendar struct {
+	Width, Height int
+	Cells         []reportCell
+	Months        []reportLabel
+	Days          []reportLabel
+	LevelOpacity  []string
+}
+
+type reportCell struct {
+	X, Y  int
+	Title string
+	Level int
+}
+
+type reportLabel struct {
+	X, Y int
+	Text string
+}
+
+// reportBar is one bar in a chart. Percent is relative to the largest bar.
+type reportBar struct {
+	Label   string
+	Count   int
+	Percent int
+	Color   string
+}
+
+type reportRepo struct {
+	ID         string
+	Events     int
+	Commits    int
+	Pushes     int
+	ActiveDays int
+	First      string
+	Last       string
+	Recent     []reportEvent
+}
+
+type reportEvent struct {
+	Time   string
+	Source string
+	Color  string
+	Branch string
+	Commit string
+	Note   string
+	Tag    string
+}
+
+func buildReportData(events []store.RepoEvent, since, until, now time.Time, colors style.ColorConfig) reportData {
+	data := reportData{
+		Generated: format.DateTimeLong(now),
+		Range:     format.DateLong(since) + " – " + format.DateLong(until),
+		Total:     len(events),
+		Accent:    ansiToHex(colors.Color1),
+	}
+
+	dayCounts := make(map[string]int)
+	sourceCounts := make(map[store.Source]int)
+	weekdayCounts := make([]int, 7)
+	hourCounts := make([]int, 24)
+	repos := make(map[string]*reportRepo)
+	repoDays := make(map[string]map[string]bool)
+
+	// Events come newest first, so the first one seen per repo is its latest
+	for _, e := range events {
+		t := e.Timestamp.Local()
+		day := t.Format(dayKeyLayout)
+		dayCounts[day]++
+		sourceCounts[e.Source]++
+		weekdayCounts[t.Weekday()]++
+		hourCounts[t.Hour()]++
+
+		r, ok := repos[e.RepoID]
+		if !ok {
+			r = &reportRepo{ID: e.RepoID, Last: format.DateTimeLong(t)}
+			repos[e.RepoID] = r
+			repoDays[e.RepoID] = make(map[string]bool)
+		}
+		r.Events++
+		r.First = format.DateTimeLong(t)
+		repoDays[e.RepoID][day] = true
+		switch e.Source {
+		case store.SourcePostCommit, store.SourceBackfill, store.SourceManual:
+			r.Commits++
+		case store.SourcePrePush:
+			r.Pushes++
+		}
+		if len(r.Recent) < reportRecentEvents {
+			r.Recent = append(r.Recent, reportEvent{
+				Time:   format.DateTimeLong(t),
+				Source: sourceName(e.Source),
+				Color:  reportSourceColor(colors, e.Source),
+				Branch: e.Branch,
+				Commit: truncateHash(e.Commit),
+				Note:   e.Note,
+				Tag:    e.Tag,
+			})
+		}
+	}
+
+	for id, r := range repos {
+		r.ActiveDays = len(repoDays[id])
+		data.Repos = append(data.Repos, *r)
+	}
+	sort.Slice(data.Repos, func(i, j int) bool {
+		if data.Repos[i].Events != data.Repos[j].Events {
+			return data.Repos[i].Events > data.Repos[j].Events
+		}
+		return data.Repos[i].ID < data.Repos[j].ID
+	})
+	data.RepoCount = len(data.Repos)
+	data.ActiveDays = len(dayCounts)
+	data.Calendar, data.LongestStreak = buildReportCalendar(dayCounts, since, until)
+
+	for _, src := range []store.Source{
+		store.SourcePostCommit, store.SourcePostRewrite, store.SourcePostCheckout,
+		store.SourcePostMerge, store.SourcePrePush, store.SourceBackfill, store.SourceManual,
+		store.SourceBranchCreate, store.SourceBranchDelete, store.SourceStash,
+	} {
+		if n := sourceCounts[src]; n > 0 {
+			data.Sources = append(data.Sources, reportBar{Label: sourceName(src), Count: n, Color: reportSourceColor(colors, src)})
+		}
+	}
+	weekStart := format.WeekStart()
+	for i := range 7 {
+		wd := (weekStart + time.Weekday(i)) % 7
+		data.Weekdays = append(data.Weekdays, reportBar{Label: wd.String()[:3], Count: weekdayCounts[wd], Color: data.Accent})
+	}
+	for h, n := range hourCounts {
+		data.Hours = append(data.Hours, reportBar{Label: fmt.Sprintf("%02d", h), Count: n, Color: data.Accent})
+	}
+	scaleBars(data.Sources)
+	scaleBars(data.Weekdays)
+	scaleBars(data.Hours)
+
+	return data
+}
+
+// addReportFocus adds context switch figures to the report, calling out
+// the days over threshold (if one is set) with the most switches.
+func addReportFocus(data *reportData, events []store.RepoEvent, threshold int) {
+	days := focusDays(events)
+	data.SwitchesPerDay = fmt.Sprintf("%.1f", averageSwitches(days))
+	data.SwitchThreshold = threshold
+	data.DaysOver = markOverThreshold(days, threshold)
+
+	for _, d := range days {
+		if d.Over {
+			data.FocusDays = append(data.FocusDays, d)
+		}
+	}
+	sort.SliceStable(data.FocusDays, func(i, j int) bool { return data.FocusDays[i].Switches > data.FocusDays[j].Switches })
+	if len(data.FocusDays) > reportFocusDays {
+		data.FocusDays = data.FocusDays[:reportFocusDays]
+	}
+}
+
+// buildReportCalendar lays out one square per day from since to until,
+// one column per week starting on week_start, and returns the longest
+// streak of active days.
+func buildReportCalendar(dayCounts map[string]int, since, until time.Time) (reportCalendar, int) {
+	first := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, time.Local)
+	last := time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, time.Local)
+	weekStart := format.WeekStart()
+	start := first.AddDate(0, 0, -format.DaysIntoWeek(first.Weekday(), weekStart))
+
+	maxCount := 0
+	for _, n := range dayCounts {
+		maxCount = max(maxCount, n)
+	}
+
+	step := reportCellSize + reportCellGap
+	cal := reportCalendar{LevelOpacity: []string{"0", "0.3", "0.5", "0.75", "1"}}
+	streak, best := 0, 0
+	lastMonth := time.Month(0)
+	week := 0
+	for d := start; !d.After(last); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == weekStart && d != start {
+			week++
+		}
+		if d.Before(first) {
+			continue
+		}
+
+		x := reportCalendarLeft + week*step
+		if d.Month() != lastMonth && d.Day() <= 7 {
+			cal.Months = append(cal.Months, reportLabel{X: x, Y: reportCalendarTop - 5, Text: d.Format("Jan")})
+			lastMonth = d.Month()
+		}
+
+		count := dayCounts[d.Format(dayKeyLayout)]
+		if count > 0 {
+			streak++
+			best = max(best, streak)
+		} else {
+			streak = 0
+		}
+		cal.Cells = append(cal.Cells, reportCell{
+			X:     x,
+			Y:     reportCalendarTop + format.DaysIntoWeek(d.Weekday(), weekStart)*step,
+			Title: fmt.Sprintf("%s %s: %d %s", d.Format("Mon"), format.DateLong(d), count, pluralize(count, "event", "events")),
+			Level: intensityLevel(count, maxCount),
+		})
+	}
+
+	for _, wd := range []time.Weekday{time.Monday, time.Wednesday, time.Friday} {
+		cal.Days = append(cal.Days, reportLabel{X: 0, Y: reportCalendarTop + format.DaysIntoWeek(wd, weekStart)*step + reportCellSize - 1, Text: wd.String()[:3]})
+	}
+	cal.Width = reportCalendarLeft + (week+1)*step
+	cal.Height = reportCalendarTop + 7*step
+	return cal, best
+}
+
+func scaleBars(bars []reportBar) {
+	maxCount := 0
+	for _, b := range bars {
+		maxCount = max(maxCount, b.Count)
+	}
+	if maxCount == 0 {
+		return
+	}
+	for i := range bars {
+		bars[i].Percent = bars[i].Count * 100 / maxCount
+	}
+}
+
+func truncateHash(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+// reportColors returns the theme colors for the report. A report is a light
+// page, so built-in themes without an explicit variant use their -light
+// colors. Custom themes have a single variant and are used as they are.
+func reportColors() style.ColorConfig {
+	cfg, err := config.GetAll()
+	if err != nil {
+		cfg = map[string]string{}
+	}
+	resolved := make(map[string]string, len(cfg)+1)
+	for k, v := range cfg {
+		resolved[k] = v
+	}
+	theme := resolved["theme"]
+	if theme == style.AutoTheme {
+		theme = resolved["theme_light"]
+	}
+	if theme == "" {
+		theme = "default"
+	}
+	if !slices.Contains(style.CustomThemeNames, theme) && !strings.HasSuffix(theme, "-dark") && !strings.HasSuffix(theme, "-light") {
+		theme += "-light"
+	}
+	resolved["theme"] = theme
+	return style.LoadColorConfig(resolved)
+}
+
+func reportSourceColor(colors style.ColorConfig, source store.Source) string {
+	return ansiToHex(string(activityModel{colors: colors}.sourceColor(source)))
+}
+
+// ansiBasicColors are the xterm defaults for ANSI colors 0-15.
+var ansiBasicColors = [16]string{
+	"#000000", "#800000", "#008000", "#808000", "#000080", "#800080", "#008080", "#c0c0c0",
+	"#808080", "#ff0000", "#00ff00", "#ffff00", "#0000ff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// ansiToHex converts a theme color (ANSI 0-255 or "#rrggbb") to a CSS hex
+// color, using the xterm palette. Anything else maps to gray.
+func ansiToHex(value string) string {
+	if strings.HasPrefix(value, "#") {
+		return value
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 || n > 255 {
+		return "#808080"
+	}
+	switch {
+	case n < 16:
+		return ansiBasicColors[n]
+	case n < 232:
+		levels := [6]int{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[(n/6)%6], levels[n%6])
+	default:
+		gray := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+	}
+}
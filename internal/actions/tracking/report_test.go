@@ -0,0 +1,168 @@
+package tracking
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportColors_CustomTheme(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("FP_COLOR_THEME", "")
+	lines, _ := config.ReadLines()
+	lines, _ = config.Set(lines, "theme", "mine")
+	require.NoError(t, config.WriteLines(lines))
+
+	themes := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(themes, "mine.toml"), []byte(`success = "#112233"`), 0600))
+	require.Empty(t, style.LoadThemeFiles(themes))
+	t.Cleanup(func() { style.LoadThemeFiles(t.TempDir()) })
+
+	require.Equal(t, "#112233", reportColors().Success)
+}
+
+func reportTestEvents() []store.RepoEvent {
+	day := func(d, h int) time.Time { return time.Date(2025, 3, d, h, 0, 0, 0, time.Local) }
+	// Newest first, like ListEvents
+	return []store.RepoEvent{
+		{RepoID: "github.com/a/one", Commit: "aaaaaaaaaa", Branch: "main", Timestamp: day(12, 15), Source: store.SourcePrePush},
+		{RepoID: "github.com/a/one", Commit: "bbbbbbbbbb", Branch: "main", Timestamp: day(12, 14), Source: store.SourcePostCommit},
+		{RepoID: "github.com/b/two", Commit: "cccccccccc", Branch: "dev", Timestamp: day(11, 9), Source: store.SourcePostCommit},
+		{RepoID: "github.com/a/one", Commit: "dddddddddd", Branch: "main", Timestamp: day(10, 9), Source: store.SourcePostCommit},
+	}
+}
+
+func TestBuildReportData(t *testing.T) {
+	setRedactionConfig(t, "date_format", "iso")
+	since := time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)
+	until := time.Date(2025, 3, 31, 0, 0, 0, 0, time.Local)
+
+	data := buildReportData(reportTestEvents(), since, until, until, style.Themes["default-light"])
+
+	require.Equal(t, 4, data.Total)
+	require.Equal(t, 2, data.RepoCount)
+	require.Equal(t, 3, data.ActiveDays)
+	require.Equal(t, 3, data.LongestStreak)
+	require.Len(t, data.Calendar.Cells, 31)
+
+	one := data.Repos[0]
+	require.Equal(t, "github.com/a/one", one.ID)
+	require.Equal(t, 3, one.Events)
+	require.Equal(t, 2, one.Commits)
+	require.Equal(t, 1, one.Pushes)
+	require.Equal(t, 2, one.ActiveDays)
+	require.Equal(t, "2025-03-10 09:00", one.First)
+	require.Equal(t, "2025-03-12 15:00", one.Last)
+	require.Equal(t, "aaaaaaa", one.Recent[0].Commit)
+
+	require.Equal(t, "POST-COMMIT", data.Sources[0].Label)
+	require.Equal(t, 100, data.Sources[0].Percent)
+	require.Equal(t, 33, data.Sources[1].Percent)
+	require.Equal(t, "Mon", data.Weekdays[0].Label)
+}
+
+func TestBuildReportData_DateFormatAndWeekStart(t *testing.T) {
+	setRedactionConfig(t, "date_format", "eu", "time_format", "12h", "week_start", "sunday")
+	since := time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)
+	until := time.Date(2025, 3, 31, 0, 0, 0, 0, time.Local)
+
+	data := buildReportData(reportTestEvents(), since, until, until, style.Themes["default-light"])
+
+	require.Equal(t, "01/03/2025 – 31/03/2025", data.Range)
+	require.Equal(t, "10/03/2025 9:00 AM", data.Repos[0].First)
+	require.Equal(t, "Sun", data.Weekdays[0].Label)
+	// Mar 1 2025 is a Saturday: the last row of the first week
+	require.Equal(t, reportCalendarTop+6*(reportCellSize+reportCellGap), data.Calendar.Cells[0].Y)
+	require.Equal(t, "Sat 01/03/2025: 0 events", data.Calendar.Cells[0].Title)
+}
+
+func TestReport_WritesSelfContainedHTML(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	out := filepath.Join(t.TempDir(), "out")
+
+	var printed string
+	deps := Deps{
+		DBPath: func() string { return ":memory:" },
+		OpenDB: func(path string) (*sql.DB, error) { return sql.Open("sqlite3", path) },
+		ListEvents: func(*sql.DB, store.EventFilter) ([]store.RepoEvent, error) {
+			return reportTestEvents(), nil
+		},
+		Now: func() time.Time { return time.Date(2025, 4, 1, 12, 0, 0, 0, time.Local) },
+		Printf: func(format string, a ...any) (int, error) {
+			printed = format
+			return 0, nil
+		},
+	}
+
+	err := report(nil, dispatchers.NewParsedFlags([]string{"--html=" + out}), deps)
+	require.NoError(t, err)
+	require.Contains(t, printed, "Wrote report")
+
+	html, err := os.ReadFile(filepath.Join(out, "index.html"))
+	require.NoError(t, err)
+	page := string(html)
+
+	require.Contains(t, page, "github.com/b/two")
+	require.Contains(t, page, "<svg")
+	require.Contains(t, page, "<style>")
+	require.NotContains(t, page, "ZgotmplZ", "template escaped an unsafe value")
+	require.NotContains(t, page, "<link")
+	require.NotContains(t, page, `src="http`)
+}
+
+func TestAddReportFocus(t *testing.T) {
+	at := func(d, h int) time.Time { return time.Date(2025, 3, d, h, 0, 0, 0, time.Local) }
+	events := []store.RepoEvent{
+		{RepoID: "a", Timestamp: at(11, 9)},
+		{RepoID: "b", Timestamp: at(11, 10)},
+		{RepoID: "a", Timestamp: at(11, 11)},
+		{RepoID: "c", Timestamp: at(11, 12)},
+		{RepoID: "a", Timestamp: at(12, 9)},
+		{RepoID: "b", Timestamp: at(12, 10)},
+	}
+
+	var data reportData
+	addReportFocus(&data, events, 2)
+	require.Equal(t, "2.0", data.SwitchesPerDay)
+	require.Equal(t, 1, data.DaysOver)
+	require.Len(t, data.FocusDays, 1)
+	require.Equal(t, "2025-03-11", data.FocusDays[0].Date)
+	require.Equal(t, 3, data.FocusDays[0].Switches)
+
+	data = reportData{}
+	addReportFocus(&data, events, 0)
+	require.Zero(t, data.DaysOver)
+	require.Empty(t, data.FocusDays, "no threshold, no callout")
+}
+
+func TestReport_RequiresOutput(t *testing.T) {
+	err := report(nil, dispatchers.NewParsedFlags(nil), Deps{})
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "--html"))
+}
+
+func TestAnsiToHex(t *testing.T) {
+	tests := map[string]string{
+		"9":       "#ff0000",
+		"16":      "#000000",
+		"74":      "#5fafd7",
+		"231":     "#ffffff",
+		"244":     "#808080",
+		"#56b4e9": "#56b4e9",
+		"bold":    "#808080",
+	}
+	for in, want := range tests {
+		require.Equal(t, want, ansiToHex(in), in)
+	}
+}
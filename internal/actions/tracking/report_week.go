@@ -0,0 +1,529 @@
+package tracking
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"mime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+const (
+	// weekReportMerges is how many merges the weekly report lists.
+	weekReportMerges = 10
+
+	// weekStreakLookback is how far before the week the current streak is
+	// followed back.
+	weekStreakLookback = 365
+)
+
+// weekReportFormats are the --format values for fp report --week.
+var weekReportFormats = []string{"txt", "md", "html"}
+
+// weekReport is a summary of one week, Monday to Sunday.
+type weekReport struct {
+	Start, End time.Time
+
+	Commits int
+	Pushes  int
+	Events  int
+
+	// PushesCompleted and PushesRejected are the pushes whose outcome fp
+	// settled; the rest of Pushes were attempts with no known outcome.
+	PushesCompleted int
+	PushesRejected  int
+
+	ActiveDays   int
+	FilesChanged int
+	Insertions   int
+	Deletions    int
+
+	// CurrentStreak is the run of active days up to the end of the week
+	// (or today, for the current week); LongestStreak is within the week.
+	CurrentStreak int
+	LongestStreak int
+
+	// SwitchesPerDay is the mean context switches between repositories per
+	// active day; DaysOver counts the days over SwitchThreshold, if set.
+	SwitchesPerDay  float64
+	SwitchThreshold int
+	DaysOver        int
+
+	Repos  []weekRepo
+	Merges []weekMerge
+	Tags   []weekTag
+	Days   []reportBar
+
+	Accent string
+}
+
+type weekRepo struct {
+	ID         string
+	Commits    int
+	Pushes     int
+	Insertions int
+	Deletions  int
+}
+
+type weekMerge struct {
+	Day     string
+	RepoID  string
+	Branch  string
+	Subject string
+}
+
+type weekTag struct {
+	Tag    string
+	Events int
+}
+
+// Title is the week's heading, e.g. "Week of Mar 10 – Mar 16, 2025".
+func (w weekReport) Title() string {
+	start := format.DateShort(w.Start)
+	if w.Start.Year() != w.End.Year() {
+		start = format.DateLong(w.Start)
+	}
+	return "Week of " + start + " – " + format.DateLong(w.End)
+}
+
+// Summary is the one-line totals of the week.
+func (w weekReport) Summary() string {
+	return fmt.Sprintf("%d %s, %d %s%s in %d %s on %d %s",
+		w.Commits, pluralize(w.Commits, "commit", "commits"),
+		w.Pushes, pluralize(w.Pushes, "push", "pushes"), w.pushOutcomes(),
+		len(w.Repos), pluralize(len(w.Repos), "repository", "repositories"),
+		w.ActiveDays, pluralize(w.ActiveDays, "day", "days"))
+}
+
+// pushOutcomes is " (3 completed, 1 rejected)" once any push has a known
+// outcome, and "" before.
+func (w weekReport) pushOutcomes() string {
+	var parts []string
+	if w.PushesCompleted > 0 {
+		parts = append(parts, fmt.Sprintf("%d completed", w.PushesCompleted))
+	}
+	if w.PushesRejected > 0 {
+		parts = append(parts, fmt.Sprintf("%d rejected", w.PushesRejected))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// Changes is the week's diff stats.
+func (w weekReport) Changes() string {
+	return fmt.Sprintf("%d %s changed, +%d -%d", w.FilesChanged,
+		pluralize(w.FilesChanged, "file", "files"), w.Insertions, w.Deletions)
+}
+
+// Streaks describes the current and in-week streaks.
+func (w weekReport) Streaks() string {
+	return fmt.Sprintf("Streak: %d %s (longest this week: %d %s)",
+		w.CurrentStreak, pluralize(w.CurrentStreak, "day", "days"),
+		w.LongestStreak, pluralize(w.LongestStreak, "day", "days"))
+}
+
+// Focus describes the context switches of the week.
+func (w weekReport) Focus() string {
+	focus := fmt.Sprintf("%.1f context switches per active day", w.SwitchesPerDay)
+	if w.DaysOver > 0 {
+		focus += fmt.Sprintf(" (%d %s over %d)", w.DaysOver, pluralize(w.DaysOver, "day", "days"), w.SwitchThreshold)
+	}
+	return focus
+}
+
+// weekBounds returns 00:00 of the first day (see week_start) and the last
+// second of the last day of the week containing day, in local time.
+func weekBounds(day time.Time) (time.Time, time.Time) {
+	start := format.StartOfWeek(day.Local())
+	return start, start.AddDate(0, 0, 7).Add(-time.Second)
+}
+
+// reportWeek prints the weekly summary for the week containing --until
+// (default: this week) to stdout.
+func reportWeek(flags *dispatchers.ParsedFlags, deps Deps) error {
+	if flags.String("--html", "") != "" || flags.String("--since", "") != "" {
+		return fmt.Errorf("--week prints one week to stdout and can't be combined with --html or --since\nHint: fp report --week --format html > week.html")
+	}
+	formatName := flags.String("--format", "txt")
+	if !slices.Contains(weekReportFormats, formatName) {
+		return fmt.Errorf("invalid --format '%s': expected one of %s", formatName, strings.Join(weekReportFormats, ", "))
+	}
+
+	now := deps.Now()
+	day := now
+	if untilStr := flags.String("--until", ""); untilStr != "" {
+		d := flags.Date("--until")
+		if d == nil {
+			return fmt.Errorf("invalid date '%s' for --until: expected %s", untilStr, dispatchers.DateFormats)
+		}
+		day = *d
+	}
+	start, end := weekBounds(day)
+
+	db, err := deps.OpenDB(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer store.CloseDB(db)
+
+	// Days before the week only extend the current streak. The daily
+	// rollups have them, unless the events are filtered by author or branch
+	lookback := start.AddDate(0, 0, -weekStreakLookback)
+	filtered := flags.Has("--mine") || flags.Has("--default-branch")
+	since := start
+	if filtered {
+		since = lookback
+	}
+	filter := store.EventFilter{Since: &since, Until: &end}
+	if repoID := flags.String("--repo", ""); repoID != "" {
+		filter.RepoID = &repoID
+	}
+	events, err := deps.ListEvents(db, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+	var earlier map[string]bool
+	if !filtered {
+		before := start.AddDate(0, 0, -1)
+		rollups, err := store.ListDailyRollups(db, store.RollupFilter{Since: &lookback, Until: &before, RepoID: filter.RepoID})
+		if err != nil {
+			return fmt.Errorf("failed to read daily counts: %w", err)
+		}
+		earlier = make(map[string]bool, len(rollups))
+		for _, r := range rollups {
+			earlier[r.Day] = true
+		}
+	}
+	if flags.Has("--mine") {
+		identities := loadIdentityMatcher()
+		if identities == nil {
+			return errNoIdentities
+		}
+		events = identities.Filter(events)
+	}
+	if flags.Has("--default-branch") {
+		events = loadDefaultBranches(db).Filter(events)
+	}
+
+	metas := newCommitMetaReader(db)
+	metas.Prefetch(events)
+	meta := func(e store.RepoEvent) git.CommitMetadata {
+		if e.RepoPath == "" {
+			return git.CommitMetadata{}
+		}
+		return metas.Of(e)
+	}
+	week := buildWeekReport(events, earlier, start, end, now, meta)
+	addWeekFocus(&week, events, contextSwitchThreshold())
+	week.Accent = ansiToHex(reportColors().Color1)
+
+	var pushIDs []int64
+	for _, e := range events {
+		if e.Source == store.SourcePrePush && !e.Timestamp.Before(start) && !e.Timestamp.After(end) {
+			pushIDs = append(pushIDs, e.ID)
+		}
+	}
+	// Without outcomes the pushes still count, as attempts
+	if outcomes, err := store.PushOutcomes(db, pushIDs); err == nil {
+		addWeekPushOutcomes(&week, outcomes)
+	} else {
+		log.Debug("report: could not read push outcomes: %v", err)
+	}
+
+	var body bytes.Buffer
+	if err := writeWeekReport(&body, week, formatName); err != nil {
+		return err
+	}
+
+	if flags.Has("--email-stdout") {
+		contentType := "text/plain"
+		if formatName == "html" {
+			contentType = "text/html"
+		}
+		_, _ = deps.Printf("Subject: %s\n", mime.QEncoding.Encode("utf-8", "fp: "+week.Title()))
+		_, _ = deps.Printf("MIME-Version: 1.0\n")
+		_, _ = deps.Printf("Content-Type: %s; charset=utf-8\n", contentType)
+		_, _ = deps.Printf("Content-Transfer-Encoding: 8bit\n\n")
+	}
+	_, _ = deps.Printf("%s", body.String())
+	return nil
+}
+
+// buildWeekReport summarizes the events between start and end. Events from
+// before start, and the earlier days with activity, only extend the
+// current streak. meta looks up a commit's
+// diff stats and parents; commits are only looked up once.
+func buildWeekReport(events []store.RepoEvent, earlier map[string]bool, start, end, now time.Time, meta func(store.RepoEvent) git.CommitMetadata) weekReport {
+	w := weekReport{Start: start, End: end}
+
+	activeDays := maps.Clone(earlier)
+	if activeDays == nil {
+		activeDays = make(map[string]bool)
+	}
+	weekDays := make(map[string]int)
+	repos := make(map[string]*weekRepo)
+	tags := make(map[string]int)
+	seen := make(map[string]bool)
+
+	// Events come newest first; go oldest first so merges read in order
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		t := e.Timestamp.Local()
+		activeDays[t.Format(dayKeyLayout)] = true
+		if t.Before(start) || t.After(end) {
+			continue
+		}
+
+		// Days count every event, not only the commits of the summary, so a
+		// day of checkouts still shows as active
+		w.Events++
+		weekDays[t.Format(dayKeyLayout)]++
+		if e.Tag != "" {
+			tags[e.Tag]++
+		}
+		r, ok := repos[e.RepoID]
+		if !ok {
+			r = &weekRepo{ID: e.RepoID}
+			repos[e.RepoID] = r
+		}
+
+		isCommit := false
+		switch e.Source {
+		case store.SourcePostCommit, store.SourceBackfill, store.SourceManual:
+			isCommit = true
+		case store.SourcePrePush:
+			r.Pushes++
+			w.Pushes++
+			continue
+		case store.SourcePostMerge:
+		default:
+			continue
+		}
+
+		key := e.RepoID + ":" + e.Commit
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		m := meta(e)
+		if isCommit {
+			r.Commits++
+			w.Commits++
+			r.Insertions += m.Insertions
+			r.Deletions += m.Deletions
+			w.FilesChanged += m.FilesChanged
+			w.Insertions += m.Insertions
+			w.Deletions += m.Deletions
+		}
+		if e.Source == store.SourcePostMerge || strings.Contains(m.ParentCommits, " ") {
+			subject := m.Subject
+			if subject == "" {
+				subject = truncateHash(e.Commit)
+			}
+			w.Merges = append(w.Merges, weekMerge{
+				Day:     t.Format("Mon ") + format.DateShort(t),
+				RepoID:  e.RepoID,
+				Branch:  e.Branch,
+				Subject: singleLine(subject),
+			})
+		}
+	}
+	if len(w.Merges) > weekReportMerges {
+		w.Merges = w.Merges[len(w.Merges)-weekReportMerges:]
+	}
+
+	for _, r := range repos {
+		w.Repos = append(w.Repos, *r)
+	}
+	sort.Slice(w.Repos, func(i, j int) bool {
+		if w.Repos[i].Commits != w.Repos[j].Commits {
+			return w.Repos[i].Commits > w.Repos[j].Commits
+		}
+		return w.Repos[i].ID < w.Repos[j].ID
+	})
+	for tag, n := range tags {
+		w.Tags = append(w.Tags, weekTag{Tag: tag, Events: n})
+	}
+	sort.Slice(w.Tags, func(i, j int) bool {
+		if w.Tags[i].Events != w.Tags[j].Events {
+			return w.Tags[i].Events > w.Tags[j].Events
+		}
+		return w.Tags[i].Tag < w.Tags[j].Tag
+	})
+
+	run := 0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		n := weekDays[d.Format(dayKeyLayout)]
+		w.Days = append(w.Days, reportBar{Label: d.Format("Mon"), Count: n})
+		if n > 0 {
+			w.ActiveDays++
+			run++
+			w.LongestStreak = max(w.LongestStreak, run)
+		} else {
+			run = 0
+		}
+	}
+	scaleBars(w.Days)
+
+	// The current streak ends today for this week; a day without events
+	// yet doesn't break it
+	last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.Local)
+	if today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local); today.Before(last) {
+		last = today
+	}
+	if !activeDays[last.Format(dayKeyLayout)] {
+		last = last.AddDate(0, 0, -1)
+	}
+	for d := last; activeDays[d.Format(dayKeyLayout)]; d = d.AddDate(0, 0, -1) {
+		w.CurrentStreak++
+	}
+
+	return w
+}
+
+// addWeekFocus adds the context switches between repositories in the
+// week, as fp stats --focus counts them.
+func addWeekFocus(w *weekReport, events []store.RepoEvent, threshold int) {
+	var inWeek []store.RepoEvent
+	for _, e := range events {
+		if !e.Timestamp.Before(w.Start) && !e.Timestamp.After(w.End) {
+			inWeek = append(inWeek, e)
+		}
+	}
+	days := focusDays(inWeek)
+	w.SwitchesPerDay = averageSwitches(days)
+	w.SwitchThreshold = threshold
+	w.DaysOver = markOverThreshold(days, threshold)
+}
+
+// addWeekPushOutcomes counts the week's pushes that completed or were
+// rejected, from the outcomes store.PushOutcomes read for them.
+func addWeekPushOutcomes(w *weekReport, outcomes map[int64]string) {
+	for _, outcome := range outcomes {
+		switch outcome {
+		case store.PushPushed:
+			w.PushesCompleted++
+		case store.PushRejected:
+			w.PushesRejected++
+		}
+	}
+}
+
+// writeWeekReport renders the week as plain text, Markdown or HTML.
+func writeWeekReport(out io.Writer, w weekReport, formatName string) error {
+	switch formatName {
+	case "md":
+		writeWeekMarkdown(out, w)
+		return nil
+	case "html":
+		tmpl, err := template.ParseFS(reportTemplates, "templates/report_week.html")
+		if err != nil {
+			return err
+		}
+		if err := tmpl.Execute(out, w); err != nil {
+			return fmt.Errorf("failed to render report: %w", err)
+		}
+		return nil
+	default:
+		writeWeekText(out, w)
+		return nil
+	}
+}
+
+// writeWeekText writes the week as plain text, without colors, so it can
+// go straight into an email or a chat message.
+func writeWeekText(out io.Writer, w weekReport) {
+	_, _ = fmt.Fprintf(out, "%s\n\n", w.Title())
+	_, _ = fmt.Fprintf(out, "%s\n%s\n%s\n%s\n", w.Summary(), w.Changes(), w.Streaks(), w.Focus())
+
+	if len(w.Repos) > 0 {
+		width := 0
+		for _, r := range w.Repos {
+			width = max(width, len(r.ID))
+		}
+		_, _ = fmt.Fprintf(out, "\nRepositories\n")
+		for _, r := range w.Repos {
+			_, _ = fmt.Fprintf(out, "  %-*s  %3d %-7s  %3d %-6s  +%d -%d\n", width, r.ID,
+				r.Commits, pluralize(r.Commits, "commit", "commits"),
+				r.Pushes, pluralize(r.Pushes, "push", "pushes"),
+				r.Insertions, r.Deletions)
+		}
+	}
+
+	if len(w.Merges) > 0 {
+		_, _ = fmt.Fprintf(out, "\nMerges\n")
+		for _, m := range w.Merges {
+			_, _ = fmt.Fprintf(out, "  %-10s  %s  %s  %s\n", m.Day, m.RepoID, m.Branch, m.Subject)
+		}
+	}
+
+	if len(w.Tags) > 0 {
+		_, _ = fmt.Fprintf(out, "\nTags\n")
+		for _, t := range w.Tags {
+			_, _ = fmt.Fprintf(out, "  %s  %d %s\n", t.Tag, t.Events, pluralize(t.Events, "event", "events"))
+		}
+	}
+
+	days := make([]string, 0, len(w.Days))
+	for _, d := range w.Days {
+		days = append(days, fmt.Sprintf("%s %d", d.Label, d.Count))
+	}
+	_, _ = fmt.Fprintf(out, "\nEvents by day\n  %s\n", strings.Join(days, "  "))
+}
+
+// writeWeekMarkdown writes the week as Markdown, for pasting into issues,
+// wikis or chat.
+func writeWeekMarkdown(out io.Writer, w weekReport) {
+	_, _ = fmt.Fprintf(out, "# %s\n\n", w.Title())
+	_, _ = fmt.Fprintf(out, "- %s\n- %s\n- %s\n- %s\n", w.Summary(), w.Changes(), w.Streaks(), w.Focus())
+
+	if len(w.Repos) > 0 {
+		_, _ = fmt.Fprintf(out, "\n## Repositories\n\n")
+		_, _ = fmt.Fprintf(out, "| Repository | Commits | Pushes | Changes |\n|---|---:|---:|---:|\n")
+		for _, r := range w.Repos {
+			_, _ = fmt.Fprintf(out, "| %s | %d | %d | +%d -%d |\n", markdownCell(r.ID), r.Commits, r.Pushes, r.Insertions, r.Deletions)
+		}
+	}
+
+	if len(w.Merges) > 0 {
+		_, _ = fmt.Fprintf(out, "\n## Merges\n\n")
+		for _, m := range w.Merges {
+			_, _ = fmt.Fprintf(out, "- %s · %s · `%s` · %s\n", m.Day, m.RepoID, m.Branch, m.Subject)
+		}
+	}
+
+	if len(w.Tags) > 0 {
+		_, _ = fmt.Fprintf(out, "\n## Tags\n\n")
+		for _, t := range w.Tags {
+			_, _ = fmt.Fprintf(out, "- %s: %d %s\n", t.Tag, t.Events, pluralize(t.Events, "event", "events"))
+		}
+	}
+
+	_, _ = fmt.Fprintf(out, "\n## Events by day\n\n")
+	header, rule, counts := "|", "|", "|"
+	for _, d := range w.Days {
+		header += " " + d.Label + " |"
+		rule += "---:|"
+		counts += fmt.Sprintf(" %d |", d.Count)
+	}
+	_, _ = fmt.Fprintf(out, "%s\n%s\n%s\n", header, rule, counts)
+}
+
+// markdownCell escapes pipes so a value stays in its table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
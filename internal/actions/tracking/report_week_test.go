@@ -0,0 +1,175 @@
+package tracking
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// weekTestEvents spans Mon Mar 10 – Sun Mar 16 2025, plus two days before
+// the week that extend the streak. Newest first, like ListEvents.
+func weekTestEvents() []store.RepoEvent {
+	day := func(d, h int) time.Time { return time.Date(2025, 3, d, h, 0, 0, 0, time.Local) }
+	return []store.RepoEvent{
+		{RepoID: "github.com/a/one", Commit: "mmmmmmmmmm", Branch: "main", Timestamp: day(12, 16), Source: store.SourcePostMerge},
+		{RepoID: "github.com/a/one", Commit: "aaaaaaaaaa", Branch: "main", Timestamp: day(12, 15), Source: store.SourcePrePush},
+		{RepoID: "github.com/a/one", Commit: "aaaaaaaaaa", Branch: "main", Timestamp: day(12, 14), Source: store.SourcePostCommit, Tag: "client-x"},
+		{RepoID: "github.com/b/two", Commit: "cccccccccc", Branch: "dev", Timestamp: day(11, 9), Source: store.SourcePostCommit, Tag: "client-x"},
+		{RepoID: "github.com/a/one", Commit: "dddddddddd", Branch: "main", Timestamp: day(10, 9), Source: store.SourcePostCommit},
+		{RepoID: "github.com/a/one", Commit: "dddddddddd", Branch: "main", Timestamp: day(10, 9), Source: store.SourceBackfill},
+		{RepoID: "github.com/a/one", Commit: "eeeeeeeeee", Branch: "main", Timestamp: day(9, 9), Source: store.SourcePostCommit},
+		{RepoID: "github.com/a/one", Commit: "ffffffffff", Branch: "main", Timestamp: day(8, 9), Source: store.SourcePostCommit},
+		{RepoID: "github.com/a/one", Commit: "gggggggggg", Branch: "main", Timestamp: day(6, 9), Source: store.SourcePostCommit},
+	}
+}
+
+func weekTestMeta(e store.RepoEvent) git.CommitMetadata {
+	if e.Commit == "mmmmmmmmmm" {
+		return git.CommitMetadata{Subject: "Merge branch 'feature'", ParentCommits: "aaaaaaaaaa bbbbbbbbbb"}
+	}
+	return git.CommitMetadata{FilesChanged: 2, Insertions: 10, Deletions: 3}
+}
+
+func TestWeekBounds(t *testing.T) {
+	for _, day := range []int{10, 13, 16} {
+		start, end := weekBounds(time.Date(2025, 3, day, 12, 0, 0, 0, time.Local))
+		require.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local), start)
+		require.Equal(t, time.Date(2025, 3, 16, 23, 59, 59, 0, time.Local), end)
+	}
+}
+
+func TestBuildWeekReport(t *testing.T) {
+	start, end := weekBounds(time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local))
+	now := time.Date(2025, 3, 13, 10, 0, 0, 0, time.Local)
+
+	w := buildWeekReport(weekTestEvents(), nil, start, end, now, weekTestMeta)
+
+	require.Equal(t, "Week of Mar 10 – Mar 16, 2025", w.Title())
+	require.Equal(t, 3, w.Commits, "the backfilled duplicate counts once")
+	require.Equal(t, 1, w.Pushes)
+	require.Equal(t, 3, w.ActiveDays)
+	require.Equal(t, 6, w.FilesChanged)
+	require.Equal(t, 30, w.Insertions)
+	require.Equal(t, 9, w.Deletions)
+	require.Equal(t, 3, w.LongestStreak)
+	// Mar 8–12; the 13th has no events yet
+	require.Equal(t, 5, w.CurrentStreak)
+
+	require.Len(t, w.Repos, 2)
+	require.Equal(t, weekRepo{ID: "github.com/a/one", Commits: 2, Pushes: 1, Insertions: 20, Deletions: 6}, w.Repos[0])
+	require.Equal(t, []weekMerge{{Day: "Wed Mar 12", RepoID: "github.com/a/one", Branch: "main", Subject: "Merge branch 'feature'"}}, w.Merges)
+	require.Equal(t, []weekTag{{Tag: "client-x", Events: 2}}, w.Tags)
+	require.Len(t, w.Days, 7)
+	require.Equal(t, "Mon", w.Days[0].Label)
+	require.Equal(t, 3, w.Days[2].Count)
+}
+
+func TestBuildWeekReport_EarlierDays(t *testing.T) {
+	start, end := weekBounds(time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local))
+	now := time.Date(2025, 3, 13, 10, 0, 0, 0, time.Local)
+
+	// Days from the rollups extend the streak, a gap ends it
+	earlier := map[string]bool{"2025-03-07": true, "2025-03-04": true}
+	w := buildWeekReport(weekTestEvents(), earlier, start, end, now, weekTestMeta)
+	require.Equal(t, 7, w.CurrentStreak)
+	require.Equal(t, 3, w.ActiveDays, "earlier days are not in the week")
+}
+
+func TestAddWeekFocus(t *testing.T) {
+	start, end := weekBounds(time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local))
+	at := func(d, h int) time.Time { return time.Date(2025, 3, d, h, 0, 0, 0, time.Local) }
+	events := []store.RepoEvent{
+		{RepoID: "a", Timestamp: at(12, 12)},
+		{RepoID: "b", Timestamp: at(12, 11)},
+		{RepoID: "a", Timestamp: at(12, 10)},
+		{RepoID: "a", Timestamp: at(11, 10)},
+		{RepoID: "b", Timestamp: at(9, 11)}, // before the week
+		{RepoID: "a", Timestamp: at(9, 10)},
+	}
+
+	w := weekReport{Start: start, End: end}
+	addWeekFocus(&w, events, 1)
+	require.Equal(t, 1.0, w.SwitchesPerDay)
+	require.Equal(t, 1, w.DaysOver)
+	require.Equal(t, "1.0 context switches per active day (1 day over 1)", w.Focus())
+}
+
+func TestWriteWeekReport_Formats(t *testing.T) {
+	start, end := weekBounds(time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local))
+	w := buildWeekReport(weekTestEvents(), nil, start, end, end, weekTestMeta)
+	addWeekFocus(&w, weekTestEvents(), 0)
+
+	var txt strings.Builder
+	require.NoError(t, writeWeekReport(&txt, w, "txt"))
+	require.Contains(t, txt.String(), "3 commits, 1 push in 2 repositories on 3 days")
+	require.Contains(t, txt.String(), "6 files changed, +30 -9")
+	require.Contains(t, txt.String(), "0.0 context switches per active day\n")
+	require.NotContains(t, txt.String(), "\x1b[", "plain text has no colors")
+	require.Contains(t, txt.String(), "Events by day\n", "days count every event, not only commits")
+
+	var md strings.Builder
+	require.NoError(t, writeWeekReport(&md, w, "md"))
+	require.Contains(t, md.String(), "# Week of Mar 10 – Mar 16, 2025")
+	require.Contains(t, md.String(), "| github.com/a/one | 2 | 1 | +20 -6 |")
+	require.Contains(t, md.String(), "## Events by day")
+
+	var html strings.Builder
+	require.NoError(t, writeWeekReport(&html, w, "html"))
+	require.Contains(t, html.String(), "<h1>Week of Mar 10 – Mar 16, 2025</h1>")
+	require.Contains(t, html.String(), "Merge branch &#39;feature&#39;")
+}
+
+func TestWeekReport_PushOutcomes(t *testing.T) {
+	w := weekReport{Commits: 2, Pushes: 4, ActiveDays: 1, Repos: []weekRepo{{ID: "a"}}}
+	require.Equal(t, "2 commits, 4 pushes in 1 repository on 1 day", w.Summary(), "no outcome known yet")
+
+	addWeekPushOutcomes(&w, map[int64]string{1: store.PushPushed, 2: store.PushPushed, 3: store.PushRejected, 4: store.PushPending})
+	require.Equal(t, "2 commits, 4 pushes (2 completed, 1 rejected) in 1 repository on 1 day", w.Summary())
+}
+
+func TestReport_WeekEmail(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	var printed strings.Builder
+	var filter store.EventFilter
+	dbPath := filepath.Join(t.TempDir(), "fp.db")
+	deps := Deps{
+		DBPath: func() string { return dbPath },
+		OpenDB: func(path string) (*sql.DB, error) {
+			s, err := store.New(path)
+			if err != nil {
+				return nil, err
+			}
+			return s.DB(), nil
+		},
+		ListEvents: func(_ *sql.DB, f store.EventFilter) ([]store.RepoEvent, error) {
+			filter = f
+			return weekTestEvents(), nil
+		},
+		Now: func() time.Time { return time.Date(2025, 4, 1, 12, 0, 0, 0, time.Local) },
+		Printf: func(format string, a ...any) (int, error) {
+			return fmt.Fprintf(&printed, format, a...)
+		},
+	}
+
+	flags := dispatchers.NewParsedFlags([]string{"--week", "--until=2025-03-14", "--email-stdout"})
+	require.NoError(t, report(nil, flags, deps))
+	require.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local), *filter.Since, "earlier days come from the rollups")
+	require.Equal(t, time.Date(2025, 3, 16, 23, 59, 59, 0, time.Local), *filter.Until)
+	require.True(t, strings.HasPrefix(printed.String(), "Subject: =?utf-8?q?fp:_Week_of_Mar_10_=E2=80=93_Mar_16,_2025?=\n"), printed.String())
+	require.Contains(t, printed.String(), "Content-Type: text/plain; charset=utf-8\nContent-Transfer-Encoding: 8bit\n\nWeek of Mar 10")
+
+	err := report(nil, dispatchers.NewParsedFlags([]string{"--week", "--format=pdf"}), deps)
+	require.ErrorContains(t, err, "invalid --format 'pdf'")
+	err = report(nil, dispatchers.NewParsedFlags([]string{"--week", "--html=out/"}), deps)
+	require.Error(t, err)
+}
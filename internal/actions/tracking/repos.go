@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/footprint-tools/cli/internal/config"
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/output"
 	"github.com/footprint-tools/cli/internal/ui/style"
@@ -30,6 +31,15 @@ func reposList(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		return err
 	}
 
+	rules := config.LoadRepoRules()
+	allowed := repos[:0]
+	for _, r := range repos {
+		if rules.Allows(r.Path) {
+			allowed = append(allowed, r)
+		}
+	}
+	repos = allowed
+
 	if len(repos) == 0 {
 		if jsonOutput {
 			output.JSONEmpty(deps.Println)
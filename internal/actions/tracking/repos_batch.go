@@ -0,0 +1,215 @@
+package tracking
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/hooks"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// Outcomes of a batch hook operation on one repository.
+const (
+	batchInstalled        = "installed"
+	batchRemoved          = "removed"
+	batchWouldInstall     = "would install"
+	batchWouldRemove      = "would remove"
+	batchAlreadyInstalled = "already installed"
+	batchNotInstalled     = "not installed"
+	batchBlocked          = "blocked"
+	batchFailed           = "failed"
+)
+
+// batchResult is one row of the summary printed by repos install/uninstall
+// and hooks upgrade.
+type batchResult struct {
+	Path   string `json:"path"`
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ReposInstall installs hooks in every repository found under --root.
+func ReposInstall(args []string, flags *dispatchers.ParsedFlags) error {
+	return reposBatch(args, flags, DefaultDeps(), true)
+}
+
+// ReposUninstall removes fp hooks from every repository found under --root.
+func ReposUninstall(args []string, flags *dispatchers.ParsedFlags) error {
+	return reposBatch(args, flags, DefaultDeps(), false)
+}
+
+// reposBatch is the non-interactive counterpart of the repos TUI: it scans
+// like `fp repos scan` and installs or removes hooks without prompting, so it
+// can run from provisioning scripts.
+func reposBatch(_ []string, flags *dispatchers.ParsedFlags, deps Deps, install bool) error {
+	jsonOutput := flags.Has("--json")
+	dryRun := flags.Has("--dry-run")
+
+	root := flags.String("--root", ".")
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return fmt.Errorf("invalid path %s: %w", root, err)
+	}
+	root = absRoot
+	maxDepth := flags.Int("--depth", 25)
+
+	if !jsonOutput {
+		_, _ = deps.Printf("Scanning for git repositories in %s...\n", root)
+	}
+	repos, err := scanForRepos(root, maxDepth)
+	if err != nil {
+		return err
+	}
+
+	results := make([]batchResult, 0, len(repos))
+	for _, r := range repos {
+		if install {
+			results = append(results, installRepoHooks(r, dryRun, deps))
+		} else {
+			results = append(results, uninstallRepoHooks(r, dryRun, deps))
+		}
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Result == batchFailed {
+			failed++
+		}
+	}
+
+	if jsonOutput {
+		if err := output.JSON(deps.Println, results); err != nil {
+			return err
+		}
+	} else {
+		printBatchSummary(results, deps)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d %s failed", failed, pluralize(failed, "repository", "repositories"))
+	}
+	return nil
+}
+
+func installRepoHooks(r RepoEntry, dryRun bool, deps Deps) batchResult {
+	res := batchResult{Path: r.Path}
+	switch {
+	case r.HasHooks:
+		res.Result = batchAlreadyInstalled
+		return res
+	case !r.Inspection.Status.CanInstall():
+		res.Result = batchBlocked
+		res.Detail = r.Inspection.Status.String()
+		return res
+	case dryRun:
+		res.Result = batchWouldInstall
+		return res
+	}
+
+	hooksPath, err := git.RepoHooksPath(r.Path)
+	if err == nil {
+		err = hooks.Install(hooksPath)
+	}
+	if err != nil {
+		res.Result = batchFailed
+		res.Detail = err.Error()
+		return res
+	}
+	res.Result = batchInstalled
+
+	if s, err := deps.OpenStore(deps.DBPath()); err == nil {
+		_ = s.AddRepo(r.Path)
+		_ = s.Close()
+	}
+	return res
+}
+
+func uninstallRepoHooks(r RepoEntry, dryRun bool, deps Deps) batchResult {
+	res := batchResult{Path: r.Path}
+	switch {
+	case !r.HasHooks:
+		res.Result = batchNotInstalled
+		return res
+	case dryRun:
+		res.Result = batchWouldRemove
+		return res
+	}
+
+	hooksPath, err := git.RepoHooksPath(r.Path)
+	if err == nil {
+		err = hooks.Uninstall(hooksPath)
+	}
+	if err != nil {
+		res.Result = batchFailed
+		res.Detail = err.Error()
+		return res
+	}
+	res.Result = batchRemoved
+
+	if s, err := deps.OpenStore(deps.DBPath()); err == nil {
+		_ = s.RemoveRepo(r.Path)
+		_ = s.Close()
+	}
+	return res
+}
+
+func printBatchSummary(results []batchResult, deps Deps) {
+	if len(results) == 0 {
+		_, _ = deps.Println("No git repositories found")
+		return
+	}
+
+	home, _ := os.UserHomeDir()
+	width := len("REPOSITORY")
+	paths := make([]string, len(results))
+	for i, r := range results {
+		paths[i] = r.Path
+		if home != "" {
+			if rel, err := filepath.Rel(home, r.Path); err == nil && !strings.HasPrefix(rel, "..") {
+				paths[i] = "~/" + rel
+			}
+		}
+		width = max(width, len(paths[i]))
+	}
+
+	_, _ = deps.Println()
+	_, _ = deps.Println(style.Header(fmt.Sprintf("%-*s  %s", width, "REPOSITORY", "RESULT")))
+
+	counts := make(map[string]int)
+	var order []string
+	for i, r := range results {
+		if counts[r.Result] == 0 {
+			order = append(order, r.Result)
+		}
+		counts[r.Result]++
+
+		result := r.Result
+		switch r.Result {
+		case batchInstalled, batchRemoved, batchUpgraded:
+			result = style.Success(result)
+		case batchWouldInstall, batchWouldRemove, batchWouldUpgrade:
+			result = style.Info(result)
+		case batchBlocked:
+			result = style.Warning(result)
+		case batchFailed:
+			result = style.Error(result)
+		default:
+			result = style.Muted(result)
+		}
+		if r.Detail != "" {
+			result += " " + style.Muted("("+r.Detail+")")
+		}
+		_, _ = deps.Printf("%-*s  %s\n", width, paths[i], result)
+	}
+
+	parts := make([]string, 0, len(order))
+	for _, result := range order {
+		parts = append(parts, fmt.Sprintf("%s: %d", result, counts[result]))
+	}
+	_, _ = deps.Printf("\n%d %s: %s\n", len(results), pluralize(len(results), "repository", "repositories"), strings.Join(parts, ", "))
+}
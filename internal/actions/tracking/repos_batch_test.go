@@ -0,0 +1,84 @@
+package tracking
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func batchTestRoot(t *testing.T, names ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	root := t.TempDir()
+	for _, name := range names {
+		dir := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		out, err := exec.Command("git", "init", "-q", dir).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return root
+}
+
+func batchTestDeps(t *testing.T, out *strings.Builder) Deps {
+	return statusTestDeps(filepath.Join(t.TempDir(), "store.db"), out)
+}
+
+func TestReposInstallAndUninstall(t *testing.T) {
+	root := batchTestRoot(t, "one", "two")
+	var out strings.Builder
+	deps := batchTestDeps(t, &out)
+
+	flags := dispatchers.NewParsedFlags([]string{"--root=" + root})
+	require.NoError(t, reposBatch(nil, flags, deps, true))
+	require.Contains(t, out.String(), "installed: 2")
+	require.FileExists(t, filepath.Join(root, "one", ".git", "hooks", "post-commit"))
+
+	s, err := store.New(deps.DBPath())
+	require.NoError(t, err)
+	tracked, err := s.ListRepos()
+	require.NoError(t, err)
+	require.Len(t, tracked, 2)
+	_ = s.Close()
+
+	out.Reset()
+	require.NoError(t, reposBatch(nil, flags, deps, true))
+	require.Contains(t, out.String(), "already installed: 2")
+
+	out.Reset()
+	require.NoError(t, reposBatch(nil, flags, deps, false))
+	require.Contains(t, out.String(), "removed: 2")
+	require.NoFileExists(t, filepath.Join(root, "one", ".git", "hooks", "post-commit"))
+}
+
+func TestReposInstall_DryRunChangesNothing(t *testing.T) {
+	root := batchTestRoot(t, "one")
+	var out strings.Builder
+	deps := batchTestDeps(t, &out)
+
+	flags := dispatchers.NewParsedFlags([]string{"--root=" + root, "--dry-run"})
+	require.NoError(t, reposBatch(nil, flags, deps, true))
+	require.Contains(t, out.String(), "would install: 1")
+	require.NoFileExists(t, filepath.Join(root, "one", ".git", "hooks", "post-commit"))
+}
+
+func TestReposInstall_JSON(t *testing.T) {
+	root := batchTestRoot(t, "one")
+	var out strings.Builder
+	deps := batchTestDeps(t, &out)
+
+	flags := dispatchers.NewParsedFlags([]string{"--root=" + root, "--json", "--dry-run"})
+	require.NoError(t, reposBatch(nil, flags, deps, true))
+	require.Contains(t, out.String(), `"result": "would install"`)
+	require.NotContains(t, out.String(), "Scanning")
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/config"
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/git"
 	"github.com/footprint-tools/cli/internal/hooks"
@@ -38,7 +39,8 @@ func ReposInteractive(args []string, flags *dispatchers.ParsedFlags) error {
 }
 
 func reposInteractive(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
-	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+	accessible := isAccessible(deps)
+	if !accessible && (!term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd()))) {
 		return errors.New("interactive mode requires a terminal")
 	}
 
@@ -69,16 +71,21 @@ func reposInteractive(_ []string, flags *dispatchers.ParsedFlags, deps Deps) err
 
 	_, _ = deps.Printf("Found %d repositories\n", len(repos))
 
+	if accessible {
+		return reposLinear(repos, deps)
+	}
+
 	// Launch TUI
 	m := newReposModel(repos)
+	m.export.exportWith(deps)
 
-	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	p := tea.NewProgram(components.NewSizeGuard(components.NewHelpOverlay(m)), tea.WithAltScreen(), tea.WithMouseCellMotion())
 	final, err := p.Run()
 	if err != nil {
 		return err
 	}
 
-	fm := final.(reposModel)
+	fm := final.(components.SizeGuard).Model.(components.HelpOverlay).Model.(reposModel)
 
 	// Show summary of changes
 	if fm.installed > 0 || fm.uninstalled > 0 {
@@ -135,6 +142,7 @@ func scanForRepos(root string, maxDepth int) ([]RepoEntry, error) {
 		".git": true,
 	}
 
+	rules := config.LoadRepoRules()
 	rootDepth := strings.Count(root, string(os.PathSeparator))
 
 	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
@@ -157,11 +165,14 @@ func scanForRepos(root string, maxDepth int) ([]RepoEntry, error) {
 		if skipDirs[name] || (strings.HasPrefix(name, ".") && name != ".") {
 			return fs.SkipDir
 		}
+		if rules.Excludes(path) {
+			return fs.SkipDir
+		}
 
 		// Check if this is a git repo
 		gitDir := filepath.Join(path, ".git")
 		if info, err := os.Stat(gitDir); err == nil && info.IsDir() {
-			if !seen[path] {
+			if !seen[path] && rules.Allows(path) {
 				seen[path] = true
 				inspection := hooks.InspectRepo(path)
 				entry := RepoEntry{
@@ -204,6 +215,7 @@ type reposModel struct {
 	focusSidebar   bool
 	drawerOpen     bool // Whether the details drawer is open
 	drawerViewport components.ThemedViewport
+	export         tuiExport // e exports pending events
 }
 
 func newReposModel(repos []RepoEntry) reposModel {
@@ -214,6 +226,7 @@ func newReposModel(repos []RepoEntry) reposModel {
 		focusSidebar:   false, // Start with focus on repo list
 		reposViewport:  components.NewThemedViewport(60, 20),
 		drawerViewport: components.NewThemedViewport(40, 20),
+		export:         newTUIExport(),
 	}
 }
 
@@ -222,6 +235,10 @@ func (m reposModel) Init() tea.Cmd {
 }
 
 func (m reposModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if cmd, ok := m.export.update(msg); ok {
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -268,10 +285,8 @@ func (m reposModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				case "g":
 					m.drawerViewport.GotoTop()
 					return m, nil
-				case "?":
-					m.drawerOpen = false
-					m.drawerViewport.GotoTop()
-					return m, nil
+				case "e":
+					return m, m.export.begin()
 				}
 			}
 			return m, nil
@@ -362,7 +377,9 @@ func (m reposModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.selectAll()
 			case "A":
 				m.deselectAll()
-			case "?", "d":
+			case "e":
+				return m, m.export.begin()
+			case "d":
 				// Open drawer for any repo (d = details)
 				if len(m.repos) > 0 {
 					m.drawerOpen = true
@@ -393,6 +410,10 @@ func (m *reposModel) toggleSelection() {
 }
 
 func (m *reposModel) installSelected() {
+	if config.IsReadOnly() {
+		m.message = "Read-only mode: hooks were not installed"
+		return
+	}
 	count := 0
 	for i := range m.repos {
 		r := &m.repos[i]
@@ -425,6 +446,10 @@ func (m *reposModel) installSelected() {
 }
 
 func (m *reposModel) uninstallSelected() {
+	if config.IsReadOnly() {
+		m.message = "Read-only mode: hooks were not removed"
+		return
+	}
 	count := 0
 	for i := range m.repos {
 		r := &m.repos[i]
@@ -558,7 +583,7 @@ func (m reposModel) View() string {
 
 	footer := m.renderFooter()
 
-	return lipgloss.JoinVertical(lipgloss.Left, header, main, footer)
+	return m.export.render(lipgloss.JoinVertical(lipgloss.Left, header, main, footer))
 }
 
 func (m *reposModel) buildDrawerPanel(layout *splitpanel.Layout, height int) splitpanel.Panel {
@@ -979,10 +1004,12 @@ func (m reposModel) renderFooter() string {
 				key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "select")),
 				key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "all")),
 				key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "details")),
+				key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "export")),
 				key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
 			}
 		}
 	}
+	bindings = append(bindings, key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "keys")))
 
 	footerStyle := lipgloss.NewStyle().
 		Width(m.width).
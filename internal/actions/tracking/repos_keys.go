@@ -0,0 +1,77 @@
+package tracking
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/footprint-tools/cli/internal/ui/components"
+)
+
+// reposKeyMap is the keys of fp repos -i.
+type reposKeyMap struct {
+	components.ListKeyMap
+	Select    key.Binding
+	SelectAll key.Binding
+	ClearAll  key.Binding
+	Install   key.Binding
+	Uninstall key.Binding
+	Details   key.Binding
+	Switch    key.Binding
+	Top       key.Binding
+	Close     key.Binding
+	Quit      key.Binding
+	Export    key.Binding
+}
+
+var reposBindings = reposKeyMap{
+	ListKeyMap: components.NewListKeyMap(),
+	Select:     key.NewBinding(key.WithKeys(" ", "enter"), key.WithHelp("Space/Enter", "select")),
+	SelectAll:  key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "select all")),
+	ClearAll:   key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "clear selection")),
+	Install:    key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "install hooks in the selection")),
+	Uninstall:  key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "remove hooks from the selection")),
+	Details:    key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "details")),
+	Switch:     key.NewBinding(key.WithKeys("tab", "h", "l"), key.WithHelp("Tab h/l", "switch between sidebar and list")),
+	Top:        key.NewBinding(key.WithKeys("g", "home"), key.WithHelp("g/Home", "top")),
+	Close:      key.NewBinding(key.WithKeys("esc", "enter", "q"), key.WithHelp("Esc/Enter/q", "close")),
+	Quit:       key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("Esc/q", "quit")),
+	Export:     key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "export pending events now")),
+}
+
+// HelpSections lists the keys of fp repos -i, the open panel first.
+func (m reposModel) HelpSections() []components.HelpSection {
+	k := reposBindings
+	list := components.HelpSection{Title: "Repositories", Bindings: []key.Binding{
+		k.UpDown,
+		components.Describe(k.Page, "move 10"),
+		k.FirstLast,
+		k.Select,
+		k.SelectAll,
+		k.ClearAll,
+		k.Install,
+		k.Uninstall,
+		k.Details,
+		k.Switch,
+		k.Quit,
+	}}
+	drawer := components.HelpSection{Title: "Details", Bindings: []key.Binding{
+		components.Describe(k.UpDown, "scroll"),
+		components.Describe(k.Page, "scroll 5"),
+		k.Top,
+		k.Close,
+	}}
+	general := components.HelpSection{Title: "Everywhere", Bindings: []key.Binding{
+		k.Export,
+		k.Help,
+		k.ForceQuit,
+	}}
+
+	if m.drawerOpen {
+		return []components.HelpSection{drawer, list, general}
+	}
+	return []components.HelpSection{list, drawer, general}
+}
+
+// CapturingInput is false: fp repos -i has no text fields.
+func (m reposModel) CapturingInput() bool {
+	return false
+}
@@ -1,16 +1,20 @@
 package tracking
 
 import (
+	"bytes"
 	"database/sql"
 	"errors"
+	"strings"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/require"
 
 	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/hooks"
 	"github.com/footprint-tools/cli/internal/store"
 	"github.com/footprint-tools/cli/internal/store/migrations"
+	"github.com/footprint-tools/cli/internal/ui"
 )
 
 func newTestStore(t *testing.T) *store.Store {
@@ -150,3 +154,24 @@ func TestReposList_JSON_WithRepos(t *testing.T) {
 	require.Contains(t, printedOutput, `"path": "/path/to/repo1"`)
 	require.Contains(t, printedOutput, `"path": "/path/to/repo2"`)
 }
+
+func TestReposLinear_BlockedRepoAndBack(t *testing.T) {
+	repos := []RepoEntry{
+		{Path: "/src/api", Name: "api", HasHooks: true},
+		{Path: "/src/web", Name: "web", Inspection: hooks.RepoInspection{Status: hooks.StatusManagedHusky}},
+	}
+	var prompts bytes.Buffer
+	deps := Deps{
+		Printf: func(string, ...any) (int, error) { t.Fatal("nothing was changed"); return 0, nil },
+		NewPrompt: func() *ui.LinearPrompt {
+			return ui.NewLinearPrompt(strings.NewReader("web\n1\n2\n\n"), &prompts)
+		},
+	}
+
+	require.NoError(t, reposLinear(repos, deps))
+	out := prompts.String()
+	require.Contains(t, out, "1. api (/src/api): hooks installed\n")
+	require.Contains(t, out, "2. web (/src/web): blocked, Managed: husky\n")
+	require.Contains(t, out, "fp can't install hooks in web: Managed: husky\n")
+	require.Contains(t, out, "1. Remove hooks\n  2. Back\n")
+}
@@ -0,0 +1,57 @@
+package tracking
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// sidebarTopRepos is how many repos BY REPO lists in the activity
+	// sidebar, busiest first.
+	sidebarTopRepos = 10
+	// repoLinesStart is the sidebar line of the first repo under BY REPO:
+	// after the ten sources, a blank line, BY REPO and another blank line.
+	repoLinesStart = sourceLinesStart + 10 + 3
+)
+
+// topRepos lists the repos BY REPO shows, busiest first.
+func topRepos(byRepo map[string]int) []namedCount {
+	repos := sortedCounts(byRepo)
+	return repos[:min(len(repos), sidebarTopRepos)]
+}
+
+// repoIndexAt returns the index in topRepos of the repo shown on screen row
+// y of a sidebar scrolled by scroll lines, and false for rows without one.
+func repoIndexAt(y, scroll, repos int) (int, bool) {
+	i := y - sidebarTop + scroll - repoLinesStart
+	if i < 0 || i >= repos {
+		return 0, false
+	}
+	return i, true
+}
+
+// repoBreakdownLines renders BY REPO: one line per repo with its event
+// count. The repo being filtered on is marked like the source filters, and
+// the one under the cursor is highlighted while the sidebar has focus.
+func repoBreakdownLines(repos []namedCount, filter string, cursor int, focused bool, width int, valueStyle, labelStyle, cursorStyle lipgloss.Style) []string {
+	var lines []string
+	for i, r := range repos {
+		indicator := "  "
+		if filter != "" && r.name == filter {
+			indicator = "> "
+		}
+		countStr := fmt.Sprintf(" %s", formatCount(r.count))
+		maxNameWidth := width - len(countStr) - 2 // -2 for indent
+		name := r.name
+		if maxNameWidth > 3 && len(name) > maxNameWidth {
+			name = name[:maxNameWidth-3] + "..."
+		}
+		if focused && i == cursor {
+			lines = append(lines, indicator+cursorStyle.Render(name+countStr))
+			continue
+		}
+		lines = append(lines, indicator+valueStyle.Render(name)+labelStyle.Render(countStr))
+	}
+	return lines
+}
@@ -0,0 +1,70 @@
+package tracking
+
+import (
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// sourceFilter is a line under BY SOURCE in the activity and watch
+// sidebars: a source, the key that toggles filtering by it, and its color.
+type sourceFilter struct {
+	key    string
+	source store.Source
+	name   string
+	color  string
+}
+
+// sourceFilters lists the sources in sidebar order.
+func sourceFilters(colors style.ColorConfig) []sourceFilter {
+	return []sourceFilter{
+		{"1", store.SourcePostCommit, "POST-COMMIT", colors.Color1},
+		{"2", store.SourcePostRewrite, "POST-REWRITE", colors.Color2},
+		{"3", store.SourcePostCheckout, "POST-CHECKOUT", colors.Color3},
+		{"4", store.SourcePostMerge, "POST-MERGE", colors.Color4},
+		{"5", store.SourcePrePush, "PRE-PUSH", colors.Color5},
+		{"6", store.SourceManual, "MANUAL", colors.Color7},
+		{"7", store.SourceBackfill, "BACKFILL", colors.Color6},
+		{"8", store.SourceBranchCreate, "BRANCH-CREATE", colors.Color8},
+		{"9", store.SourceBranchDelete, "BRANCH-DELETE", colors.Color9},
+		{"0", store.SourceStash, "STASH", colors.Color10},
+	}
+}
+
+const (
+	// sidebarTop is the screen row of the first sidebar line, below the
+	// header and the top border of the panel.
+	sidebarTop = 2
+	// sourceLinesStart is the sidebar line of the first source. Both
+	// sidebars open with a title, a blank line, the event count, a blank
+	// line, BY SOURCE and another blank line.
+	sourceLinesStart = 6
+)
+
+// sourceKeyAt returns the key of the source shown on screen row y of a
+// sidebar scrolled by scroll lines, and false for rows without a source.
+func sourceKeyAt(y, scroll int) (string, bool) {
+	i := y - sidebarTop + scroll - sourceLinesStart
+	filters := sourceFilters(style.ColorConfig{})
+	if i < 0 || i >= len(filters) {
+		return "", false
+	}
+	return filters[i].key, true
+}
+
+// stepSource returns the source delta lines below current in the sidebar,
+// wrapping around, for moving the filter with the scroll wheel. Without a
+// filter it starts from the top or bottom.
+func stepSource(current store.Source, delta int) store.Source {
+	filters := sourceFilters(style.ColorConfig{})
+	pos := -1
+	for i, sf := range filters {
+		if sf.source == current {
+			pos = i
+		}
+	}
+	if pos == -1 && delta < 0 {
+		pos = 0
+	}
+	n := len(filters)
+	return filters[((pos+delta)%n+n)%n].source
+}
@@ -0,0 +1,95 @@
+package tracking
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestStepSource(t *testing.T) {
+	require.Equal(t, store.SourcePostCommit, stepSource(-1, 1), "from no filter, down starts at the top")
+	require.Equal(t, store.SourceStash, stepSource(-1, -1), "and up at the bottom")
+	require.Equal(t, store.SourcePostRewrite, stepSource(store.SourcePostCommit, 1))
+	require.Equal(t, store.SourcePostCommit, stepSource(store.SourceStash, 1), "wraps around")
+}
+
+// sidebarRow is the screen row of the nth source line in an unscrolled
+// sidebar.
+func sidebarRow(n int) int {
+	return sidebarTop + sourceLinesStart + n
+}
+
+func TestActivityModel_ClickSourceInSidebar(t *testing.T) {
+	events := []store.RepoEvent{
+		{ID: 2, Commit: "bbb", Source: store.SourcePrePush},
+		{ID: 1, Commit: "aaa", Source: store.SourcePostCommit},
+	}
+	var model tea.Model = newActivityModel(events, map[string]git.CommitMetadata{})
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	click := func(y int) {
+		model, _ = model.Update(tea.MouseMsg{X: 2, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	}
+
+	click(sidebarRow(4))
+	m := model.(activityModel)
+	require.Equal(t, store.SourcePrePush, m.filterSource)
+	require.Equal(t, 1, m.focusedPanel)
+	require.Len(t, m.filteredEvents(), 1)
+
+	click(sidebarRow(4))
+	require.Equal(t, store.Source(-1), model.(activityModel).filterSource, "clicking again clears it")
+
+	click(sidebarTop)
+	require.Equal(t, store.Source(-1), model.(activityModel).filterSource, "other lines only focus the sidebar")
+
+	model, _ = model.Update(tea.MouseMsg{X: 2, Y: sidebarRow(0), Button: tea.MouseButtonWheelDown})
+	require.Equal(t, store.SourcePostCommit, model.(activityModel).filterSource)
+	model, _ = model.Update(tea.MouseMsg{X: 2, Y: sidebarRow(0), Button: tea.MouseButtonWheelDown})
+	require.Equal(t, store.SourcePostRewrite, model.(activityModel).filterSource)
+}
+
+func TestWatchModel_ClickSourceInSidebar(t *testing.T) {
+	var model tea.Model = newWatchModel(nil, 0)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	model, _ = model.Update(tea.MouseMsg{X: 2, Y: sidebarRow(9), Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	require.Equal(t, store.SourceStash, model.(watchModel).filterSource)
+
+	model, _ = model.Update(tea.MouseMsg{X: 2, Y: sidebarRow(9), Button: tea.MouseButtonWheelUp})
+	require.Equal(t, store.SourceBranchDelete, model.(watchModel).filterSource)
+}
+
+func TestActivityModel_FilterByRepoInSidebar(t *testing.T) {
+	events := []store.RepoEvent{
+		{ID: 3, Commit: "ccc", RepoPath: "/code/api", Source: store.SourcePostCommit},
+		{ID: 2, Commit: "bbb", RepoPath: "/code/api", Source: store.SourcePostCommit},
+		{ID: 1, Commit: "aaa", RepoPath: "/code/web", Source: store.SourcePostCommit},
+	}
+	var model tea.Model = newActivityModel(events, map[string]git.CommitMetadata{})
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 100, Height: 20})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyTab})
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m := model.(activityModel)
+	require.Equal(t, "api", m.filterRepo, "the busiest repo is selected first")
+	require.Len(t, m.filteredEvents(), 2)
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(activityModel)
+	require.Equal(t, "web", m.filterRepo)
+	require.Len(t, m.filteredEvents(), 1)
+	require.Positive(t, m.sidebarScrollPos(), "the sidebar scrolls to keep the selected repo in view")
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	require.Empty(t, model.(activityModel).filterRepo, "Esc clears it")
+
+	scroll := model.(activityModel).sidebarScrollPos()
+	model, _ = model.Update(tea.MouseMsg{X: 2, Y: sidebarTop + repoLinesStart - scroll, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	require.Equal(t, "api", model.(activityModel).filterRepo, "clicking a repo filters on it")
+}
@@ -0,0 +1,365 @@
+package tracking
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/format"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// statsDefaultDays is how far back fp stats looks without --since.
+const statsDefaultDays = 7
+
+// statsReport is the --json shape of fp stats.
+type statsReport struct {
+	SchemaVersion int        `json:"schema_version"`
+	Project       string     `json:"project,omitempty"`
+	Since         string     `json:"since"`
+	Until         string     `json:"until"`
+	Events        int        `json:"events"`
+	Average       float64    `json:"average_context_switches"`
+	Threshold     int        `json:"context_switch_threshold,omitempty"`
+	Over          int        `json:"days_over_threshold"`
+	Days          []focusDay `json:"days"`
+	// Events with a branch, by whether it was the repository's default
+	DefaultBranch int `json:"default_branch_events"`
+	FeatureBranch int `json:"feature_branch_events"`
+}
+
+// focusDay is one day of activity seen as a sequence of repositories. A
+// context switch is an event in a different repository than the event
+// before it on the same day.
+type focusDay struct {
+	Date     string `json:"date"`
+	Events   int    `json:"events"`
+	Repos    int    `json:"repos"`
+	Switches int    `json:"context_switches"`
+	Over     bool   `json:"over_threshold,omitempty"`
+}
+
+// focusDays groups events by local day, oldest first, counting the
+// repositories touched and the switches between them.
+func focusDays(events []store.RepoEvent) []focusDay {
+	sorted := make([]store.RepoEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	var (
+		days     []focusDay
+		repos    map[string]bool
+		lastRepo string
+	)
+	for _, e := range sorted {
+		key := e.Timestamp.Local().Format(dayKeyLayout)
+		if len(days) == 0 || days[len(days)-1].Date != key {
+			days = append(days, focusDay{Date: key})
+			repos = make(map[string]bool)
+			lastRepo = ""
+		}
+		day := &days[len(days)-1]
+		day.Events++
+		if lastRepo != "" && e.RepoID != lastRepo {
+			day.Switches++
+		}
+		lastRepo = e.RepoID
+		if !repos[e.RepoID] {
+			repos[e.RepoID] = true
+			day.Repos++
+		}
+	}
+	return days
+}
+
+// contextSwitchThreshold returns the context_switch_threshold config key,
+// or 0 when no callout is wanted.
+func contextSwitchThreshold() int {
+	value, _ := config.Get("context_switch_threshold")
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Warn("stats: invalid context_switch_threshold '%s', ignoring it", value)
+		return 0
+	}
+	return n
+}
+
+// markOverThreshold flags the days with more switches than threshold and
+// returns how many there are.
+func markOverThreshold(days []focusDay, threshold int) int {
+	if threshold <= 0 {
+		return 0
+	}
+	over := 0
+	for i := range days {
+		if days[i].Switches > threshold {
+			days[i].Over = true
+			over++
+		}
+	}
+	return over
+}
+
+// averageSwitches is the mean number of switches per active day.
+func averageSwitches(days []focusDay) float64 {
+	if len(days) == 0 {
+		return 0
+	}
+	total := 0
+	for _, d := range days {
+		total += d.Switches
+	}
+	return float64(total) / float64(len(days))
+}
+
+// Stats handles the `fp stats` command.
+func Stats(args []string, flags *dispatchers.ParsedFlags) error {
+	return stats(args, flags, DefaultDeps())
+}
+
+// stats prints focus metrics per day: events, repositories touched, and how
+// often work moved from one repository to another.
+func stats(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	now := deps.Now()
+	until := now
+	if untilStr := flags.String("--until", ""); untilStr != "" {
+		d := flags.Date("--until")
+		if d == nil {
+			return fmt.Errorf("invalid date '%s' for --until: expected %s", untilStr, dispatchers.DateFormats)
+		}
+		until = *d
+	}
+	since := time.Date(until.Year(), until.Month(), until.Day(), 0, 0, 0, 0, until.Location()).AddDate(0, 0, 1-statsDefaultDays)
+	if sinceStr := flags.String("--since", ""); sinceStr != "" {
+		d := flags.Date("--since")
+		if d == nil {
+			return fmt.Errorf("invalid date '%s' for --since: expected %s", sinceStr, dispatchers.DateFormats)
+		}
+		since = *d
+	}
+	if since.After(until) {
+		return fmt.Errorf("--since %s is after --until %s", since.Format(dayKeyLayout), until.Format(dayKeyLayout))
+	}
+
+	db, err := deps.OpenDB(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer store.CloseDB(db)
+
+	filter := store.EventFilter{Since: &since, Until: &until}
+	if device := flags.String("--device", ""); device != "" {
+		filter.Device = &device
+	}
+	if err := projectFilter(db, flags, &filter); err != nil {
+		return err
+	}
+	events, err := deps.ListEvents(db, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+	if flags.Has("--mine") {
+		identities := loadIdentityMatcher()
+		if identities == nil {
+			return errNoIdentities
+		}
+		events = identities.Filter(events)
+	}
+	events = parseHistoryFilter(flags).apply(events, newCommitMetaReader(db))
+	branches := loadDefaultBranches(db)
+	if flags.Has("--default-branch") {
+		events = branches.Filter(events)
+	}
+	onDefault, onFeature := branches.branchShare(events)
+
+	if flags.Has("--by-language") {
+		return statsByLanguage(db, events, since, until, filter.Project, flags.Has("--json"), deps)
+	}
+
+	days := focusDays(events)
+	threshold := contextSwitchThreshold()
+	over := markOverThreshold(days, threshold)
+
+	if flags.Has("--json") {
+		if days == nil {
+			days = []focusDay{}
+		}
+		result := statsReport{
+			SchemaVersion: output.SchemaVersion,
+			Since:         since.Format(dayKeyLayout),
+			Until:         until.Format(dayKeyLayout),
+			Events:        len(events),
+			Average:       averageSwitches(days),
+			Threshold:     threshold,
+			Over:          over,
+			Days:          days,
+			DefaultBranch: onDefault,
+			FeatureBranch: onFeature,
+		}
+		if filter.Project != nil {
+			result.Project = *filter.Project
+		}
+		return output.JSON(deps.Println, result)
+	}
+
+	title := "Focus"
+	if filter.Project != nil {
+		title += " · " + *filter.Project
+	}
+	_, _ = deps.Printf("%s %s\n\n", style.Header(title), style.Muted(format.Date(since)+" – "+format.Date(until)))
+	if len(days) == 0 {
+		_, _ = deps.Println("no events")
+		return nil
+	}
+
+	// The day column holds the date and its weekday, as in "Jan 23 Tue"
+	dayWidth := max(12, format.DateWidth()+4)
+	_, _ = deps.Printf("%-*s %7s %6s %9s\n", dayWidth, "DAY", "EVENTS", "REPOS", "SWITCHES")
+	for _, d := range days {
+		t, _ := time.ParseInLocation(dayKeyLayout, d.Date, time.Local)
+		line := fmt.Sprintf("%-*s %7d %6d %9d", dayWidth, format.Date(t)+" "+t.Format("Mon"), d.Events, d.Repos, d.Switches)
+		if d.Over {
+			line += "  " + style.Warning(fmt.Sprintf("over %d", threshold))
+		}
+		_, _ = deps.Println(line)
+	}
+
+	_, _ = deps.Printf("\n%.1f context switches per active day\n", averageSwitches(days))
+	if share := featureShare(onDefault, onFeature); share != "" {
+		_, _ = deps.Printf("%s of events on feature branches, %d of %d\n", share, onFeature, onDefault+onFeature)
+	}
+	if over > 0 {
+		_, _ = deps.Println(style.Warning(fmt.Sprintf("%d %s over the threshold of %d context switches", over, pluralize(over, "day", "days"), threshold)))
+	}
+	return nil
+}
+
+// languageTotal is the lines one language saw over the commits of a
+// period.
+type languageTotal struct {
+	Language   string `json:"language"`
+	Commits    int    `json:"commits"`
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+}
+
+// languageReport is the --json shape of fp stats --by-language.
+type languageReport struct {
+	SchemaVersion int             `json:"schema_version"`
+	Project       string          `json:"project,omitempty"`
+	Since         string          `json:"since"`
+	Until         string          `json:"until"`
+	Commits       int             `json:"commits"`
+	Unknown       int             `json:"commits_without_languages"`
+	Languages     []languageTotal `json:"languages"`
+}
+
+// languageTotals adds up the languages of the commits of events, counting
+// each commit once however many events it has. unknown is the commits
+// with no languages stored, as those recorded before fp kept them. The
+// busiest language comes first.
+func languageTotals(events []store.RepoEvent, byCommit map[string][]store.LanguageLines) (totals []languageTotal, commits, unknown int) {
+	seen := make(map[string]bool)
+	index := make(map[string]int)
+	for _, e := range events {
+		key := store.CommitKey(e.RepoID, e.Commit)
+		if e.Commit == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		commits++
+
+		lines, ok := byCommit[key]
+		if !ok {
+			unknown++
+			continue
+		}
+		for _, l := range lines {
+			i, ok := index[l.Language]
+			if !ok {
+				i = len(totals)
+				index[l.Language] = i
+				totals = append(totals, languageTotal{Language: l.Language})
+			}
+			totals[i].Commits++
+			totals[i].Insertions += l.Insertions
+			totals[i].Deletions += l.Deletions
+		}
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		ti, tj := totals[i].Insertions+totals[i].Deletions, totals[j].Insertions+totals[j].Deletions
+		if ti != tj {
+			return ti > tj
+		}
+		return totals[i].Language < totals[j].Language
+	})
+	return totals, commits, unknown
+}
+
+// statsByLanguage prints the lines the commits of events added and
+// removed per language.
+func statsByLanguage(db *sql.DB, events []store.RepoEvent, since, until time.Time, project *string, jsonOutput bool, deps Deps) error {
+	byCommit, err := store.ListCommitLanguages(db, &since, &until)
+	if err != nil {
+		return fmt.Errorf("failed to read languages: %w", err)
+	}
+	totals, commits, unknown := languageTotals(events, byCommit)
+
+	if jsonOutput {
+		if totals == nil {
+			totals = []languageTotal{}
+		}
+		result := languageReport{
+			SchemaVersion: output.SchemaVersion,
+			Since:         since.Format(dayKeyLayout),
+			Until:         until.Format(dayKeyLayout),
+			Commits:       commits,
+			Unknown:       unknown,
+			Languages:     totals,
+		}
+		if project != nil {
+			result.Project = *project
+		}
+		return output.JSON(deps.Println, result)
+	}
+
+	title := "Languages"
+	if project != nil {
+		title += " · " + *project
+	}
+	_, _ = deps.Printf("%s %s\n\n", style.Header(title), style.Muted(format.Date(since)+" – "+format.Date(until)))
+	if len(totals) == 0 {
+		_, _ = deps.Println("no line counts")
+	} else {
+		changed := 0
+		width := len("LANGUAGE")
+		for _, t := range totals {
+			changed += t.Insertions + t.Deletions
+			width = max(width, len(t.Language))
+		}
+		_, _ = deps.Printf("%-*s %8s %9s %9s %6s\n", width, "LANGUAGE", "COMMITS", "ADDED", "REMOVED", "SHARE")
+		for _, t := range totals {
+			share := 0
+			if changed > 0 {
+				share = (t.Insertions + t.Deletions) * 100 / changed
+			}
+			_, _ = deps.Printf("%-*s %8d %9s %9s %5d%%\n", width, t.Language, t.Commits,
+				"+"+strconv.Itoa(t.Insertions), "-"+strconv.Itoa(t.Deletions), share)
+		}
+	}
+	if unknown > 0 {
+		_, _ = deps.Printf("\n%s\n", style.Muted(fmt.Sprintf("%d of %d %s have no line counts yet; 'fp backfill' in their repositories adds them",
+			unknown, commits, pluralize(commits, "commit", "commits"))))
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package tracking
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/enrich"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func statsTestEvents() []store.RepoEvent {
+	at := func(d, h, m int) time.Time { return time.Date(2025, 3, d, h, m, 0, 0, time.Local) }
+	// Newest first, like ListEvents
+	return []store.RepoEvent{
+		{RepoID: "github.com/a/one", Timestamp: at(12, 16, 0)},
+		{RepoID: "github.com/a/one", Timestamp: at(12, 9, 0)},
+		{RepoID: "github.com/c/three", Timestamp: at(11, 17, 0)},
+		{RepoID: "github.com/a/one", Timestamp: at(11, 15, 0)},
+		{RepoID: "github.com/b/two", Timestamp: at(11, 11, 0)},
+		{RepoID: "github.com/b/two", Timestamp: at(11, 10, 30)},
+		// The first event of a day is not a switch from the day before
+		{RepoID: "github.com/a/one", Timestamp: at(11, 9, 0)},
+		{RepoID: "github.com/b/two", Timestamp: at(10, 18, 0)},
+	}
+}
+
+func TestFocusDays(t *testing.T) {
+	days := focusDays(statsTestEvents())
+
+	require.Equal(t, []focusDay{
+		{Date: "2025-03-10", Events: 1, Repos: 1, Switches: 0},
+		{Date: "2025-03-11", Events: 5, Repos: 3, Switches: 3},
+		{Date: "2025-03-12", Events: 2, Repos: 1, Switches: 0},
+	}, days)
+	require.InDelta(t, 1.0, averageSwitches(days), 0.001)
+	require.Zero(t, averageSwitches(nil))
+}
+
+func TestMarkOverThreshold(t *testing.T) {
+	days := focusDays(statsTestEvents())
+
+	require.Equal(t, 0, markOverThreshold(days, 0), "no threshold set")
+	require.Equal(t, 0, markOverThreshold(days, 3), "over means more than")
+	require.Equal(t, 1, markOverThreshold(days, 2))
+	require.True(t, days[1].Over)
+}
+
+func TestContextSwitchThreshold(t *testing.T) {
+	setRedactionConfig(t, "context_switch_threshold", "8")
+	require.Equal(t, 8, contextSwitchThreshold())
+
+	setRedactionConfig(t, "context_switch_threshold", "lots")
+	require.Equal(t, 0, contextSwitchThreshold())
+}
+
+func statsTestDeps(printed *strings.Builder) Deps {
+	return Deps{
+		DBPath: func() string { return ":memory:" },
+		OpenDB: func(path string) (*sql.DB, error) { return sql.Open("sqlite3", path) },
+		ListEvents: func(*sql.DB, store.EventFilter) ([]store.RepoEvent, error) {
+			return statsTestEvents(), nil
+		},
+		Now: func() time.Time { return time.Date(2025, 3, 12, 18, 0, 0, 0, time.Local) },
+		Printf: func(format string, a ...any) (int, error) {
+			printed.WriteString(fmt.Sprintf(format, a...))
+			return 0, nil
+		},
+		Println: func(a ...any) (int, error) {
+			printed.WriteString(a[0].(string) + "\n")
+			return 0, nil
+		},
+	}
+}
+
+func TestStats_Text(t *testing.T) {
+	setRedactionConfig(t, "context_switch_threshold", "2")
+
+	var printed strings.Builder
+	err := stats(nil, dispatchers.NewParsedFlags(nil), statsTestDeps(&printed))
+	require.NoError(t, err)
+
+	out := printed.String()
+	require.Contains(t, out, "SWITCHES")
+	require.Contains(t, out, "1.0 context switches per active day")
+	require.Contains(t, out, "1 day over the threshold of 2 context switches")
+}
+
+func TestStats_JSON(t *testing.T) {
+	setRedactionConfig(t, "context_switch_threshold", "2")
+
+	var printed strings.Builder
+	err := stats(nil, dispatchers.NewParsedFlags([]string{"--json"}), statsTestDeps(&printed))
+	require.NoError(t, err)
+
+	var result struct {
+		Since   string     `json:"since"`
+		Until   string     `json:"until"`
+		Average float64    `json:"average_context_switches"`
+		Over    int        `json:"days_over_threshold"`
+		Days    []focusDay `json:"days"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(printed.String()), &result))
+	require.Equal(t, "2025-03-06", result.Since, "a week ending today")
+	require.Equal(t, 1, result.Over)
+	require.Len(t, result.Days, 3)
+	require.True(t, result.Days[1].Over)
+}
+
+func TestStats_SinceAfterUntil(t *testing.T) {
+	var printed strings.Builder
+	err := stats(nil, dispatchers.NewParsedFlags([]string{"--since=2025-03-12", "--until=2025-03-01"}), statsTestDeps(&printed))
+	require.ErrorContains(t, err, "is after")
+}
+
+func TestLanguageTotals(t *testing.T) {
+	events := []store.RepoEvent{
+		{RepoID: "r", Commit: "aaa"},
+		{RepoID: "r", Commit: "aaa"}, // pushed as well: counted once
+		{RepoID: "r", Commit: "bbb"},
+		{RepoID: "r", Commit: "ccc"},
+		{RepoID: "r"},
+	}
+	byCommit := map[string][]store.LanguageLines{
+		store.CommitKey("r", "aaa"): {{Language: "Go", Insertions: 10, Deletions: 2}, {Language: "YAML", Insertions: 3}},
+		store.CommitKey("r", "bbb"): {{Language: "Go", Insertions: 5, Deletions: 5}, {Language: "Markdown", Insertions: 3}},
+	}
+
+	totals, commits, unknown := languageTotals(events, byCommit)
+	require.Equal(t, []languageTotal{
+		{Language: "Go", Commits: 2, Insertions: 15, Deletions: 7},
+		{Language: "Markdown", Commits: 1, Insertions: 3},
+		{Language: "YAML", Commits: 1, Insertions: 3},
+	}, totals)
+	require.Equal(t, 3, commits)
+	require.Equal(t, 1, unknown)
+}
+
+func TestStats_ByLanguage(t *testing.T) {
+	setRedactionConfig(t)
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+	db, err := openDBFresh(dbPath)
+	require.NoError(t, err)
+	at := time.Date(2025, 3, 11, 10, 0, 0, 0, time.Local)
+	for _, commit := range []string{"aaa", "bbb"} {
+		require.NoError(t, store.InsertEvent(db, store.RepoEvent{RepoID: "github.com/a/one", Commit: commit, Branch: "main", Timestamp: at, Status: store.StatusPending, Source: store.SourcePostCommit}))
+	}
+	require.NoError(t, store.SaveCommitLanguages(db, "github.com/a/one", "aaa", enrich.LinesByLanguage([]git.FileStat{
+		{Path: "main.go", Insertions: 30, Deletions: 10},
+		{Path: "docs/README.md", Insertions: 10},
+		{Path: "go.mod", Insertions: 2},
+	})))
+	store.CloseDB(db)
+
+	var printed strings.Builder
+	deps := statsTestDeps(&printed)
+	deps.DBPath = func() string { return dbPath }
+	deps.OpenDB = openDBFresh
+	deps.ListEvents = store.ListEvents
+
+	require.NoError(t, stats(nil, dispatchers.NewParsedFlags([]string{"--by-language"}), deps))
+	out := printed.String()
+	require.Regexp(t, `Go\s+1\s+\+32\s+-10\s+80%`, out)
+	require.Regexp(t, `Markdown\s+1\s+\+10\s+-0\s+19%`, out)
+	require.Contains(t, out, "1 of 2 commits have no line counts yet")
+
+	printed.Reset()
+	require.NoError(t, stats(nil, dispatchers.NewParsedFlags([]string{"--by-language", "--json"}), deps))
+	var report languageReport
+	require.NoError(t, json.Unmarshal([]byte(printed.String()), &report))
+	require.Equal(t, 2, report.Commits)
+	require.Equal(t, 1, report.Unknown)
+	require.Equal(t, "Go", report.Languages[0].Language)
+}
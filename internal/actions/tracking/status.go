@@ -0,0 +1,90 @@
+package tracking
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// Status handles the `fp status` command.
+func Status(args []string, flags *dispatchers.ParsedFlags) error {
+	return status(args, flags, DefaultDeps())
+}
+
+// statusReport is the --json shape of fp status.
+type statusReport struct {
+	SchemaVersion int          `json:"schema_version"`
+	Ready         bool         `json:"ready"`
+	Checks        []setupCheck `json:"checks"`
+	LastExport    *time.Time   `json:"last_export,omitempty"`
+	Repos         []repoHealth `json:"repos,omitempty"`
+}
+
+// status shows the setup checklist: what is done, what is missing, and the
+// exact commands that fix each missing step. Below it come the hooks, branch
+// and export backlog of the current repository, or of every tracked one
+// with --all.
+func status(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	checks := runSetupChecks(deps)
+
+	ready := true
+	for _, c := range checks {
+		if !c.OK && !c.Optional {
+			ready = false
+		}
+	}
+	lastExport := lastExportTime()
+	repos := repoHealthFor(deps, statusRepoPaths(deps, flags.Has("--all")))
+
+	if flags.Has("--json") {
+		return output.JSON(deps.Println, statusReport{SchemaVersion: output.SchemaVersion, Ready: ready, Checks: checks, LastExport: lastExport, Repos: repos})
+	}
+
+	for _, c := range checks {
+		marker := style.Success("✓")
+		switch {
+		case !c.OK && c.Optional:
+			marker = style.Muted("○")
+		case !c.OK:
+			marker = style.Error("✗")
+		}
+		_, _ = deps.Printf("%s %s\n", marker, c.Title)
+		if c.OK {
+			continue
+		}
+		if c.Detail != "" {
+			_, _ = deps.Printf("    %s\n", style.Muted(c.Detail))
+		}
+		for _, fix := range c.Fix {
+			_, _ = deps.Printf("    %s %s\n", style.Muted("→"), style.Info(fix))
+		}
+	}
+
+	printRepoHealth(deps, repos)
+
+	_, _ = deps.Println("")
+	if lastExport != nil {
+		_, _ = deps.Printf("Last export: %s\n", lastExport.Local().Format("2006-01-02 15:04"))
+	}
+	if ready {
+		_, _ = deps.Println(style.Success("All set. fp is recording your activity."))
+	} else {
+		_, _ = deps.Println(style.Warning("Run the commands above to finish setting up fp."))
+	}
+	return nil
+}
+
+// lastExportTime returns when fp last exported, or nil if it never has.
+func lastExportTime() *time.Time {
+	value, _ := config.Get("export_last")
+	ts, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ts <= 0 {
+		return nil
+	}
+	t := time.Unix(ts, 0).UTC()
+	return &t
+}
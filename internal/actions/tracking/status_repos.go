@@ -0,0 +1,160 @@
+package tracking
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/hooks"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// repoHealth is the state of one repository in fp status.
+type repoHealth struct {
+	Path         string                     `json:"path"`
+	RepoID       string                     `json:"repo_id,omitempty"`
+	Branch       string                     `json:"branch,omitempty"`
+	HooksPath    string                     `json:"hooks_path,omitempty"`
+	Hooks        map[string]hooks.HookState `json:"hooks"`
+	HooksCurrent bool                       `json:"hooks_current"`
+	LastEvent    *repoLastEvent             `json:"last_event,omitempty"`
+	Pending      int64                      `json:"pending"`
+	Error        string                     `json:"error,omitempty"`
+}
+
+// repoLastEvent is the newest event recorded for a repository.
+type repoLastEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source"`
+	Commit    string    `json:"commit"`
+	Branch    string    `json:"branch"`
+}
+
+// statusRepoPaths returns the repositories fp status reports on: every
+// tracked one with all, otherwise the one containing the working directory.
+func statusRepoPaths(deps Deps, all bool) []string {
+	if !all {
+		if root, err := deps.RepoRoot("."); err == nil {
+			return []string{root}
+		}
+		return nil
+	}
+
+	s, err := deps.OpenStore(deps.DBPath())
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = s.Close() }()
+	paths, _ := s.ListRepoPaths()
+	return paths
+}
+
+// repoHealthFor gathers the hook, branch and event state of each path.
+func repoHealthFor(deps Deps, paths []string) []repoHealth {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	var activity map[string]store.RepoActivity
+	if s, err := deps.OpenStore(deps.DBPath()); err == nil {
+		activity, _ = s.ActivityByRepo()
+		_ = s.Close()
+	}
+
+	results := make([]repoHealth, 0, len(paths))
+	for _, path := range paths {
+		results = append(results, inspectRepo(deps, path, activity))
+	}
+	return results
+}
+
+func inspectRepo(deps Deps, path string, activity map[string]store.RepoActivity) repoHealth {
+	result := repoHealth{Path: path}
+
+	repoRoot, err := deps.RepoRoot(path)
+	if err != nil {
+		result.Error = "not a git repository"
+		return result
+	}
+	result.Path = repoRoot
+
+	remoteURL, _ := deps.OriginURL(repoRoot)
+	if id, err := deps.DeriveID(remoteURL, repoRoot); err == nil {
+		result.RepoID = string(id)
+	}
+	result.Branch, _ = git.GetCurrentBranch(repoRoot)
+
+	if hooksPath, err := git.RepoHooksPath(repoRoot); err == nil {
+		result.HooksPath = hooksPath
+		result.Hooks = hooks.States(hooksPath)
+		result.HooksCurrent = true
+		for _, state := range result.Hooks {
+			if state != hooks.HookCurrent {
+				result.HooksCurrent = false
+			}
+		}
+	} else {
+		result.Error = fmt.Sprintf("could not find hooks directory: %v", err)
+	}
+
+	if a, ok := activity[result.RepoID]; ok {
+		result.Pending = a.Pending
+		result.LastEvent = &repoLastEvent{
+			Timestamp: a.LastEvent.Timestamp,
+			Source:    a.LastEvent.Source.String(),
+			Commit:    a.LastEvent.Commit,
+			Branch:    a.LastEvent.Branch,
+		}
+	}
+	return result
+}
+
+// printRepoHealth prints one block per repository after the checklist.
+func printRepoHealth(deps Deps, repos []repoHealth) {
+	for _, r := range repos {
+		_, _ = deps.Println("")
+		_, _ = deps.Println(style.Header(r.Path))
+		if r.Error != "" {
+			_, _ = deps.Printf("  %s\n", style.Error(r.Error))
+			continue
+		}
+
+		if r.Branch != "" {
+			_, _ = deps.Printf("  %-12s %s\n", "branch", r.Branch)
+		}
+		if r.LastEvent != nil {
+			_, _ = deps.Printf("  %-12s %s  %s  %s\n", "last event",
+				r.LastEvent.Timestamp.Local().Format("2006-01-02 15:04"),
+				r.LastEvent.Source, shortHash(r.LastEvent.Commit))
+		} else {
+			_, _ = deps.Printf("  %-12s %s\n", "last event", style.Muted("none"))
+		}
+		_, _ = deps.Printf("  %-12s %d\n", "pending", r.Pending)
+
+		_, _ = deps.Printf("  %s\n", "hooks")
+		fix := "fp setup " + shellArg(r.Path)
+		for _, hook := range hooks.ManagedHooks {
+			state := r.Hooks[hook]
+			marker := style.Success("✓")
+			switch state {
+			case hooks.HookMissing:
+				marker = style.Muted("○")
+			case hooks.HookOutdated, hooks.HookForeign:
+				marker = style.Warning("!")
+				fix = "fp setup --force " + shellArg(r.Path)
+			}
+			_, _ = deps.Printf("    %s %-22s %s\n", marker, hook, style.Muted(string(state)))
+		}
+		if !r.HooksCurrent {
+			_, _ = deps.Printf("    %s %s\n", style.Muted("→"), style.Info(fix))
+		}
+	}
+}
+
+func shortHash(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
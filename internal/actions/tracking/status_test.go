@@ -0,0 +1,143 @@
+package tracking
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/hooks"
+	repodomain "github.com/footprint-tools/cli/internal/repo"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func statusTestDeps(dbPath string, out *strings.Builder) Deps {
+	return Deps{
+		GitIsAvailable: func() bool { return true },
+		RepoRoot:       func(string) (string, error) { return "", errors.New("not a git repository") },
+		DBPath:         func() string { return dbPath },
+		OpenStore:      store.New,
+		Printf: func(format string, a ...any) (int, error) {
+			return fmt.Fprintf(out, format, a...)
+		},
+		Println: func(a ...any) (int, error) {
+			return fmt.Fprintln(out, a...)
+		},
+	}
+}
+
+func TestStatus_FreshInstallShowsNextSteps(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+
+	var out strings.Builder
+	err := status(nil, dispatchers.NewParsedFlags(nil), statusTestDeps(dbPath, &out))
+	require.NoError(t, err)
+
+	text := out.String()
+	require.Contains(t, text, "No repositories tracked yet")
+	require.Contains(t, text, "fp repos scan --root ~/code")
+	require.Contains(t, text, "fp backfill --all")
+	require.Contains(t, text, "fp config set export_remote")
+	require.Contains(t, text, "Run the commands above")
+}
+
+func TestStatus_LockedDatabase(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	var out strings.Builder
+	deps := statusTestDeps(filepath.Join(t.TempDir(), "store.db"), &out)
+	deps.OpenStore = func(string) (*store.Store, error) { return nil, store.ErrLocked }
+
+	err := status(nil, dispatchers.NewParsedFlags(nil), deps)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "Database is encrypted and locked")
+	require.Contains(t, out.String(), "fp unlock")
+}
+
+func TestStatus_JSONReady(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+
+	s, err := store.New(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, s.AddRepo(t.TempDir()))
+	require.NoError(t, store.InsertEvent(s.DB(), store.RepoEvent{
+		RepoID:    "github.com/a/one",
+		Commit:    "abc",
+		Branch:    "main",
+		Timestamp: time.Now(),
+		Status:    store.StatusPending,
+		Source:    store.SourcePostCommit,
+	}))
+	require.NoError(t, s.Close())
+
+	var out strings.Builder
+	err = status(nil, dispatchers.NewParsedFlags([]string{"--json"}), statusTestDeps(dbPath, &out))
+	require.NoError(t, err)
+
+	text := out.String()
+	require.Contains(t, text, `"ready": true`)
+	require.Contains(t, text, `"id": "export_remote"`)
+	require.Contains(t, text, `"title": "1 events recorded (1 pending export)"`)
+}
+
+func TestStatus_RepoHealth(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dbPath := filepath.Join(t.TempDir(), "store.db")
+
+	repoDir := t.TempDir()
+	hooksDir := filepath.Join(repoDir, ".git", "hooks")
+	require.NoError(t, os.MkdirAll(hooksDir, 0o755))
+	require.NoError(t, exec.Command("git", "init", "-q", repoDir).Run())
+	require.NoError(t, os.WriteFile(filepath.Join(hooksDir, "pre-push"), []byte("#!/bin/sh\necho mine\n"), 0o755))
+
+	s, err := store.New(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, s.AddRepo(repoDir))
+	require.NoError(t, store.InsertEvent(s.DB(), store.RepoEvent{
+		RepoID:    "local:" + repoDir,
+		Commit:    "abcdef1234",
+		Branch:    "main",
+		Timestamp: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		Status:    store.StatusPending,
+		Source:    store.SourcePostCommit,
+	}))
+	require.NoError(t, s.Close())
+
+	deps := statusTestDeps(dbPath, new(strings.Builder))
+	deps.RepoRoot = func(path string) (string, error) {
+		if path == "." {
+			return "", errors.New("not a git repository")
+		}
+		return path, nil
+	}
+	deps.OriginURL = func(string) (string, error) { return "", nil }
+	deps.DeriveID = func(_, root string) (repodomain.RepoID, error) { return repodomain.RepoID("local:" + root), nil }
+
+	var out strings.Builder
+	deps.Println = func(a ...any) (int, error) { return fmt.Fprintln(&out, a...) }
+	require.NoError(t, status(nil, dispatchers.NewParsedFlags(nil), deps))
+	require.NotContains(t, out.String(), repoDir, "only --all lists repos outside the working directory")
+
+	out.Reset()
+	require.NoError(t, status(nil, dispatchers.NewParsedFlags([]string{"--all", "--json"}), deps))
+
+	var report statusReport
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &report))
+	require.Len(t, report.Repos, 1)
+	r := report.Repos[0]
+	require.Equal(t, "local:"+repoDir, r.RepoID)
+	require.Equal(t, int64(1), r.Pending)
+	require.NotNil(t, r.LastEvent)
+	require.Equal(t, "abcdef1234", r.LastEvent.Commit)
+	require.False(t, r.HooksCurrent)
+	require.Equal(t, hooks.HookForeign, r.Hooks["pre-push"])
+	require.Equal(t, hooks.HookMissing, r.Hooks["post-commit"])
+}
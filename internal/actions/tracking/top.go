@@ -0,0 +1,330 @@
+package tracking
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/daemon"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/components"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"golang.org/x/term"
+)
+
+const (
+	// topDays is how many days each repository's sparkline covers, today
+	// included.
+	topDays = 14
+	// topInterval is how often fp top reloads without --interval.
+	topInterval = 2 * time.Second
+)
+
+// Top runs the live dashboard.
+func Top(args []string, flags *dispatchers.ParsedFlags) error {
+	return top(args, flags, DefaultDeps())
+}
+
+func top(_ []string, flags *dispatchers.ParsedFlags, deps Deps) error {
+	interval := topInterval
+	if s := flags.String("--interval", ""); s != "" {
+		secs, err := strconv.Atoi(s)
+		if err != nil || secs < 1 {
+			return fmt.Errorf("invalid interval '%s': expected a whole number of seconds, at least 1", s)
+		}
+		interval = time.Duration(secs) * time.Second
+	}
+
+	accessible := isAccessible(deps)
+	if !accessible && (!term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd()))) {
+		return errors.New("fp top requires an interactive terminal")
+	}
+
+	db, err := deps.OpenSnapshot(deps.DBPath())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer store.CloseDB(db)
+
+	load := func(now time.Time) (topSnapshot, error) {
+		return loadTopSnapshot(db, deps.GetExportRepo(), now)
+	}
+
+	if accessible {
+		snap, err := load(deps.Now())
+		if err != nil {
+			return err
+		}
+		printTopSummary(snap, deps)
+		return nil
+	}
+
+	m := newTopModel(load, interval)
+	p := tea.NewProgram(components.NewSizeGuard(components.NewHelpOverlay(m)), tea.WithAltScreen())
+	_, err = p.Run()
+	return err
+}
+
+// topRepo is a repository row of fp top.
+type topRepo struct {
+	id    string
+	name  string
+	days  [topDays]int // events per day, oldest first, today last
+	total int
+}
+
+func (r topRepo) today() int {
+	return r.days[topDays-1]
+}
+
+// topSnapshot is everything fp top shows, read at once.
+type topSnapshot struct {
+	at time.Time
+
+	// Today's totals across repositories
+	events      int
+	insertions  int
+	deletions   int
+	activeRepos int
+
+	// Repositories with events in the last topDays days, busiest today
+	// first, then busiest over the whole window
+	repos []topRepo
+
+	pending  int
+	schedule exportSchedule
+
+	// The export repo's primary remote: whether there is one, whether the
+	// branch was ever pushed to it, and how many commits it is behind
+	remote   bool
+	pushed   bool
+	unpushed int
+}
+
+// loadTopSnapshot reads the daily rollups of the last topDays days, the
+// export backlog and the state of the export repo. The push status comes
+// from the export repo alone, as of its last push or fetch, so refreshing
+// never touches the network.
+func loadTopSnapshot(db *sql.DB, exportRepo string, now time.Time) (topSnapshot, error) {
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, -(topDays - 1))
+	rollups, err := store.ListDailyRollups(db, store.RollupFilter{Since: &since})
+	if err != nil {
+		return topSnapshot{}, fmt.Errorf("failed to read daily counts: %w", err)
+	}
+	snap := summarizeTop(rollups, now)
+
+	counts, err := store.NewWithDB(db).CountByStatus()
+	if err != nil {
+		return topSnapshot{}, fmt.Errorf("failed to count pending events: %w", err)
+	}
+	snap.pending = int(counts[domain.StatusPending])
+
+	snap.schedule = loadExportSchedule(now, daemon.IsRunning(), config.IsReadOnly())
+	if diagnoseExportRepo(exportRepo) != exportRepoMissing && hasRemote(exportRepo) {
+		snap.remote = true
+		snap.unpushed, snap.pushed = unpushedCommits(exportRepo, primaryRemote, exportBranch(exportRepo))
+	}
+	return snap, nil
+}
+
+// summarizeTop totals today's rollups and spreads each repository's over
+// the last topDays days ending with now.
+func summarizeTop(rollups []store.DailyRollup, now time.Time) topSnapshot {
+	snap := topSnapshot{at: now}
+
+	index := make(map[string]int, topDays)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	for i := range topDays {
+		index[today.AddDate(0, 0, i-(topDays-1)).Format(dayKeyLayout)] = i
+	}
+
+	byRepo := make(map[string]*topRepo)
+	activeToday := make(map[string]bool)
+	for _, r := range rollups {
+		i, ok := index[r.Day]
+		if !ok {
+			continue
+		}
+		repo, ok := byRepo[r.RepoID]
+		if !ok {
+			name := filepath.Base(r.RepoPath)
+			if name == "" || name == "." {
+				name = r.RepoID
+			}
+			repo = &topRepo{id: r.RepoID, name: name}
+			byRepo[r.RepoID] = repo
+		}
+		repo.days[i] += r.Events
+		repo.total += r.Events
+
+		if i == topDays-1 {
+			snap.events += r.Events
+			snap.insertions += r.Insertions
+			snap.deletions += r.Deletions
+			activeToday[r.RepoID] = true
+		}
+	}
+	snap.activeRepos = len(activeToday)
+
+	snap.repos = make([]topRepo, 0, len(byRepo))
+	for _, r := range byRepo {
+		snap.repos = append(snap.repos, *r)
+	}
+	sort.Slice(snap.repos, func(i, j int) bool {
+		a, b := snap.repos[i], snap.repos[j]
+		if a.today() != b.today() {
+			return a.today() > b.today()
+		}
+		if a.total != b.total {
+			return a.total > b.total
+		}
+		return a.name < b.name
+	})
+	return snap
+}
+
+// sparklineGlyphs are the bar heights of a sparkline, lowest first.
+var sparklineGlyphs = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws counts as one bar each, scaled to the largest. Days
+// without events are a dot, so a quiet day doesn't look like a slow one.
+func sparkline(counts []int) string {
+	peak := 0
+	for _, n := range counts {
+		peak = max(peak, n)
+	}
+	bars := make([]rune, len(counts))
+	for i, n := range counts {
+		if n <= 0 {
+			bars[i] = '·'
+			continue
+		}
+		level := (n*len(sparklineGlyphs) + peak - 1) / peak // ceil
+		bars[i] = sparklineGlyphs[max(1, min(len(sparklineGlyphs), level))-1]
+	}
+	return string(bars)
+}
+
+// Messages
+
+// Ticks and loads carry the generation that started them: refreshing by
+// hand starts a new one, so the old ticker stops instead of running
+// alongside.
+type topTickMsg struct {
+	seq int
+}
+
+type topLoadedMsg struct {
+	seq  int
+	snap topSnapshot
+	err  error
+}
+
+// topModel is the Bubble Tea model for fp top.
+type topModel struct {
+	load     func(now time.Time) (topSnapshot, error)
+	interval time.Duration
+
+	snap   topSnapshot
+	loaded bool
+	err    error
+
+	paused bool
+	seq    int
+
+	// UI dimensions
+	width  int
+	height int
+
+	// Styling
+	colors style.ColorConfig
+}
+
+func newTopModel(load func(now time.Time) (topSnapshot, error), interval time.Duration) topModel {
+	return topModel{
+		load:     load,
+		interval: interval,
+		colors:   style.GetColors(),
+	}
+}
+
+// Init implements tea.Model
+func (m topModel) Init() tea.Cmd {
+	return m.reload()
+}
+
+// reload reads a new snapshot off the UI goroutine.
+func (m topModel) reload() tea.Cmd {
+	load, seq := m.load, m.seq
+	return func() tea.Msg {
+		snap, err := load(time.Now())
+		return topLoadedMsg{seq: seq, snap: snap, err: err}
+	}
+}
+
+func (m topModel) tick() tea.Cmd {
+	seq := m.seq
+	return tea.Tick(m.interval, func(time.Time) tea.Msg {
+		return topTickMsg{seq: seq}
+	})
+}
+
+// Update implements tea.Model
+func (m topModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+
+	case topTickMsg:
+		if m.paused || msg.seq != m.seq {
+			return m, nil
+		}
+		return m, m.reload()
+
+	case topLoadedMsg:
+		if msg.seq != m.seq {
+			// A newer load is on its way
+			return m, nil
+		}
+		// Keep showing the last good snapshot when a read fails
+		m.err = msg.err
+		if msg.err == nil {
+			m.snap = msg.snap
+			m.loaded = true
+		}
+		return m, m.tick()
+	}
+
+	return m, nil
+}
+
+func (m topModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		return m, tea.Quit
+	case "p":
+		m.paused = !m.paused
+		if !m.paused {
+			m.seq++
+			return m, m.reload()
+		}
+	case "r":
+		m.seq++
+		return m, m.reload()
+	}
+	return m, nil
+}
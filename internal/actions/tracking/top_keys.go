@@ -0,0 +1,39 @@
+package tracking
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/footprint-tools/cli/internal/ui/components"
+)
+
+// topKeyMap is the keys of fp top.
+type topKeyMap struct {
+	components.ListKeyMap
+	Pause   key.Binding
+	Refresh key.Binding
+	Quit    key.Binding
+}
+
+var topBindings = topKeyMap{
+	ListKeyMap: components.NewListKeyMap(),
+	Pause:      key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pause or resume refreshing")),
+	Refresh:    key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh now")),
+	Quit:       key.NewBinding(key.WithKeys("q", "esc"), key.WithHelp("q/Esc", "quit")),
+}
+
+// HelpSections lists the keys of fp top.
+func (m topModel) HelpSections() []components.HelpSection {
+	k := topBindings
+	return []components.HelpSection{{Title: "Dashboard", Bindings: []key.Binding{
+		k.Pause,
+		k.Refresh,
+		k.Help,
+		k.Quit,
+		k.ForceQuit,
+	}}}
+}
+
+// CapturingInput is false: fp top takes no text input.
+func (m topModel) CapturingInput() bool {
+	return false
+}
@@ -0,0 +1,171 @@
+package tracking
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/testutil"
+)
+
+func TestSummarizeTop(t *testing.T) {
+	now := time.Date(2025, 3, 14, 18, 0, 0, 0, time.Local)
+	rollups := heatmapRollups(
+		heatmapEvent("a", heatmapDay(2025, 3, 14)),
+		heatmapEvent("a", heatmapDay(2025, 3, 14)),
+		heatmapEvent("b", heatmapDay(2025, 3, 14)),
+		heatmapEvent("c", heatmapDay(2025, 3, 10)),
+		heatmapEvent("c", heatmapDay(2025, 3, 10)),
+		heatmapEvent("c", heatmapDay(2025, 3, 1)),
+		// Outside the 14 days
+		heatmapEvent("d", heatmapDay(2025, 2, 28)),
+	)
+	rollups[0].Insertions, rollups[0].Deletions = 30, 5
+
+	snap := summarizeTop(rollups, now)
+	require.Equal(t, 3, snap.events)
+	require.Equal(t, 30, snap.insertions)
+	require.Equal(t, 5, snap.deletions)
+	require.Equal(t, 2, snap.activeRepos)
+
+	require.Len(t, snap.repos, 3)
+	require.Equal(t, "a", snap.repos[0].name)
+	require.Equal(t, 2, snap.repos[0].today())
+	require.Equal(t, "b", snap.repos[1].name)
+	require.Equal(t, "c", snap.repos[2].name)
+	require.Equal(t, 3, snap.repos[2].total)
+	require.Equal(t, 1, snap.repos[2].days[0])
+	require.Equal(t, 2, snap.repos[2].days[topDays-5])
+}
+
+func TestSparkline(t *testing.T) {
+	require.Equal(t, "·▂▄█", sparkline([]int{0, 1, 2, 4}))
+	require.Equal(t, "··", sparkline([]int{0, 0}))
+	require.Equal(t, "▁█", sparkline([]int{1, 100}))
+}
+
+func TestTopStatusRows(t *testing.T) {
+	now := time.Date(2025, 3, 14, 18, 0, 0, 0, time.Local)
+	last := now.Add(-90 * time.Minute)
+	snap := topSnapshot{
+		at:       now,
+		pending:  3,
+		schedule: exportSchedule{LastExport: &last, NextExport: now.Add(30 * time.Minute), Runner: exportRunnerDaemon},
+		remote:   true,
+		pushed:   true,
+		unpushed: 2,
+	}
+
+	rows := topStatusRows(snap)
+	require.Equal(t, "3 events", rows[0].value)
+	require.Contains(t, rows[1].value, "(1h30m ago)")
+	require.Contains(t, rows[2].value, "(in 30m)")
+	require.Equal(t, "2 commits not pushed to origin", rows[3].value)
+	require.True(t, rows[3].warn)
+
+	snap.pending, snap.unpushed = 0, 0
+	snap.schedule = exportSchedule{Due: true, Runner: exportRunnerHooks}
+	rows = topStatusRows(snap)
+	require.Equal(t, "nothing to export", rows[0].value)
+	require.Equal(t, "never", rows[1].value)
+	require.Equal(t, "due, with the next event recorded", rows[2].value)
+	require.Equal(t, "up to date with origin", rows[3].value)
+
+	snap.remote = false
+	snap.schedule.Runner = exportRunnerNone
+	rows = topStatusRows(snap)
+	require.Equal(t, "off in read-only mode", rows[2].value)
+	require.Equal(t, "no export remote", rows[3].value)
+}
+
+func TestLoadTopSnapshot(t *testing.T) {
+	exportDir, _ := setupSquashRepo(t, 1)
+	db := testutil.NewTestDB(t)
+	now := time.Now()
+	testutil.SeedEvents(t, db, []store.RepoEvent{
+		{RepoID: "github.com/u/app", RepoPath: "/src/app", Commit: "a1", Branch: "main", Source: store.SourcePostCommit, Status: store.StatusPending, Timestamp: now},
+		{RepoID: "github.com/u/app", RepoPath: "/src/app", Commit: "a2", Branch: "main", Source: store.SourcePostCommit, Status: store.StatusExported, Timestamp: now.AddDate(0, 0, -3)},
+	})
+
+	snap, err := loadTopSnapshot(db, exportDir, now)
+	require.NoError(t, err)
+	require.Equal(t, 1, snap.events)
+	require.Equal(t, 1, snap.pending)
+	require.Len(t, snap.repos, 1)
+	require.Equal(t, "app", snap.repos[0].name)
+	require.Equal(t, 2, snap.repos[0].total)
+	require.True(t, snap.remote)
+	require.True(t, snap.pushed)
+	require.Zero(t, snap.unpushed)
+
+	// An export committed but not pushed yet
+	require.NoError(t, os.WriteFile(filepath.Join(exportDir, "commits.csv"), []byte("changed\n"), 0600))
+	require.NoError(t, commitExportChanges(exportDir, []string{"commits.csv"}))
+	snap, err = loadTopSnapshot(db, exportDir, now)
+	require.NoError(t, err)
+	require.Equal(t, 1, snap.unpushed)
+}
+
+func TestTopModel_Refresh(t *testing.T) {
+	loads := 0
+	m := newTopModel(func(now time.Time) (topSnapshot, error) {
+		loads++
+		return topSnapshot{at: now, events: loads}, nil
+	}, time.Second)
+
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = model.(topModel)
+	require.Contains(t, m.View(), "Loading")
+
+	model, cmd := m.Update(m.reload()())
+	m = model.(topModel)
+	require.NotNil(t, cmd, "a load schedules the next tick")
+	require.Equal(t, 1, m.snap.events)
+	require.Contains(t, m.View(), "No activity in the last 14 days")
+
+	// Refreshing by hand retires the running ticker
+	stale := topTickMsg{seq: m.seq}
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = model.(topModel)
+	model, cmd = m.Update(stale)
+	m = model.(topModel)
+	require.Nil(t, cmd)
+
+	// A failed read keeps the last snapshot on screen
+	m.load = func(time.Time) (topSnapshot, error) { return topSnapshot{}, errors.New("database is locked") }
+	model, _ = m.Update(m.reload()())
+	m = model.(topModel)
+	require.Equal(t, 1, m.snap.events)
+	require.Contains(t, m.View(), "database is locked")
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m = model.(topModel)
+	require.True(t, m.paused)
+	_, cmd = m.Update(topTickMsg{seq: m.seq})
+	require.Nil(t, cmd, "no reloads while paused")
+}
+
+func TestPrintTopSummary(t *testing.T) {
+	now := time.Date(2025, 3, 14, 18, 0, 0, 0, time.Local)
+	snap := summarizeTop(heatmapRollups(heatmapEvent("a", heatmapDay(2025, 3, 14))), now)
+	snap.schedule = exportSchedule{Due: true, Runner: exportRunnerDaemon}
+
+	var out strings.Builder
+	deps := Deps{
+		Printf:  func(format string, a ...any) (int, error) { return fmt.Fprintf(&out, format, a...) },
+		Println: func(a ...any) (int, error) { return fmt.Fprintln(&out, a...) },
+	}
+	printTopSummary(snap, deps)
+	require.Contains(t, out.String(), "Today: 1 event, +0 -0, in 1 repository")
+	require.Contains(t, out.String(), "  a: 1 event, 1 today")
+	require.Contains(t, out.String(), "Next export: due now")
+	require.Contains(t, out.String(), "Push: no export remote")
+}
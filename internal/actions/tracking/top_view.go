@@ -0,0 +1,193 @@
+package tracking
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/ui/components"
+	"github.com/footprint-tools/cli/internal/ui/text"
+)
+
+// topStatusRow is a line of the export block of fp top.
+type topStatusRow struct {
+	label string
+	value string
+	warn  bool
+}
+
+// topStatusRows describes the export backlog, schedule and push state of
+// snap, for the dashboard and its accessible summary alike.
+func topStatusRows(snap topSnapshot) []topStatusRow {
+	pending := topStatusRow{label: "Pending", value: "nothing to export"}
+	if snap.pending > 0 {
+		pending.value = fmt.Sprintf("%d %s", snap.pending, pluralize(snap.pending, "event", "events"))
+	}
+
+	last := topStatusRow{label: "Last export", value: "never"}
+	if s := snap.schedule.LastExport; s != nil {
+		last.value = fmt.Sprintf("%s (%s ago)", s.Local().Format("2006-01-02 15:04"), shortDuration(snap.at.Sub(*s)))
+	}
+
+	next := topStatusRow{label: "Next export"}
+	switch {
+	case snap.schedule.Runner == exportRunnerNone:
+		next.value = "off in read-only mode"
+	case snap.schedule.Due && snap.schedule.Runner == exportRunnerHooks:
+		next.value = "due, with the next event recorded"
+	case snap.schedule.Due:
+		next.value = "due now"
+	default:
+		next.value = fmt.Sprintf("%s (in %s)", snap.schedule.NextExport.Local().Format("15:04"), shortDuration(snap.schedule.NextExport.Sub(snap.at)))
+	}
+
+	push := topStatusRow{label: "Push"}
+	switch {
+	case !snap.remote:
+		push.value = "no export remote"
+	case !snap.pushed:
+		push.value, push.warn = "never pushed to "+primaryRemote, true
+	case snap.unpushed == 0:
+		push.value = "up to date with " + primaryRemote
+	default:
+		push.value = fmt.Sprintf("%d %s not pushed to %s", snap.unpushed, pluralize(snap.unpushed, "commit", "commits"), primaryRemote)
+		push.warn = true
+	}
+
+	return []topStatusRow{pending, last, next, push}
+}
+
+// View implements tea.Model
+func (m topModel) View() string {
+	if m.width == 0 || m.height == 0 {
+		return ""
+	}
+
+	footer := m.renderFooter()
+	lines := []string{m.renderHeader()}
+	if m.err != nil {
+		warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Warning))
+		lines = append(lines, warnStyle.Render(text.TruncateWithEllipsis(" "+m.err.Error(), m.width)))
+	}
+	lines = append(lines, "")
+
+	if m.loaded {
+		status := m.renderStatus()
+		rows := max(1, m.height-len(lines)-len(status)-lipgloss.Height(footer)-1)
+		lines = append(lines, m.renderRepos(rows)...)
+		lines = append(lines, "")
+		lines = append(lines, status...)
+	}
+
+	body := strings.Join(lines, "\n")
+	gap := max(0, m.height-lipgloss.Height(body)-lipgloss.Height(footer))
+	return body + strings.Repeat("\n", gap+1) + footer
+}
+
+func (m topModel) renderHeader() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.colors.Info))
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Muted))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Warning))
+
+	content := titleStyle.Render("fp top")
+	if !m.loaded {
+		content += mutedStyle.Render(" | Loading...")
+		return lipgloss.NewStyle().Width(m.width).Padding(0, 1).Render(content)
+	}
+
+	s := m.snap
+	content += mutedStyle.Render(" | ") + titleStyle.Render(s.at.Format("15:04:05")) +
+		mutedStyle.Render(" | Today: ") + titleStyle.Render(formatCount(s.events)) +
+		mutedStyle.Render(" "+pluralize(s.events, "event", "events")+", ") +
+		titleStyle.Render(fmt.Sprintf("+%d -%d", s.insertions, s.deletions)) +
+		mutedStyle.Render(" in ") + titleStyle.Render(formatCount(s.activeRepos)) +
+		mutedStyle.Render(" "+pluralize(s.activeRepos, "repo", "repos"))
+	if m.paused {
+		content += mutedStyle.Render(" | ") + warnStyle.Render("Paused")
+	}
+
+	return lipgloss.NewStyle().Width(m.width).Padding(0, 1).Render(content)
+}
+
+// renderRepos lists up to height lines of repositories with their
+// sparklines, the busiest first.
+func (m topModel) renderRepos(height int) []string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.colors.Header))
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Muted))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Info))
+	sparkStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Success))
+
+	const countWidth = 7
+	nameWidth := max(8, min(32, m.width-2-2-topDays-2*countWidth-1))
+
+	header := " " + padRight("REPOS", nameWidth+2) + "  " + padRight(fmt.Sprintf("%dd", topDays), topDays) +
+		padLeft("today", countWidth) + padLeft(fmt.Sprintf("%dd", topDays), countWidth)
+	lines := []string{headerStyle.Render(header)}
+
+	if len(m.snap.repos) == 0 {
+		return append(lines, mutedStyle.Render(fmt.Sprintf("   No activity in the last %d days", topDays)))
+	}
+
+	repos := m.snap.repos
+	hidden := 0
+	if visible := max(1, height-1); len(repos) > visible {
+		visible = max(1, visible-1)
+		hidden = len(repos) - visible
+		repos = repos[:visible]
+	}
+
+	for _, r := range repos {
+		name := padRight(text.TruncateWithEllipsis(r.name, nameWidth), nameWidth)
+		today := padLeft(formatCount(r.today()), countWidth)
+		nameStyle := mutedStyle
+		if r.today() > 0 {
+			nameStyle = valueStyle
+		}
+		lines = append(lines, "   "+nameStyle.Render(name)+"  "+sparkStyle.Render(sparkline(r.days[:]))+
+			valueStyle.Render(today)+mutedStyle.Render(padLeft(formatCount(r.total), countWidth)))
+	}
+	if hidden > 0 {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("   ... and %d more", hidden)))
+	}
+	return lines
+}
+
+// renderStatus is the export block: backlog, schedule and push state.
+func (m topModel) renderStatus() []string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.colors.Header))
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Muted))
+	valueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Info))
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Warning))
+
+	lines := []string{headerStyle.Render(" EXPORT")}
+	for _, row := range topStatusRows(m.snap) {
+		value := valueStyle.Render(row.value)
+		if row.warn {
+			value = warnStyle.Render(row.value)
+		}
+		lines = append(lines, "   "+labelStyle.Render(padRight(row.label, 13))+value)
+	}
+	return lines
+}
+
+func (m topModel) renderFooter() string {
+	help := components.NewThemedHelp()
+	pause := "pause"
+	if m.paused {
+		pause = "resume"
+	}
+	bindings := []key.Binding{
+		key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+		key.NewBinding(key.WithKeys("p"), key.WithHelp("p", pause)),
+		key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh")),
+		key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+	}
+	footer := help.ShortHelpView(bindings)
+	if m.loaded {
+		mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(m.colors.Muted))
+		footer += mutedStyle.Render("  every " + m.interval.Round(time.Second).String())
+	}
+	return lipgloss.NewStyle().Width(m.width).Padding(0, 1).Render(footer)
+}
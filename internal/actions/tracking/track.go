@@ -0,0 +1,143 @@
+package tracking
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/hooks"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// Track installs hooks in one repository and registers it in the store.
+func Track(args []string, flags *dispatchers.ParsedFlags) error {
+	return track(args, flags, DefaultDeps(), true)
+}
+
+// Untrack removes fp hooks from one repository and drops it from the store.
+func Untrack(args []string, flags *dispatchers.ParsedFlags) error {
+	return track(args, flags, DefaultDeps(), false)
+}
+
+// track is the single-repository counterpart of fp repos install and
+// uninstall: it inspects the repository first, never prompts, and says
+// what it changed or what is in the way.
+func track(args []string, flags *dispatchers.ParsedFlags, deps Deps, install bool) error {
+	jsonOutput := flags.Has("--json")
+	dryRun := flags.Has("--dry-run")
+
+	target := "."
+	if len(args) > 0 && args[0] != "" {
+		target = args[0]
+	}
+	root, err := deps.RepoRoot(target)
+	if err != nil {
+		return usage.NotInGitRepo()
+	}
+
+	inspection := hooks.InspectRepo(root)
+	entry := RepoEntry{
+		Path:       root,
+		Name:       filepath.Base(root),
+		HasHooks:   inspection.FpInstalled,
+		Inspection: inspection,
+	}
+
+	var res batchResult
+	if install {
+		res = installRepoHooks(entry, dryRun, deps)
+	} else {
+		res = uninstallRepoHooks(entry, dryRun, deps)
+	}
+
+	if jsonOutput {
+		if err := output.JSON(deps.Println, res); err != nil {
+			return err
+		}
+	} else {
+		printTrackResult(res, inspection, deps)
+	}
+
+	switch res.Result {
+	case batchFailed:
+		return fmt.Errorf("%s: %s", root, res.Detail)
+	case batchBlocked:
+		return fmt.Errorf("hooks not installed: %s", strings.ToLower(inspection.Status.String()))
+	}
+	return nil
+}
+
+func printTrackResult(res batchResult, inspection hooks.RepoInspection, deps Deps) {
+	_, _ = deps.Println(style.Header(res.Path))
+
+	hooksPath, _ := git.RepoHooksPath(res.Path)
+	names := strings.Join(hooks.ManagedHooks, ", ")
+	switch res.Result {
+	case batchInstalled:
+		_, _ = deps.Printf("  %s installed %s\n", style.Success("✓"), names)
+		_, _ = deps.Printf("    in %s\n", hooksPath)
+		_, _ = deps.Printf("  %s registered in the store as %s\n", style.Success("✓"), trackedRepoID(res.Path, deps))
+	case batchWouldInstall:
+		_, _ = deps.Printf("  would install %s\n", names)
+		_, _ = deps.Printf("    in %s\n", hooksPath)
+		_, _ = deps.Printf("  would register it in the store as %s\n", trackedRepoID(res.Path, deps))
+	case batchAlreadyInstalled:
+		_, _ = deps.Println("  already tracked, nothing to do")
+	case batchRemoved:
+		_, _ = deps.Printf("  %s removed fp hooks from %s\n", style.Success("✓"), hooksPath)
+		_, _ = deps.Println("    hooks fp had backed up were put back")
+		_, _ = deps.Printf("  %s removed from the store; recorded events are kept\n", style.Success("✓"))
+	case batchWouldRemove:
+		_, _ = deps.Printf("  would remove fp hooks from %s and restore backed up hooks\n", hooksPath)
+		_, _ = deps.Println("  would remove it from the store; recorded events are kept")
+	case batchNotInstalled:
+		_, _ = deps.Println("  not tracked, nothing to do")
+	case batchBlocked:
+		_, _ = deps.Printf("  %s %s\n", style.Warning("!"), inspection.Status.String())
+		for _, line := range trackAdvice(inspection) {
+			_, _ = deps.Printf("    %s\n", line)
+		}
+	case batchFailed:
+		_, _ = deps.Printf("  %s %s\n", style.Error("×"), res.Detail)
+	}
+}
+
+// trackedRepoID is the id the store registers root under.
+func trackedRepoID(root string, deps Deps) string {
+	remoteURL, _ := deps.OriginURL(root)
+	id, err := deps.DeriveID(remoteURL, root)
+	if err != nil {
+		return root
+	}
+	return string(id)
+}
+
+// trackAdvice says how to track a repository whose hooks fp won't
+// overwrite on its own.
+func trackAdvice(inspection hooks.RepoInspection) []string {
+	switch inspection.Status {
+	case hooks.StatusManagedPreCommit, hooks.StatusManagedHusky, hooks.StatusManagedLefthook:
+		manager := strings.TrimPrefix(inspection.Status.String(), "Managed: ")
+		return []string{
+			manager + " owns this repository's hooks; add 'fp record <hook>' to",
+			"its hooks instead (see 'fp help hooks' for examples)",
+		}
+	case hooks.StatusUnmanagedHooks:
+		return []string{
+			"existing hooks: " + strings.Join(inspection.UnmanagedHooks, ", "),
+			"run 'fp setup --chain' to keep them running before fp, or",
+			"'fp setup --force' to back them up and replace them",
+		}
+	case hooks.StatusHooksPathOverride:
+		return []string{
+			"core.hooksPath is " + inspection.GlobalHooksPath + ", so git ignores .git/hooks;",
+			"run 'fp setup --global' to track every repository, or add",
+			"'fp record <hook>' to the hooks in that directory",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package tracking
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/repo"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func trackTestDeps(t *testing.T, out *strings.Builder) Deps {
+	deps := batchTestDeps(t, out)
+	deps.RepoRoot = git.RepoRoot
+	deps.OriginURL = git.OriginURL
+	deps.DeriveID = repo.DeriveID
+	return deps
+}
+
+func TestTrackAndUntrack(t *testing.T) {
+	root := batchTestRoot(t, "app")
+	dir := filepath.Join(root, "app")
+	var out strings.Builder
+	deps := trackTestDeps(t, &out)
+	flags := dispatchers.NewParsedFlags(nil)
+
+	require.NoError(t, track([]string{dir}, flags, deps, true))
+	require.Contains(t, out.String(), "installed post-commit")
+	require.Contains(t, out.String(), "registered in the store as local:")
+	require.FileExists(t, filepath.Join(dir, ".git", "hooks", "post-commit"))
+
+	s, err := store.New(deps.DBPath())
+	require.NoError(t, err)
+	tracked, err := s.ListRepos()
+	require.NoError(t, err)
+	require.Len(t, tracked, 1)
+	_ = s.Close()
+
+	out.Reset()
+	require.NoError(t, track([]string{dir}, flags, deps, true))
+	require.Contains(t, out.String(), "already tracked")
+
+	out.Reset()
+	require.NoError(t, track([]string{dir}, flags, deps, false))
+	require.Contains(t, out.String(), "removed fp hooks")
+	require.NoFileExists(t, filepath.Join(dir, ".git", "hooks", "post-commit"))
+
+	out.Reset()
+	require.NoError(t, track([]string{dir}, flags, deps, false))
+	require.Contains(t, out.String(), "not tracked")
+}
+
+func TestTrack_Blocked(t *testing.T) {
+	root := batchTestRoot(t, "app")
+	dir := filepath.Join(root, "app")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "hooks", "pre-commit"), []byte("#!/bin/sh\nmake lint\n"), 0755))
+	var out strings.Builder
+	deps := trackTestDeps(t, &out)
+
+	err := track([]string{dir}, dispatchers.NewParsedFlags(nil), deps, true)
+	require.ErrorContains(t, err, "unmanaged hooks")
+	require.Contains(t, out.String(), "existing hooks: pre-commit")
+	require.Contains(t, out.String(), "fp setup --chain")
+	require.NoFileExists(t, filepath.Join(dir, ".git", "hooks", "post-commit"))
+
+	// Hook managers get pointed at fp record instead
+	require.NoError(t, os.Remove(filepath.Join(dir, ".git", "hooks", "pre-commit")))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".husky"), 0755))
+	out.Reset()
+	err = track([]string{dir}, dispatchers.NewParsedFlags(nil), deps, true)
+	require.ErrorContains(t, err, "husky")
+	require.Contains(t, out.String(), "add 'fp record <hook>'")
+}
+
+func TestTrack_DryRunJSON(t *testing.T) {
+	root := batchTestRoot(t, "app")
+	dir := filepath.Join(root, "app")
+	var out strings.Builder
+	deps := trackTestDeps(t, &out)
+
+	flags := dispatchers.NewParsedFlags([]string{"--dry-run", "--json"})
+	require.NoError(t, track([]string{dir}, flags, deps, true))
+	require.Contains(t, out.String(), `"result": "would install"`)
+	require.NoFileExists(t, filepath.Join(dir, ".git", "hooks", "post-commit"))
+}
+
+func TestTrack_NotInGitRepo(t *testing.T) {
+	var out strings.Builder
+	deps := batchTestDeps(t, &out)
+	err := track([]string{t.TempDir()}, dispatchers.NewParsedFlags(nil), deps, true)
+	require.Error(t, err)
+}
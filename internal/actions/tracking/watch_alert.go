@@ -0,0 +1,95 @@
+package tracking
+
+import (
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/log"
+	"golang.org/x/term"
+)
+
+// watchAlert is what fp watch does when an event arrives while its window
+// is in the background, set by the watch_alert config key.
+type watchAlert int
+
+const (
+	alertNone watchAlert = iota
+	alertBell
+	alertFlash
+)
+
+// flashDuration is how long the header stays inverted for alertFlash.
+const flashDuration = 400 * time.Millisecond
+
+// flashEndMsg turns a flash off. seq is the flash it ends, so a flash
+// started while another was showing isn't cut short.
+type flashEndMsg struct{ seq int }
+
+func parseWatchAlert(value string) (watchAlert, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "none":
+		return alertNone, true
+	case "bell":
+		return alertBell, true
+	case "flash":
+		return alertFlash, true
+	}
+	return alertNone, false
+}
+
+// watchAlertSetting reads watch_alert, treating a bad value as none.
+func watchAlertSetting() watchAlert {
+	value, _ := config.Get("watch_alert")
+	alert, ok := parseWatchAlert(value)
+	if !ok {
+		log.Warn("watch: invalid watch_alert '%s': valid values are bell, flash, none", value)
+	}
+	return alert
+}
+
+// ringBell writes the terminal bell to w, stderr in practice: the bell
+// makes no mark on screen and stays out of piped output.
+func ringBell(w io.Writer) {
+	_, _ = io.WriteString(w, "\a")
+}
+
+// streamBell returns the bell the line stream rings for new events, or nil.
+// The stream can't tell whether its window has focus, so with watch_alert
+// = bell it rings for every batch of events, as long as stderr is a
+// terminal. Flashing needs the dashboard's header and only applies there.
+func streamBell(plain, jsonOutput bool) func() {
+	if plain || jsonOutput || watchAlertSetting() != alertBell {
+		return nil
+	}
+	if !term.IsTerminal(int(os.Stderr.Fd())) {
+		return nil
+	}
+	return func() { ringBell(os.Stderr) }
+}
+
+// alertCmd alerts for new events if the dashboard is in the background.
+func (m *watchModel) alertCmd() tea.Cmd {
+	if !m.blurred {
+		return nil
+	}
+	switch m.alert {
+	case alertBell:
+		out := m.bellOut
+		return func() tea.Msg {
+			ringBell(out)
+			return nil
+		}
+	case alertFlash:
+		m.flashSeq++
+		m.flashing = true
+		seq := m.flashSeq
+		return tea.Tick(flashDuration, func(time.Time) tea.Msg {
+			return flashEndMsg{seq: seq}
+		})
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package tracking
+
+import (
+	"bytes"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestParseWatchAlert(t *testing.T) {
+	for value, want := range map[string]watchAlert{
+		"":       alertNone,
+		"none":   alertNone,
+		"bell":   alertBell,
+		" Bell ": alertBell,
+		"flash":  alertFlash,
+	} {
+		got, ok := parseWatchAlert(value)
+		require.True(t, ok, value)
+		require.Equal(t, want, got, value)
+	}
+
+	_, ok := parseWatchAlert("beep")
+	require.False(t, ok)
+}
+
+func watchAlertModel(alert watchAlert, out *bytes.Buffer) watchModel {
+	m := newWatchModel(nil, 0)
+	m.alert = alert
+	m.bellOut = out
+	return m
+}
+
+func sendWatch(t *testing.T, m watchModel, msg tea.Msg) (watchModel, tea.Cmd) {
+	t.Helper()
+	next, cmd := m.Update(msg)
+	return next.(watchModel), cmd
+}
+
+func TestWatchModel_BellOnlyWhileBlurred(t *testing.T) {
+	var out bytes.Buffer
+	m := watchAlertModel(alertBell, &out)
+	events := newEventsMsg{{ID: 1, RepoPath: "/nonexistent", Commit: "abc"}}
+
+	m, cmd := sendWatch(t, m, events)
+	require.Nil(t, cmd, "no alert while focused")
+
+	m, _ = sendWatch(t, m, tea.BlurMsg{})
+	m, cmd = sendWatch(t, m, newEventsMsg{{ID: 2, RepoPath: "/nonexistent", Commit: "def"}})
+	require.NotNil(t, cmd)
+	cmd()
+	require.Equal(t, "\a", out.String())
+
+	// Polls that find nothing don't ring
+	_, cmd = sendWatch(t, m, newEventsMsg{})
+	require.Nil(t, cmd)
+
+	m, _ = sendWatch(t, m, tea.FocusMsg{})
+	_, cmd = sendWatch(t, m, newEventsMsg{{ID: 3, RepoPath: "/nonexistent", Commit: "ghi"}})
+	require.Nil(t, cmd)
+}
+
+func TestWatchModel_FlashInvertsHeaderBriefly(t *testing.T) {
+	var out bytes.Buffer
+	m := watchAlertModel(alertFlash, &out)
+	m.width = 80
+	events := func(id int64) newEventsMsg {
+		return newEventsMsg{store.RepoEvent{ID: id, RepoPath: "/nonexistent", Commit: "abc"}}
+	}
+
+	m, _ = sendWatch(t, m, tea.BlurMsg{})
+	m, cmd := sendWatch(t, m, events(1))
+	require.NotNil(t, cmd)
+	require.True(t, m.flashing)
+	require.Empty(t, out.String(), "flash doesn't ring")
+
+	// A second event restarts the flash; the first one's end is ignored
+	m, _ = sendWatch(t, m, events(2))
+	m, _ = sendWatch(t, m, flashEndMsg{seq: 1})
+	require.True(t, m.flashing)
+	m, _ = sendWatch(t, m, flashEndMsg{seq: 2})
+	require.False(t, m.flashing)
+
+	// Coming back to the window ends a flash at once
+	m, _ = sendWatch(t, m, events(3))
+	m, _ = sendWatch(t, m, tea.FocusMsg{})
+	require.False(t, m.flashing)
+}
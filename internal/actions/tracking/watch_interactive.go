@@ -8,6 +8,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/components"
 	"golang.org/x/term"
 )
 
@@ -22,18 +23,14 @@ func watchInteractive(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 		return errors.New("interactive watch requires an interactive terminal")
 	}
 
-	// Open database
-	db, err := deps.OpenDB(deps.DBPath())
+	// Open a read-only snapshot connection: polling keeps working while an
+	// export is writing, and the TUI never holds a lock the hooks wait on.
+	db, err := deps.OpenSnapshot(deps.DBPath())
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
 	defer func() { _ = db.Close() }()
 
-	// Ensure database is initialized
-	if err := deps.InitDB(db); err != nil {
-		return fmt.Errorf("failed to initialize database: %w", err)
-	}
-
 	// Get current max ID as starting point (we only want new events)
 	lastID, err := store.GetMaxEventID(db)
 	if err != nil {
@@ -45,9 +42,10 @@ func watchInteractive(_ []string, _ *dispatchers.ParsedFlags, deps Deps) error {
 
 	// Run program
 	p := tea.NewProgram(
-		m,
+		components.NewSizeGuard(components.NewHelpOverlay(m)),
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
+		tea.WithReportFocus(),
 	)
 
 	_, err = p.Run()
@@ -0,0 +1,92 @@
+package tracking
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/footprint-tools/cli/internal/ui/components"
+)
+
+// watchKeyMap is the keys of fp watch.
+type watchKeyMap struct {
+	components.ListKeyMap
+	Open      key.Binding
+	Pause     key.Binding
+	Backspace key.Binding
+	Sources   key.Binding
+	Clear     key.Binding
+	Escape    key.Binding
+	Close     key.Binding
+	Quit      key.Binding
+	Tab       key.Binding
+	Compact   key.Binding
+}
+
+var watchBindings = watchKeyMap{
+	ListKeyMap: components.NewListKeyMap(),
+	Open:       key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "open details")),
+	Pause:      key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pause or resume")),
+	Backspace:  key.NewBinding(key.WithKeys("backspace"), key.WithHelp("Backspace", "delete from the filter")),
+	Sources:    key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9", "0"), key.WithHelp("0-9", "show one source")),
+	Clear:      key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear filters")),
+	Escape:     key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "clear filter, close details or quit")),
+	Close:      key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("Esc/q", "close")),
+	Quit:       key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+	Tab:        key.NewBinding(key.WithKeys("tab"), key.WithHelp("Tab", "next panel")),
+	Compact:    key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("Ctrl+T", "compact / full rows")),
+}
+
+// HelpSections lists the keys of fp watch, the focused panel first.
+func (m watchModel) HelpSections() []components.HelpSection {
+	k := watchBindings
+	events := components.HelpSection{Title: "Events", Bindings: []key.Binding{
+		k.UpDown,
+		k.FirstLast,
+		k.Open,
+		k.Pause,
+		components.HelpKey("type", "filter by repo, branch or commit"),
+		k.Backspace,
+		k.Sources,
+		k.Clear,
+		k.Escape,
+		k.Quit,
+	}}
+	sidebar := components.HelpSection{Title: "Sidebar", Bindings: []key.Binding{
+		components.Describe(k.UpDown, "scroll"),
+		k.Sources,
+		components.HelpKey("click/wheel", "toggle / step a source"),
+		k.Clear,
+		components.Describe(k.Escape, "clear filters or back to events"),
+		k.Quit,
+	}}
+	drawer := components.HelpSection{Title: "Details", Bindings: []key.Binding{
+		components.Describe(k.UpDown, "scroll"),
+		components.Describe(k.Page, "scroll 10"),
+		components.Describe(k.FirstLast, "top / bottom"),
+		k.Close,
+	}}
+	general := components.HelpSection{Title: "Everywhere", Bindings: []key.Binding{
+		k.Tab,
+		components.HelpKey("click/wheel", "focus, select and scroll"),
+		k.Compact,
+		k.Help,
+		k.ForceQuit,
+	}}
+
+	sections := []components.HelpSection{events, sidebar}
+	switch {
+	case m.drawerOpen && m.focusedPanel == 2:
+		sections = []components.HelpSection{drawer, events, sidebar}
+	case m.focusedPanel == 1:
+		sections = []components.HelpSection{sidebar, events}
+	}
+	if m.drawerOpen && m.focusedPanel != 2 {
+		sections = append(sections, drawer)
+	}
+	return append(sections, general)
+}
+
+// CapturingInput is false: the watch filter takes keys as they are typed,
+// and ? opens the help rather than joining it.
+func (m watchModel) CapturingInput() bool {
+	return false
+}
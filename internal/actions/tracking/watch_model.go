@@ -2,6 +2,8 @@ package tracking
 
 import (
 	"database/sql"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -33,6 +35,8 @@ type newEventsMsg []store.RepoEvent
 type EventDetail struct {
 	Event store.RepoEvent
 	Meta  git.CommitMetadata
+	// Push is what a pre-push event sent, when it is known
+	Push []store.PushRef
 }
 
 // watchModel is the Bubble Tea model for interactive watch
@@ -52,6 +56,7 @@ type watchModel struct {
 	totalEvents  int
 	byType       map[string]int
 	byRepo       map[string]int
+	byDevice     map[string]int
 
 	// Adaptive polling
 	lastEventTime time.Time // When we last received new events
@@ -67,6 +72,7 @@ type watchModel struct {
 	filterQuery  string
 	filterSource store.Source // -1 means no filter
 	filterRepo   string       // "" means no filter
+	compact      compactMode
 
 	// Focus: 0=events, 1=sidebar, 2=drawer
 	focusedPanel    int
@@ -77,6 +83,14 @@ type watchModel struct {
 	drawerDetail   *EventDetail
 	drawerViewport components.ThemedViewport
 
+	// Alerts for events that arrive while the terminal is in the
+	// background (watch_alert)
+	alert    watchAlert
+	blurred  bool
+	flashing bool
+	flashSeq int
+	bellOut  io.Writer
+
 	// Styling
 	colors style.ColorConfig
 }
@@ -90,7 +104,10 @@ func newWatchModel(db *sql.DB, lastID int64) watchModel {
 		sessionStart:    time.Now(),
 		byType:          make(map[string]int),
 		byRepo:          make(map[string]int),
+		byDevice:        make(map[string]int),
 		filterSource:    -1, // No filter
+		alert:           watchAlertSetting(),
+		bellOut:         os.Stderr,
 		colors:          style.GetColors(),
 		sidebarViewport: components.NewThemedViewport(20, 20),
 		drawerViewport:  components.NewThemedViewport(40, 20),
@@ -146,6 +163,21 @@ func (m watchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.MouseMsg:
 		return m.handleMouse(msg)
 
+	case tea.FocusMsg:
+		m.blurred = false
+		m.flashing = false
+		return m, nil
+
+	case tea.BlurMsg:
+		m.blurred = true
+		return m, nil
+
+	case flashEndMsg:
+		if msg.seq == m.flashSeq {
+			m.flashing = false
+		}
+		return m, nil
+
 	case tickMsg:
 		if m.paused {
 			return m, tickCmd(pollSlow) // Slow poll when paused
@@ -158,7 +190,10 @@ func (m watchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case newEventsMsg:
 		m.addEvents([]store.RepoEvent(msg))
 		// Don't update drawer - cursor is adjusted in addEvents to keep same event selected
-		return m, nil
+		if len(msg) == 0 {
+			return m, nil
+		}
+		return m, m.alertCmd()
 	}
 
 	return m, nil
@@ -169,6 +204,9 @@ func (m watchModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyCtrlC:
 		return m, tea.Quit
+	case tea.KeyCtrlT:
+		m.compact.toggle(m.width)
+		return m, nil
 	case tea.KeyTab:
 		// Cycle focus: events -> sidebar -> (drawer if open) -> events
 		if m.drawerOpen {
@@ -266,7 +304,7 @@ func (m watchModel) handleSidebarKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.filterSource = -1
 		m.filterRepo = ""
 		return m, nil
-	case "1", "2", "3", "4", "5", "6", "7":
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9", "0":
 		return m.toggleSourceFilter(msg.String())
 	}
 	return m, nil
@@ -317,20 +355,14 @@ func (m watchModel) handleEventsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m watchModel) toggleSourceFilter(key string) (tea.Model, tea.Cmd) {
-	sourceMap := map[string]store.Source{
-		"1": store.SourcePostCommit,
-		"2": store.SourcePostRewrite,
-		"3": store.SourcePostCheckout,
-		"4": store.SourcePostMerge,
-		"5": store.SourcePrePush,
-		"6": store.SourceManual,
-		"7": store.SourceBackfill,
-	}
-	if source, ok := sourceMap[key]; ok {
-		if m.filterSource == source {
+	for _, sf := range sourceFilters(m.colors) {
+		if sf.key != key {
+			continue
+		}
+		if m.filterSource == sf.source {
 			m.filterSource = -1
 		} else {
-			m.filterSource = source
+			m.filterSource = sf.source
 		}
 		m.cursor = 0
 		m.eventScroll = 0
@@ -338,6 +370,13 @@ func (m watchModel) toggleSourceFilter(key string) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// stepSourceFilter moves the source filter delta lines down the sidebar.
+func (m *watchModel) stepSourceFilter(delta int) {
+	m.filterSource = stepSource(m.filterSource, delta)
+	m.cursor = 0
+	m.eventScroll = 0
+}
+
 func (m watchModel) handleRunes(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
 
@@ -368,7 +407,7 @@ func (m watchModel) handleRunes(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.filterSource = -1
 		m.filterRepo = ""
 		return m, nil
-	case "1", "2", "3", "4", "5", "6", "7":
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9", "0":
 		return m.toggleSourceFilter(key)
 	case "/":
 		// Start filter mode
@@ -399,8 +438,11 @@ func (m watchModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		// Determine which panel was clicked and set focus
 		switch {
 		case msg.X < statsWidth:
-			// Click in sidebar
+			// Click in sidebar; on a source, toggle filtering by it
 			m.focusedPanel = 1
+			if key, ok := sourceKeyAt(msg.Y, m.sidebarScrollPos()); ok {
+				return m.toggleSourceFilter(key)
+			}
 		case msg.X < drawerStart:
 			// Click in events area
 			m.focusedPanel = 0
@@ -423,6 +465,10 @@ func (m watchModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		// Scroll based on mouse position
 		switch {
 		case msg.X < statsWidth:
+			if _, ok := sourceKeyAt(msg.Y, m.sidebarScrollPos()); ok {
+				m.stepSourceFilter(-1)
+				break
+			}
 			m.sidebarViewport.LineUp(1)
 		case msg.X < drawerStart:
 			m.moveCursor(-1)
@@ -434,6 +480,10 @@ func (m watchModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		// Scroll based on mouse position
 		switch {
 		case msg.X < statsWidth:
+			if _, ok := sourceKeyAt(msg.Y, m.sidebarScrollPos()); ok {
+				m.stepSourceFilter(1)
+				break
+			}
 			m.sidebarViewport.LineDown(1)
 		case msg.X < drawerStart:
 			m.moveCursor(1)
@@ -480,6 +530,8 @@ func (m *watchModel) addEvents(events []store.RepoEvent) {
 
 	// Count how many events we'll actually add (to adjust cursor)
 	eventsAdded := 0
+	metas := newCommitMetaReader(m.db)
+	metas.Prefetch(events)
 
 	for _, e := range events {
 		// Update lastID
@@ -492,11 +544,11 @@ func (m *watchModel) addEvents(events []store.RepoEvent) {
 		m.byType["commit"]++ // For now all events are commits
 		repoName := filepath.Base(e.RepoPath)
 		m.byRepo[repoName]++
+		m.byDevice[deviceLabel(e)]++
 
-		// Fetch and cache commit metadata
+		// Fetch and cache commit metadata, stored by fp record
 		if _, exists := m.commitMeta[e.Commit]; !exists {
-			meta := git.GetCommitMetadata(e.RepoPath, e.Commit)
-			m.commitMeta[e.Commit] = meta
+			m.commitMeta[e.Commit] = metas.Of(e)
 		}
 
 		// Add to buffer (prepend for newest-first)
@@ -599,6 +651,7 @@ func (m watchModel) matchesQuery(e store.RepoEvent, query string) bool {
 	return strings.Contains(repoName, query) ||
 		strings.Contains(strings.ToLower(e.Branch), query) ||
 		strings.Contains(strings.ToLower(e.Commit), query) ||
+		strings.Contains(strings.ToLower(e.Cwd), query) ||
 		strings.Contains(strings.ToLower(m.getCommitMessage(e.Commit)), query)
 }
 
@@ -0,0 +1,239 @@
+package tracking
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/output"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// workspacePollInterval is how often fp watch --root rescans its root.
+// Walking a tree of repositories costs far more than reading new events, so
+// it runs much less often than the event poll.
+const workspacePollInterval = 5 * time.Second
+
+// Kinds of workspace signals.
+const (
+	signalNewRepo      = "new-repo"
+	signalBranchCreate = "branch-create"
+	signalCommit       = "commit"
+)
+
+// workspaceSignal is something fp watch --root noticed on disk rather than
+// read from the database: a repository appearing under the root, or a
+// branch created or moved in a repository without fp hooks.
+type workspaceSignal struct {
+	Timestamp time.Time
+	Kind      string
+	RepoPath  string
+	Branch    string
+	Commit    string
+	Tracked   bool
+}
+
+// workspaceRepo is what the last scan saw of one repository.
+type workspaceRepo struct {
+	tracked bool
+	tips    map[string]string
+}
+
+// workspaceWatcher compares successive scans of a directory tree.
+type workspaceWatcher struct {
+	root  string
+	depth int
+	now   func() time.Time
+	repos map[string]workspaceRepo
+}
+
+// newWorkspaceWatcher scans root once, so only what changes afterwards is
+// reported.
+func newWorkspaceWatcher(root string, depth int, now func() time.Time) (*workspaceWatcher, error) {
+	w := &workspaceWatcher{root: root, depth: depth, now: now}
+	if _, err := w.poll(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// untracked returns the repositories under the root without fp hooks, as
+// of the last scan.
+func (w *workspaceWatcher) untracked() []string {
+	var paths []string
+	for path, repo := range w.repos {
+		if !repo.tracked {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// workspaceScan is the outcome of one background rescan.
+type workspaceScan struct {
+	signals []workspaceSignal
+	err     error
+}
+
+// pollAsync runs poll in a goroutine and delivers its outcome on the
+// returned channel, so a slow walk doesn't hold up the event stream. The
+// caller must wait for the outcome before starting another scan.
+func (w *workspaceWatcher) pollAsync() <-chan workspaceScan {
+	done := make(chan workspaceScan, 1)
+	go func() {
+		signals, err := w.poll()
+		done <- workspaceScan{signals: signals, err: err}
+	}()
+	return done
+}
+
+// poll rescans the root and returns what changed since the previous scan.
+// Branches and commits are only reported for repositories without fp
+// hooks: in the others, the hooks record them and they arrive as events.
+func (w *workspaceWatcher) poll() ([]workspaceSignal, error) {
+	found, err := scanForRepos(w.root, w.depth)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline := w.repos == nil
+	next := make(map[string]workspaceRepo, len(found))
+	var signals []workspaceSignal
+	for _, r := range found {
+		repo := workspaceRepo{tracked: repoHasFpHooks(r.Path)}
+		repo.tips, _ = git.BranchTips(r.Path)
+		next[r.Path] = repo
+		if baseline {
+			continue
+		}
+
+		prev, known := w.repos[r.Path]
+		if !known {
+			signals = append(signals, w.signal(signalNewRepo, r.Path, "", "", repo.tracked))
+			continue
+		}
+		if repo.tracked {
+			continue
+		}
+
+		branches := make([]string, 0, len(repo.tips))
+		for branch := range repo.tips {
+			branches = append(branches, branch)
+		}
+		sort.Strings(branches)
+		for _, branch := range branches {
+			commit := repo.tips[branch]
+			old, had := prev.tips[branch]
+			switch {
+			case !had:
+				signals = append(signals, w.signal(signalBranchCreate, r.Path, branch, commit, false))
+			case old != commit:
+				signals = append(signals, w.signal(signalCommit, r.Path, branch, commit, false))
+			}
+		}
+	}
+	w.repos = next
+	return signals, nil
+}
+
+func (w *workspaceWatcher) signal(kind, path, branch, commit string, tracked bool) workspaceSignal {
+	return workspaceSignal{
+		Timestamp: w.now(),
+		Kind:      kind,
+		RepoPath:  path,
+		Branch:    branch,
+		Commit:    commit,
+		Tracked:   tracked,
+	}
+}
+
+// repoHasFpHooks reports whether fp's hooks run in the repository, from
+// its own hooks directory or the global one.
+func repoHasFpHooks(path string) bool {
+	hooksPath, err := git.RepoHooksPath(path)
+	return err == nil && hasFpHooks(hooksPath)
+}
+
+// writeWorkspaceSignal prints one signal in the format fp watch streams
+// events in.
+func writeWorkspaceSignal(s workspaceSignal, jsonOutput, plain, oneline bool, println func(...any) (int, error)) error {
+	var err error
+	switch {
+	case jsonOutput:
+		err = output.JSONLine(println, struct {
+			Timestamp string `json:"timestamp"`
+			Signal    string `json:"signal"`
+			RepoPath  string `json:"repo_path"`
+			Branch    string `json:"branch,omitempty"`
+			Commit    string `json:"commit,omitempty"`
+			Tracked   bool   `json:"tracked"`
+		}{
+			Timestamp: s.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+			Signal:    s.Kind,
+			RepoPath:  s.RepoPath,
+			Branch:    s.Branch,
+			Commit:    s.Commit,
+			Tracked:   s.Tracked,
+		})
+	case plain:
+		_, err = println(formatWorkspaceSignalPlain(s))
+	default:
+		_, err = println(formatWorkspaceSignal(s, oneline))
+	}
+	return err
+}
+
+// formatWorkspaceSignal formats a signal like formatEvent formats events,
+// flagging activity fp isn't recording.
+func formatWorkspaceSignal(s workspaceSignal, oneline bool) string {
+	head := []string{style.Warning(s.Kind)}
+	if s.Commit != "" {
+		head = append(head, style.Header(fmt.Sprintf("%.7s", s.Commit)))
+	}
+	if s.Branch != "" {
+		head = append(head, s.Branch)
+	}
+	head = append(head, style.Muted(s.RepoPath))
+	if !s.Tracked {
+		head = append(head, style.Error("untracked"))
+	}
+	if oneline {
+		return strings.Join(head, " ")
+	}
+
+	lines := []string{strings.Join(head, " "), style.Muted(s.Timestamp.Local().Format("2006-01-02 15:04:05"))}
+	if !s.Tracked {
+		lines = append(lines, fmt.Sprintf("fp isn't recording this repository: run %s", style.Info("fp setup "+shellArg(s.RepoPath))))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// formatWorkspaceSignalPlain lays a signal out like formatEventPlain, with
+// the repository's path in place of its id and whether fp records it last.
+func formatWorkspaceSignalPlain(s workspaceSignal) string {
+	tracked := "untracked"
+	if s.Tracked {
+		tracked = "tracked"
+	}
+	return strings.Join([]string{
+		s.Timestamp.UTC().Format(time.RFC3339),
+		s.Kind,
+		s.Commit,
+		s.RepoPath,
+		s.Branch,
+		tracked,
+	}, "\t")
+}
+
+// workspaceRoot resolves the --root directory for fp watch.
+func workspaceRoot(root string) (string, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %s: %w", root, err)
+	}
+	return abs, nil
+}
@@ -0,0 +1,82 @@
+package tracking
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/hooks"
+)
+
+func TestWorkspaceWatcher(t *testing.T) {
+	root := batchTestRoot(t, "tracked", "untracked")
+	tracked := filepath.Join(root, "tracked")
+	untracked := filepath.Join(root, "untracked")
+	runGit(t, tracked, "commit", "-q", "--allow-empty", "-m", "first")
+	runGit(t, untracked, "commit", "-q", "--allow-empty", "-m", "first")
+	require.NoError(t, hooks.Install(filepath.Join(tracked, ".git", "hooks")))
+
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	w, err := newWorkspaceWatcher(root, 25, func() time.Time { return now })
+	require.NoError(t, err)
+	require.Equal(t, []string{untracked}, w.untracked())
+
+	signals, err := w.poll()
+	require.NoError(t, err)
+	require.Empty(t, signals, "nothing changed since the first scan")
+
+	runGit(t, untracked, "branch", "feature")
+	feature := runGit(t, untracked, "rev-parse", "feature")
+	runGit(t, untracked, "commit", "-q", "--allow-empty", "-m", "second")
+	head := runGit(t, untracked, "rev-parse", "HEAD")
+	current := runGit(t, untracked, "branch", "--show-current")
+	runGit(t, tracked, "branch", "ignored")
+	out, err := exec.Command("git", "init", "-q", filepath.Join(root, "fresh")).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	signals, err = w.poll()
+	require.NoError(t, err)
+	require.Equal(t, []workspaceSignal{
+		{Timestamp: now, Kind: signalNewRepo, RepoPath: filepath.Join(root, "fresh")},
+		{Timestamp: now, Kind: signalBranchCreate, RepoPath: untracked, Branch: "feature", Commit: feature},
+		{Timestamp: now, Kind: signalCommit, RepoPath: untracked, Branch: current, Commit: head},
+	}, signals, "branches in repos with fp hooks arrive as events instead")
+}
+
+func TestWorkspaceWatcher_PollAsync(t *testing.T) {
+	root := batchTestRoot(t, "app")
+	w, err := newWorkspaceWatcher(root, 25, time.Now)
+	require.NoError(t, err)
+
+	out, err := exec.Command("git", "init", "-q", filepath.Join(root, "fresh")).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	scan := <-w.pollAsync()
+	require.NoError(t, scan.err)
+	require.Len(t, scan.signals, 1)
+	require.Equal(t, signalNewRepo, scan.signals[0].Kind)
+	require.Equal(t, filepath.Join(root, "fresh"), scan.signals[0].RepoPath)
+}
+
+func TestFormatWorkspaceSignal(t *testing.T) {
+	s := workspaceSignal{
+		Timestamp: time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+		Kind:      signalBranchCreate,
+		RepoPath:  "/code/app",
+		Branch:    "feature",
+		Commit:    "abcdef0123456789",
+	}
+
+	require.Equal(t, "2026-03-02T10:00:00Z\tbranch-create\tabcdef0123456789\t/code/app\tfeature\tuntracked", formatWorkspaceSignalPlain(s))
+
+	line := formatWorkspaceSignal(s, true)
+	require.Contains(t, line, "abcdef0")
+	require.Contains(t, line, "untracked")
+	require.Contains(t, formatWorkspaceSignal(s, false), "fp setup /code/app")
+
+	s.Tracked = true
+	require.NotContains(t, formatWorkspaceSignal(s, false), "fp setup")
+}
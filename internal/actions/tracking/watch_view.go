@@ -3,7 +3,6 @@ package tracking
 import (
 	"fmt"
 	"path/filepath"
-	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -15,20 +14,15 @@ import (
 )
 
 // View implements tea.Model
-func (m watchModel) View() string {
-	if m.width == 0 || m.height == 0 {
-		return "Loading..."
-	}
-
-	// Calculate dimensions
+// mainHeight is the height of the panels, between header and footer.
+func (m watchModel) mainHeight() int {
 	headerHeight := 3
 	footerHeight := 2
-	mainHeight := m.height - headerHeight - footerHeight
-	if mainHeight < 1 {
-		mainHeight = 1 // Ensure minimum height to prevent layout issues
-	}
+	return max(1, m.height-headerHeight-footerHeight) // at least 1 to keep the layout valid
+}
 
-	// Create layout with drawer support
+// newLayout creates the layout with drawer support.
+func (m watchModel) newLayout() *splitpanel.Layout {
 	cfg := splitpanel.Config{
 		SidebarWidthPercent: 0.20,
 		SidebarMinWidth:     18,
@@ -39,6 +33,21 @@ func (m watchModel) View() string {
 	layout := splitpanel.NewLayout(m.width, cfg, m.colors)
 	layout.SetFocusedPanel(m.focusedPanel)
 	layout.SetDrawerOpen(m.drawerOpen)
+	return layout
+}
+
+// sidebarScrollPos is how far the sidebar is scrolled as drawn.
+func (m watchModel) sidebarScrollPos() int {
+	return m.buildStatsPanel(m.newLayout(), m.mainHeight()).ScrollPos
+}
+
+func (m watchModel) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading..."
+	}
+
+	mainHeight := m.mainHeight()
+	layout := m.newLayout()
 
 	// Build panels
 	statsPanel := m.buildStatsPanel(layout, mainHeight)
@@ -112,7 +121,8 @@ func (m watchModel) renderHeader() string {
 
 	headerStyle := lipgloss.NewStyle().
 		Width(m.width).
-		Padding(0, 1)
+		Padding(0, 1).
+		Reverse(m.flashing)
 
 	return headerStyle.Render(headerContent)
 }
@@ -144,22 +154,7 @@ func (m *watchModel) buildStatsPanel(layout *splitpanel.Layout, height int) spli
 	lines = append(lines, headerStyle.Render("BY SOURCE"))
 	lines = append(lines, "")
 
-	sourceFilters := []struct {
-		key    string
-		source store.Source
-		name   string
-		color  string
-	}{
-		{"1", store.SourcePostCommit, "POST-COMMIT", colors.Color1},
-		{"2", store.SourcePostRewrite, "POST-REWRITE", colors.Color2},
-		{"3", store.SourcePostCheckout, "POST-CHECKOUT", colors.Color3},
-		{"4", store.SourcePostMerge, "POST-MERGE", colors.Color4},
-		{"5", store.SourcePrePush, "PRE-PUSH", colors.Color5},
-		{"6", store.SourceManual, "MANUAL", colors.Color7},
-		{"7", store.SourceBackfill, "BACKFILL", colors.Color6},
-	}
-
-	for _, sf := range sourceFilters {
+	for _, sf := range sourceFilters(colors) {
 		count := m.countBySource(sf.source)
 		indicator := "  "
 		if m.filterSource == sf.source {
@@ -180,21 +175,7 @@ func (m *watchModel) buildStatsPanel(layout *splitpanel.Layout, height int) spli
 		lines = append(lines, headerStyle.Render("BY REPO"))
 		lines = append(lines, "")
 
-		// Sort repos by count (descending), then by name (ascending) for stability
-		type repoCount struct {
-			name  string
-			count int
-		}
-		repos := make([]repoCount, 0, len(m.byRepo))
-		for name, count := range m.byRepo {
-			repos = append(repos, repoCount{name, count})
-		}
-		sort.Slice(repos, func(i, j int) bool {
-			if repos[i].count != repos[j].count {
-				return repos[i].count > repos[j].count
-			}
-			return repos[i].name < repos[j].name
-		})
+		repos := sortedCounts(m.byRepo)
 
 		// Show top repos - name and count on same line
 		width := layout.SidebarContentWidth()
@@ -213,6 +194,13 @@ func (m *watchModel) buildStatsPanel(layout *splitpanel.Layout, height int) spli
 		}
 	}
 
+	if len(m.byDevice) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, headerStyle.Render("BY DEVICE"))
+		lines = append(lines, "")
+		lines = append(lines, deviceBreakdownLines(m.byDevice, "", layout.SidebarContentWidth(), valueStyle, labelStyle)...)
+	}
+
 	// Set content and dimensions on viewport
 	totalLines := len(lines)
 	visibleHeight := height - 2
@@ -307,9 +295,13 @@ func (m watchModel) formatEventLine(event store.RepoEvent, width int, selected b
 	// Get cached metadata
 	meta := m.getCommitMeta(event.RepoPath, event.Commit)
 
+	if m.compact.active(m.width) {
+		return compactEventLine(padRight(format.Time(event.Timestamp), timeColumnWidth()), event.RepoPath, meta.Subject, width, selected, false, m.sourceColor(event.Source), colors)
+	}
+
 	// Column definitions (predefined widths)
+	colTime := timeColumnWidth() // "15:04" or "3:04 PM"
 	const (
-		colTime   = 5  // "15:04"
 		colSource = 13 // "POST-CHECKOUT"
 		colRepo   = 12 // repo name
 		colBranch = 12 // branch name
@@ -359,8 +351,8 @@ func (m watchModel) formatEventLine(event store.RepoEvent, width int, selected b
 	}
 
 	// Commit message - remaining space
-	// Fixed: 2 (prefix) + 5 + 1 + 13 + 1 + 12 + 1 + 12 + 1 + 7 + 1 + 6 + 1 + 6 + 1 + 4 + 1 = 75
-	fixedWidth := 75
+	// Fixed: 2 (prefix) + time + 1 + 13 + 1 + 12 + 1 + 12 + 1 + 7 + 1 + 6 + 1 + 6 + 1 + 4 + 1 = 70 + time
+	fixedWidth := 70 + colTime
 	msgWidth := width - fixedWidth
 	if msgWidth < 5 {
 		msgWidth = 5
@@ -417,7 +409,7 @@ func (m watchModel) formatEventLine(event store.RepoEvent, width int, selected b
 
 		// Same column structure as non-selected
 		line := prefix +
-			fmt.Sprintf("%-5s", timeStr) + " " +
+			padRight(timeStr, colTime) + " " +
 			fmt.Sprintf("%-13s", source) + " " +
 			fmt.Sprintf("%-12s", repoName) + " " +
 			fmt.Sprintf("%-12s", branch) + " " +
@@ -432,7 +424,7 @@ func (m watchModel) formatEventLine(event store.RepoEvent, width int, selected b
 
 	// Normal row - columns: time | source | repo | branch | commit | +add | -del | files | message
 	line := prefix +
-		timeStyle.Render(fmt.Sprintf("%-5s", timeStr)) + " " +
+		timeStyle.Render(padRight(timeStr, colTime)) + " " +
 		sourceStyle.Render(fmt.Sprintf("%-13s", source)) + " " +
 		repoStyle.Render(fmt.Sprintf("%-12s", repoName)) + " " +
 		branchStyle.Render(fmt.Sprintf("%-12s", branch)) + " " +
@@ -463,6 +455,12 @@ func (m watchModel) sourceColor(source store.Source) lipgloss.Color {
 		return lipgloss.Color(colors.Color6)
 	case store.SourceManual:
 		return lipgloss.Color(colors.Color7)
+	case store.SourceBranchCreate:
+		return lipgloss.Color(colors.Color8)
+	case store.SourceBranchDelete:
+		return lipgloss.Color(colors.Color9)
+	case store.SourceStash:
+		return lipgloss.Color(colors.Color10)
 	default:
 		return lipgloss.Color(colors.Muted)
 	}
@@ -485,6 +483,12 @@ func sourceName(source store.Source) string {
 		return "MANUAL"
 	case store.SourceBackfill:
 		return "BACKFILL"
+	case store.SourceBranchCreate:
+		return "BRANCH-CREATE"
+	case store.SourceBranchDelete:
+		return "BRANCH-DELETE"
+	case store.SourceStash:
+		return "STASH"
 	default:
 		return "UNKNOWN"
 	}
@@ -616,6 +620,11 @@ func (m *watchModel) buildDrawerPanel(layout *splitpanel.Layout, height int) spl
 		// Path (clickable)
 		addClickable("Path:    ", event.RepoPath)
 
+		// Directory the command ran in (clickable)
+		if event.Cwd != "" {
+			addClickable("Dir:     ", event.Cwd)
+		}
+
 		// ID (clickable)
 		addClickable("ID:      ", event.RepoID)
 
@@ -708,7 +717,7 @@ func (m watchModel) renderFooter() string {
 			tabBinding,
 			key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
 			key.NewBinding(key.WithKeys("j", "down"), key.WithHelp("j/↓", "scroll")),
-			key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7"), key.WithHelp("1-7", "filter")),
+			key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9", "0"), key.WithHelp("0-9", "filter")),
 			key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear")),
 		}
 	default:
@@ -719,10 +728,11 @@ func (m watchModel) renderFooter() string {
 			key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pause")),
 			key.NewBinding(key.WithKeys("j", "k"), key.WithHelp("jk", "nav")),
 			key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "detail")),
-			key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7"), key.WithHelp("1-7", "source")),
+			key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9", "0"), key.WithHelp("0-9", "source")),
 			key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear")),
 		}
 	}
+	bindings = append(bindings, key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "keys")))
 
 	footerStyle := lipgloss.NewStyle().
 		Width(m.width).
@@ -0,0 +1,139 @@
+package update
+
+import (
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// platform describes the machine an update is downloaded for, which is not
+// always what runtime reports: an amd64 fp running under Rosetta is on an
+// arm64 Mac, and GOOS says nothing about the Linux C library.
+type platform struct {
+	OS      string
+	Arch    string
+	Musl    bool // Linux with musl libc (Alpine, Void)
+	Rosetta bool // amd64 process translated on Apple Silicon
+}
+
+func (p platform) String() string {
+	s := p.OS + "/" + p.Arch
+	switch {
+	case p.Rosetta:
+		s += " under Rosetta"
+	case p.Musl:
+		s += " (musl)"
+	}
+	return s
+}
+
+// detectPlatform inspects the running system.
+func detectPlatform() platform {
+	p := platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+	switch p.OS {
+	case "darwin":
+		if p.Arch == "amd64" {
+			out, err := exec.Command("sysctl", "-n", "sysctl.proc_translated").Output()
+			p.Rosetta = err == nil && strings.TrimSpace(string(out)) == "1"
+		}
+	case "linux":
+		// musl's dynamic loader is the one file every musl system has
+		matches, _ := filepath.Glob("/lib/ld-musl-*.so.1")
+		p.Musl = len(matches) > 0
+	}
+	return p
+}
+
+// assetChoice is a release asset picked for a platform, with why.
+type assetChoice struct {
+	Asset  githubAsset
+	Reason string
+}
+
+// assetCandidate is one acceptable build for a platform, best first.
+type assetCandidate struct {
+	os, arch, suffix string
+	reason           string
+}
+
+// assetCandidates lists the builds that run on p, in order of preference.
+func assetCandidates(p platform) []assetCandidate {
+	exact := assetCandidate{os: p.OS, arch: p.Arch, reason: "exact match"}
+
+	switch {
+	case p.OS == "darwin" && p.Rosetta:
+		return []assetCandidate{
+			{os: "darwin", arch: "arm64", reason: "Apple Silicon; installing the native build instead of the Rosetta one"},
+			{os: "darwin", arch: "amd64", reason: "no arm64 build; keeps running under Rosetta"},
+		}
+	case p.OS == "darwin" && p.Arch == "arm64":
+		return []assetCandidate{
+			exact,
+			{os: "darwin", arch: "amd64", reason: "no arm64 build; runs under Rosetta"},
+		}
+	case p.OS == "linux" && p.Musl:
+		return []assetCandidate{
+			{os: "linux", arch: p.Arch, suffix: "musl", reason: "musl build"},
+			{os: "linux", arch: p.Arch, reason: "no musl build; release binaries are statically linked"},
+		}
+	default:
+		return []assetCandidate{exact}
+	}
+}
+
+// archAliases are other spellings of GOARCH seen in asset names.
+var archAliases = map[string][]string{
+	"amd64": {"amd64", "x86_64"},
+	"arm64": {"arm64", "aarch64"},
+}
+
+// names returns the asset file names that provide c.
+func (c assetCandidate) names() []string {
+	arches := archAliases[c.arch]
+	if arches == nil {
+		arches = []string{c.arch}
+	}
+	var names []string
+	for _, arch := range arches {
+		base := "fp_" + c.os + "_" + arch
+		if c.suffix != "" {
+			names = append(names, base+"_"+c.suffix, base+"-"+c.suffix)
+		} else {
+			names = append(names, base)
+		}
+	}
+	for i, name := range names {
+		names[i] = name + archiveExt(c.os)
+	}
+	return names
+}
+
+// archiveExt is the archive type releases ship for goos: zip for Windows,
+// where tar isn't at hand, and gzipped tar everywhere else.
+func archiveExt(goos string) string {
+	if goos == "windows" {
+		return ".zip"
+	}
+	return ".tar.gz"
+}
+
+// selectAsset picks the best release asset for p. When none fits, it
+// returns the names that were looked for.
+func selectAsset(assets []githubAsset, p platform) (assetChoice, []string, bool) {
+	byName := make(map[string]githubAsset, len(assets))
+	for _, a := range assets {
+		byName[a.Name] = a
+	}
+
+	var tried []string
+	for _, c := range assetCandidates(p) {
+		for _, name := range c.names() {
+			if a, ok := byName[name]; ok {
+				return assetChoice{Asset: a, Reason: c.reason}, nil, true
+			}
+			tried = append(tried, name)
+		}
+	}
+	return assetChoice{}, tried, false
+}
@@ -0,0 +1,75 @@
+package update
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func assets(names ...string) []githubAsset {
+	out := make([]githubAsset, len(names))
+	for i, name := range names {
+		out[i] = githubAsset{Name: name, BrowserDownloadURL: "https://example.com/" + name}
+	}
+	return out
+}
+
+func TestSelectAsset(t *testing.T) {
+	release := assets("fp_darwin_arm64.tar.gz", "fp_darwin_amd64.tar.gz", "fp_linux_amd64.tar.gz", "fp_linux_arm64.tar.gz")
+
+	tests := []struct {
+		name     string
+		assets   []githubAsset
+		platform platform
+		want     string
+		reason   string
+	}{
+		{"exact match", release, platform{OS: "linux", Arch: "amd64"}, "fp_linux_amd64.tar.gz", "exact match"},
+		{"rosetta gets the native build", release, platform{OS: "darwin", Arch: "amd64", Rosetta: true}, "fp_darwin_arm64.tar.gz", "native"},
+		{"rosetta without arm64 build", assets("fp_darwin_amd64.tar.gz"), platform{OS: "darwin", Arch: "amd64", Rosetta: true}, "fp_darwin_amd64.tar.gz", "Rosetta"},
+		{"apple silicon falls back to amd64", assets("fp_darwin_amd64.tar.gz"), platform{OS: "darwin", Arch: "arm64"}, "fp_darwin_amd64.tar.gz", "Rosetta"},
+		{"musl prefers musl build", assets("fp_linux_amd64.tar.gz", "fp_linux_amd64_musl.tar.gz"), platform{OS: "linux", Arch: "amd64", Musl: true}, "fp_linux_amd64_musl.tar.gz", "musl build"},
+		{"musl falls back to static build", release, platform{OS: "linux", Arch: "arm64", Musl: true}, "fp_linux_arm64.tar.gz", "statically linked"},
+		{"arch alias", assets("fp_linux_x86_64.tar.gz"), platform{OS: "linux", Arch: "amd64"}, "fp_linux_x86_64.tar.gz", "exact match"},
+		{"windows uses zip", assets("fp_windows_amd64.tar.gz", "fp_windows_amd64.zip"), platform{OS: "windows", Arch: "amd64"}, "fp_windows_amd64.zip", "exact match"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			choice, _, ok := selectAsset(tt.assets, tt.platform)
+			require.True(t, ok)
+			require.Equal(t, tt.want, choice.Asset.Name)
+			require.Contains(t, choice.Reason, tt.reason)
+		})
+	}
+}
+
+func TestSelectAsset_NoMatchListsNames(t *testing.T) {
+	_, tried, ok := selectAsset(assets("fp_linux_amd64.tar.gz"), platform{OS: "freebsd", Arch: "riscv64"})
+	require.False(t, ok)
+	require.Equal(t, []string{"fp_freebsd_riscv64.tar.gz"}, tried)
+}
+
+func TestInstallFromRelease_ReportsChosenAsset(t *testing.T) {
+	releaseJSON := `{"tag_name": "v1.2.0", "assets": [{"name": "fp_darwin_amd64.tar.gz", "browser_download_url": "https://example.com/amd64"}]}`
+	client := &mockHTTPClient{
+		responses: map[string]*http.Response{
+			apiURL + "/releases/latest": newMockResponse(200, releaseJSON),
+		},
+	}
+
+	var stdout bytes.Buffer
+	deps := Deps{
+		Stdout:         &stdout,
+		Stderr:         &stdout,
+		HTTPClient:     client,
+		CurrentVersion: "v1.0.0",
+		ExecutablePath: func() (string, error) { return "/nonexistent/fp", nil },
+		Platform:       func() platform { return platform{OS: "darwin", Arch: "arm64"} },
+	}
+
+	_ = installFromRelease(deps, "")
+	require.Contains(t, stdout.String(), "Using fp_darwin_amd64.tar.gz for darwin/arm64 (no arm64 build; runs under Rosetta)")
+}
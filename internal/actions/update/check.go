@@ -1,16 +1,19 @@
 package update
 
 import (
-	"encoding/json"
+	"cmp"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/footprint-tools/cli/internal/app"
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/log"
 	"github.com/footprint-tools/cli/internal/store"
 	"github.com/footprint-tools/cli/internal/ui/style"
 )
@@ -34,9 +37,10 @@ type CheckResult struct {
 // CheckDependencies contains injectable dependencies for update checking
 type CheckDependencies struct {
 	CurrentVersion string
+	Channel        string
 	HTTPClient     HTTPClient
 	GetUpdateCache func() (store.UpdateCache, error)
-	SetUpdateCache func(lastCheck, latestVersion string) error
+	SetUpdateCache func(lastCheck, latestVersion, channel string) error
 	Now            func() time.Time
 	Stderr         io.Writer
 }
@@ -45,6 +49,7 @@ type CheckDependencies struct {
 func NewCheckDependencies() CheckDependencies {
 	return CheckDependencies{
 		CurrentVersion: app.Version,
+		Channel:        configuredChannel(),
 		HTTPClient:     &http.Client{Timeout: httpTimeout},
 		GetUpdateCache: func() (store.UpdateCache, error) {
 			s, err := store.New(store.DBPath())
@@ -54,19 +59,30 @@ func NewCheckDependencies() CheckDependencies {
 			defer func() { _ = s.Close() }()
 			return s.GetUpdateCache()
 		},
-		SetUpdateCache: func(lastCheck, latestVersion string) error {
-			s, err := store.New(store.DBPath())
-			if err != nil {
-				return err
-			}
-			defer func() { _ = s.Close() }()
-			return s.SetUpdateCache(lastCheck, latestVersion)
-		},
-		Now:    time.Now,
-		Stderr: os.Stderr,
+		SetUpdateCache: setUpdateCache,
+		Now:            time.Now,
+		Stderr:         os.Stderr,
 	}
 }
 
+// setUpdateCache records the latest version seen on channel, for the
+// update notice.
+func setUpdateCache(lastCheck, latestVersion, channel string) error {
+	s, err := store.New(store.DBPath())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = s.Close() }()
+	return s.SetUpdateCache(lastCheck, latestVersion, channel)
+}
+
+// configuredChannel returns the update_channel setting as written; it is
+// validated where it's used.
+func configuredChannel() string {
+	value, _ := config.Get("update_channel")
+	return value
+}
+
 // gitDescribeSuffix matches the suffix added by git describe: -{commits}-g{hash}
 var gitDescribeSuffix = regexp.MustCompile(`-\d+-g[a-f0-9]+$`)
 
@@ -77,6 +93,100 @@ func cleanVersion(v string) string {
 	return gitDescribeSuffix.ReplaceAllString(v, "")
 }
 
+// isDevBuild reports whether version is a build without a release
+// version, which update checks skip.
+func isDevBuild(version string) bool {
+	return version == "" || version == "dev"
+}
+
+// newerVersion reports whether latest is a later release than current,
+// comparing them as semantic versions: v1.2.0 is newer than v1.2.0-rc.1,
+// which is newer than v1.1.9. A tag that isn't a version is offered
+// whenever it differs from current.
+func newerVersion(latest, current string) bool {
+	if latest == "" {
+		return false
+	}
+	l, lok := parseSemver(latest)
+	c, cok := parseSemver(current)
+	if !lok || !cok {
+		return latest != current
+	}
+	return compareSemver(l, c) > 0
+}
+
+// semver is a parsed vMAJOR.MINOR.PATCH[-PRERELEASE] version; build
+// metadata is dropped.
+type semver struct {
+	core       [3]int
+	prerelease []string
+}
+
+func parseSemver(v string) (semver, bool) {
+	v = strings.TrimPrefix(v, "v")
+	v, _, _ = strings.Cut(v, "+")
+	core, pre, hasPre := strings.Cut(v, "-")
+
+	var s semver
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return semver{}, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return semver{}, false
+		}
+		s.core[i] = n
+	}
+	if hasPre {
+		if pre == "" {
+			return semver{}, false
+		}
+		s.prerelease = strings.Split(pre, ".")
+	}
+	return s, true
+}
+
+// compareSemver orders versions as semver.org does: a prerelease comes
+// before its release, and prerelease fields compare numerically when
+// both are numbers.
+func compareSemver(a, b semver) int {
+	for i := range a.core {
+		if c := cmp.Compare(a.core[i], b.core[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a.prerelease) == 0 && len(b.prerelease) == 0:
+		return 0
+	case len(a.prerelease) == 0:
+		return 1
+	case len(b.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.prerelease) && i < len(b.prerelease); i++ {
+		x, y := a.prerelease[i], b.prerelease[i]
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		var c int
+		switch {
+		case xerr == nil && yerr == nil:
+			c = cmp.Compare(xn, yn)
+		case xerr == nil:
+			c = -1
+		case yerr == nil:
+			c = 1
+		default:
+			c = strings.Compare(x, y)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a.prerelease), len(b.prerelease))
+}
+
 // CheckForUpdate checks if a newer version is available.
 // Uses cached result if checked recently (within CheckInterval).
 func CheckForUpdate() *CheckResult {
@@ -86,20 +196,26 @@ func CheckForUpdate() *CheckResult {
 // checkForUpdate is the internal implementation with dependency injection
 func checkForUpdate(deps CheckDependencies) *CheckResult {
 	currentVersion := deps.CurrentVersion
-	if currentVersion == "" || currentVersion == "dev" {
+	if isDevBuild(currentVersion) {
 		return &CheckResult{UpdateAvailable: false, CurrentVersion: currentVersion}
 	}
 
 	// Clean version for comparison (strip -dirty, etc.)
 	cleanCurrent := cleanVersion(currentVersion)
 
-	// Check if we should skip (checked recently)
+	channel, ok := parseChannel(deps.Channel)
+	if !ok {
+		log.Warn("update: invalid update_channel '%s': valid values are stable, prerelease", deps.Channel)
+		channel = channelStable
+	}
+
+	// Check if we should skip (checked recently on the same channel)
 	cache, err := deps.GetUpdateCache()
-	if err == nil && cache.LastCheck != "" {
+	if err == nil && cache.LastCheck != "" && cache.Channel == channel {
 		if t, err := time.Parse(time.RFC3339, cache.LastCheck); err == nil {
 			if deps.Now().Sub(t) < CheckInterval {
 				// Use cached version
-				if cache.LatestVersion != "" && cache.LatestVersion != cleanCurrent {
+				if newerVersion(cache.LatestVersion, cleanCurrent) {
 					return &CheckResult{
 						UpdateAvailable: true,
 						CurrentVersion:  currentVersion,
@@ -112,17 +228,17 @@ func checkForUpdate(deps CheckDependencies) *CheckResult {
 	}
 
 	// Fetch latest version from GitHub (with short timeout to not block)
-	latestVersion, err := fetchLatestVersionQuick(deps.HTTPClient)
+	latestVersion, err := fetchLatestVersionQuick(deps.HTTPClient, channel)
 	if err != nil {
 		// On error, don't block - just skip the check
 		return &CheckResult{UpdateAvailable: false, CurrentVersion: currentVersion}
 	}
 
 	// Cache the result
-	_ = deps.SetUpdateCache(deps.Now().Format(time.RFC3339), latestVersion)
+	_ = deps.SetUpdateCache(deps.Now().Format(time.RFC3339), latestVersion, channel)
 
 	// Compare versions (use cleaned current version)
-	updateAvailable := latestVersion != cleanCurrent && latestVersion != ""
+	updateAvailable := newerVersion(latestVersion, cleanCurrent)
 
 	return &CheckResult{
 		UpdateAvailable: updateAvailable,
@@ -131,32 +247,13 @@ func checkForUpdate(deps CheckDependencies) *CheckResult {
 	}
 }
 
-// fetchLatestVersionQuick fetches the latest release tag from GitHub with a short timeout
-func fetchLatestVersionQuick(client HTTPClient) (string, error) {
-	url := apiURL + "/releases/latest"
-
-	resp, err := client.Get(url)
-	if err != nil {
-		return "", err
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed to fetch latest version (status %d)", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
+// fetchLatestVersionQuick fetches the latest release tag on channel from
+// GitHub with a short timeout
+func fetchLatestVersionQuick(client HTTPClient, channel string) (string, error) {
+	release, err := latestRelease(client, channel)
 	if err != nil {
 		return "", err
 	}
-
-	var release struct {
-		TagName string `json:"tag_name"`
-	}
-	if err := json.Unmarshal(body, &release); err != nil {
-		return "", err
-	}
-
 	return release.TagName, nil
 }
 
@@ -195,6 +292,7 @@ func ShouldCheckUpdate(command string) bool {
 		"export":   true, // Can be automated
 		"update":   true, // Already updating
 		"backfill": true, // Long-running process
+		"lock":     true, // Would unlock the database to read the cache
 	}
 	return !skipCommands[command]
 }
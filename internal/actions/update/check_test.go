@@ -135,6 +135,7 @@ func TestCheckForUpdate_CachedVersionRecent(t *testing.T) {
 			return store.UpdateCache{
 				LastCheck:     now.Add(-1 * time.Hour).Format(time.RFC3339), // 1 hour ago
 				LatestVersion: "v1.1.0",
+				Channel:       "stable",
 			}, nil
 		},
 		Now: func() time.Time { return now },
@@ -155,6 +156,7 @@ func TestCheckForUpdate_CachedVersionSame(t *testing.T) {
 			return store.UpdateCache{
 				LastCheck:     now.Add(-1 * time.Hour).Format(time.RFC3339),
 				LatestVersion: "v1.0.0", // Same as current
+				Channel:       "stable",
 			}, nil
 		},
 		Now: func() time.Time { return now },
@@ -165,6 +167,38 @@ func TestCheckForUpdate_CachedVersionSame(t *testing.T) {
 	require.Equal(t, "v1.0.0", result.CurrentVersion)
 }
 
+func TestCheckForUpdate_CacheFromOtherChannel(t *testing.T) {
+	now := time.Now()
+	client := &mockHTTPClient{
+		responses: map[string]*http.Response{
+			apiURL + "/releases/latest": newMockResponse(200, `{"tag_name": "v1.0.0"}`),
+		},
+	}
+
+	var cachedChannel string
+	deps := CheckDependencies{
+		CurrentVersion: "v1.0.0",
+		HTTPClient:     client,
+		GetUpdateCache: func() (store.UpdateCache, error) {
+			return store.UpdateCache{
+				LastCheck:     now.Add(-1 * time.Hour).Format(time.RFC3339),
+				LatestVersion: "v1.1.0-rc.1",
+				Channel:       "prerelease",
+			}, nil
+		},
+		SetUpdateCache: func(lastCheck, latestVersion, channel string) error {
+			cachedChannel = channel
+			return nil
+		},
+		Now: func() time.Time { return now },
+	}
+
+	// Back on stable, the prerelease seen earlier isn't offered
+	result := checkForUpdate(deps)
+	require.False(t, result.UpdateAvailable)
+	require.Equal(t, "stable", cachedChannel)
+}
+
 func TestCheckForUpdate_CacheExpired(t *testing.T) {
 	now := time.Now()
 	releaseJSON := `{"tag_name": "v1.2.0"}`
@@ -183,9 +217,10 @@ func TestCheckForUpdate_CacheExpired(t *testing.T) {
 			return store.UpdateCache{
 				LastCheck:     now.Add(-25 * time.Hour).Format(time.RFC3339), // 25 hours ago (expired)
 				LatestVersion: "v1.1.0",
+				Channel:       "stable",
 			}, nil
 		},
-		SetUpdateCache: func(lastCheck, latestVersion string) error {
+		SetUpdateCache: func(lastCheck, latestVersion, channel string) error {
 			setCacheCalled = true
 			require.Equal(t, "v1.2.0", latestVersion)
 			return nil
@@ -200,6 +235,27 @@ func TestCheckForUpdate_CacheExpired(t *testing.T) {
 	require.True(t, setCacheCalled)
 }
 
+func TestCheckForUpdate_PrereleaseChannel(t *testing.T) {
+	client := &mockHTTPClient{
+		responses: map[string]*http.Response{
+			apiURL + "/releases?per_page=20": newMockResponse(200, `[{"tag_name": "v1.3.0-rc.1", "prerelease": true}]`),
+		},
+	}
+
+	deps := CheckDependencies{
+		CurrentVersion: "v1.2.0",
+		Channel:        "prerelease",
+		HTTPClient:     client,
+		GetUpdateCache: func() (store.UpdateCache, error) { return store.UpdateCache{}, nil },
+		SetUpdateCache: func(lastCheck, latestVersion, channel string) error { return nil },
+		Now:            time.Now,
+	}
+
+	result := checkForUpdate(deps)
+	require.True(t, result.UpdateAvailable)
+	require.Equal(t, "v1.3.0-rc.1", result.LatestVersion)
+}
+
 func TestCheckForUpdate_CacheError(t *testing.T) {
 	now := time.Now()
 	releaseJSON := `{"tag_name": "v1.2.0"}`
@@ -216,7 +272,7 @@ func TestCheckForUpdate_CacheError(t *testing.T) {
 		GetUpdateCache: func() (store.UpdateCache, error) {
 			return store.UpdateCache{}, errors.New("cache error")
 		},
-		SetUpdateCache: func(lastCheck, latestVersion string) error {
+		SetUpdateCache: func(lastCheck, latestVersion, channel string) error {
 			return nil
 		},
 		Now: func() time.Time { return now },
@@ -243,6 +299,7 @@ func TestCheckForUpdate_HTTPError(t *testing.T) {
 			return store.UpdateCache{
 				LastCheck:     now.Add(-25 * time.Hour).Format(time.RFC3339), // expired
 				LatestVersion: "",
+				Channel:       "stable",
 			}, nil
 		},
 		Now: func() time.Time { return now },
@@ -269,7 +326,7 @@ func TestCheckForUpdate_DirtyVersion(t *testing.T) {
 		GetUpdateCache: func() (store.UpdateCache, error) {
 			return store.UpdateCache{}, errors.New("no cache")
 		},
-		SetUpdateCache: func(lastCheck, latestVersion string) error {
+		SetUpdateCache: func(lastCheck, latestVersion, channel string) error {
 			return nil
 		},
 		Now: func() time.Time { return now },
@@ -296,7 +353,7 @@ func TestCheckForUpdate_GitDescribeVersion(t *testing.T) {
 		GetUpdateCache: func() (store.UpdateCache, error) {
 			return store.UpdateCache{}, errors.New("no cache")
 		},
-		SetUpdateCache: func(lastCheck, latestVersion string) error {
+		SetUpdateCache: func(lastCheck, latestVersion, channel string) error {
 			return nil
 		},
 		Now: func() time.Time { return now },
@@ -314,7 +371,7 @@ func TestFetchLatestVersionQuick_Success(t *testing.T) {
 		},
 	}
 
-	version, err := fetchLatestVersionQuick(client)
+	version, err := fetchLatestVersionQuick(client, channelStable)
 	require.NoError(t, err)
 	require.Equal(t, "v2.0.0", version)
 }
@@ -326,7 +383,7 @@ func TestFetchLatestVersionQuick_HTTPError(t *testing.T) {
 		},
 	}
 
-	_, err := fetchLatestVersionQuick(client)
+	_, err := fetchLatestVersionQuick(client, channelStable)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "network error")
 }
@@ -338,7 +395,7 @@ func TestFetchLatestVersionQuick_NotFound(t *testing.T) {
 		},
 	}
 
-	_, err := fetchLatestVersionQuick(client)
+	_, err := fetchLatestVersionQuick(client, channelStable)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "status 404")
 }
@@ -350,7 +407,7 @@ func TestFetchLatestVersionQuick_InvalidJSON(t *testing.T) {
 		},
 	}
 
-	_, err := fetchLatestVersionQuick(client)
+	_, err := fetchLatestVersionQuick(client, channelStable)
 	require.Error(t, err)
 }
 
@@ -364,7 +421,7 @@ func TestFetchLatestVersionQuick_ReadError(t *testing.T) {
 		},
 	}
 
-	_, err := fetchLatestVersionQuick(client)
+	_, err := fetchLatestVersionQuick(client, channelStable)
 	require.Error(t, err)
 }
 
@@ -384,6 +441,7 @@ func TestPrintUpdateNotice_NoUpdate(t *testing.T) {
 			return store.UpdateCache{
 				LastCheck:     time.Now().Add(-1 * time.Hour).Format(time.RFC3339),
 				LatestVersion: "v1.0.0", // Same version
+				Channel:       "stable",
 			}, nil
 		},
 		Now:    time.Now,
@@ -403,6 +461,7 @@ func TestPrintUpdateNotice_WithUpdate(t *testing.T) {
 			return store.UpdateCache{
 				LastCheck:     time.Now().Add(-1 * time.Hour).Format(time.RFC3339),
 				LatestVersion: "v1.1.0",
+				Channel:       "stable",
 			}, nil
 		},
 		Now:    time.Now,
@@ -443,7 +502,7 @@ func TestCheckForUpdate_AlreadyAtLatest(t *testing.T) {
 		GetUpdateCache: func() (store.UpdateCache, error) {
 			return store.UpdateCache{}, errors.New("no cache")
 		},
-		SetUpdateCache: func(lastCheck, latestVersion string) error {
+		SetUpdateCache: func(lastCheck, latestVersion, channel string) error {
 			return nil
 		},
 		Now: func() time.Time { return now },
@@ -472,9 +531,10 @@ func TestCheckForUpdate_InvalidCacheTime(t *testing.T) {
 			return store.UpdateCache{
 				LastCheck:     "invalid-time-format",
 				LatestVersion: "v1.0.5",
+				Channel:       "stable",
 			}, nil
 		},
-		SetUpdateCache: func(lastCheck, latestVersion string) error {
+		SetUpdateCache: func(lastCheck, latestVersion, channel string) error {
 			return nil
 		},
 		Now: func() time.Time { return now },
@@ -502,7 +562,7 @@ func TestCheckForUpdate_EmptyLatestVersion(t *testing.T) {
 		GetUpdateCache: func() (store.UpdateCache, error) {
 			return store.UpdateCache{}, errors.New("no cache")
 		},
-		SetUpdateCache: func(lastCheck, latestVersion string) error {
+		SetUpdateCache: func(lastCheck, latestVersion, channel string) error {
 			return nil
 		},
 		Now: func() time.Time { return now },
@@ -529,9 +589,10 @@ func TestCheckForUpdate_CachedEmptyLastCheck(t *testing.T) {
 			return store.UpdateCache{
 				LastCheck:     "", // Empty
 				LatestVersion: "v1.0.5",
+				Channel:       "stable",
 			}, nil
 		},
-		SetUpdateCache: func(lastCheck, latestVersion string) error {
+		SetUpdateCache: func(lastCheck, latestVersion, channel string) error {
 			return nil
 		},
 		Now: func() time.Time { return now },
@@ -552,6 +613,7 @@ func TestCheckForUpdate_CachedEmptyLatestVersion(t *testing.T) {
 			return store.UpdateCache{
 				LastCheck:     now.Add(-1 * time.Hour).Format(time.RFC3339),
 				LatestVersion: "", // Empty cached version
+				Channel:       "stable",
 			}, nil
 		},
 		Now: func() time.Time { return now },
@@ -582,7 +644,7 @@ func TestNewCheckDependencies_Closures(t *testing.T) {
 	_, _ = deps.GetUpdateCache()
 
 	// Test SetUpdateCache - will use real store
-	_ = deps.SetUpdateCache("2024-01-01T00:00:00Z", "v1.0.0")
+	_ = deps.SetUpdateCache("2024-01-01T00:00:00Z", "v1.0.0", "stable")
 
 	// Test Now
 	now := deps.Now()
@@ -598,3 +660,27 @@ func TestNewCheckDependencies_Closures(t *testing.T) {
 	// Test HTTPClient is set
 	require.NotNil(t, deps.HTTPClient)
 }
+
+func TestNewerVersion(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v1.1.0", "v1.0.0", true},
+		{"v1.0.0", "v1.0.0", false},
+		{"v1.0.0", "v1.1.0", false},
+		{"v1.10.0", "v1.9.0", true},
+		{"v1.2.0", "v1.2.0-rc.1", true},
+		{"v1.2.0-rc.1", "v1.2.0", false},
+		{"v1.2.0-rc.2", "v1.2.0-rc.1", true},
+		{"v1.2.0-rc.10", "v1.2.0-rc.9", true},
+		{"v1.2.0-rc.1", "v1.1.0", true},
+		{"", "v1.0.0", false},
+		{"nightly", "v1.0.0", true},
+	}
+	for _, tt := range tests {
+		if got := newerVersion(tt.latest, tt.current); got != tt.want {
+			t.Errorf("newerVersion(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"runtime"
+	"time"
 
 	"github.com/footprint-tools/cli/internal/app"
 )
@@ -14,8 +16,14 @@ type Deps struct {
 	Stderr         io.Writer
 	HTTPClient     HTTPClient
 	CurrentVersion string
+	Channel        string // update_channel: stable or prerelease
 	ExecutablePath func() (string, error)
 	RunCommand     func(name string, args ...string) error
+	RunCommandEnv  func(env []string, name string, args ...string) error // RunCommand with env added to the environment
+	CommandOutput  func(name string, args ...string) (string, error)
+	Platform       func() platform
+	SetUpdateCache func(lastCheck, latestVersion, channel string) error
+	Now            func() time.Time
 }
 
 type HTTPClient interface {
@@ -28,6 +36,7 @@ func DefaultDeps() Deps {
 		Stderr:         os.Stderr,
 		HTTPClient:     http.DefaultClient,
 		CurrentVersion: app.Version,
+		Channel:        configuredChannel(),
 		ExecutablePath: os.Executable,
 		RunCommand: func(name string, args ...string) error {
 			cmd := exec.Command(name, args...)
@@ -35,5 +44,28 @@ func DefaultDeps() Deps {
 			cmd.Stderr = os.Stderr
 			return cmd.Run()
 		},
+		RunCommandEnv: func(env []string, name string, args ...string) error {
+			cmd := exec.Command(name, args...)
+			cmd.Env = append(os.Environ(), env...)
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			return cmd.Run()
+		},
+		CommandOutput: func(name string, args ...string) (string, error) {
+			out, err := exec.Command(name, args...).Output()
+			return string(out), err
+		},
+		Platform:       detectPlatform,
+		SetUpdateCache: setUpdateCache,
+		Now:            time.Now,
+	}
+}
+
+// platform returns the machine to update for, defaulting to what the
+// runtime reports.
+func (d Deps) platform() platform {
+	if d.Platform == nil {
+		return platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
 	}
+	return d.Platform()
 }
@@ -2,17 +2,23 @@ package update
 
 import (
 	"archive/tar"
+	"archive/zip"
 	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/usage"
 )
 
 const (
@@ -21,6 +27,23 @@ const (
 	apiURL    = "https://api.github.com/repos/" + repoOwner + "/" + repoName
 )
 
+// Release channels for update_channel.
+const (
+	channelStable     = "stable"
+	channelPrerelease = "prerelease"
+)
+
+// parseChannel validates an update_channel value; empty means stable.
+func parseChannel(value string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", channelStable:
+		return channelStable, true
+	case channelPrerelease:
+		return channelPrerelease, true
+	}
+	return "", false
+}
+
 // ensureVersionPrefix adds "v" prefix if not present.
 func ensureVersionPrefix(version string) string {
 	if strings.HasPrefix(version, "v") {
@@ -30,8 +53,10 @@ func ensureVersionPrefix(version string) string {
 }
 
 type githubRelease struct {
-	TagName string        `json:"tag_name"`
-	Assets  []githubAsset `json:"assets"`
+	TagName    string        `json:"tag_name"`
+	Prerelease bool          `json:"prerelease"`
+	Draft      bool          `json:"draft"`
+	Assets     []githubAsset `json:"assets"`
 }
 
 type githubAsset struct {
@@ -52,6 +77,30 @@ func update(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 		targetVersion = args[0]
 	}
 
+	// --channel overrides update_channel for this run
+	if value := flags.String("--channel", ""); value != "" {
+		channel, ok := parseChannel(value)
+		if !ok {
+			return fmt.Errorf("fp: invalid --channel '%s': valid values are stable, prerelease", value)
+		}
+		deps.Channel = channel
+	}
+	channel, ok := parseChannel(deps.Channel)
+	if !ok {
+		return fmt.Errorf("fp: invalid update_channel '%s': valid values are stable, prerelease", deps.Channel)
+	}
+	deps.Channel = channel
+
+	if flags.Has("--check") {
+		if useTag {
+			return usage.ConflictingFlags("--check", "--tag")
+		}
+		if targetVersion != "" {
+			return fmt.Errorf("fp: --check looks up the latest release and takes no version")
+		}
+		return checkOnly(deps)
+	}
+
 	// If --tag flag is used, go straight to go install
 	if useTag {
 		if targetVersion == "" {
@@ -64,6 +113,39 @@ func update(args []string, flags *dispatchers.ParsedFlags, deps Deps) error {
 	return installFromRelease(deps, targetVersion)
 }
 
+// checkOnly reports whether a newer release is out on the channel without
+// installing it. An available update is returned as an error with exit
+// code 10, so scripts can tell it apart from failing to check.
+func checkOnly(deps Deps) error {
+	if isDevBuild(deps.CurrentVersion) {
+		_, _ = fmt.Fprintln(deps.Stdout, "This fp is a development build; it isn't checked for updates")
+		return nil
+	}
+
+	release, err := latestRelease(deps.HTTPClient, deps.Channel)
+	if err != nil {
+		return fmt.Errorf("fp: could not fetch latest release: %w", err)
+	}
+
+	// Refresh the cache behind the update notice, so it follows a channel
+	// change right away instead of at its next daily check
+	if deps.SetUpdateCache != nil && deps.Now != nil {
+		_ = deps.SetUpdateCache(deps.Now().Format(time.RFC3339), release.TagName, deps.Channel)
+	}
+
+	current := cleanVersion(deps.CurrentVersion)
+	if !newerVersion(release.TagName, current) {
+		if release.TagName == current {
+			_, _ = fmt.Fprintf(deps.Stdout, "fp %s is the latest %s release\n", current, deps.Channel)
+		} else {
+			_, _ = fmt.Fprintf(deps.Stdout, "fp %s is newer than the latest %s release (%s)\n", current, deps.Channel, release.TagName)
+		}
+		return nil
+	}
+	_, _ = fmt.Fprintf(deps.Stdout, "Update available: %s → %s (%s)\n", current, release.TagName, deps.Channel)
+	return usage.UpdateAvailable(release.TagName)
+}
+
 func installFromRelease(deps Deps, targetVersion string) error {
 	// Fetch release info
 	release, err := fetchRelease(deps, targetVersion)
@@ -81,20 +163,15 @@ func installFromRelease(deps Deps, targetVersion string) error {
 		return nil
 	}
 
-	// Find the right asset for this OS/arch
-	assetName := fmt.Sprintf("fp_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH)
-	var downloadURL string
-	for _, asset := range release.Assets {
-		if asset.Name == assetName {
-			downloadURL = asset.BrowserDownloadURL
-			break
-		}
-	}
-
-	if downloadURL == "" {
-		_, _ = fmt.Fprintf(deps.Stdout, "No binary for %s/%s, trying go install...\n", runtime.GOOS, runtime.GOARCH)
+	// Find the right asset for this machine
+	p := deps.platform()
+	choice, tried, ok := selectAsset(release.Assets, p)
+	if !ok {
+		_, _ = fmt.Fprintf(deps.Stdout, "No binary for %s in %s (looked for %s), trying go install...\n", p, release.TagName, strings.Join(tried, ", "))
 		return installFromSource(deps, release.TagName)
 	}
+	_, _ = fmt.Fprintf(deps.Stdout, "Using %s for %s (%s)\n", choice.Asset.Name, p, choice.Reason)
+	downloadURL := choice.Asset.BrowserDownloadURL
 
 	// Download and install
 	_, _ = fmt.Fprintf(deps.Stdout, "Downloading %s...\n", release.TagName)
@@ -107,30 +184,54 @@ func installFromRelease(deps Deps, targetVersion string) error {
 	return nil
 }
 
+// fetchRelease returns the given version's release, or the latest one on
+// the configured channel when version is empty.
 func fetchRelease(deps Deps, version string) (*githubRelease, error) {
-	var url string
 	if version == "" {
-		url = apiURL + "/releases/latest"
-	} else {
-		url = apiURL + "/releases/tags/" + ensureVersionPrefix(version)
+		return latestRelease(deps.HTTPClient, deps.Channel)
 	}
-
-	resp, err := deps.HTTPClient.Get(url)
-	if err != nil {
+	var release githubRelease
+	if err := getJSON(deps.HTTPClient, apiURL+"/releases/tags/"+ensureVersionPrefix(version), &release); err != nil {
 		return nil, err
 	}
-	defer func() { _ = resp.Body.Close() }()
+	return &release, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("release not found (status %d)", resp.StatusCode)
+// latestRelease returns the newest release on channel. /releases/latest
+// already leaves out prereleases and drafts, so only the prerelease channel
+// lists /releases, newest first, and takes the first one that isn't a draft.
+func latestRelease(client HTTPClient, channel string) (*githubRelease, error) {
+	if channel != channelPrerelease {
+		var release githubRelease
+		if err := getJSON(client, apiURL+"/releases/latest", &release); err != nil {
+			return nil, err
+		}
+		return &release, nil
 	}
 
-	var release githubRelease
-	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+	var releases []githubRelease
+	if err := getJSON(client, apiURL+"/releases?per_page=20", &releases); err != nil {
 		return nil, err
 	}
+	for i := range releases {
+		if !releases[i].Draft {
+			return &releases[i], nil
+		}
+	}
+	return nil, errors.New("release not found (no published releases)")
+}
 
-	return &release, nil
+func getJSON(client HTTPClient, url string, v any) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("release not found (status %d)", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
 }
 
 func downloadAndInstall(deps Deps, url string) error {
@@ -157,8 +258,12 @@ func downloadAndInstall(deps Deps, url string) error {
 		return fmt.Errorf("download failed (status %d)", resp.StatusCode)
 	}
 
-	// Create temp file for the archive
-	tmpArchive, err := os.CreateTemp("", "fp-update-*.tar.gz")
+	// Create temp file for the archive, named so extractBinary knows its type
+	ext := ".tar.gz"
+	if strings.HasSuffix(url, ".zip") {
+		ext = ".zip"
+	}
+	tmpArchive, err := os.CreateTemp("", "fp-update-*"+ext)
 	if err != nil {
 		return err
 	}
@@ -178,15 +283,8 @@ func downloadAndInstall(deps Deps, url string) error {
 	}
 	defer func() { _ = os.Remove(binary) }()
 
-	// Replace the current executable
-	// First, try to remove the old one (may fail if no write permission)
-	if err := os.Remove(execPath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("could not remove old binary (try with sudo): %w", err)
-	}
-
-	// Copy new binary to the executable path
-	if err := copyFile(binary, execPath); err != nil {
-		return fmt.Errorf("could not install new binary: %w", err)
+	if err := replaceExecutable(execPath, binary, deps.platform().OS); err != nil {
+		return err
 	}
 
 	// Make executable
@@ -197,7 +295,74 @@ func downloadAndInstall(deps Deps, url string) error {
 	return nil
 }
 
+// replaceExecutable puts binary in place of the fp at execPath. Windows
+// won't delete a running executable but does let it be renamed, so there
+// the old one is moved aside to fp.old.exe first, and RemoveOldBinary
+// deletes it on the next run.
+func replaceExecutable(execPath, binary, goos string) error {
+	if goos != "windows" {
+		// Remove the old one first (may fail if no write permission)
+		if err := os.Remove(execPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("could not remove old binary (try with sudo): %w", err)
+		}
+		if err := copyFile(binary, execPath); err != nil {
+			return fmt.Errorf("could not install new binary: %w", err)
+		}
+		return nil
+	}
+
+	oldPath := oldBinaryPath(execPath)
+	_ = os.Remove(oldPath) // left behind by an earlier update
+	if err := os.Rename(execPath, oldPath); err != nil {
+		return fmt.Errorf("could not move old binary aside (try from an administrator prompt): %w", err)
+	}
+	if err := copyFile(binary, execPath); err != nil {
+		_ = os.Remove(execPath)
+		_ = os.Rename(oldPath, execPath)
+		return fmt.Errorf("could not install new binary: %w", err)
+	}
+	return nil
+}
+
+// oldBinaryPath is where a replaced Windows executable waits to be
+// deleted: fp.exe becomes fp.old.exe.
+func oldBinaryPath(execPath string) string {
+	ext := filepath.Ext(execPath)
+	return strings.TrimSuffix(execPath, ext) + ".old" + ext
+}
+
+// RemoveOldBinary deletes the executable a Windows update moved aside,
+// once it is no longer running. Elsewhere it does nothing.
+func RemoveOldBinary() {
+	if runtime.GOOS != "windows" {
+		return
+	}
+	removeOldBinary(os.Executable)
+}
+
+func removeOldBinary(executable func() (string, error)) {
+	execPath, err := executable()
+	if err != nil {
+		return
+	}
+	if err := os.Remove(oldBinaryPath(execPath)); err != nil && !os.IsNotExist(err) {
+		log.Debug("update: could not remove the previous binary: %v", err)
+	}
+}
+
+// isBinaryName reports whether an archive entry is the fp executable.
+func isBinaryName(name string) bool {
+	base := path.Base(name)
+	return base == "fp" || base == "fp.exe"
+}
+
+// extractBinary extracts the fp executable from a downloaded release
+// archive, a .zip or a .tar.gz, into a temp file and returns its path.
 func extractBinary(archivePath string) (string, error) {
+	if strings.HasSuffix(archivePath, ".zip") {
+		return extractBinaryZip(archivePath)
+	}
+
 	f, err := os.Open(archivePath)
 	if err != nil {
 		return "", err
@@ -222,29 +387,56 @@ func extractBinary(archivePath string) (string, error) {
 		}
 
 		// Look for the 'fp' binary
-		if header.Typeflag == tar.TypeReg && (header.Name == "fp" || strings.HasSuffix(header.Name, "/fp")) {
-			tmpBinary, err := os.CreateTemp("", "fp-binary-*")
-			if err != nil {
-				return "", err
-			}
-
-			if _, err := io.Copy(tmpBinary, tr); err != nil {
-				_ = tmpBinary.Close()
-				_ = os.Remove(tmpBinary.Name())
-				return "", err
-			}
-			if err := tmpBinary.Close(); err != nil {
-				_ = os.Remove(tmpBinary.Name())
-				return "", err
-			}
-
-			return tmpBinary.Name(), nil
+		if header.Typeflag == tar.TypeReg && isBinaryName(header.Name) {
+			return writeTempBinary(tr)
+		}
+	}
+
+	return "", fmt.Errorf("binary not found in archive")
+}
+
+func extractBinaryZip(archivePath string) (string, error) {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = zr.Close() }()
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !isBinaryName(f.Name) {
+			continue
 		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		defer func() { _ = rc.Close() }()
+		return writeTempBinary(rc)
 	}
 
 	return "", fmt.Errorf("binary not found in archive")
 }
 
+// writeTempBinary copies an extracted executable into a temp file.
+func writeTempBinary(r io.Reader) (string, error) {
+	tmpBinary, err := os.CreateTemp("", "fp-binary-*")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(tmpBinary, r); err != nil {
+		_ = tmpBinary.Close()
+		_ = os.Remove(tmpBinary.Name())
+		return "", err
+	}
+	if err := tmpBinary.Close(); err != nil {
+		_ = os.Remove(tmpBinary.Name())
+		return "", err
+	}
+
+	return tmpBinary.Name(), nil
+}
+
 func copyFile(src, dst string) error {
 	in, err := os.Open(src)
 	if err != nil {
@@ -265,6 +457,11 @@ func copyFile(src, dst string) error {
 	return out.Close()
 }
 
+// installFromSource builds version with go install and puts it in place of
+// the running fp. The build goes to a temp GOBIN with the module proxy and
+// checksum database pinned (see goInstallEnv), and the binary has to report
+// the requested version both before and after it replaces the old one;
+// otherwise the old binary is put back.
 func installFromSource(deps Deps, version string) error {
 	// Check if Go is available
 	if err := deps.RunCommand("go", "version"); err != nil {
@@ -273,15 +470,134 @@ func installFromSource(deps Deps, version string) error {
 
 	version = ensureVersionPrefix(version)
 
+	execPath, err := deps.ExecutablePath()
+	if err != nil {
+		return fmt.Errorf("could not determine executable path: %w", err)
+	}
+	execPath, err = filepath.EvalSymlinks(execPath)
+	if err != nil {
+		return fmt.Errorf("could not resolve executable path: %w", err)
+	}
+
 	_, _ = fmt.Fprintf(deps.Stdout, "Building %s from source...\n", version)
 
-	pkg := "github.com/" + repoOwner + "/" + repoName + "/cmd/fp@" + version
-	if err := deps.RunCommand("go", "install", pkg); err != nil {
+	gobin, err := os.MkdirTemp("", "fp-gobin-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(gobin) }()
+
+	pkg := modulePath + "/cmd/fp@" + version
+	ldflags := "-X " + appPackage + ".Version=" + version
+	if err := deps.RunCommandEnv(goInstallEnv(gobin), "go", "install", "-tags", "sqlite_fts5", "-ldflags", ldflags, pkg); err != nil {
 		return fmt.Errorf("fp: go install failed: %w", err)
 	}
 
+	goos := deps.platform().OS
+	binary := filepath.Join(gobin, "fp")
+	if goos == "windows" {
+		binary += ".exe"
+	}
+	if err := verifyBinary(deps, binary, version); err != nil {
+		return fmt.Errorf("fp: the built binary failed verification, nothing was installed: %w", err)
+	}
+
+	backup, err := backupExecutable(execPath)
+	if err != nil {
+		return fmt.Errorf("could not back up the current binary: %w", err)
+	}
+	defer func() { _ = os.Remove(backup) }()
+
+	if err := replaceExecutable(execPath, binary, goos); err != nil {
+		return err
+	}
+	if err := os.Chmod(execPath, 0755); err != nil {
+		return fmt.Errorf("could not set permissions: %w", err)
+	}
+
+	if err := verifyBinary(deps, execPath, version); err != nil {
+		if restoreErr := replaceExecutable(execPath, backup, goos); restoreErr != nil {
+			return fmt.Errorf("fp: the installed binary failed verification (%v) and the previous one could not be restored: %w", err, restoreErr)
+		}
+		_ = os.Chmod(execPath, 0755)
+		return fmt.Errorf("fp: the installed binary failed verification, restored %s: %w", deps.CurrentVersion, err)
+	}
+
 	_, _ = fmt.Fprintf(deps.Stdout, "Installed %s (via go install)\n", version)
-	_, _ = fmt.Fprintf(deps.Stdout, "Note: binary is in $GOPATH/bin or $HOME/go/bin\n")
 
 	return nil
 }
+
+// modulePath is the module go install builds fp from. It differs from the
+// GitHub repository name.
+const modulePath = "github.com/footprint-tools/cli"
+
+// appPackage is where the version reported by fp version is set at build
+// time.
+const appPackage = modulePath + "/internal/app"
+
+// goInstallEnv pins how go install fetches and checks fp, whatever the
+// user's go env says: modules come from the public proxy only and are
+// verified against the public checksum database, and GOFLAGS or GOPRIVATE
+// meant for their own projects don't apply.
+func goInstallEnv(gobin string) []string {
+	return []string{
+		"GOBIN=" + gobin,
+		"GOPROXY=https://proxy.golang.org",
+		"GOSUMDB=sum.golang.org",
+		"GOFLAGS=",
+		"GOPRIVATE=",
+		"GONOPROXY=",
+		"GONOSUMDB=",
+		"GOINSECURE=",
+	}
+}
+
+// verifyBinary checks the fp at path runs and was built from version of
+// the fp module. The version fp reports comes from the -ldflags
+// installFromSource passes, so the module version go recorded in the
+// binary (go version -m) is what tells which source was built.
+func verifyBinary(deps Deps, path, version string) error {
+	if _, err := deps.CommandOutput(path, "version"); err != nil {
+		return fmt.Errorf("could not run %s: %w", filepath.Base(path), err)
+	}
+	out, err := deps.CommandOutput("go", "version", "-m", path)
+	if err != nil {
+		return fmt.Errorf("could not read the build info of %s: %w", filepath.Base(path), err)
+	}
+	built := builtModuleVersion(out)
+	if built == "" {
+		return fmt.Errorf("%s has no build info for %s", filepath.Base(path), modulePath)
+	}
+	if cleanVersion(built) != cleanVersion(version) {
+		return fmt.Errorf("it was built from %s %s instead of %s", modulePath, built, version)
+	}
+	return nil
+}
+
+// builtModuleVersion returns the version of the fp module in the output of
+// go version -m, from its main module ("mod") line.
+func builtModuleVersion(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[0] == "mod" && fields[1] == modulePath {
+			return fields[2]
+		}
+	}
+	return ""
+}
+
+// backupExecutable copies the fp at execPath to a temp file, so it can be
+// put back if its replacement turns out broken.
+func backupExecutable(execPath string) (string, error) {
+	backup, err := os.CreateTemp("", "fp-backup-*")
+	if err != nil {
+		return "", err
+	}
+	_ = backup.Close()
+	if err := copyFile(execPath, backup.Name()); err != nil {
+		_ = os.Remove(backup.Name())
+		return "", err
+	}
+	return backup.Name(), nil
+}
@@ -2,6 +2,7 @@ package update
 
 import (
 	"archive/tar"
+	"archive/zip"
 	"bytes"
 	"compress/gzip"
 	"errors"
@@ -12,8 +13,10 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/usage"
 	"github.com/stretchr/testify/require"
 )
 
@@ -43,6 +46,45 @@ func newMockResponse(status int, body string) *http.Response {
 	}
 }
 
+// fakeGoInstall sets deps up so go install "builds" a new fp into GOBIN
+// that, like every binary asked, runs and was built from version of the fp
+// module. It returns the path of the fp being replaced.
+func fakeGoInstall(t *testing.T, deps *Deps, version string) string {
+	t.Helper()
+	execPath := filepath.Join(t.TempDir(), "fp")
+	require.NoError(t, os.WriteFile(execPath, []byte("old fp"), 0755))
+	deps.ExecutablePath = func() (string, error) { return execPath, nil }
+	deps.RunCommandEnv = func(env []string, name string, args ...string) error {
+		for _, kv := range env {
+			if gobin, ok := strings.CutPrefix(kv, "GOBIN="); ok {
+				return os.WriteFile(filepath.Join(gobin, "fp"), []byte("new fp"), 0755)
+			}
+		}
+		return errors.New("no GOBIN")
+	}
+	deps.CommandOutput = func(name string, _ ...string) (string, error) {
+		return fakeCommandOutput(name, version), nil
+	}
+	deps.Platform = func() platform { return platform{OS: "linux", Arch: "amd64"} }
+	return execPath
+}
+
+// fakeCommandOutput answers fp version, and go version -m with the build
+// info of an fp built from version.
+func fakeCommandOutput(name, version string) string {
+	if name == "go" {
+		return "fp: go1.24.0\n\tpath\tgithub.com/footprint-tools/cli/cmd/fp\n" +
+			"\tmod\tgithub.com/footprint-tools/cli\t" + version + "\th1:abc=\n"
+	}
+	return "fp version v1.0.0-dev\n"
+}
+
+func TestBuiltModuleVersion(t *testing.T) {
+	require.Equal(t, "v1.2.0", builtModuleVersion(fakeCommandOutput("go", "v1.2.0")))
+	require.Empty(t, builtModuleVersion("fp: go1.24.0\n\tpath\tgithub.com/footprint-tools/cli/cmd/fp\n\tmod\tgithub.com/footprint-tools/cli\n"))
+	require.Empty(t, builtModuleVersion("fp: go1.24.0\n\tmod\texample.com/other\tv1.2.0\n"))
+}
+
 func TestUpdate_WithTagFlag(t *testing.T) {
 	var stdout bytes.Buffer
 	commandRun := false
@@ -56,6 +98,7 @@ func TestUpdate_WithTagFlag(t *testing.T) {
 			return nil
 		},
 	}
+	fakeGoInstall(t, &deps, "v1.2.0")
 
 	flags := dispatchers.NewParsedFlags([]string{"--tag"})
 
@@ -139,6 +182,7 @@ func TestUpdate_SpecificVersionNotFound_FallsBackToSource(t *testing.T) {
 			return nil
 		},
 	}
+	fakeGoInstall(t, &deps, "v2.0.0")
 
 	flags := dispatchers.NewParsedFlags([]string{})
 	err := update([]string{"v2.0.0"}, flags, deps)
@@ -255,41 +299,99 @@ func TestInstallFromSource_GoInstallFails(t *testing.T) {
 	var stdout bytes.Buffer
 
 	deps := Deps{
-		Stdout: &stdout,
-		Stderr: &stdout,
-		RunCommand: func(name string, args ...string) error {
-			if name == "go" && len(args) > 0 && args[0] == "install" {
-				return errors.New("install failed")
-			}
-			return nil
-		},
+		Stdout:     &stdout,
+		Stderr:     &stdout,
+		RunCommand: func(name string, args ...string) error { return nil },
+	}
+	execPath := fakeGoInstall(t, &deps, "v1.0.0")
+	deps.RunCommandEnv = func([]string, string, ...string) error {
+		return errors.New("install failed")
 	}
 
 	err := installFromSource(deps, "v1.0.0")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "go install failed")
+
+	data, err := os.ReadFile(execPath)
+	require.NoError(t, err)
+	require.Equal(t, "old fp", string(data))
 }
 
 func TestInstallFromSource_Success(t *testing.T) {
 	var stdout bytes.Buffer
-	var installedPkg string
+	var installEnv, installArgs []string
 
 	deps := Deps{
-		Stdout: &stdout,
-		Stderr: &stdout,
-		RunCommand: func(name string, args ...string) error {
-			if name == "go" && len(args) > 0 && args[0] == "install" {
-				installedPkg = args[1]
-			}
-			return nil
-		},
+		Stdout:     &stdout,
+		Stderr:     &stdout,
+		RunCommand: func(name string, args ...string) error { return nil },
+	}
+	execPath := fakeGoInstall(t, &deps, "v1.0.0")
+	install := deps.RunCommandEnv
+	deps.RunCommandEnv = func(env []string, name string, args ...string) error {
+		installEnv, installArgs = env, args
+		return install(env, name, args...)
 	}
 
 	err := installFromSource(deps, "1.0.0") // Without 'v' prefix
 	require.NoError(t, err)
-	require.Contains(t, installedPkg, "@v1.0.0")
+	require.Contains(t, installArgs[len(installArgs)-1], "@v1.0.0")
+	require.Equal(t, []string{"install", "-tags", "sqlite_fts5", "-ldflags", "-X " + appPackage + ".Version=v1.0.0"}, installArgs[:5])
+	require.Contains(t, installEnv, "GOPROXY=https://proxy.golang.org")
+	require.Contains(t, installEnv, "GOSUMDB=sum.golang.org")
+	require.Contains(t, installEnv, "GOFLAGS=", "the user's GOFLAGS don't apply")
 	require.Contains(t, stdout.String(), "Building v1.0.0")
 	require.Contains(t, stdout.String(), "Installed v1.0.0")
+
+	data, err := os.ReadFile(execPath)
+	require.NoError(t, err)
+	require.Equal(t, "new fp", string(data))
+}
+
+func TestInstallFromSource_BuiltBinaryReportsWrongVersion(t *testing.T) {
+	var stdout bytes.Buffer
+
+	deps := Deps{
+		Stdout:     &stdout,
+		Stderr:     &stdout,
+		RunCommand: func(name string, args ...string) error { return nil },
+	}
+	execPath := fakeGoInstall(t, &deps, "v0.9.0")
+
+	err := installFromSource(deps, "v1.0.0")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "built from github.com/footprint-tools/cli v0.9.0 instead of v1.0.0")
+	require.Contains(t, err.Error(), "nothing was installed")
+
+	data, err := os.ReadFile(execPath)
+	require.NoError(t, err)
+	require.Equal(t, "old fp", string(data))
+}
+
+func TestInstallFromSource_RestoresOldBinary(t *testing.T) {
+	var stdout bytes.Buffer
+
+	deps := Deps{
+		Stdout:         &stdout,
+		Stderr:         &stdout,
+		CurrentVersion: "v0.9.0",
+		RunCommand:     func(name string, args ...string) error { return nil },
+	}
+	execPath := fakeGoInstall(t, &deps, "v1.0.0")
+	deps.CommandOutput = func(name string, _ ...string) (string, error) {
+		if name == execPath {
+			return "", errors.New("exec format error")
+		}
+		return fakeCommandOutput(name, "v1.0.0"), nil
+	}
+
+	err := installFromSource(deps, "v1.0.0")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "restored v0.9.0")
+
+	data, err := os.ReadFile(execPath)
+	require.NoError(t, err)
+	require.Equal(t, "old fp", string(data))
 }
 
 func TestDefaultDeps(t *testing.T) {
@@ -301,6 +403,8 @@ func TestDefaultDeps(t *testing.T) {
 	require.NotEmpty(t, deps.CurrentVersion)
 	require.NotNil(t, deps.ExecutablePath)
 	require.NotNil(t, deps.RunCommand)
+	require.NotNil(t, deps.RunCommandEnv)
+	require.NotNil(t, deps.CommandOutput)
 }
 
 func TestInstallFromRelease_NoBinaryForPlatform(t *testing.T) {
@@ -327,6 +431,7 @@ func TestInstallFromRelease_NoBinaryForPlatform(t *testing.T) {
 			return nil
 		},
 	}
+	fakeGoInstall(t, &deps, "v1.2.0")
 
 	flags := dispatchers.NewParsedFlags([]string{})
 	err := update([]string{}, flags, deps)
@@ -857,6 +962,7 @@ func TestInstallFromRelease_WithSpecificVersion(t *testing.T) {
 			return nil
 		},
 	}
+	fakeGoInstall(t, &deps, "v1.5.0")
 
 	// Specific version with no matching binary falls back to source
 	err := installFromRelease(deps, "v1.5.0")
@@ -1025,3 +1131,204 @@ func TestNewDependencies_ExecutablePath(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, path)
 }
+
+func TestUpdate_Check(t *testing.T) {
+	client := &mockHTTPClient{
+		responses: map[string]*http.Response{
+			apiURL + "/releases/latest": newMockResponse(200, `{"tag_name": "v1.1.0", "assets": []}`),
+		},
+	}
+	var stdout bytes.Buffer
+	var cached string
+	deps := Deps{
+		Stdout:         &stdout,
+		HTTPClient:     client,
+		CurrentVersion: "v1.0.0-3-gabc1234",
+		SetUpdateCache: func(_, latestVersion, _ string) error {
+			cached = latestVersion
+			return nil
+		},
+		Now: time.Now,
+	}
+
+	err := update(nil, dispatchers.NewParsedFlags([]string{"--check"}), deps)
+	var ue *usage.Error
+	require.ErrorAs(t, err, &ue)
+	require.Equal(t, 10, ue.GetExitCode())
+	require.Contains(t, stdout.String(), "Update available: v1.0.0 → v1.1.0 (stable)")
+	require.Equal(t, "v1.1.0", cached)
+
+	stdout.Reset()
+	deps.CurrentVersion = "v1.1.0"
+	client.responses[apiURL+"/releases/latest"] = newMockResponse(200, `{"tag_name": "v1.1.0", "assets": []}`)
+	err = update(nil, dispatchers.NewParsedFlags([]string{"--check"}), deps)
+	require.NoError(t, err)
+	require.Contains(t, stdout.String(), "fp v1.1.0 is the latest stable release")
+}
+
+func TestUpdate_CheckAheadOfChannel(t *testing.T) {
+	client := &mockHTTPClient{
+		responses: map[string]*http.Response{
+			apiURL + "/releases/latest": newMockResponse(200, `{"tag_name": "v1.1.0", "assets": []}`),
+		},
+	}
+	var stdout bytes.Buffer
+	deps := Deps{Stdout: &stdout, HTTPClient: client, CurrentVersion: "v1.2.0-rc.1"}
+
+	// A prerelease build ahead of the stable channel isn't offered a downgrade
+	err := update(nil, dispatchers.NewParsedFlags([]string{"--check"}), deps)
+	require.NoError(t, err)
+	require.Contains(t, stdout.String(), "fp v1.2.0-rc.1 is newer than the latest stable release (v1.1.0)")
+
+	// Development builds aren't checked at all
+	stdout.Reset()
+	deps.CurrentVersion = "dev"
+	deps.HTTPClient = &mockHTTPClient{}
+	require.NoError(t, update(nil, dispatchers.NewParsedFlags([]string{"--check"}), deps))
+	require.Contains(t, stdout.String(), "development build")
+}
+
+func TestUpdate_ChannelFlag(t *testing.T) {
+	client := &mockHTTPClient{
+		responses: map[string]*http.Response{
+			apiURL + "/releases/latest":      newMockResponse(200, `{"tag_name": "v1.1.0", "assets": []}`),
+			apiURL + "/releases?per_page=20": newMockResponse(200, `[{"tag_name": "v1.2.0-rc.1", "prerelease": true, "assets": []}]`),
+		},
+	}
+	var stdout bytes.Buffer
+	var cachedChannel string
+	deps := Deps{
+		Stdout:         &stdout,
+		HTTPClient:     client,
+		CurrentVersion: "v1.1.0",
+		Channel:        "stable",
+		SetUpdateCache: func(_, _, channel string) error {
+			cachedChannel = channel
+			return nil
+		},
+		Now: time.Now,
+	}
+
+	// --channel overrides update_channel for the run
+	err := update(nil, dispatchers.NewParsedFlags([]string{"--check", "--channel", "prerelease"}), deps)
+	require.Error(t, err)
+	require.Contains(t, stdout.String(), "Update available: v1.1.0 → v1.2.0-rc.1 (prerelease)")
+	require.Equal(t, "prerelease", cachedChannel)
+
+	err = update(nil, dispatchers.NewParsedFlags([]string{"--check", "--channel", "nightly"}), deps)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid --channel 'nightly'")
+}
+
+func TestUpdate_CheckRejectsTagAndVersion(t *testing.T) {
+	deps := Deps{Stdout: io.Discard, HTTPClient: &mockHTTPClient{}}
+
+	err := update([]string{"v1.2.0"}, dispatchers.NewParsedFlags([]string{"--check", "--tag"}), deps)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--check")
+
+	err = update([]string{"v1.2.0"}, dispatchers.NewParsedFlags([]string{"--check"}), deps)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "takes no version")
+}
+
+func TestUpdate_InvalidChannel(t *testing.T) {
+	deps := Deps{Stdout: io.Discard, HTTPClient: &mockHTTPClient{}, Channel: "nightly"}
+
+	err := update(nil, dispatchers.NewParsedFlags([]string{"--check"}), deps)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid update_channel 'nightly'")
+}
+
+func TestFetchRelease_PrereleaseChannel(t *testing.T) {
+	releasesJSON := `[
+		{"tag_name": "v1.3.0", "draft": true},
+		{"tag_name": "v1.3.0-rc.1", "prerelease": true},
+		{"tag_name": "v1.2.0"}
+	]`
+	client := &mockHTTPClient{
+		responses: map[string]*http.Response{
+			apiURL + "/releases?per_page=20": newMockResponse(200, releasesJSON),
+		},
+	}
+
+	release, err := fetchRelease(Deps{HTTPClient: client, Channel: channelPrerelease}, "")
+	require.NoError(t, err)
+	require.Equal(t, "v1.3.0-rc.1", release.TagName)
+	require.True(t, release.Prerelease)
+}
+
+func TestParseChannel(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"", channelStable, true},
+		{"stable", channelStable, true},
+		{" Prerelease ", channelPrerelease, true},
+		{"beta", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := parseChannel(tt.value)
+		require.Equal(t, tt.ok, ok, tt.value)
+		require.Equal(t, tt.want, got, tt.value)
+	}
+}
+
+func TestExtractBinary_Zip(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "fp_windows_amd64.zip")
+	f, err := os.Create(archivePath)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for name, content := range map[string]string{"README.md": "readme", "fp_windows_amd64/fp.exe": "windows binary"} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	extractedPath, err := extractBinary(archivePath)
+	require.NoError(t, err)
+	defer func() { _ = os.Remove(extractedPath) }()
+
+	content, err := os.ReadFile(extractedPath)
+	require.NoError(t, err)
+	require.Equal(t, "windows binary", string(content))
+}
+
+func TestReplaceExecutable_Windows(t *testing.T) {
+	dir := t.TempDir()
+	execPath := filepath.Join(dir, "fp.exe")
+	binary := filepath.Join(dir, "new")
+	require.NoError(t, os.WriteFile(execPath, []byte("old"), 0755))
+	require.NoError(t, os.WriteFile(binary, []byte("new"), 0755))
+
+	require.NoError(t, replaceExecutable(execPath, binary, "windows"))
+
+	content, err := os.ReadFile(execPath)
+	require.NoError(t, err)
+	require.Equal(t, "new", string(content))
+	old, err := os.ReadFile(filepath.Join(dir, "fp.old.exe"))
+	require.NoError(t, err)
+	require.Equal(t, "old", string(old), "the running binary is moved aside, not deleted")
+
+	removeOldBinary(func() (string, error) { return execPath, nil })
+	require.NoFileExists(t, filepath.Join(dir, "fp.old.exe"))
+	require.FileExists(t, execPath)
+}
+
+func TestReplaceExecutable_WindowsRestoresOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	execPath := filepath.Join(dir, "fp.exe")
+	require.NoError(t, os.WriteFile(execPath, []byte("old"), 0755))
+
+	err := replaceExecutable(execPath, filepath.Join(dir, "missing"), "windows")
+	require.ErrorContains(t, err, "could not install new binary")
+
+	content, err := os.ReadFile(execPath)
+	require.NoError(t, err)
+	require.Equal(t, "old", string(content))
+}
@@ -1,6 +1,11 @@
 package cli
 
-import "github.com/footprint-tools/cli/internal/dispatchers"
+import (
+	"github.com/footprint-tools/cli/internal/completions"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
 
 var (
 	ConfigKeyArg = []dispatchers.ArgSpec{
@@ -8,6 +13,7 @@ var (
 			Name:        "key",
 			Description: "Configuration key",
 			Required:    true,
+			Choices:     configKeyNames,
 		},
 	}
 
@@ -16,6 +22,7 @@ var (
 			Name:        "key",
 			Description: "Configuration key",
 			Required:    true,
+			Choices:     configKeyNames,
 		},
 		{
 			Name:        "value",
@@ -32,11 +39,75 @@ var (
 		},
 	}
 
+	BadgeRepoArg = []dispatchers.ArgSpec{
+		{
+			Name:        "repo",
+			Description: "Repository path or id (defaults to current directory)",
+			Required:    false,
+		},
+	}
+
+	QuerySQLArg = []dispatchers.ArgSpec{
+		{
+			Name:        "sql",
+			Description: "SQL query to run (quote it to keep your shell away from * and ;)",
+			Required:    true,
+		},
+	}
+
+	ImportPathArg = []dispatchers.ArgSpec{
+		{
+			Name:        "path",
+			Description: "Exported CSV or JSONL file (optionally .age or .gpg encrypted), or a directory of them",
+			Required:    true,
+		},
+	}
+
 	ThemeNameArg = []dispatchers.ArgSpec{
 		{
 			Name:        "name",
 			Description: "Theme name (e.g., default-dark, neon-light)",
 			Required:    true,
+			Choices:     themeNames,
+			Values:      completions.ValuesThemes,
+		},
+	}
+
+	ProjectNameArg = []dispatchers.ArgSpec{
+		{
+			Name:        "name",
+			Description: "Project name",
+			Required:    true,
+			Values:      completions.ValuesProjects,
+		},
+	}
+
+	ProjectAddRepoArgs = []dispatchers.ArgSpec{
+		{
+			Name:        "name",
+			Description: "Project name",
+			Required:    true,
+			Values:      completions.ValuesProjects,
+		},
+		{
+			Name:        "path",
+			Description: "Repository paths or ids (defaults to current directory)",
+			Required:    false,
+		},
+	}
+
+	ProjectRemoveRepoArgs = []dispatchers.ArgSpec{
+		{
+			Name:        "name",
+			Description: "Project name",
+			Required:    true,
+			Values:      completions.ValuesProjects,
+		},
+		{
+			Name:        "repo",
+			Description: "Repository paths or ids",
+			Required:    true,
+			Values:      completions.ValuesRepos,
 		},
 	}
 
@@ -48,3 +119,18 @@ var (
 		},
 	}
 )
+
+// configKeyNames lists the keys offered when prompting for a config key.
+func configKeyNames() []string {
+	keys := domain.VisibleConfigKeys()
+	names := make([]string, 0, len(keys))
+	for _, k := range keys {
+		names = append(names, k.Name)
+	}
+	return names
+}
+
+// themeNames lists the themes offered when prompting for a theme.
+func themeNames() []string {
+	return append(append([]string{style.AutoTheme}, style.BaseThemeNames...), style.AllThemeNames()...)
+}
@@ -1,6 +1,9 @@
 package cli
 
-import "github.com/footprint-tools/cli/internal/dispatchers"
+import (
+	"github.com/footprint-tools/cli/internal/completions"
+	"github.com/footprint-tools/cli/internal/dispatchers"
+)
 
 var (
 	RootFlags = []dispatchers.FlagDescriptor{
@@ -24,6 +27,11 @@ var (
 			Description: "Disable colored output",
 			Scope:       dispatchers.FlagScopeGlobal,
 		},
+		{
+			Names:       []string{"--force-color"},
+			Description: "Color output even when it is not a terminal",
+			Scope:       dispatchers.FlagScopeGlobal,
+		},
 		{
 			Names:       []string{"--no-pager"},
 			Description: "Do not use pager for output",
@@ -35,6 +43,33 @@ var (
 			Description: "Use specified pager for this command",
 			Scope:       dispatchers.FlagScopeGlobal,
 		},
+		{
+			Names:       []string{"--accessible"},
+			Description: "Use numbered plain-text prompts instead of full-screen views (or set FP_ACCESSIBLE)",
+			Scope:       dispatchers.FlagScopeGlobal,
+		},
+		{
+			Names:       []string{"--read-only"},
+			Description: "Block commands that change hooks, events, exports or config",
+			Scope:       dispatchers.FlagScopeGlobal,
+		},
+		{
+			Names:       []string{"--profile"},
+			ValueHint:   "<name>",
+			Description: "Use a config profile, with its own database and exports (or set FP_PROFILE)",
+			Scope:       dispatchers.FlagScopeGlobal,
+		},
+		{
+			Names:       []string{"--tz"},
+			ValueHint:   "<zone>",
+			Description: "Show times in this time zone (IANA name, e.g. Europe/Berlin)",
+			Scope:       dispatchers.FlagScopeGlobal,
+		},
+		{
+			Names:       []string{"--utc"},
+			Description: "Show times in UTC (same as --tz UTC)",
+			Scope:       dispatchers.FlagScopeGlobal,
+		},
 	}
 
 	ConfigUnsetFlags = []dispatchers.FlagDescriptor{
@@ -71,30 +106,61 @@ var (
 			ValueHint:   "<status>",
 			Description: "Filter by status: pending, exported, orphaned, skipped",
 			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesStatuses,
 		},
 		{
 			Names:       []string{"-S", "--source"},
 			ValueHint:   "<source>",
-			Description: "Filter by source (post-commit, post-rewrite, post-checkout, post-merge, pre-push, manual, backfill)",
+			Description: "Filter by source (post-commit, post-rewrite, post-checkout, post-merge, pre-push, manual, backfill, branch-create, branch-delete, stash)",
 			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesSources,
 		},
 		{
 			Names:       []string{"--since"},
 			ValueHint:   "<date>",
-			Description: "Show events after date (YYYY-MM-DD)",
+			Description: "Show events after date (YYYY-MM-DD, yesterday, 7d, \"2 weeks ago\", a month)",
 			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesDates,
 		},
 		{
 			Names:       []string{"--until"},
 			ValueHint:   "<date>",
-			Description: "Show events before date (YYYY-MM-DD)",
+			Description: "Show events before date (YYYY-MM-DD, yesterday, 7d, \"2 weeks ago\", a month)",
 			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesDates,
 		},
 		{
 			Names:       []string{"-r", "--repo"},
 			ValueHint:   "<id>",
 			Description: "Filter by repository id",
 			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesRepos,
+		},
+		{
+			Names:       []string{"--path"},
+			ValueHint:   "<dir>",
+			Description: "Only events run in this directory of the repository, or below it",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--device"},
+			ValueHint:   "<name>",
+			Description: "Only events recorded on this machine (see device_name)",
+			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesDevices,
+		},
+		{
+			Names:       []string{"--project"},
+			ValueHint:   "<name>",
+			Description: "Only events in the repositories of this project (see 'fp project')",
+			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesProjects,
+		},
+		{
+			Names:       []string{"--search"},
+			ValueHint:   "<query>",
+			Description: "Only events whose commit message or note contains every word",
+			Scope:       dispatchers.FlagScopeLocal,
 		},
 		{
 			Names:       []string{"-n", "--limit"},
@@ -102,6 +168,199 @@ var (
 			Description: "Limit number of results (shorthand: -<n>, e.g., -50)",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
+		{
+			Names:       []string{"--group-by"},
+			ValueHint:   "<field>",
+			Description: "Group events with subtotals by repo, branch, day or source",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--mine"},
+			Description: "Only show commits authored by one of your identities",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--no-merges"},
+			Description: "Don't show merge commits or pulls",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--first-parent"},
+			Description: "Don't show commits a merge brought in from another branch",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--dedupe-rewrites"},
+			Description: "Show a rebased or amended commit once, not once per copy",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--default-branch"},
+			Description: "Only show events on their repository's default branch (origin/HEAD)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	HeatmapFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--year"},
+			ValueHint:   "<yyyy>",
+			Description: "Year to show first (default: current year)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"-r", "--repo"},
+			ValueHint:   "<id>",
+			Description: "Only count events from this repository id",
+			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesRepos,
+		},
+	}
+
+	TopFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--interval"},
+			ValueHint:   "<seconds>",
+			Description: "Seconds between refreshes (default: 2)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	ReportFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--html"},
+			ValueHint:   "<dir>",
+			Description: "Write a self-contained HTML report to <dir>/index.html (or to <file>.html)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--week"},
+			Description: "Print a summary of the week containing --until (default: this week)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--format"},
+			ValueHint:   "<txt|md|html>",
+			Description: "Format of the --week summary (default: txt)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--email-stdout"},
+			Description: "Print the --week summary as an email message, for piping to sendmail",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--since"},
+			ValueHint:   "<date>",
+			Description: "Start of the report (default: one year before --until)",
+			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesDates,
+		},
+		{
+			Names:       []string{"--until"},
+			ValueHint:   "<date>",
+			Description: "End of the report (default: today)",
+			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesDates,
+		},
+		{
+			Names:       []string{"-r", "--repo"},
+			ValueHint:   "<id>",
+			Description: "Only include events from this repository id",
+			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesRepos,
+		},
+		{
+			Names:       []string{"--mine"},
+			Description: "Only include commits authored by one of your identities",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--default-branch"},
+			Description: "Only include events on their repository's default branch (origin/HEAD)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	StatsFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--since"},
+			ValueHint:   "<date>",
+			Description: "First day to include (default: six days before --until)",
+			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesDates,
+		},
+		{
+			Names:       []string{"--until"},
+			ValueHint:   "<date>",
+			Description: "Last day to include (default: today)",
+			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesDates,
+		},
+		{
+			Names:       []string{"--device"},
+			ValueHint:   "<name>",
+			Description: "Only count events recorded on this machine (see device_name)",
+			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesDevices,
+		},
+		{
+			Names:       []string{"--project"},
+			ValueHint:   "<name>",
+			Description: "Only count events in the repositories of this project (see 'fp project')",
+			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesProjects,
+		},
+		{
+			Names:       []string{"--mine"},
+			Description: "Only count commits authored by one of your identities",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--no-merges"},
+			Description: "Don't count merge commits or pulls",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--first-parent"},
+			Description: "Don't count commits a merge brought in from another branch",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--dedupe-rewrites"},
+			Description: "Count a rebased or amended commit once, not once per copy",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--default-branch"},
+			Description: "Only count events on their repository's default branch (origin/HEAD)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--by-language"},
+			Description: "Show lines added and removed per language instead of focus",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	BadgeFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--out"},
+			ValueHint:   "<file>",
+			Description: "Write the SVG to <file> instead of stdout",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--metric"},
+			ValueHint:   "<name>",
+			Description: "What the badge shows: commits (this month, default) or last-activity",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
 	}
 
 	RecordFlags = []dispatchers.FlagDescriptor{
@@ -115,12 +374,24 @@ var (
 			Description: "Acknowledge manual execution (suppresses note)",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
+		{
+			Names:       []string{"--tag"},
+			ValueHint:   "<label>",
+			Description: "Label the recorded event, such as \"pairing\" or \"client-x\"",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--note"},
+			ValueHint:   "<text>",
+			Description: "Attach a note to the recorded event",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
 	}
 
 	SetupFlags = []dispatchers.FlagDescriptor{
 		{
-			Names:       []string{"--core-hooks-path"},
-			Description: "Set git core.hooksPath globally (affects ALL repos on this machine)",
+			Names:       []string{"--global", "--core-hooks-path"},
+			Description: "Install fp hooks for ALL repos on this machine via git's global core.hooksPath",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
 		{
@@ -128,6 +399,11 @@ var (
 			Description: "Overwrite existing hooks without prompting",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
+		{
+			Names:       []string{"--chain"},
+			Description: "Keep existing hooks and run fp after them",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
 		{
 			Names:       []string{"--dry-run"},
 			Description: "Show what would be installed without doing it",
@@ -135,10 +411,23 @@ var (
 		},
 	}
 
+	InitFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--encrypt"},
+			Description: "Encrypt the database, keeping the key in the OS keychain",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--passphrase"},
+			Description: "With --encrypt, use a passphrase instead of the OS keychain",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
 	TeardownFlags = []dispatchers.FlagDescriptor{
 		{
-			Names:       []string{"--core-hooks-path"},
-			Description: "Unset git core.hooksPath and remove global hooks",
+			Names:       []string{"--global", "--core-hooks-path"},
+			Description: "Remove global hooks and restore git's previous core.hooksPath",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
 		{
@@ -153,6 +442,19 @@ var (
 		},
 	}
 
+	TrackFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--dry-run"},
+			Description: "Show what would change without touching any hooks",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output the result as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
 	ReposFlags = []dispatchers.FlagDescriptor{
 		{
 			Names:       []string{"-i", "--interactive"},
@@ -198,6 +500,61 @@ var (
 		},
 	}
 
+	ReposBatchFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--root"},
+			ValueHint:   "<path>",
+			Description: "Root directory to scan (default: current directory)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--depth"},
+			ValueHint:   "<n>",
+			Description: "Maximum depth to scan (default: 25)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--dry-run"},
+			Description: "Show what would change without touching any hooks",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output the per-repository results as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	HooksUpgradeFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--all"},
+			Description: "Upgrade every tracked repository, those found under --root, and global hooks",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--root"},
+			ValueHint:   "<path>",
+			Description: "Root directory to scan with --all (default: current directory)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--depth"},
+			ValueHint:   "<n>",
+			Description: "Maximum depth to scan (default: 25)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--dry-run"},
+			Description: "Show which hooks are outdated without rewriting them",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output the per-repository results as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
 	ReposCheckFlags = []dispatchers.FlagDescriptor{
 		{
 			Names:       []string{"--json"},
@@ -217,6 +574,11 @@ var (
 			Description: "Output as JSON (one object per line)",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
+		{
+			Names:       []string{"--plain"},
+			Description: "Print uncolored, tab-separated lines with no banner, for pipes and scripts",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
 		{
 			Names:       []string{"-e", "--enrich"},
 			Description: "Show commit message and author from git",
@@ -232,18 +594,39 @@ var (
 			ValueHint:   "<status>",
 			Description: "Filter by status: pending, exported, orphaned, skipped",
 			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesStatuses,
 		},
 		{
 			Names:       []string{"-S", "--source"},
 			ValueHint:   "<source>",
-			Description: "Filter by source (post-commit, post-rewrite, post-checkout, post-merge, pre-push, manual, backfill)",
+			Description: "Filter by source (post-commit, post-rewrite, post-checkout, post-merge, pre-push, manual, backfill, branch-create, branch-delete, stash)",
 			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesSources,
 		},
 		{
 			Names:       []string{"-r", "--repo"},
 			ValueHint:   "<id>",
 			Description: "Filter by repository id",
 			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesRepos,
+		},
+		{
+			Names:       []string{"--path"},
+			ValueHint:   "<dir>",
+			Description: "Only events run in this directory of the repository, or below it",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--root"},
+			ValueHint:   "<path>",
+			Description: "Also report new repositories under this directory, and branches and commits in ones without fp hooks",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--depth"},
+			ValueHint:   "<n>",
+			Description: "Maximum depth to scan with --root (default: 25)",
+			Scope:       dispatchers.FlagScopeLocal,
 		},
 	}
 
@@ -263,6 +646,180 @@ var (
 			Description: "Open the export directory in file manager",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
+		{
+			Names:       []string{"--format"},
+			ValueHint:   "<csv|jsonl|parquet|ics|ics-daily>",
+			Description: "Also write exports in this format (default: export_format)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--mine"},
+			Description: "Only export your own commits; others are marked skipped (see identities)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--no-merges"},
+			Description: "Don't export merge commits or pulls; they are marked skipped",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--first-parent"},
+			Description: "Don't export commits a merge brought in from another branch; they are marked skipped",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--dedupe-rewrites"},
+			Description: "Export a rebased or amended commit once; other copies are marked skipped",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--sqlite"},
+			ValueHint:   "<file>",
+			Description: "Write a read-only SQLite snapshot of all events to this file instead",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--verify-remote"},
+			Description: "Check the export remotes can be fetched and pushed to, without exporting",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--squash"},
+			Description: "Rewrite the export repo's history into one snapshot commit and force-push it",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--since"},
+			ValueHint:   "<date>",
+			Description: "With --sqlite, only events on or after this date",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--until"},
+			ValueHint:   "<date>",
+			Description: "With --sqlite, only events on or before this date",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--repo"},
+			ValueHint:   "<id>",
+			Description: "With --sqlite, only events of this repository",
+			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesRepos,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	ExportRepairFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--dry-run"},
+			Description: "Diagnose the export repo without changing it",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--force-reclone"},
+			Description: "Re-create the export repo from its remote, keeping local CSVs",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	ExportScheduleFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	ExportRemotesListFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	DaemonStartFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--foreground"},
+			Description: "Run in the current terminal instead of the background",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	DaemonStatusFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	MaintenanceFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	GCFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	QueryFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output rows as a JSON array of objects",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	StatusFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--json"},
+			Description: "Output the checklist as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--all"},
+			Description: "Show hooks and events of every tracked repository",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	DoctorFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--fix"},
+			Description: "Fix the issues that don't need your input",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output check results as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	ImportFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--dry-run"},
+			Description: "Show what would be imported without doing it",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--pending"},
+			Description: "Include the imported events in the next export",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
 		{
 			Names:       []string{"--json"},
 			Description: "Output as JSON",
@@ -271,17 +828,24 @@ var (
 	}
 
 	BackfillFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--all"},
+			Description: "Backfill every tracked repository",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
 		{
 			Names:       []string{"--since"},
 			ValueHint:   "<date>",
-			Description: "Import commits after date (YYYY-MM-DD)",
+			Description: "Import commits after date (YYYY-MM-DD, 7d, \"2 weeks ago\", a month)",
 			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesDates,
 		},
 		{
 			Names:       []string{"--until"},
 			ValueHint:   "<date>",
-			Description: "Import commits before date (YYYY-MM-DD)",
+			Description: "Import commits before date (YYYY-MM-DD, 7d, \"2 weeks ago\", a month)",
 			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesDates,
 		},
 		{
 			Names:       []string{"--limit", "-n"},
@@ -294,6 +858,19 @@ var (
 			ValueHint:   "<name>",
 			Description: "Use this branch name for all commits (default: infer)",
 			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesBranches,
+		},
+		{
+			Names:       []string{"--from-github"},
+			ValueHint:   "<owner/repo>",
+			Description: "Import commits from the GitHub API instead of local history",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--author"},
+			ValueHint:   "<login>",
+			Description: "With --from-github, whose commits to import (default: me, the token's user)",
+			Scope:       dispatchers.FlagScopeLocal,
 		},
 		{
 			Names:       []string{"--dry-run"},
@@ -314,7 +891,7 @@ var (
 			Scope:       dispatchers.FlagScopeLocal,
 		},
 		{
-			Names:       []string{"--tail", "-f"},
+			Names:       []string{"--follow", "-f"},
 			Description: "Follow logs in real time",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
@@ -324,11 +901,17 @@ var (
 			Scope:       dispatchers.FlagScopeLocal,
 		},
 		{
-			Names:       []string{"--limit", "-n"},
+			Names:       []string{"--tail", "--limit", "-n"},
 			ValueHint:   "<n>",
 			Description: "Number of lines to show (default: 50, shorthand: -<n>)",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
+		{
+			Names:       []string{"--level"},
+			ValueHint:   "<level>",
+			Description: "Show only entries at this level or above: debug, info, warn, error",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
 		{
 			Names:       []string{"--json"},
 			Description: "Output as JSON array of log entries",
@@ -336,12 +919,71 @@ var (
 		},
 	}
 
+	ProjectListFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--since"},
+			ValueHint:   "<date>",
+			Description: "Only count events after date (YYYY-MM-DD, yesterday, 7d, \"2 weeks ago\", a month)",
+			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesDates,
+		},
+		{
+			Names:       []string{"--until"},
+			ValueHint:   "<date>",
+			Description: "Only count events before date (YYYY-MM-DD, yesterday, 7d, \"2 weeks ago\", a month)",
+			Scope:       dispatchers.FlagScopeLocal,
+			Values:      completions.ValuesDates,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	ReplayFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--speed"},
+			ValueHint:   "<n>x",
+			Description: "How much faster than recorded to replay (default: 10x)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--file"},
+			ValueHint:   "<path>",
+			Description: "JSON lines to replay, as printed by 'fp watch --json' (default: synthetic events)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--count"},
+			ValueHint:   "<n>",
+			Description: "Number of synthetic events to generate (default: 30)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--clean"},
+			Description: "Remove the events earlier replays stored",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
 	UpdateFlags = []dispatchers.FlagDescriptor{
 		{
 			Names:       []string{"--tag"},
 			Description: "Install from git tag using go install (requires Go)",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
+		{
+			Names:       []string{"--check"},
+			Description: "Only report whether an update is available (exit code 10 if so)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--channel"},
+			ValueHint:   "<stable|prerelease>",
+			Description: "Release channel for this run (default: update_channel)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
 	}
 
 	CompletionsFlags = []dispatchers.FlagDescriptor{
@@ -350,6 +992,12 @@ var (
 			Description: "Print completion script to stdout (for eval)",
 			Scope:       dispatchers.FlagScopeLocal,
 		},
+		{
+			Names:       []string{"--values"},
+			ValueHint:   "<kind>",
+			Description: "List values the scripts complete: repos, branches, themes or statuses",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
 	}
 
 	VersionFlags = []dispatchers.FlagDescriptor{
@@ -368,6 +1016,29 @@ var (
 		},
 	}
 
+	ThemeExportFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--format"},
+			ValueHint:   "<toml|json>",
+			Description: "Theme file format (default: toml)",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	ThemeImportFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--name"},
+			ValueHint:   "<name>",
+			Description: "Import under this name instead of the file's",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--force"},
+			Description: "Replace a custom theme with the same name",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
 	ConfigFlags = []dispatchers.FlagDescriptor{
 		{
 			Names:       []string{"-i", "--interactive"},
@@ -377,6 +1048,19 @@ var (
 	}
 
 	ConfigListFlags = []dispatchers.FlagDescriptor{
+		{
+			Names:       []string{"--defaults"},
+			Description: "List every setting with its value and default",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+		{
+			Names:       []string{"--json"},
+			Description: "Output as JSON",
+			Scope:       dispatchers.FlagScopeLocal,
+		},
+	}
+
+	ConfigProfileListFlags = []dispatchers.FlagDescriptor{
 		{
 			Names:       []string{"--json"},
 			Description: "Output as JSON",
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+)
+
+// slowCommandHints suggest a fix for commands that are slow because of the
+// size or state of the local data. Commands that are expected to take a
+// while (backfill, update, interactive views) are intentionally missing.
+var slowCommandHints = map[string]string{
+	"activity":   "run 'fp maintenance' to prune and compact the database, or narrow it down with --since/--limit",
+	"record":     "run 'fp maintenance' to compact the database; slow hooks delay every git command",
+	"export":     "run 'fp maintenance' to verify the export repo; a slow remote also delays pushes",
+	"repos list": "run 'fp maintenance' to compact the database",
+}
+
+// SlowCommandHint returns the message shown when a command is slow, or ""
+// if there is nothing useful to suggest for it.
+func SlowCommandHint(node *dispatchers.DispatchNode, elapsed time.Duration) string {
+	if len(node.Path) < 2 {
+		return ""
+	}
+	name := strings.Join(node.Path[1:], " ")
+
+	hint, ok := slowCommandHints[name]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s took %.1fs - %s", name, elapsed.Seconds(), hint)
+}
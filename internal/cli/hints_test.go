@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlowCommandHint(t *testing.T) {
+	root := buildFullTree(t)
+
+	hint := SlowCommandHint(root.Children["activity"], 6200*time.Millisecond)
+	require.Contains(t, hint, "activity took 6.2s")
+	require.Contains(t, hint, "fp maintenance")
+
+	hint = SlowCommandHint(root.Children["repos"].Children["list"], 7*time.Second)
+	require.Contains(t, hint, "repos list took 7.0s")
+
+	// Commands that are expected to be slow get no hint
+	require.Empty(t, SlowCommandHint(root.Children["backfill"], time.Minute))
+	require.Empty(t, SlowCommandHint(root, time.Minute))
+}
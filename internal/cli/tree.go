@@ -4,6 +4,7 @@ import (
 	"github.com/footprint-tools/cli/internal/actions"
 	completionsactions "github.com/footprint-tools/cli/internal/actions/completions"
 	configactions "github.com/footprint-tools/cli/internal/actions/config"
+	daemonactions "github.com/footprint-tools/cli/internal/actions/daemon"
 	logsactions "github.com/footprint-tools/cli/internal/actions/logs"
 	setupactions "github.com/footprint-tools/cli/internal/actions/setup"
 	themeactions "github.com/footprint-tools/cli/internal/actions/theme"
@@ -38,7 +39,16 @@ func BuildTree() *dispatchers.DispatchNode {
 		Description: `Shows instructions for enabling tab-completion.
 
 Auto-detects your shell if not specified. Use --script to output the
-completion script directly (for use with eval or redirection).`,
+completion script directly (for use with eval or redirection).
+
+Besides commands and flags, the scripts complete values: --repo offers
+the ids of tracked repositories, --branch the branches of the current
+repository, --status and --source the event statuses and sources,
+--device the machines events came from, --since and --until common date
+expressions (today, 7d, "2 weeks ago", recent months) and 'fp theme set'
+the theme names. They are looked up with 'fp completions --values <kind>' each
+time you press Tab, so new repositories show up without regenerating
+the script.`,
 		Usage:    "fp completions [bash|zsh|fish]",
 		Args:     []dispatchers.ArgSpec{{Name: "shell", Description: "Shell type (bash, zsh, fish). Auto-detected if omitted."}},
 		Flags:    CompletionsFlags,
@@ -46,14 +56,22 @@ completion script directly (for use with eval or redirection).`,
 		Category: dispatchers.CategoryPlumbing,
 	})
 
-	addConfigCommands(root)
-	addThemeCommands(root)
-	addTrackingCommands(root)
-	addActivityCommands(root)
-	addSetupCommands(root)
-	addLogsCommand(root)
-	addUpdateCommand(root)
-	addHelpCommand(root)
+	// Each group is built when one of its commands is resolved, so
+	// running fp record doesn't construct the rest of the tree.
+	dispatchers.Lazy(root, []string{"config"}, addConfigCommands)
+	dispatchers.Lazy(root, []string{"theme"}, addThemeCommands)
+	dispatchers.Lazy(root, []string{"repos", "record", "track", "untrack"}, addTrackingCommands)
+	dispatchers.Lazy(root, []string{"hooks"}, addHooksCommands)
+	dispatchers.Lazy(root, []string{"project"}, addProjectCommands)
+	dispatchers.Lazy(root, []string{"activity", "heatmap", "report", "stats", "badge", "query", "watch", "top", "export", "backfill", "import"}, addActivityCommands)
+	dispatchers.Lazy(root, []string{"setup", "init", "unlock", "lock", "status", "doctor", "teardown"}, addSetupCommands)
+	dispatchers.Lazy(root, []string{"logs"}, addLogsCommand)
+	dispatchers.Lazy(root, []string{"daemon"}, addDaemonCommands)
+	dispatchers.Lazy(root, []string{"maintenance"}, addMaintenanceCommand)
+	dispatchers.Lazy(root, []string{"gc"}, addGCCommand)
+	dispatchers.Lazy(root, []string{"dev"}, addDevCommands)
+	dispatchers.Lazy(root, []string{"update"}, addUpdateCommand)
+	dispatchers.Lazy(root, []string{"help"}, addHelpCommand)
 
 	return root
 }
@@ -100,15 +118,21 @@ Common settings:
   theme               Color theme (e.g., neon-dark, ocean-light)
   export_remote       Git remote for syncing exports
   export_interval_sec Seconds between exports (default: 3600)
-  display_date        Date format (dd/mm/yyyy, mm/dd/yyyy, yyyy-mm-dd)
-  display_time        Time format (12h, 24h)
+  export_format       Extra export format: csv, jsonl, parquet, ics, ics-daily
+  date_format         Date format (iso, us, eu, dd.mm.yyyy, locale)
+  time_format         Time format (12h, 24h, locale)
+  week_start          First day of the week (monday, sunday, saturday, locale)
   enable_log          Enable logging (true/false)
 
+Unknown keys and values that don't fit a key (a word where a number goes,
+a format fp doesn't know) are rejected, with the closest match suggested.
+
 Example:
   fp config set theme neon-dark`,
 		Usage:    "fp config set <key> <value>",
 		Args:     ConfigKeyValueArgs,
 		Action:   configactions.Set,
+		Mutating: true,
 		Category: dispatchers.CategoryConfig,
 	})
 
@@ -129,17 +153,89 @@ Use --all to reset all settings to defaults.`,
 			},
 		},
 		Action:   configactions.Unset,
+		Mutating: true,
+		Category: dispatchers.CategoryConfig,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "list",
+		Parent:  config,
+		Summary: "Show all settings",
+		Description: `Prints all current settings from ~/.fprc.
+
+With --defaults, every setting is listed with the value fp uses and its
+default, including optional ones that aren't set.`,
+		Usage:    "fp config list [--defaults] [--json]",
+		Flags:    ConfigListFlags,
+		Action:   configactions.List,
+		Category: dispatchers.CategoryConfig,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "edit",
+		Parent:  config,
+		Summary: "Edit settings in your editor",
+		Description: `Opens the config file in $VISUAL or $EDITOR (vi if neither is set).
+
+When you save and quit, every setting is checked like 'fp config set'
+checks it. If something is wrong, fp lists the problems by line and
+offers to reopen the editor; the config file only changes once the
+edited copy is valid.`,
+		Usage:    "fp config edit",
+		Action:   configactions.Edit,
+		Mutating: true,
+		Category: dispatchers.CategoryConfig,
+	})
+
+	profile := dispatchers.Group(dispatchers.GroupSpec{
+		Name:    "profile",
+		Parent:  config,
+		Summary: "Keep separate footprints, e.g. work and personal",
+		Description: `Profiles keep separate footprints that never mix. Each profile has its
+own settings, database and export repository.
+
+Pick a profile for one command with --profile, or for a shell with
+FP_PROFILE. Without either, fp uses the default profile (~/.fprc).
+
+Git hooks record into the profile whose repos_include matches the
+repository, and into the default profile when none does.
+
+Examples:
+  fp config profile create work
+  fp --profile work config set repos_include ~/work
+  fp --profile work activity
+  FP_PROFILE=work fp export`,
+		Usage: "fp config profile <command>",
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "create",
+		Parent:  profile,
+		Summary: "Create a profile",
+		Description: `Creates a profile. It starts with the default profile's settings,
+except exports, identities, github_token and repos_include/repos_exclude,
+which every profile sets for itself.`,
+		Usage: "fp config profile create <name>",
+		Args: []dispatchers.ArgSpec{
+			{
+				Name:        "name",
+				Description: "Profile name (lowercase letters, digits, - and _)",
+				Required:    true,
+			},
+		},
+		Action:   configactions.ProfileCreate,
+		Mutating: true,
 		Category: dispatchers.CategoryConfig,
 	})
 
 	dispatchers.Command(dispatchers.CommandSpec{
 		Name:        "list",
-		Parent:      config,
-		Summary:     "Show all settings",
-		Description: `Prints all current settings from ~/.fprc.`,
-		Usage:       "fp config list [--json]",
-		Flags:       ConfigListFlags,
-		Action:      configactions.List,
+		Parent:      profile,
+		Summary:     "List profiles",
+		Description: `Lists the profiles, marking the active one with *.`,
+		Usage:       "fp config profile list [--json]",
+		Flags:       ConfigProfileListFlags,
+		Action:      configactions.ProfileList,
 		Category:    dispatchers.CategoryConfig,
 	})
 }
@@ -160,11 +256,30 @@ Available themes (add -dark or -light):
   sunset     Warm orange to purple
   candy      Soft pastels
   contrast   High readability
+  colorblind Okabe-Ito palette, safe for color vision deficiency
+
+Without -dark or -light, the variant follows the terminal background.
+'auto' goes further and picks a different theme for each background,
+theme_dark and theme_light:
+  fp theme set auto
+  fp config set theme_dark neon
+  fp config set theme_light ocean-light
+
+Per-source colors can be overridden on top of any theme:
+  fp config set source_colors.post_commit 74
+  fp config set source_colors.pre_push '#e69f00'
+
+Custom themes are .toml or .json files in the themes directory
+(~/.config/footprint/themes on Linux), named after the file. See
+'fp theme import' for the format.
 
 Examples:
   fp theme list         # Show all themes
   fp theme set neon-dark
-  fp theme -i           # Interactive picker`,
+  fp theme preview ocean-light
+  fp theme pick         # Interactive picker (also fp theme -i)
+  fp theme export neon-dark > mine.toml
+  fp theme import mine.toml`,
 		Usage: "fp theme [command]",
 	})
 
@@ -190,10 +305,99 @@ Examples:
 
 Use -dark for dark terminals, -light for light terminals.
 
+A base name without -dark or -light follows the terminal background.
+Works without a terminal, e.g. from provisioning scripts; an unknown
+name fails with the list of themes.
+
 Example: fp theme set ocean-dark`,
 		Usage:    "fp theme set <name>",
 		Args:     ThemeNameArg,
 		Action:   themeactions.Set,
+		Mutating: true,
+		Category: dispatchers.CategoryTheme,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "preview",
+		Parent:  theme,
+		Summary: "Show a theme's colors",
+		Description: `Prints each color of a theme with its value, without changing the
+current theme.
+
+Swatches are drawn at a terminal. When piped, only the values are printed
+unless --force-color is given.
+
+Examples:
+  fp theme preview neon-dark
+  fp --force-color theme preview ocean-light | less -R`,
+		Usage:    "fp theme preview <name>",
+		Args:     ThemeNameArg,
+		Action:   themeactions.Preview,
+		Category: dispatchers.CategoryTheme,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "pick",
+		Parent:  theme,
+		Summary: "Pick a theme with live preview",
+		Description: `Opens the interactive theme picker: built-in and custom themes on the
+left, the highlighted one previewed on the right. Same as 'fp theme -i'.`,
+		Usage:    "fp theme pick",
+		Action:   themeactions.Interactive,
+		Mutating: true,
+		Category: dispatchers.CategoryTheme,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "export",
+		Parent:  theme,
+		Summary: "Print a theme as a theme file",
+		Description: `Prints a theme as a theme file with every color spelled out, including
+your source_colors.* overrides. Save it to share the theme or to start a
+custom one.
+
+Examples:
+  fp theme export neon-dark > neon-mine.toml
+  fp theme export ocean-light --format json > ocean.json`,
+		Usage:    "fp theme export <name> [--format <toml|json>]",
+		Args:     ThemeNameArg,
+		Flags:    ThemeExportFlags,
+		Action:   themeactions.Export,
+		Category: dispatchers.CategoryTheme,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "import",
+		Parent:  theme,
+		Summary: "Add a custom theme from a file",
+		Description: `Checks a theme file and copies it into the themes directory, where it
+becomes a custom theme named after the file (or --name).
+
+A theme file sets colors by name as ANSI numbers (0-255) or #hex:
+  base = "ocean-dark"      # Colors not set below come from here
+  success = "#56b4e9"
+  post_commit = 74
+
+Colors: success, warning, error, info, muted, header (or "bold"),
+border, ui_active, ui_dim, post_commit, post_rewrite, post_checkout,
+post_merge, pre_push, backfill, manual, branch_create, branch_delete,
+stash. Without base, missing colors come from default-dark. JSON files
+hold the same keys in one object.
+
+Examples:
+  fp theme import ~/Downloads/dracula.toml
+  fp theme import shared.json --name team`,
+		Usage: "fp theme import <file> [--name <name>] [--force]",
+		Args: []dispatchers.ArgSpec{
+			{
+				Name:        "file",
+				Description: "Theme file (.toml or .json)",
+				Required:    true,
+			},
+		},
+		Flags:    ThemeImportFlags,
+		Action:   themeactions.Import,
+		Mutating: true,
 		Category: dispatchers.CategoryTheme,
 	})
 }
@@ -210,8 +414,9 @@ Examples:
   fp repos scan         # Scan and show hook status
   fp repos check        # Verify hooks in current repo
   fp repos -i           # Interactive hook manager
+  fp repos install --root ~/dev    # Install hooks in every repo found
 
-To install/remove hooks, use 'fp setup' and 'fp teardown'.`,
+To install/remove hooks in a single repo, use 'fp track' and 'fp untrack'.`,
 		Usage: "fp repos <command>",
 	})
 
@@ -242,34 +447,255 @@ Examples:
 	})
 
 	dispatchers.Command(dispatchers.CommandSpec{
-		Name:        "check",
-		Parent:      repos,
-		Summary:     "Verify hooks are installed",
-		Description: `Shows which hooks are installed in the current repository.`,
-		Usage:       "fp repos check [--json]",
-		Flags:       ReposCheckFlags,
-		Action:      setupactions.Check,
-		Category:    dispatchers.CategoryInspectActivity,
+		Name:    "install",
+		Parent:  repos,
+		Summary: "Install hooks in every repository under a directory",
+		Description: `Scans like 'fp repos scan' and installs hooks in every repository
+found, without prompting. Repositories that already have fp hooks are
+left alone; repositories managed by Husky, lefthook and similar tools
+are reported as blocked (see 'fp help hooks').
+
+Prints one line per repository and a summary. Exits non-zero if any
+installation failed, so it can be used from provisioning scripts and
+dotfile installers.
+
+Examples:
+  fp repos install --root ~/dev             # Install everywhere under ~/dev
+  fp repos install --root ~/dev --dry-run   # Preview
+  fp repos install --root ~/dev --depth 2 --json`,
+		Usage:    "fp repos install [--root <path>] [--depth <n>] [--dry-run] [--json]",
+		Flags:    ReposBatchFlags,
+		Action:   trackingactions.ReposInstall,
+		Mutating: true,
+		Category: dispatchers.CategoryManageRepos,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "uninstall",
+		Parent:  repos,
+		Summary: "Remove hooks from every repository under a directory",
+		Description: `Scans like 'fp repos scan' and removes fp hooks from every repository
+found, without prompting. Backed up hooks are restored, as with
+'fp teardown'. Recorded events are kept.
+
+Examples:
+  fp repos uninstall --root ~/dev
+  fp repos uninstall --root ~/dev --dry-run`,
+		Usage:    "fp repos uninstall [--root <path>] [--depth <n>] [--dry-run] [--json]",
+		Flags:    ReposBatchFlags,
+		Action:   trackingactions.ReposUninstall,
+		Mutating: true,
+		Category: dispatchers.CategoryManageRepos,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "check",
+		Parent:  repos,
+		Summary: "Verify hooks are installed",
+		Description: `Shows which hooks are installed in the current repository.
+
+--json reports each hook with an "id" (hooks.<name>), "status" and
+"severity", using the same values as 'fp doctor --json'.`,
+		Usage:    "fp repos check [--json]",
+		Flags:    ReposCheckFlags,
+		Action:   setupactions.Check,
+		Category: dispatchers.CategoryInspectActivity,
 	})
 
 	// Interactive mode at group level (no Action = shows help by default)
 	repos.Flags = ReposFlags
 	repos.InteractiveAction = trackingactions.ReposInteractive
 
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "track",
+		Parent:  root,
+		Summary: "Install hooks in one repository and register it",
+		Description: `Checks a repository's hooks, installs fp's and registers the repository
+in the store, then prints what changed. It never prompts: a repository
+whose hooks belong to Husky, pre-commit or lefthook, that has hooks of
+its own, or that sets core.hooksPath is left alone, with what to run
+instead. Exits non-zero when nothing could be installed.
+
+Examples:
+  fp track                      # Current repo
+  fp track ~/projects/myapp     # Specific repo
+  fp track --dry-run            # Preview`,
+		Usage:    "fp track [path] [--dry-run] [--json]",
+		Args:     OptionalRepoPathArg,
+		Flags:    TrackFlags,
+		Action:   trackingactions.Track,
+		Mutating: true,
+		Category: dispatchers.CategoryGetStarted,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "untrack",
+		Parent:  root,
+		Summary: "Remove hooks from one repository and unregister it",
+		Description: `Removes fp's hooks from a repository without prompting, puts back the
+hooks fp backed up and drops the repository from the store. Recorded
+events are kept.
+
+Examples:
+  fp untrack                    # Current repo
+  fp untrack ~/projects/myapp   # Specific repo`,
+		Usage:    "fp untrack [path] [--dry-run] [--json]",
+		Args:     OptionalRepoPathArg,
+		Flags:    TrackFlags,
+		Action:   trackingactions.Untrack,
+		Mutating: true,
+		Category: dispatchers.CategoryManageRepos,
+	})
+
 	dispatchers.Command(dispatchers.CommandSpec{
 		Name:    "record",
 		Parent:  root,
 		Summary: "Save a git event (internal)",
 		Description: `Saves a git event to the database.
 
-This runs automatically via git hooks. You don't need to use it directly.`,
-		Usage:    "fp record",
+This runs automatically via git hooks. You don't need to use it directly,
+except to annotate your work: run by hand, it records the current commit as
+a manual event, and --tag and --note label it for later reporting. Tags and
+notes appear in fp activity, its detail panel, reports and exports.
+
+Examples:
+  fp record --tag pairing
+  fp record --tag client-x --note "Estimate for the billing rework"`,
+		Usage:    "fp record [--tag <label>] [--note <text>]",
 		Flags:    RecordFlags,
 		Action:   trackingactions.Record,
 		Category: dispatchers.CategoryPlumbing,
 	})
 }
 
+func addHooksCommands(root *dispatchers.DispatchNode) {
+	group := dispatchers.Group(dispatchers.GroupSpec{
+		Name:    "hooks",
+		Parent:  root,
+		Summary: "Maintain installed hooks",
+		Description: `Maintain the git hooks fp installed.
+
+To install or remove hooks, use 'fp setup', 'fp teardown' and
+'fp repos install'. For how hooks work, see 'fp help hooks'.`,
+		Usage: "fp hooks <command>",
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "upgrade",
+		Parent:  group,
+		Summary: "Rewrite hooks installed by an older fp",
+		Description: `Compares each installed fp hook with the script this fp writes and
+rewrites the ones that differ: hooks from an older fp version, or that
+point to an fp binary that has since moved. Chained and global hooks stay
+chained and global. Hooks fp didn't install are never touched.
+
+Without --all, upgrades the current repository. With --all, upgrades
+every tracked repository, every repository with fp hooks found under
+--root, and the global hooks directory if fp manages it, then prints
+one line per repository and a summary. Exits non-zero if any upgrade
+failed.
+
+Examples:
+  fp hooks upgrade                     # Current repository
+  fp hooks upgrade --all --dry-run     # Preview
+  fp hooks upgrade --all --root ~/dev --json`,
+		Usage:    "fp hooks upgrade [--all] [--root <path>] [--depth <n>] [--dry-run] [--json]",
+		Flags:    HooksUpgradeFlags,
+		Action:   trackingactions.HooksUpgrade,
+		Mutating: true,
+		Category: dispatchers.CategoryManageRepos,
+	})
+}
+
+func addProjectCommands(root *dispatchers.DispatchNode) {
+	project := dispatchers.Group(dispatchers.GroupSpec{
+		Name:    "project",
+		Parent:  root,
+		Summary: "Group repositories into projects",
+		Description: `Groups repositories into projects, such as all the repositories of one
+client, so activity and stats can be read per project. Repositories are
+kept by id, so every clone of one belongs to the project; a repository
+can be in several projects.
+
+'fp activity --project <name>' and 'fp stats --project <name>' then
+cover only the project's repositories, and 'fp project list' sums up the
+activity of each project.
+
+Examples:
+  fp project create acme
+  fp project add-repo acme ~/src/acme-api ~/src/acme-web
+  fp project add-repo acme                    # The repository you are in
+  fp project list --since 2025-06-01          # Events and days per project
+  fp activity --project acme --since 7d
+  fp stats --project acme --since "1 month ago"`,
+		Usage: "fp project <command>",
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:        "create",
+		Parent:      project,
+		Summary:     "Create a project",
+		Description: `Creates an empty project. Add repositories to it with 'fp project add-repo'.`,
+		Usage:       "fp project create <name>",
+		Args:        ProjectNameArg,
+		Action:      trackingactions.ProjectCreate,
+		Mutating:    true,
+		Category:    dispatchers.CategoryManageRepos,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "add-repo",
+		Parent:  project,
+		Summary: "Add repositories to a project",
+		Description: `Adds the repositories at the given paths to a project, or the one in
+the current directory when no path is given. A repository that isn't on
+this machine, such as one whose events were imported, can be added by
+the id 'fp activity' shows for it.`,
+		Usage:    "fp project add-repo <name> [<path|id>...]",
+		Args:     ProjectAddRepoArgs,
+		Action:   trackingactions.ProjectAddRepo,
+		Mutating: true,
+		Category: dispatchers.CategoryManageRepos,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:        "remove-repo",
+		Parent:      project,
+		Summary:     "Remove repositories from a project",
+		Description: `Removes repositories, given by path or id, from a project. Their events are kept.`,
+		Usage:       "fp project remove-repo <name> <path|id>...",
+		Args:        ProjectRemoveRepoArgs,
+		Action:      trackingactions.ProjectRemoveRepo,
+		Mutating:    true,
+		Category:    dispatchers.CategoryManageRepos,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "list",
+		Parent:  project,
+		Summary: "List projects and their activity",
+		Description: `Shows each project with its repositories, how many events they
+recorded, on how many days, and when the latest one was. --since and
+--until limit the counts to a period, such as a billing month.`,
+		Usage:    "fp project list [--since <date>] [--until <date>] [--json]",
+		Flags:    ProjectListFlags,
+		Action:   trackingactions.ProjectList,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:        "delete",
+		Parent:      project,
+		Summary:     "Delete a project",
+		Description: `Deletes a project and its list of repositories. Events are kept.`,
+		Usage:       "fp project delete <name>",
+		Args:        ProjectNameArg,
+		Action:      trackingactions.ProjectDelete,
+		Mutating:    true,
+		Category:    dispatchers.CategoryManageRepos,
+	})
+}
+
 func addActivityCommands(root *dispatchers.DispatchNode) {
 	dispatchers.Command(dispatchers.CommandSpec{
 		Name:    "activity",
@@ -279,19 +705,202 @@ func addActivityCommands(root *dispatchers.DispatchNode) {
 
 Each entry shows: time, event type, repository, commit/branch info.
 
+Events also remember the directory they were recorded from, relative to
+the repository root; --path keeps those run in a directory or below it,
+which helps in monorepos.
+
+--since and --until take YYYY-MM-DD or an expression relative to today:
+today, yesterday, 7d, 2w, 3m, "2 weeks ago", or a month name for the
+first of that month.
+
+Each event also names the machine it was recorded on: the device_name
+config key, or the hostname when it isn't set. --device keeps the events
+from one machine, which helps once exports from several are imported.
+
+--search keeps events whose commit message (subject or body) or note
+contains every word of the query. Messages are stored when an event is recorded, so search doesn't run git; events
+recorded before that are found by subject after 'fp backfill'. Searches
+use a full-text index when fp is built with -tags sqlite_fts5.
+
+In the interactive viewer, press / to search messages the same way, and
+n in an event's detail panel to add or edit a note on it ("this was the
+prod hotfix"). Notes are searchable, and appear in --json output, reports
+and exports. The sidebar counts events per device; press d there to step
+through them as a filter.
+
+With --enrich --json, events also carry the fields the enrichers derived
+from their commit when it was recorded (author, ticket id, lines changed
+per language, and any enrich_exec.<name> commands); see the enrichers
+config key.
+
 Examples:
   fp activity           # Recent events
   fp activity -i        # Interactive viewer with filtering
   fp activity -50       # Show 50 events (shorthand for -n 50)
   fp activity -e        # Include commit messages
   fp activity --json    # Output as JSON
-  fp activity --repo github.com/user/project  # One repo only`,
+  fp activity --repo github.com/user/project  # One repo only
+  fp activity --path services/api             # One monorepo package
+  fp activity --device work-laptop            # One machine only
+  fp activity --project acme                  # A project's repositories
+  fp activity --search "login redirect"       # Commit messages and notes
+  fp activity --since 2025-06-02 --group-by repo  # Events per repo this week
+  fp activity --since "2 weeks ago"           # The last two weeks`,
 		Usage:    "fp activity [options]",
 		Action:   trackingactions.Activity,
 		Flags:    ActivityFlags,
 		Category: dispatchers.CategoryInspectActivity,
 	})
 
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "heatmap",
+		Parent:  root,
+		Summary: "Browse a contribution calendar",
+		Description: `Shows a GitHub-style contribution calendar of your git activity.
+
+Each cell is one day, shaded by how many events were recorded.
+Press Enter on a day to see its commits, [ and ] to change year,
+and Tab to focus the repo list and filter by repository.
+
+Examples:
+  fp heatmap               # Current year, all repos
+  fp heatmap --year 2024   # Start on a different year
+  fp heatmap --repo github.com/user/project  # One repo only`,
+		Usage:    "fp heatmap [--year <yyyy>] [--repo <id>]",
+		Action:   trackingactions.Heatmap,
+		Flags:    HeatmapFlags,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "report",
+		Parent:  root,
+		Summary: "Generate a shareable HTML report or a weekly summary",
+		Description: `Writes a static HTML report of your activity: a contribution
+calendar, charts by event type, weekday and hour, and a table per
+repository. It also gives context switches per active day (see 'fp stats')
+and lists the worst days when context_switch_threshold is set.
+
+The report is a single self-contained file (CSS and JS are inlined, no
+external requests), so it can be emailed or hosted anywhere and opened
+without fp or a server. Colors follow your theme and source_colors.*.
+
+Covers the last 12 months unless --since is given.
+
+--week prints a summary of one week (Monday to Sunday) instead, for
+standups or invoicing: commits and pushes per repository, diff stats,
+merges, tags and streaks. It covers the week containing --until, this week
+by default. --format picks plain text (txt, the default), Markdown (md) or
+HTML, and --email-stdout adds the headers of an email message, so the
+output can be piped to sendmail. Diff stats need the repositories on disk.
+
+Examples:
+  fp report --html out/                    # Writes out/index.html
+  fp report --html activity.html           # Writes a single file
+  fp report --html out/ --since 2025-01-01 --until 2025-12-31
+  fp report --html out/ --repo github.com/user/project
+  fp report --week                         # This week, as plain text
+  fp report --week --until 2025-03-14 --format md
+  fp report --week --email-stdout | sendmail me@example.com`,
+		Usage:    "fp report (--html <dir> | --week [--format txt|md|html] [--email-stdout]) [--since <date>] [--until <date>] [--repo <id>]",
+		Action:   trackingactions.Report,
+		Flags:    ReportFlags,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "stats",
+		Parent:  root,
+		Summary: "Show focus metrics per day",
+		Description: `Shows, for each day with activity, how many events were recorded,
+how many repositories they touched, and how many context switches there
+were: events in a different repository than the event just before them
+on the same day. Days that jump between many repositories tend to be
+days with little deep work.
+
+Covers the last 7 days unless --since is given. Set
+context_switch_threshold to call out days with more switches than that,
+here and in 'fp report'. --device limits the count to one machine, and
+--project to the repositories of a project (see 'fp project').
+
+--by-language shows the lines the period's commits added and removed per
+language instead, named from file extensions, each commit counted once.
+fp record and fp backfill store them; commits recorded before fp kept
+them are counted apart until 'fp backfill' fills them in. The heatmap's
+day view lists the same for one day.
+
+Examples:
+  fp stats                                  # The last 7 days
+  fp stats --since 2025-06-01 --until 2025-06-30
+  fp stats --device work-laptop             # One machine only
+  fp stats --project acme --since 2025-06-01
+  fp stats --by-language --since "1 month ago"  # Go vs YAML vs docs
+  fp stats --json
+  fp config set context_switch_threshold 10 # Flag busy days`,
+		Usage:    "fp stats [--since <date>] [--until <date>] [--device <name>] [--project <name>] [--mine] [--by-language] [--json]",
+		Action:   trackingactions.Stats,
+		Flags:    StatsFlags,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "badge",
+		Parent:  root,
+		Summary: "Generate an SVG activity badge for a README",
+		Description: `Writes a small SVG badge for one repository, in the same style as
+shields.io badges, computed from the local database. Commit it next to
+your README and regenerate it when you like (for example from a hook or
+a cron job).
+
+The repository is a path (defaults to the current directory) or a
+repository id as shown by 'fp repos list'.
+
+Metrics:
+  commits          Commits this month (default)
+  last-activity    Date of the most recent event
+
+Examples:
+  fp badge --out badge.svg
+  fp badge ~/projects/myapp --metric last-activity --out activity.svg
+  fp badge github.com/user/project > badge.svg
+
+Then reference it from the README:
+  ![activity](badge.svg)`,
+		Usage:    "fp badge [repo] [--out <file>] [--metric commits|last-activity]",
+		Args:     BadgeRepoArg,
+		Flags:    BadgeFlags,
+		Action:   trackingactions.Badge,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "query",
+		Parent:  root,
+		Summary: "Run SQL against your activity",
+		Description: `Runs an ad-hoc SQL query against the local database and prints the
+result as a table (or JSON with --json).
+
+The database is opened read-only: queries can't change anything, and
+they don't block the hooks from recording.
+
+Views:
+  events   id, repo_id, repo_path, commit_hash, branch, timestamp,
+           status, source, cwd, note, device
+  repos    repo_id, repo_path, added_at, last_seen
+
+The underlying tables (repo_events, tracked_repos, ...) can be queried too.
+
+Examples:
+  fp query "SELECT repo_id, count(*) AS commits FROM events GROUP BY 1 ORDER BY 2 DESC"
+  fp query "SELECT date(timestamp) AS day, count(*) FROM events GROUP BY day" --json
+  fp query "SELECT * FROM events WHERE branch LIKE 'feature/%' LIMIT 20"`,
+		Usage:    "fp query <sql> [--json]",
+		Args:     QuerySQLArg,
+		Flags:    QueryFlags,
+		Action:   trackingactions.Query,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
 	dispatchers.Command(dispatchers.CommandSpec{
 		Name:    "watch",
 		Parent:  root,
@@ -301,9 +910,27 @@ Examples:
 Events appear as you make commits, switch branches, etc.
 Press Ctrl+C to stop.
 
+Use --plain to pipe events into other tools: each event is one
+uncolored line of tab-separated fields (timestamp, source, commit,
+repository, branch, plus the subject with --enrich). Add --json for
+one JSON object per line instead.
+
 Examples:
-  fp watch       # Stream events live
-  fp watch -i    # Interactive dashboard with stats`,
+  fp watch                        # Stream events live
+  fp watch --plain | cut -f4      # Repository of each new event
+  fp watch --plain --json | jq .  # JSON lines for scripts
+  fp watch -i                     # Interactive dashboard with stats
+  fp watch --root ~/code          # Also flag activity fp isn't recording
+
+With --root fp also rescans a directory every few seconds and reports
+what its hooks can't: repositories appearing under it, and branches
+created or commits made in repositories without fp hooks, marked
+untracked with the 'fp setup' command that starts recording them.
+
+Set watch_alert to bell or flash to be told about events while watch
+runs in a background pane: the dashboard rings the bell or inverts its
+header when an event arrives while its window is unfocused. The stream
+can't tell whether it has focus, so with bell it rings for every event.`,
 		Usage:    "fp watch [options]",
 		Action:   trackingactions.Log,
 		Flags:    WatchFlags,
@@ -311,6 +938,36 @@ Examples:
 	})
 
 	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "top",
+		Parent:  root,
+		Summary: "Live dashboard of today's activity and export state",
+		Description: `Shows a dashboard that refreshes every 2 seconds, like top for your
+coding activity:
+
+  - Today's events, lines added and removed, and active repositories
+  - Each repository's events over the last 14 days as a sparkline,
+    busiest today first
+  - Events waiting to be exported, when fp last exported and when it
+    exports next
+  - Whether the export repo's commits reached its remote
+
+It reads the daily totals, so it stays light however many events there
+are. The push state is the export repo's as of its last push, without
+contacting the remote; run 'fp export --verify-remote' to check it.
+
+Press p to pause, r to refresh now and q to quit. With --accessible it
+prints the same information once.
+
+Examples:
+  fp top               # Refresh every 2 seconds
+  fp top --interval 10 # Refresh every 10 seconds`,
+		Usage:    "fp top [--interval <seconds>]",
+		Action:   trackingactions.Top,
+		Flags:    TopFlags,
+		Category: dispatchers.CategoryInspectActivity,
+	})
+
+	export := dispatchers.Command(dispatchers.CommandSpec{
 		Name:    "export",
 		Parent:  root,
 		Summary: "Export events to CSV (internal)",
@@ -321,14 +978,165 @@ You usually don't need this - exports happen in the background.
 Use --now to export immediately (skip the hourly interval).
 Use --open to view the export folder.
 Use --dry-run to preview without exporting.
+Use --format to also write JSONL or Parquet files next to the CSVs
+(commits.jsonl, commits.parquet). Set export_format to make it permanent.
+--format ics writes an iCalendar file (commits.ics) with an entry per
+commit to subscribe to or import in a calendar; ics-daily writes one
+entry per day instead.
+
+Set export_http_url to POST events to an HTTPS endpoint instead, or
+export_sinks (e.g. git,http,s3) to send events to several destinations.
+Use 'fp export remotes' to push the export repo to mirrors as well.
+
+When a push fails, fp says why (credentials, host key, missing
+repository, rejected or unreachable) and how to fix it. Use
+--verify-remote to test every remote's connection and credentials
+without exporting: it runs git ls-remote and git push --dry-run with
+prompts turned off, as background exports do.
+
+The export repo gains a commit per export. Use --squash to rewrite its
+history into a single snapshot commit of the current files and
+force-push it (with a lease, so nothing pushed from another machine in
+the meantime is lost); fp checks the remote took it and keeps the old
+history if not. Set export_squash_days to have 'fp maintenance' do it
+once the history is that old. Other machines pick up the snapshot on
+their next export.
+
+Use --sqlite <file> to write a single read-only SQLite file for others
+to query, or to attach in DuckDB. It holds an events table with the CSV
+columns, one row per commit, indexed by timestamp, repo_id and
+commit_hash, and the same redaction. It covers all events, not just
+pending ones, narrowed by --since, --until, --repo and --mine, and
+doesn't count as an export.
+
+Examples:
+  fp export --now
+  fp export --sqlite footprint-snapshot.db --since 2026-01-01
+  fp export --squash --dry-run
+  fp export --verify-remote
 
 Export location: ~/.config/Footprint/exports`,
-		Usage:    "fp export [--now] [--dry-run] [--open]",
+		Usage:    "fp export [--now] [--dry-run] [--open] [--verify-remote] [--squash] [--format <csv|jsonl|parquet|ics|ics-daily>] [--sqlite <file>]",
 		Action:   trackingactions.Export,
 		Flags:    ExportFlags,
 		Category: dispatchers.CategoryPlumbing,
 	})
 
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "repair",
+		Parent:  export,
+		Summary: "Fix a broken export repository",
+		Description: `Diagnoses the export repository and recovers from states that make
+'fp export' fail: an unfinished merge, rebase or cherry-pick, or a
+detached HEAD.
+
+Merges are retried with automatic CSV conflict resolution and aborted
+if that fails. A detached HEAD is moved back onto a branch, keeping any
+records that only exist in the working tree.
+
+Use --force-reclone to move the repository aside and re-create it from
+the remote. Records from the local CSVs are merged back in.
+
+Examples:
+  fp export repair                   # Diagnose and fix
+  fp export repair --dry-run         # Only diagnose
+  fp export repair --force-reclone   # Start over from the remote`,
+		Usage:    "fp export repair [--dry-run] [--force-reclone]",
+		Flags:    ExportRepairFlags,
+		Action:   trackingactions.ExportRepair,
+		Mutating: true,
+		Category: dispatchers.CategoryPlumbing,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "schedule",
+		Parent:  export,
+		Summary: "Show when the next automatic export runs",
+		Description: `Shows the export interval (export_interval_sec), when fp last exported
+(export_last), when the next automatic export is due, and what will run
+it: the daemon, which checks every minute while it runs, or otherwise
+the git hooks, at the first event recorded once the export is due.
+
+Examples:
+  fp export schedule
+  fp export schedule --json`,
+		Usage:    "fp export schedule [--json]",
+		Flags:    ExportScheduleFlags,
+		Action:   trackingactions.ExportSchedule,
+		Category: dispatchers.CategoryPlumbing,
+	})
+
+	remotes := dispatchers.Group(dispatchers.GroupSpec{
+		Name:    "remotes",
+		Parent:  export,
+		Summary: "Manage the remotes exports are pushed to",
+		Description: `Exports are pushed to the primary remote (export_remote, named origin)
+and then to every mirror, such as a self-hosted Gitea next to GitHub.
+An export counts as pushed once origin has it; a mirror that fails is
+reported and catches up on the next push.
+
+Mirrors are stored in export_mirrors as name=url pairs.
+
+Examples:
+  fp export remotes add gitea https://git.example.com/me/activity.git
+  fp export remotes list
+  fp export remotes remove gitea`,
+		Usage: "fp export remotes <command>",
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "list",
+		Parent:  remotes,
+		Summary: "List export remotes",
+		Description: `Lists origin and the mirrors with their URLs, and how many export
+commits each is missing as of the last push.`,
+		Usage:    "fp export remotes list [--json]",
+		Flags:    ExportRemotesListFlags,
+		Action:   trackingactions.ExportRemotesList,
+		Category: dispatchers.CategoryPlumbing,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:        "add",
+		Parent:      remotes,
+		Summary:     "Add a mirror remote",
+		Description: `Adds a mirror that every export is also pushed to.`,
+		Usage:       "fp export remotes add <name> <url>",
+		Args: []dispatchers.ArgSpec{
+			{
+				Name:        "name",
+				Description: "Mirror name (letters, digits, - and _)",
+				Required:    true,
+			},
+			{
+				Name:        "url",
+				Description: "Git remote URL",
+				Required:    true,
+			},
+		},
+		Action:   trackingactions.ExportRemotesAdd,
+		Mutating: true,
+		Category: dispatchers.CategoryPlumbing,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:        "remove",
+		Parent:      remotes,
+		Summary:     "Remove a mirror remote",
+		Description: `Stops pushing exports to a mirror and removes its git remote.`,
+		Usage:       "fp export remotes remove <name>",
+		Args: []dispatchers.ArgSpec{
+			{
+				Name:        "name",
+				Description: "Mirror name",
+				Required:    true,
+			},
+		},
+		Action:   trackingactions.ExportRemotesRemove,
+		Mutating: true,
+		Category: dispatchers.CategoryPlumbing,
+	})
+
 	dispatchers.Command(dispatchers.CommandSpec{
 		Name:    "backfill",
 		Parent:  root,
@@ -338,15 +1146,68 @@ Export location: ~/.config/Footprint/exports`,
 Scans git history and adds each commit to the database.
 Duplicates are skipped automatically.
 
+Use --all to backfill every tracked repository (see 'fp repos list').
+Date and limit filters then apply to each repository separately.
+
+When local history is gone (squashed, rebased away, or an old clone
+deleted), --from-github owner/repo imports your commits through the GitHub
+API instead, from the default branch unless --branch names another. The
+token comes from the github_token config key or GITHUB_TOKEN; public
+repositories work without one except for --author me, the default, which
+asks GitHub whose token it is. Imported commits carry remote.source=github
+and remote.url fields (see 'fp activity --enrich --json').
+
 Examples:
   fp backfill                     # Import all past commits
+  fp backfill --all               # Every tracked repository
   fp backfill --since 2024-01-01  # From a specific date
+  fp backfill --since 6m          # The last six months
   fp backfill --limit 100         # Only last 100 commits
-  fp backfill --dry-run           # Preview without importing`,
-		Usage:    "fp backfill [path] [--since=<date>] [--until=<date>] [--limit=<n>]",
+  fp backfill --dry-run           # Preview without importing
+  fp backfill --from-github me/app --since 2023-01-01`,
+		Usage:    "fp backfill [path | --all | --from-github=<owner/repo> [--author=<login>]] [--since=<date>] [--until=<date>] [--limit=<n>]",
 		Args:     OptionalRepoPathArg,
 		Flags:    BackfillFlags,
 		Action:   trackingactions.Backfill,
+		Mutating: true,
+		Category: dispatchers.CategoryManageRepos,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "import",
+		Parent:  root,
+		Summary: "Import events exported on another machine",
+		Description: `Reads a commits.csv (or exported JSONL) from another machine and adds
+its events to the local database. Use it to consolidate history from
+several computers in one place.
+
+Pass a single file or a directory such as a copy of another export
+repository. Commits already stored for the same repository are skipped,
+so importing the same file again is safe.
+
+Encrypted exports (commits.csv.age, commits.csv.gpg, see
+export_encrypt_recipient) are decrypted with age and
+export_encrypt_identity, or with gpg and its keyring.
+
+Imported events keep the source they were recorded with (exports made
+before the source column come in as backfill events). They were exported
+already, by the machine that recorded them, so they are stored as
+exported: exporting them again from here would overwrite that machine's
+rows with ones missing the commit details git can't read on this one.
+--pending includes them in the next export anyway, say to move a
+machine's history into a new export repository.
+
+Examples:
+  fp import ~/work-laptop/commits.csv
+  fp import ~/work-laptop/exports          # Every CSV in the folder
+  fp import commits.csv.gpg                # Decrypts with gpg
+  fp import events.jsonl --dry-run
+  fp import old-laptop.csv --pending       # Export them from here too`,
+		Usage:    "fp import <file-or-dir> [--dry-run] [--pending] [--json]",
+		Args:     ImportPathArg,
+		Flags:    ImportFlags,
+		Action:   trackingactions.Import,
+		Mutating: true,
 		Category: dispatchers.CategoryManageRepos,
 	})
 }
@@ -361,21 +1222,156 @@ func addSetupCommands(root *dispatchers.DispatchNode) {
 After setup, every commit, merge, checkout, rebase, and push in this
 repo will be tracked. Run this once per repository.
 
-Existing hooks are backed up before installation.
+Existing hooks are backed up before installation. With --chain they
+keep running instead: fp's hook calls the original first, then records.
+'fp teardown' puts the originals back either way.
+
+In a bare repository, as on a git server, setup installs a post-receive
+hook that records the branches each push updates.
 
 Examples:
   fp setup                     # Install in current repo
   fp setup ~/projects/myapp    # Install in specific repo
-  fp setup --core-hooks-path   # Set global hooks (see note below)
-
-The --core-hooks-path flag sets git's global core.hooksPath. This works
-for repos WITHOUT their own core.hooksPath setting. Repos with local
-core.hooksPath (like Husky) will ignore the global setting - for those,
-integrate manually by adding 'fp record <hook>' to their hooks.`,
-		Usage:    "fp setup [path] [--core-hooks-path] [--force] [--dry-run]",
+  fp setup --chain             # Keep existing hooks, run fp after them
+  fp setup --global            # Track every repo on this machine
+
+--global installs fp hooks in a directory fp owns and points git's global
+core.hooksPath at it, so every repo is tracked without running setup in
+each. Hooks in a repo's own .git/hooks keep running: fp's hooks call them
+first. Repos with a local core.hooksPath (like Husky) ignore the global
+setting - for those, add 'fp record <hook>' to their hooks. --global was
+called --core-hooks-path, which still works.`,
+		Usage:    "fp setup [path] [--global] [--chain] [--force] [--dry-run]",
 		Args:     OptionalRepoPathArg,
 		Flags:    SetupFlags,
 		Action:   setupactions.Setup,
+		Mutating: true,
+		Category: dispatchers.CategoryGetStarted,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "init",
+		Parent:  root,
+		Summary: "Create the database, optionally encrypted",
+		Description: `Creates the database if it doesn't exist yet and prints where it is.
+
+With --encrypt, the database is encrypted at rest, for machines other
+people can log into. The events are sealed with AES-256-GCM in
+store.db.enc, next to where store.db was, and store.db is removed. fp
+works on an unlocked copy in a directory only you can read: under
+$XDG_RUNTIME_DIR, which is cleared when you log out, or in your temp
+directory. Each command writes its changes back to store.db.enc when it
+finishes, and the daemon does on each round.
+
+The key is kept in the OS keychain (the login keychain on macOS, the
+Secret Service on Linux), so the hooks unlock the database on their own.
+With --passphrase, or when there is no keychain, the key is derived from
+a passphrase instead: fp asks for it when it needs to unlock, reads
+FP_DB_PASSPHRASE where it can't ask, and the hooks don't record until
+it is unlocked ('fp unlock').
+
+The passphrase can't be recovered. Forgetting it loses the database.
+
+Examples:
+  fp init                          # Create the database
+  fp init --encrypt                # Encrypt it, key in the OS keychain
+  fp init --encrypt --passphrase   # Encrypt it with a passphrase`,
+		Usage:    "fp init [--encrypt] [--passphrase]",
+		Flags:    InitFlags,
+		Action:   setupactions.Init,
+		Mutating: true,
+		Category: dispatchers.CategoryGetStarted,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "unlock",
+		Parent:  root,
+		Summary: "Unlock the encrypted database",
+		Description: `Decrypts the database set up with 'fp init --encrypt' into its
+unlocked copy, asking for the passphrase if it needs one. Run it after
+logging in so the hooks can record: they can't ask for a passphrase.
+
+Any other command unlocks the database too when it needs it.`,
+		Usage:    "fp unlock",
+		Action:   setupactions.Unlock,
+		Category: dispatchers.CategoryConfig,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "lock",
+		Parent:  root,
+		Summary: "Lock the encrypted database",
+		Description: `Writes the unlocked copy of an encrypted database back to
+store.db.enc and removes it, along with the key it was opened with.
+Until it is unlocked again, nothing can read the events.
+
+Run it before leaving a shared machine. Logging out clears the unlocked
+copy too where it lives under $XDG_RUNTIME_DIR.`,
+		Usage:    "fp lock",
+		Action:   setupactions.Lock,
+		Category: dispatchers.CategoryConfig,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "status",
+		Parent:  root,
+		Summary: "Check that fp is set up",
+		Description: `Shows a checklist of the setup steps fp needs: git, the database,
+tracked repositories, up-to-date hooks, recorded activity, and an
+export remote. Anything missing is listed with the exact commands to
+run next.
+
+The export remote is optional and marked with ○ when not configured.
+
+Inside a repository, the checklist is followed by its branch, last
+recorded event, events pending export, and the state of each hook:
+current, outdated (written by another fp version or binary path),
+foreign (not installed by fp) or missing. --all shows every tracked
+repository instead.
+
+Examples:
+  fp status           # Show the checklist
+  fp status --all     # Hooks and events of every tracked repository
+  fp status --json    # Machine-readable output`,
+		Usage:    "fp status [--all] [--json]",
+		Flags:    StatusFlags,
+		Action:   trackingactions.Status,
+		Category: dispatchers.CategoryGetStarted,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "doctor",
+		Parent:  root,
+		Summary: "Diagnose problems with fp",
+		Description: `Runs a battery of checks and reports each as pass, warn, or fail:
+
+  git          git is installed
+  hooks        installed hooks run this fp binary (global, current
+               repo, and every tracked repo)
+  database     PRAGMA integrity_check passes
+  export repo  no merge, rebase, or detached HEAD left behind
+  remote       the export remote answers (git ls-remote)
+  config       ~/.fprc parses and holds valid values
+  lockfiles    no lock or pid files left by crashed processes
+
+With --fix, outdated hooks are rewritten, the export repo is repaired
+(as 'fp export repair' would), and stale lockfiles are removed.
+Everything else is reported with the command to fix it by hand.
+
+Exits with status 1 when a check fails.
+
+--json prints one object per check with a stable "id" (the names above,
+with export_repo and export_remote for the export checks), "status"
+(pass, warn, fail), "severity" (info, warning, error), "fixable" and
+"hint", for configuration management tools to act on.
+
+Examples:
+  fp doctor           # Run all checks
+  fp doctor --fix     # Fix what can be fixed automatically
+  fp doctor --json    # Machine-readable output`,
+		Usage:    "fp doctor [--fix] [--json]",
+		Flags:    DoctorFlags,
+		Action:   trackingactions.Doctor,
 		Category: dispatchers.CategoryGetStarted,
 	})
 
@@ -390,11 +1386,12 @@ If you had hooks before fp, they will be restored from backup.
 Examples:
   fp teardown                     # Remove from current repo
   fp teardown ~/projects/myapp    # Remove from specific repo
-  fp teardown --core-hooks-path   # Remove global hooks, unset core.hooksPath`,
-		Usage:    "fp teardown [path] [--core-hooks-path] [--force] [--dry-run]",
+  fp teardown --global            # Remove global hooks, restore core.hooksPath`,
+		Usage:    "fp teardown [path] [--global] [--force] [--dry-run]",
 		Args:     OptionalRepoPathArg,
 		Flags:    TeardownFlags,
 		Action:   setupactions.Teardown,
+		Mutating: true,
 		Category: dispatchers.CategoryManageRepos,
 	})
 }
@@ -406,19 +1403,188 @@ func addLogsCommand(root *dispatchers.DispatchNode) {
 		Summary: "View fp logs",
 		Description: `Shows fp's log file (useful for debugging).
 
+Entries are written one per line as key=value pairs (time, level, caller
+and msg) and shown here laid out for reading, colored by level. --json
+prints them as objects instead.
+
 Examples:
-  fp logs              # Last 50 lines
-  fp logs -n 100       # Last 100 lines
-  fp logs -f           # Follow in real time
-  fp logs -i           # Interactive viewer
-  fp logs --clear      # Delete log file`,
-		Usage:    "fp logs [-i] [-n <lines>] [--tail] [--clear]",
+  fp logs                  # Last 50 lines
+  fp logs --tail 100       # Last 100 lines
+  fp logs --level warn     # Only warnings and errors
+  fp logs -f --level error # Follow errors in real time
+  fp logs -i               # Interactive viewer
+  fp logs --clear          # Delete log file`,
+		Usage:    "fp logs [-i] [--tail <n>] [--follow] [--level <level>] [--json] [--clear]",
 		Flags:    LogsFlags,
 		Action:   logsAction,
 		Category: dispatchers.CategoryInspectActivity,
 	})
 }
 
+func addDaemonCommands(root *dispatchers.DispatchNode) {
+	daemon := dispatchers.Group(dispatchers.GroupSpec{
+		Name:    "daemon",
+		Parent:  root,
+		Summary: "Run exports in the background",
+		Description: `Runs a long-lived background process that exports pending events
+on the configured interval (export_interval_sec), checks for updates,
+and runs 'fp maintenance' once a week.
+
+While the daemon is running, git hooks only record events and leave
+exporting to the daemon. Activity is logged to the fp log (see 'fp logs').
+
+Examples:
+  fp daemon start               # Start in the background
+  fp daemon start --foreground  # Run in this terminal
+  fp daemon status              # Check if it's running
+  fp daemon stop                # Stop it`,
+		Usage: "fp daemon <command>",
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "start",
+		Parent:  daemon,
+		Summary: "Start the background daemon",
+		Description: `Starts the daemon in the background and writes its pid to daemon.pid
+in the fp data directory. Does nothing if it's already running.
+
+Use --foreground to run in the current terminal (Ctrl+C to stop).`,
+		Usage:    "fp daemon start [--foreground]",
+		Flags:    DaemonStartFlags,
+		Action:   daemonactions.Start,
+		Mutating: true,
+		Category: dispatchers.CategoryPlumbing,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:        "stop",
+		Parent:      daemon,
+		Summary:     "Stop the background daemon",
+		Description: `Stops the running daemon and removes its pidfile.`,
+		Usage:       "fp daemon stop",
+		Action:      daemonactions.Stop,
+		Mutating:    true,
+		Category:    dispatchers.CategoryPlumbing,
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:        "status",
+		Parent:      daemon,
+		Summary:     "Show whether the daemon is running",
+		Description: `Shows whether the daemon is running and its pid.`,
+		Usage:       "fp daemon status [--json]",
+		Flags:       DaemonStatusFlags,
+		Action:      daemonactions.Status,
+		Category:    dispatchers.CategoryPlumbing,
+	})
+}
+
+func addMaintenanceCommand(root *dispatchers.DispatchNode) {
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "maintenance",
+		Parent:  root,
+		Summary: "Run routine housekeeping",
+		Description: `Runs all housekeeping tasks in one go and prints a summary:
+
+  prune     Delete exported events older than retention_days (if set)
+  orphans   Delete events from repositories that no longer exist
+  meta      Delete stored metadata of commits without events
+  pushes    Settle whether recorded pushes went through or were
+            rejected, for pushes no later hook settled
+  vacuum    Compact the database file
+  logs      Rotate the log file once it grows past 10 MB
+  compact   Rewrite export CSVs that exports appended to, sorted and
+            without duplicate rows, and commit them
+  squash    Squash the export repo's history into one commit once it
+            is older than export_squash_days (if set)
+  export    Check the export repo and its CSV files are readable
+
+Pending events are never pruned. A failing task doesn't stop the others.
+The daemon runs maintenance automatically once a week.
+
+Examples:
+  fp maintenance          # Run everything
+  fp maintenance --json   # Machine-readable summary`,
+		Usage:    "fp maintenance [--json]",
+		Flags:    MaintenanceFlags,
+		Action:   trackingactions.Maintenance,
+		Mutating: true,
+		Category: dispatchers.CategoryPlumbing,
+	})
+}
+
+func addGCCommand(root *dispatchers.DispatchNode) {
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "gc",
+		Parent:  root,
+		Summary: "Free disk space used by fp",
+		Description: `Deletes what fp no longer needs and prints the space reclaimed:
+
+  prune     Delete exported events older than retention_days (if set)
+  orphans   Delete events from repositories that no longer exist
+  meta      Delete stored metadata of commits without events
+  rollups   Recount the daily totals fp heatmap reads, as after a move
+            to another time zone
+  vacuum    Compact the database file
+  logs      Move the log to fp.log.1, replacing the previous one
+
+Pending events are never pruned. A failing task doesn't stop the others.
+'fp maintenance' runs the same cleanup, but only rotates the log once it
+grows past 10 MB.
+
+Examples:
+  fp gc          # Clean up and report reclaimed space
+  fp gc --json   # Machine-readable report`,
+		Usage:    "fp gc [--json]",
+		Flags:    GCFlags,
+		Action:   trackingactions.GC,
+		Mutating: true,
+		Category: dispatchers.CategoryPlumbing,
+	})
+}
+
+func addDevCommands(root *dispatchers.DispatchNode) {
+	dev := dispatchers.Group(dispatchers.GroupSpec{
+		Name:    "dev",
+		Parent:  root,
+		Summary: "Tools for demos and testing fp",
+		Description: `Commands for trying out fp without real activity: they are meant for
+demos, screenshots and testing, not everyday use.`,
+		Usage: "fp dev <command>",
+	})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "replay",
+		Parent:  dev,
+		Summary: "Feed events into the store at an accelerated pace",
+		Description: `Stores events one by one, spaced out the way they happened divided by
+--speed, so fp watch and the other views see a realistic stream of
+activity. Without --file, replays made-up events for three demo
+repositories; with it, replays a recording made with
+'fp watch --json > demo.jsonl'. Waits are capped at 5 seconds however
+far apart the recorded events were.
+
+Replayed events are stored with the current time under repository IDs
+starting with fp-replay/, so they show up in fp watch and fp activity
+next to real activity without ever replacing it, even when the
+recording was made from your own events. They are marked skipped, so
+exports never send them anywhere. --clean removes them, and only them.
+Run --clean before replaying the same recording again, or its events
+update the earlier ones instead of appearing as new.
+
+Examples:
+  fp dev replay                          # 30 synthetic events at 10x
+  fp dev replay --speed 60x --count 100  # More events, faster
+  fp dev replay --file demo.jsonl        # Replay a recording
+  fp dev replay --clean                  # Remove replayed events`,
+		Usage:    "fp dev replay [--speed <n>x] [--file <path>] [--count <n>] [--clean]",
+		Flags:    ReplayFlags,
+		Action:   trackingactions.Replay,
+		Mutating: true,
+		Category: dispatchers.CategoryPlumbing,
+	})
+}
+
 func addUpdateCommand(root *dispatchers.DispatchNode) {
 	dispatchers.Command(dispatchers.CommandSpec{
 		Name:    "update",
@@ -426,13 +1592,34 @@ func addUpdateCommand(root *dispatchers.DispatchNode) {
 		Summary: "Update to latest version",
 		Description: `Downloads and installs a newer version of fp.
 
+The release binary is picked for this machine and fp says which one it
+used: Apple Silicon gets the native arm64 build even when fp runs under
+Rosetta, and musl systems (Alpine) prefer a musl build when there is one.
+If no binary fits, fp builds the version from source with go install,
+fetching modules only from proxy.golang.org and checking them against
+sum.golang.org, whatever your go env says. The new binary has to report
+the requested version, or the old one is kept.
+
+On Windows, where a running program can't be deleted, the old fp.exe is
+renamed to fp.old.exe and removed the next time fp runs.
+
+The latest release comes from the channel set by update_channel: stable
+(the default) or prerelease, which also offers release candidates.
+--channel picks the channel for one run without changing the setting.
+
+With --check fp only reports whether a newer release is out, and exits
+with code 10 when there is one, so scripts can tell it from an error.
+
 Examples:
   fp update          # Install latest release
-  fp update v0.1.0   # Install specific version`,
-		Usage:    "fp update [version] [--tag]",
+  fp update v0.1.0   # Install specific version
+  fp update --check  # Exit 10 if an update is available
+  fp update --channel prerelease   # Install the latest release candidate`,
+		Usage:    "fp update [version] [--tag] [--check] [--channel <stable|prerelease>]",
 		Args:     OptionalVersionArg,
 		Flags:    UpdateFlags,
 		Action:   updateactions.Update,
+		Mutating: true,
 		Category: dispatchers.CategoryManageRepos,
 	})
 }
@@ -471,7 +1658,8 @@ func logsAction(args []string, flags *dispatchers.ParsedFlags) error {
 	if flags.Has("-i") || flags.Has("--interactive") {
 		return logsactions.Interactive(args, flags)
 	}
-	if flags.Has("--tail") || flags.Has("-f") {
+	// A bare --tail follows, as it did before it took a line count
+	if flags.Has("--follow") || flags.Has("-f") || flags.Has("--tail") {
 		return logsactions.Tail(args, flags)
 	}
 	return logsactions.View(args, flags)
@@ -3,6 +3,7 @@ package cli
 import (
 	"testing"
 
+	"github.com/footprint-tools/cli/internal/dispatchers"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,13 +34,13 @@ func TestBuildTree_HasExpectedTopLevelCommands(t *testing.T) {
 	}
 
 	for _, cmd := range expectedCommands {
-		_, found := root.Children[cmd]
+		_, found := root.Child(cmd)
 		require.True(t, found, "expected top-level command '%s' not found", cmd)
 	}
 }
 
 func TestBuildTree_ConfigHasSubcommands(t *testing.T) {
-	root := BuildTree()
+	root := buildFullTree(t)
 
 	config, found := root.Children["config"]
 	require.True(t, found, "config group not found")
@@ -52,7 +53,7 @@ func TestBuildTree_ConfigHasSubcommands(t *testing.T) {
 }
 
 func TestBuildTree_ThemeHasSubcommands(t *testing.T) {
-	root := BuildTree()
+	root := buildFullTree(t)
 
 	theme, found := root.Children["theme"]
 	require.True(t, found, "theme group not found")
@@ -65,7 +66,7 @@ func TestBuildTree_ThemeHasSubcommands(t *testing.T) {
 }
 
 func TestBuildTree_ReposHasSubcommands(t *testing.T) {
-	root := BuildTree()
+	root := buildFullTree(t)
 
 	repos, found := root.Children["repos"]
 	require.True(t, found, "repos group not found")
@@ -78,7 +79,7 @@ func TestBuildTree_ReposHasSubcommands(t *testing.T) {
 }
 
 func TestBuildTree_CommandsHaveActions(t *testing.T) {
-	root := BuildTree()
+	root := buildFullTree(t)
 
 	// Commands that should have actions
 	commandsWithActions := []string{
@@ -101,7 +102,7 @@ func TestBuildTree_CommandsHaveActions(t *testing.T) {
 }
 
 func TestBuildTree_RootHasFlags(t *testing.T) {
-	root := BuildTree()
+	root := buildFullTree(t)
 
 	require.NotEmpty(t, root.Flags, "root should have flags")
 
@@ -119,7 +120,7 @@ func TestBuildTree_RootHasFlags(t *testing.T) {
 }
 
 func TestBuildTree_CommandsHaveUsage(t *testing.T) {
-	root := BuildTree()
+	root := buildFullTree(t)
 
 	require.NotEmpty(t, root.Usage, "root should have usage")
 
@@ -132,7 +133,7 @@ func TestBuildTree_CommandsHaveUsage(t *testing.T) {
 }
 
 func TestBuildTree_CommandsHaveSummary(t *testing.T) {
-	root := BuildTree()
+	root := buildFullTree(t)
 
 	require.NotEmpty(t, root.Summary, "root should have summary")
 
@@ -142,7 +143,7 @@ func TestBuildTree_CommandsHaveSummary(t *testing.T) {
 }
 
 func TestBuildTree_GroupsWithInteractiveFlag(t *testing.T) {
-	root := BuildTree()
+	root := buildFullTree(t)
 
 	// All groups have -i flag and InteractiveAction (not Action)
 	groups := []string{"config", "theme", "repos"}
@@ -158,7 +159,7 @@ func TestBuildTree_GroupsWithInteractiveFlag(t *testing.T) {
 }
 
 func TestBuildTree_ThemeHasInteractiveFlag(t *testing.T) {
-	root := BuildTree()
+	root := buildFullTree(t)
 
 	theme, found := root.Children["theme"]
 	require.True(t, found, "theme group not found")
@@ -177,11 +178,17 @@ func TestBuildTree_ThemeHasInteractiveFlag(t *testing.T) {
 }
 
 func TestBuildTree_SubcommandsHaveActions(t *testing.T) {
-	root := BuildTree()
+	root := buildFullTree(t)
 
 	// Check config subcommands
 	config := root.Children["config"]
 	for name, child := range config.Children {
+		if name == "profile" {
+			for sub, grandchild := range child.Children {
+				require.NotNil(t, grandchild.Action, "config profile subcommand '%s' should have an action", sub)
+			}
+			continue
+		}
 		require.NotNil(t, child.Action, "config subcommand '%s' should have an action", name)
 	}
 
@@ -199,7 +206,7 @@ func TestBuildTree_SubcommandsHaveActions(t *testing.T) {
 }
 
 func TestBuildTree_HelpHasNoAction(t *testing.T) {
-	root := BuildTree()
+	root := buildFullTree(t)
 
 	help, found := root.Children["help"]
 	require.True(t, found, "help command not found")
@@ -207,7 +214,7 @@ func TestBuildTree_HelpHasNoAction(t *testing.T) {
 }
 
 func TestBuildTree_SetupHasFlags(t *testing.T) {
-	root := BuildTree()
+	root := buildFullTree(t)
 
 	setup := root.Children["setup"]
 	require.NotEmpty(t, setup.Flags, "setup should have flags")
@@ -224,7 +231,7 @@ func TestBuildTree_SetupHasFlags(t *testing.T) {
 }
 
 func TestBuildTree_ActivityHasFlags(t *testing.T) {
-	root := BuildTree()
+	root := buildFullTree(t)
 
 	activity := root.Children["activity"]
 	require.NotEmpty(t, activity.Flags, "activity should have flags")
@@ -242,3 +249,38 @@ func TestBuildTree_ActivityHasFlags(t *testing.T) {
 	require.True(t, flagNames["--until"], "activity should have --until flag")
 	require.True(t, flagNames["--limit"], "activity should have --limit flag")
 }
+
+// buildFullTree builds the tree with every lazy group loaded.
+func buildFullTree(t *testing.T) *dispatchers.DispatchNode {
+	t.Helper()
+	root := BuildTree()
+	root.LoadAll()
+	return root
+}
+
+func TestBuildTree_GroupsAreLazy(t *testing.T) {
+	root := BuildTree()
+	require.NotContains(t, root.Children, "record", "groups should not be built up front")
+
+	record, found := root.Child("record")
+	require.True(t, found)
+	require.NotNil(t, record.Action)
+	require.NotContains(t, root.Children, "activity", "resolving record should not build other groups")
+}
+
+func TestBuildTree_LazyNamesMatchGroups(t *testing.T) {
+	full := buildFullTree(t)
+
+	// Every command must be reachable by name on a fresh tree, which fails if
+	// a group builds a command it doesn't declare to Lazy.
+	for name := range full.Children {
+		_, found := BuildTree().Child(name)
+		require.True(t, found, "top-level command %q is not declared to dispatchers.Lazy", name)
+	}
+
+	root := BuildTree()
+	for name := range full.Children {
+		root.Child(name)
+	}
+	require.Len(t, root.Children, len(full.Children))
+}
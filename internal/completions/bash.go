@@ -2,6 +2,8 @@ package completions
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -16,11 +18,19 @@ func GenerateBash(commands []CommandInfo) string {
 	b.WriteString(fmt.Sprintf(`# %s bash completion script
 # Generated by %s completions
 
+# Completes values of a kind, one per line. Values with spaces ("2 weeks
+# ago") are escaped so they stay one argument.
+_%s_values() {
+    local IFS=$'\n'
+    COMPREPLY=($(compgen -W "$(%s completions --values "$1" 2>/dev/null)" -- "${cur//\\ / }"))
+    COMPREPLY=("${COMPREPLY[@]// /\\ }")
+}
+
 _%s_completions() {
     local cur prev words cword
     _init_completion || return
 
-    local commands="`, bin, bin, funcName))
+    local commands="`, bin, bin, funcName, bin, funcName))
 
 	// Get root command's subcommands
 	var rootSubcmds []string
@@ -49,6 +59,25 @@ _%s_completions() {
 
 		b.WriteString(fmt.Sprintf("        %s)\n", cmd.Name))
 
+		// Flag values, looked up when completing
+		if flags := valueFlags(commands, cmd); len(flags) > 0 {
+			b.WriteString("            case \"$prev\" in\n")
+			for _, f := range flags {
+				b.WriteString(fmt.Sprintf("                %s)\n", strings.Join(f.Names, "|")))
+				b.WriteString(fmt.Sprintf("                    _%s_values %s\n", funcName, f.Values))
+				b.WriteString("                    return\n")
+				b.WriteString("                    ;;\n")
+			}
+			b.WriteString("            esac\n")
+		}
+		argValues := subcommandArgValues(commands, cmd)
+		for _, name := range slices.Sorted(maps.Keys(argValues)) {
+			b.WriteString(fmt.Sprintf("            if [[ $cword -eq 3 && \"${COMP_WORDS[2]}\" == \"%s\" ]]; then\n", name))
+			b.WriteString(fmt.Sprintf("                _%s_values %s\n", funcName, argValues[name]))
+			b.WriteString("                return\n")
+			b.WriteString("            fi\n")
+		}
+
 		if len(cmd.Subcommands) > 0 {
 			sort.Strings(cmd.Subcommands)
 			b.WriteString("            if [[ $cword -eq 2 ]]; then\n")
@@ -81,7 +110,7 @@ _%s_completions() {
 
     # Global flags
     if [[ "$cur" == -* ]]; then
-        COMPREPLY=($(compgen -W "--help --version --no-color --no-pager" -- "$cur"))
+        COMPREPLY=($(compgen -W "--help --version --no-color --force-color --no-pager --read-only" -- "$cur"))
         return
     fi
 }
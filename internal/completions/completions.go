@@ -13,6 +13,7 @@ type CommandInfo struct {
 	Summary     string
 	Subcommands []string
 	Flags       []FlagInfo
+	ArgValues   string // Values kind of the first argument, if it has one
 }
 
 // FlagInfo represents a flag for a command
@@ -20,10 +21,13 @@ type FlagInfo struct {
 	Names       []string
 	Description string
 	HasValue    bool
+	Values      string // Values kind completed for the flag's value, if any
 }
 
 // ExtractCommands walks the dispatch tree and extracts all commands
 func ExtractCommands(root *dispatchers.DispatchNode) []CommandInfo {
+	root.LoadAll()
+
 	var commands []CommandInfo
 	extractNode(root, &commands)
 	return commands
@@ -47,15 +51,22 @@ func extractNode(node *dispatchers.DispatchNode, commands *[]CommandInfo) {
 			Names:       f.Names,
 			Description: f.Description,
 			HasValue:    f.ValueHint != "",
+			Values:      f.Values,
 		})
 	}
 
+	var argValues string
+	if len(node.Args) > 0 {
+		argValues = node.Args[0].Values
+	}
+
 	cmd := CommandInfo{
 		Name:        node.Name,
 		Path:        node.Path,
 		Summary:     node.Summary,
 		Subcommands: subcommands,
 		Flags:       flags,
+		ArgValues:   argValues,
 	}
 	*commands = append(*commands, cmd)
 
@@ -74,3 +85,41 @@ func FindCommand(commands []CommandInfo, path []string) *CommandInfo {
 	}
 	return nil
 }
+
+// valueFlags returns the flags of cmd and its subcommands that complete a
+// value, once per flag name, in the order they are declared.
+func valueFlags(commands []CommandInfo, cmd CommandInfo) []FlagInfo {
+	var out []FlagInfo
+	seen := make(map[string]bool)
+	add := func(flags []FlagInfo) {
+		for _, f := range flags {
+			if f.Values == "" || len(f.Names) == 0 || seen[f.Names[0]] {
+				continue
+			}
+			seen[f.Names[0]] = true
+			out = append(out, f)
+		}
+	}
+
+	add(cmd.Flags)
+	subcommands := slices.Sorted(slices.Values(cmd.Subcommands))
+	for _, name := range subcommands {
+		if sub := FindCommand(commands, append(append([]string{}, cmd.Path...), name)); sub != nil {
+			add(sub.Flags)
+		}
+	}
+	return out
+}
+
+// subcommandArgValues maps the subcommands of cmd whose first argument
+// completes a value to that value's kind.
+func subcommandArgValues(commands []CommandInfo, cmd CommandInfo) map[string]string {
+	out := make(map[string]string)
+	for _, name := range cmd.Subcommands {
+		sub := FindCommand(commands, append(append([]string{}, cmd.Path...), name))
+		if sub != nil && sub.ArgValues != "" {
+			out[name] = sub.ArgValues
+		}
+	}
+	return out
+}
@@ -2,6 +2,8 @@ package completions
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 )
 
@@ -21,10 +23,12 @@ complete -c %s -f
 complete -c %s -s h -l help -d 'Show help'
 complete -c %s -s v -l version -d 'Show version'
 complete -c %s -l no-color -d 'Disable colored output'
+complete -c %s -l force-color -d 'Color output even when piped'
 complete -c %s -l no-pager -d 'Do not use pager'
 complete -c %s -l pager -d 'Use specified pager' -r
+complete -c %s -l read-only -d 'Block commands that change data'
 
-`, bin, bin, bin, bin, bin, bin, bin, bin, bin)
+`, bin, bin, bin, bin, bin, bin, bin, bin, bin, bin, bin)
 
 	// Find root command to get top-level subcommands
 	var rootSubcmds []string
@@ -80,6 +84,20 @@ complete -c %s -l pager -d 'Use specified pager' -r
 			}
 		}
 
+		// Values for subcommand flags and arguments
+		if len(cmd.Subcommands) > 0 {
+			for _, f := range valueFlags(commands, cmd) {
+				if !slices.ContainsFunc(cmd.Flags, func(g FlagInfo) bool { return slices.Equal(g.Names, f.Names) }) {
+					writeFishFlag(&b, bin, cmdName, f)
+				}
+			}
+			argValues := subcommandArgValues(commands, cmd)
+			for _, name := range slices.Sorted(maps.Keys(argValues)) {
+				fmt.Fprintf(&b, "complete -c %s -n '__fish_seen_subcommand_from %s; and __fish_seen_subcommand_from %s' -a %s\n",
+					bin, cmdName, name, fishValues(bin, argValues[name]))
+			}
+		}
+
 		if len(cmd.Subcommands) > 0 || len(cmd.Flags) > 0 {
 			b.WriteString("\n")
 		}
@@ -113,7 +131,10 @@ func writeFishFlag(b *strings.Builder, bin, cmdName string, f FlagInfo) {
 	if long != "" {
 		parts = append(parts, fmt.Sprintf("-l %s", long))
 	}
-	if f.HasValue {
+	switch {
+	case f.Values != "":
+		parts = append(parts, "-x", "-a "+fishValues(bin, f.Values))
+	case f.HasValue:
 		parts = append(parts, "-r")
 	}
 	parts = append(parts, fmt.Sprintf("-d '%s'", desc))
@@ -121,6 +142,11 @@ func writeFishFlag(b *strings.Builder, bin, cmdName string, f FlagInfo) {
 	b.WriteString(strings.Join(parts, " ") + "\n")
 }
 
+// fishValues is the command substitution that lists values of kind.
+func fishValues(bin, kind string) string {
+	return fmt.Sprintf("'(%s completions --values %s 2>/dev/null)'", bin, kind)
+}
+
 func escapeForFish(s string) string {
 	s = strings.ReplaceAll(s, "'", "\\'")
 	s = strings.ReplaceAll(s, "\\", "\\\\")
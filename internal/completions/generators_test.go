@@ -163,3 +163,65 @@ func TestGenerateFish_EmptyTree(t *testing.T) {
 		t.Error("fish script should contain basic completion setup even for empty tree")
 	}
 }
+
+func buildValuesTestTree() *dispatchers.DispatchNode {
+	root := dispatchers.Root(dispatchers.RootSpec{Name: "fp", Summary: "Test CLI"})
+
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "activity",
+		Parent:  root,
+		Summary: "View activity",
+		Flags: []dispatchers.FlagDescriptor{
+			{Names: []string{"-r", "--repo"}, ValueHint: "<id>", Description: "Filter by repository id", Values: ValuesRepos},
+			{Names: []string{"--json"}, Description: "Output as JSON"},
+		},
+	})
+
+	theme := dispatchers.Group(dispatchers.GroupSpec{Name: "theme", Parent: root, Summary: "Change colors"})
+	dispatchers.Command(dispatchers.CommandSpec{
+		Name:    "set",
+		Parent:  theme,
+		Summary: "Set the theme",
+		Args:    []dispatchers.ArgSpec{{Name: "name", Required: true, Values: ValuesThemes}},
+	})
+
+	return root
+}
+
+func TestGenerators_CompleteValues(t *testing.T) {
+	commands := ExtractCommands(buildValuesTestTree())
+
+	tests := []struct {
+		shell  string
+		script string
+		checks []string
+	}{
+		{"bash", GenerateBash(commands), []string{
+			"_fp_values() {",
+			`compgen -W "$(fp completions --values "$1" 2>/dev/null)"`,
+			"-r|--repo)\n                    _fp_values repos",
+			`if [[ $cword -eq 3 && "${COMP_WORDS[2]}" == "set" ]]; then`,
+			"_fp_values themes",
+		}},
+		{"zsh", GenerateZsh(commands), []string{
+			"_fp_values() {",
+			"[Filter by repository id]:value:_fp_values repos'",
+			"_fp_values themes",
+		}},
+		{"fish", GenerateFish(commands), []string{
+			"-l repo -x -a '(fp completions --values repos 2>/dev/null)'",
+			"-n '__fish_seen_subcommand_from theme; and __fish_seen_subcommand_from set' -a '(fp completions --values themes 2>/dev/null)'",
+		}},
+	}
+
+	for _, tt := range tests {
+		for _, check := range tt.checks {
+			if !strings.Contains(tt.script, check) {
+				t.Errorf("%s script should contain %q", tt.shell, check)
+			}
+		}
+		if strings.Contains(tt.script, "--values json") {
+			t.Errorf("%s script completes values for a flag without them", tt.shell)
+		}
+	}
+}
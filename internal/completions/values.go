@@ -0,0 +1,87 @@
+package completions
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// Kinds of flag and argument values the completion scripts can offer. The
+// scripts ask for them at completion time with `fp completions --values
+// <kind>`, so repositories and branches are current.
+const (
+	ValuesRepos    = "repos"    // ids of tracked repositories
+	ValuesBranches = "branches" // branches of the repository in the working directory
+	ValuesThemes   = "themes"   // theme names
+	ValuesStatuses = "statuses" // event statuses
+	ValuesDevices  = "devices"  // machines events were recorded on
+	ValuesProjects = "projects" // project names
+	ValuesSources  = "sources"  // event sources
+	ValuesDates    = "dates"    // common --since/--until expressions
+)
+
+// Values returns the completion candidates of the given kind. Unknown kinds,
+// and sources that can't be read (no database yet, not in a repository),
+// give no candidates rather than an error, so completion never prints one.
+func Values(kind string) []string {
+	switch kind {
+	case ValuesRepos:
+		return fromDB(store.ListRepoIDs)
+	case ValuesDevices:
+		return fromDB(store.ListDevices)
+	case ValuesProjects:
+		return fromDB(store.ListProjectNames)
+	case ValuesBranches:
+		branches, _ := git.ListBranches()
+		return branches
+	case ValuesThemes:
+		return append(append([]string{style.AutoTheme}, style.BaseThemeNames...), style.AllThemeNames()...)
+	case ValuesStatuses:
+		statuses := []domain.EventStatus{domain.StatusPending, domain.StatusExported, domain.StatusOrphaned, domain.StatusSkipped}
+		names := make([]string, len(statuses))
+		for i, s := range statuses {
+			names[i] = strings.ToLower(s.String())
+		}
+		return names
+	case ValuesSources:
+		names := make([]string, 0, int(domain.SourceStash)+1)
+		for s := domain.SourcePostCommit; s <= domain.SourceStash; s++ {
+			names = append(names, strings.ToLower(s.String()))
+		}
+		return names
+	case ValuesDates:
+		return dateValues(time.Now())
+	default:
+		return nil
+	}
+}
+
+// dateValues offers the date expressions people reach for most, then the
+// names of this month and the two before it. Any YYYY-MM-DD date works
+// too; it just isn't worth listing.
+func dateValues(now time.Time) []string {
+	values := []string{"today", "yesterday", "7d", "30d", "1 week ago", "2 weeks ago", "1 month ago", "3 months ago"}
+	first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		values = append(values, strings.ToLower(first.AddDate(0, -i, 0).Month().String()))
+	}
+	return values
+}
+
+// fromDB reads candidates through a read-only connection, so completing
+// never creates or migrates the database.
+func fromDB(list func(*sql.DB) ([]string, error)) []string {
+	db, err := store.OpenReadOnly(store.DBPath())
+	if err != nil {
+		return nil
+	}
+	defer store.CloseDB(db)
+
+	values, _ := list(db)
+	return values
+}
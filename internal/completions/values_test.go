@@ -0,0 +1,57 @@
+package completions
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/dispatchers"
+)
+
+func TestValues(t *testing.T) {
+	statuses := Values(ValuesStatuses)
+	if !slices.Equal(statuses, []string{"pending", "exported", "orphaned", "skipped"}) {
+		t.Errorf("Values(statuses) = %v", statuses)
+	}
+
+	themes := Values(ValuesThemes)
+	if !slices.Contains(themes, "default") || !slices.Contains(themes, "default-dark") {
+		t.Errorf("Values(themes) = %v, want base and variant names", themes)
+	}
+
+	sources := Values(ValuesSources)
+	if len(sources) != 10 || sources[0] != "post-commit" || !slices.Contains(sources, "branch-create") {
+		t.Errorf("Values(sources) = %v", sources)
+	}
+
+	if got := Values("nope"); got != nil {
+		t.Errorf("Values(nope) = %v, want nil", got)
+	}
+}
+
+func TestValues_ReposWithoutDatabase(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+
+	if got := Values(ValuesRepos); len(got) != 0 {
+		t.Errorf("Values(repos) = %v, want none without a database", got)
+	}
+	if got := Values(ValuesDevices); len(got) != 0 {
+		t.Errorf("Values(devices) = %v, want none without a database", got)
+	}
+}
+
+func TestDateValues(t *testing.T) {
+	now := time.Date(2025, time.January, 20, 12, 0, 0, 0, time.UTC)
+	values := dateValues(now)
+
+	if !slices.Equal(values[len(values)-3:], []string{"january", "december", "november"}) {
+		t.Errorf("dateValues() = %v, want the last three months at the end", values)
+	}
+	for _, v := range values {
+		if _, ok := dispatchers.ParseDate(v, now); !ok {
+			t.Errorf("dateValues() offers %q, which --since doesn't accept", v)
+		}
+	}
+}
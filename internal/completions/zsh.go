@@ -2,6 +2,8 @@ package completions
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -25,8 +27,10 @@ _%s() {
         '(-h --help)'{-h,--help}'[Show help]' \
         '(-v --version)'{-v,--version}'[Show version]' \
         '--no-color[Disable colored output]' \
+        '--force-color[Color output even when piped]' \
         '--no-pager[Do not use pager]' \
         '--pager=[Use specified pager]:pager:' \
+        '--read-only[Block commands that change data]' \
         '1: :_%s_commands' \
         '*::arg:->args'
 
@@ -69,7 +73,13 @@ _%s_commands() {
     _describe -t commands '%s commands' commands
 }
 
-`, bin)
+_%s_values() {
+    local -a values
+    values=(${(f)"$(%s completions --values $1 2>/dev/null)"})
+    compadd -a values
+}
+
+`, bin, funcName, bin)
 
 	// Generate function for each command with subcommands or flags
 	for _, cmd := range commands {
@@ -97,7 +107,7 @@ _%s_commands() {
 
 			// Add flags for the group
 			for _, f := range cmd.Flags {
-				flagStr := formatZshFlag(f)
+				flagStr := formatZshFlag(f, funcName)
 				if flagStr != "" {
 					fmt.Fprintf(&b, "        %s \\\n", flagStr)
 				}
@@ -109,12 +119,13 @@ _%s_commands() {
 			b.WriteString("        subcmd)\n")
 			b.WriteString("            _describe -t subcommands 'subcommands' subcommands\n")
 			b.WriteString("            ;;\n")
+			writeZshSubcommandValues(&b, funcName, commands, cmd)
 			b.WriteString("    esac\n")
 		} else {
 			// Leaf command with just flags
 			b.WriteString("    _arguments \\\n")
 			for i, f := range cmd.Flags {
-				flagStr := formatZshFlag(f)
+				flagStr := formatZshFlag(f, funcName)
 				if flagStr != "" {
 					if i < len(cmd.Flags)-1 {
 						fmt.Fprintf(&b, "        %s \\\n", flagStr)
@@ -138,7 +149,41 @@ _%s_commands() {
 	return b.String()
 }
 
-func formatZshFlag(f FlagInfo) string {
+// writeZshSubcommandValues completes the values of subcommand flags and
+// first arguments once a subcommand of cmd has been typed.
+func writeZshSubcommandValues(b *strings.Builder, funcName string, commands []CommandInfo, cmd CommandInfo) {
+	flags := valueFlags(commands, cmd)
+	argValues := subcommandArgValues(commands, cmd)
+	if len(flags) == 0 && len(argValues) == 0 {
+		return
+	}
+
+	b.WriteString("        args)\n")
+	if len(flags) > 0 {
+		b.WriteString("            case $words[CURRENT-1] in\n")
+		for _, f := range flags {
+			fmt.Fprintf(b, "                %s)\n", strings.Join(f.Names, "|"))
+			fmt.Fprintf(b, "                    _%s_values %s\n", funcName, f.Values)
+			b.WriteString("                    return\n")
+			b.WriteString("                    ;;\n")
+		}
+		b.WriteString("            esac\n")
+	}
+	if len(argValues) > 0 {
+		b.WriteString("            if (( CURRENT == 2 )); then\n")
+		b.WriteString("                case $line[1] in\n")
+		for _, name := range slices.Sorted(maps.Keys(argValues)) {
+			fmt.Fprintf(b, "                    %s)\n", name)
+			fmt.Fprintf(b, "                        _%s_values %s\n", funcName, argValues[name])
+			b.WriteString("                        ;;\n")
+		}
+		b.WriteString("                esac\n")
+		b.WriteString("            fi\n")
+	}
+	b.WriteString("            ;;\n")
+}
+
+func formatZshFlag(f FlagInfo, funcName string) string {
 	if len(f.Names) == 0 {
 		return ""
 	}
@@ -154,19 +199,25 @@ func formatZshFlag(f FlagInfo) string {
 		return len(names[i]) < len(names[j])
 	})
 
+	// Values the flag completes, if any
+	action := ""
+	if f.Values != "" {
+		action = fmt.Sprintf("_%s_values %s", funcName, f.Values)
+	}
+
 	if len(names) == 2 {
 		// Both short and long form
 		short := names[0]
 		long := names[1]
 		if f.HasValue {
-			return fmt.Sprintf("'(%s %s)'%s'[%s]:value:'", short, long, fmt.Sprintf("{%s,%s}", short, long), desc)
+			return fmt.Sprintf("'(%s %s)'%s'[%s]:value:%s'", short, long, fmt.Sprintf("{%s,%s}", short, long), desc, action)
 		}
 		return fmt.Sprintf("'(%s %s)'{%s,%s}'[%s]'", short, long, short, long, desc)
 	}
 
 	// Single flag
 	if f.HasValue {
-		return fmt.Sprintf("'%s=[%s]:value:'", names[0], desc)
+		return fmt.Sprintf("'%s=[%s]:value:%s'", names[0], desc, action)
 	}
 	return fmt.Sprintf("'%s[%s]'", names[0], desc)
 }
@@ -215,6 +215,22 @@ func TestSet(t *testing.T) {
 			wantLines:    []string{"key1=newvalue"},
 			wantUpdated:  true,
 		},
+		{
+			name:         "preserves inline comment",
+			initialLines: []string{"key1=value1 # note"},
+			key:          "key1",
+			value:        "newvalue",
+			wantLines:    []string{"key1=newvalue # note"},
+			wantUpdated:  true,
+		},
+		{
+			name:         "hex value is not a comment",
+			initialLines: []string{"source_colors.manual=#56b4e9"},
+			key:          "source_colors.manual",
+			value:        "#e69f00",
+			wantLines:    []string{"source_colors.manual=#e69f00"},
+			wantUpdated:  true,
+		},
 	}
 
 	for _, tt := range tests {
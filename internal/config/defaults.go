@@ -1,6 +1,7 @@
 package config
 
 import (
+	"github.com/footprint-tools/cli/internal/domain"
 	"github.com/footprint-tools/cli/internal/paths"
 )
 
@@ -10,9 +11,21 @@ var Defaults = map[string]func() string{
 	"export_path":         paths.ExportRepoDir,
 	"export_last":         func() string { return "0" },
 	"export_remote":       func() string { return "" },
+	"export_format":       func() string { return "csv" },
+	"export_http_url":     func() string { return "" },
+	"export_http_token":   func() string { return "" },
+	"export_sinks":        func() string { return "" }, // git, or http if export_http_url is set
+	"export_s3_bucket":    func() string { return "" },
+	"export_s3_region":    func() string { return "" }, // us-east-1
+	"export_s3_prefix":    func() string { return "" },
+	"export_s3_endpoint":  func() string { return "" }, // AWS
+	"retention_days":      func() string { return "" }, // keep events forever
+	"maintenance_last":    func() string { return "0" },
 	"theme":               func() string { return "default" }, // auto-detects -dark/-light
-	"display_date":        func() string { return "Jan 02" },
-	"display_time":        func() string { return "24h" },
+	"date_format":         func() string { return "Jan 02" },
+	"time_format":         func() string { return "24h" },
+	"week_start":          func() string { return "monday" },
+	"timezone":            func() string { return "local" },
 	"color_success":       func() string { return "" }, // uses theme default
 	"color_warning":       func() string { return "" }, // uses theme default
 	"color_error":         func() string { return "" }, // uses theme default
@@ -20,7 +33,20 @@ var Defaults = map[string]func() string{
 	"color_muted":         func() string { return "" }, // uses theme default
 	"color_header":        func() string { return "" }, // uses theme default
 	"enable_log":          func() string { return "true" },
+	"read_only":           func() string { return "" }, // same as --read-only when true
 	"pager":               func() string { return "less -FRSX" },
+	"enrichers":           func() string { return "git, ticket" },
+	"update_channel":      func() string { return "stable" },
+}
+
+// Default returns the value a config key has when it isn't set: the
+// computed default when there is one, or the one in domain.ConfigKeys.
+func Default(key string) string {
+	if fn, ok := Defaults[key]; ok {
+		return fn()
+	}
+	value, _ := domain.GetDefaultValue(key)
+	return value
 }
 
 // Get returns the value for a config key.
@@ -16,10 +16,12 @@ func Set(lines []string, key, value string) ([]string, bool) {
 		}
 
 		if strings.TrimSpace(parts[0]) == key {
-			// Check for inline comment after the value and preserve it
-			oldValue := parts[1]
-			commentIdx := strings.Index(oldValue, "#")
-			if commentIdx >= 0 {
+			// Check for inline comment after the value and preserve it.
+			// A comment needs whitespace before '#', so hex colors like
+			// "#56b4e9" are values, not comments.
+			oldValue := strings.TrimSpace(parts[1])
+			commentIdx := strings.Index(oldValue, " #")
+			if commentIdx > 0 {
 				comment := strings.TrimSpace(oldValue[commentIdx:])
 				lines[i] = key + "=" + value + " " + comment
 			} else {
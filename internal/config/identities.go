@@ -0,0 +1,23 @@
+package config
+
+import "strings"
+
+// Identities returns the author emails listed in the identities key,
+// lowercased so they compare case-insensitively against commit authors.
+func Identities() []string {
+	value, _ := Get("identities")
+	var emails []string
+	for _, part := range strings.Split(value, ",") {
+		if email := strings.ToLower(strings.TrimSpace(part)); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// ExportMineOnly reports whether exports are limited to commits authored by
+// one of the configured identities.
+func ExportMineOnly() bool {
+	value, _ := Get("export_mine_only")
+	return isTrue(value)
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentities(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	require.Empty(t, Identities())
+	require.False(t, ExportMineOnly())
+
+	lines, err := ReadLines()
+	require.NoError(t, err)
+	lines, _ = Set(lines, "identities", " Me@Work.com, ,me@home.dev ")
+	lines, _ = Set(lines, "export_mine_only", "yes")
+	require.NoError(t, WriteLines(lines))
+
+	require.Equal(t, []string{"me@work.com", "me@home.dev"}, Identities())
+	require.True(t, ExportMineOnly())
+}
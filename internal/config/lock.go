@@ -43,6 +43,20 @@ func WithLock(fn func() error) error {
 	return fn()
 }
 
+// StaleLock returns the path of the config lock file when it was left
+// behind by a process that died while holding it.
+func StaleLock() (string, bool) {
+	lockPath, err := getLockPath()
+	if err != nil {
+		return "", false
+	}
+	info, err := os.Stat(lockPath)
+	if err != nil || time.Since(info.ModTime()) <= staleLockTimeout {
+		return "", false
+	}
+	return lockPath, true
+}
+
 // getLockPath returns the path to the lock file.
 func getLockPath() (string, error) {
 	home, err := os.UserHomeDir()
@@ -128,3 +128,21 @@ func TestWithLock_StaleLockRemoval(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, executed)
 }
+
+func TestStaleLock(t *testing.T) {
+	tempHome := setupTempHome(t)
+	lockPath := filepath.Join(tempHome, lockFileName)
+
+	_, stale := StaleLock()
+	require.False(t, stale, "no lock file")
+
+	require.NoError(t, os.WriteFile(lockPath, []byte("123"), 0600))
+	_, stale = StaleLock()
+	require.False(t, stale, "fresh lock is held, not stale")
+
+	oldTime := time.Now().Add(-staleLockTimeout - time.Second)
+	require.NoError(t, os.Chtimes(lockPath, oldTime, oldTime))
+	path, stale := StaleLock()
+	require.True(t, stale)
+	require.Equal(t, lockPath, path)
+}
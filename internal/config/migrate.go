@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/domain"
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// migrationOutput is where renamed keys are reported.
+var migrationOutput io.Writer = os.Stderr
+
+// migrateConfig rewrites the keys of the config file at configPath that
+// were renamed (see domain.ConfigKeyRenames). The file is rewritten once,
+// after copying it to a .bak file next to it, and each rename is reported
+// on stderr. In read-only mode the keys are only renamed in memory.
+func migrateConfig(configPath string, lines []string) []string {
+	migrated, applied := migrateKeys(lines)
+	if len(applied) == 0 {
+		return lines
+	}
+
+	if readOnlyFlag.Load() {
+		return migrated
+	}
+	if values, err := Parse(lines); err == nil && isTrue(values["read_only"]) {
+		return migrated
+	}
+
+	backupPath := configPath + ".bak"
+	if err := writeLinesTo(backupPath, lines); err != nil {
+		log.Warn("config: could not back up config before renaming keys: %v", err)
+		return migrated
+	}
+	if err := writeLinesTo(configPath, migrated); err != nil {
+		log.Warn("config: could not rewrite renamed keys: %v", err)
+		return migrated
+	}
+
+	for _, r := range applied {
+		log.Info("config: renamed %s to %s", r.Old, r.New)
+		_, _ = fmt.Fprintf(migrationOutput, "fp: config key %s is now %s, updated %s (previous version in %s)\n",
+			r.Old, r.New, configPath, backupPath)
+	}
+	return migrated
+}
+
+// migrateKeys rewrites the keys that were renamed to their new names. When
+// the new key is already set too, the old line is commented out instead,
+// so the value set under the new name wins. It returns the renames that
+// were applied, once each.
+func migrateKeys(lines []string) ([]string, []domain.ConfigKeyRename) {
+	set := make(map[string]bool)
+	for _, line := range lines {
+		if key, _, ok := lineKey(line); ok {
+			set[key] = true
+		}
+	}
+
+	var migrated []string
+	var applied []domain.ConfigKeyRename
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		key, value, ok := lineKey(line)
+		name, isArray := strings.CutSuffix(key, "[]")
+		newName, renamed := domain.RenamedConfigKey(name)
+		if !ok || !renamed {
+			migrated = append(migrated, line)
+			continue
+		}
+
+		if !seen[name] {
+			seen[name] = true
+			applied = append(applied, domain.ConfigKeyRename{Old: name, New: newName})
+		}
+		if !isArray && set[newName] {
+			migrated = append(migrated, "# "+strings.TrimSpace(line))
+			continue
+		}
+		if isArray {
+			newName += "[]"
+		}
+		migrated = append(migrated, newName+"="+value)
+	}
+	return migrated, applied
+}
+
+// lineKey splits a config line into its key and raw value. ok is false for
+// blank lines and comments.
+func lineKey(line string) (key, value string, ok bool) {
+	trimmed := strings.TrimSpace(strings.TrimPrefix(line, "\uFEFF"))
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return "", "", false
+	}
+	key, value, found := strings.Cut(trimmed, "=")
+	if !found {
+		return "", "", false
+	}
+	return strings.TrimSpace(key), value, true
+}
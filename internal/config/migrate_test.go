@@ -0,0 +1,102 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/domain"
+)
+
+// withRenames replaces domain.ConfigKeyRenames for one test and captures
+// what is reported about them.
+func withRenames(t *testing.T, renames ...domain.ConfigKeyRename) *bytes.Buffer {
+	t.Helper()
+	savedRenames, savedOutput := domain.ConfigKeyRenames, migrationOutput
+	t.Cleanup(func() {
+		domain.ConfigKeyRenames, migrationOutput = savedRenames, savedOutput
+	})
+	var out bytes.Buffer
+	domain.ConfigKeyRenames, migrationOutput = renames, &out
+	return &out
+}
+
+func TestMigrateKeys(t *testing.T) {
+	withRenames(t,
+		domain.ConfigKeyRename{Old: "export_interval_sec", New: "export.interval"},
+		domain.ConfigKeyRename{Old: "colour_muted", New: "color_muted"},
+		domain.ConfigKeyRename{Old: "ignore", New: "repos_exclude"},
+	)
+
+	lines := []string{
+		"# Footprint configuration",
+		"export_interval_sec=600",
+		"colour_muted = \"245\"",
+		"color_muted=240",
+		"ignore[]=~/tmp",
+		"# export_interval_sec=60",
+		"theme=neon-dark",
+	}
+
+	migrated, applied := migrateKeys(lines)
+	require.Equal(t, []string{
+		"# Footprint configuration",
+		"export.interval=600",
+		"# colour_muted = \"245\"",
+		"color_muted=240",
+		"repos_exclude[]=~/tmp",
+		"# export_interval_sec=60",
+		"theme=neon-dark",
+	}, migrated, "a key already set under its new name keeps that value")
+	require.Len(t, applied, 3)
+	require.Equal(t, "export_interval_sec", applied[0].Old)
+
+	_, applied = migrateKeys(migrated)
+	require.Empty(t, applied, "migrated lines need nothing more")
+}
+
+func TestReadLines_MigratesRenamedKeys(t *testing.T) {
+	out := withRenames(t, domain.ConfigKeyRename{Old: "export_interval_sec", New: "export.interval"})
+	tempHome := setupTempHome(t)
+	configPath := filepath.Join(tempHome, ".fprc")
+	require.NoError(t, os.WriteFile(configPath, []byte("export_interval_sec=600\ntheme=neon-dark\n"), 0600))
+
+	lines, err := ReadLines()
+	require.NoError(t, err)
+	require.Equal(t, []string{"export.interval=600", "theme=neon-dark"}, lines)
+	require.Contains(t, out.String(), "export_interval_sec is now export.interval")
+
+	saved, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.Equal(t, "export.interval=600\ntheme=neon-dark\n", string(saved))
+
+	backup, err := os.ReadFile(configPath + ".bak")
+	require.NoError(t, err)
+	require.Equal(t, "export_interval_sec=600\ntheme=neon-dark\n", string(backup))
+
+	out.Reset()
+	_, err = ReadLines()
+	require.NoError(t, err)
+	require.Empty(t, out.String(), "the rename is reported once")
+}
+
+func TestReadLines_MigratesInMemoryWhenReadOnly(t *testing.T) {
+	out := withRenames(t, domain.ConfigKeyRename{Old: "export_interval_sec", New: "export.interval"})
+	tempHome := setupTempHome(t)
+	configPath := filepath.Join(tempHome, ".fprc")
+	content := "read_only=true\nexport_interval_sec=600\n"
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0600))
+
+	lines, err := ReadLines()
+	require.NoError(t, err)
+	require.Equal(t, []string{"read_only=true", "export.interval=600"}, lines)
+	require.Empty(t, out.String())
+
+	saved, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.Equal(t, content, string(saved))
+	require.NoFileExists(t, configPath+".bak")
+}
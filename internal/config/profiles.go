@@ -0,0 +1,159 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/paths"
+)
+
+// profileNamePattern is what profile names may look like. They name
+// directories, so they are kept to lowercase letters, digits, - and _.
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// ErrProfileExists is returned when creating a profile that already exists.
+var ErrProfileExists = errors.New("profile already exists")
+
+// isProfileKey reports whether key belongs to a single profile, so a new
+// profile doesn't copy it from the default one: where events are exported,
+// whose commits they are and which repositories they come from.
+func isProfileKey(key string) bool {
+	if strings.HasPrefix(key, "export_") {
+		return true
+	}
+	switch key {
+	case "identities", "repos_include", "repos_exclude", "github_token", "maintenance_last":
+		return true
+	}
+	return false
+}
+
+// ValidateProfileName checks name can be used as a profile name.
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name '%s': use lowercase letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// ProfileExists reports whether the named profile has been created. The
+// default profile, "", always exists.
+func ProfileExists(name string) bool {
+	if name == "" {
+		return true
+	}
+	path, err := paths.ProfileConfigFilePath(name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// Profiles returns the names of the profiles created, sorted. The default
+// profile isn't listed.
+func Profiles() ([]string, error) {
+	entries, err := os.ReadDir(paths.ProfilesDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && ProfileExists(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// CreateProfile creates the named profile, starting from the default
+// profile's settings without the ones that belong to a single profile
+// (see isProfileKey). It returns the path of the new profile's config file.
+func CreateProfile(name string) (string, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return "", err
+	}
+	if readOnlyFlag.Load() || readOnlyOnDisk() {
+		return "", ErrReadOnly
+	}
+	if ProfileExists(name) {
+		return "", fmt.Errorf("%w: %s", ErrProfileExists, name)
+	}
+
+	basePath, err := paths.ProfileConfigFilePath("")
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(basePath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	lines := []string{"# Footprint configuration for profile " + name}
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r", ""), "\n") {
+		key, _, found := strings.Cut(strings.TrimSpace(line), "=")
+		if found && !strings.HasPrefix(key, "#") && isProfileKey(strings.TrimSpace(key)) {
+			continue
+		}
+		if strings.HasPrefix(line, "# Footprint configuration") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	path, err := paths.ProfileConfigFilePath(name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	if err := writeLinesTo(path, lines); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ProfileForRepo returns the profile whose repos_include claims the
+// repository at repoPath, or "" when none does. Git hooks don't say which
+// profile they record for, so this is how events from work repositories
+// end up in a work profile. When several profiles match, the first by
+// name wins.
+func ProfileForRepo(repoPath string) string {
+	names, err := Profiles()
+	if err != nil {
+		return ""
+	}
+	for _, name := range names {
+		path, err := paths.ProfileConfigFilePath(name)
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		values, err := Parse(strings.Split(strings.ReplaceAll(string(data), "\r", ""), "\n"))
+		if err != nil {
+			continue
+		}
+		rules := ParseRepoRules(values["repos_include"], values["repos_exclude"])
+		if len(rules.Include) > 0 && rules.Allows(repoPath) {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/paths"
+)
+
+func TestProfiles(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Cleanup(func() { paths.SetProfile("") })
+
+	lines, err := ReadLines()
+	require.NoError(t, err)
+	lines, _ = Set(lines, "theme", "ocean-dark")
+	lines, _ = Set(lines, "identities", "me@home.dev")
+	lines, _ = Set(lines, "export_remote", "git@github.com:me/footprint.git")
+	require.NoError(t, WriteLines(lines))
+
+	_, err = CreateProfile("Work!")
+	require.ErrorContains(t, err, "invalid profile name")
+
+	path, err := CreateProfile("work")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(paths.ProfilesDir(), "work", "fprc"), path)
+	_, err = CreateProfile("work")
+	require.ErrorIs(t, err, ErrProfileExists)
+
+	names, err := Profiles()
+	require.NoError(t, err)
+	require.Equal(t, []string{"work"}, names)
+
+	paths.SetProfile("work")
+	theme, _ := Get("theme")
+	require.Equal(t, "ocean-dark", theme, "display settings carry over")
+	require.Empty(t, Identities(), "identities don't")
+	remote, _ := Get("export_remote")
+	require.Empty(t, remote)
+	exportPath, _ := Get("export_path")
+	require.Contains(t, exportPath, filepath.Join("profiles", "work", "export"))
+
+	lines, err = ReadLines()
+	require.NoError(t, err)
+	lines, _ = Set(lines, "repos_include", filepath.Join(home, "work"))
+	require.NoError(t, WriteLines(lines))
+
+	paths.SetProfile("")
+	require.Equal(t, []string{"me@home.dev"}, Identities(), "the default profile is left alone")
+	require.Equal(t, "work", ProfileForRepo(filepath.Join(home, "work", "api")))
+	require.Empty(t, ProfileForRepo(filepath.Join(home, "code", "blog")))
+}
@@ -48,8 +48,12 @@ func ReadLines() ([]string, error) {
 		return nil, err
 	}
 
+	if !isNew {
+		lines = migrateConfig(configPath, lines)
+	}
+
 	// If file is new/empty, initialize with defaults
-	if isNew && len(lines) == 0 {
+	if isNew && len(lines) == 0 && !readOnlyFlag.Load() {
 		lines = initializeDefaults()
 		if err := WriteLines(lines); err != nil {
 			log.Warn("config: could not write default config: %v", err)
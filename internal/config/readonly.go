@@ -0,0 +1,58 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/footprint-tools/cli/internal/paths"
+)
+
+// ErrReadOnly is returned by writes attempted while read-only mode is on.
+var ErrReadOnly = errors.New("fp is in read-only mode")
+
+// readOnlyFlag is set by --read-only for the current process.
+var readOnlyFlag atomic.Bool
+
+// EnableReadOnly turns on read-only mode for this process (used by --read-only).
+func EnableReadOnly() {
+	readOnlyFlag.Store(true)
+}
+
+// IsReadOnly reports whether mutating operations are blocked, either by
+// --read-only or by read_only=true in the config file.
+func IsReadOnly() bool {
+	if readOnlyFlag.Load() {
+		return true
+	}
+	value, _ := Get("read_only")
+	return isTrue(value)
+}
+
+// readOnlyOnDisk reports whether the config file as currently saved turns
+// read-only mode on. Used by WriteLines, which can't call Get without
+// recursing, and which must still allow the write that sets read_only.
+func readOnlyOnDisk() bool {
+	configPath, err := paths.ConfigFilePath()
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return false
+	}
+	values, err := Parse(strings.Split(strings.ReplaceAll(string(data), "\r", ""), "\n"))
+	if err != nil {
+		return false
+	}
+	return isTrue(values["read_only"])
+}
+
+func isTrue(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "1", "yes", "on":
+		return true
+	}
+	return false
+}
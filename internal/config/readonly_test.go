@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteLines_ReadOnlyFlag(t *testing.T) {
+	setupTempHome(t)
+	require.NoError(t, WriteLines([]string{"theme=neon"}))
+
+	readOnlyFlag.Store(true)
+	t.Cleanup(func() { readOnlyFlag.Store(false) })
+
+	require.True(t, IsReadOnly())
+	require.ErrorIs(t, WriteLines([]string{"theme=ocean"}), ErrReadOnly)
+
+	value, _ := Get("theme")
+	require.Equal(t, "neon", value)
+}
+
+func TestWriteLines_ReadOnlyConfig(t *testing.T) {
+	setupTempHome(t)
+	require.False(t, IsReadOnly())
+
+	// Turning read-only on is itself allowed
+	require.NoError(t, WriteLines([]string{"read_only=true"}))
+	require.True(t, IsReadOnly())
+
+	require.ErrorIs(t, WriteLines([]string{"read_only=false"}), ErrReadOnly)
+}
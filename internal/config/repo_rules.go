@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// RepoRules decides which repositories fp scans, backfills and records,
+// from the repos_include and repos_exclude keys. Both hold comma-separated
+// path patterns:
+//
+//   - ~ expands to the home directory
+//   - * and ? match within one path segment, ** matches any number of them
+//   - a pattern matches the directories it names and everything below them
+//   - a pattern that isn't absolute matches at any depth (like .gitignore)
+//
+// Exclusions always win. When repos_include is set, only repositories
+// matching one of its patterns are allowed.
+type RepoRules struct {
+	Include []string
+	Exclude []string
+}
+
+// LoadRepoRules reads the current repos_include and repos_exclude settings.
+func LoadRepoRules() RepoRules {
+	include, _ := Get("repos_include")
+	exclude, _ := Get("repos_exclude")
+	return ParseRepoRules(include, exclude)
+}
+
+// ParseRepoRules builds rules from comma-separated include and exclude lists.
+func ParseRepoRules(include, exclude string) RepoRules {
+	home, _ := os.UserHomeDir()
+	return RepoRules{
+		Include: parseRepoPatterns(include, home),
+		Exclude: parseRepoPatterns(exclude, home),
+	}
+}
+
+// RepoAllowed reports whether the repository at repoPath may be tracked under
+// the current configuration.
+func RepoAllowed(repoPath string) bool {
+	return LoadRepoRules().Allows(repoPath)
+}
+
+// Empty reports whether no rules are configured.
+func (r RepoRules) Empty() bool {
+	return len(r.Include) == 0 && len(r.Exclude) == 0
+}
+
+// Excludes reports whether dir matches an exclude pattern. Scanners use it to
+// skip whole directory trees.
+func (r RepoRules) Excludes(dir string) bool {
+	return matchAnyRepoPattern(r.Exclude, dir)
+}
+
+// Allows reports whether the repository at repoPath passes both lists.
+func (r RepoRules) Allows(repoPath string) bool {
+	if r.Excludes(repoPath) {
+		return false
+	}
+	if len(r.Include) == 0 {
+		return true
+	}
+	return matchAnyRepoPattern(r.Include, repoPath)
+}
+
+func parseRepoPatterns(value, home string) []string {
+	var patterns []string
+	for _, part := range strings.Split(value, ",") {
+		p := strings.TrimSpace(part)
+		if p == "" {
+			continue
+		}
+		if home != "" && (p == "~" || strings.HasPrefix(p, "~/")) {
+			p = home + p[1:]
+		}
+		p = strings.TrimRight(filepath.ToSlash(p), "/")
+		if !strings.HasSuffix(p, "/**") {
+			p += "/**"
+		}
+		if !strings.HasPrefix(p, "/") && !filepath.IsAbs(filepath.FromSlash(p)) {
+			p = "**/" + p
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns
+}
+
+func matchAnyRepoPattern(patterns []string, repoPath string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	segs := splitRepoPath(filepath.ToSlash(filepath.Clean(repoPath)))
+	for _, p := range patterns {
+		if matchRepoSegments(splitRepoPath(p), segs) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitRepoPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// matchRepoSegments matches path segments against pattern segments, where a
+// "**" segment matches zero or more path segments.
+func matchRepoSegments(pattern, segs []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segs); i++ {
+				if matchRepoSegments(pattern[1:], segs[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segs) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segs[0]); !ok {
+			return false
+		}
+		pattern, segs = pattern[1:], segs[1:]
+	}
+	return len(segs) == 0
+}
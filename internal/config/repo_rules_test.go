@@ -0,0 +1,57 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoRules_Exclude(t *testing.T) {
+	t.Setenv("HOME", "/home/me")
+	rules := ParseRepoRules("", "~/work/vendor/**, ~/tmp, scratch-*")
+
+	require.False(t, rules.Allows("/home/me/work/vendor/lib"))
+	require.False(t, rules.Allows("/home/me/work/vendor/a/b/c"))
+	require.True(t, rules.Excludes("/home/me/work/vendor"), "** matches zero segments so scanners can prune")
+	require.False(t, rules.Allows("/home/me/tmp"))
+	require.False(t, rules.Allows("/home/me/tmp/clone"))
+	require.False(t, rules.Allows("/home/me/code/scratch-1"), "relative patterns match at any depth")
+	require.False(t, rules.Allows("/home/me/code/scratch-1/nested"))
+
+	require.True(t, rules.Allows("/home/me/work/app"))
+	require.True(t, rules.Allows("/home/me/tmpfiles"))
+}
+
+func TestRepoRules_Include(t *testing.T) {
+	t.Setenv("HOME", "/home/me")
+	rules := ParseRepoRules("~/code, /srv/*/repo", "~/code/archive")
+
+	require.True(t, rules.Allows("/home/me/code/app"))
+	require.True(t, rules.Allows("/srv/team/repo"))
+	require.True(t, rules.Allows("/srv/team/repo/nested"))
+	require.False(t, rules.Allows("/srv/team/sub/repo"), "* stays within one segment")
+	require.False(t, rules.Allows("/home/me/other"))
+	require.False(t, rules.Allows("/home/me/code/archive/old"), "exclude wins over include")
+}
+
+func TestRepoRules_EmptyAllowsEverything(t *testing.T) {
+	rules := ParseRepoRules(" , ", "")
+	require.True(t, rules.Empty())
+	require.True(t, rules.Allows("/anything"))
+	require.False(t, rules.Excludes("/anything"))
+}
+
+func TestLoadRepoRules_ReadsConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	lines, err := ReadLines()
+	require.NoError(t, err)
+	lines, _ = Set(lines, "repos_exclude", "~/throwaway")
+	require.NoError(t, WriteLines(lines))
+
+	require.False(t, RepoAllowed(filepath.Join(home, "throwaway", "x")))
+	require.True(t, RepoAllowed(filepath.Join(home, "keep")))
+}
@@ -10,11 +10,19 @@ import (
 )
 
 func WriteLines(lines []string) error {
+	if readOnlyFlag.Load() || readOnlyOnDisk() {
+		return ErrReadOnly
+	}
+
 	configPath, err := paths.ConfigFilePath()
 	if err != nil {
 		return err
 	}
+	return writeLinesTo(configPath, lines)
+}
 
+// writeLinesTo replaces the config file at configPath with lines.
+func writeLinesTo(configPath string, lines []string) error {
 	// Write to a temporary file first for atomic operation
 	dir := filepath.Dir(configPath)
 	tmpFile, err := os.CreateTemp(dir, ".fprc.tmp.*")
@@ -0,0 +1,69 @@
+// Package daemon manages the pidfile of the background fp daemon, and
+// holds what other commands need to know about its schedule.
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/paths"
+)
+
+// TickInterval is how often a running daemon checks for due work. Exports
+// still honor export_interval_sec; this only bounds the delay.
+const TickInterval = time.Minute
+
+// PIDFilePath returns the path of the daemon pidfile.
+func PIDFilePath() string {
+	return filepath.Join(paths.AppDataDir(), "daemon.pid")
+}
+
+// ReadPID returns the pid stored in the pidfile.
+func ReadPID(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("invalid pidfile %s", path)
+	}
+	return pid, nil
+}
+
+// WritePID writes pid to the pidfile, creating parent directories.
+func WritePID(path string, pid int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strconv.Itoa(pid)+"\n"), 0600)
+}
+
+// RemovePID deletes the pidfile. A missing file is not an error.
+func RemovePID(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Running reports whether the process recorded in the pidfile is alive.
+// Returns the pid when it is.
+func Running(path string) (int, bool) {
+	pid, err := ReadPID(path)
+	if err != nil {
+		return 0, false
+	}
+	return pid, processAlive(pid)
+}
+
+// IsRunning reports whether the daemon is running, using the default pidfile.
+func IsRunning() bool {
+	_, ok := Running(PIDFilePath())
+	return ok
+}
@@ -0,0 +1,45 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAndReadPID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "daemon.pid")
+
+	require.NoError(t, WritePID(path, 4242))
+
+	pid, err := ReadPID(path)
+	require.NoError(t, err)
+	require.Equal(t, 4242, pid)
+}
+
+func TestReadPID_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.pid")
+	require.NoError(t, os.WriteFile(path, []byte("garbage"), 0600))
+
+	_, err := ReadPID(path)
+	require.Error(t, err)
+}
+
+func TestRunning_CurrentProcess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.pid")
+	require.NoError(t, WritePID(path, os.Getpid()))
+
+	pid, ok := Running(path)
+	require.True(t, ok)
+	require.Equal(t, os.Getpid(), pid)
+}
+
+func TestRunning_MissingFile(t *testing.T) {
+	_, ok := Running(filepath.Join(t.TempDir(), "daemon.pid"))
+	require.False(t, ok)
+}
+
+func TestRemovePID_Missing(t *testing.T) {
+	require.NoError(t, RemovePID(filepath.Join(t.TempDir(), "daemon.pid")))
+}
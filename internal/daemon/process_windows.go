@@ -0,0 +1,27 @@
+package daemon
+
+import "syscall"
+
+const (
+	// processQueryLimitedInformation is enough access to read an exit code
+	processQueryLimitedInformation = 0x1000
+	// stillActive is the exit code of a process that hasn't exited
+	stillActive = 259
+)
+
+// processAlive checks for a live process by asking Windows for its exit
+// code: a pid that can't be opened belongs to no process.
+func processAlive(pid int) bool {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		// Another user's process still exists
+		return err == syscall.ERROR_ACCESS_DENIED
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
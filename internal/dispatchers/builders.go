@@ -71,5 +71,6 @@ func Command(spec CommandSpec) *DispatchNode {
 	)
 
 	node.Category = spec.Category
+	node.Mutating = spec.Mutating
 	return node
 }
@@ -0,0 +1,78 @@
+package dispatchers
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateFormats describes the values date flags accept, for help and errors.
+const DateFormats = `YYYY-MM-DD, today, yesterday, 7d, "2 weeks ago" or a month name`
+
+// relativeDatePattern matches "7d", "2w", "3m", "1y" and "2 weeks ago",
+// also written the git way as "2.weeks.ago".
+var relativeDatePattern = regexp.MustCompile(`^(\d+)\s*(d|w|m|y|days?|weeks?|months?|years?)(\s+ago)?$`)
+
+// ParseDate reads a date flag value relative to now. Besides YYYY-MM-DD it
+// takes today, yesterday, a count of days, weeks, months or years ago
+// ("7d", "2 weeks ago"), and a month name for the first day of that month,
+// this year or last if it hasn't come yet. Like YYYY-MM-DD, the result is
+// midnight UTC of the calendar day it names on the local calendar.
+func ParseDate(value string, now time.Time) (time.Time, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true
+	}
+
+	now = now.Local()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch value {
+	case "today":
+		return today, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	}
+
+	if m := relativeDatePattern.FindStringSubmatch(strings.ReplaceAll(value, ".", " ")); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, false
+		}
+		switch m[2][0] {
+		case 'd':
+			return today.AddDate(0, 0, -n), true
+		case 'w':
+			return today.AddDate(0, 0, -7*n), true
+		case 'm':
+			return today.AddDate(0, -n, 0), true
+		case 'y':
+			return today.AddDate(-n, 0, 0), true
+		}
+	}
+
+	if month, ok := parseMonth(value); ok {
+		year := today.Year()
+		if month > today.Month() {
+			year--
+		}
+		return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), true
+	}
+
+	return time.Time{}, false
+}
+
+// parseMonth reads a month name, in full or its first three letters.
+func parseMonth(value string) (time.Month, bool) {
+	if len(value) < 3 {
+		return 0, false
+	}
+	for m := time.January; m <= time.December; m++ {
+		name := strings.ToLower(m.String())
+		if value == name || value == name[:3] {
+			return m, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,45 @@
+package dispatchers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2025, time.March, 15, 12, 0, 0, 0, time.Local)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-01-15", day(2024, time.January, 15)},
+		{"today", day(2025, time.March, 15)},
+		{"Yesterday", day(2025, time.March, 14)},
+		{"7d", day(2025, time.March, 8)},
+		{"2w", day(2025, time.March, 1)},
+		{"1m", day(2025, time.February, 15)},
+		{"1y", day(2024, time.March, 15)},
+		{"2 weeks ago", day(2025, time.March, 1)},
+		{"1 day ago", day(2025, time.March, 14)},
+		{"3 months", day(2024, time.December, 15)},
+		{"2.weeks.ago", day(2025, time.March, 1)},
+		{"january", day(2025, time.January, 1)},
+		{"Mar", day(2025, time.March, 1)},
+		{"october", day(2024, time.October, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := ParseDate(tt.value, now)
+			require.True(t, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, bad := range []string{"", "someday", "15-01-2024", "7x", "ma", "weeks ago"} {
+		_, ok := ParseDate(bad, now)
+		require.False(t, ok, bad)
+	}
+}
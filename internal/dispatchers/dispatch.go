@@ -30,6 +30,27 @@ func getInteractiveBrowserFunc() CommandFunc {
 	return interactiveBrowserFunc
 }
 
+// readOnlyFunc reports whether read-only mode is on. Injected from main so
+// dispatchers doesn't depend on config.
+var (
+	readOnlyFunc   func() bool
+	readOnlyFuncMu sync.RWMutex
+)
+
+// SetReadOnlyFunc sets the read-only check thread-safely.
+func SetReadOnlyFunc(fn func() bool) {
+	readOnlyFuncMu.Lock()
+	defer readOnlyFuncMu.Unlock()
+	readOnlyFunc = fn
+}
+
+// isReadOnly reports whether mutating commands are blocked.
+func isReadOnly() bool {
+	readOnlyFuncMu.RLock()
+	defer readOnlyFuncMu.RUnlock()
+	return readOnlyFunc != nil && readOnlyFunc()
+}
+
 func handleHelpCommand(root *DispatchNode, tokens []string, flags *ParsedFlags) (Resolution, error, bool) {
 	for i, tok := range tokens {
 		if tok != "help" {
@@ -50,16 +71,21 @@ func handleHelpCommand(root *DispatchNode, tokens []string, flags *ParsedFlags)
 			return Resolution{Node: root, Flags: flags, Execute: TopicsListAction()}, nil, true
 		}
 
+		var topic *help.Topic
+		if len(targetPath) == 1 {
+			topic = help.LookupTopic(targetPath[0])
+		}
+		// `fp help hooks` reads the topic; `fp hooks help` the command
+		if topic != nil && i == 0 {
+			return Resolution{Node: root, Flags: flags, Execute: TopicHelpAction(topic)}, nil, true
+		}
+
 		target := resolveNode(root, targetPath)
 		if target != nil {
 			return Resolution{Node: target, Flags: flags, Execute: HelpAction(target, root)}, nil, true
 		}
-
-		if len(targetPath) == 1 {
-			topic := help.LookupTopic(targetPath[0])
-			if topic != nil {
-				return Resolution{Node: root, Flags: flags, Execute: TopicHelpAction(topic)}, nil, true
-			}
+		if topic != nil {
+			return Resolution{Node: root, Flags: flags, Execute: TopicHelpAction(topic)}, nil, true
 		}
 
 		suggestions := FindSimilarCommands(targetPath[0], root, defaultSuggestionsCount)
@@ -78,17 +104,17 @@ func Dispatch(root *DispatchNode, tokens []string, flags *ParsedFlags) (Resoluti
 	pathLen := 0
 
 	for i, tok := range tokens {
-		child, ok := current.Children[tok]
+		child, ok := current.Child(tok)
 		if !ok {
 			// If this is the first token (top-level command) and it doesn't exist,
 			// it's likely a typo - suggest similar commands
-			if i == 0 && len(current.Children) > 0 {
+			if i == 0 && current.hasChildren() {
 				suggestions := FindSimilarCommands(tok, current, defaultSuggestionsCount)
 				return Resolution{}, usage.UnknownCommand(tok, suggestions...)
 			}
 			// For subcommands: if the current node has children but no action,
 			// it's a command group and unknown tokens are errors, not args
-			if current.Action == nil && len(current.Children) > 0 {
+			if current.Action == nil && current.hasChildren() {
 				suggestions := FindSimilarCommands(tok, current, defaultSuggestionsCount)
 				cmdPath := strings.Join(append(current.Path, tok), " ")
 				return Resolution{}, usage.UnknownCommand(cmdPath, suggestions...)
@@ -113,16 +139,21 @@ func Dispatch(root *DispatchNode, tokens []string, flags *ParsedFlags) (Resoluti
 
 	valid := validFlagsForNode(current, root)
 	if err := validateFlags(flags, valid); err != nil {
-		return Resolution{}, err
+		return Resolution{}, WithCommand(err, current, root)
 	}
 
 	// Check for conflicting flags: --interactive/-i and --json are mutually exclusive
 	if hasInteractiveFlag(flags) && flags.Has("--json") {
-		return Resolution{}, usage.ConflictingFlags("--interactive", "--json")
+		return Resolution{}, WithCommand(usage.ConflictingFlags("--interactive", "--json"), current, root)
+	}
+
+	args, err := promptMissingArgs(current, args, flags)
+	if err != nil {
+		return Resolution{}, WithCommand(err, current, root)
 	}
 
 	if err := validateArgs(current.Args, args); err != nil {
-		return Resolution{}, err
+		return Resolution{}, WithCommand(err, current, root)
 	}
 
 	if current.Action == nil {
@@ -149,6 +180,12 @@ func Dispatch(root *DispatchNode, tokens []string, flags *ParsedFlags) (Resoluti
 		}, nil
 	}
 
+	// --dry-run and --check only preview, so they stay available in
+	// read-only mode
+	if current.Mutating && !previewOnly(flags) && isReadOnly() {
+		return Resolution{}, usage.ReadOnly(strings.Join(current.Path, " "))
+	}
+
 	return Resolution{
 		Node:    current,
 		Args:    args,
@@ -157,6 +194,10 @@ func Dispatch(root *DispatchNode, tokens []string, flags *ParsedFlags) (Resoluti
 	}, nil
 }
 
+func previewOnly(flags *ParsedFlags) bool {
+	return flags.Has("--dry-run") || flags.Has("--check")
+}
+
 func hasHelpFlag(flags *ParsedFlags) bool {
 	return flags.Has("--help") || flags.Has("-h")
 }
@@ -218,7 +259,7 @@ func resolveNode(root *DispatchNode, path []string) *DispatchNode {
 	current := root
 
 	for _, p := range path {
-		child, ok := current.Children[p]
+		child, ok := current.Child(p)
 		if !ok {
 			return nil
 		}
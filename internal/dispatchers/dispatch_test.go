@@ -562,3 +562,46 @@ func TestDispatch_CommandThenHelp(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, res.Execute)
 }
+
+func TestDispatch_ReadOnlyBlocksMutatingCommands(t *testing.T) {
+	root := createTestTree()
+	root.Children["track"].Mutating = true
+
+	SetReadOnlyFunc(func() bool { return true })
+	t.Cleanup(func() { SetReadOnlyFunc(nil) })
+
+	_, err := Dispatch(root, []string{"track", "/path"}, NewParsedFlags(nil))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "read-only mode")
+
+	// Viewing commands and help still work
+	_, err = Dispatch(root, []string{"version"}, NewParsedFlags(nil))
+	require.NoError(t, err)
+
+	res, err := Dispatch(root, []string{"track"}, NewParsedFlags([]string{"--help"}))
+	require.NoError(t, err)
+	require.Equal(t, "track", res.Node.Name)
+
+	root.Children["track"].Flags = append(root.Children["track"].Flags, FlagDescriptor{Names: []string{"--dry-run"}})
+	_, err = Dispatch(root, []string{"track", "/path"}, NewParsedFlags([]string{"--dry-run"}))
+	require.NoError(t, err)
+
+	root.Children["track"].Flags = append(root.Children["track"].Flags, FlagDescriptor{Names: []string{"--check"}})
+	_, err = Dispatch(root, []string{"track", "/path"}, NewParsedFlags([]string{"--check"}))
+	require.NoError(t, err)
+}
+
+func TestDispatch_HelpTopicSharingCommandName(t *testing.T) {
+	root := createTestTree()
+	Command(CommandSpec{Name: "hooks", Parent: root, Summary: "Hooks", Action: func([]string, *ParsedFlags) error { return nil }})
+	flags := NewParsedFlags([]string{})
+
+	// `fp help hooks` reads the topic, `fp hooks help` the command
+	res, err := Dispatch(root, []string{"help", "hooks"}, flags)
+	require.NoError(t, err)
+	require.Equal(t, root, res.Node)
+
+	res, err = Dispatch(root, []string{"hooks", "help"}, flags)
+	require.NoError(t, err)
+	require.Equal(t, "hooks", res.Node.Name)
+}
@@ -65,14 +65,15 @@ func (f *ParsedFlags) Int(name string, defaultVal int) int {
 	return n
 }
 
-// Date returns the time.Time value of a flag (YYYY-MM-DD format), or nil if not present or invalid.
+// Date returns the time.Time value of a flag, or nil if not present or
+// invalid. See ParseDate for the formats accepted.
 func (f *ParsedFlags) Date(name string) *time.Time {
 	str := f.String(name, "")
 	if str == "" {
 		return nil
 	}
-	t, err := time.Parse("2006-01-02", str)
-	if err != nil {
+	t, ok := ParseDate(str, time.Now())
+	if !ok {
 		return nil
 	}
 	return &t
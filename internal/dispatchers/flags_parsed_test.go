@@ -300,7 +300,7 @@ func TestParsedFlags_Date(t *testing.T) {
 		},
 		{
 			name:     "non-date string returns nil",
-			flags:    []string{"--since=yesterday"},
+			flags:    []string{"--since=someday"},
 			flagName: "--since",
 			want:     nil,
 		},
@@ -16,6 +16,7 @@ import (
 var commandDisplayOrder = map[string]int{
 	// get started
 	"setup": 1,
+	"track": 2,
 	// inspect activity and state
 	"activity": 1,
 	"watch":    2,
@@ -26,6 +27,7 @@ var commandDisplayOrder = map[string]int{
 	"logs":     7,
 	// manage tracked repositories
 	"teardown": 1,
+	"untrack":  2,
 	// config commands
 	"config get":   1,
 	"config set":   2,
@@ -74,6 +76,8 @@ func collectLeafCommands(node *DispatchNode, out *[]*DispatchNode) {
 // HelpAction generates help output for a command node.
 func HelpAction(node *DispatchNode, root *DispatchNode) CommandFunc {
 	return func(args []string, flags *ParsedFlags) error {
+		root.LoadAll()
+
 		var out bytes.Buffer
 
 		if node == root {
@@ -0,0 +1,50 @@
+package dispatchers
+
+import "slices"
+
+// childFactory builds a group of child commands on first use.
+type childFactory struct {
+	names []string
+	build func(parent *DispatchNode)
+}
+
+// Lazy registers build as the factory for the children of parent listed in
+// names. It runs the first time one of them is resolved, or when something
+// needs every command (help, completions, suggestions), so dispatching one
+// command doesn't pay for constructing all the others.
+func Lazy(parent *DispatchNode, names []string, build func(parent *DispatchNode)) {
+	parent.factories = append(parent.factories, &childFactory{names: names, build: build})
+}
+
+// Child returns the named child of n, building its group first if needed.
+func (n *DispatchNode) Child(name string) (*DispatchNode, bool) {
+	if child, ok := n.Children[name]; ok {
+		return child, true
+	}
+	for i, f := range n.factories {
+		if slices.Contains(f.names, name) {
+			n.factories = slices.Delete(n.factories, i, i+1)
+			f.build(n)
+			break
+		}
+	}
+	child, ok := n.Children[name]
+	return child, ok
+}
+
+// LoadAll builds every pending group below n, leaving Children complete.
+func (n *DispatchNode) LoadAll() {
+	for len(n.factories) > 0 {
+		f := n.factories[0]
+		n.factories = n.factories[1:]
+		f.build(n)
+	}
+	for _, child := range n.Children {
+		child.LoadAll()
+	}
+}
+
+// hasChildren reports whether n has children, built or not.
+func (n *DispatchNode) hasChildren() bool {
+	return len(n.Children) > 0 || len(n.factories) > 0
+}
@@ -0,0 +1,47 @@
+package dispatchers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func lazyTestRoot(builds *[]string) *DispatchNode {
+	root := Root(RootSpec{Name: "fp"})
+	Lazy(root, []string{"record"}, func(parent *DispatchNode) {
+		*builds = append(*builds, "tracking")
+		Command(CommandSpec{Name: "record", Parent: parent, Action: func([]string, *ParsedFlags) error { return nil }})
+	})
+	Lazy(root, []string{"config"}, func(parent *DispatchNode) {
+		*builds = append(*builds, "config")
+		config := Group(GroupSpec{Name: "config", Parent: parent})
+		Command(CommandSpec{Name: "get", Parent: config, Action: func([]string, *ParsedFlags) error { return nil }})
+	})
+	return root
+}
+
+func TestLazy_BuildsOnlyTheResolvedGroup(t *testing.T) {
+	var builds []string
+	root := lazyTestRoot(&builds)
+
+	res, err := Dispatch(root, []string{"record"}, NewParsedFlags(nil))
+	require.NoError(t, err)
+	require.Equal(t, []string{"fp", "record"}, res.Node.Path)
+	require.Equal(t, []string{"tracking"}, builds)
+
+	_, err = Dispatch(root, []string{"record"}, NewParsedFlags(nil))
+	require.NoError(t, err)
+	require.Equal(t, []string{"tracking"}, builds, "a group is built once")
+}
+
+func TestLazy_LoadAllForSuggestionsAndHelp(t *testing.T) {
+	var builds []string
+	root := lazyTestRoot(&builds)
+
+	_, err := Dispatch(root, []string{"confg"}, NewParsedFlags(nil))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "config", "unknown commands are suggested from unbuilt groups too")
+	require.ElementsMatch(t, []string{"tracking", "config"}, builds)
+
+	require.Contains(t, root.Children["config"].Children, "get")
+}
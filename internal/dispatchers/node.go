@@ -22,12 +22,15 @@ type FlagDescriptor struct {
 	ValueHint   string
 	Description string
 	Scope       FlagScope
+	Values      string // Kind of value shell completion offers, see completions.Values
 }
 
 type ArgSpec struct {
 	Name        string
 	Description string
 	Required    bool
+	Choices     func() []string // Values offered when prompting for a missing argument
+	Values      string          // Kind of value shell completion offers, see completions.Values
 }
 
 type DispatchNode struct {
@@ -42,4 +45,7 @@ type DispatchNode struct {
 	Action            CommandFunc
 	InteractiveAction CommandFunc // Called when -i/--interactive flag is used (for groups without Action)
 	Category          CommandCategory
+	Mutating          bool // Changes hooks, events, exports or config; blocked in read-only mode
+
+	factories []*childFactory // Children not built yet, see Lazy
 }
@@ -0,0 +1,54 @@
+package dispatchers
+
+import (
+	"sync"
+
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+// ArgPromptFunc asks the user for a missing argument of node.
+type ArgPromptFunc func(node *DispatchNode, arg ArgSpec) (string, error)
+
+// argPromptFunc is injected from main only when fp runs in a terminal, so
+// scripts and hooks keep failing on missing arguments.
+var (
+	argPromptFunc   ArgPromptFunc
+	argPromptFuncMu sync.RWMutex
+)
+
+// SetArgPromptFunc sets the prompt for missing arguments thread-safely.
+// Passing nil turns prompting off.
+func SetArgPromptFunc(fn ArgPromptFunc) {
+	argPromptFuncMu.Lock()
+	defer argPromptFuncMu.Unlock()
+	argPromptFunc = fn
+}
+
+func getArgPromptFunc() ArgPromptFunc {
+	argPromptFuncMu.RLock()
+	defer argPromptFuncMu.RUnlock()
+	return argPromptFunc
+}
+
+// promptMissingArgs asks for the required arguments missing from args. It
+// leaves args alone when prompting is off or JSON output was requested, and
+// a cancelled prompt is reported as the missing argument.
+func promptMissingArgs(node *DispatchNode, args []string, flags *ParsedFlags) ([]string, error) {
+	prompt := getArgPromptFunc()
+	if prompt == nil || flags.Has("--json") {
+		return args, nil
+	}
+	// Don't ask for input the command will refuse anyway
+	if node.Action == nil || (node.Mutating && !previewOnly(flags) && isReadOnly()) {
+		return args, nil
+	}
+
+	for i := len(args); i < len(node.Args) && node.Args[i].Required; i++ {
+		value, err := prompt(node, node.Args[i])
+		if err != nil {
+			return nil, usage.MissingArgument(node.Args[i].Name)
+		}
+		args = append(args, value)
+	}
+	return args, nil
+}
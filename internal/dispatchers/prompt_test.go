@@ -0,0 +1,47 @@
+package dispatchers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatch_PromptsForMissingArgs(t *testing.T) {
+	var asked []string
+	SetArgPromptFunc(func(_ *DispatchNode, arg ArgSpec) (string, error) {
+		asked = append(asked, arg.Name)
+		return "from-prompt-" + arg.Name, nil
+	})
+	t.Cleanup(func() { SetArgPromptFunc(nil) })
+
+	res, err := Dispatch(createTestTree(), []string{"config", "set", "theme"}, NewParsedFlags(nil))
+	require.NoError(t, err)
+	require.Equal(t, []string{"value"}, asked, "only the missing argument is asked for")
+	require.Equal(t, []string{"theme", "from-prompt-value"}, res.Args)
+}
+
+func TestDispatch_PromptCancelledIsMissingArg(t *testing.T) {
+	SetArgPromptFunc(func(*DispatchNode, ArgSpec) (string, error) { return "", errors.New("cancelled") })
+	t.Cleanup(func() { SetArgPromptFunc(nil) })
+
+	_, err := Dispatch(createTestTree(), []string{"track"}, NewParsedFlags(nil))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing required argument 'path'")
+}
+
+func TestDispatch_NoPromptWithoutTerminalOrForJSON(t *testing.T) {
+	_, err := Dispatch(createTestTree(), []string{"track"}, NewParsedFlags(nil))
+	require.Error(t, err, "without a prompt func missing args fail")
+
+	SetArgPromptFunc(func(*DispatchNode, ArgSpec) (string, error) {
+		t.Fatal("should not prompt when --json is set")
+		return "", nil
+	})
+	t.Cleanup(func() { SetArgPromptFunc(nil) })
+
+	root := createTestTree()
+	root.Children["track"].Flags = append(root.Children["track"].Flags, FlagDescriptor{Names: []string{"--json"}})
+	_, err = Dispatch(root, []string{"track"}, NewParsedFlags([]string{"--json"}))
+	require.Error(t, err)
+}
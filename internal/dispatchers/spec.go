@@ -26,4 +26,5 @@ type CommandSpec struct {
 	Args        []ArgSpec
 	Action      CommandFunc
 	Category    CommandCategory
+	Mutating    bool // Blocked in read-only mode
 }
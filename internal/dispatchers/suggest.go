@@ -60,15 +60,26 @@ type suggestion struct {
 // FindSimilarCommands finds commands similar to the input string
 // It searches in the given node's children and returns up to maxResults suggestions
 func FindSimilarCommands(input string, node *DispatchNode, maxResults int) []string {
-	if node == nil || node.Children == nil {
+	if node == nil {
 		return nil
 	}
+	node.LoadAll()
+
+	names := make([]string, 0, len(node.Children))
+	for name := range node.Children {
+		names = append(names, name)
+	}
+	return FindSimilar(input, names, maxResults)
+}
 
+// FindSimilar returns up to maxResults of names that are a few edits away
+// from input, closest first.
+func FindSimilar(input string, names []string, maxResults int) []string {
 	const maxDistance = 3
 
 	var suggestions []suggestion
 
-	for name := range node.Children {
+	for _, name := range names {
 		dist := levenshtein(input, name)
 		if dist <= maxDistance && dist > 0 {
 			suggestions = append(suggestions, suggestion{name: name, distance: dist})
@@ -103,6 +114,7 @@ func CollectAllCommands(node *DispatchNode, prefix string) []string {
 	if node == nil {
 		return nil
 	}
+	node.LoadAll()
 
 	var commands []string
 
@@ -0,0 +1,39 @@
+package dispatchers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// SlowCommandThreshold is how long a command may take before Run reports it as slow.
+var SlowCommandThreshold = 5 * time.Second
+
+// SlowFunc is called by Run when a command exceeds SlowCommandThreshold.
+type SlowFunc func(node *DispatchNode, elapsed time.Duration)
+
+// Run executes the resolved command and logs its wall-clock duration.
+// If the command took longer than SlowCommandThreshold, onSlow is called.
+// Interactive sessions (-i) are not reported since their duration is up to the user.
+func (r Resolution) Run(onSlow SlowFunc) error {
+	start := time.Now()
+	err := r.Execute(r.Args, r.Flags)
+	elapsed := time.Since(start)
+
+	name := "fp"
+	if r.Node != nil {
+		name = strings.Join(r.Node.Path, " ")
+	}
+	if err != nil {
+		log.Info("dispatch: %s failed after %s", name, elapsed.Round(time.Millisecond))
+	} else {
+		log.Info("dispatch: %s finished in %s", name, elapsed.Round(time.Millisecond))
+	}
+
+	if onSlow != nil && r.Node != nil && elapsed >= SlowCommandThreshold && (r.Flags == nil || !hasInteractiveFlag(r.Flags)) {
+		onSlow(r.Node, elapsed)
+	}
+
+	return err
+}
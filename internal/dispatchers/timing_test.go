@@ -0,0 +1,64 @@
+package dispatchers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolution_Run_ReportsSlowCommands(t *testing.T) {
+	old := SlowCommandThreshold
+	SlowCommandThreshold = 10 * time.Millisecond
+	t.Cleanup(func() { SlowCommandThreshold = old })
+
+	node := &DispatchNode{Name: "slow", Path: []string{"fp", "slow"}}
+	wantErr := errors.New("boom")
+	res := Resolution{
+		Node:  node,
+		Flags: NewParsedFlags(nil),
+		Execute: func([]string, *ParsedFlags) error {
+			time.Sleep(20 * time.Millisecond)
+			return wantErr
+		},
+	}
+
+	var reported *DispatchNode
+	var elapsed time.Duration
+	err := res.Run(func(n *DispatchNode, d time.Duration) {
+		reported, elapsed = n, d
+	})
+
+	require.ErrorIs(t, err, wantErr)
+	require.Same(t, node, reported)
+	require.GreaterOrEqual(t, elapsed, 20*time.Millisecond)
+}
+
+func TestResolution_Run_SkipsFastAndInteractive(t *testing.T) {
+	old := SlowCommandThreshold
+	SlowCommandThreshold = 10 * time.Millisecond
+	t.Cleanup(func() { SlowCommandThreshold = old })
+
+	called := false
+	onSlow := func(*DispatchNode, time.Duration) { called = true }
+
+	fast := Resolution{
+		Node:    &DispatchNode{Path: []string{"fp", "fast"}},
+		Flags:   NewParsedFlags(nil),
+		Execute: mockAction,
+	}
+	require.NoError(t, fast.Run(onSlow))
+	require.False(t, called)
+
+	interactive := Resolution{
+		Node:  &DispatchNode{Path: []string{"fp", "activity"}},
+		Flags: NewParsedFlags([]string{"-i"}),
+		Execute: func([]string, *ParsedFlags) error {
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		},
+	}
+	require.NoError(t, interactive.Run(onSlow))
+	require.False(t, called)
+}
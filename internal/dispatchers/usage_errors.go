@@ -0,0 +1,134 @@
+package dispatchers
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/usage"
+)
+
+const (
+	// maxRelevantFlags caps the flags listed under an invalid flag error.
+	maxRelevantFlags = 3
+	// maxFlagDistance is how far a flag name may be from a typo to be listed.
+	maxFlagDistance = 2
+)
+
+// WithCommand attaches node's usage line, the arguments or flags relevant to
+// err, and an example to usage errors about how the command was called.
+// Other errors are returned unchanged. root may be nil.
+func WithCommand(err error, node, root *DispatchNode) error {
+	var ue *usage.Error
+	if node == nil || !errors.As(err, &ue) || !ue.IsInputError() || ue.Command != nil {
+		return err
+	}
+
+	cmd := &usage.Command{
+		Path:    strings.Join(node.Path, " "),
+		Usage:   node.Usage,
+		Example: firstExample(node),
+	}
+
+	switch ue.Kind {
+	case usage.ErrMissingArgument:
+		for _, a := range node.Args {
+			cmd.Args = append(cmd.Args, usage.Item{Name: "<" + a.Name + ">", Description: a.Description})
+		}
+	case usage.ErrInvalidFlag:
+		cmd.Flags = similarFlags(flagNameFromMessage(ue.Message), node, root)
+	case usage.ErrConflictingFlags:
+		for _, f := range node.Flags {
+			if slices.ContainsFunc(f.Names, func(n string) bool { return strings.Contains(ue.Message, "'"+n+"'") }) {
+				cmd.Flags = append(cmd.Flags, flagItem(f))
+			}
+		}
+	}
+
+	ue.Command = cmd
+	return ue
+}
+
+// firstExample returns the first command under "Examples:" in the node
+// description, without its trailing comment. Examples that only repeat the
+// command name are skipped, since the usage line already shows them.
+func firstExample(node *DispatchNode) string {
+	_, examples, ok := strings.Cut(node.Description, "Examples:")
+	if !ok {
+		return ""
+	}
+	bare := strings.Join(node.Path, " ")
+	for _, line := range strings.Split(examples, "\n") {
+		cmd, _, _ := strings.Cut(strings.TrimSpace(line), " #")
+		cmd = strings.TrimSpace(cmd)
+		if strings.HasPrefix(cmd, "fp ") && cmd != bare {
+			return cmd
+		}
+	}
+	return ""
+}
+
+// flagNameFromMessage extracts the quoted flag from an invalid flag message,
+// without any =value.
+func flagNameFromMessage(msg string) string {
+	_, rest, ok := strings.Cut(msg, "'")
+	if !ok {
+		return ""
+	}
+	flag, _, _ := strings.Cut(rest, "'")
+	name, _, _ := strings.Cut(flag, "=")
+	return name
+}
+
+// similarFlags returns the flags of node (and root) whose names are closest
+// to name, so a typo shows what was probably meant. Short flags are one
+// letter apart from each other, so only long flags get suggestions.
+func similarFlags(name string, node, root *DispatchNode) []usage.Item {
+	if !strings.HasPrefix(name, "--") {
+		return nil
+	}
+
+	type candidate struct {
+		flag     FlagDescriptor
+		distance int
+	}
+	var candidates []candidate
+	flags := node.Flags
+	if root != nil && root != node {
+		flags = append(slices.Clone(node.Flags), root.Flags...)
+	}
+	for _, f := range flags {
+		best := -1
+		for _, n := range f.Names {
+			if !strings.HasPrefix(n, "--") {
+				continue
+			}
+			if d := levenshtein(name, n); d <= maxFlagDistance && (best < 0 || d < best) {
+				best = d
+			}
+		}
+		if best >= 0 {
+			candidates = append(candidates, candidate{f, best})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+	if len(candidates) > maxRelevantFlags {
+		candidates = candidates[:maxRelevantFlags]
+	}
+
+	items := make([]usage.Item, 0, len(candidates))
+	for _, c := range candidates {
+		items = append(items, flagItem(c.flag))
+	}
+	return items
+}
+
+func flagItem(f FlagDescriptor) usage.Item {
+	name := strings.Join(f.Names, ", ")
+	if f.ValueHint != "" {
+		name += " " + f.ValueHint
+	}
+	return usage.Item{Name: name, Description: f.Description}
+}
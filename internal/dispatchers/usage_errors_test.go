@@ -0,0 +1,56 @@
+package dispatchers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/footprint-tools/cli/internal/usage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatch_InvalidFlagShowsUsageAndSimilarFlags(t *testing.T) {
+	root := createTestTree()
+	track := root.Children["track"]
+	track.Path = []string{"fp", "track"}
+	track.Usage = "fp track <path> [--remote <name>]"
+	track.Description = "Tracks a repository.\n\nExamples:\n  fp track              # Current directory\n  fp track ~/code/app   # Another repo"
+
+	_, err := Dispatch(root, []string{"track", "/path"}, NewParsedFlags([]string{"--remtoe=origin"}))
+
+	var ue *usage.Error
+	require.True(t, errors.As(err, &ue))
+	require.Equal(t, 2, ue.GetExitCode())
+	require.NotNil(t, ue.Command)
+	require.Equal(t, "fp track <path> [--remote <name>]", ue.Command.Usage)
+	require.Equal(t, []usage.Item{{Name: "--remote name", Description: "Remote name"}}, ue.Command.Flags)
+	require.Equal(t, "fp track ~/code/app", ue.Command.Example, "the bare command isn't a useful example")
+
+	msg := err.Error()
+	require.Contains(t, msg, "fp: invalid flag '--remtoe=origin'")
+	require.Contains(t, msg, "usage: fp track <path> [--remote <name>]")
+	require.Contains(t, msg, "example: fp track ~/code/app")
+	require.Contains(t, msg, "See 'fp track --help' for more.")
+}
+
+func TestDispatch_MissingArgListsArgs(t *testing.T) {
+	root := createTestTree()
+
+	_, err := Dispatch(root, []string{"config", "set", "key"}, NewParsedFlags(nil))
+
+	var ue *usage.Error
+	require.True(t, errors.As(err, &ue))
+	require.Equal(t, []usage.Item{
+		{Name: "<key>", Description: "Config key"},
+		{Name: "<value>", Description: "Config value"},
+	}, ue.Command.Args)
+}
+
+func TestWithCommand_LeavesEnvironmentErrorsAlone(t *testing.T) {
+	root := createTestTree()
+
+	err := WithCommand(usage.NotInGitRepo(), root.Children["track"], root)
+	require.Nil(t, err.(*usage.Error).Command)
+
+	plain := errors.New("boom")
+	require.Equal(t, plain, WithCommand(plain, root.Children["track"], root))
+}
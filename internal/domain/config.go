@@ -1,5 +1,7 @@
 package domain
 
+import "strings"
+
 // ConfigKey defines a configuration key with its metadata.
 type ConfigKey struct {
 	Name        string
@@ -8,6 +10,9 @@ type ConfigKey struct {
 	Section     string // Section for grouping in UI (Display, Colors, Export, etc.)
 	Hidden      bool   // Hidden keys are not shown in help or config list
 	HideIfEmpty bool   // Only show in config list if explicitly set
+	Secret      bool   // Value is masked in config list (use config get to read it)
+	Type        ConfigType
+	Values      []string // Allowed values of enum and list keys, extra words for colors
 }
 
 // ConfigKeys defines all available configuration keys.
@@ -24,20 +29,63 @@ var ConfigKeys = []ConfigKey{
 	{
 		Name:        "theme",
 		Default:     "default",
-		Description: "Color theme: default, neon, aurora, mono, ocean, sunset, candy, contrast",
+		Description: "Color theme: default, neon, aurora, mono, ocean, sunset, candy, contrast, colorblind, or auto",
+		Section:     "Display",
+	},
+	{
+		Name:        "theme_dark",
+		Description: "Theme theme=auto uses on dark terminal backgrounds (default: default-dark)",
+		Section:     "Display",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "theme_light",
+		Description: "Theme theme=auto uses on light terminal backgrounds (default: default-light)",
 		Section:     "Display",
+		HideIfEmpty: true,
 	},
 	{
-		Name:        "display_date",
+		Name:        "date_format",
 		Default:     "Jan 02",
-		Description: "Date format: dd/mm/yyyy, mm/dd/yyyy, yyyy-mm-dd, or Go format",
+		Description: "Date format: iso (yyyy-mm-dd), us (mm/dd/yyyy), eu (dd/mm/yyyy), dd.mm.yyyy, locale, or Go format",
 		Section:     "Display",
 	},
 	{
-		Name:        "display_time",
+		Name:        "time_format",
 		Default:     "24h",
-		Description: "Time format: 12h, 24h",
+		Description: "Time format: 12h, 24h, locale",
+		Section:     "Display",
+		Type:        ConfigEnum,
+		Values:      []string{"12h", "24h", "locale"},
+	},
+	{
+		Name:        "week_start",
+		Default:     "monday",
+		Description: "First day of the week in heatmaps, calendars and week reports: monday, sunday, saturday, locale",
 		Section:     "Display",
+		Type:        ConfigEnum,
+		Values:      []string{"monday", "sunday", "saturday", "locale"},
+	},
+	{
+		Name:        "timezone",
+		Default:     "local",
+		Description: "Time zone times are shown in: local, UTC, or an IANA name (e.g. America/New_York)",
+		Section:     "Display",
+	},
+	{
+		Name:        "watch_alert",
+		Description: "What fp watch does when an event arrives while it's in the background: bell, flash (-i only), none",
+		Section:     "Display",
+		HideIfEmpty: true,
+		Type:        ConfigEnum,
+		Values:      []string{"bell", "flash", "none"},
+	},
+	{
+		Name:        "accessible",
+		Description: "Use numbered plain-text prompts instead of full-screen views, for screen readers (true/false)",
+		Section:     "Display",
+		HideIfEmpty: true,
+		Type:        ConfigBool,
 	},
 	// Logging
 	{
@@ -45,6 +93,56 @@ var ConfigKeys = []ConfigKey{
 		Default:     "true",
 		Description: "Enable logging to file (true/false)",
 		Section:     "Logging",
+		Type:        ConfigBool,
+	},
+	// Updates
+	{
+		Name:        "update_channel",
+		Default:     "stable",
+		Description: "Releases fp update offers: stable, prerelease (includes release candidates)",
+		Section:     "Updates",
+		Type:        ConfigEnum,
+		Values:      []string{"stable", "prerelease"},
+	},
+	// Safety
+	{
+		Name:        "read_only",
+		Description: "Block commands that change hooks, events, exports or config (true/false)",
+		Section:     "Safety",
+		HideIfEmpty: true,
+		Type:        ConfigBool,
+	},
+	// Repositories
+	{
+		Name:        "repos_include",
+		Description: "Comma-separated path patterns; when set, only matching repos are scanned and tracked (e.g. ~/code/**)",
+		Section:     "Repositories",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "repos_exclude",
+		Description: "Comma-separated path patterns never scanned or tracked (e.g. ~/work/vendor/**, ~/tmp/**)",
+		Section:     "Repositories",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "identities",
+		Description: "Comma-separated author emails that are you, for --mine (e.g. me@work.com, me@home.dev)",
+		Section:     "Repositories",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "device_name",
+		Description: "Name for this machine on the events it records (default: the hostname)",
+		Section:     "Repositories",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "github_token",
+		Description: "GitHub token for fp backfill --from-github (default: GITHUB_TOKEN)",
+		Section:     "Repositories",
+		HideIfEmpty: true,
+		Secret:      true,
 	},
 	// Export
 	{
@@ -52,6 +150,7 @@ var ConfigKeys = []ConfigKey{
 		Default:     "3600",
 		Description: "Seconds between automatic exports",
 		Section:     "Export",
+		Type:        ConfigInt,
 	},
 	{
 		Name:        "export_path",
@@ -65,6 +164,160 @@ var ConfigKeys = []ConfigKey{
 		Description: "Remote URL for syncing exports",
 		Section:     "Export",
 	},
+	{
+		Name:        "export_mirrors",
+		Description: "Extra remotes the export repo is pushed to, as comma-separated name=url pairs (manage with fp export remotes)",
+		Section:     "Export",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "export_squash_days",
+		Description: "Squash the export repo's history into one commit during 'fp maintenance' once it is older than this many days",
+		Section:     "Export",
+		HideIfEmpty: true,
+		Type:        ConfigInt,
+	},
+	{
+		Name:        "export_format",
+		Default:     "csv",
+		Description: "Export format: csv, jsonl, parquet, ics, ics-daily (CSV is always written)",
+		Section:     "Export",
+		Type:        ConfigEnum,
+		Values:      []string{"csv", "jsonl", "parquet", "ics", "ics-daily"},
+	},
+	{
+		Name:        "export_http_url",
+		Description: "HTTPS endpoint for the http export sink",
+		Section:     "Export",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "export_http_token",
+		Description: "Bearer token sent with HTTP exports",
+		Section:     "Export",
+		HideIfEmpty: true,
+		Secret:      true,
+	},
+	{
+		Name:        "export_sinks",
+		Description: "Comma-separated export destinations: git, http, s3, stdout",
+		Section:     "Export",
+		HideIfEmpty: true,
+		Type:        ConfigList,
+		Values:      []string{"git", "http", "s3", "stdout"},
+	},
+	{
+		Name:        "export_mine_only",
+		Description: "When true, only export commits authored by one of your identities",
+		Section:     "Export",
+		HideIfEmpty: true,
+		Type:        ConfigBool,
+	},
+	{
+		Name:        "export_columns",
+		Description: "Comma-separated CSV columns to write, in order (default: all)",
+		Section:     "Export",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "export_redact_messages",
+		Description: "Keep commit messages out of exports: true (drop) or hash",
+		Section:     "Export",
+		HideIfEmpty: true,
+		Type:        ConfigEnum,
+		Values:      []string{"true", "false", "drop", "hash"},
+	},
+	{
+		Name:        "export_redact_emails",
+		Description: "When true, leave author emails out of exports (author_id is kept)",
+		Section:     "Export",
+		HideIfEmpty: true,
+		Type:        ConfigBool,
+	},
+	{
+		Name:        "export_exclude_repos",
+		Description: "Comma-separated repo ids never exported, * matches within a segment (e.g. github.com/acme/*)",
+		Section:     "Export",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "export_encrypt_recipient",
+		Description: "Encrypt export files before committing them: an age recipient (age1..., ssh-...) or a gpg key id or email",
+		Section:     "Export",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "export_encrypt_identity",
+		Description: "age identity file that decrypts exports, for syncing and fp import (gpg uses its keyring)",
+		Section:     "Export",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "export_s3_bucket",
+		Description: "S3 bucket for the s3 export sink",
+		Section:     "Export",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "export_s3_region",
+		Description: "S3 region (default: us-east-1)",
+		Section:     "Export",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "export_s3_prefix",
+		Description: "Key prefix for objects written by the s3 export sink",
+		Section:     "Export",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "export_s3_endpoint",
+		Description: "S3-compatible endpoint URL, for services other than AWS",
+		Section:     "Export",
+		HideIfEmpty: true,
+	},
+	// Stats
+	{
+		Name:        "context_switch_threshold",
+		Description: "Call out days with more context switches between repos than this in fp stats and reports",
+		Section:     "Stats",
+		HideIfEmpty: true,
+		Type:        ConfigInt,
+	},
+	// Enrichment
+	{
+		Name:        "enrichers",
+		Default:     "git, ticket",
+		Description: "Enrichers fp record runs on each new commit, in order (none turns the built-ins off); enrich_exec.<name> adds your own",
+		Section:     "Enrichment",
+	},
+	{
+		Name:        "ticket_pattern",
+		Description: "Regular expression for the issue ids the ticket enricher finds in branch names and commit subjects (default: keys like PROJ-123)",
+		Section:     "Enrichment",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "on_event_exec",
+		Description: "Command fp record starts after storing each event, with the event as JSON on stdin; it runs in the background (on_event_exec.<name> adds more)",
+		Section:     "Enrichment",
+		HideIfEmpty: true,
+	},
+	{
+		Name:        "on_event_filter",
+		Description: "Events on_event_exec runs for, e.g. 'repo:github.com/acme/* !source:post-checkout' (on_event_filter.<name> for on_event_exec.<name>)",
+		Section:     "Enrichment",
+		HideIfEmpty: true,
+		Type:        ConfigSelector,
+	},
+	// Maintenance
+	{
+		Name:        "retention_days",
+		Description: "Delete exported events older than this many days during 'fp maintenance'",
+		Section:     "Maintenance",
+		HideIfEmpty: true,
+		Type:        ConfigInt,
+	},
 	// Hidden (internal)
 	{
 		Name:        "export_last",
@@ -72,6 +325,15 @@ var ConfigKeys = []ConfigKey{
 		Description: "Unix timestamp of last export",
 		Section:     "Export",
 		Hidden:      true,
+		Type:        ConfigInt,
+	},
+	{
+		Name:        "maintenance_last",
+		Default:     "0",
+		Description: "Unix timestamp of last maintenance run",
+		Section:     "Maintenance",
+		Hidden:      true,
+		Type:        ConfigInt,
 	},
 	// Color Overrides - override specific colors from the current theme (ANSI 0-255)
 	{
@@ -79,51 +341,160 @@ var ConfigKeys = []ConfigKey{
 		Description: "Override success color from current theme (ANSI 0-255)",
 		Section:     "Color Overrides",
 		HideIfEmpty: true,
+		Type:        ConfigColor,
 	},
 	{
 		Name:        "color_warning",
 		Description: "Override warning color from current theme (ANSI 0-255)",
 		Section:     "Color Overrides",
 		HideIfEmpty: true,
+		Type:        ConfigColor,
 	},
 	{
 		Name:        "color_error",
 		Description: "Override error color from current theme (ANSI 0-255)",
 		Section:     "Color Overrides",
 		HideIfEmpty: true,
+		Type:        ConfigColor,
 	},
 	{
 		Name:        "color_info",
 		Description: "Override info color from current theme (ANSI 0-255)",
 		Section:     "Color Overrides",
 		HideIfEmpty: true,
+		Type:        ConfigColor,
 	},
 	{
 		Name:        "color_muted",
 		Description: "Override muted text color from current theme (ANSI 0-255)",
 		Section:     "Color Overrides",
 		HideIfEmpty: true,
+		Type:        ConfigColor,
 	},
 	{
 		Name:        "color_header",
 		Description: "Override header style from current theme (ANSI 0-255 or 'bold')",
 		Section:     "Color Overrides",
 		HideIfEmpty: true,
+		Type:        ConfigColor,
+		Values:      []string{"bold"},
 	},
 	{
 		Name:        "color_ui_active",
 		Description: "Override focused/active UI color from current theme (ANSI 0-255)",
 		Section:     "Color Overrides",
 		HideIfEmpty: true,
+		Type:        ConfigColor,
 	},
 	{
 		Name:        "color_ui_dim",
 		Description: "Override unfocused/inactive UI color from current theme (ANSI 0-255)",
 		Section:     "Color Overrides",
 		HideIfEmpty: true,
+		Type:        ConfigColor,
+	},
+	{
+		Name:        "source_colors.post_commit",
+		Description: "Override POST-COMMIT event color (ANSI 0-255 or #hex)",
+		Section:     "Color Overrides",
+		HideIfEmpty: true,
+		Type:        ConfigColor,
+	},
+	{
+		Name:        "source_colors.post_rewrite",
+		Description: "Override POST-REWRITE event color (ANSI 0-255 or #hex)",
+		Section:     "Color Overrides",
+		HideIfEmpty: true,
+		Type:        ConfigColor,
+	},
+	{
+		Name:        "source_colors.post_checkout",
+		Description: "Override POST-CHECKOUT event color (ANSI 0-255 or #hex)",
+		Section:     "Color Overrides",
+		HideIfEmpty: true,
+		Type:        ConfigColor,
+	},
+	{
+		Name:        "source_colors.post_merge",
+		Description: "Override POST-MERGE event color (ANSI 0-255 or #hex)",
+		Section:     "Color Overrides",
+		HideIfEmpty: true,
+		Type:        ConfigColor,
+	},
+	{
+		Name:        "source_colors.pre_push",
+		Description: "Override PRE-PUSH event color (ANSI 0-255 or #hex)",
+		Section:     "Color Overrides",
+		HideIfEmpty: true,
+		Type:        ConfigColor,
+	},
+	{
+		Name:        "source_colors.backfill",
+		Description: "Override BACKFILL event color (ANSI 0-255 or #hex)",
+		Section:     "Color Overrides",
+		HideIfEmpty: true,
+		Type:        ConfigColor,
+	},
+	{
+		Name:        "source_colors.manual",
+		Description: "Override MANUAL event color (ANSI 0-255 or #hex)",
+		Section:     "Color Overrides",
+		HideIfEmpty: true,
+		Type:        ConfigColor,
+	},
+	{
+		Name:        "source_colors.branch_create",
+		Description: "Override BRANCH-CREATE event color (ANSI 0-255 or #hex)",
+		Section:     "Color Overrides",
+		HideIfEmpty: true,
+		Type:        ConfigColor,
+	},
+	{
+		Name:        "source_colors.branch_delete",
+		Description: "Override BRANCH-DELETE event color (ANSI 0-255 or #hex)",
+		Section:     "Color Overrides",
+		HideIfEmpty: true,
+		Type:        ConfigColor,
+	},
+	{
+		Name:        "source_colors.stash",
+		Description: "Override STASH event color (ANSI 0-255 or #hex)",
+		Section:     "Color Overrides",
+		HideIfEmpty: true,
+		Type:        ConfigColor,
 	},
 }
 
+// ConfigKeyRename records a config key that was renamed.
+type ConfigKeyRename struct {
+	Old string
+	New string
+}
+
+// ConfigKeyRenames lists renamed config keys, oldest first, so a config
+// file written by an older fp keeps working: the old keys are rewritten to
+// the new ones when the file is read. Add an entry whenever a key in
+// ConfigKeys is renamed, for example
+//
+//	{Old: "export_interval_sec", New: "export.interval"},
+var ConfigKeyRenames = []ConfigKeyRename{
+	{Old: "display_date", New: "date_format"},
+	{Old: "display_time", New: "time_format"},
+}
+
+// RenamedConfigKey returns the current name of a key that was renamed,
+// following later renames of the new name too.
+func RenamedConfigKey(name string) (string, bool) {
+	renamed := false
+	for _, r := range ConfigKeyRenames {
+		if r.Old == name {
+			name = r.New
+			renamed = true
+		}
+	}
+	return name, renamed
+}
+
 // configKeyMap is a lookup map for configuration keys.
 var configKeyMap map[string]ConfigKey
 
@@ -140,10 +511,18 @@ func GetConfigKey(name string) (ConfigKey, bool) {
 	return key, ok
 }
 
-// IsValidConfigKey checks if a key name is valid.
+// IsValidConfigKey checks if a key name is valid: one of ConfigKeys, or a
+// key named by the user under one of configKeyPrefixes.
 func IsValidConfigKey(name string) bool {
-	_, ok := configKeyMap[name]
-	return ok
+	if _, ok := configKeyMap[name]; ok {
+		return true
+	}
+	for _, prefix := range configKeyPrefixes {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && rest != "" {
+			return true
+		}
+	}
+	return false
 }
 
 // GetDefaultValue returns the default value for a config key.
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ConfigType is the kind of value a config key holds.
+type ConfigType int
+
+const (
+	ConfigString   ConfigType = iota // Any text
+	ConfigBool                       // true/false (also yes/no, on/off, 1/0)
+	ConfigInt                        // A whole number, 0 or more
+	ConfigEnum                       // One of the key's Values
+	ConfigList                       // Comma-separated, each item one of the key's Values
+	ConfigColor                      // ANSI 0-255, #hex, or one of the key's Values
+	ConfigSelector                   // An EventSelector expression
+)
+
+// String returns the name of the type, as shown by fp config list --defaults.
+func (t ConfigType) String() string {
+	switch t {
+	case ConfigBool:
+		return "bool"
+	case ConfigInt:
+		return "number"
+	case ConfigEnum:
+		return "enum"
+	case ConfigList:
+		return "list"
+	case ConfigColor:
+		return "color"
+	case ConfigSelector:
+		return "selector"
+	default:
+		return "string"
+	}
+}
+
+// configKeyPrefixes start keys that are named by the user, such as
+// enrich_exec.<name>, so they can't be listed in ConfigKeys.
+var configKeyPrefixes = []string{"enrich_exec.", "on_event_exec.", "on_event_filter."}
+
+// ValidateConfigValue checks value fits the type of the config key name.
+// An empty value unsets the key and always fits, as do the values of keys
+// outside ConfigKeys. A key named by the user, such as on_event_filter.<name>,
+// takes the type of the key it is named after (on_event_filter), if any.
+func ValidateConfigValue(name, value string) error {
+	key, ok := GetConfigKey(name)
+	if !ok {
+		for _, prefix := range configKeyPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				key, ok = GetConfigKey(strings.TrimSuffix(prefix, "."))
+				break
+			}
+		}
+	}
+	value = strings.TrimSpace(value)
+	if !ok || value == "" {
+		return nil
+	}
+
+	switch key.Type {
+	case ConfigBool:
+		switch strings.ToLower(value) {
+		case "true", "false", "yes", "no", "on", "off", "1", "0":
+			return nil
+		}
+		return fmt.Errorf("%s must be true or false, got '%s'", name, value)
+
+	case ConfigInt:
+		if n, err := strconv.Atoi(value); err != nil || n < 0 {
+			return fmt.Errorf("%s must be a non-negative number, got '%s'", name, value)
+		}
+
+	case ConfigEnum:
+		if !hasValue(key.Values, value) {
+			return fmt.Errorf("%s must be one of %s, got '%s'", name, strings.Join(key.Values, ", "), value)
+		}
+
+	case ConfigList:
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item != "" && !hasValue(key.Values, item) {
+				return fmt.Errorf("%s takes a comma-separated list of %s, got '%s'", name, strings.Join(key.Values, ", "), item)
+			}
+		}
+
+	case ConfigColor:
+		if !isColor(value) && !hasValue(key.Values, value) {
+			return fmt.Errorf("%s must be an ANSI color (0-255) or #hex, got '%s'", name, value)
+		}
+
+	case ConfigSelector:
+		if _, err := ParseEventSelector(value); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func hasValue(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(v, value) })
+}
+
+// isColor reports whether value is an ANSI 256 color number or a #rgb or
+// #rrggbb hex color.
+func isColor(value string) bool {
+	if hex, ok := strings.CutPrefix(value, "#"); ok {
+		if len(hex) != 3 && len(hex) != 6 {
+			return false
+		}
+		_, err := strconv.ParseUint(hex, 16, 32)
+		return err == nil
+	}
+	n, err := strconv.Atoi(value)
+	return err == nil && n >= 0 && n <= 255
+}
@@ -102,6 +102,8 @@ func TestEventSource_String(t *testing.T) {
 		{SourcePostCommit, "POST-COMMIT"},
 		{SourceBackfill, "BACKFILL"},
 		{SourceManual, "MANUAL"},
+		{SourceBranchCreate, "BRANCH-CREATE"},
+		{SourceStash, "STASH"},
 		{EventSource(99), "UNKNOWN"},
 	}
 
@@ -119,6 +121,10 @@ func TestParseEventSource(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, SourceBackfill, source)
 
+	source, ok = ParseEventSource("branch-delete")
+	require.True(t, ok)
+	require.Equal(t, SourceBranchDelete, source)
+
 	_, ok = ParseEventSource("invalid")
 	require.False(t, ok)
 }
@@ -135,6 +141,114 @@ func TestConfigKey_IsValidConfigKey(t *testing.T) {
 	require.False(t, IsValidConfigKey("invalid_key"))
 }
 
+func TestRenamedConfigKey(t *testing.T) {
+	saved := ConfigKeyRenames
+	t.Cleanup(func() { ConfigKeyRenames = saved })
+	ConfigKeyRenames = []ConfigKeyRename{
+		{Old: "export_every", New: "export_interval"},
+		{Old: "export_interval", New: "export.interval"},
+	}
+
+	name, ok := RenamedConfigKey("export_every")
+	require.True(t, ok)
+	require.Equal(t, "export.interval", name, "later renames are followed")
+
+	name, ok = RenamedConfigKey("theme")
+	require.False(t, ok)
+	require.Equal(t, "theme", name)
+}
+
+func TestValidateConfigValue(t *testing.T) {
+	valid := map[string]string{
+		"enable_log":          "yes",
+		"export_interval_sec": "600",
+		"time_format":         "12H",
+		"week_start":          "Sunday",
+		"export_sinks":        "git, http",
+		"color_muted":         "245",
+		"color_header":        "bold",
+		"source_colors.stash": "#e69f00",
+		"theme":               "anything",
+		"retention_days":      "",
+		"enrich_exec.jira":    "~/bin/jira.sh",
+		"on_event_filter":     "repo:github.com/acme/* !source:post_checkout",
+		"on_event_filter.ci":  "branch:main,release/*",
+	}
+	for key, value := range valid {
+		require.NoError(t, ValidateConfigValue(key, value), "%s=%s", key, value)
+	}
+
+	invalid := map[string]string{
+		"enable_log":          "maybe",
+		"export_interval_sec": "-1",
+		"time_format":         "25h",
+		"week_start":          "thursday",
+		"export_sinks":        "git, ftp",
+		"color_muted":         "256",
+		"source_colors.stash": "#e69f0",
+		"on_event_filter":     "source:checkout",
+		"on_event_filter.ci":  "author:me",
+	}
+	for key, value := range invalid {
+		require.Error(t, ValidateConfigValue(key, value), "%s=%s", key, value)
+	}
+}
+
+func TestConfigKeys_DefaultsValidate(t *testing.T) {
+	for _, key := range ConfigKeys {
+		require.NoError(t, ValidateConfigValue(key.Name, key.Default), key.Name)
+	}
+}
+
+func TestConfigKey_IsValidConfigKey_Prefixes(t *testing.T) {
+	require.True(t, IsValidConfigKey("enrich_exec.jira"))
+	require.False(t, IsValidConfigKey("enrich_exec."))
+	require.True(t, IsValidConfigKey("on_event_exec.slack"))
+	require.True(t, IsValidConfigKey("on_event_filter.slack"))
+}
+
+func TestEventSelector_Matches(t *testing.T) {
+	tests := []struct {
+		expr   string
+		repo   string
+		branch string
+		source EventSource
+		want   bool
+	}{
+		{"", "github.com/me/app", "main", SourcePostCheckout, true},
+		{"source:post-commit,pre-push", "r", "main", SourcePrePush, true},
+		{"source:POST_COMMIT", "r", "main", SourcePostCommit, true},
+		{"source:post-commit", "r", "main", SourcePostCheckout, false},
+		{"!source:post-checkout", "r", "main", SourcePostCheckout, false},
+		{"!source:post-checkout", "r", "main", SourcePostMerge, true},
+		{"repo:github.com/acme/*", "github.com/acme/app", "main", SourcePostCommit, true},
+		{"repo:github.com/acme/*", "github.com/acme/app/sub", "main", SourcePostCommit, false},
+		{"branch:release/*", "r", "release/1.2", SourcePostCommit, true},
+		{"branch:main", "r", "", SourcePostCommit, false},
+		{"repo:github.com/acme/* !branch:wip/*", "github.com/acme/app", "wip/x", SourcePostCommit, false},
+		{"repo:a repo:b", "a", "main", SourcePostCommit, false},
+	}
+	for _, tt := range tests {
+		s, err := ParseEventSelector(tt.expr)
+		require.NoError(t, err, tt.expr)
+		require.Equal(t, tt.want, s.Matches(tt.repo, tt.branch, tt.source), "%q on %s %s %s", tt.expr, tt.repo, tt.branch, tt.source)
+	}
+}
+
+func TestParseEventSelector_Errors(t *testing.T) {
+	for expr, want := range map[string]string{
+		"post-commit":       "expected field:value",
+		"source:":           "expected field:value",
+		"source:,":          "expected field:value",
+		"source:checkout":   "unknown source 'checkout'",
+		"author:me":         "unknown field 'author'",
+		"repo:github.com/[": "bad pattern",
+	} {
+		_, err := ParseEventSelector(expr)
+		require.ErrorContains(t, err, want, expr)
+	}
+}
+
 func TestConfigKey_GetDefaultValue(t *testing.T) {
 	val, ok := GetDefaultValue("export_interval_sec")
 	require.True(t, ok)
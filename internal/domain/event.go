@@ -62,6 +62,9 @@ const (
 	SourcePrePush      EventSource = 4 // stable
 	SourceManual       EventSource = 5 // stable
 	SourceBackfill     EventSource = 6 // stable
+	SourceBranchCreate EventSource = 7 // stable
+	SourceBranchDelete EventSource = 8 // stable
+	SourceStash        EventSource = 9 // stable
 )
 
 // String returns the string representation of the source.
@@ -81,6 +84,12 @@ func (s EventSource) String() string {
 		return "MANUAL"
 	case SourceBackfill:
 		return "BACKFILL"
+	case SourceBranchCreate:
+		return "BRANCH-CREATE"
+	case SourceBranchDelete:
+		return "BRANCH-DELETE"
+	case SourceStash:
+		return "STASH"
 	default:
 		return "UNKNOWN"
 	}
@@ -103,6 +112,12 @@ func ParseEventSource(s string) (EventSource, bool) {
 		return SourceManual, true
 	case "BACKFILL":
 		return SourceBackfill, true
+	case "BRANCH-CREATE":
+		return SourceBranchCreate, true
+	case "BRANCH-DELETE":
+		return SourceBranchDelete, true
+	case "STASH":
+		return SourceStash, true
 	default:
 		return 0, false
 	}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// EventSelector decides which events an integration such as on_event_exec
+// sees. It is written as space-separated terms, all of which must hold:
+//
+//	repo:github.com/acme/* source:post-commit,pre-push !branch:wip/*
+//
+// A term is a field (repo, source or branch) and comma-separated values,
+// any of which may match; ! in front negates the term. Repo and branch
+// values are globs where * doesn't cross a /. The empty selector matches
+// every event.
+type EventSelector struct {
+	terms []selectorTerm
+}
+
+type selectorTerm struct {
+	field  string
+	values []string
+	negate bool
+}
+
+// Fields an EventSelector can test.
+const (
+	selectorRepo   = "repo"
+	selectorSource = "source"
+	selectorBranch = "branch"
+)
+
+// ParseEventSelector parses a selector expression (see EventSelector).
+func ParseEventSelector(expr string) (EventSelector, error) {
+	var s EventSelector
+	for _, word := range strings.Fields(expr) {
+		term := selectorTerm{}
+		word, term.negate = strings.CutPrefix(word, "!")
+		field, values, found := strings.Cut(word, ":")
+		term.field = strings.ToLower(field)
+		if !found || values == "" {
+			return EventSelector{}, fmt.Errorf("expected field:value, got '%s'", word)
+		}
+
+		for _, v := range strings.Split(values, ",") {
+			if v == "" {
+				continue
+			}
+			switch term.field {
+			case selectorSource:
+				src, ok := ParseEventSource(strings.ReplaceAll(v, "_", "-"))
+				if !ok {
+					return EventSelector{}, fmt.Errorf("unknown source '%s'", v)
+				}
+				v = src.String()
+			case selectorRepo, selectorBranch:
+				if _, err := path.Match(v, ""); err != nil {
+					return EventSelector{}, fmt.Errorf("bad pattern '%s' for %s", v, term.field)
+				}
+			default:
+				return EventSelector{}, fmt.Errorf("unknown field '%s': use repo, source or branch", field)
+			}
+			term.values = append(term.values, v)
+		}
+		if len(term.values) == 0 {
+			return EventSelector{}, fmt.Errorf("expected field:value, got '%s'", word)
+		}
+		s.terms = append(s.terms, term)
+	}
+	return s, nil
+}
+
+// Matches reports whether an event from repoID on branch, recorded by
+// source, is selected.
+func (s EventSelector) Matches(repoID, branch string, source EventSource) bool {
+	for _, t := range s.terms {
+		var hit bool
+		switch t.field {
+		case selectorSource:
+			hit = slices.Contains(t.values, source.String())
+		case selectorRepo:
+			hit = matchAny(t.values, repoID)
+		case selectorBranch:
+			hit = matchAny(t.values, branch)
+		}
+		if hit == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty reports whether the selector has no terms and so selects every
+// event.
+func (s EventSelector) IsEmpty() bool {
+	return len(s.terms) == 0
+}
+
+func matchAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, value); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+// DefaultTicketPattern matches issue keys like PROJ-123.
+const DefaultTicketPattern = `\b[A-Z][A-Z0-9]+-[0-9]+\b`
+
+// gitEnricher adds the author and size of the commit.
+type gitEnricher struct {
+	metadata func(repoPath, commit string) git.CommitMetadata
+}
+
+func (gitEnricher) Name() string { return "git" }
+
+func (g gitEnricher) Enrich(_ context.Context, e Event) (map[string]string, error) {
+	if e.Commit == "" {
+		return nil, nil
+	}
+	meta := g.metadata(e.RepoPath, e.Commit)
+	if meta.AuthorEmail == "" && meta.Subject == "" {
+		return nil, errors.New("no metadata for commit " + e.Commit)
+	}
+	return map[string]string{
+		"author_email":  meta.AuthorEmail,
+		"subject":       meta.Subject,
+		"files_changed": strconv.Itoa(meta.FilesChanged),
+		"insertions":    strconv.Itoa(meta.Insertions),
+		"deletions":     strconv.Itoa(meta.Deletions),
+	}, nil
+}
+
+// ticketEnricher finds the issue an event belongs to in its branch name,
+// or else in the commit subject found by the git enricher.
+type ticketEnricher struct {
+	pattern *regexp.Regexp
+}
+
+func (ticketEnricher) Name() string { return "ticket" }
+
+func (t ticketEnricher) Enrich(_ context.Context, e Event) (map[string]string, error) {
+	for _, text := range []string{e.Branch, e.Fields["git.subject"]} {
+		if id := t.pattern.FindString(text); id != "" {
+			return map[string]string{"id": id}, nil
+		}
+	}
+	return nil, nil
+}
+
+// LinesByLanguage adds up the file stats of a commit per language, in
+// language order.
+func LinesByLanguage(stats []git.FileStat) []store.LanguageLines {
+	byLanguage := make(map[string]*store.LanguageLines)
+	for _, s := range stats {
+		lang := Language(s.Path)
+		l := byLanguage[lang]
+		if l == nil {
+			l = &store.LanguageLines{Language: lang}
+			byLanguage[lang] = l
+		}
+		l.Insertions += s.Insertions
+		l.Deletions += s.Deletions
+	}
+	out := make([]store.LanguageLines, 0, len(byLanguage))
+	for _, l := range byLanguage {
+		out = append(out, *l)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Language < out[j].Language })
+	return out
+}
+
+// languagesByExt names the language of a file from its extension.
+var languagesByExt = map[string]string{
+	".go":    "Go",
+	".py":    "Python",
+	".rb":    "Ruby",
+	".rs":    "Rust",
+	".js":    "JavaScript",
+	".mjs":   "JavaScript",
+	".cjs":   "JavaScript",
+	".jsx":   "JavaScript",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".java":  "Java",
+	".kt":    "Kotlin",
+	".swift": "Swift",
+	".c":     "C",
+	".h":     "C",
+	".cc":    "C++",
+	".cpp":   "C++",
+	".hpp":   "C++",
+	".cs":    "C#",
+	".php":   "PHP",
+	".lua":   "Lua",
+	".sh":    "Shell",
+	".bash":  "Shell",
+	".zsh":   "Shell",
+	".sql":   "SQL",
+	".html":  "HTML",
+	".css":   "CSS",
+	".scss":  "CSS",
+	".md":    "Markdown",
+	".txt":   "Text",
+	".json":  "JSON",
+	".yml":   "YAML",
+	".yaml":  "YAML",
+	".toml":  "TOML",
+	".xml":   "XML",
+	".proto": "Protobuf",
+	".tf":    "Terraform",
+}
+
+// languagesByName covers files known by name rather than extension.
+var languagesByName = map[string]string{
+	"Makefile":   "Makefile",
+	"Dockerfile": "Dockerfile",
+	"go.mod":     "Go",
+	"go.sum":     "Go",
+}
+
+// Language names the language of a file, or "Other".
+func Language(file string) string {
+	base := path.Base(file)
+	if lang, ok := languagesByName[base]; ok {
+		return lang
+	}
+	if lang, ok := languagesByExt[strings.ToLower(path.Ext(base))]; ok {
+		return lang
+	}
+	return "Other"
+}
@@ -0,0 +1,77 @@
+package enrich
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/store"
+)
+
+func TestGitEnricher(t *testing.T) {
+	g := gitEnricher{metadata: func(repoPath, commit string) git.CommitMetadata {
+		require.Equal(t, "/src/repo", repoPath)
+		return git.CommitMetadata{AuthorEmail: "me@example.com", Subject: "Fix login", FilesChanged: 2, Insertions: 10, Deletions: 3}
+	}}
+
+	fields, err := g.Enrich(context.Background(), Event{RepoPath: "/src/repo", Commit: "abc123"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"author_email":  "me@example.com",
+		"subject":       "Fix login",
+		"files_changed": "2",
+		"insertions":    "10",
+		"deletions":     "3",
+	}, fields)
+
+	g.metadata = func(string, string) git.CommitMetadata { return git.CommitMetadata{} }
+	_, err = g.Enrich(context.Background(), Event{Commit: "abc123"})
+	require.Error(t, err)
+}
+
+func TestTicketEnricher(t *testing.T) {
+	ticket := ticketEnricher{pattern: regexp.MustCompile(DefaultTicketPattern)}
+
+	tests := []struct {
+		name   string
+		branch string
+		fields map[string]string
+		want   map[string]string
+	}{
+		{"branch", "feature/PAY-42-refunds", map[string]string{"git.subject": "OPS-1 something"}, map[string]string{"id": "PAY-42"}},
+		{"subject", "main", map[string]string{"git.subject": "Fix login (AUTH-7)"}, map[string]string{"id": "AUTH-7"}},
+		{"none", "main", map[string]string{"git.subject": "utf-8 fixes"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ticket.Enrich(context.Background(), Event{Branch: tt.branch, Fields: tt.fields})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLinesByLanguage(t *testing.T) {
+	lines := LinesByLanguage([]git.FileStat{
+		{Path: "cmd/main.go", Insertions: 10, Deletions: 2},
+		{Path: "internal/x_test.go", Insertions: 5},
+		{Path: "go.mod", Insertions: 1, Deletions: 1},
+		{Path: "README.md", Insertions: 3},
+		{Path: "logo.png"},
+	})
+	require.Equal(t, []store.LanguageLines{
+		{Language: "Go", Insertions: 16, Deletions: 3},
+		{Language: "Markdown", Insertions: 3},
+		{Language: "Other"},
+	}, lines)
+}
+
+func TestLanguage(t *testing.T) {
+	require.Equal(t, "Go", Language("a/b/c.go"))
+	require.Equal(t, "YAML", Language(".github/workflows/ci.YML"))
+	require.Equal(t, "Makefile", Language("Makefile"))
+	require.Equal(t, "Other", Language("LICENSE"))
+}
@@ -0,0 +1,124 @@
+package enrich
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/config"
+	"github.com/footprint-tools/cli/internal/git"
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// DefaultOrder is the enrichers run when the enrichers config key isn't
+// set. The ticket enricher reads the subject found by the git enricher,
+// so it comes after it.
+const DefaultOrder = "git, ticket"
+
+// retiredLanguage was the enricher storing lines per language as fields.
+// fp now stores them for every commit (see store.SaveCommitLanguages), so
+// the name is still reserved but runs nothing.
+const retiredLanguage = "language"
+
+// execPrefix starts the config keys of user enrichers:
+// enrich_exec.<name> = <command>.
+const execPrefix = "enrich_exec."
+
+// FromConfig builds the pipeline from config: the built-in enrichers named
+// in the enrichers key, in that order, and a user enricher for each
+// enrich_exec.<name> key. User enrichers run where enrichers names them,
+// or after the built-ins in name order. "none" turns the built-ins off.
+func FromConfig() Pipeline {
+	all, _ := config.GetAll()
+	return build(all)
+}
+
+func build(cfg map[string]string) Pipeline {
+	execs := make(map[string]Enricher)
+	for key, command := range cfg {
+		name, ok := strings.CutPrefix(key, execPrefix)
+		if !ok || strings.TrimSpace(command) == "" {
+			continue
+		}
+		if !validKey(name) || strings.Contains(name, ".") || isBuiltin(name) {
+			log.Warn("enrich: ignoring %s: invalid or reserved enricher name", key)
+			continue
+		}
+		execs[name] = execEnricher{name: name, command: command}
+	}
+
+	order, ok := cfg["enrichers"]
+	if !ok {
+		order = DefaultOrder
+	}
+
+	var (
+		p    Pipeline
+		seen = make(map[string]bool)
+	)
+	for _, name := range splitNames(order) {
+		if name == "none" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if name == retiredLanguage {
+			log.Debug("enrich: languages are stored with every commit, skipping the language enricher")
+			continue
+		}
+		if en, ok := execs[name]; ok {
+			p.Enrichers = append(p.Enrichers, en)
+			continue
+		}
+		en := builtin(name, cfg)
+		if en == nil {
+			log.Warn("enrich: unknown enricher '%s' in enrichers", name)
+			continue
+		}
+		p.Enrichers = append(p.Enrichers, en)
+	}
+
+	var rest []string
+	for name := range execs {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		p.Enrichers = append(p.Enrichers, execs[name])
+	}
+	return p
+}
+
+func isBuiltin(name string) bool {
+	switch name {
+	case "git", "ticket", retiredLanguage:
+		return true
+	}
+	return false
+}
+
+// builtin returns the built-in enricher called name, or nil.
+func builtin(name string, cfg map[string]string) Enricher {
+	switch name {
+	case "git":
+		return gitEnricher{metadata: git.GetCommitMetadata}
+	case "ticket":
+		pattern := strings.TrimSpace(cfg["ticket_pattern"])
+		if pattern == "" {
+			pattern = DefaultTicketPattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			log.Warn("enrich: invalid ticket_pattern '%s', using the default: %v", pattern, err)
+			re = regexp.MustCompile(DefaultTicketPattern)
+		}
+		return ticketEnricher{pattern: re}
+	}
+	return nil
+}
+
+// splitNames splits a list of names separated by commas or spaces.
+func splitNames(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+}
@@ -0,0 +1,47 @@
+package enrich
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  map[string]string
+		want []string
+	}{
+		{"default order", map[string]string{}, []string{"git", "ticket"}},
+		{"chosen order", map[string]string{"enrichers": "ticket,git"}, []string{"ticket", "git"}},
+		{"language is retired", map[string]string{"enrichers": "language, git", "enrich_exec.language": "x"}, []string{"git"}},
+		{"none", map[string]string{"enrichers": "none"}, []string{}},
+		{"unknown names are skipped", map[string]string{"enrichers": "git, nope, git"}, []string{"git"}},
+		{
+			"plugins run after the built-ins",
+			map[string]string{"enrichers": "git", "enrich_exec.zeta": "z", "enrich_exec.alpha": "a"},
+			[]string{"git", "alpha", "zeta"},
+		},
+		{
+			"plugins run where named",
+			map[string]string{"enrichers": "jira, git", "enrich_exec.jira": "j"},
+			[]string{"jira", "git"},
+		},
+		{
+			"reserved and empty plugins are ignored",
+			map[string]string{"enrichers": "none", "enrich_exec.git": "x", "enrich_exec.a.b": "x", "enrich_exec.empty": " "},
+			[]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, build(tt.cfg).Names())
+		})
+	}
+}
+
+func TestBuild_InvalidTicketPattern(t *testing.T) {
+	p := build(map[string]string{"enrichers": "ticket", "ticket_pattern": "(["})
+	require.Len(t, p.Enrichers, 1)
+	require.Equal(t, DefaultTicketPattern, p.Enrichers[0].(ticketEnricher).pattern.String())
+}
@@ -0,0 +1,148 @@
+// Package enrich derives extra fields from recorded events. An enricher
+// looks at one event and returns named values; a Pipeline runs enrichers
+// in order and keeps going when one fails, so a new derived field never
+// puts recording the event itself at risk.
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is how long one enricher may run before the pipeline
+// gives up on it and moves on.
+const DefaultTimeout = 5 * time.Second
+
+// maxValueLen caps a field value, so a misbehaving plugin can't fill the
+// database.
+const maxValueLen = 1024
+
+// Event is what an enricher sees of an event.
+type Event struct {
+	RepoID    string    `json:"repo_id"`
+	RepoPath  string    `json:"repo_path"`
+	Commit    string    `json:"commit"`
+	Branch    string    `json:"branch"`
+	Source    string    `json:"source"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Fields holds what the enrichers before this one derived, so later
+	// enrichers can build on earlier ones.
+	Fields map[string]string `json:"fields"`
+}
+
+// Enricher derives fields from an event. Field names are relative to the
+// enricher: the pipeline stores "id" from the "ticket" enricher as
+// "ticket.id". Returning no fields is not an error.
+type Enricher interface {
+	Name() string
+	Enrich(ctx context.Context, e Event) (map[string]string, error)
+}
+
+// Failure is an enricher that returned an error, panicked or ran out of
+// time. The fields of the other enrichers are kept.
+type Failure struct {
+	Enricher string
+	Err      error
+}
+
+func (f Failure) Error() string {
+	return fmt.Sprintf("enricher %s: %v", f.Enricher, f.Err)
+}
+
+// Pipeline runs enrichers one after the other, in order.
+type Pipeline struct {
+	Enrichers []Enricher
+	Timeout   time.Duration // per enricher; 0 means DefaultTimeout
+}
+
+// Empty reports whether the pipeline has nothing to run.
+func (p Pipeline) Empty() bool {
+	return len(p.Enrichers) == 0
+}
+
+// Names returns the enricher names in the order they run.
+func (p Pipeline) Names() []string {
+	names := make([]string, len(p.Enrichers))
+	for i, en := range p.Enrichers {
+		names[i] = en.Name()
+	}
+	return names
+}
+
+// Run derives the fields of e, named "<enricher>.<field>". It always
+// returns what the enrichers that succeeded produced, along with the
+// failures of the others.
+func (p Pipeline) Run(ctx context.Context, e Event) (map[string]string, []Failure) {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	fields := make(map[string]string)
+	var failures []Failure
+	for _, en := range p.Enrichers {
+		e.Fields = maps.Clone(fields)
+		out, err := runOne(ctx, en, e, timeout)
+		if err != nil {
+			failures = append(failures, Failure{Enricher: en.Name(), Err: err})
+			continue
+		}
+		for key, value := range out {
+			key = strings.TrimSpace(key)
+			if !validKey(key) {
+				failures = append(failures, Failure{Enricher: en.Name(), Err: fmt.Errorf("invalid field name %q", key)})
+				continue
+			}
+			fields[en.Name()+"."+key] = truncateValue(strings.TrimSpace(value))
+		}
+	}
+	return fields, failures
+}
+
+type result struct {
+	fields map[string]string
+	err    error
+}
+
+// runOne runs a single enricher, turning a panic or a timeout into an
+// error. An enricher that ignores its context is left running in the
+// background rather than holding up the rest of the pipeline.
+func runOne(ctx context.Context, en Enricher, e Event, timeout time.Duration) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{err: fmt.Errorf("panic: %v", r)}
+			}
+		}()
+		fields, err := en.Enrich(ctx, e)
+		done <- result{fields: fields, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.fields, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out after %s", timeout)
+	}
+}
+
+// validKey reports whether key can name a field: not empty, and without
+// spaces or '=' so fields read back unambiguously in config-style output.
+func validKey(key string) bool {
+	return key != "" && !strings.ContainsAny(key, " \t\r\n=")
+}
+
+func truncateValue(value string) string {
+	if len(value) <= maxValueLen {
+		return value
+	}
+	return value[:maxValueLen]
+}
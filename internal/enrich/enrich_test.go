@@ -0,0 +1,86 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// funcEnricher adapts a function to Enricher.
+type funcEnricher struct {
+	name string
+	fn   func(ctx context.Context, e Event) (map[string]string, error)
+}
+
+func (f funcEnricher) Name() string { return f.name }
+
+func (f funcEnricher) Enrich(ctx context.Context, e Event) (map[string]string, error) {
+	return f.fn(ctx, e)
+}
+
+func fixed(name string, fields map[string]string) Enricher {
+	return funcEnricher{name, func(context.Context, Event) (map[string]string, error) { return fields, nil }}
+}
+
+func TestPipeline_NamespacesAndOrders(t *testing.T) {
+	var seen map[string]string
+	p := Pipeline{Enrichers: []Enricher{
+		fixed("first", map[string]string{"a": " 1 "}),
+		funcEnricher{"second", func(_ context.Context, e Event) (map[string]string, error) {
+			seen = e.Fields
+			return map[string]string{"b": e.Fields["first.a"] + "2"}, nil
+		}},
+	}}
+
+	fields, failures := p.Run(context.Background(), Event{Commit: "abc"})
+	require.Empty(t, failures)
+	require.Equal(t, map[string]string{"first.a": "1", "second.b": "12"}, fields)
+	require.Equal(t, map[string]string{"first.a": "1"}, seen, "later enrichers see earlier fields")
+	require.Equal(t, []string{"first", "second"}, p.Names())
+}
+
+func TestPipeline_IsolatesFailures(t *testing.T) {
+	p := Pipeline{
+		Timeout: 50 * time.Millisecond,
+		Enrichers: []Enricher{
+			funcEnricher{"broken", func(context.Context, Event) (map[string]string, error) {
+				return map[string]string{"lost": "x"}, errors.New("no network")
+			}},
+			funcEnricher{"panics", func(context.Context, Event) (map[string]string, error) { panic("boom") }},
+			funcEnricher{"slow", func(ctx context.Context, _ Event) (map[string]string, error) {
+				<-ctx.Done()
+				time.Sleep(time.Second) // ignores the deadline
+				return map[string]string{"late": "x"}, nil
+			}},
+			fixed("bad", map[string]string{"has space": "x", "ok": "y"}),
+			fixed("good", map[string]string{"long": strings.Repeat("v", maxValueLen+10)}),
+		},
+	}
+
+	start := time.Now()
+	fields, failures := p.Run(context.Background(), Event{})
+	require.Less(t, time.Since(start), time.Second, "a slow enricher doesn't hold up the pipeline")
+
+	require.Equal(t, map[string]string{"bad.ok": "y", "good.long": strings.Repeat("v", maxValueLen)}, fields)
+
+	byName := make(map[string]string)
+	for _, f := range failures {
+		byName[f.Enricher] = f.Error()
+	}
+	require.Len(t, byName, 4)
+	require.Contains(t, byName["broken"], "no network")
+	require.Contains(t, byName["panics"], "panic: boom")
+	require.Contains(t, byName["slow"], "timed out")
+	require.Contains(t, byName["bad"], `invalid field name "has space"`)
+}
+
+func TestPipeline_Empty(t *testing.T) {
+	require.True(t, Pipeline{}.Empty())
+	fields, failures := Pipeline{}.Run(context.Background(), Event{})
+	require.Empty(t, fields)
+	require.Empty(t, failures)
+}
@@ -0,0 +1,92 @@
+package enrich
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// execEnricher runs a user command as an enricher. The command gets the
+// event as JSON on stdin, including the fields derived before it, and
+// prints a JSON object of the fields it derives on stdout:
+//
+//	{"estimate": "3h", "customer": "acme"}
+//
+// Values that aren't strings are stored as their JSON text. A non-zero
+// exit is a failure, reported with the first line of stderr.
+type execEnricher struct {
+	name    string
+	command string
+}
+
+func (x execEnricher) Name() string { return x.name }
+
+func (x execEnricher) Enrich(ctx context.Context, e Event) (map[string]string, error) {
+	input, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := ShellCommand(ctx, x.command)
+	cmd.Dir = e.RepoPath
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := firstLine(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	return parseExecOutput(stdout.Bytes())
+}
+
+// parseExecOutput reads the JSON object a command printed. Printing
+// nothing means no fields.
+func parseExecOutput(out []byte) (map[string]string, error) {
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, errors.New("output is not a JSON object of fields")
+	}
+
+	fields := make(map[string]string, len(raw))
+	for key, value := range raw {
+		var s string
+		if err := json.Unmarshal(value, &s); err == nil {
+			fields[key] = s
+			continue
+		}
+		fields[key] = string(value)
+	}
+	return fields, nil
+}
+
+// ShellCommand runs command through the platform shell, so config values
+// can use arguments, pipes and ~. fp record's on_event_exec runs through
+// it too.
+func ShellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,33 @@
+package enrich
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecEnricher(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	x := execEnricher{name: "jira", command: `grep -q '"ticket.id":"PAY-42"' && echo '{"estimate": "3h", "points": 5}'`}
+	fields, err := x.Enrich(context.Background(), Event{RepoPath: t.TempDir(), Fields: map[string]string{"ticket.id": "PAY-42"}})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"estimate": "3h", "points": "5"}, fields)
+
+	x.command = `echo 'token expired' >&2; exit 3`
+	_, err = x.Enrich(context.Background(), Event{RepoPath: t.TempDir()})
+	require.ErrorContains(t, err, "token expired")
+}
+
+func TestParseExecOutput(t *testing.T) {
+	fields, err := parseExecOutput([]byte("  \n"))
+	require.NoError(t, err)
+	require.Nil(t, fields)
+
+	_, err = parseExecOutput([]byte("estimate=3h"))
+	require.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package format
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/config"
+)
+
+// localeVars name the user's locale, in the order POSIX gives them
+// precedence for dates and times.
+var localeVars = []string{"LC_ALL", "LC_TIME", "LANG"}
+
+// locale returns the language and region of the user's locale, as in "de"
+// and "AT" for de_AT.UTF-8. Both are empty when no locale is set, or for
+// the C and POSIX locales.
+func locale() (lang, region string) {
+	for _, name := range localeVars {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		value, _, _ = strings.Cut(value, ".")
+		value, _, _ = strings.Cut(value, "@")
+		if value == "C" || value == "POSIX" {
+			return "", ""
+		}
+		lang, region, _ = strings.Cut(strings.ReplaceAll(value, "-", "_"), "_")
+		return strings.ToLower(lang), strings.ToUpper(region)
+	}
+	return "", ""
+}
+
+// Regions and languages that differ from the day-first, 24-hour,
+// Monday-first conventions most locales share.
+var (
+	monthFirstRegions = []string{"US", "PH", "FM"}
+	isoDateRegions    = []string{"CA"}
+	isoDateLangs      = []string{"sv", "lt"}
+	slashYearLangs    = []string{"ja", "zh", "ko"} // 2006/01/02
+	dotDateLangs      = []string{"de", "ru", "pl", "fi", "nb", "nn", "no", "cs", "sk", "tr", "uk", "da", "ro", "hr", "sl", "sr", "bg", "et", "lv"}
+	dashDateLangs     = []string{"nl"} // 02-01-2006
+	twelveHourRegions = []string{"US", "CA", "AU", "NZ", "IN", "PH"}
+	sundayRegions     = []string{"US", "CA", "MX", "BR", "JP", "KR", "TW", "PH", "IL", "IN"}
+	saturdayRegions   = []string{"EG", "IR", "AF"}
+)
+
+// localeDate is the date layout date_format=locale uses. Without a locale
+// it is ISO 8601, which no one misreads.
+func localeDate() string {
+	lang, region := locale()
+	switch {
+	case lang == "":
+		return isoDate
+	case slices.Contains(monthFirstRegions, region):
+		return usDate
+	case slices.Contains(isoDateRegions, region), slices.Contains(isoDateLangs, lang):
+		return isoDate
+	case slices.Contains(slashYearLangs, lang):
+		return "2006/01/02"
+	case slices.Contains(dotDateLangs, lang):
+		return dotDate
+	case slices.Contains(dashDateLangs, lang):
+		return "02-01-2006"
+	default:
+		return euDate
+	}
+}
+
+// locale12h reports whether time_format=locale uses a 12-hour clock.
+func locale12h() bool {
+	_, region := locale()
+	return slices.Contains(twelveHourRegions, region)
+}
+
+// WeekStart returns the day weeks start on, by the week_start key: Monday
+// (ISO 8601) unless set to sunday, saturday or locale.
+func WeekStart() time.Weekday {
+	weekStart, _ := config.Get("week_start")
+	switch strings.ToLower(weekStart) {
+	case "sunday":
+		return time.Sunday
+	case "saturday":
+		return time.Saturday
+	case "locale":
+		_, region := locale()
+		switch {
+		case slices.Contains(sundayRegions, region):
+			return time.Sunday
+		case slices.Contains(saturdayRegions, region):
+			return time.Saturday
+		}
+	}
+	return time.Monday
+}
+
+// DaysIntoWeek returns how many days wd is past the start of weeks that
+// start on start: from 0 to 6.
+func DaysIntoWeek(wd, start time.Weekday) int {
+	return (int(wd) - int(start) + 7) % 7
+}
+
+// StartOfWeek returns midnight of the first day of the week containing t
+// (see WeekStart), in t's location.
+func StartOfWeek(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()-DaysIntoWeek(t.Weekday(), WeekStart()), 0, 0, 0, 0, t.Location())
+}
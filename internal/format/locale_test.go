@@ -0,0 +1,102 @@
+package format
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setLocale(t *testing.T, lang string) {
+	t.Helper()
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_TIME", "")
+	t.Setenv("LANG", lang)
+}
+
+func TestLocale(t *testing.T) {
+	setLocale(t, "de_AT.UTF-8")
+	lang, region := locale()
+	require.Equal(t, "de", lang)
+	require.Equal(t, "AT", region)
+
+	t.Setenv("LC_TIME", "en_US")
+	lang, region = locale()
+	require.Equal(t, "en", lang)
+	require.Equal(t, "US", region, "LC_TIME wins over LANG")
+
+	setLocale(t, "C.UTF-8")
+	lang, region = locale()
+	require.Empty(t, lang)
+	require.Empty(t, region)
+}
+
+func TestLocaleDate(t *testing.T) {
+	tests := map[string]string{
+		"en_US.UTF-8": "01/23/2024",
+		"en_GB.UTF-8": "23/01/2024",
+		"de_DE.UTF-8": "23.01.2024",
+		"sv_SE.UTF-8": "2024-01-23",
+		"ja_JP.UTF-8": "2024/01/23",
+		"nl_NL.UTF-8": "23-01-2024",
+		"":            "2024-01-23",
+	}
+	for lang, want := range tests {
+		t.Run(lang, func(t *testing.T) {
+			setLocale(t, lang)
+			require.Equal(t, want, testTime.Format(localeDate()))
+		})
+	}
+}
+
+func TestTime_Locale(t *testing.T) {
+	cleanup := setupConfig(t, "time_format=locale")
+	defer cleanup()
+
+	setLocale(t, "en_US.UTF-8")
+	require.Equal(t, "3:04 PM", Time(testTime))
+
+	setLocale(t, "fr_FR.UTF-8")
+	require.Equal(t, "15:04", Time(testTime))
+}
+
+func TestWeekStart(t *testing.T) {
+	tests := []struct {
+		config string
+		lang   string
+		want   time.Weekday
+	}{
+		{"", "en_US.UTF-8", time.Monday},
+		{"week_start=sunday", "", time.Sunday},
+		{"week_start=Saturday", "", time.Saturday},
+		{"week_start=locale", "en_US.UTF-8", time.Sunday},
+		{"week_start=locale", "fa_IR.UTF-8", time.Saturday},
+		{"week_start=locale", "de_DE.UTF-8", time.Monday},
+	}
+	for _, tt := range tests {
+		t.Run(tt.config+" "+tt.lang, func(t *testing.T) {
+			cleanup := setupConfig(t, tt.config)
+			defer cleanup()
+			setLocale(t, tt.lang)
+
+			require.Equal(t, tt.want, WeekStart())
+		})
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	// testTime is Tuesday Jan 23 2024
+	cleanup := setupConfig(t, "")
+	defer cleanup()
+	require.Equal(t, time.Date(2024, 1, 22, 0, 0, 0, 0, time.UTC), StartOfWeek(testTime))
+
+	cleanup = setupConfig(t, "week_start=sunday")
+	defer cleanup()
+	require.Equal(t, time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC), StartOfWeek(testTime))
+}
+
+func TestDaysIntoWeek(t *testing.T) {
+	require.Equal(t, 0, DaysIntoWeek(time.Monday, time.Monday))
+	require.Equal(t, 6, DaysIntoWeek(time.Sunday, time.Monday))
+	require.Equal(t, 1, DaysIntoWeek(time.Sunday, time.Saturday))
+}
@@ -19,117 +19,166 @@ func DateTimeShort(t time.Time) string {
 	return DateShort(t) + " " + Time(t)
 }
 
-// Date formats only the date portion according to config.
+// Date formats only the date portion, in the display zone (see SetZone),
+// according to date_format.
 // Example output: "23/01/2024" or "01/23/2024" or "2024-01-23"
 func Date(t time.Time) string {
 	format := getDateFormat()
-	return t.Format(format)
+	return t.Local().Format(format)
 }
 
 // DateShort formats date without year.
 // Example output: "23/01" or "01/23"
 func DateShort(t time.Time) string {
 	format := getDateFormatShort()
-	return t.Format(format)
+	return t.Local().Format(format)
 }
 
-// Time formats only the time portion according to config.
+// Time formats only the time portion according to time_format.
 // Example output: "15:04" or "3:04 PM"
 func Time(t time.Time) string {
 	format := getTimeFormat()
-	return t.Format(format)
+	return t.Local().Format(format)
 }
 
 // TimeFull formats time with seconds.
 // Example output: "15:04:05" or "3:04:05 PM"
 func TimeFull(t time.Time) string {
 	format := getTimeFormatFull()
-	return t.Format(format)
+	return t.Local().Format(format)
 }
 
+// DateLong formats the date with its year even when date_format leaves it
+// out, as reports spanning years need.
+// Example output: "Jan 23, 2024" or "23/01/2024"
+func DateLong(t time.Time) string {
+	return t.Local().Format(getDateFormatLong())
+}
+
+// DateTimeLong formats a time with DateLong and Time.
+// Example output: "Jan 23, 2024 15:04" or "01/23/2024 3:04 PM"
+func DateTimeLong(t time.Time) string {
+	return DateLong(t) + " " + Time(t)
+}
+
+// DateWidth is the widest Date can be, for aligning date columns.
+func DateWidth() int {
+	return len(widestTime.Format(getDateFormat()))
+}
+
+// TimeWidth is the widest Time can be, for aligning time columns.
+func TimeWidth() int {
+	return len(widestTime.Format(getTimeFormat()))
+}
+
+// widestTime formats as wide as any time: a two-digit day and hour, in the
+// afternoon, on the weekday and in the month with the longest names.
+var widestTime = time.Date(2024, time.September, 25, 22, 59, 59, 0, time.UTC)
+
 // Full formats with full date and time with seconds.
 // Example output: "23/01/2024 15:04:05"
 func Full(t time.Time) string {
 	return Date(t) + " " + TimeFull(t)
 }
 
+// Date presets of the date_format key.
+const (
+	isoDate = "2006-01-02"
+	usDate  = "01/02/2006"
+	euDate  = "02/01/2006"
+	dotDate = "02.01.2006"
+)
+
+// defaultDate is the date layout used when date_format isn't set.
+const defaultDate = "Jan 02"
+
 // getDateFormat returns the Go time format string for dates.
 func getDateFormat() string {
-	displayDate, _ := config.Get("display_date")
-	if displayDate == "" {
-		displayDate = "Jan 02"
+	dateFormat, _ := config.Get("date_format")
+	if dateFormat == "" {
+		dateFormat = defaultDate
 	}
 
 	// Check for preset formats
-	switch displayDate {
-	case "mm/dd/yyyy":
-		return "01/02/2006"
-	case "yyyy-mm-dd":
-		return "2006-01-02"
-	case "dd/mm/yyyy":
-		return "02/01/2006"
+	switch strings.ToLower(dateFormat) {
+	case "mm/dd/yyyy", "us":
+		return usDate
+	case "yyyy-mm-dd", "iso":
+		return isoDate
+	case "dd/mm/yyyy", "eu":
+		return euDate
+	case "dd.mm.yyyy":
+		return dotDate
+	case "locale":
+		return localeDate()
 	default:
 		// Assume it's a custom Go time format (e.g., "Jan 02")
-		return displayDate
+		return dateFormat
 	}
 }
 
 // getDateFormatShort returns the Go time format string for short dates (no year).
 func getDateFormatShort() string {
-	displayDate, _ := config.Get("display_date")
-	if displayDate == "" {
-		displayDate = "Jan 02"
-	}
-
-	// Check for preset formats
-	switch displayDate {
-	case "mm/dd/yyyy":
+	switch layout := getDateFormat(); layout {
+	case usDate:
 		return "01/02"
-	case "yyyy-mm-dd":
+	case isoDate:
 		return "01-02"
-	case "dd/mm/yyyy":
+	case euDate:
 		return "02/01"
+	case dotDate:
+		return "02.01."
 	default:
-		// For custom formats, derive a short version by removing year patterns
+		// For other formats, derive a short version by removing year patterns
 		yearPatterns := strings.NewReplacer("2006", "", "/06", "", "-06", "", " 06", "")
-		short := strings.Trim(strings.TrimSpace(yearPatterns.Replace(displayDate)), "/-")
+		short := strings.Trim(strings.TrimSpace(yearPatterns.Replace(layout)), "/-.")
 		if short == "" {
-			return "Jan 02"
+			return defaultDate
 		}
 		return short
 	}
 }
 
-// getTimeFormat returns the Go time format string for times.
-func getTimeFormat() string {
-	displayTime, _ := config.Get("display_time")
-	if displayTime == "" {
-		displayTime = "24h"
+// getDateFormatLong returns the Go time format string for dates that
+// always show the year, for reports that span more than one.
+func getDateFormatLong() string {
+	layout := getDateFormat()
+	switch {
+	case layout == defaultDate:
+		return "Jan 02, 2006"
+	case strings.Contains(layout, "06"):
+		return layout
+	default:
+		return layout + " 2006"
 	}
+}
 
-	switch displayTime {
-	case "12h":
+// getTimeFormat returns the Go time format string for times.
+func getTimeFormat() string {
+	if uses12h() {
 		return "3:04 PM"
-	case "24h":
-		fallthrough
-	default:
-		return "15:04"
 	}
+	return "15:04"
 }
 
 // getTimeFormatFull returns the Go time format string for times with seconds.
 func getTimeFormatFull() string {
-	displayTime, _ := config.Get("display_time")
-	if displayTime == "" {
-		displayTime = "24h"
+	if uses12h() {
+		return "3:04:05 PM"
 	}
+	return "15:04:05"
+}
 
-	switch displayTime {
+// uses12h reports whether time_format asks for a 12-hour clock. Unknown
+// values fall back to 24h.
+func uses12h() bool {
+	timeFormat, _ := config.Get("time_format")
+	switch strings.ToLower(timeFormat) {
 	case "12h":
-		return "3:04:05 PM"
-	case "24h":
-		fallthrough
+		return true
+	case "locale":
+		return locale12h()
 	default:
-		return "15:04:05"
+		return false
 	}
 }
@@ -57,7 +57,7 @@ func TestDateTime(t *testing.T) {
 }
 
 func TestDateTime_WithCustomDateFormat(t *testing.T) {
-	cleanup := setupConfig(t, "display_date=mm/dd/yyyy")
+	cleanup := setupConfig(t, "date_format=mm/dd/yyyy")
 	defer cleanup()
 
 	result := DateTime(testTime)
@@ -75,7 +75,7 @@ func TestDateTimeShort(t *testing.T) {
 }
 
 func TestDateTimeShort_WithCustomFormat(t *testing.T) {
-	cleanup := setupConfig(t, "display_date=dd/mm/yyyy")
+	cleanup := setupConfig(t, "date_format=dd/mm/yyyy")
 	defer cleanup()
 
 	result := DateTimeShort(testTime)
@@ -96,22 +96,22 @@ func TestDate(t *testing.T) {
 		},
 		{
 			name:           "mm/dd/yyyy",
-			config:         "display_date=mm/dd/yyyy",
+			config:         "date_format=mm/dd/yyyy",
 			expectContains: "01/23/2024",
 		},
 		{
 			name:           "yyyy-mm-dd",
-			config:         "display_date=yyyy-mm-dd",
+			config:         "date_format=yyyy-mm-dd",
 			expectContains: "2024-01-23",
 		},
 		{
 			name:           "dd/mm/yyyy",
-			config:         "display_date=dd/mm/yyyy",
+			config:         "date_format=dd/mm/yyyy",
 			expectContains: "23/01/2024",
 		},
 		{
 			name:           "custom Go format",
-			config:         "display_date=2006/01/02",
+			config:         "date_format=2006/01/02",
 			expectContains: "2024/01/23",
 		},
 	}
@@ -140,17 +140,17 @@ func TestDateShort(t *testing.T) {
 		},
 		{
 			name:           "mm/dd/yyyy - short",
-			config:         "display_date=mm/dd/yyyy",
+			config:         "date_format=mm/dd/yyyy",
 			expectContains: "01/23",
 		},
 		{
 			name:           "yyyy-mm-dd - short",
-			config:         "display_date=yyyy-mm-dd",
+			config:         "date_format=yyyy-mm-dd",
 			expectContains: "01-23",
 		},
 		{
 			name:           "dd/mm/yyyy - short",
-			config:         "display_date=dd/mm/yyyy",
+			config:         "date_format=dd/mm/yyyy",
 			expectContains: "23/01",
 		},
 	}
@@ -179,7 +179,7 @@ func TestTime_24h(t *testing.T) {
 		},
 		{
 			name:   "explicit 24h format",
-			config: "display_time=24h",
+			config: "time_format=24h",
 			want:   "15:04",
 		},
 	}
@@ -196,7 +196,7 @@ func TestTime_24h(t *testing.T) {
 }
 
 func TestTime_12h(t *testing.T) {
-	cleanup := setupConfig(t, "display_time=12h")
+	cleanup := setupConfig(t, "time_format=12h")
 	defer cleanup()
 
 	result := Time(testTime)
@@ -205,7 +205,7 @@ func TestTime_12h(t *testing.T) {
 }
 
 func TestTimeFull_24h(t *testing.T) {
-	cleanup := setupConfig(t, "display_time=24h")
+	cleanup := setupConfig(t, "time_format=24h")
 	defer cleanup()
 
 	result := TimeFull(testTime)
@@ -213,7 +213,7 @@ func TestTimeFull_24h(t *testing.T) {
 }
 
 func TestTimeFull_12h(t *testing.T) {
-	cleanup := setupConfig(t, "display_time=12h")
+	cleanup := setupConfig(t, "time_format=12h")
 	defer cleanup()
 
 	result := TimeFull(testTime)
@@ -222,7 +222,7 @@ func TestTimeFull_12h(t *testing.T) {
 }
 
 func TestFull(t *testing.T) {
-	cleanup := setupConfig(t, "display_date=mm/dd/yyyy\ndisplay_time=24h")
+	cleanup := setupConfig(t, "date_format=mm/dd/yyyy\ntime_format=24h")
 	defer cleanup()
 
 	result := Full(testTime)
@@ -238,22 +238,22 @@ func TestGetDateFormatShort_CustomFormat(t *testing.T) {
 	}{
 		{
 			name:   "format with /06 year",
-			config: "display_date=01/02/06",
+			config: "date_format=01/02/06",
 			want:   "01/02", // /06 should be removed
 		},
 		{
 			name:   "format with -06 year",
-			config: "display_date=01-02-06",
+			config: "date_format=01-02-06",
 			want:   "01-02", // -06 should be removed
 		},
 		{
 			name:   "format with 2006",
-			config: "display_date=01/02/2006",
+			config: "date_format=01/02/2006",
 			want:   "01/02", // 2006 should be removed
 		},
 		{
 			name:   "format with space 06",
-			config: "display_date=01/02 06",
+			config: "date_format=01/02 06",
 			want:   "01/02", // space 06 should be removed
 		},
 	}
@@ -272,7 +272,7 @@ func TestGetDateFormatShort_CustomFormat(t *testing.T) {
 func TestGetDateFormatShort_EmptyAfterStripping(t *testing.T) {
 	// This is hard to trigger because we'd need a format that only contains year
 	// The fallback is "Jan 02"
-	cleanup := setupConfig(t, "display_date=2006")
+	cleanup := setupConfig(t, "date_format=2006")
 	defer cleanup()
 
 	result := DateShort(testTime)
@@ -281,7 +281,7 @@ func TestGetDateFormatShort_EmptyAfterStripping(t *testing.T) {
 }
 
 func TestDateTime_WithMorningTime(t *testing.T) {
-	cleanup := setupConfig(t, "display_time=12h")
+	cleanup := setupConfig(t, "time_format=12h")
 	defer cleanup()
 
 	// Test with morning time (before noon)
@@ -309,7 +309,7 @@ func TestTime_DefaultIs24h(t *testing.T) {
 
 func TestTime_UnknownFormat_FallsTo24h(t *testing.T) {
 	// Unknown format should fall through to 24h
-	cleanup := setupConfig(t, "display_time=unknown")
+	cleanup := setupConfig(t, "time_format=unknown")
 	defer cleanup()
 
 	result := Time(testTime)
@@ -318,7 +318,7 @@ func TestTime_UnknownFormat_FallsTo24h(t *testing.T) {
 
 func TestTimeFull_UnknownFormat_FallsTo24h(t *testing.T) {
 	// Unknown format should fall through to 24h
-	cleanup := setupConfig(t, "display_time=unknown")
+	cleanup := setupConfig(t, "time_format=unknown")
 	defer cleanup()
 
 	result := TimeFull(testTime)
@@ -327,7 +327,7 @@ func TestTimeFull_UnknownFormat_FallsTo24h(t *testing.T) {
 
 func TestGetDateFormatShort_FallbackToJan02(t *testing.T) {
 	// Test with format that becomes empty after stripping year patterns
-	cleanup := setupConfig(t, "display_date=/2006/")
+	cleanup := setupConfig(t, "date_format=/2006/")
 	defer cleanup()
 
 	result := DateShort(testTime)
@@ -336,9 +336,61 @@ func TestGetDateFormatShort_FallbackToJan02(t *testing.T) {
 }
 
 func TestDate_DDMMYYYYFormat(t *testing.T) {
-	cleanup := setupConfig(t, "display_date=dd/mm/yyyy")
+	cleanup := setupConfig(t, "date_format=dd/mm/yyyy")
 	defer cleanup()
 
 	result := Date(testTime)
 	require.Equal(t, "23/01/2024", result)
 }
+
+func TestDate_Presets(t *testing.T) {
+	tests := map[string]string{
+		"iso":        "2024-01-23",
+		"us":         "01/23/2024",
+		"eu":         "23/01/2024",
+		"dd.mm.yyyy": "23.01.2024",
+		"ISO":        "2024-01-23",
+	}
+	for preset, want := range tests {
+		t.Run(preset, func(t *testing.T) {
+			cleanup := setupConfig(t, "date_format="+preset)
+			defer cleanup()
+
+			require.Equal(t, want, Date(testTime))
+		})
+	}
+}
+
+func TestDateShort_DottedDate(t *testing.T) {
+	cleanup := setupConfig(t, "date_format=dd.mm.yyyy")
+	defer cleanup()
+
+	require.Equal(t, "23.01.", DateShort(testTime))
+}
+
+func TestDateLong(t *testing.T) {
+	tests := []struct {
+		config string
+		want   string
+	}{
+		{"", "Jan 23, 2024"},
+		{"date_format=iso", "2024-01-23"},
+		{"date_format=02 Jan", "23 Jan 2024"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.config, func(t *testing.T) {
+			cleanup := setupConfig(t, tt.config)
+			defer cleanup()
+
+			require.Equal(t, tt.want, DateLong(testTime))
+		})
+	}
+}
+
+func TestColumnWidths(t *testing.T) {
+	cleanup := setupConfig(t, "date_format=us\ntime_format=12h")
+	defer cleanup()
+
+	require.Equal(t, 10, DateWidth())
+	require.Equal(t, 8, TimeWidth()) // "10:59 PM"
+}
@@ -0,0 +1,37 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// Embedded zone database, for systems without one (Windows, minimal containers)
+	_ "time/tzdata"
+)
+
+// machineZone is the system's own zone, kept so "local" still means it
+// after SetZone has replaced time.Local.
+var machineZone = time.Local
+
+// LoadZone resolves a zone name from the timezone key or --tz: "local" (or
+// empty) for this machine's zone, "UTC", or an IANA name such as
+// "Europe/Berlin".
+func LoadZone(name string) (*time.Location, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "local":
+		return machineZone, nil
+	case "utc", "z":
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(strings.TrimSpace(name))
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone '%s' (use an IANA name like Europe/Berlin, UTC or local)", name)
+	}
+	return loc, nil
+}
+
+// SetZone makes loc the zone times are displayed in. It replaces time.Local,
+// so day boundaries ("today", heatmap days, --since dates) follow it too.
+func SetZone(loc *time.Location) {
+	time.Local = loc
+}
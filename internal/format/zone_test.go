@@ -0,0 +1,45 @@
+package format
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The expectations in this package are written for UTC.
+func TestMain(m *testing.M) {
+	SetZone(time.UTC)
+	os.Exit(m.Run())
+}
+
+func TestLoadZone(t *testing.T) {
+	loc, err := LoadZone("UTC")
+	require.NoError(t, err)
+	require.Equal(t, time.UTC, loc)
+
+	loc, err = LoadZone("local")
+	require.NoError(t, err)
+	require.Equal(t, machineZone, loc)
+
+	loc, err = LoadZone("America/New_York")
+	require.NoError(t, err)
+	require.Equal(t, "America/New_York", loc.String())
+
+	_, err = LoadZone("Mars/Olympus_Mons")
+	require.ErrorContains(t, err, "unknown time zone 'Mars/Olympus_Mons'")
+}
+
+func TestSetZone_FormatsInZone(t *testing.T) {
+	t.Cleanup(func() { SetZone(time.UTC) })
+
+	tokyo, err := LoadZone("Asia/Tokyo")
+	require.NoError(t, err)
+	SetZone(tokyo)
+
+	// 23:30 UTC is already the next morning in Tokyo
+	ts := time.Date(2024, 1, 23, 23, 30, 0, 0, time.UTC)
+	require.Equal(t, "08:30", Time(ts))
+	require.Equal(t, "Jan 24", Date(ts))
+}
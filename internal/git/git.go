@@ -3,6 +3,8 @@ package git
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"os/exec"
 	"regexp"
 	"strconv"
@@ -68,6 +70,20 @@ func RepoRoot(path string) (string, error) {
 	return runGit("-C", path, "rev-parse", "--show-toplevel")
 }
 
+// BareRepoDir returns the absolute path of the bare repository at path, as
+// on a git server, where RepoRoot fails for want of a working tree. It
+// returns an error when path isn't in a bare repository.
+func BareRepoDir(path string) (string, error) {
+	bare, err := runGit("-C", path, "rev-parse", "--is-bare-repository")
+	if err != nil {
+		return "", err
+	}
+	if bare != "true" {
+		return "", fmt.Errorf("%s is not a bare repository", path)
+	}
+	return runGit("-C", path, "rev-parse", "--absolute-git-dir")
+}
+
 func OriginURL(repoRoot string) (string, error) {
 	return runGit("-C", repoRoot, "remote", "get-url", "origin")
 }
@@ -100,6 +116,19 @@ func CommitMessage() (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// CommitText returns the subject and body of a commit in a repository.
+func CommitText(repoPath, commit string) (string, string, error) {
+	if !isValidCommitRef(commit) {
+		return "", "", fmt.Errorf("invalid commit reference: %s", commit)
+	}
+	out, err := runGitInRepo(repoPath, "show", "-s", "--format=%s%x00%b", commit)
+	if err != nil {
+		return "", "", err
+	}
+	subject, body, _ := strings.Cut(out, "\x00")
+	return subject, strings.TrimSpace(body), nil
+}
+
 func CurrentBranch() (string, error) {
 	return runGit("rev-parse", "--abbrev-ref", "HEAD")
 }
@@ -109,6 +138,123 @@ func GetCurrentBranch(repoPath string) (string, error) {
 	return runGit("-C", repoPath, "branch", "--show-current")
 }
 
+// ListBranches returns the local branch names of the current repository.
+func ListBranches() ([]string, error) {
+	out, err := runGit("for-each-ref", "--format=%(refname:short)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// BranchTips returns the commit each local branch of the repository at
+// repoPath points to, by branch name.
+func BranchTips(repoPath string) (map[string]string, error) {
+	out, err := runGitInRepo(repoPath, "for-each-ref", "--format=%(objectname) %(refname:short)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	tips := make(map[string]string)
+	for _, line := range splitLines(out) {
+		if commit, branch, ok := strings.Cut(line, " "); ok {
+			tips[branch] = commit
+		}
+	}
+	return tips, nil
+}
+
+// DefaultBranch returns the default branch of the repository at
+// repoPath: the branch origin/HEAD points to or, when it isn't set (no
+// remote, or a clone made without it), main or master if one exists.
+func DefaultBranch(repoPath string) (string, error) {
+	if ref, err := runGitInRepo(repoPath, "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil && ref != "" {
+		return strings.TrimPrefix(ref, "origin/"), nil
+	}
+	for _, name := range []string{"main", "master"} {
+		if _, err := runGitInRepo(repoPath, "show-ref", "--verify", "--quiet", "refs/heads/"+name); err == nil {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("no default branch in %s: origin/HEAD is not set and there is no main or master", repoPath)
+}
+
+// patchIDBatch is how many commits PatchIDs hands git at once.
+const patchIDBatch = 200
+
+// PatchIDs returns the stable patch-id of each of commits in the
+// repository at repoPath, by commit. Commits with the same change, such as
+// a commit and its copy after a rebase, share a patch-id. Merges and empty
+// commits have none and are left out, as are commits the repository no
+// longer has.
+func PatchIDs(repoPath string, commits []string) (map[string]string, error) {
+	ids := make(map[string]string)
+	var valid []string
+	for _, c := range commits {
+		if isValidCommitRef(c) && !strings.HasPrefix(c, "-") {
+			valid = append(valid, c)
+		}
+	}
+	for start := 0; start < len(valid); start += patchIDBatch {
+		batch := valid[start:min(start+patchIDBatch, len(valid))]
+		err := addPatchIDs(ids, repoPath, batch)
+		if err == nil {
+			continue
+		}
+		// One missing commit fails the whole git log
+		if _, repoErr := runGitInRepo(repoPath, "rev-parse", "--git-dir"); repoErr != nil {
+			return nil, repoErr
+		}
+		if len(batch) == 1 {
+			continue
+		}
+		log.Debug("git: patch-id batch failed, reading %d commits one by one: %v", len(batch), err)
+		for _, c := range batch {
+			_ = addPatchIDs(ids, repoPath, []string{c})
+		}
+	}
+	return ids, nil
+}
+
+// addPatchIDs adds the patch-ids of commits to ids.
+func addPatchIDs(ids map[string]string, repoPath string, commits []string) error {
+	args := append([]string{"-C", repoPath, "log", "--no-walk=unsorted", "-p", "--no-color", "--format=commit %H"}, commits...)
+	patches, err := runGitRaw(nil, args...)
+	if err != nil {
+		return err
+	}
+	out, err := runGitRaw(bytes.NewReader(patches), "-C", repoPath, "patch-id", "--stable")
+	if err != nil {
+		return err
+	}
+	for _, line := range splitLines(string(out)) {
+		if id, commit, ok := strings.Cut(line, " "); ok {
+			ids[commit] = id
+		}
+	}
+	return nil
+}
+
+// FirstParentCommits returns the commits on the first-parent history of
+// branch in the repository at repoPath: the commits made on it and its
+// merges, without the commits the merges brought in.
+func FirstParentCommits(repoPath, branch string) (map[string]bool, error) {
+	if branch == "" || !isValidCommitRef(branch) {
+		return nil, fmt.Errorf("invalid branch name: %s", branch)
+	}
+	out, err := runGitInRepo(repoPath, "rev-list", "--first-parent", "refs/heads/"+branch)
+	if err != nil {
+		return nil, err
+	}
+	commits := make(map[string]bool)
+	for _, c := range splitLines(out) {
+		commits[c] = true
+	}
+	return commits, nil
+}
+
 func CommitAuthor() (string, error) {
 	return runGit("show", "-s", "--format=%an <%ae>", "HEAD")
 }
@@ -132,6 +278,25 @@ func runGit(args ...string) (string, error) {
 	return strings.TrimSpace(out.String()), nil
 }
 
+// runGitRaw is runGit for output that must stay byte for byte, such as
+// patches: it isn't trimmed, stderr is kept out of it, and input, if not
+// nil, is git's standard input.
+func runGitRaw(input io.Reader, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdin = input
+	var out, stderr bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		log.Debug("git: command failed: git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
 // splitLines splits output into non-empty trimmed lines.
 // Returns nil if output is empty.
 func splitLines(output string) []string {
@@ -211,6 +376,116 @@ func GetCommitMetadata(repoPath, commit string) CommitMetadata {
 	return meta
 }
 
+// metadataBatch is how many commits GetCommitMetadataBatch passes to one
+// git log, keeping the command line well under OS limits.
+const metadataBatch = 500
+
+// metadataFormat is one commit in GetCommitMetadataBatch's git log output:
+// a record separator, then hash, parents, author, committer, subject and
+// body separated by NULs, then the --numstat lines.
+const metadataFormat = "%x1e%H%x00%P%x00%an%x00%ae%x00%aI%x00%cn%x00%ce%x00%s%x00%b%x00"
+
+// GetCommitMetadataBatch reads the metadata of many commits of the
+// repository at repoPath with one git log per metadataBatch commits,
+// where GetCommitMetadata runs three git commands per commit. The result
+// is keyed by the hashes as given and matches GetCommitMetadata's, down to
+// root commits and merges counting no changed lines. Commits git can't
+// read are left out; when a batch fails because one of them doesn't
+// exist, its commits are read one by one instead. The error is only set
+// when repoPath isn't a repository git can read.
+func GetCommitMetadataBatch(repoPath string, commits []string) (map[string]CommitMetadata, error) {
+	metas := make(map[string]CommitMetadata, len(commits))
+	var valid []string
+	seen := make(map[string]bool, len(commits))
+	for _, c := range commits {
+		if isValidCommitRef(c) && !strings.HasPrefix(c, "-") && !seen[c] {
+			seen[c] = true
+			valid = append(valid, c)
+		}
+	}
+
+	for start := 0; start < len(valid); start += metadataBatch {
+		batch := valid[start:min(start+metadataBatch, len(valid))]
+		args := append([]string{
+			"-C", repoPath, "-c", "log.showRoot=false",
+			"log", "--no-walk=unsorted", "--no-renames", "--no-color", "--no-show-signature",
+			"--numstat", "--format=" + metadataFormat,
+		}, batch...)
+		out, err := runGitRaw(nil, args...)
+		if err != nil {
+			if _, repoErr := runGitInRepo(repoPath, "rev-parse", "--git-dir"); repoErr != nil {
+				return metas, repoErr
+			}
+			if len(batch) == 1 {
+				continue
+			}
+			log.Debug("git: metadata batch failed, reading %d commits one by one: %v", len(batch), err)
+			for _, c := range batch {
+				if meta := GetCommitMetadata(repoPath, c); meta.AuthoredAt != "" {
+					metas[c] = meta
+				}
+			}
+			continue
+		}
+
+		byHash := parseMetadataBatch(string(out))
+		for _, c := range batch {
+			if meta, ok := byHash[c]; ok {
+				metas[c] = meta
+				continue
+			}
+			// Abbreviated hashes come back in full
+			for hash, meta := range byHash {
+				if strings.HasPrefix(hash, strings.ToLower(c)) {
+					metas[c] = meta
+					break
+				}
+			}
+		}
+	}
+	return metas, nil
+}
+
+// parseMetadataBatch parses git log output in metadataFormat, by full hash.
+func parseMetadataBatch(output string) map[string]CommitMetadata {
+	metas := make(map[string]CommitMetadata)
+	for _, record := range strings.Split(output, "\x1e") {
+		parts := strings.SplitN(record, "\x00", 10)
+		if len(parts) < 10 {
+			continue
+		}
+		stats := parseDiffStats(parts[9])
+		metas[parts[0]] = CommitMetadata{
+			ParentCommits:  strings.Join(strings.Fields(parts[1]), " "),
+			AuthorName:     parts[2],
+			AuthorEmail:    parts[3],
+			AuthoredAt:     parts[4],
+			CommitterName:  parts[5],
+			CommitterEmail: parts[6],
+			Subject:        parts[7],
+			Body:           strings.TrimSpace(parts[8]),
+			FilesChanged:   stats.FilesChanged,
+			Insertions:     stats.Insertions,
+			Deletions:      stats.Deletions,
+		}
+	}
+	return metas
+}
+
+// CommitAuthorEmail returns the author email of a commit, or "" if it can't be
+// read. It is a single git call, cheaper than GetCommitMetadata when only the
+// author is needed.
+func CommitAuthorEmail(repoPath, commit string) string {
+	if !isValidCommitRef(commit) {
+		return ""
+	}
+	email, err := runGitInRepo(repoPath, "show", "-s", "--format=%ae", commit)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(email)
+}
+
 // runGitInRepo runs a git command in the specified repository directory.
 func runGitInRepo(repoPath string, args ...string) (string, error) {
 	fullArgs := append([]string{"-C", repoPath}, args...)
@@ -248,6 +523,59 @@ func parseDiffStats(output string) DiffStats {
 	return stats
 }
 
+// FileStat is the lines a commit added and removed in one file.
+type FileStat struct {
+	Path       string
+	Insertions int
+	Deletions  int
+}
+
+// CommitFileStats returns the lines changed per file in a commit. Renamed
+// files are reported under their new path; binary files count no lines.
+func CommitFileStats(repoPath, commit string) ([]FileStat, error) {
+	if !isValidCommitRef(commit) {
+		return nil, fmt.Errorf("invalid commit reference %q", commit)
+	}
+	out, err := runGitInRepo(repoPath, "diff-tree", "--no-commit-id", "--numstat", "-r", "--root", commit)
+	if err != nil {
+		return nil, err
+	}
+	return parseFileStats(out), nil
+}
+
+// parseFileStats parses git diff-tree --numstat output per file.
+func parseFileStats(output string) []FileStat {
+	var stats []FileStat
+	for _, line := range splitLines(output) {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		stats = append(stats, FileStat{
+			Path:       renamedPath(parts[2]),
+			Insertions: parseNumstat(parts[0]),
+			Deletions:  parseNumstat(parts[1]),
+		})
+	}
+	return stats
+}
+
+// renamedPath returns the new path of a numstat rename, written either
+// "old => new" or "dir/{old => new}/file".
+func renamedPath(path string) string {
+	arrow := strings.Index(path, " => ")
+	if arrow < 0 {
+		return path
+	}
+	open := strings.LastIndex(path[:arrow], "{")
+	end := strings.Index(path[arrow:], "}")
+	if open < 0 || end < 0 {
+		return path[arrow+len(" => "):]
+	}
+	end += arrow
+	return path[:open] + path[arrow+len(" => "):end] + path[end+1:]
+}
+
 // HistoryCommit represents a commit from git log.
 type HistoryCommit struct {
 	Hash        string
@@ -320,6 +648,45 @@ func ListCommits(repoPath string, opts ListCommitsOptions) ([]HistoryCommit, err
 	return commits, nil
 }
 
+// IsAncestor reports whether commit is reachable from ref in the
+// repository at repoPath. A ref or commit that doesn't exist is not.
+func IsAncestor(repoPath, commit, ref string) bool {
+	if !isValidCommitRef(commit) || !isValidCommitRef(ref) {
+		return false
+	}
+	_, err := runGitInRepo(repoPath, "merge-base", "--is-ancestor", commit, ref)
+	return err == nil
+}
+
+// CountPushCommits counts the commits a push of localSHA brings to a
+// remote whose ref is at remoteSHA (all zeros for a new ref): those not
+// reachable from remoteSHA, or, when this repository doesn't have that
+// commit, from any of remote's remote-tracking refs. ok is false when
+// neither tells what the remote has, as for a new ref pushed to a URL
+// (remote "").
+func CountPushCommits(repoPath, localSHA, remoteSHA, remote string) (count int, ok bool) {
+	if !isValidCommitRef(localSHA) {
+		return 0, false
+	}
+	var exclude [][]string
+	if isValidCommitRef(remoteSHA) && strings.Trim(remoteSHA, "0") != "" {
+		exclude = append(exclude, []string{"^" + remoteSHA})
+	}
+	if remote != "" && isValidCommitRef(remote) {
+		exclude = append(exclude, []string{"--not", "--remotes=" + remote})
+	}
+	for _, args := range exclude {
+		out, err := runGitInRepo(repoPath, append([]string{"rev-list", "--count", localSHA}, args...)...)
+		if err != nil {
+			continue
+		}
+		if n, err := strconv.Atoi(out); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
 // GetBranchForCommit tries to infer which branch a commit belongs to.
 // Returns the branch name or empty string if unable to determine.
 func GetBranchForCommit(repoPath, commit string) string {
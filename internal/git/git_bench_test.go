@@ -0,0 +1,64 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// benchRepo creates a repository with n commits, each changing one file,
+// through git fast-import, and returns its path and the commit hashes.
+func benchRepo(b *testing.B, n int) (string, []string) {
+	b.Helper()
+	dir := b.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		b.Fatalf("git init: %v: %s", err, out)
+	}
+
+	var stream strings.Builder
+	for i := range n {
+		content := fmt.Sprintf("line %d\n", i)
+		msg := fmt.Sprintf("Commit %d", i)
+		fmt.Fprintf(&stream, "commit refs/heads/main\nmark :%d\n", i+1)
+		fmt.Fprintf(&stream, "author Bench <bench@example.com> %d +0000\n", 1700000000+i*60)
+		fmt.Fprintf(&stream, "committer Bench <bench@example.com> %d +0000\n", 1700000000+i*60)
+		fmt.Fprintf(&stream, "data %d\n%s\n", len(msg), msg)
+		if i > 0 {
+			fmt.Fprintf(&stream, "from :%d\n", i)
+		}
+		fmt.Fprintf(&stream, "M 644 inline file%d.txt\ndata %d\n%s\n", i%50, len(content), content)
+	}
+	cmd := exec.Command("git", "fast-import", "--quiet")
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(stream.String())
+	if out, err := cmd.CombinedOutput(); err != nil {
+		b.Fatalf("git fast-import: %v: %s", err, out)
+	}
+
+	out, err := runGitInRepo(dir, "rev-list", "main")
+	if err != nil {
+		b.Fatalf("git rev-list: %v", err)
+	}
+	return dir, splitLines(out)
+}
+
+// On 1,000 commits, one GetCommitMetadataBatch call replaces 3,000 git
+// processes with two.
+func BenchmarkGetCommitMetadata_1k(b *testing.B) {
+	repo, commits := benchRepo(b, 1000)
+	for b.Loop() {
+		for _, c := range commits {
+			GetCommitMetadata(repo, c)
+		}
+	}
+}
+
+func BenchmarkGetCommitMetadataBatch_1k(b *testing.B) {
+	repo, commits := benchRepo(b, 1000)
+	for b.Loop() {
+		if _, err := GetCommitMetadataBatch(repo, commits); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -108,6 +108,21 @@ func TestRepoRoot_NotARepo(t *testing.T) {
 	require.Error(t, err, "should error when not in a git repo")
 }
 
+func TestBareRepoDir(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	bare := filepath.Join(dir, "app.git")
+	out, err := exec.Command("git", "init", "--bare", bare).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	got, err := BareRepoDir(bare)
+	require.NoError(t, err)
+	require.Equal(t, bare, got)
+
+	_, err = BareRepoDir(newTestRepo(t))
+	require.ErrorContains(t, err, "not a bare repository")
+}
+
 func TestOriginURL(t *testing.T) {
 	repo := newTestRepo(t)
 	setRemote(t, repo, "origin", "https://github.com/user/repo.git")
@@ -443,6 +458,23 @@ func TestListCommits(t *testing.T) {
 	})
 }
 
+func TestBranchTips(t *testing.T) {
+	repo := newTestRepo(t)
+	first := commitFile(t, repo, "a.txt", "a")
+
+	cmd := exec.Command("git", "branch", "feature")
+	cmd.Dir = repo
+	require.NoError(t, cmd.Run())
+	second := commitFile(t, repo, "b.txt", "b")
+
+	current, err := GetCurrentBranch(repo)
+	require.NoError(t, err)
+
+	tips, err := BranchTips(repo)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{current: second, "feature": first}, tips)
+}
+
 func TestGetBranchForCommit(t *testing.T) {
 	repo := newTestRepo(t)
 
@@ -724,3 +756,188 @@ func TestProvider_CommitAuthor(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "Test User <test@example.com>", author)
 }
+
+func TestCommitText(t *testing.T) {
+	repo := newTestRepo(t)
+	commitFile(t, repo, "a.txt", "a")
+
+	cmd := exec.Command("git", "commit", "-q", "--allow-empty", "-m", "Fix login redirect", "-m", "The session cookie was dropped.\n\nCloses #12")
+	cmd.Dir = repo
+	require.NoError(t, cmd.Run())
+
+	head, err := runGitInRepo(repo, "rev-parse", "HEAD")
+	require.NoError(t, err)
+
+	subject, body, err := CommitText(repo, head)
+	require.NoError(t, err)
+	require.Equal(t, "Fix login redirect", subject)
+	require.Equal(t, "The session cookie was dropped.\n\nCloses #12", body)
+
+	_, _, err = CommitText(repo, "not a ref")
+	require.Error(t, err)
+}
+
+func TestParseFileStats(t *testing.T) {
+	got := parseFileStats("10\t2\tmain.go\n-\t-\tlogo.png\n3\t1\tdocs/{old.md => guide.md}\n1\t0\ta.txt => b.yml")
+	require.Equal(t, []FileStat{
+		{Path: "main.go", Insertions: 10, Deletions: 2},
+		{Path: "logo.png"},
+		{Path: "docs/guide.md", Insertions: 3, Deletions: 1},
+		{Path: "b.yml", Insertions: 1},
+	}, got)
+}
+
+func TestCommitFileStats(t *testing.T) {
+	repo := newTestRepo(t)
+	commitFile(t, repo, "main.go", "package main\n\nfunc main() {}\n")
+
+	head, err := runGitInRepo(repo, "rev-parse", "HEAD")
+	require.NoError(t, err)
+
+	stats, err := CommitFileStats(repo, head)
+	require.NoError(t, err)
+	require.Equal(t, []FileStat{{Path: "main.go", Insertions: 3}}, stats)
+
+	_, err = CommitFileStats(repo, "not a ref")
+	require.Error(t, err)
+}
+
+// runIn runs a git command in the test repo.
+func runIn(t *testing.T, repoPath string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+func TestPatchIDs_SameChangeAfterCherryPick(t *testing.T) {
+	repo := newTestRepo(t)
+	commitFile(t, repo, "a.txt", "a")
+	main := runIn(t, repo, "branch", "--show-current")
+
+	runIn(t, repo, "checkout", "-q", "-b", "feature")
+	original := commitFile(t, repo, "b.txt", "b\n \n")
+	runIn(t, repo, "checkout", "-q", main)
+	other := commitFile(t, repo, "c.txt", "c")
+	runIn(t, repo, "cherry-pick", original)
+	picked := runIn(t, repo, "rev-parse", "HEAD")
+
+	// A commit the repository doesn't have doesn't lose the others
+	missing := strings.Repeat("0", 40)
+	ids, err := PatchIDs(repo, []string{original, picked, missing, other, "not a commit"})
+	require.NoError(t, err)
+	require.NotContains(t, ids, missing)
+	require.NotEmpty(t, ids[original])
+	require.Equal(t, ids[original], ids[picked])
+	require.NotEqual(t, ids[original], ids[other])
+}
+
+func TestFirstParentCommits(t *testing.T) {
+	repo := newTestRepo(t)
+	first := commitFile(t, repo, "a.txt", "a")
+	main := runIn(t, repo, "branch", "--show-current")
+
+	runIn(t, repo, "checkout", "-q", "-b", "feature")
+	side := commitFile(t, repo, "b.txt", "b")
+	runIn(t, repo, "checkout", "-q", main)
+	commitFile(t, repo, "c.txt", "c")
+	runIn(t, repo, "merge", "-q", "--no-edit", "feature")
+	merge := runIn(t, repo, "rev-parse", "HEAD")
+
+	commits, err := FirstParentCommits(repo, main)
+	require.NoError(t, err)
+	require.True(t, commits[first])
+	require.True(t, commits[merge])
+	require.False(t, commits[side], "commits brought in by the merge are not on the first-parent history")
+
+	_, err = FirstParentCommits(repo, "gone")
+	require.Error(t, err)
+}
+
+func TestDefaultBranch(t *testing.T) {
+	repo := newTestRepo(t)
+	commitFile(t, repo, "a.txt", "a")
+	runIn(t, repo, "branch", "-M", "main")
+
+	branch, err := DefaultBranch(repo)
+	require.NoError(t, err)
+	require.Equal(t, "main", branch, "without origin/HEAD main is the default")
+
+	runIn(t, repo, "branch", "-M", "trunk")
+	_, err = DefaultBranch(repo)
+	require.Error(t, err)
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	runIn(t, repo, "clone", "-q", repo, clone)
+	branch, err = DefaultBranch(clone)
+	require.NoError(t, err)
+	require.Equal(t, "trunk", branch, "origin/HEAD wins")
+}
+
+func TestGetCommitMetadataBatch_MatchesGetCommitMetadata(t *testing.T) {
+	repo := newTestRepo(t)
+	root := commitFile(t, repo, "a.txt", "one\n")
+	second := commitFile(t, repo, "b.txt", "two\nlines\n")
+
+	cmd := exec.Command("git", "commit", "--allow-empty", "-m", "Subject", "-m", "Body line\n\nwith\ttabs; and: punctuation")
+	cmd.Dir = repo
+	require.NoError(t, cmd.Run())
+	third := commitFile(t, repo, "a.txt", "one\nchanged\n")
+
+	commits := []string{third, root, second, second[:12]}
+	metas, err := GetCommitMetadataBatch(repo, commits)
+	require.NoError(t, err)
+	require.Len(t, metas, 4)
+	for _, c := range commits {
+		require.Equal(t, GetCommitMetadata(repo, c), metas[c], c)
+	}
+	require.Equal(t, 2, metas[second].Insertions)
+	require.Equal(t, 0, metas[root].Insertions, "root commits count no lines, as with diff-tree")
+	require.Equal(t, root, metas[second].ParentCommits)
+}
+
+func TestGetCommitMetadataBatch_UnknownCommit(t *testing.T) {
+	repo := newTestRepo(t)
+	known := commitFile(t, repo, "a.txt", "one\n")
+	missing := strings.Repeat("ab", 20)
+
+	metas, err := GetCommitMetadataBatch(repo, []string{known, missing, "--exec=evil"})
+	require.NoError(t, err)
+	require.Len(t, metas, 1)
+	require.Equal(t, "Add a.txt", metas[known].Subject)
+
+	_, err = GetCommitMetadataBatch(filepath.Join(t.TempDir(), "nope"), []string{known})
+	require.Error(t, err)
+}
+
+func TestCountPushCommits(t *testing.T) {
+	repo := newTestRepo(t)
+	first := commitFile(t, repo, "a.txt", "one\n")
+	runIn(t, repo, "update-ref", "refs/remotes/origin/main", first)
+	commitFile(t, repo, "b.txt", "two\n")
+	third := commitFile(t, repo, "c.txt", "three\n")
+	zero := strings.Repeat("0", 40)
+
+	n, ok := CountPushCommits(repo, third, first, "origin")
+	require.True(t, ok)
+	require.Equal(t, 2, n)
+
+	// A new branch: what none of origin's refs have
+	n, ok = CountPushCommits(repo, third, zero, "origin")
+	require.True(t, ok)
+	require.Equal(t, 2, n)
+
+	// The remote's commit isn't here, as after someone else pushed
+	n, ok = CountPushCommits(repo, third, strings.Repeat("ab", 20), "origin")
+	require.True(t, ok)
+	require.Equal(t, 2, n)
+
+	// A new ref pushed to a URL can't be counted
+	_, ok = CountPushCommits(repo, third, zero, "")
+	require.False(t, ok)
+	_, ok = CountPushCommits(repo, "--exec=evil", first, "origin")
+	require.False(t, ok)
+}
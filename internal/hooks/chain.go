@@ -0,0 +1,134 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// chainMarker is the line that tells a chained hook apart from a plain
+// one, so refreshing a chained hook keeps it chained.
+const chainMarker = "# fp: chained hook"
+
+// stdinHooks are the hooks git feeds on stdin. Their chained script reads
+// stdin once and hands a copy to the original hook and to fp.
+var stdinHooks = map[string]bool{
+	"post-receive":          true,
+	"post-rewrite":          true,
+	"pre-push":              true,
+	"reference-transaction": true,
+}
+
+// ChainScript is the hook fp writes in chaining mode: it runs the hook that
+// was there before fp, kept in .fp-backup next to it, then records the
+// event the way Script does. The original's exit status is the hook's, so
+// a pre-push check that fails still stops the push, and fp doesn't record
+// a push that didn't happen.
+func ChainScript(fpPath string, source string) string {
+	return chainScript(fpPath, source, chainMarker+": runs the original hook, then fp\n",
+		"\"$(dirname \"$0\")/.fp-backup/"+source+"\"")
+}
+
+// chainScript writes a hook that runs the hook at original, a shell
+// expression, and then fp. header goes right after the shebang.
+func chainScript(fpPath, source, header, original string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString(header)
+	b.WriteString("original=" + original + "\n")
+
+	feed := ""
+	if stdinHooks[source] {
+		b.WriteString("input=$(cat)\n")
+		feed = "{ [ -z \"$input\" ] || printf '%s\\n' \"$input\"; } | "
+	}
+
+	b.WriteString("status=0\n")
+	b.WriteString("if [ -x \"$original\" ]; then\n")
+	b.WriteString("\t" + feed + "\"$original\" \"$@\"\n")
+	b.WriteString("\tstatus=$?\n")
+	b.WriteString("fi\n")
+	if source == "pre-push" {
+		b.WriteString("[ \"$status\" -eq 0 ] || exit \"$status\"\n")
+	}
+
+	// The plain script runs in a subshell, so its own exits don't skip
+	// returning the original's status.
+	body := strings.TrimPrefix(Script(fpPath, source), "#!/bin/sh\n")
+	b.WriteString(feed + "(\n" + body + ")\n")
+	b.WriteString("exit \"$status\"\n")
+	return b.String()
+}
+
+// isChainedHook reports whether the hook at hookPath is a chained fp hook.
+func isChainedHook(hookPath string) bool {
+	data, err := os.ReadFile(hookPath)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(data), chainMarker)
+}
+
+// InstallChained installs fp hooks that keep the hooks already in
+// hooksPath working. Each existing hook is moved to .fp-backup, as Install
+// does, and the fp hook written in its place runs it before recording.
+// Hooks fp installed before are rewritten without being backed up, so the
+// user's originals stay in .fp-backup. Uninstall puts the originals back.
+func InstallChained(hooksPath string) error {
+	return installChained(hooksPath, ManagedHooks)
+}
+
+// InstallServerChained is InstallChained for the ServerHooks of a bare
+// repository, so a server's own post-receive (a CI trigger, a mirror push)
+// keeps running.
+func InstallServerChained(hooksPath string) error {
+	return installChained(hooksPath, ServerHooks)
+}
+
+func installChained(hooksPath string, names []string) error {
+	log.Debug("hooks: installing chained hooks to %s", hooksPath)
+
+	if err := os.MkdirAll(hooksPath, filePermExecutable); err != nil {
+		log.Error("hooks: failed to create directory %s: %v", hooksPath, err)
+		return err
+	}
+
+	fpPath, err := os.Executable()
+	if err != nil {
+		log.Error("hooks: failed to get executable path: %v", err)
+		return err
+	}
+	return installChainedFor(hooksPath, fpPath, names)
+}
+
+func installChainedFor(hooksPath, fpPath string, names []string) error {
+	chained := 0
+	for _, hook := range names {
+		target := filepath.Join(hooksPath, hook)
+
+		if exists(target) && !isFpHook(target) {
+			log.Debug("hooks: chaining existing %s", hook)
+			if err := backupHook(hooksPath, hook); err != nil {
+				log.Error("hooks: failed to backup %s: %v", hook, err)
+				return err
+			}
+		}
+
+		script := Script(fpPath, hook)
+		if exists(filepath.Join(backupDir(hooksPath), hook)) {
+			script = ChainScript(fpPath, hook)
+			chained++
+		}
+
+		if err := os.WriteFile(target, []byte(script), filePermExecutable); err != nil {
+			log.Error("hooks: failed to write %s: %v", hook, err)
+			return err
+		}
+		log.Debug("hooks: installed %s", hook)
+	}
+
+	log.Info("hooks: installed %d hooks to %s (%d chained)", len(names), hooksPath, chained)
+	return nil
+}
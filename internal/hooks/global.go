@@ -7,17 +7,56 @@ import (
 	"strings"
 
 	"github.com/footprint-tools/cli/internal/log"
+	"github.com/footprint-tools/cli/internal/paths"
 )
 
-// GlobalHooksDir returns the default directory for global hooks.
-// On Unix: ~/.config/git/hooks
-// On macOS: ~/Library/Application Support/git/hooks (but we use ~/.config for consistency)
+// globalMarker is the line that tells a global fp hook apart from a plain
+// one, so refreshing it keeps the delegation to repository hooks.
+const globalMarker = "# fp: global hook"
+
+// previousHooksPathFile remembers, inside the global hooks directory, the
+// core.hooksPath that was set before fp's, so teardown can put it back.
+const previousHooksPathFile = ".fp-previous-hooks-path"
+
+// GlobalHooksDir returns the directory fp owns for global hooks, inside the
+// application data directory (~/.config/footprint/hooks on Linux). If
+// core.hooksPath already points at fp hooks somewhere else, from an older
+// install, that directory is used instead.
 func GlobalHooksDir() (string, error) {
-	home, err := os.UserHomeDir()
+	if current := GetCurrentGlobalHooksPath(); current != "" && isFpHooksDir(current) {
+		return current, nil
+	}
+	return filepath.Join(paths.AppDataDir(), "hooks"), nil
+}
+
+// GlobalScript is the hook fp writes to the global hooks directory. Git
+// ignores .git/hooks once core.hooksPath is set, so the script runs the
+// repository's own hook first, then records. A repository that has fp
+// hooks of its own (from fp setup) gets only those, so nothing is
+// recorded twice.
+func GlobalScript(fpPath string, source string) string {
+	header := globalMarker + ": runs the repository's own hook, then fp\n" +
+		"repo_hook=\n" +
+		"gitdir=$(git rev-parse --git-common-dir 2>/dev/null) && repo_hook=\"$gitdir/hooks/" + source + "\"\n" +
+		"if [ -x \"$repo_hook\" ] && grep -q 'FP_SOURCE=' \"$repo_hook\"; then\n" +
+		"\texec \"$repo_hook\" \"$@\"\n" +
+		"fi\n"
+	return chainScript(fpPath, source, header, "\"$repo_hook\"")
+}
+
+// isGlobalHook reports whether the hook at hookPath is a global fp hook.
+func isGlobalHook(hookPath string) bool {
+	data, err := os.ReadFile(hookPath)
 	if err != nil {
-		return "", err
+		return false
 	}
-	return filepath.Join(home, ".config", "git", "hooks"), nil
+	return strings.Contains(string(data), globalMarker)
+}
+
+// isFpHooksDir reports whether dir holds fp hooks and nothing else.
+func isFpHooksDir(dir string) bool {
+	found := findUnmanagedHooks(dir)
+	return len(found) > 0 && areAllFpHooks(dir, found)
 }
 
 // GetCurrentGlobalHooksPath returns the current value of core.hooksPath, if set.
@@ -42,7 +81,9 @@ func UnsetGlobalHooksPath() error {
 	return cmd.Run()
 }
 
-// InstallGlobal installs fp hooks in the global hooks directory and sets core.hooksPath.
+// InstallGlobal installs fp hooks in the global hooks directory and sets
+// core.hooksPath to it. A core.hooksPath that pointed somewhere else is
+// remembered, and UninstallGlobal sets it back.
 func InstallGlobal(hooksDir string) error {
 	log.Debug("hooks: installing globally to %s", hooksDir)
 
@@ -52,8 +93,13 @@ func InstallGlobal(hooksDir string) error {
 		return err
 	}
 
-	// Install the hooks
-	if err := Install(hooksDir); err != nil {
+	fpPath, err := os.Executable()
+	if err != nil {
+		log.Error("hooks: failed to get executable path: %v", err)
+		return err
+	}
+
+	if err := installGlobalFor(hooksDir, fpPath, GetCurrentGlobalHooksPath()); err != nil {
 		return err
 	}
 
@@ -67,7 +113,38 @@ func InstallGlobal(hooksDir string) error {
 	return nil
 }
 
-// UninstallGlobal removes fp hooks from the global directory and unsets core.hooksPath.
+// installGlobalFor writes the global hooks and records previous, the
+// core.hooksPath being replaced, unless it is this directory already.
+func installGlobalFor(hooksDir, fpPath, previous string) error {
+	if previous != "" && filepath.Clean(previous) != filepath.Clean(hooksDir) {
+		file := filepath.Join(hooksDir, previousHooksPathFile)
+		if err := os.WriteFile(file, []byte(previous+"\n"), 0600); err != nil {
+			log.Error("hooks: failed to remember core.hooksPath %s: %v", previous, err)
+			return err
+		}
+	}
+
+	for _, hook := range ManagedHooks {
+		target := filepath.Join(hooksDir, hook)
+
+		if exists(target) && !isFpHook(target) {
+			log.Debug("hooks: backing up existing %s", hook)
+			if err := backupHook(hooksDir, hook); err != nil {
+				log.Error("hooks: failed to backup %s: %v", hook, err)
+				return err
+			}
+		}
+
+		if err := os.WriteFile(target, []byte(GlobalScript(fpPath, hook)), filePermExecutable); err != nil {
+			log.Error("hooks: failed to write %s: %v", hook, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// UninstallGlobal removes fp hooks from the global directory and unsets
+// core.hooksPath, or sets it back to what it was before fp.
 func UninstallGlobal(hooksDir string) error {
 	log.Debug("hooks: uninstalling globally from %s", hooksDir)
 
@@ -76,6 +153,16 @@ func UninstallGlobal(hooksDir string) error {
 		return err
 	}
 
+	if previous := PreviousGlobalHooksPath(hooksDir); previous != "" {
+		if err := SetGlobalHooksPath(previous); err != nil {
+			log.Error("hooks: failed to restore core.hooksPath to %s: %v", previous, err)
+			return err
+		}
+		_ = os.Remove(filepath.Join(hooksDir, previousHooksPathFile))
+		log.Info("hooks: restored core.hooksPath to %s", previous)
+		return nil
+	}
+
 	// Unset core.hooksPath
 	if err := UnsetGlobalHooksPath(); err != nil {
 		// Ignore error if not set
@@ -85,6 +172,16 @@ func UninstallGlobal(hooksDir string) error {
 	return nil
 }
 
+// PreviousGlobalHooksPath returns the core.hooksPath that was set before
+// fp installed its global hooks in hooksDir, or "" if there was none.
+func PreviousGlobalHooksPath(hooksDir string) string {
+	data, err := os.ReadFile(filepath.Join(hooksDir, previousHooksPathFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
 // GlobalHooksStatus describes the current state of global hooks.
 type GlobalHooksStatus struct {
 	// IsSet is true if core.hooksPath is configured
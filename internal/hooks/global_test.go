@@ -2,6 +2,7 @@ package hooks
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 
@@ -12,8 +13,9 @@ func TestGlobalHooksDir(t *testing.T) {
 	dir, err := GlobalHooksDir()
 	require.NoError(t, err)
 	require.NotEmpty(t, dir)
-	require.Contains(t, dir, ".config")
-	require.Contains(t, dir, "git")
+	if GetCurrentGlobalHooksPath() == "" {
+		require.Contains(t, dir, "footprint")
+	}
 	require.Contains(t, dir, "hooks")
 }
 
@@ -76,3 +78,60 @@ func TestCanInstall(t *testing.T) {
 	require.False(t, StatusUnmanagedHooks.CanInstall())
 	require.False(t, StatusHooksPathOverride.CanInstall())
 }
+
+func TestInstallGlobal_RemembersPreviousHooksPath(t *testing.T) {
+	dir := t.TempDir()
+	fpPath := "/usr/local/bin/fp"
+
+	require.NoError(t, installGlobalFor(dir, fpPath, "/home/me/.githooks"))
+	require.Equal(t, "/home/me/.githooks", PreviousGlobalHooksPath(dir))
+	for _, hook := range ManagedHooks {
+		data, err := os.ReadFile(filepath.Join(dir, hook))
+		require.NoError(t, err)
+		require.Equal(t, GlobalScript(fpPath, hook), string(data))
+	}
+
+	// The remembered path isn't mistaken for a hook
+	require.True(t, isFpHooksDir(dir))
+	require.Empty(t, outdatedFor(dir, fpPath))
+
+	// Reinstalling over itself keeps what was remembered
+	require.NoError(t, installGlobalFor(dir, fpPath, dir))
+	require.Equal(t, "/home/me/.githooks", PreviousGlobalHooksPath(dir))
+
+	require.Empty(t, PreviousGlobalHooksPath(t.TempDir()))
+}
+
+func TestGlobalScript_DelegatesToRepoHooks(t *testing.T) {
+	dir, fpPath, logPath := chainFixture(t)
+	repo := filepath.Join(dir, "repo")
+	require.NoError(t, exec.Command("git", "init", "-q", repo).Run())
+
+	hooksDir := filepath.Join(dir, "global")
+	require.NoError(t, os.MkdirAll(hooksDir, 0700))
+	require.NoError(t, installGlobalFor(hooksDir, fpPath, ""))
+
+	run := func() string {
+		t.Helper()
+		_ = os.Remove(logPath)
+		cmd := exec.Command(filepath.Join(hooksDir, "post-merge"), "0")
+		cmd.Dir = repo
+		require.NoError(t, cmd.Run())
+		data, err := os.ReadFile(logPath)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	// No repository hook: only fp runs
+	require.Equal(t, "fp post-merge record\n", run())
+
+	// The repository's own hook runs first
+	repoHook := filepath.Join(repo, ".git", "hooks", "post-merge")
+	own := "#!/bin/sh\necho \"repo $*\" >> " + shellQuote(logPath) + "\n"
+	require.NoError(t, os.WriteFile(repoHook, []byte(own), 0755))
+	require.Equal(t, "repo 0\nfp post-merge record\n", run())
+
+	// A repository with its own fp hooks records through them only
+	require.NoError(t, os.WriteFile(repoHook, []byte(Script(fpPath, "post-merge")), 0755))
+	require.Equal(t, "fp post-merge record\n", run())
+}
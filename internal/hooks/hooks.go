@@ -16,4 +16,13 @@ var ManagedHooks = []string{
 	"post-checkout",
 	"post-rewrite",
 	"pre-push",
+	"reference-transaction",
+}
+
+// ServerHooks are the hooks fp installs in a bare repository, as on a
+// self-hosted git server. Nobody commits or checks out there, so none of
+// ManagedHooks ever run; post-receive records the branches each push
+// updates.
+var ServerHooks = []string{
+	"post-receive",
 }
@@ -2,7 +2,10 @@ package hooks
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -15,6 +18,7 @@ func TestManagedHooks_ContainsExpectedHooks(t *testing.T) {
 		"post-checkout",
 		"post-rewrite",
 		"pre-push",
+		"reference-transaction",
 	}
 
 	require.Equal(t, expectedHooks, ManagedHooks)
@@ -110,6 +114,21 @@ func TestScript_DifferentSources(t *testing.T) {
 	}
 }
 
+func TestScript_PrePushPassesRemote(t *testing.T) {
+	require.Contains(t, Script("/opt/fp", "pre-push"), `FP_SOURCE='pre-push' FP_PUSH_REMOTE="$1" FP_PUSH_URL="$2" '/opt/fp' record`)
+	require.NotContains(t, Script("/opt/fp", "post-commit"), "FP_PUSH_REMOTE")
+}
+
+func TestScript_ReferenceTransactionFilters(t *testing.T) {
+	script := Script("/opt/fp", "reference-transaction")
+
+	require.Contains(t, script, `[ "$1" = committed ] || exit 0`)
+	require.Contains(t, script, "refs/heads/")
+	require.Contains(t, script, "refs/stash$")
+	require.Contains(t, script, "refs/remotes/")
+	require.Contains(t, script, "'/opt/fp' record >/dev/null 2>&1 || true")
+}
+
 func TestBackupDir_ReturnsCorrectPath(t *testing.T) {
 	hooksPath := "/home/user/.config/git/hooks"
 
@@ -233,6 +252,28 @@ func TestUninstall_RemovesHooks(t *testing.T) {
 	}
 }
 
+func TestInstallServer(t *testing.T) {
+	tmpDir := t.TempDir()
+	original := "#!/bin/sh\necho 'deploy'"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "post-receive"), []byte(original), 0755))
+	require.Equal(t, map[string]bool{"post-receive": true}, ServerStatus(tmpDir))
+
+	require.NoError(t, InstallServer(tmpDir))
+
+	// Only the server hooks go into a bare repository
+	for _, hook := range ManagedHooks {
+		require.False(t, exists(filepath.Join(tmpDir, hook)), "hook '%s' should not be installed", hook)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, "post-receive"))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "FP_SOURCE='post-receive'")
+
+	require.NoError(t, UninstallServer(tmpDir))
+	data, err = os.ReadFile(filepath.Join(tmpDir, "post-receive"))
+	require.NoError(t, err)
+	require.Equal(t, original, string(data))
+}
+
 func TestUninstall_RestoresBackups(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -276,3 +317,179 @@ func TestInstall_InvalidPath(t *testing.T) {
 	err := Install("/nonexistent/path/that/does/not/exist")
 	require.Error(t, err)
 }
+
+func TestOutdated(t *testing.T) {
+	dir := t.TempDir()
+	fpPath := "/usr/local/bin/fp"
+
+	write := func(hook, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, hook), []byte(content), 0755))
+	}
+	write("post-commit", Script(fpPath, "post-commit"))
+	write("post-merge", Script("/old/location/fp", "post-merge"))
+	write("post-checkout", "#!/bin/sh\nfp record post-checkout\n")
+	write("pre-push", "#!/bin/sh\necho not ours\n")
+
+	require.Equal(t, []string{"post-merge", "post-checkout"}, outdatedFor(dir, fpPath))
+}
+
+func TestStates(t *testing.T) {
+	dir := t.TempDir()
+	fpPath := "/usr/local/bin/fp"
+
+	write := func(hook, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, hook), []byte(content), 0755))
+	}
+	write("post-commit", Script(fpPath, "post-commit"))
+	write("post-merge", Script("/old/location/fp", "post-merge"))
+	write("pre-push", "#!/bin/sh\necho not ours\n")
+
+	require.Equal(t, map[string]HookState{
+		"post-commit":           HookCurrent,
+		"post-merge":            HookOutdated,
+		"post-checkout":         HookMissing,
+		"post-rewrite":          HookMissing,
+		"pre-push":              HookForeign,
+		"reference-transaction": HookMissing,
+	}, statesFor(dir, fpPath))
+}
+
+func TestRefresh(t *testing.T) {
+	dir := t.TempDir()
+	fpPath := "/usr/local/bin/fp"
+
+	stale := Script("/old/location/fp", "post-merge")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "post-merge"), []byte(stale), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pre-push"), []byte("#!/bin/sh\necho not ours\n"), 0755))
+
+	refreshed, err := refreshFor(dir, fpPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{"post-merge"}, refreshed)
+	require.Empty(t, outdatedFor(dir, fpPath))
+
+	// No backups are taken and foreign hooks are untouched
+	require.NoDirExists(t, backupDir(dir))
+	data, err := os.ReadFile(filepath.Join(dir, "pre-push"))
+	require.NoError(t, err)
+	require.Equal(t, "#!/bin/sh\necho not ours\n", string(data))
+}
+
+// chainFixture writes a fake fp that logs how it was called, and returns
+// its path and the log file both it and the test hooks append to.
+func chainFixture(t *testing.T) (dir, fpPath, logPath string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts need sh")
+	}
+	dir = t.TempDir()
+	logPath = filepath.Join(dir, "calls.log")
+	fpPath = filepath.Join(dir, "fp")
+	fake := "#!/bin/sh\necho \"fp $FP_SOURCE $*\" >> " + shellQuote(logPath) + "\ncat >> " + shellQuote(logPath) + "\n"
+	require.NoError(t, os.WriteFile(fpPath, []byte(fake), 0755))
+	return dir, fpPath, logPath
+}
+
+func runHook(t *testing.T, path, stdin string, args ...string) error {
+	t.Helper()
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	return cmd.Run()
+}
+
+func TestInstallChained_RunsOriginalThenFp(t *testing.T) {
+	dir, fpPath, logPath := chainFixture(t)
+	hooksPath := filepath.Join(dir, "hooks")
+	require.NoError(t, os.MkdirAll(hooksPath, 0755))
+
+	original := "#!/bin/sh\necho \"original $*\" >> " + shellQuote(logPath) + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(hooksPath, "post-checkout"), []byte(original), 0755))
+
+	require.NoError(t, installChainedFor(hooksPath, fpPath, ManagedHooks))
+
+	// The original is kept in the backup dir and the hook chains to it;
+	// hooks that had no original get the plain script
+	require.FileExists(t, filepath.Join(backupDir(hooksPath), "post-checkout"))
+	require.True(t, isChainedHook(filepath.Join(hooksPath, "post-checkout")))
+	require.False(t, isChainedHook(filepath.Join(hooksPath, "post-commit")))
+
+	require.NoError(t, runHook(t, filepath.Join(hooksPath, "post-checkout"), "", "a", "b", "1"))
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.Equal(t, "original a b 1\nfp post-checkout record\n", string(data))
+
+	// Installing again doesn't back up fp's own hook over the original
+	require.NoError(t, installChainedFor(hooksPath, fpPath, ManagedHooks))
+	backup, err := os.ReadFile(filepath.Join(backupDir(hooksPath), "post-checkout"))
+	require.NoError(t, err)
+	require.Equal(t, original, string(backup))
+
+	require.NoError(t, Uninstall(hooksPath))
+	restored, err := os.ReadFile(filepath.Join(hooksPath, "post-checkout"))
+	require.NoError(t, err)
+	require.Equal(t, original, string(restored))
+	require.NoFileExists(t, filepath.Join(hooksPath, "post-commit"))
+}
+
+func TestChainScript_PrePush(t *testing.T) {
+	dir, fpPath, logPath := chainFixture(t)
+	hooksPath := filepath.Join(dir, "hooks")
+	require.NoError(t, os.MkdirAll(backupDir(hooksPath), 0755))
+	hook := filepath.Join(hooksPath, "pre-push")
+	require.NoError(t, os.WriteFile(hook, []byte(ChainScript(fpPath, "pre-push")), 0755))
+
+	// Both the original and fp see the refs on stdin
+	original := "#!/bin/sh\necho \"original $*\" >> " + shellQuote(logPath) + "\ncat >> " + shellQuote(logPath) + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(backupDir(hooksPath), "pre-push"), []byte(original), 0755))
+
+	refs := "refs/heads/main abc refs/heads/main def\n"
+	require.NoError(t, runHook(t, hook, refs, "origin", "url"))
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.Equal(t, "original origin url\n"+refs+"fp pre-push record\n"+refs, string(data))
+
+	// A failing original stops the push, and fp doesn't record it
+	require.NoError(t, os.Remove(logPath))
+	failing := "#!/bin/sh\nexit 3\n"
+	require.NoError(t, os.WriteFile(filepath.Join(backupDir(hooksPath), "pre-push"), []byte(failing), 0755))
+
+	err = runHook(t, hook, refs, "origin", "url")
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, 3, exitErr.ExitCode())
+	require.NoFileExists(t, logPath)
+}
+
+func TestChainScript_PostReceive(t *testing.T) {
+	dir, fpPath, logPath := chainFixture(t)
+	hooksPath := filepath.Join(dir, "hooks")
+	require.NoError(t, os.MkdirAll(hooksPath, 0755))
+	original := "#!/bin/sh\necho original >> " + shellQuote(logPath) + "\ncat >> " + shellQuote(logPath) + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(hooksPath, "post-receive"), []byte(original), 0755))
+
+	require.NoError(t, installChainedFor(hooksPath, fpPath, ServerHooks))
+
+	// The server's own hook and fp both read the pushed refs
+	refs := "0000000000000000000000000000000000000000 abc refs/heads/main\n"
+	require.NoError(t, runHook(t, filepath.Join(hooksPath, "post-receive"), refs))
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	require.Equal(t, "original\n"+refs+"fp post-receive record\n"+refs, string(data))
+}
+
+func TestRefresh_KeepsChainedHooks(t *testing.T) {
+	dir := t.TempDir()
+	fpPath := "/usr/local/bin/fp"
+
+	stale := ChainScript("/old/location/fp", "post-merge")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "post-merge"), []byte(stale), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "post-commit"), []byte(ChainScript(fpPath, "post-commit")), 0755))
+
+	require.Equal(t, []string{"post-merge"}, outdatedFor(dir, fpPath))
+	refreshed, err := refreshFor(dir, fpPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{"post-merge"}, refreshed)
+
+	data, err := os.ReadFile(filepath.Join(dir, "post-merge"))
+	require.NoError(t, err)
+	require.Equal(t, ChainScript(fpPath, "post-merge"), string(data))
+}
@@ -182,7 +182,7 @@ func findUnmanagedHooks(hooksPath string) []string {
 		}
 
 		// Skip fp backup directory marker or other non-hook files
-		if name == ".fp-backup" {
+		if name == ".fp-backup" || name == previousHooksPathFile {
 			continue
 		}
 
@@ -296,7 +296,10 @@ To install Footprint, you have these options:
   1. Remove or rename the existing hooks in .git/hooks/
      Then run: fp setup
 
-  2. Manually add Footprint to your existing hooks by adding:
+  2. Keep the existing hooks and run fp after them:
+     fp setup --chain
+
+  3. Manually add Footprint to your existing hooks by adding:
      fp record <hook-name>
 
      For example, in .git/hooks/post-commit add:
@@ -8,6 +8,16 @@ import (
 )
 
 func Install(hooksPath string) error {
+	return install(hooksPath, ManagedHooks)
+}
+
+// InstallServer installs ServerHooks in the hooks directory of a bare
+// repository, backing up existing hooks as Install does.
+func InstallServer(hooksPath string) error {
+	return install(hooksPath, ServerHooks)
+}
+
+func install(hooksPath string, names []string) error {
 	log.Debug("hooks: installing to %s", hooksPath)
 
 	// Ensure hooks directory exists
@@ -22,7 +32,7 @@ func Install(hooksPath string) error {
 		return err
 	}
 
-	for _, hook := range ManagedHooks {
+	for _, hook := range names {
 		target := filepath.Join(hooksPath, hook)
 
 		if exists(target) {
@@ -42,6 +52,6 @@ func Install(hooksPath string) error {
 		log.Debug("hooks: installed %s", hook)
 	}
 
-	log.Info("hooks: installed %d hooks to %s", len(ManagedHooks), hooksPath)
+	log.Info("hooks: installed %d hooks to %s", len(names), hooksPath)
 	return nil
 }
@@ -8,12 +8,38 @@ func shellQuote(s string) string {
 	return "'" + strings.ReplaceAll(s, "'", "'\"'\"'") + "'"
 }
 
+// referenceTransactionFilter matches the ref updates fp acts on: branch
+// creation, branch deletion and stash pushes, which it records, and
+// remote-tracking refs moving, which settle pushes waiting for an outcome.
+// Everything else (commits moving a branch, tags) is dropped before fp is
+// started.
+const referenceTransactionFilter = `^0+ [0-9a-f]+ refs/heads/|^[0-9a-f]+ 0+ refs/heads/|^[0-9a-f]+ 0*[1-9a-f][0-9a-f]* refs/stash$|^[0-9a-f]+ 0*[1-9a-f][0-9a-f]* refs/remotes/`
+
 func Script(fpPath string, source string) string {
+	if source == "reference-transaction" {
+		return referenceTransactionScript(fpPath)
+	}
+
 	// Run fp record with the source environment variable
 	// Redirect stdout to /dev/null (suppress normal output)
 	// Errors are now logged internally by fp record via the logger
 	// Use proper shell quoting to prevent injection
+	env := "FP_SOURCE=" + shellQuote(source) + " "
+	if source == "pre-push" {
+		// git passes the remote's name and URL as $1 and $2 and the refs
+		// being pushed on stdin
+		env += "FP_PUSH_REMOTE=\"$1\" FP_PUSH_URL=\"$2\" "
+	}
+	return "#!/bin/sh\n" + env + shellQuote(fpPath) + " record >/dev/null 2>&1 || true\n"
+}
+
+// referenceTransactionScript only runs fp for committed transactions that touch
+// branches or the stash, passing the matching "<old> <new> <ref>" lines on stdin.
+// The hook fires for every ref update, so filtering in shell keeps it cheap.
+func referenceTransactionScript(fpPath string) string {
 	return "#!/bin/sh\n" +
-		"FP_SOURCE=" + shellQuote(source) + " " +
+		"[ \"$1\" = committed ] || exit 0\n" +
+		"refs=$(grep -E " + shellQuote(referenceTransactionFilter) + ") || exit 0\n" +
+		"printf '%s\\n' \"$refs\" | FP_SOURCE='reference-transaction' " +
 		shellQuote(fpPath) + " record >/dev/null 2>&1 || true\n"
 }
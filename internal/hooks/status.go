@@ -2,13 +2,24 @@ package hooks
 
 import (
 	"os"
+	"path/filepath"
+	"slices"
 )
 
 func Status(hooksPath string) map[string]bool {
-	out := make(map[string]bool, len(ManagedHooks))
+	return status(hooksPath, ManagedHooks)
+}
+
+// ServerStatus is Status for the ServerHooks of a bare repository.
+func ServerStatus(hooksPath string) map[string]bool {
+	return status(hooksPath, ServerHooks)
+}
+
+func status(hooksPath string, names []string) map[string]bool {
+	out := make(map[string]bool, len(names))
 
 	// Initialize all hooks as false
-	for _, hook := range ManagedHooks {
+	for _, hook := range names {
 		out[hook] = false
 	}
 
@@ -27,9 +38,116 @@ func Status(hooksPath string) map[string]bool {
 	}
 
 	// Check which managed hooks exist
-	for _, hook := range ManagedHooks {
+	for _, hook := range names {
 		_, out[hook] = existing[hook]
 	}
 
 	return out
 }
+
+// Outdated returns the managed hooks in hooksPath that fp installed but
+// that differ from what Install would write today: an older script format,
+// or a path to an fp binary that has since moved. Hooks fp didn't install
+// are not reported.
+func Outdated(hooksPath string) []string {
+	fpPath, err := os.Executable()
+	if err != nil {
+		return nil
+	}
+	return outdatedFor(hooksPath, fpPath)
+}
+
+func outdatedFor(hooksPath, fpPath string) []string {
+	var outdated []string
+	// Server hooks are checked too, so fp hooks upgrade refreshes bare
+	// repositories; elsewhere there is no fp post-receive to report
+	for _, hook := range append(slices.Clone(ManagedHooks), ServerHooks...) {
+		target := filepath.Join(hooksPath, hook)
+		if !isFpHook(target) {
+			continue
+		}
+		data, err := os.ReadFile(target)
+		if err != nil {
+			continue
+		}
+		if string(data) != expectedScript(target, fpPath, hook) {
+			outdated = append(outdated, hook)
+		}
+	}
+	return outdated
+}
+
+// HookState is the state of one managed hook file.
+type HookState string
+
+const (
+	HookMissing  HookState = "missing"  // no file
+	HookCurrent  HookState = "current"  // fp's hook, as this fp would write it
+	HookOutdated HookState = "outdated" // fp's hook from another fp version or binary path
+	HookForeign  HookState = "foreign"  // a hook fp didn't install
+)
+
+// States returns the state of every managed hook in hooksPath, checked
+// against the running fp binary.
+func States(hooksPath string) map[string]HookState {
+	fpPath, err := os.Executable()
+	if err != nil {
+		fpPath = ""
+	}
+	return statesFor(hooksPath, fpPath)
+}
+
+func statesFor(hooksPath, fpPath string) map[string]HookState {
+	states := make(map[string]HookState, len(ManagedHooks))
+	for _, hook := range ManagedHooks {
+		target := filepath.Join(hooksPath, hook)
+		data, err := os.ReadFile(target)
+		switch {
+		case err != nil:
+			states[hook] = HookMissing
+		case !isFpHook(target):
+			states[hook] = HookForeign
+		case string(data) != expectedScript(target, fpPath, hook):
+			states[hook] = HookOutdated
+		default:
+			states[hook] = HookCurrent
+		}
+	}
+	return states
+}
+
+// expectedScript is what the fp hook at target should contain: the global
+// or chained script if it was installed that way, the plain one otherwise.
+func expectedScript(target, fpPath, hook string) string {
+	if isGlobalHook(target) {
+		return GlobalScript(fpPath, hook)
+	}
+	if isChainedHook(target) {
+		return ChainScript(fpPath, hook)
+	}
+	return Script(fpPath, hook)
+}
+
+// Refresh rewrites the outdated fp hooks in hooksPath with the current
+// script, keeping chained and global hooks as they were. Unlike Install, it
+// leaves backups alone: the hooks being replaced are fp's own, and backing
+// them up would overwrite the user's originals. Returns the hooks that were
+// rewritten.
+func Refresh(hooksPath string) ([]string, error) {
+	fpPath, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	return refreshFor(hooksPath, fpPath)
+}
+
+func refreshFor(hooksPath, fpPath string) ([]string, error) {
+	outdated := outdatedFor(hooksPath, fpPath)
+	for _, hook := range outdated {
+		target := filepath.Join(hooksPath, hook)
+		if err := os.WriteFile(target, []byte(expectedScript(target, fpPath, hook)), filePermExecutable); err != nil {
+			return nil, err
+		}
+	}
+	return outdated, nil
+}
@@ -8,10 +8,20 @@ import (
 )
 
 func Uninstall(hooksPath string) error {
+	return uninstall(hooksPath, ManagedHooks)
+}
+
+// UninstallServer removes ServerHooks from a bare repository, restoring
+// the hooks InstallServer backed up.
+func UninstallServer(hooksPath string) error {
+	return uninstall(hooksPath, ServerHooks)
+}
+
+func uninstall(hooksPath string, names []string) error {
 	log.Debug("hooks: uninstalling from %s", hooksPath)
 	bkpDir := backupDir(hooksPath)
 
-	for _, hook := range ManagedHooks {
+	for _, hook := range names {
 		target := filepath.Join(hooksPath, hook)
 		backup := filepath.Join(bkpDir, hook)
 
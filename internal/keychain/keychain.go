@@ -0,0 +1,57 @@
+// Package keychain keeps small secrets in the OS keychain: the login
+// keychain on macOS and the Secret Service (GNOME Keyring, KWallet) on
+// Linux, through their command-line tools. Other systems have none.
+package keychain
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// service names fp's entries in the keychain.
+const service = "footprint"
+
+// ErrUnavailable is returned when there is no keychain fp can use.
+var ErrUnavailable = errors.New("no OS keychain available")
+
+// ErrNotFound is returned by Get when the keychain has no such entry.
+var ErrNotFound = errors.New("not found in the OS keychain")
+
+// Get returns the secret stored for account.
+func Get(account string) (string, error) {
+	if !Available() {
+		return "", ErrUnavailable
+	}
+	out, err := getCommand(account).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("could not read the OS keychain: %w", err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// Set stores secret for account, replacing any earlier one.
+func Set(account, secret string) error {
+	if !Available() {
+		return ErrUnavailable
+	}
+	cmd := setCommand(account, secret)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("could not write to the OS keychain: %w\n%s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Available reports whether the keychain's tool is installed.
+func Available() bool {
+	if tool == "" {
+		return false
+	}
+	_, err := exec.LookPath(tool)
+	return err == nil
+}
@@ -0,0 +1,16 @@
+package keychain
+
+import "os/exec"
+
+// tool is the command-line interface to the login keychain.
+const tool = "security"
+
+func getCommand(account string) *exec.Cmd {
+	return exec.Command(tool, "find-generic-password", "-s", service, "-a", account, "-w")
+}
+
+// setCommand passes the secret as an argument: security can only read it
+// from a prompt otherwise.
+func setCommand(account, secret string) *exec.Cmd {
+	return exec.Command(tool, "add-generic-password", "-U", "-s", service, "-a", account, "-w", secret)
+}
@@ -0,0 +1,20 @@
+package keychain
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// tool is libsecret's command-line interface to the Secret Service.
+const tool = "secret-tool"
+
+func getCommand(account string) *exec.Cmd {
+	return exec.Command(tool, "lookup", "service", service, "account", account)
+}
+
+// setCommand passes the secret on stdin, where secret-tool reads it.
+func setCommand(account, secret string) *exec.Cmd {
+	cmd := exec.Command(tool, "store", "--label", "fp "+account, "service", service, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	return cmd
+}
@@ -0,0 +1,16 @@
+//go:build !darwin && !linux
+
+package keychain
+
+import "os/exec"
+
+// tool is empty: fp has no keychain on this system.
+const tool = ""
+
+func getCommand(string) *exec.Cmd {
+	return nil
+}
+
+func setCommand(string, string) *exec.Cmd {
+	return nil
+}
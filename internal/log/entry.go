@@ -0,0 +1,143 @@
+package log
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is one line of the log file.
+//
+// Entries are written as logfmt, key=value pairs with values quoted when
+// they hold spaces, quotes or line breaks:
+//
+//	time=2026-01-29T10:30:45+01:00 level=WARN caller=export.go:42 msg="export: push failed"
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Caller  string
+	Message string
+}
+
+// String renders the entry as a log line, without the trailing newline.
+func (e Entry) String() string {
+	var b strings.Builder
+	writeField(&b, "time", e.Time.Format(time.RFC3339))
+	writeField(&b, "level", e.Level.String())
+	writeField(&b, "caller", e.Caller)
+	writeField(&b, "msg", e.Message)
+	return b.String()
+}
+
+func writeField(b *strings.Builder, key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(key)
+	b.WriteByte('=')
+	if needsQuoting(value) {
+		b.WriteString(strconv.Quote(value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+func needsQuoting(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '"' || r == '=' || r == '\\' {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseEntry parses a line written by the logger. ok is false for other
+// lines, such as those written before entries were structured.
+func ParseEntry(line string) (Entry, bool) {
+	fields, ok := parseFields(line)
+	if !ok {
+		return Entry{}, false
+	}
+	t, err := time.Parse(time.RFC3339, fields["time"])
+	if err != nil {
+		return Entry{}, false
+	}
+	level, ok := ParseLevel(fields["level"])
+	if !ok {
+		return Entry{}, false
+	}
+	return Entry{
+		Time:    t,
+		Level:   level,
+		Caller:  fields["caller"],
+		Message: fields["msg"],
+	}, true
+}
+
+// parseFields splits a logfmt line into its keys and values.
+func parseFields(line string) (map[string]string, bool) {
+	fields := make(map[string]string)
+	for rest := strings.TrimSpace(line); rest != ""; rest = strings.TrimLeft(rest, " ") {
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 || strings.ContainsRune(rest[:eq], ' ') {
+			return nil, false
+		}
+		key := rest[:eq]
+		rest = rest[eq+1:]
+
+		if strings.HasPrefix(rest, `"`) {
+			end := closingQuote(rest)
+			if end < 0 {
+				return nil, false
+			}
+			value, err := strconv.Unquote(rest[:end+1])
+			if err != nil {
+				return nil, false
+			}
+			fields[key] = value
+			rest = rest[end+1:]
+			continue
+		}
+
+		end := strings.IndexByte(rest, ' ')
+		if end < 0 {
+			end = len(rest)
+		}
+		fields[key] = rest[:end]
+		rest = rest[end:]
+	}
+	return fields, len(fields) > 0
+}
+
+// closingQuote returns the index of the quote closing the quoted value s
+// starts with, or -1 if it isn't closed.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// ParseLevel parses a level name, such as "warn" or "ERROR".
+func ParseLevel(name string) (Level, bool) {
+	switch strings.ToUpper(name) {
+	case "DEBUG":
+		return LevelDebug, true
+	case "INFO":
+		return LevelInfo, true
+	case "WARN", "WARNING":
+		return LevelWarn, true
+	case "ERROR":
+		return LevelError, true
+	default:
+		return 0, false
+	}
+}
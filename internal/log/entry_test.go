@@ -0,0 +1,56 @@
+package log
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntry_RoundTrip(t *testing.T) {
+	entry := Entry{
+		Time:    time.Date(2026, 1, 29, 10, 30, 45, 0, time.FixedZone("", 3600)),
+		Level:   LevelWarn,
+		Caller:  "export.go:42",
+		Message: `export: push failed: "remote rejected" (exit=1)` + "\nhint: pull first",
+	}
+
+	line := entry.String()
+	want := `time=2026-01-29T10:30:45+01:00 level=WARN caller=export.go:42 msg="export: push failed: \"remote rejected\" (exit=1)\nhint: pull first"`
+	if line != want {
+		t.Fatalf("String() = %s, want %s", line, want)
+	}
+
+	got, ok := ParseEntry(line)
+	if !ok {
+		t.Fatalf("ParseEntry(%q) failed", line)
+	}
+	if !got.Time.Equal(entry.Time) || got.Level != entry.Level || got.Caller != entry.Caller || got.Message != entry.Message {
+		t.Errorf("ParseEntry() = %+v, want %+v", got, entry)
+	}
+}
+
+func TestParseEntry_OtherLines(t *testing.T) {
+	for _, line := range []string{
+		"",
+		"[2024-01-15 10:30:45] ERROR store.go:123: something failed",
+		"time=yesterday level=INFO msg=hi",
+		"time=2026-01-29T10:30:45Z level=LOUD msg=hi",
+		`time=2026-01-29T10:30:45Z level=INFO msg="unterminated`,
+	} {
+		if _, ok := ParseEntry(line); ok {
+			t.Errorf("ParseEntry(%q) succeeded, want it to fail", line)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]Level{"debug": LevelDebug, "Info": LevelInfo, "warn": LevelWarn, "WARNING": LevelWarn, "error": LevelError}
+	for name, want := range tests {
+		got, ok := ParseLevel(name)
+		if !ok || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", name, got, ok, want)
+		}
+	}
+	if _, ok := ParseLevel("fatal"); ok {
+		t.Error("ParseLevel(\"fatal\") succeeded")
+	}
+}
@@ -112,7 +112,6 @@ func (l *Logger) logWithCaller(level Level, skip int, format string, args ...any
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
 	message := fmt.Sprintf(format, args...)
 
 	// Get caller information
@@ -123,7 +122,8 @@ func (l *Logger) logWithCaller(level Level, skip int, format string, args ...any
 		caller = fmt.Sprintf("%s:%d", file, line)
 	}
 
-	logLine := fmt.Sprintf("[%s] %s %s: %s\n", timestamp, level.String(), caller, message)
+	entry := Entry{Time: time.Now(), Level: level, Caller: caller, Message: message}
+	logLine := entry.String() + "\n"
 
 	if _, err := l.file.Write([]byte(logLine)); err != nil {
 		// Can't log to file, output to stderr for critical messages
@@ -34,7 +34,7 @@ func TestLogger_BasicLogging(t *testing.T) {
 
 	logContent := string(content)
 
-	// Verify all messages are present (format: [timestamp] LEVEL caller: message)
+	// Verify all messages are present (format: time=... level=LEVEL caller=... msg=...)
 	if !strings.Contains(logContent, "DEBUG") || !strings.Contains(logContent, "debug message") {
 		t.Error("Debug message not found in log")
 	}
@@ -366,3 +366,53 @@ func TestNopLogger(t *testing.T) {
 		t.Errorf("NopLogger.Close() should return nil, got %v", err)
 	}
 }
+
+func TestRotate(t *testing.T) {
+	tmpDir := t.TempDir()
+	logPath := filepath.Join(tmpDir, "test.log")
+
+	// Missing file is not an error
+	rotated, err := Rotate(logPath, 10)
+	if err != nil || rotated {
+		t.Fatalf("Rotate on missing file = %v, %v; want false, nil", rotated, err)
+	}
+
+	logger, err := New(logPath, LevelDebug)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() { _ = logger.Close() }()
+
+	logger.Info("first message that is long enough to rotate")
+
+	// Under the limit: nothing happens
+	rotated, err = Rotate(logPath, 1<<20)
+	if err != nil || rotated {
+		t.Fatalf("Rotate under limit = %v, %v; want false, nil", rotated, err)
+	}
+
+	rotated, err = Rotate(logPath, 10)
+	if err != nil || !rotated {
+		t.Fatalf("Rotate over limit = %v, %v; want true, nil", rotated, err)
+	}
+
+	old, err := os.ReadFile(logPath + ".1")
+	if err != nil {
+		t.Fatalf("Failed to read rotated log: %v", err)
+	}
+	if !strings.Contains(string(old), "first message") {
+		t.Errorf("Rotated log missing old content: %q", old)
+	}
+
+	// The open logger keeps writing to the live file
+	logger.Info("second message")
+	_ = logger.Close()
+
+	current, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	if strings.Contains(string(current), "first message") || !strings.Contains(string(current), "second message") {
+		t.Errorf("Unexpected live log content: %q", current)
+	}
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Rotate moves the contents of the log file at path to path+".1" when the
+// file is larger than maxBytes, replacing any previous rotation.
+//
+// The log is copied and then truncated in place rather than renamed, so
+// loggers that already hold the file open (opened with O_APPEND) keep
+// writing to the live log. Returns whether a rotation happened.
+func Rotate(path string, maxBytes int64) (bool, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if info.Size() <= maxBytes {
+		return false, nil
+	}
+
+	src, err := os.OpenFile(path, os.O_RDWR, 0600)
+	if err != nil {
+		return false, fmt.Errorf("open log file: %w", err)
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := os.OpenFile(path+".1", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return false, fmt.Errorf("create rotated log: %w", err)
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return false, fmt.Errorf("copy log file: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		return false, fmt.Errorf("close rotated log: %w", err)
+	}
+
+	if err := src.Truncate(0); err != nil {
+		return false, fmt.Errorf("truncate log file: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,30 @@
+package output
+
+// Check statuses used by the JSON output of fp doctor and fp repos check.
+// They are part of the scripting contract: tools such as Ansible or chezmoi
+// match on them, so existing values must not change.
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// Severities paired with the check statuses above.
+const (
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// Severity maps a check status to its severity. Unknown statuses are
+// treated as errors so a consumer never mistakes them for success.
+func Severity(status string) string {
+	switch status {
+	case CheckPass:
+		return SeverityInfo
+	case CheckWarn:
+		return SeverityWarning
+	default:
+		return SeverityError
+	}
+}
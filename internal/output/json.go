@@ -5,6 +5,11 @@ import (
 	"fmt"
 )
 
+// SchemaVersion is the schema_version of the objects fp prints with
+// --json. It only changes when a key is renamed or removed, or its meaning
+// changes; new keys don't bump it.
+const SchemaVersion = 1
+
 // PrintlnFunc is the function signature for printing with a newline
 type PrintlnFunc func(a ...any) (int, error)
 
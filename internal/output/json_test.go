@@ -138,3 +138,17 @@ func TestJSONLineMarshalError(t *testing.T) {
 		t.Errorf("error should mention marshal failure, got: %v", err)
 	}
 }
+
+func TestSeverity(t *testing.T) {
+	tests := map[string]string{
+		CheckPass: SeverityInfo,
+		CheckWarn: SeverityWarning,
+		CheckFail: SeverityError,
+		"bogus":   SeverityError,
+	}
+	for status, want := range tests {
+		if got := Severity(status); got != want {
+			t.Errorf("Severity(%q) = %q, want %q", status, got, want)
+		}
+	}
+}
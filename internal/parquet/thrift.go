@@ -0,0 +1,99 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol type identifiers used by the parquet metadata.
+const (
+	ctI32    = 5
+	ctI64    = 6
+	ctBinary = 8
+	ctList   = 9
+	ctStruct = 12
+)
+
+// compactWriter encodes the subset of the Thrift compact protocol needed to
+// serialize parquet page headers and file metadata.
+type compactWriter struct {
+	buf     bytes.Buffer
+	lastIDs []int16 // field id stack, one entry per open struct
+	lastID  int16
+}
+
+func (w *compactWriter) varint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	w.buf.Write(tmp[:n])
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func (w *compactWriter) fieldHeader(id int16, typ byte) {
+	delta := id - w.lastID
+	if delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.varint(zigzag(int64(id)))
+	}
+	w.lastID = id
+}
+
+func (w *compactWriter) beginStruct() {
+	w.lastIDs = append(w.lastIDs, w.lastID)
+	w.lastID = 0
+}
+
+func (w *compactWriter) endStruct() {
+	w.buf.WriteByte(0) // stop field
+	w.lastID = w.lastIDs[len(w.lastIDs)-1]
+	w.lastIDs = w.lastIDs[:len(w.lastIDs)-1]
+}
+
+func (w *compactWriter) i32Field(id int16, v int32) {
+	w.fieldHeader(id, ctI32)
+	w.varint(zigzag(int64(v)))
+}
+
+func (w *compactWriter) i64Field(id int16, v int64) {
+	w.fieldHeader(id, ctI64)
+	w.varint(zigzag(v))
+}
+
+func (w *compactWriter) stringField(id int16, s string) {
+	w.fieldHeader(id, ctBinary)
+	w.varint(uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+func (w *compactWriter) structField(id int16) {
+	w.fieldHeader(id, ctStruct)
+	w.beginStruct()
+}
+
+func (w *compactWriter) listField(id int16, elemType byte, size int) {
+	w.fieldHeader(id, ctList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		w.buf.WriteByte(0xF0 | elemType)
+		w.varint(uint64(size))
+	}
+}
+
+func (w *compactWriter) i32Elem(v int32) {
+	w.varint(zigzag(int64(v)))
+}
+
+func (w *compactWriter) stringElem(s string) {
+	w.varint(uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+func (w *compactWriter) Bytes() []byte {
+	return w.buf.Bytes()
+}
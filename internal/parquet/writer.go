@@ -0,0 +1,218 @@
+// Package parquet implements a minimal Apache Parquet writer.
+//
+// It only supports what the exporter needs: flat schemas of required
+// UTF-8 string and int64 columns, written as a single uncompressed row
+// group with PLAIN encoding. Files produced here are readable by standard
+// tools (DuckDB, pandas/pyarrow, Spark).
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const magic = "PAR1"
+
+// Type is the logical type of a column.
+type Type int
+
+const (
+	String Type = iota // UTF-8 string stored as BYTE_ARRAY
+	Int64              // signed 64-bit integer
+)
+
+// Column describes one column of the file schema.
+type Column struct {
+	Name string
+	Type Type
+}
+
+// Parquet physical types, encodings and enums from parquet.thrift.
+const (
+	physicalInt64     = 2
+	physicalByteArray = 6
+
+	repetitionRequired = 0
+	convertedUTF8      = 0
+	encodingPlain      = 0
+	encodingRLE        = 3
+	codecUncompressed  = 0
+	pageTypeData       = 0
+)
+
+type chunkInfo struct {
+	offset int64
+	size   int64
+}
+
+// Write encodes rows as a parquet file. Each row must have one value per
+// column; Int64 columns are parsed from their string form, with an empty
+// string treated as zero.
+func Write(w io.Writer, columns []Column, rows [][]string) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("parquet: schema has no columns")
+	}
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return fmt.Errorf("parquet: row %d has %d values, expected %d", i, len(row), len(columns))
+		}
+	}
+
+	cw := &countingWriter{w: w}
+	if _, err := io.WriteString(cw, magic); err != nil {
+		return err
+	}
+
+	chunks := make([]chunkInfo, len(columns))
+	for i, col := range columns {
+		page, err := encodeColumn(col, i, rows)
+		if err != nil {
+			return err
+		}
+
+		header := encodePageHeader(len(rows), len(page))
+		chunks[i].offset = cw.n
+		chunks[i].size = int64(len(header) + len(page))
+
+		if _, err := cw.Write(header); err != nil {
+			return err
+		}
+		if _, err := cw.Write(page); err != nil {
+			return err
+		}
+	}
+
+	footer := encodeFileMetaData(columns, chunks, int64(len(rows)))
+	if _, err := cw.Write(footer); err != nil {
+		return err
+	}
+
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(footer)))
+	if _, err := cw.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := io.WriteString(cw, magic)
+	return err
+}
+
+// encodeColumn returns the PLAIN-encoded values of one column.
+// Required columns carry no repetition or definition levels.
+func encodeColumn(col Column, idx int, rows [][]string) ([]byte, error) {
+	var out []byte
+	for r, row := range rows {
+		value := row[idx]
+		switch col.Type {
+		case Int64:
+			var n int64
+			if value != "" {
+				var err error
+				n, err = strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("parquet: column %s row %d: %w", col.Name, r, err)
+				}
+			}
+			out = binary.LittleEndian.AppendUint64(out, uint64(n))
+		default:
+			out = binary.LittleEndian.AppendUint32(out, uint32(len(value)))
+			out = append(out, value...)
+		}
+	}
+	return out, nil
+}
+
+func encodePageHeader(numValues, size int) []byte {
+	w := &compactWriter{}
+	w.beginStruct()
+	w.i32Field(1, pageTypeData)
+	w.i32Field(2, int32(size)) // uncompressed_page_size
+	w.i32Field(3, int32(size)) // compressed_page_size
+	w.structField(5)           // data_page_header
+	w.i32Field(1, int32(numValues))
+	w.i32Field(2, encodingPlain)
+	w.i32Field(3, encodingRLE)
+	w.i32Field(4, encodingRLE)
+	w.endStruct()
+	w.endStruct()
+	return w.Bytes()
+}
+
+func encodeFileMetaData(columns []Column, chunks []chunkInfo, numRows int64) []byte {
+	w := &compactWriter{}
+	w.beginStruct()
+	w.i32Field(1, 1) // version
+
+	// Schema: root element followed by one leaf per column
+	w.listField(2, ctStruct, len(columns)+1)
+	w.beginStruct()
+	w.stringField(4, "schema")
+	w.i32Field(5, int32(len(columns)))
+	w.endStruct()
+	for _, col := range columns {
+		w.beginStruct()
+		w.i32Field(1, physicalType(col.Type))
+		w.i32Field(3, repetitionRequired)
+		w.stringField(4, col.Name)
+		if col.Type == String {
+			w.i32Field(6, convertedUTF8)
+		}
+		w.endStruct()
+	}
+
+	w.i64Field(3, numRows)
+
+	// Single row group holding every column chunk
+	var totalSize int64
+	for _, c := range chunks {
+		totalSize += c.size
+	}
+	w.listField(4, ctStruct, 1)
+	w.beginStruct()
+	w.listField(1, ctStruct, len(columns))
+	for i, col := range columns {
+		w.beginStruct()
+		w.i64Field(2, chunks[i].offset) // file_offset
+		w.structField(3)                // meta_data
+		w.i32Field(1, physicalType(col.Type))
+		w.listField(2, ctI32, 1)
+		w.i32Elem(encodingPlain)
+		w.listField(3, ctBinary, 1)
+		w.stringElem(col.Name)
+		w.i32Field(4, codecUncompressed)
+		w.i64Field(5, numRows)
+		w.i64Field(6, chunks[i].size)
+		w.i64Field(7, chunks[i].size)
+		w.i64Field(9, chunks[i].offset) // data_page_offset
+		w.endStruct()
+		w.endStruct()
+	}
+	w.i64Field(2, totalSize)
+	w.i64Field(3, numRows)
+	w.endStruct()
+
+	w.stringField(6, "footprint")
+	w.endStruct()
+	return w.Bytes()
+}
+
+func physicalType(t Type) int32 {
+	if t == Int64 {
+		return physicalInt64
+	}
+	return physicalByteArray
+}
+
+// countingWriter tracks the number of bytes written so column chunk
+// offsets can be recorded in the footer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
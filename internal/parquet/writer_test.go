@@ -0,0 +1,62 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite_FileLayout(t *testing.T) {
+	var buf bytes.Buffer
+	columns := []Column{{Name: "name", Type: String}, {Name: "count", Type: Int64}}
+	rows := [][]string{{"alpha", "1"}, {"beta", ""}}
+
+	err := Write(&buf, columns, rows)
+	require.NoError(t, err)
+
+	data := buf.Bytes()
+	require.Equal(t, magic, string(data[:4]))
+	require.Equal(t, magic, string(data[len(data)-4:]))
+
+	footerLen := binary.LittleEndian.Uint32(data[len(data)-8 : len(data)-4])
+	require.Less(t, int(footerLen), len(data)-12)
+
+	// First column chunk starts right after the leading magic with a DATA_PAGE header
+	require.Equal(t, byte(0x15), data[4]) // field 1, type i32
+	require.Equal(t, byte(0x00), data[5]) // DATA_PAGE
+	require.Contains(t, string(data), "alpha")
+	require.Contains(t, string(data[len(data)-8-int(footerLen):]), "count")
+}
+
+func TestWrite_PlainEncodesValues(t *testing.T) {
+	page, err := encodeColumn(Column{Name: "n", Type: Int64}, 0, [][]string{{"7"}, {""}})
+	require.NoError(t, err)
+	require.Len(t, page, 16)
+	require.Equal(t, uint64(7), binary.LittleEndian.Uint64(page[:8]))
+	require.Equal(t, uint64(0), binary.LittleEndian.Uint64(page[8:]))
+
+	page, err = encodeColumn(Column{Name: "s", Type: String}, 0, [][]string{{"ab"}})
+	require.NoError(t, err)
+	require.Equal(t, []byte{2, 0, 0, 0, 'a', 'b'}, page)
+}
+
+func TestWrite_RejectsBadRows(t *testing.T) {
+	var buf bytes.Buffer
+	columns := []Column{{Name: "count", Type: Int64}}
+
+	require.Error(t, Write(&buf, columns, [][]string{{"x"}}))
+	require.Error(t, Write(&buf, columns, [][]string{{"1", "2"}}))
+	require.Error(t, Write(&buf, nil, nil))
+}
+
+func TestCompactWriter_LongFieldDelta(t *testing.T) {
+	w := &compactWriter{}
+	w.beginStruct()
+	w.i32Field(20, 1)
+	w.endStruct()
+
+	// delta > 15 falls back to explicit zigzag field id
+	require.Equal(t, []byte{ctI32, 40, 2, 0}, w.Bytes())
+}
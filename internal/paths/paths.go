@@ -4,14 +4,60 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 )
 
 const (
 	appDirName = "footprint"
+	// profilesDirName holds one directory per config profile, in both
+	// AppDataDir and AppLocalDataDir.
+	profilesDirName = "profiles"
 	// dirPermPrivate is for directories containing sensitive data (owner only).
 	dirPermPrivate os.FileMode = 0700
 )
 
+// profile is the active config profile; "" is the default profile.
+var profile string
+
+// SetProfile makes name the active config profile. A profile has its own
+// config file, database and export repository, so its events never mix
+// with those of other profiles. "" selects the default profile.
+func SetProfile(name string) {
+	profile = name
+}
+
+// Profile returns the active config profile, "" for the default one.
+func Profile() string {
+	return profile
+}
+
+// ProfilesDir returns the directory holding the config and database of
+// each profile other than the default one.
+func ProfilesDir() string {
+	return filepath.Join(AppDataDir(), profilesDirName)
+}
+
+// ProfileDir returns the directory holding the active profile's database:
+// AppDataDir for the default profile.
+func ProfileDir() string {
+	if profile == "" {
+		return AppDataDir()
+	}
+	return filepath.Join(ProfilesDir(), profile)
+}
+
+// ProfileConfigFilePath returns the config file of the named profile.
+func ProfileConfigFilePath(name string) (string, error) {
+	if name == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".fprc"), nil
+	}
+	return filepath.Join(ProfilesDir(), name, "fprc"), nil
+}
+
 // AppDataDir returns the application data directory for config/database.
 // Uses os.UserConfigDir() which returns:
 //   - macOS: ~/Library/Application Support
@@ -74,22 +120,57 @@ func AppLocalDataDir() string {
 	return filepath.Join(base, appDirName)
 }
 
+// RuntimeDir returns a directory for files that shouldn't outlive the
+// login session, such as the unlocked copy of an encrypted database:
+//   - $XDG_RUNTIME_DIR/footprint when set (a tmpfs only the user can read,
+//     cleared at logout)
+//   - footprint-<uid> in the temp directory otherwise (footprint on
+//     Windows, which has no uids); the temp directory is per user on
+//     macOS and Windows
+//
+// The directory is created owner-only; callers that keep secrets in it
+// should still check it is, since a shared temp directory could hold one
+// another user made.
+func RuntimeDir() string {
+	base, name := os.Getenv("XDG_RUNTIME_DIR"), appDirName
+	if base == "" {
+		base = os.TempDir()
+		if uid := os.Getuid(); uid >= 0 {
+			name += "-" + strconv.Itoa(uid)
+		}
+	}
+	path := filepath.Join(base, name)
+	_ = os.MkdirAll(path, dirPermPrivate)
+	return path
+}
+
 // ExportRepoDir returns the path to the export repository.
 // The export repo is internal application data and lives inside AppLocalDataDir.
 //   - macOS: ~/Library/Application Support/footprint/export
 //   - Linux: $XDG_DATA_HOME/footprint/export or ~/.local/share/footprint/export
 //   - Windows: %LOCALAPPDATA%\footprint\export
+//
+// Other profiles than the default one export to profiles/<name>/export.
 func ExportRepoDir() string {
+	if profile != "" {
+		return filepath.Join(AppLocalDataDir(), profilesDirName, profile, "export")
+	}
 	return filepath.Join(AppLocalDataDir(), "export")
 }
 
+// ConfigFilePath returns the config file of the active profile: ~/.fprc
+// for the default profile.
 func ConfigFilePath() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
+	return ProfileConfigFilePath(profile)
+}
 
-	return filepath.Join(home, ".fprc"), nil
+// ThemesDir returns the directory holding custom theme files, shared by
+// all profiles:
+//   - macOS: ~/Library/Application Support/footprint/themes
+//   - Linux: $XDG_CONFIG_HOME/footprint/themes or ~/.config/footprint/themes
+//   - Windows: %AppData%\footprint\themes
+func ThemesDir() string {
+	return filepath.Join(AppDataDir(), "themes")
 }
 
 // LogFilePath returns the path to the application log file.
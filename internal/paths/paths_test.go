@@ -107,6 +107,10 @@ func TestLogFilePath_IsUnderAppDataDir(t *testing.T) {
 		logPath, appDataDir)
 }
 
+func TestThemesDir_IsUnderAppDataDir(t *testing.T) {
+	require.Equal(t, filepath.Join(AppDataDir(), "themes"), ThemesDir())
+}
+
 func TestAppDataDir_CreatesDirectory(t *testing.T) {
 	// This test verifies that AppDataDir creates the directory if it doesn't exist
 	dir := AppDataDir()
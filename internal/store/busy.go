@@ -0,0 +1,61 @@
+package store
+
+import (
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// busyRetries and busyBackoff bound how long a write keeps retrying after
+// SQLite reports the database busy. busy_timeout already waits for most
+// locks; these cover the cases where SQLite gives up at once instead, such
+// as a WAL snapshot that went stale before the write began.
+var (
+	busyRetries = 8
+	busyBackoff = 10 * time.Millisecond
+)
+
+// writeMu queues the writes of this process, so goroutines sharing a
+// database only compete with other processes for the lock.
+var writeMu sync.Mutex
+
+// busyMessages are how the driver words SQLITE_BUSY and SQLITE_LOCKED.
+// They are matched as text because the driver's error type only exists in
+// cgo builds, and releases are built without cgo.
+var busyMessages = []string{"database is locked", "database table is locked", "SQLITE_BUSY", "SQLITE_LOCKED"}
+
+// isBusy reports whether err means another connection holds the lock.
+func isBusy(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	for _, m := range busyMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryBusy runs write, retrying with jittered exponential backoff while
+// the database is busy. Other errors are returned at once.
+func retryBusy(what string, write func() error) error {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	backoff := busyBackoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = write(); !isBusy(err) || attempt == busyRetries {
+			return err
+		}
+		wait := backoff/2 + rand.N(backoff)
+		log.Debug("store: %s: database busy, retrying in %s", what, wait)
+		time.Sleep(wait)
+		backoff *= 2
+	}
+}
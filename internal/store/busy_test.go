@@ -0,0 +1,97 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBusy(t *testing.T) {
+	saved := busyBackoff
+	busyBackoff = time.Millisecond
+	t.Cleanup(func() { busyBackoff = saved })
+
+	busy := errors.New("database is locked")
+
+	calls := 0
+	err := retryBusy("test", func() error {
+		calls++
+		if calls < 3 {
+			return busy
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+
+	calls = 0
+	err = retryBusy("test", func() error {
+		calls++
+		return busy
+	})
+	require.True(t, isBusy(err))
+	require.Equal(t, busyRetries+1, calls)
+
+	calls = 0
+	err = retryBusy("test", func() error {
+		calls++
+		return errors.New("constraint failed")
+	})
+	require.EqualError(t, err, "constraint failed")
+	require.Equal(t, 1, calls, "other errors are not retried")
+
+	require.True(t, isBusy(fmt.Errorf("insert: %w", errors.New("database table is locked"))))
+	require.False(t, isBusy(nil))
+}
+
+// TestInsertEvent_ConcurrentRecorders fires recorders the way simultaneous
+// hooks do, each with its own connection, and checks no event is lost.
+func TestInsertEvent_ConcurrentRecorders(t *testing.T) {
+	const recorders = 100
+	path := filepath.Join(t.TempDir(), "store.db")
+
+	s, err := New(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	errs := make(chan error, recorders)
+	for i := range recorders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s, err := New(path)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer func() { _ = s.Close() }()
+			errs <- InsertEvent(s.DB(), RepoEvent{
+				RepoID:    fmt.Sprintf("github.com/user/repo%d", i%10),
+				Commit:    fmt.Sprintf("commit%03d", i),
+				Branch:    "main",
+				Timestamp: ts.Add(time.Duration(i) * time.Second),
+				Status:    StatusPending,
+				Source:    SourcePostCommit,
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	s, err = New(path)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+	var count int
+	require.NoError(t, s.DB().QueryRow(`SELECT COUNT(*) FROM repo_events`).Scan(&count))
+	require.Equal(t, recorders, count)
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+// LanguageLines is the lines added and removed in one language, by one
+// commit or added up over many.
+type LanguageLines struct {
+	Language   string `json:"language"`
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+}
+
+// SaveCommitLanguages stores the lines a commit changed per language,
+// replacing any stored before for it.
+func SaveCommitLanguages(db *sql.DB, repoID, commit string, lines []LanguageLines) error {
+	return retryBusy("save commit languages", func() error {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if _, err := tx.Exec(`DELETE FROM commit_languages WHERE repo_id = ? AND commit_hash = ?`, repoID, commit); err != nil {
+			return err
+		}
+		for _, l := range lines {
+			if _, err := tx.Exec(`
+				INSERT INTO commit_languages (repo_id, commit_hash, language, insertions, deletions)
+				VALUES (?, ?, ?, ?, ?)`,
+				repoID, commit, l.Language, l.Insertions, l.Deletions); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+}
+
+// HasCommitLanguages reports whether languages are stored for a commit.
+func HasCommitLanguages(db *sql.DB, repoID, commit string) (bool, error) {
+	var n int
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM commit_languages WHERE repo_id = ? AND commit_hash = ?`,
+		repoID, commit,
+	).Scan(&n)
+	return n > 0, err
+}
+
+// ListCommitLanguages returns the stored languages of the commits with an
+// event between since and until, keyed by CommitKey. Either bound may be
+// nil.
+func ListCommitLanguages(db *sql.DB, since, until *time.Time) (map[string][]LanguageLines, error) {
+	query := `
+		SELECT l.repo_id, l.commit_hash, l.language, l.insertions, l.deletions
+		FROM commit_languages l
+		WHERE EXISTS (
+			SELECT 1 FROM repo_events e
+			WHERE e.repo_id = l.repo_id AND e.commit_hash = l.commit_hash`
+	var args []any
+	if since != nil {
+		query += " AND e.timestamp >= ?"
+		args = append(args, since.UTC().Format(time.RFC3339))
+	}
+	if until != nil {
+		query += " AND e.timestamp <= ?"
+		args = append(args, until.UTC().Format(time.RFC3339))
+	}
+	query += `)
+		ORDER BY l.repo_id, l.commit_hash, l.language`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	out := make(map[string][]LanguageLines)
+	for rows.Next() {
+		var repoID, commit string
+		var l LanguageLines
+		if err := rows.Scan(&repoID, &commit, &l.Language, &l.Insertions, &l.Deletions); err != nil {
+			return nil, err
+		}
+		key := CommitKey(repoID, commit)
+		out[key] = append(out[key], l)
+	}
+	return out, rows.Err()
+}
+
+// CommitKey identifies a commit across repositories in the maps returned
+// by ListCommitLanguages.
+func CommitKey(repoID, commit string) string {
+	return repoID + "\x00" + commit
+}
+
+// DeleteUnusedCommitLanguages deletes the languages of commits no event
+// refers to any more, as DeleteUnusedCommitMeta does for metadata.
+func DeleteUnusedCommitLanguages(db *sql.DB) (int64, error) {
+	var deleted int64
+	err := retryBusy("delete unused commit languages", func() error {
+		result, err := db.Exec(`
+			DELETE FROM commit_languages
+			WHERE NOT EXISTS (
+				SELECT 1 FROM repo_events e
+				WHERE e.repo_id = commit_languages.repo_id
+					AND e.commit_hash = commit_languages.commit_hash
+			)
+		`)
+		if err != nil {
+			return err
+		}
+		deleted, err = result.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitLanguages(t *testing.T) {
+	db := newTestDB(t)
+	day := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, InsertEvent(db, rollupEvent("aaa", SourcePostCommit, day)))
+	require.NoError(t, InsertEvent(db, rollupEvent("bbb", SourcePostCommit, day.AddDate(0, 0, 1))))
+
+	ok, err := HasCommitLanguages(db, "github.com/user/repo", "aaa")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, SaveCommitLanguages(db, "github.com/user/repo", "aaa", []LanguageLines{
+		{Language: "Go", Insertions: 10, Deletions: 2},
+		{Language: "YAML", Insertions: 1},
+	}))
+	// Saving again replaces what was stored
+	require.NoError(t, SaveCommitLanguages(db, "github.com/user/repo", "bbb", []LanguageLines{{Language: "Markdown", Insertions: 5}}))
+	require.NoError(t, SaveCommitLanguages(db, "github.com/user/repo", "bbb", []LanguageLines{{Language: "Markdown", Insertions: 6}}))
+
+	ok, err = HasCommitLanguages(db, "github.com/user/repo", "aaa")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	all, err := ListCommitLanguages(db, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]LanguageLines{
+		CommitKey("github.com/user/repo", "aaa"): {{Language: "Go", Insertions: 10, Deletions: 2}, {Language: "YAML", Insertions: 1}},
+		CommitKey("github.com/user/repo", "bbb"): {{Language: "Markdown", Insertions: 6}},
+	}, all)
+
+	// Only commits with an event in the range are listed
+	until := day.Add(time.Hour)
+	first, err := ListCommitLanguages(db, &day, &until)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+	require.Contains(t, first, CommitKey("github.com/user/repo", "aaa"))
+
+	_, err = db.Exec(`DELETE FROM repo_events WHERE device = ?`, "laptop")
+	require.NoError(t, err)
+	deleted, err := DeleteUnusedCommitLanguages(db)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), deleted)
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/footprint-tools/cli/internal/git"
+)
+
+// SaveCommitMeta stores the metadata of a commit, replacing any stored
+// before for it.
+func SaveCommitMeta(db *sql.DB, repoID, commit string, meta git.CommitMetadata) error {
+	return retryBusy("save commit metadata", func() error {
+		_, err := db.Exec(`
+			INSERT INTO commit_meta (
+				repo_id, commit_hash, authored_at, parent_commits,
+				author_name, author_email, committer_name, committer_email,
+				subject, body, files_changed, insertions, deletions
+			)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (repo_id, commit_hash) DO UPDATE SET
+				authored_at = excluded.authored_at,
+				parent_commits = excluded.parent_commits,
+				author_name = excluded.author_name,
+				author_email = excluded.author_email,
+				committer_name = excluded.committer_name,
+				committer_email = excluded.committer_email,
+				subject = excluded.subject,
+				body = excluded.body,
+				files_changed = excluded.files_changed,
+				insertions = excluded.insertions,
+				deletions = excluded.deletions
+		`, repoID, commit, meta.AuthoredAt, meta.ParentCommits,
+			meta.AuthorName, meta.AuthorEmail, meta.CommitterName, meta.CommitterEmail,
+			meta.Subject, meta.Body, meta.FilesChanged, meta.Insertions, meta.Deletions)
+		return err
+	})
+}
+
+// GetCommitMeta returns the metadata stored for a commit. ok is false when
+// none is stored, as for commits recorded before fp kept it.
+func GetCommitMeta(db *sql.DB, repoID, commit string) (meta git.CommitMetadata, ok bool, err error) {
+	err = db.QueryRow(`
+		SELECT authored_at, parent_commits, author_name, author_email,
+			committer_name, committer_email, subject, body,
+			files_changed, insertions, deletions
+		FROM commit_meta
+		WHERE repo_id = ? AND commit_hash = ?
+	`, repoID, commit).Scan(
+		&meta.AuthoredAt, &meta.ParentCommits, &meta.AuthorName, &meta.AuthorEmail,
+		&meta.CommitterName, &meta.CommitterEmail, &meta.Subject, &meta.Body,
+		&meta.FilesChanged, &meta.Insertions, &meta.Deletions,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return git.CommitMetadata{}, false, nil
+	}
+	if err != nil {
+		return git.CommitMetadata{}, false, err
+	}
+	return meta, true, nil
+}
+
+// HasCommitMeta reports whether metadata is stored for a commit.
+func HasCommitMeta(db *sql.DB, repoID, commit string) (bool, error) {
+	var n int
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM commit_meta WHERE repo_id = ? AND commit_hash = ?`,
+		repoID, commit,
+	).Scan(&n)
+	return n > 0, err
+}
+
+// DeleteUnusedCommitMeta deletes metadata of commits no event refers to
+// any more, as after pruning or orphan cleanup.
+func DeleteUnusedCommitMeta(db *sql.DB) (int64, error) {
+	var deleted int64
+	err := retryBusy("delete unused commit metadata", func() error {
+		result, err := db.Exec(`
+			DELETE FROM commit_meta
+			WHERE NOT EXISTS (
+				SELECT 1 FROM repo_events e
+				WHERE e.repo_id = commit_meta.repo_id
+					AND e.commit_hash = commit_meta.commit_hash
+			)
+		`)
+		if err != nil {
+			return err
+		}
+		deleted, err = result.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+)
+
+func TestCommitMeta(t *testing.T) {
+	db := newTestDB(t)
+
+	_, ok, err := GetCommitMeta(db, "github.com/user/repo", "abc123")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = HasCommitMeta(db, "github.com/user/repo", "abc123")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	meta := git.CommitMetadata{
+		AuthoredAt:     "2026-03-02T10:00:00+01:00",
+		ParentCommits:  "p1 p2",
+		AuthorName:     "Ada",
+		AuthorEmail:    "ada@example.com",
+		CommitterName:  "Grace",
+		CommitterEmail: "grace@example.com",
+		Subject:        "Fix login redirect",
+		Body:           "The session cookie was dropped.",
+		FilesChanged:   3,
+		Insertions:     10,
+		Deletions:      2,
+	}
+	require.NoError(t, SaveCommitMeta(db, "github.com/user/repo", "abc123", git.CommitMetadata{Subject: "first read"}))
+	require.NoError(t, SaveCommitMeta(db, "github.com/user/repo", "abc123", meta))
+
+	got, ok, err := GetCommitMeta(db, "github.com/user/repo", "abc123")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, meta, got, "saving again replaces the row")
+
+	ok, err = HasCommitMeta(db, "github.com/user/other", "abc123")
+	require.NoError(t, err)
+	require.False(t, ok, "rows are per repository")
+}
+
+func TestDeleteUnusedCommitMeta(t *testing.T) {
+	db := newTestDB(t)
+
+	require.NoError(t, InsertEvent(db, RepoEvent{RepoID: "r", Commit: "kept", Branch: "main", Source: SourcePostCommit}))
+	require.NoError(t, SaveCommitMeta(db, "r", "kept", git.CommitMetadata{Subject: "kept"}))
+	require.NoError(t, SaveCommitMeta(db, "r", "gone", git.CommitMetadata{Subject: "gone"}))
+
+	deleted, err := DeleteUnusedCommitMeta(db)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	ok, err := HasCommitMeta(db, "r", "kept")
+	require.NoError(t, err)
+	require.True(t, ok)
+}
@@ -23,7 +23,14 @@ func Open(path string) (*sql.DB, error) {
 	once.Do(func() {
 		log.Debug("store: opening database at %s", path)
 
-		var err error
+		if err := unlockFor(path); err != nil {
+			singletonMu.Lock()
+			openError = err
+			singletonMu.Unlock()
+			log.Error("store: failed to unlock database: %v", err)
+			return
+		}
+
 		conn, err := sql.Open("sqlite3", path)
 		if err != nil {
 			singletonMu.Lock()
@@ -62,6 +69,8 @@ func Open(path string) (*sql.DB, error) {
 			return
 		}
 
+		EnsureSearchIndex(conn)
+
 		singletonMu.Lock()
 		db = conn
 		singletonMu.Unlock()
@@ -0,0 +1,59 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// SaveDefaultBranch stores the default branch of a repository, as read
+// from git at checkedAt.
+func SaveDefaultBranch(db *sql.DB, repoID, branch string, checkedAt time.Time) error {
+	return retryBusy("save default branch", func() error {
+		_, err := db.Exec(`
+			INSERT INTO repo_default_branch (repo_id, branch, checked_at)
+			VALUES (?, ?, ?)
+			ON CONFLICT (repo_id) DO UPDATE SET
+				branch = excluded.branch,
+				checked_at = excluded.checked_at
+		`, repoID, branch, checkedAt.UTC().Format(time.RFC3339))
+		return err
+	})
+}
+
+// GetDefaultBranch returns the default branch stored for a repository and
+// when it was read. ok is false when none is stored.
+func GetDefaultBranch(db *sql.DB, repoID string) (branch string, checkedAt time.Time, ok bool, err error) {
+	var at string
+	err = db.QueryRow(
+		`SELECT branch, checked_at FROM repo_default_branch WHERE repo_id = ?`, repoID,
+	).Scan(&branch, &at)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", time.Time{}, false, nil
+	}
+	if err != nil {
+		return "", time.Time{}, false, err
+	}
+	checkedAt, _ = time.Parse(time.RFC3339, at)
+	return branch, checkedAt, true, nil
+}
+
+// DefaultBranches returns the stored default branch of every repository,
+// by repo id.
+func DefaultBranches(db *sql.DB) (map[string]string, error) {
+	rows, err := db.Query(`SELECT repo_id, branch FROM repo_default_branch`)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	branches := make(map[string]string)
+	for rows.Next() {
+		var repoID, branch string
+		if err := rows.Scan(&repoID, &branch); err != nil {
+			return nil, err
+		}
+		branches[repoID] = branch
+	}
+	return branches, rows.Err()
+}
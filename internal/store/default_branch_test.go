@@ -0,0 +1,31 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultBranch(t *testing.T) {
+	db := newTestDB(t)
+
+	_, _, ok, err := GetDefaultBranch(db, "github.com/user/repo")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	checked := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, SaveDefaultBranch(db, "github.com/user/repo", "master", checked.Add(-time.Hour)))
+	require.NoError(t, SaveDefaultBranch(db, "github.com/user/repo", "main", checked))
+	require.NoError(t, SaveDefaultBranch(db, "github.com/user/other", "trunk", checked))
+
+	branch, at, ok, err := GetDefaultBranch(db, "github.com/user/repo")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "main", branch)
+	require.True(t, at.Equal(checked))
+
+	all, err := DefaultBranches(db)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"github.com/user/repo": "main", "github.com/user/other": "trunk"}, all)
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// MarkDelivered records that the given events were accepted by an export sink.
+func MarkDelivered(db *sql.DB, ids []int64, sink string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(`INSERT OR IGNORE INTO export_deliveries (event_id, sink) VALUES (?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, id := range ids {
+		if _, err := stmt.Exec(id, sink); err != nil {
+			log.Error("store: mark delivered failed: %v (sink=%s)", err, sink)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// DeliveredTo returns which of the given events a sink has already accepted.
+func DeliveredTo(db *sql.DB, ids []int64, sink string) (map[int64]bool, error) {
+	delivered := make(map[int64]bool)
+	if len(ids) == 0 {
+		return delivered, nil
+	}
+
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, sink)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	query := fmt.Sprintf("SELECT event_id FROM export_deliveries WHERE sink = ? AND event_id IN (%s)", placeholders)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		delivered[id] = true
+	}
+	return delivered, rows.Err()
+}
+
+// ClearDeliveries removes delivery records for the given events.
+func ClearDeliveries(db *sql.DB, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	_, err := db.Exec(fmt.Sprintf("DELETE FROM export_deliveries WHERE event_id IN (%s)", placeholders), args...)
+	return err
+}
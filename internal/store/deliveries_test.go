@@ -0,0 +1,44 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveries(t *testing.T) {
+	db := newTestDB(t)
+
+	for _, commit := range []string{"a", "b"} {
+		require.NoError(t, InsertEvent(db, RepoEvent{
+			RepoID:    "github.com/user/repo",
+			Commit:    commit,
+			Branch:    "main",
+			Timestamp: time.Now(),
+			Status:    StatusPending,
+			Source:    SourcePostCommit,
+		}))
+	}
+	events, err := GetPendingEvents(db)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	ids := []int64{events[0].ID, events[1].ID}
+
+	require.NoError(t, MarkDelivered(db, ids[:1], "git"))
+	// Marking twice is harmless
+	require.NoError(t, MarkDelivered(db, ids[:1], "git"))
+
+	delivered, err := DeliveredTo(db, ids, "git")
+	require.NoError(t, err)
+	require.Equal(t, map[int64]bool{ids[0]: true}, delivered)
+
+	delivered, err = DeliveredTo(db, ids, "http")
+	require.NoError(t, err)
+	require.Empty(t, delivered)
+
+	require.NoError(t, ClearDeliveries(db, ids))
+	delivered, err = DeliveredTo(db, ids, "git")
+	require.NoError(t, err)
+	require.Empty(t, delivered)
+}
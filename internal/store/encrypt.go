@@ -0,0 +1,534 @@
+package store
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/keychain"
+	"github.com/footprint-tools/cli/internal/paths"
+)
+
+// An encrypted store keeps the database sealed in store.db.enc, in place of
+// store.db, and works on an unlocked copy in paths.RuntimeDir. The copy is
+// an ordinary SQLite database, so hooks, the daemon and open views share it
+// with the usual locking, and Seal writes it back after each command.
+//
+// The sealed file is
+//
+//	magic | key source | salt | key nonce | wrapped data key | data nonce | ciphertext
+//
+// The data key is random and encrypts the database with AES-256-GCM. It is
+// wrapped with a key derived from a passphrase, or from a random secret kept
+// in the OS keychain so that unattended hooks can unlock the store.
+const (
+	sealedFileName  = "store.db.enc"
+	unlockedKeyName = "store.key"
+	sealStampName   = "store.seal"
+	sealLockName    = "store.lock"
+
+	// PassphraseEnv holds the passphrase of a passphrase-encrypted store
+	// for processes that can't prompt for it.
+	PassphraseEnv = "FP_DB_PASSPHRASE"
+
+	keySourceKeychain   byte = 1
+	keySourcePassphrase byte = 2
+
+	passphraseIterations = 600000
+	saltSize             = 16
+	dataKeySize          = 32
+
+	sealLockTimeout = 10 * time.Second
+	staleSealLock   = 2 * time.Minute
+)
+
+var sealMagic = []byte("FPSEAL1\x00")
+
+// sealHeaderSize is the length of the header up to the data nonce; it is
+// kept as is when the database is sealed again.
+var sealHeaderSize = len(sealMagic) + 1 + saltSize + 12 + dataKeySize + 16
+
+var (
+	// ErrLocked is returned when the store is encrypted and can't be
+	// unlocked without a passphrase.
+	ErrLocked = errors.New("the database is encrypted and locked; run 'fp unlock' or set " + PassphraseEnv)
+	// ErrAlreadyEncrypted is returned by Encrypt for a store that is.
+	ErrAlreadyEncrypted = errors.New("the database is already encrypted")
+)
+
+// Stubbed in tests.
+var (
+	keychainGet       = keychain.Get
+	keychainSet       = keychain.Set
+	keychainAvailable = keychain.Available
+)
+
+// passphrasePrompt asks for the passphrase when the store is locked; nil
+// when fp isn't running in a terminal.
+var passphrasePrompt func(prompt string) (string, error)
+
+// SetPassphrasePrompt sets how Unlock asks for the passphrase of a locked
+// store. Without one, it only reads FP_DB_PASSPHRASE.
+func SetPassphrasePrompt(prompt func(prompt string) (string, error)) {
+	passphrasePrompt = prompt
+}
+
+// SealedPath returns the encrypted database of the active profile.
+func SealedPath() string {
+	return filepath.Join(paths.ProfileDir(), sealedFileName)
+}
+
+// IsEncrypted reports whether the active profile's store is encrypted.
+func IsEncrypted() bool {
+	_, err := os.Stat(SealedPath())
+	return err == nil
+}
+
+// IsUnlocked reports whether an encrypted store has an unlocked copy.
+func IsUnlocked() bool {
+	_, err := os.Stat(filepath.Join(unlockedDir(), unlockedKeyName))
+	return err == nil
+}
+
+// KeychainAvailable reports whether Encrypt can keep the key in the OS
+// keychain.
+func KeychainAvailable() bool {
+	return keychainAvailable()
+}
+
+// unlockedDir returns the directory holding the active profile's unlocked
+// copy, mirroring the profile layout under paths.RuntimeDir.
+func unlockedDir() string {
+	dir := paths.RuntimeDir()
+	if profile := paths.Profile(); profile != "" {
+		dir = filepath.Join(dir, "profiles", profile)
+	}
+	_ = os.MkdirAll(dir, 0700)
+	return dir
+}
+
+func unlockedDBPath() string {
+	return filepath.Join(unlockedDir(), "store.db")
+}
+
+func plainDBPath() string {
+	return filepath.Join(paths.ProfileDir(), "store.db")
+}
+
+// keychainAccount names the active profile's entry in the OS keychain.
+func keychainAccount() string {
+	if profile := paths.Profile(); profile != "" {
+		return "store:" + profile
+	}
+	return "store"
+}
+
+// unlockFor unlocks the store first when path is the unlocked copy of an
+// encrypted one, so the open functions work on it like on store.db.
+func unlockFor(path string) error {
+	if filepath.Base(path) != "store.db" || !IsEncrypted() || path != unlockedDBPath() {
+		return nil
+	}
+	return Unlock()
+}
+
+// privateUnlockedDir returns unlockedDir after making sure it is a
+// directory only the user can use: in a shared temp directory another user
+// could have created it first.
+func privateUnlockedDir() (string, error) {
+	dir := unlockedDir()
+	for _, d := range []string{paths.RuntimeDir(), dir} {
+		info, err := os.Lstat(d)
+		if err != nil {
+			return "", err
+		}
+		if !info.IsDir() {
+			return "", fmt.Errorf("%s is not a directory", d)
+		}
+		if err := os.Chmod(d, 0700); err != nil {
+			return "", fmt.Errorf("%s is not private: %w", d, err)
+		}
+	}
+	return dir, nil
+}
+
+// Encrypt turns the active profile's store into an encrypted one. With an
+// empty passphrase the key is kept in the OS keychain. The plaintext
+// store.db is removed once the sealed copy is written.
+func Encrypt(passphrase string) error {
+	if IsEncrypted() {
+		return ErrAlreadyEncrypted
+	}
+
+	// Create or migrate the database, so the sealed copy is current
+	s, err := New(plainDBPath())
+	if err != nil {
+		return err
+	}
+	if err := s.Close(); err != nil {
+		return fmt.Errorf("close database: %w", err)
+	}
+
+	source, secret := keySourcePassphrase, passphrase
+	if passphrase == "" {
+		random := make([]byte, 32)
+		_, _ = rand.Read(random)
+		source, secret = keySourceKeychain, base64.StdEncoding.EncodeToString(random)
+		if err := keychainSet(keychainAccount(), secret); err != nil {
+			return err
+		}
+	}
+
+	header := make([]byte, 0, sealHeaderSize)
+	header = append(header, sealMagic...)
+	header = append(header, source)
+	salt := make([]byte, saltSize)
+	_, _ = rand.Read(salt)
+	header = append(header, salt...)
+
+	dataKey := make([]byte, dataKeySize)
+	_, _ = rand.Read(dataKey)
+	wrapped, err := sealBytes(deriveKey(secret, source, salt), dataKey, header)
+	if err != nil {
+		return err
+	}
+	header = append(header, wrapped...)
+
+	dir, err := privateUnlockedDir()
+	if err != nil {
+		return err
+	}
+	err = withSealLock(dir, func() error {
+		removeUnlocked(dir)
+		unlocked := filepath.Join(dir, "store.db")
+		if err := vacuumInto(plainDBPath(), unlocked); err != nil {
+			return err
+		}
+		plain, err := os.ReadFile(unlocked)
+		if err != nil {
+			return err
+		}
+		if err := writeSealed(header, dataKey, plain); err != nil {
+			return err
+		}
+		writeSealStamp(dir, plain)
+		return writePrivateFile(filepath.Join(dir, unlockedKeyName), dataKey)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if err := os.Remove(plainDBPath() + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("could not remove the plaintext database: %w", err)
+		}
+	}
+	return nil
+}
+
+// Unlock decrypts the active profile's store into its unlocked copy, if it
+// is encrypted and not unlocked yet. The key comes from the OS keychain, or
+// for a passphrase from FP_DB_PASSPHRASE or the prompt.
+func Unlock() error {
+	if !IsEncrypted() || IsUnlocked() {
+		return nil
+	}
+	dir, err := privateUnlockedDir()
+	if err != nil {
+		return err
+	}
+	return withSealLock(dir, func() error {
+		if IsUnlocked() {
+			return nil
+		}
+		sealed, err := os.ReadFile(SealedPath())
+		if err != nil {
+			return err
+		}
+		if len(sealed) < sealHeaderSize+12 || !bytes.HasPrefix(sealed, sealMagic) {
+			return fmt.Errorf("%s is not an encrypted fp database", SealedPath())
+		}
+		source := sealed[len(sealMagic)]
+		salt := sealed[len(sealMagic)+1 : len(sealMagic)+1+saltSize]
+		secret, err := unlockSecret(source)
+		if err != nil {
+			return err
+		}
+
+		wrappedAt := len(sealMagic) + 1 + saltSize
+		dataKey, err := openBytes(deriveKey(secret, source, salt), sealed[wrappedAt:sealHeaderSize], sealed[:wrappedAt])
+		if err != nil {
+			if source == keySourcePassphrase {
+				return errors.New("wrong passphrase for the encrypted database")
+			}
+			return errors.New("the key in the OS keychain doesn't open the encrypted database")
+		}
+		plain, err := openBytes(dataKey, sealed[sealHeaderSize:], sealed[:sealHeaderSize])
+		if err != nil {
+			return fmt.Errorf("%s is damaged: %w", SealedPath(), err)
+		}
+
+		// A WAL left by an earlier copy would be replayed onto this one
+		removeUnlocked(dir)
+		if err := writePrivateFile(filepath.Join(dir, "store.db"), plain); err != nil {
+			return err
+		}
+		writeSealStamp(dir, plain)
+		return writePrivateFile(filepath.Join(dir, unlockedKeyName), dataKey)
+	})
+}
+
+// Seal writes the unlocked copy back to the encrypted store, if it changed
+// since it was last sealed. It does nothing for a store that isn't
+// encrypted or unlocked.
+func Seal() error {
+	if !IsEncrypted() {
+		return nil
+	}
+	dir := unlockedDir()
+	dataKey, err := os.ReadFile(filepath.Join(dir, unlockedKeyName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	unlocked := filepath.Join(dir, "store.db")
+	fingerprint := fileFingerprint(unlocked)
+	stampSum, stampFingerprint := readSealStamp(dir)
+	if fingerprint == stampFingerprint {
+		return nil
+	}
+
+	return withSealLock(dir, func() error {
+		snapshot := filepath.Join(dir, "store.db.snapshot")
+		_ = os.Remove(snapshot)
+		defer func() { _ = os.Remove(snapshot) }()
+		if err := vacuumInto(unlocked, snapshot); err != nil {
+			return err
+		}
+		plain, err := os.ReadFile(snapshot)
+		if err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(plain)
+		if hex.EncodeToString(sum[:]) != stampSum {
+			sealed, err := os.ReadFile(SealedPath())
+			if err != nil {
+				return err
+			}
+			if len(sealed) < sealHeaderSize {
+				return fmt.Errorf("%s is not an encrypted fp database", SealedPath())
+			}
+			if err := writeSealed(sealed[:sealHeaderSize], dataKey, plain); err != nil {
+				return err
+			}
+		}
+		// The fingerprint taken before the snapshot, so changes made
+		// while it was taken are sealed next time
+		writeStamp(dir, sum[:], fingerprint)
+		return nil
+	})
+}
+
+// Lock seals the store and removes its unlocked copy, so the next command
+// has to unlock it again.
+func Lock() error {
+	if !IsEncrypted() {
+		return nil
+	}
+	if err := Seal(); err != nil {
+		return err
+	}
+	dir := unlockedDir()
+	return withSealLock(dir, func() error {
+		removeUnlocked(dir)
+		return nil
+	})
+}
+
+// removeUnlocked removes the unlocked copy, the key first so that nothing
+// takes the copy for unlocked while it goes.
+func removeUnlocked(dir string) {
+	for _, name := range []string{unlockedKeyName, sealStampName, "store.db", "store.db-wal", "store.db-shm"} {
+		_ = os.Remove(filepath.Join(dir, name))
+	}
+}
+
+// unlockSecret returns the secret the data key is wrapped with.
+func unlockSecret(source byte) (string, error) {
+	switch source {
+	case keySourceKeychain:
+		secret, err := keychainGet(keychainAccount())
+		if err != nil {
+			return "", fmt.Errorf("could not read the database key: %w", err)
+		}
+		return secret, nil
+	case keySourcePassphrase:
+		if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+			return passphrase, nil
+		}
+		if passphrasePrompt == nil {
+			return "", ErrLocked
+		}
+		passphrase, err := passphrasePrompt("Passphrase for the fp database: ")
+		if err != nil {
+			return "", err
+		}
+		if passphrase == "" {
+			return "", ErrLocked
+		}
+		return passphrase, nil
+	default:
+		return "", fmt.Errorf("%s uses an unknown key source %d", SealedPath(), source)
+	}
+}
+
+// deriveKey turns the secret into the key that wraps the data key. The
+// keychain secret is already random, so only passphrases are stretched.
+func deriveKey(secret string, source byte, salt []byte) []byte {
+	iterations := 1
+	if source == keySourcePassphrase {
+		iterations = passphraseIterations
+	}
+	key, _ := pbkdf2.Key(sha256.New, secret, salt, iterations, 32)
+	return key
+}
+
+// sealBytes encrypts plain with AES-256-GCM, returning the nonce followed
+// by the ciphertext.
+func sealBytes(key, plain, additional []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, _ = rand.Read(nonce)
+	return gcm.Seal(nonce, nonce, plain, additional), nil
+}
+
+func openBytes(key, sealed, additional []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], additional)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// writeSealed encrypts plain under header and replaces the sealed file.
+func writeSealed(header, dataKey, plain []byte) error {
+	ciphertext, err := sealBytes(dataKey, plain, header)
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(SealedPath(), append(append([]byte{}, header...), ciphertext...))
+}
+
+// writePrivateFile writes data through a temp file, so readers never see
+// it half written.
+func writePrivateFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// vacuumInto writes a consistent copy of the database at src to dst; it
+// only reads src, so the hooks can keep writing to it.
+func vacuumInto(src, dst string) error {
+	conn, err := sql.Open("sqlite3", src+"?_busy_timeout=5000")
+	if err != nil {
+		return err
+	}
+	defer CloseDB(conn)
+	if _, err := conn.Exec("VACUUM INTO ?", dst); err != nil {
+		return fmt.Errorf("copy database: %w", err)
+	}
+	return os.Chmod(dst, 0600)
+}
+
+// fileFingerprint identifies the state of the unlocked copy by the size
+// and modification time of the database and its WAL.
+func fileFingerprint(path string) string {
+	var parts []string
+	for _, p := range []string{path, path + "-wal"} {
+		if info, err := os.Stat(p); err == nil {
+			parts = append(parts, strconv.FormatInt(info.Size(), 10)+"@"+strconv.FormatInt(info.ModTime().UnixNano(), 10))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// The seal stamp holds the hash of the last sealed snapshot and the
+// fingerprint of the unlocked copy it was taken from.
+func readSealStamp(dir string) (sum, fingerprint string) {
+	data, err := os.ReadFile(filepath.Join(dir, sealStampName))
+	if err != nil {
+		return "", ""
+	}
+	sum, fingerprint, _ = strings.Cut(strings.TrimSpace(string(data)), " ")
+	return sum, fingerprint
+}
+
+func writeSealStamp(dir string, plain []byte) {
+	sum := sha256.Sum256(plain)
+	writeStamp(dir, sum[:], fileFingerprint(filepath.Join(dir, "store.db")))
+}
+
+func writeStamp(dir string, sum []byte, fingerprint string) {
+	_ = writePrivateFile(filepath.Join(dir, sealStampName), []byte(hex.EncodeToString(sum)+" "+fingerprint+"\n"))
+}
+
+// withSealLock runs fn holding the lock on the unlocked copy, so that
+// processes don't unlock or seal it at the same time.
+func withSealLock(dir string, fn func() error) error {
+	lockPath := filepath.Join(dir, sealLockName)
+	deadline := time.Now().Add(sealLockTimeout)
+	for {
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleSealLock {
+			_ = os.Remove(lockPath)
+		}
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, _ = f.WriteString(strconv.Itoa(os.Getpid()))
+			_ = f.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("the encrypted database is busy: %s is held by another fp", lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	defer func() { _ = os.Remove(lockPath) }()
+	return fn()
+}
@@ -0,0 +1,134 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/domain"
+)
+
+// setupEncryptTest points the profile and runtime directories at temp dirs.
+func setupEncryptTest(t *testing.T) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv(PassphraseEnv, "")
+	prompt := passphrasePrompt
+	t.Cleanup(func() { passphrasePrompt = prompt })
+	passphrasePrompt = nil
+}
+
+func insertTestEvent(t *testing.T, commit string) {
+	t.Helper()
+	s, err := New(DBPath())
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+	require.NoError(t, s.Insert(domain.RepoEvent{
+		RepoID:    "github.com/test/repo",
+		RepoPath:  "/repo",
+		Commit:    commit,
+		Branch:    "main",
+		Timestamp: time.Now().Truncate(time.Second),
+		Status:    domain.StatusPending,
+		Source:    domain.SourcePostCommit,
+	}))
+}
+
+func countTestEvents(t *testing.T) int {
+	t.Helper()
+	s, err := New(DBPath())
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+	var n int
+	require.NoError(t, s.DB().QueryRow("SELECT COUNT(*) FROM repo_events").Scan(&n))
+	return n
+}
+
+func TestEncrypt_Passphrase(t *testing.T) {
+	setupEncryptTest(t)
+	insertTestEvent(t, "aaa")
+
+	require.NoError(t, Encrypt("correct horse"))
+	require.True(t, IsEncrypted())
+	require.True(t, IsUnlocked())
+	require.NoFileExists(t, plainDBPath(), "the plaintext database is removed")
+	require.Equal(t, unlockedDBPath(), DBPath())
+	require.ErrorIs(t, Encrypt("again"), ErrAlreadyEncrypted)
+
+	sealed, err := os.ReadFile(SealedPath())
+	require.NoError(t, err)
+	require.NotContains(t, string(sealed), "github.com/test/repo")
+
+	insertTestEvent(t, "bbb")
+	require.NoError(t, Lock())
+	require.False(t, IsUnlocked())
+	require.NoFileExists(t, unlockedDBPath())
+
+	// Locked, without a passphrase
+	_, err = New(DBPath())
+	require.ErrorIs(t, err, ErrLocked)
+
+	t.Setenv(PassphraseEnv, "wrong")
+	require.ErrorContains(t, Unlock(), "wrong passphrase")
+
+	t.Setenv(PassphraseEnv, "")
+	SetPassphrasePrompt(func(string) (string, error) { return "correct horse", nil })
+	require.Equal(t, 2, countTestEvents(t), "the event added while unlocked was sealed")
+}
+
+func TestSeal_Unchanged(t *testing.T) {
+	setupEncryptTest(t)
+	t.Setenv(PassphraseEnv, "secret")
+	require.NoError(t, Encrypt("secret"))
+
+	before, err := os.ReadFile(SealedPath())
+	require.NoError(t, err)
+	require.NoError(t, Seal())
+	after, err := os.ReadFile(SealedPath())
+	require.NoError(t, err)
+	require.Equal(t, before, after, "nothing changed, nothing to seal")
+
+	insertTestEvent(t, "aaa")
+	require.NoError(t, Seal())
+	after, err = os.ReadFile(SealedPath())
+	require.NoError(t, err)
+	require.NotEqual(t, before, after)
+}
+
+func TestEncrypt_Keychain(t *testing.T) {
+	setupEncryptTest(t)
+	secrets := map[string]string{}
+	get, set := keychainGet, keychainSet
+	t.Cleanup(func() { keychainGet, keychainSet = get, set })
+	keychainSet = func(account, secret string) error { secrets[account] = secret; return nil }
+	keychainGet = func(account string) (string, error) { return secrets[account], nil }
+
+	insertTestEvent(t, "aaa")
+	require.NoError(t, Encrypt(""))
+	require.Contains(t, secrets, "store")
+	require.NoError(t, Lock())
+
+	// Hooks unlock it without asking
+	require.Equal(t, 1, countTestEvents(t))
+
+	require.NoError(t, Lock())
+	secrets["store"] = "not the key"
+	require.ErrorContains(t, Unlock(), "OS keychain")
+}
+
+func TestUnlock_SharedRuntimeDir(t *testing.T) {
+	setupEncryptTest(t)
+	require.NoError(t, Encrypt("secret"))
+	require.NoError(t, Lock())
+
+	// Someone else's symlink in place of the runtime directory
+	runtime := filepath.Join(os.Getenv("XDG_RUNTIME_DIR"), "footprint")
+	require.NoError(t, os.RemoveAll(runtime))
+	require.NoError(t, os.Symlink(t.TempDir(), runtime))
+	t.Setenv(PassphraseEnv, "secret")
+	require.ErrorContains(t, Unlock(), "is not a directory")
+}
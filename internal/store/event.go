@@ -11,4 +11,8 @@ type RepoEvent struct {
 	Timestamp time.Time
 	Status    Status
 	Source    Source
+	Cwd       string // directory the command ran in, relative to RepoPath
+	Note      string // free-text annotation added by the user
+	Tag       string // label given with fp record --tag
+	Device    string // machine the event was recorded on
 }
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// SaveCommitFields stores fields derived from a commit, replacing any
+// stored before under the same names.
+func SaveCommitFields(db *sql.DB, repoID, commit string, fields map[string]string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO commit_fields (repo_id, commit_hash, name, value)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (repo_id, commit_hash, name) DO UPDATE SET value = excluded.value
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare statement: %w", err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for name, value := range fields {
+		if _, err := stmt.Exec(repoID, commit, name, value); err != nil {
+			return fmt.Errorf("save field %s: %w", name, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// HasCommitFields reports whether any fields are stored for a commit.
+func HasCommitFields(db *sql.DB, repoID, commit string) (bool, error) {
+	var n int
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM commit_fields WHERE repo_id = ? AND commit_hash = ?`,
+		repoID, commit,
+	).Scan(&n)
+	return n > 0, err
+}
+
+// GetCommitFields returns the fields stored for a commit, or nil if there
+// are none.
+func GetCommitFields(db *sql.DB, repoID, commit string) (map[string]string, error) {
+	rows, err := db.Query(
+		`SELECT name, value FROM commit_fields WHERE repo_id = ? AND commit_hash = ?`,
+		repoID, commit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var fields map[string]string
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		if fields == nil {
+			fields = make(map[string]string)
+		}
+		fields[name] = value
+	}
+	return fields, rows.Err()
+}
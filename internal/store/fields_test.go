@@ -0,0 +1,35 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitFields(t *testing.T) {
+	db := newTestDB(t)
+
+	ok, err := HasCommitFields(db, "github.com/user/repo", "abc123")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	fields, err := GetCommitFields(db, "github.com/user/repo", "abc123")
+	require.NoError(t, err)
+	require.Nil(t, fields)
+
+	require.NoError(t, SaveCommitFields(db, "github.com/user/repo", "abc123", map[string]string{"ticket.id": "PAY-1", "language.Go": "+3 -1"}))
+	require.NoError(t, SaveCommitFields(db, "github.com/user/repo", "abc123", map[string]string{"ticket.id": "PAY-2"}))
+	require.NoError(t, SaveCommitFields(db, "github.com/user/repo", "def456", nil))
+
+	ok, err = HasCommitFields(db, "github.com/user/repo", "abc123")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	fields, err = GetCommitFields(db, "github.com/user/repo", "abc123")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"ticket.id": "PAY-2", "language.Go": "+3 -1"}, fields)
+
+	ok, err = HasCommitFields(db, "github.com/user/repo", "def456")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -3,6 +3,7 @@ package migrations
 import (
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"sort"
@@ -29,6 +30,11 @@ CREATE TABLE IF NOT EXISTS schema_migrations (
 	applied_at TEXT NOT NULL DEFAULT (datetime('now'))
 )`
 
+// ErrSchemaTooNew is returned when the database was migrated by a newer fp
+// than the one running. Older binaries refuse to touch it rather than
+// writing rows the newer schema doesn't expect.
+var ErrSchemaTooNew = errors.New("database schema is newer than this version of fp")
+
 // Load reads all embedded SQL files and returns them as migrations.
 func Load() ([]Migration, error) {
 	entries, err := sqlFiles.ReadDir("sql")
@@ -114,6 +120,10 @@ func Run(db *sql.DB) error {
 		return err
 	}
 
+	if latest := migrations[len(migrations)-1].Version; current > latest {
+		return fmt.Errorf("%w (database is at version %d, this fp supports up to %d)\nHint: Run 'fp update' to install the latest fp", ErrSchemaTooNew, current, latest)
+	}
+
 	for _, m := range migrations {
 		if m.Version <= current {
 			continue
@@ -2,6 +2,7 @@ package migrations_test
 
 import (
 	"database/sql"
+	"errors"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -138,3 +139,25 @@ func TestRedundantColumnsRemoved(t *testing.T) {
 		}
 	}
 }
+
+func TestRunRefusesDowngrade(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := migrations.Run(db); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	// Simulate a database migrated by a newer fp
+	if _, err := db.Exec("INSERT INTO schema_migrations (version, description) VALUES (999, 'from_the_future')"); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	err = migrations.Run(db)
+	if !errors.Is(err, migrations.ErrSchemaTooNew) {
+		t.Fatalf("expected ErrSchemaTooNew, got %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+-- Per-sink delivery tracking for exports with more than one sink.
+-- An event stays pending until every configured sink has accepted it;
+-- rows are removed once the event is marked exported.
+CREATE TABLE IF NOT EXISTS export_deliveries (
+    event_id INTEGER NOT NULL,
+    sink TEXT NOT NULL,
+    delivered_at TEXT NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY(event_id, sink),
+    FOREIGN KEY(event_id) REFERENCES repo_events(id) ON DELETE CASCADE
+);
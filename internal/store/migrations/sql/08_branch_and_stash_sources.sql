@@ -0,0 +1,5 @@
+-- Add sources recorded by the reference-transaction hook
+INSERT OR IGNORE INTO event_source (id, name) VALUES
+    (7, 'branch-create'),
+    (8, 'branch-delete'),
+    (9, 'stash');
@@ -0,0 +1,2 @@
+-- Directory the command ran in, relative to the repository root ('' for the root)
+ALTER TABLE repo_events ADD COLUMN cwd TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- Free-text note attached to an event from the activity view
+ALTER TABLE repo_events ADD COLUMN note TEXT NOT NULL DEFAULT '';
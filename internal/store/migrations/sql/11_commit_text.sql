@@ -0,0 +1,11 @@
+-- Commit subjects and bodies, stored at record time so activity search
+-- doesn't need git. The full-text index over them is created at runtime
+-- (see store.EnsureSearchIndex), as not every SQLite build has FTS5.
+CREATE TABLE IF NOT EXISTS commit_text (
+    id INTEGER PRIMARY KEY,
+    repo_id TEXT NOT NULL,
+    commit_hash TEXT NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL DEFAULT '',
+    UNIQUE (repo_id, commit_hash)
+);
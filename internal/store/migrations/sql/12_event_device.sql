@@ -0,0 +1,3 @@
+-- Machine an event was recorded on: the device_name config key, or the
+-- hostname. Empty for events recorded before this column existed.
+ALTER TABLE repo_events ADD COLUMN device TEXT NOT NULL DEFAULT '';
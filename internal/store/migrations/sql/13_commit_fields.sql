@@ -0,0 +1,10 @@
+-- Fields derived from a commit by the enrichers fp record runs (see
+-- internal/enrich), named "<enricher>.<field>". Rows are per commit, like
+-- commit_text, so every event for the commit shares them.
+CREATE TABLE IF NOT EXISTS commit_fields (
+    repo_id TEXT NOT NULL,
+    commit_hash TEXT NOT NULL,
+    name TEXT NOT NULL,
+    value TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (repo_id, commit_hash, name)
+);
@@ -0,0 +1,3 @@
+-- Label attached to an event with 'fp record --tag', such as "pairing" or
+-- "client-x", for grouping in reports and exports.
+ALTER TABLE repo_events ADD COLUMN tag TEXT NOT NULL DEFAULT '';
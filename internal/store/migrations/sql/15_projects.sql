@@ -0,0 +1,19 @@
+-- Projects group repositories, such as all the repositories of one client,
+-- so activity and stats can be read per project. Repositories are kept by
+-- repo_id, so every clone of one counts; repo_path is where it was added
+-- from, for display.
+CREATE TABLE IF NOT EXISTS projects (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE TABLE IF NOT EXISTS project_repos (
+    project_id INTEGER NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    repo_id TEXT NOT NULL,
+    repo_path TEXT NOT NULL DEFAULT '',
+    added_at TEXT NOT NULL DEFAULT (datetime('now')),
+    PRIMARY KEY (project_id, repo_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_repos_repo_id ON project_repos(repo_id);
@@ -0,0 +1,18 @@
+-- pre-push runs before the remote answers, so a PRE-PUSH event only says a
+-- push was attempted. push_refs keeps each branch the push tried to update
+-- and how it ended: 'pending' until fp sees the remote-tracking ref reach
+-- local_sha ('pushed') or gives up waiting ('rejected'). Pushes recorded
+-- without rows, such as pushes to a URL, have no known outcome.
+CREATE TABLE IF NOT EXISTS push_refs (
+    event_id INTEGER NOT NULL,
+    remote TEXT NOT NULL,
+    remote_ref TEXT NOT NULL,
+    tracking_ref TEXT NOT NULL,
+    local_sha TEXT NOT NULL,
+    outcome TEXT NOT NULL DEFAULT 'pending',
+    resolved_at TEXT,
+    PRIMARY KEY(event_id, remote_ref),
+    FOREIGN KEY(event_id) REFERENCES repo_events(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_refs_outcome ON push_refs(outcome);
@@ -0,0 +1,21 @@
+-- Commit metadata (author, committer, message, parents and diff stats),
+-- stored when fp record or backfill sees a commit, so activity and export
+-- don't run git for every event they show. Rows are per commit, like
+-- commit_text; commits recorded before this table existed are read from
+-- git as before.
+CREATE TABLE IF NOT EXISTS commit_meta (
+    repo_id TEXT NOT NULL,
+    commit_hash TEXT NOT NULL,
+    authored_at TEXT NOT NULL DEFAULT '',
+    parent_commits TEXT NOT NULL DEFAULT '',
+    author_name TEXT NOT NULL DEFAULT '',
+    author_email TEXT NOT NULL DEFAULT '',
+    committer_name TEXT NOT NULL DEFAULT '',
+    committer_email TEXT NOT NULL DEFAULT '',
+    subject TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL DEFAULT '',
+    files_changed INTEGER NOT NULL DEFAULT 0,
+    insertions INTEGER NOT NULL DEFAULT 0,
+    deletions INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (repo_id, commit_hash)
+);
@@ -0,0 +1,9 @@
+-- The default branch of each repository (what origin/HEAD points to, or
+-- main/master without a remote), so events can be told apart by whether
+-- they happened on it or on a feature branch. checked_at is when fp last
+-- read it from git; it is refreshed about once a day.
+CREATE TABLE IF NOT EXISTS repo_default_branch (
+    repo_id TEXT PRIMARY KEY,
+    branch TEXT NOT NULL,
+    checked_at TEXT NOT NULL
+);
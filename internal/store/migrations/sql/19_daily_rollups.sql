@@ -0,0 +1,94 @@
+-- Event counts and line totals per local day, repository and source, so
+-- the calendar views read a row per active day instead of every event.
+-- Triggers keep them in step with repo_events and commit_meta: every
+-- insert, import, prune or cleanup updates the days it touches. Days are
+-- local to the machine writing the event; fp gc rebuilds them all, as
+-- after a move to another time zone. The triggers check for a row rather
+-- than use INSERT OR IGNORE, which the upsert of fp record would override.
+CREATE TABLE IF NOT EXISTS daily_rollups (
+    day TEXT NOT NULL,
+    repo_id TEXT NOT NULL,
+    source_id INTEGER NOT NULL,
+    repo_path TEXT NOT NULL DEFAULT '',
+    events INTEGER NOT NULL DEFAULT 0,
+    insertions INTEGER NOT NULL DEFAULT 0,
+    deletions INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, repo_id, source_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_daily_rollups_repo ON daily_rollups(repo_id, day);
+
+INSERT INTO daily_rollups (day, repo_id, source_id, repo_path, events, insertions, deletions)
+SELECT date(e.timestamp, 'localtime'), e.repo_id, e.source_id, MAX(COALESCE(e.repo_path, '')),
+    COUNT(*), COALESCE(SUM(m.insertions), 0), COALESCE(SUM(m.deletions), 0)
+FROM repo_events e
+LEFT JOIN commit_meta m ON m.repo_id = e.repo_id AND m.commit_hash = e.commit_hash
+GROUP BY 1, 2, 3;
+
+CREATE TRIGGER IF NOT EXISTS daily_rollups_event_ai AFTER INSERT ON repo_events BEGIN
+    INSERT INTO daily_rollups (day, repo_id, source_id)
+    SELECT date(NEW.timestamp, 'localtime'), NEW.repo_id, NEW.source_id
+    WHERE NOT EXISTS (SELECT 1 FROM daily_rollups WHERE day = date(NEW.timestamp, 'localtime') AND repo_id = NEW.repo_id AND source_id = NEW.source_id);
+    UPDATE daily_rollups SET
+        events = events + 1,
+        repo_path = COALESCE(NULLIF(NEW.repo_path, ''), repo_path),
+        insertions = insertions + COALESCE((SELECT insertions FROM commit_meta WHERE repo_id = NEW.repo_id AND commit_hash = NEW.commit_hash), 0),
+        deletions = deletions + COALESCE((SELECT deletions FROM commit_meta WHERE repo_id = NEW.repo_id AND commit_hash = NEW.commit_hash), 0)
+    WHERE day = date(NEW.timestamp, 'localtime') AND repo_id = NEW.repo_id AND source_id = NEW.source_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS daily_rollups_event_ad AFTER DELETE ON repo_events BEGIN
+    UPDATE daily_rollups SET
+        events = events - 1,
+        insertions = insertions - COALESCE((SELECT insertions FROM commit_meta WHERE repo_id = OLD.repo_id AND commit_hash = OLD.commit_hash), 0),
+        deletions = deletions - COALESCE((SELECT deletions FROM commit_meta WHERE repo_id = OLD.repo_id AND commit_hash = OLD.commit_hash), 0)
+    WHERE day = date(OLD.timestamp, 'localtime') AND repo_id = OLD.repo_id AND source_id = OLD.source_id;
+    DELETE FROM daily_rollups
+    WHERE day = date(OLD.timestamp, 'localtime') AND repo_id = OLD.repo_id AND source_id = OLD.source_id AND events <= 0;
+END;
+
+-- Recording a commit again moves its event to the new timestamp
+CREATE TRIGGER IF NOT EXISTS daily_rollups_event_au AFTER UPDATE OF timestamp, repo_id, source_id, commit_hash ON repo_events BEGIN
+    UPDATE daily_rollups SET
+        events = events - 1,
+        insertions = insertions - COALESCE((SELECT insertions FROM commit_meta WHERE repo_id = OLD.repo_id AND commit_hash = OLD.commit_hash), 0),
+        deletions = deletions - COALESCE((SELECT deletions FROM commit_meta WHERE repo_id = OLD.repo_id AND commit_hash = OLD.commit_hash), 0)
+    WHERE day = date(OLD.timestamp, 'localtime') AND repo_id = OLD.repo_id AND source_id = OLD.source_id;
+    INSERT INTO daily_rollups (day, repo_id, source_id)
+    SELECT date(NEW.timestamp, 'localtime'), NEW.repo_id, NEW.source_id
+    WHERE NOT EXISTS (SELECT 1 FROM daily_rollups WHERE day = date(NEW.timestamp, 'localtime') AND repo_id = NEW.repo_id AND source_id = NEW.source_id);
+    UPDATE daily_rollups SET
+        events = events + 1,
+        repo_path = COALESCE(NULLIF(NEW.repo_path, ''), repo_path),
+        insertions = insertions + COALESCE((SELECT insertions FROM commit_meta WHERE repo_id = NEW.repo_id AND commit_hash = NEW.commit_hash), 0),
+        deletions = deletions + COALESCE((SELECT deletions FROM commit_meta WHERE repo_id = NEW.repo_id AND commit_hash = NEW.commit_hash), 0)
+    WHERE day = date(NEW.timestamp, 'localtime') AND repo_id = NEW.repo_id AND source_id = NEW.source_id;
+    DELETE FROM daily_rollups
+    WHERE day = date(OLD.timestamp, 'localtime') AND repo_id = OLD.repo_id AND source_id = OLD.source_id AND events <= 0;
+END;
+
+-- Metadata usually lands after its events: fp record stores the event
+-- first. Its lines are added to every event of the commit.
+CREATE TRIGGER IF NOT EXISTS daily_rollups_meta_ai AFTER INSERT ON commit_meta BEGIN
+    UPDATE daily_rollups SET
+        insertions = insertions + NEW.insertions * (SELECT COUNT(*) FROM repo_events e WHERE e.repo_id = NEW.repo_id AND e.commit_hash = NEW.commit_hash AND e.source_id = daily_rollups.source_id AND date(e.timestamp, 'localtime') = daily_rollups.day),
+        deletions = deletions + NEW.deletions * (SELECT COUNT(*) FROM repo_events e WHERE e.repo_id = NEW.repo_id AND e.commit_hash = NEW.commit_hash AND e.source_id = daily_rollups.source_id AND date(e.timestamp, 'localtime') = daily_rollups.day)
+    WHERE repo_id = NEW.repo_id
+        AND (day, source_id) IN (SELECT date(timestamp, 'localtime'), source_id FROM repo_events WHERE repo_id = NEW.repo_id AND commit_hash = NEW.commit_hash);
+END;
+
+CREATE TRIGGER IF NOT EXISTS daily_rollups_meta_au AFTER UPDATE OF insertions, deletions ON commit_meta BEGIN
+    UPDATE daily_rollups SET
+        insertions = insertions + (NEW.insertions - OLD.insertions) * (SELECT COUNT(*) FROM repo_events e WHERE e.repo_id = NEW.repo_id AND e.commit_hash = NEW.commit_hash AND e.source_id = daily_rollups.source_id AND date(e.timestamp, 'localtime') = daily_rollups.day),
+        deletions = deletions + (NEW.deletions - OLD.deletions) * (SELECT COUNT(*) FROM repo_events e WHERE e.repo_id = NEW.repo_id AND e.commit_hash = NEW.commit_hash AND e.source_id = daily_rollups.source_id AND date(e.timestamp, 'localtime') = daily_rollups.day)
+    WHERE repo_id = NEW.repo_id
+        AND (day, source_id) IN (SELECT date(timestamp, 'localtime'), source_id FROM repo_events WHERE repo_id = NEW.repo_id AND commit_hash = NEW.commit_hash);
+END;
+
+CREATE TRIGGER IF NOT EXISTS daily_rollups_meta_ad AFTER DELETE ON commit_meta BEGIN
+    UPDATE daily_rollups SET
+        insertions = insertions - OLD.insertions * (SELECT COUNT(*) FROM repo_events e WHERE e.repo_id = OLD.repo_id AND e.commit_hash = OLD.commit_hash AND e.source_id = daily_rollups.source_id AND date(e.timestamp, 'localtime') = daily_rollups.day),
+        deletions = deletions - OLD.deletions * (SELECT COUNT(*) FROM repo_events e WHERE e.repo_id = OLD.repo_id AND e.commit_hash = OLD.commit_hash AND e.source_id = daily_rollups.source_id AND date(e.timestamp, 'localtime') = daily_rollups.day)
+    WHERE repo_id = OLD.repo_id
+        AND (day, source_id) IN (SELECT date(timestamp, 'localtime'), source_id FROM repo_events WHERE repo_id = OLD.repo_id AND commit_hash = OLD.commit_hash);
+END;
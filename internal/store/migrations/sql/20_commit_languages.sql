@@ -0,0 +1,20 @@
+-- Lines a commit added and removed per language, from git's numstat with
+-- files named by extension (see enrich.Language). Stored when fp record or
+-- backfill sees a commit, like commit_meta, so fp stats --by-language
+-- reads them without git. Commits the language enricher already saw are
+-- carried over from its "+<insertions> -<deletions>" fields.
+CREATE TABLE IF NOT EXISTS commit_languages (
+    repo_id TEXT NOT NULL,
+    commit_hash TEXT NOT NULL,
+    language TEXT NOT NULL,
+    insertions INTEGER NOT NULL DEFAULT 0,
+    deletions INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (repo_id, commit_hash, language)
+);
+
+INSERT OR IGNORE INTO commit_languages (repo_id, commit_hash, language, insertions, deletions)
+SELECT repo_id, commit_hash, substr(name, length('language.') + 1),
+    CAST(substr(value, 2, instr(value, ' ') - 2) AS INTEGER),
+    CAST(substr(value, instr(value, ' -') + 2) AS INTEGER)
+FROM commit_fields
+WHERE name LIKE 'language.%' AND value LIKE '+% -%';
@@ -0,0 +1,10 @@
+-- What a push sent, from the lines pre-push reads on stdin: the local ref
+-- and the remote's old commit next to the ones push_refs already had, how
+-- many commits the remote didn't have yet (NULL when fp couldn't count
+-- them) and the remote's URL, without credentials. Tags, deletions and
+-- pushes to a URL are kept too, with the outcome 'unknown': there is no
+-- remote-tracking ref to settle them against.
+ALTER TABLE push_refs ADD COLUMN local_ref TEXT NOT NULL DEFAULT '';
+ALTER TABLE push_refs ADD COLUMN remote_sha TEXT NOT NULL DEFAULT '';
+ALTER TABLE push_refs ADD COLUMN remote_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE push_refs ADD COLUMN commits INTEGER;
@@ -0,0 +1,3 @@
+-- The latest events of one repository, for fp status: the daily rollups
+-- give the repository's last active day, this index finds its events.
+CREATE INDEX IF NOT EXISTS idx_repo_events_repo_timestamp ON repo_events(repo_id, timestamp);
@@ -0,0 +1,3 @@
+-- The release channel the cached latest version came from, so a change of
+-- update_channel doesn't keep offering the other channel's release.
+ALTER TABLE state ADD COLUMN update_channel TEXT;
@@ -6,6 +6,11 @@ import (
 	"github.com/footprint-tools/cli/internal/paths"
 )
 
+// DBPath returns the database of the active config profile: for an
+// encrypted store, its unlocked copy (see Unlock).
 func DBPath() string {
-	return filepath.Join(paths.AppDataDir(), "store.db")
+	if IsEncrypted() {
+		return unlockedDBPath()
+	}
+	return filepath.Join(paths.ProfileDir(), "store.db")
 }
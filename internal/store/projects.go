@@ -0,0 +1,196 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// Project is a named group of repositories, such as a client's.
+type Project struct {
+	Name      string
+	CreatedAt string
+	Repos     []ProjectRepo
+}
+
+// ProjectRepo is a repository in a project. Path is where it was added
+// from and may be empty for repositories added by ID.
+type ProjectRepo struct {
+	RepoID string
+	Path   string
+}
+
+// CreateProject adds an empty project. Names are unique.
+func CreateProject(db *sql.DB, name string) error {
+	if _, err := projectID(db, name); err == nil {
+		return fmt.Errorf("project '%s' already exists", name)
+	}
+	if _, err := db.Exec(`INSERT INTO projects (name) VALUES (?)`, name); err != nil {
+		log.Error("store: create project failed: %v (name=%s)", err, name)
+		return err
+	}
+	return nil
+}
+
+// DeleteProject removes a project and its repository list. Events are
+// not touched.
+func DeleteProject(db *sql.DB, name string) error {
+	id, err := projectID(db, name)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM project_repos WHERE project_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM projects WHERE id = ?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// AddProjectRepo adds a repository to a project. It returns false if the
+// project already had it.
+func AddProjectRepo(db *sql.DB, name, repoID, repoPath string) (bool, error) {
+	id, err := projectID(db, name)
+	if err != nil {
+		return false, err
+	}
+	result, err := db.Exec(`
+		INSERT OR IGNORE INTO project_repos (project_id, repo_id, repo_path)
+		VALUES (?, ?, ?)`, id, repoID, repoPath)
+	if err != nil {
+		log.Error("store: add repo to project failed: %v (project=%s, repo=%s)", err, name, repoID)
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// RemoveProjectRepo removes a repository from a project. It returns false
+// if the project didn't have it.
+func RemoveProjectRepo(db *sql.DB, name, repoID string) (bool, error) {
+	id, err := projectID(db, name)
+	if err != nil {
+		return false, err
+	}
+	result, err := db.Exec(`DELETE FROM project_repos WHERE project_id = ? AND repo_id = ?`, id, repoID)
+	if err != nil {
+		log.Error("store: remove repo from project failed: %v (project=%s, repo=%s)", err, name, repoID)
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// ListProjects returns every project with its repositories, by name.
+func ListProjects(db *sql.DB) ([]Project, error) {
+	rows, err := db.Query(`
+		SELECT p.name, p.created_at, COALESCE(pr.repo_id, ''), COALESCE(pr.repo_path, '')
+		FROM projects p
+		LEFT JOIN project_repos pr ON pr.project_id = p.id
+		ORDER BY p.name, pr.repo_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var projects []Project
+	for rows.Next() {
+		var name, createdAt, repoID, repoPath string
+		if err := rows.Scan(&name, &createdAt, &repoID, &repoPath); err != nil {
+			return nil, err
+		}
+		if len(projects) == 0 || projects[len(projects)-1].Name != name {
+			projects = append(projects, Project{Name: name, CreatedAt: createdAt})
+		}
+		if repoID != "" {
+			p := &projects[len(projects)-1]
+			p.Repos = append(p.Repos, ProjectRepo{RepoID: repoID, Path: repoPath})
+		}
+	}
+	return projects, rows.Err()
+}
+
+// ListProjectNames returns the names of all projects, sorted.
+func ListProjectNames(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`SELECT name FROM projects ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// RepoProjects returns the names of the projects a repository is in,
+// sorted.
+func RepoProjects(db *sql.DB, repoID string) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT p.name FROM projects p
+		JOIN project_repos pr ON pr.project_id = p.id
+		WHERE pr.repo_id = ?
+		ORDER BY p.name`, repoID)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// ProjectExists reports whether a project with the given name exists.
+func ProjectExists(db *sql.DB, name string) (bool, error) {
+	_, err := projectID(db, name)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, errProjectNotFound) {
+		return false, nil
+	}
+	return false, err
+}
+
+// errProjectNotFound wraps the error projectID returns for unknown names.
+var errProjectNotFound = errors.New("project not found")
+
+func projectID(db *sql.DB, name string) (int64, error) {
+	var id int64
+	err := db.QueryRow(`SELECT id FROM projects WHERE name = ?`, name).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w: '%s'", errProjectNotFound, name)
+	}
+	return id, err
+}
+
+// projectClause matches events in the repositories of the named project.
+func projectClause(name string) (string, []any) {
+	return `repo_id IN (
+		SELECT pr.repo_id FROM project_repos pr
+		JOIN projects p ON p.id = pr.project_id
+		WHERE p.name = ?)`, []any{name}
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjects(t *testing.T) {
+	db := newTestDB(t)
+
+	require.NoError(t, CreateProject(db, "acme"))
+	require.Error(t, CreateProject(db, "acme"), "names are unique")
+	require.NoError(t, CreateProject(db, "empty"))
+
+	added, err := AddProjectRepo(db, "acme", "github.com/acme/api", "/src/api")
+	require.NoError(t, err)
+	require.True(t, added)
+	added, err = AddProjectRepo(db, "acme", "github.com/acme/api", "/src/api-clone")
+	require.NoError(t, err)
+	require.False(t, added, "same repo_id twice")
+	_, err = AddProjectRepo(db, "acme", "github.com/acme/web", "")
+	require.NoError(t, err)
+
+	_, err = AddProjectRepo(db, "missing", "github.com/acme/api", "")
+	require.ErrorIs(t, err, errProjectNotFound)
+
+	projects, err := ListProjects(db)
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+	require.Equal(t, "acme", projects[0].Name)
+	require.Equal(t, []ProjectRepo{
+		{RepoID: "github.com/acme/api", Path: "/src/api"},
+		{RepoID: "github.com/acme/web"},
+	}, projects[0].Repos)
+	require.Equal(t, "empty", projects[1].Name)
+	require.Empty(t, projects[1].Repos)
+
+	_, err = AddProjectRepo(db, "empty", "github.com/acme/api", "")
+	require.NoError(t, err)
+	names, err := RepoProjects(db, "github.com/acme/api")
+	require.NoError(t, err)
+	require.Equal(t, []string{"acme", "empty"}, names)
+	names, err = RepoProjects(db, "github.com/other/repo")
+	require.NoError(t, err)
+	require.Empty(t, names)
+	_, err = RemoveProjectRepo(db, "empty", "github.com/acme/api")
+	require.NoError(t, err)
+
+	removed, err := RemoveProjectRepo(db, "acme", "github.com/acme/web")
+	require.NoError(t, err)
+	require.True(t, removed)
+	removed, err = RemoveProjectRepo(db, "acme", "github.com/acme/web")
+	require.NoError(t, err)
+	require.False(t, removed)
+
+	require.NoError(t, DeleteProject(db, "acme"))
+	ok, err := ProjectExists(db, "acme")
+	require.NoError(t, err)
+	require.False(t, ok)
+	var left int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM project_repos`).Scan(&left))
+	require.Zero(t, left)
+}
+
+func TestListEvents_ProjectFilter(t *testing.T) {
+	db := newTestDB(t)
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	for i, repoID := range []string{"github.com/acme/api", "github.com/acme/web", "github.com/me/dotfiles"} {
+		require.NoError(t, InsertEvent(db, RepoEvent{
+			RepoID: repoID, Commit: repoID, Timestamp: now.Add(time.Duration(i) * time.Minute),
+			Status: StatusPending, Source: SourcePostCommit,
+		}))
+	}
+	require.NoError(t, CreateProject(db, "acme"))
+	_, err := AddProjectRepo(db, "acme", "github.com/acme/api", "")
+	require.NoError(t, err)
+	_, err = AddProjectRepo(db, "acme", "github.com/acme/web", "")
+	require.NoError(t, err)
+
+	project := "acme"
+	events, err := ListEvents(db, EventFilter{Project: &project})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	for _, e := range events {
+		require.Contains(t, []string{"github.com/acme/api", "github.com/acme/web"}, e.RepoID)
+	}
+
+	events, err = ListEventsSinceFiltered(db, 0, EventFilter{Project: &project})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	unknown := "other"
+	events, err = ListEvents(db, EventFilter{Project: &unknown})
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
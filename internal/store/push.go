@@ -0,0 +1,217 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// Outcomes of a push, from the remote's side. Refs without a
+// remote-tracking ref to check, such as tags, deletions and pushes to a
+// URL, are unknown from the start.
+const (
+	PushPending  = "pending"
+	PushPushed   = "pushed"
+	PushRejected = "rejected"
+	PushUnknown  = "unknown"
+)
+
+// PushRef is one ref a push tried to update.
+type PushRef struct {
+	EventID     int64
+	RepoPath    string    // of the event; filled in by PendingPushRefs
+	Timestamp   time.Time // of the event; filled in by PendingPushRefs
+	Remote      string    // the remote's name, or its URL for a push to a URL
+	RemoteURL   string    // without credentials
+	LocalRef    string    // "(delete)" for a deletion
+	RemoteRef   string    // refs/heads/<branch> on the remote
+	TrackingRef string    // refs/remotes/<remote>/<branch> here, "" when there is none
+	LocalSHA    string    // all zeros for a deletion
+	RemoteSHA   string    // all zeros when the remote didn't have the ref
+	Commits     int       // commits the remote didn't have, -1 when not counted
+	Outcome     string
+}
+
+// Deleted reports whether the push deleted the ref on the remote.
+func (r PushRef) Deleted() bool {
+	return strings.Trim(r.LocalSHA, "0") == "" && r.LocalSHA != ""
+}
+
+// SavePushRefs stores the refs of the push recorded as e, which must
+// already be inserted. Recording the same push again, as when a rejected
+// push is retried, starts its outcome over.
+func SavePushRefs(db *sql.DB, e RepoEvent, refs []PushRef) error {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	return retryBusy("save push refs", func() error {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		var eventID int64
+		err = tx.QueryRow(`SELECT id FROM repo_events WHERE repo_id = ? AND commit_hash = ? AND source_id = ?`,
+			e.RepoID, e.Commit, int(e.Source)).Scan(&eventID)
+		if err != nil {
+			return fmt.Errorf("find push event: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM push_refs WHERE event_id = ?`, eventID); err != nil {
+			return err
+		}
+		for _, r := range refs {
+			outcome := PushPending
+			if r.TrackingRef == "" {
+				outcome = PushUnknown
+			}
+			var commits any
+			if r.Commits >= 0 {
+				commits = r.Commits
+			}
+			if _, err := tx.Exec(`
+				INSERT INTO push_refs (event_id, remote, remote_url, local_ref, remote_ref, tracking_ref, local_sha, remote_sha, commits, outcome)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				eventID, r.Remote, r.RemoteURL, r.LocalRef, r.RemoteRef, r.TrackingRef, r.LocalSHA, r.RemoteSHA, commits, outcome); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+}
+
+// PendingPushRefs returns the branches of pushes whose outcome isn't known
+// yet, oldest first, for the repository at repoPath or for all of them
+// when repoPath is "".
+func PendingPushRefs(db *sql.DB, repoPath string) ([]PushRef, error) {
+	rows, err := db.Query(`
+		SELECT p.event_id, e.repo_path, e.timestamp, p.remote, p.remote_ref, p.tracking_ref, p.local_sha
+		FROM push_refs p
+		JOIN repo_events e ON e.id = p.event_id
+		WHERE p.outcome = ? AND (? = '' OR e.repo_path = ?)
+		ORDER BY e.timestamp, p.event_id`,
+		PushPending, repoPath, repoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var refs []PushRef
+	for rows.Next() {
+		r := PushRef{Outcome: PushPending}
+		var ts string
+		if err := rows.Scan(&r.EventID, &r.RepoPath, &ts, &r.Remote, &r.RemoteRef, &r.TrackingRef, &r.LocalSHA); err != nil {
+			return nil, err
+		}
+		if r.Timestamp, err = time.Parse(time.RFC3339, ts); err != nil {
+			return nil, err
+		}
+		refs = append(refs, r)
+	}
+	return refs, rows.Err()
+}
+
+// ListPushRefs returns the refs of each of the given push events, in the
+// order git listed them. Events without push refs are left out.
+func ListPushRefs(db *sql.DB, ids []int64) (map[int64][]PushRef, error) {
+	refs := make(map[int64][]PushRef)
+	if len(ids) == 0 {
+		return refs, nil
+	}
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT event_id, remote, remote_url, local_ref, remote_ref, tracking_ref, local_sha, remote_sha, commits, outcome
+		FROM push_refs
+		WHERE event_id IN (%s)
+		ORDER BY event_id, rowid`, placeholders), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var r PushRef
+		var commits sql.NullInt64
+		if err := rows.Scan(&r.EventID, &r.Remote, &r.RemoteURL, &r.LocalRef, &r.RemoteRef, &r.TrackingRef,
+			&r.LocalSHA, &r.RemoteSHA, &commits, &r.Outcome); err != nil {
+			return nil, err
+		}
+		r.Commits = -1
+		if commits.Valid {
+			r.Commits = int(commits.Int64)
+		}
+		refs[r.EventID] = append(refs[r.EventID], r)
+	}
+	return refs, rows.Err()
+}
+
+// SetPushOutcome records how the push of one branch ended.
+func SetPushOutcome(db *sql.DB, eventID int64, remoteRef, outcome string) error {
+	err := retryBusy("set push outcome", func() error {
+		_, err := db.Exec(`
+			UPDATE push_refs SET outcome = ?, resolved_at = datetime('now')
+			WHERE event_id = ? AND remote_ref = ?`,
+			outcome, eventID, remoteRef)
+		return err
+	})
+	if err != nil {
+		log.Error("store: set push outcome failed: %v (event=%d, ref=%s)", err, eventID, remoteRef)
+	}
+	return err
+}
+
+// PushOutcomes returns the outcome of each of the given push events that
+// has one: rejected if any branch was rejected, pushed once every branch
+// was, pending otherwise. Refs with an unknown outcome don't count, and
+// events without any others are left out.
+func PushOutcomes(db *sql.DB, ids []int64) (map[int64]string, error) {
+	outcomes := make(map[int64]string)
+	if len(ids) == 0 {
+		return outcomes, nil
+	}
+
+	args := make([]any, 0, len(ids)+3)
+	args = append(args, PushRejected, PushPushed)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	args = append(args, PushUnknown)
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	query := fmt.Sprintf(`
+		SELECT event_id, SUM(outcome = ?), SUM(outcome = ?), COUNT(*)
+		FROM push_refs
+		WHERE event_id IN (%s) AND outcome != ?
+		GROUP BY event_id`, placeholders)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var id int64
+		var rejected, pushed, total int
+		if err := rows.Scan(&id, &rejected, &pushed, &total); err != nil {
+			return nil, err
+		}
+		switch {
+		case rejected > 0:
+			outcomes[id] = PushRejected
+		case pushed == total:
+			outcomes[id] = PushPushed
+		default:
+			outcomes[id] = PushPending
+		}
+	}
+	return outcomes, rows.Err()
+}
@@ -0,0 +1,130 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushRefs(t *testing.T) {
+	db := newTestDB(t)
+
+	ts := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	push := func(commit string) RepoEvent {
+		e := RepoEvent{
+			RepoID:    "github.com/user/repo",
+			RepoPath:  "/src/repo",
+			Commit:    commit,
+			Branch:    "main",
+			Timestamp: ts,
+			Status:    StatusPending,
+			Source:    SourcePrePush,
+		}
+		require.NoError(t, InsertEvent(db, e))
+		return e
+	}
+
+	one := push("aaa")
+	require.NoError(t, SavePushRefs(db, one, []PushRef{
+		{Remote: "origin", RemoteRef: "refs/heads/main", TrackingRef: "refs/remotes/origin/main", LocalSHA: "aaa"},
+		{Remote: "origin", RemoteRef: "refs/heads/topic", TrackingRef: "refs/remotes/origin/topic", LocalSHA: "bbb"},
+	}))
+	two := push("ccc")
+	require.NoError(t, SavePushRefs(db, two, []PushRef{
+		{Remote: "origin", RemoteRef: "refs/heads/main", TrackingRef: "refs/remotes/origin/main", LocalSHA: "ccc"},
+	}))
+	push("ddd") // a push with no known refs
+
+	pending, err := PendingPushRefs(db, "/src/repo")
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	require.Equal(t, "/src/repo", pending[0].RepoPath)
+	require.True(t, ts.Equal(pending[0].Timestamp))
+	pending, err = PendingPushRefs(db, "/src/other")
+	require.NoError(t, err)
+	require.Empty(t, pending)
+
+	events, err := GetPendingEvents(db)
+	require.NoError(t, err)
+	ids := make(map[string]int64)
+	var all []int64
+	for _, e := range events {
+		ids[e.Commit] = e.ID
+		all = append(all, e.ID)
+	}
+
+	require.NoError(t, SetPushOutcome(db, ids["aaa"], "refs/heads/main", PushPushed))
+	require.NoError(t, SetPushOutcome(db, ids["ccc"], "refs/heads/main", PushRejected))
+	outcomes, err := PushOutcomes(db, all)
+	require.NoError(t, err)
+	require.Equal(t, map[int64]string{ids["aaa"]: PushPending, ids["ccc"]: PushRejected}, outcomes)
+
+	require.NoError(t, SetPushOutcome(db, ids["aaa"], "refs/heads/topic", PushPushed))
+	outcomes, err = PushOutcomes(db, all)
+	require.NoError(t, err)
+	require.Equal(t, PushPushed, outcomes[ids["aaa"]])
+
+	// Retrying a rejected push starts its outcome over
+	require.NoError(t, SavePushRefs(db, two, []PushRef{
+		{Remote: "origin", RemoteRef: "refs/heads/main", TrackingRef: "refs/remotes/origin/main", LocalSHA: "ccc"},
+	}))
+	pending, err = PendingPushRefs(db, "")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, ids["ccc"], pending[0].EventID)
+
+	require.Error(t, SavePushRefs(db, RepoEvent{RepoID: "x", Commit: "y", Source: SourcePrePush}, []PushRef{{RemoteRef: "refs/heads/main"}}),
+		"push refs need their event")
+}
+
+func TestListPushRefs(t *testing.T) {
+	db := newTestDB(t)
+
+	e := RepoEvent{
+		RepoID:    "github.com/user/repo",
+		RepoPath:  "/src/repo",
+		Commit:    "aaa",
+		Branch:    "main",
+		Timestamp: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		Status:    StatusPending,
+		Source:    SourcePrePush,
+	}
+	require.NoError(t, InsertEvent(db, e))
+	zero := "0000000000000000000000000000000000000000"
+	require.NoError(t, SavePushRefs(db, e, []PushRef{
+		{Remote: "origin", RemoteURL: "git@github.com:user/repo.git", LocalRef: "refs/heads/main", RemoteRef: "refs/heads/main",
+			TrackingRef: "refs/remotes/origin/main", LocalSHA: "aaa", RemoteSHA: "999", Commits: 3},
+		{Remote: "origin", RemoteURL: "git@github.com:user/repo.git", LocalRef: "refs/tags/v1", RemoteRef: "refs/tags/v1",
+			LocalSHA: "aaa", RemoteSHA: zero, Commits: -1},
+		{Remote: "origin", RemoteURL: "git@github.com:user/repo.git", LocalRef: "(delete)", RemoteRef: "refs/heads/old",
+			LocalSHA: zero, RemoteSHA: "888", Commits: 0},
+	}))
+
+	events, err := GetPendingEvents(db)
+	require.NoError(t, err)
+	id := events[0].ID
+
+	refs, err := ListPushRefs(db, []int64{id, id + 1})
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+	require.Len(t, refs[id], 3)
+	require.Equal(t, "refs/heads/main", refs[id][0].RemoteRef)
+	require.Equal(t, 3, refs[id][0].Commits)
+	require.Equal(t, PushPending, refs[id][0].Outcome)
+	require.Equal(t, "git@github.com:user/repo.git", refs[id][0].RemoteURL)
+	require.Equal(t, -1, refs[id][1].Commits)
+	require.Equal(t, PushUnknown, refs[id][1].Outcome)
+	require.True(t, refs[id][2].Deleted())
+	require.False(t, refs[id][0].Deleted())
+
+	// Only the branch with a remote-tracking ref waits for an outcome
+	pending, err := PendingPushRefs(db, "")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	require.NoError(t, SetPushOutcome(db, id, "refs/heads/main", PushPushed))
+	outcomes, err := PushOutcomes(db, []int64{id})
+	require.NoError(t, err)
+	require.Equal(t, PushPushed, outcomes[id], "refs with unknown outcomes don't hold a push back")
+}
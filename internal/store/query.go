@@ -0,0 +1,97 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+)
+
+// queryViews are created for ad-hoc queries (fp query) so users don't need
+// to know the status and source lookup tables.
+var queryViews = []string{
+	`CREATE TEMP VIEW events AS
+	 SELECT e.id, e.repo_id, e.repo_path, e.commit_hash, e.branch, e.timestamp,
+	        st.name AS status, src.name AS source, e.cwd, e.note, e.device, e.tag
+	 FROM repo_events e
+	 JOIN event_status st ON st.id = e.status_id
+	 JOIN event_source src ON src.id = e.source_id`,
+	`CREATE TEMP VIEW repos AS
+	 SELECT repo_id, repo_path, added_at, last_seen FROM tracked_repos`,
+}
+
+// QueryResult holds the rows returned by an ad-hoc query.
+type QueryResult struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// OpenReadOnly opens the database for ad-hoc queries. The connection is
+// opened read-only and with query_only set, so nothing run on it can change
+// the store or hold a write lock that blocks the hooks. Migrations are not
+// run; the database must already exist.
+func OpenReadOnly(path string) (*sql.DB, error) {
+	if err := unlockFor(path); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	return openReadOnlyConn(path, queryViews...)
+}
+
+// openReadOnlyConn opens path read-only, runs setup on the connection and
+// then sets query_only. The pool is limited to one connection because temp
+// views and query_only are per connection.
+func openReadOnlyConn(path string, setup ...string) (*sql.DB, error) {
+	dsn := (&url.URL{Scheme: "file", Path: path, RawQuery: "mode=ro&_busy_timeout=5000"}).String()
+	conn, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetMaxOpenConns(1)
+
+	setup = append(append([]string{}, setup...), "PRAGMA query_only = ON")
+	for _, stmt := range setup {
+		if _, err := conn.Exec(stmt); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("could not prepare read-only database: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// Query runs an ad-hoc SQL statement and returns all of its rows.
+// Text values are returned as strings rather than byte slices.
+func Query(db *sql.DB, query string, args ...any) (QueryResult, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return QueryResult{}, err
+	}
+	defer closeRows(rows)
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return QueryResult{}, err
+	}
+
+	result := QueryResult{Columns: columns}
+	for rows.Next() {
+		values := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return QueryResult{}, err
+		}
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result, rows.Err()
+}
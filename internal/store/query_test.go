@@ -0,0 +1,38 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenReadOnly_QueriesEventsView(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.db")
+	s, err := New(path)
+	require.NoError(t, err)
+	require.NoError(t, InsertEvent(s.DB(), RepoEvent{
+		RepoID: "github.com/user/repo", Commit: "abc", Branch: "main",
+		Timestamp: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+		Status:    StatusPending, Source: SourcePostCommit,
+	}))
+	require.NoError(t, s.Close())
+
+	db, err := OpenReadOnly(path)
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	result, err := Query(db, "SELECT repo_id, commit_hash, status, source FROM events")
+	require.NoError(t, err)
+	require.Equal(t, []string{"repo_id", "commit_hash", "status", "source"}, result.Columns)
+	require.Equal(t, [][]any{{"github.com/user/repo", "abc", "pending", "post-commit"}}, result.Rows)
+
+	_, err = Query(db, "DELETE FROM repo_events")
+	require.Error(t, err, "writes must be rejected")
+}
+
+func TestOpenReadOnly_MissingDatabase(t *testing.T) {
+	_, err := OpenReadOnly(filepath.Join(t.TempDir(), "missing.db"))
+	require.Error(t, err)
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"database/sql"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -10,12 +11,31 @@ import (
 )
 
 type EventFilter struct {
-	Status *Status
-	Source *Source
-	Since  *time.Time
-	Until  *time.Time
-	RepoID *string
-	Limit  int
+	Status  *Status
+	Source  *Source
+	Since   *time.Time
+	Until   *time.Time
+	RepoID  *string
+	Path    *string // cwd at or below this directory, relative to the repository root
+	Search  *string // words in the commit subject, body or note
+	Device  *string
+	Project *string      // repositories of the named project
+	Before  *EventCursor // only events after this one in ListEvents order
+	Limit   int
+}
+
+// EventCursor is a position in the newest-first order of ListEvents. As
+// EventFilter.Before it continues a listing where the last page ended, so
+// a large store can be read a page at a time without rescanning the rows
+// already read.
+type EventCursor struct {
+	Timestamp time.Time
+	ID        int64
+}
+
+// CursorAfter returns the cursor that continues a listing after e.
+func CursorAfter(e RepoEvent) *EventCursor {
+	return &EventCursor{Timestamp: e.Timestamp, ID: e.ID}
 }
 
 // scanRepoEvent scans a single row into a RepoEvent.
@@ -36,6 +56,10 @@ func scanRepoEvent(rows *sql.Rows) (RepoEvent, error) {
 		&ts,
 		&statusID,
 		&sourceID,
+		&e.Cwd,
+		&e.Note,
+		&e.Device,
+		&e.Tag,
 	); err != nil {
 		return RepoEvent{}, err
 	}
@@ -63,7 +87,11 @@ func ListEvents(db *sql.DB, filter EventFilter) ([]RepoEvent, error) {
 			branch,
 			timestamp,
 			status_id,
-			source_id
+			source_id,
+			cwd,
+			note,
+			device,
+			tag
 		FROM repo_events
 	`
 
@@ -97,6 +125,35 @@ func ListEvents(db *sql.DB, filter EventFilter) ([]RepoEvent, error) {
 		filterArgs = append(filterArgs, *filter.RepoID)
 	}
 
+	if filter.Device != nil {
+		filterClauses = append(filterClauses, "device = ?")
+		filterArgs = append(filterArgs, *filter.Device)
+	}
+
+	if filter.Path != nil {
+		clause, args := pathClause(*filter.Path)
+		filterClauses = append(filterClauses, clause)
+		filterArgs = append(filterArgs, args...)
+	}
+
+	if filter.Project != nil {
+		clause, args := projectClause(*filter.Project)
+		filterClauses = append(filterClauses, clause)
+		filterArgs = append(filterArgs, args...)
+	}
+
+	if filter.Search != nil {
+		clause, args := searchClause(*filter.Search, searchIndexReady(db))
+		filterClauses = append(filterClauses, clause)
+		filterArgs = append(filterArgs, args...)
+	}
+
+	if filter.Before != nil {
+		ts := filter.Before.Timestamp.Format(time.RFC3339)
+		filterClauses = append(filterClauses, "(timestamp < ? OR (timestamp = ? AND id < ?))")
+		filterArgs = append(filterArgs, ts, ts, filter.Before.ID)
+	}
+
 	var queryBuilder strings.Builder
 	queryBuilder.WriteString(base)
 
@@ -105,7 +162,9 @@ func ListEvents(db *sql.DB, filter EventFilter) ([]RepoEvent, error) {
 		queryBuilder.WriteString(strings.Join(filterClauses, " AND "))
 	}
 
-	queryBuilder.WriteString(" ORDER BY timestamp DESC")
+	// id breaks ties between events in the same second, so pages
+	// continued with Before neither skip nor repeat any of them
+	queryBuilder.WriteString(" ORDER BY timestamp DESC, id DESC")
 
 	if filter.Limit > 0 {
 		queryBuilder.WriteString(" LIMIT ?")
@@ -133,6 +192,61 @@ func ListEvents(db *sql.DB, filter EventFilter) ([]RepoEvent, error) {
 	return out, rows.Err()
 }
 
+// EventCounts is how many events there are in total and per source,
+// repository path and device.
+type EventCounts struct {
+	Total    int
+	BySource map[Source]int
+	ByRepo   map[string]int // by repo_path
+	ByDevice map[string]int
+}
+
+// CountEvents counts every event in the store, for summaries that stay
+// right while events are read a page at a time.
+func CountEvents(db *sql.DB) (EventCounts, error) {
+	rows, err := db.Query(`
+		SELECT source_id, repo_path, device, COUNT(*)
+		FROM repo_events
+		GROUP BY source_id, repo_path, device
+	`)
+	if err != nil {
+		return EventCounts{}, err
+	}
+	defer closeRows(rows)
+
+	counts := EventCounts{
+		BySource: make(map[Source]int),
+		ByRepo:   make(map[string]int),
+		ByDevice: make(map[string]int),
+	}
+	for rows.Next() {
+		var (
+			sourceID int
+			repoPath string
+			device   string
+			n        int
+		)
+		if err := rows.Scan(&sourceID, &repoPath, &device, &n); err != nil {
+			return EventCounts{}, err
+		}
+		counts.Total += n
+		counts.BySource[Source(sourceID)] += n
+		counts.ByRepo[repoPath] += n
+		counts.ByDevice[device] += n
+	}
+	return counts, rows.Err()
+}
+
+// pathClause matches events whose cwd is dir or a directory below it.
+func pathClause(dir string) (string, []any) {
+	dir = strings.Trim(filepath.ToSlash(filepath.Clean(dir)), "/")
+	if dir == "." || dir == "" {
+		return "1 = 1", nil
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(dir)
+	return `(cwd = ? OR cwd LIKE ? ESCAPE '\')`, []any{dir, escaped + "/%"}
+}
+
 // GetMaxEventID returns the highest event ID in the database.
 // Returns 0 if no events exist.
 func GetMaxEventID(db *sql.DB) (int64, error) {
@@ -147,6 +261,50 @@ func GetMaxEventID(db *sql.DB) (int64, error) {
 	return maxID.Int64, nil
 }
 
+// ListRepoIDs returns the ids of tracked repositories and of every
+// repository with recorded events, sorted.
+func ListRepoIDs(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT repo_id FROM tracked_repos WHERE repo_id != ''
+		UNION
+		SELECT repo_id FROM repo_events
+		ORDER BY repo_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ListDevices returns the machines events were recorded on, sorted.
+func ListDevices(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`SELECT DISTINCT device FROM repo_events WHERE device != '' ORDER BY device`)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var devices []string
+	for rows.Next() {
+		var device string
+		if err := rows.Scan(&device); err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	return devices, rows.Err()
+}
+
 // ListEventsSince returns events with ID greater than afterID, ordered by ID ascending.
 // Used for polling new events in real-time.
 func ListEventsSince(db *sql.DB, afterID int64) ([]RepoEvent, error) {
@@ -176,6 +334,29 @@ func ListEventsSinceFiltered(db *sql.DB, afterID int64, filter EventFilter) ([]R
 		filterArgs = append(filterArgs, *filter.RepoID)
 	}
 
+	if filter.Device != nil {
+		filterClauses = append(filterClauses, "device = ?")
+		filterArgs = append(filterArgs, *filter.Device)
+	}
+
+	if filter.Path != nil {
+		clause, args := pathClause(*filter.Path)
+		filterClauses = append(filterClauses, clause)
+		filterArgs = append(filterArgs, args...)
+	}
+
+	if filter.Project != nil {
+		clause, args := projectClause(*filter.Project)
+		filterClauses = append(filterClauses, clause)
+		filterArgs = append(filterArgs, args...)
+	}
+
+	if filter.Search != nil {
+		clause, args := searchClause(*filter.Search, searchIndexReady(db))
+		filterClauses = append(filterClauses, clause)
+		filterArgs = append(filterArgs, args...)
+	}
+
 	query := fmt.Sprintf(`
 		SELECT
 			id,
@@ -185,7 +366,11 @@ func ListEventsSinceFiltered(db *sql.DB, afterID int64, filter EventFilter) ([]R
 			branch,
 			timestamp,
 			status_id,
-			source_id
+			source_id,
+			cwd,
+			note,
+			device,
+			tag
 		FROM repo_events
 		WHERE %s
 		ORDER BY id ASC
@@ -198,6 +198,45 @@ func TestListEvents_FilterByRepoID(t *testing.T) {
 	}
 }
 
+func TestListEvents_FilterByPath(t *testing.T) {
+	db := newTestDB(t)
+
+	base := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	for i, cwd := range []string{"", "services/api", "services/api/handlers", "services/api_v2", "web"} {
+		require.NoError(t, InsertEvent(db, RepoEvent{
+			RepoID:    "github.com/user/mono",
+			RepoPath:  "/path/to/mono",
+			Commit:    "c" + string(rune('0'+i)),
+			Timestamp: base.Add(time.Duration(i) * time.Hour),
+			Status:    StatusPending,
+			Source:    SourcePostCommit,
+			Cwd:       cwd,
+		}))
+	}
+
+	cwds := func(dir string) []string {
+		got, err := ListEvents(db, EventFilter{Path: &dir})
+		require.NoError(t, err)
+		var out []string
+		for _, e := range got {
+			out = append(out, e.Cwd)
+		}
+		return out
+	}
+
+	// A directory matches itself and below, but not siblings sharing a prefix
+	require.Equal(t, []string{"services/api/handlers", "services/api"}, cwds("services/api"))
+	require.Equal(t, []string{"services/api/handlers", "services/api"}, cwds("./services/api/"))
+	require.Equal(t, []string{"services/api/handlers"}, cwds("services/api/handlers"))
+	require.Len(t, cwds("."), 5)
+
+	web := "web"
+	since, err := ListEventsSinceFiltered(db, 0, EventFilter{Path: &web})
+	require.NoError(t, err)
+	require.Len(t, since, 1)
+	require.Equal(t, "web", since[0].Cwd)
+}
+
 func TestListEvents_CombinedFilters(t *testing.T) {
 	db := newTestDB(t)
 
@@ -262,6 +301,64 @@ func TestListEvents_Limit(t *testing.T) {
 	require.Len(t, got, 10)
 }
 
+func TestListEvents_Paging(t *testing.T) {
+	db := newTestDB(t)
+
+	// Seven events, four sharing a second, so pages split between ties
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		ts := base.Add(time.Duration(min(i, 3)) * time.Minute)
+		require.NoError(t, InsertEvent(db, RepoEvent{
+			RepoID:    "repo1",
+			RepoPath:  "/path1",
+			Commit:    string(rune('a' + i)),
+			Branch:    "main",
+			Timestamp: ts,
+			Status:    StatusPending,
+			Source:    SourcePostCommit,
+		}))
+	}
+
+	all, err := ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+
+	var paged []RepoEvent
+	filter := EventFilter{Limit: 3}
+	for {
+		page, err := ListEvents(db, filter)
+		require.NoError(t, err)
+		paged = append(paged, page...)
+		if len(page) < filter.Limit {
+			break
+		}
+		filter.Before = CursorAfter(page[len(page)-1])
+	}
+	require.Equal(t, all, paged, "pages add up to the whole listing, in order")
+}
+
+func TestCountEvents(t *testing.T) {
+	db := newTestDB(t)
+
+	for i, e := range []RepoEvent{
+		{RepoPath: "/code/app", Device: "laptop", Source: SourcePostCommit},
+		{RepoPath: "/code/app", Device: "laptop", Source: SourcePrePush},
+		{RepoPath: "/code/lib", Device: "", Source: SourcePostCommit},
+	} {
+		e.RepoID = e.RepoPath
+		e.Commit = string(rune('a' + i))
+		e.Timestamp = time.Now()
+		e.Status = StatusPending
+		require.NoError(t, InsertEvent(db, e))
+	}
+
+	counts, err := CountEvents(db)
+	require.NoError(t, err)
+	require.Equal(t, 3, counts.Total)
+	require.Equal(t, map[Source]int{SourcePostCommit: 2, SourcePrePush: 1}, counts.BySource)
+	require.Equal(t, map[string]int{"/code/app": 2, "/code/lib": 1}, counts.ByRepo)
+	require.Equal(t, map[string]int{"laptop": 2, "": 1}, counts.ByDevice)
+}
+
 func TestGetMaxEventID(t *testing.T) {
 	db := newTestDB(t)
 
@@ -448,3 +545,52 @@ func TestGetPendingEvents(t *testing.T) {
 		require.Equal(t, StatusPending, e.Status)
 	}
 }
+
+func TestListRepoIDs(t *testing.T) {
+	db := newTestDB(t)
+	ts := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	for _, id := range []string{"github.com/user/b", "github.com/user/a", "github.com/user/b"} {
+		require.NoError(t, InsertEvent(db, RepoEvent{
+			RepoID: id, Commit: id + "-commit", Branch: "main", Timestamp: ts,
+			Status: StatusPending, Source: SourcePostCommit,
+		}))
+	}
+	_, err := db.Exec(`INSERT INTO tracked_repos (repo_id, repo_path) VALUES ('github.com/user/c', '/src/c')`)
+	require.NoError(t, err)
+
+	ids, err := ListRepoIDs(db)
+	require.NoError(t, err)
+	require.Equal(t, []string{"github.com/user/a", "github.com/user/b", "github.com/user/c"}, ids)
+}
+
+func TestListEvents_FilterByDevice(t *testing.T) {
+	db := newTestDB(t)
+	ts := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	for i, device := range []string{"laptop", "desktop", "laptop", ""} {
+		require.NoError(t, InsertEvent(db, RepoEvent{
+			RepoID: "github.com/user/repo", RepoPath: "/path", Commit: "c" + string(rune('0'+i)),
+			Timestamp: ts.Add(time.Duration(i) * time.Minute), Status: StatusPending, Source: SourcePostCommit,
+			Device: device,
+		}))
+	}
+
+	laptop := "laptop"
+	got, err := ListEvents(db, EventFilter{Device: &laptop})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	for _, e := range got {
+		require.Equal(t, "laptop", e.Device)
+	}
+
+	desktop := "desktop"
+	since, err := ListEventsSinceFiltered(db, 0, EventFilter{Device: &desktop})
+	require.NoError(t, err)
+	require.Len(t, since, 1)
+	require.Equal(t, "c1", since[0].Commit)
+
+	devices, err := ListDevices(db)
+	require.NoError(t, err)
+	require.Equal(t, []string{"desktop", "laptop"}, devices)
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DailyRollup is what happened in one repository on one local day, for one
+// source. Insertions and deletions add up the stored diff stats of every
+// event's commit, so a commit recorded by several sources counts once per
+// source.
+type DailyRollup struct {
+	Day        string // YYYY-MM-DD
+	RepoID     string
+	RepoPath   string
+	Source     Source
+	Events     int
+	Insertions int
+	Deletions  int
+}
+
+// RollupFilter narrows ListDailyRollups. Since and Until are compared by
+// local day.
+type RollupFilter struct {
+	Since  *time.Time
+	Until  *time.Time
+	RepoID *string
+}
+
+// ListDailyRollups returns the daily rollups matching filter, oldest day
+// first. They are kept up to date as events are written, so reading them
+// takes a row per active day however many events there are.
+func ListDailyRollups(db *sql.DB, filter RollupFilter) ([]DailyRollup, error) {
+	query := `
+		SELECT day, repo_id, repo_path, source_id, events, insertions, deletions
+		FROM daily_rollups
+		WHERE events > 0`
+	var args []any
+	if filter.Since != nil {
+		query += " AND day >= ?"
+		args = append(args, filter.Since.Local().Format("2006-01-02"))
+	}
+	if filter.Until != nil {
+		query += " AND day <= ?"
+		args = append(args, filter.Until.Local().Format("2006-01-02"))
+	}
+	if filter.RepoID != nil {
+		query += " AND repo_id = ?"
+		args = append(args, *filter.RepoID)
+	}
+	query += " ORDER BY day, repo_id, source_id"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var out []DailyRollup
+	for rows.Next() {
+		var (
+			r        DailyRollup
+			sourceID int
+		)
+		if err := rows.Scan(&r.Day, &r.RepoID, &r.RepoPath, &sourceID, &r.Events, &r.Insertions, &r.Deletions); err != nil {
+			return nil, err
+		}
+		r.Source = Source(sourceID)
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// RebuildDailyRollups recomputes every rollup from the events, for days
+// written in another time zone or a store the triggers missed. Returns the
+// number of rollups.
+func RebuildDailyRollups(db *sql.DB) (int64, error) {
+	var rebuilt int64
+	err := retryBusy("rebuild daily rollups", func() error {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = tx.Rollback() }()
+
+		if _, err := tx.Exec(`DELETE FROM daily_rollups`); err != nil {
+			return err
+		}
+		result, err := tx.Exec(`
+			INSERT INTO daily_rollups (day, repo_id, source_id, repo_path, events, insertions, deletions)
+			SELECT date(e.timestamp, 'localtime'), e.repo_id, e.source_id, MAX(COALESCE(e.repo_path, '')),
+				COUNT(*), COALESCE(SUM(m.insertions), 0), COALESCE(SUM(m.deletions), 0)
+			FROM repo_events e
+			LEFT JOIN commit_meta m ON m.repo_id = e.repo_id AND m.commit_hash = e.commit_hash
+			GROUP BY 1, 2, 3
+		`)
+		if err != nil {
+			return err
+		}
+		if rebuilt, err = result.RowsAffected(); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+	return rebuilt, err
+}
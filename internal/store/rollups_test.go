@@ -0,0 +1,105 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/footprint-tools/cli/internal/git"
+)
+
+func rollupEvent(commit string, source Source, t time.Time) RepoEvent {
+	return RepoEvent{
+		RepoID:    "github.com/user/repo",
+		RepoPath:  "/src/repo",
+		Commit:    commit,
+		Branch:    "main",
+		Timestamp: t,
+		Status:    StatusPending,
+		Source:    source,
+		Device:    "laptop",
+	}
+}
+
+func TestDailyRollups(t *testing.T) {
+	db := newTestDB(t)
+	day1 := time.Date(2025, 3, 3, 10, 0, 0, 0, time.Local)
+	day2 := time.Date(2025, 3, 4, 9, 0, 0, 0, time.Local)
+
+	require.NoError(t, InsertEvent(db, rollupEvent("aaa", SourcePostCommit, day1)))
+	require.NoError(t, InsertEvent(db, rollupEvent("bbb", SourcePostCommit, day1.Add(time.Hour))))
+	require.NoError(t, InsertEvent(db, rollupEvent("bbb", SourcePrePush, day1.Add(2*time.Hour))))
+
+	// Metadata lands after the events and adds its lines to each of them
+	require.NoError(t, SaveCommitMeta(db, "github.com/user/repo", "bbb", git.CommitMetadata{Insertions: 10, Deletions: 2}))
+	require.NoError(t, SaveCommitMeta(db, "github.com/user/repo", "bbb", git.CommitMetadata{Insertions: 12, Deletions: 3}))
+
+	rollups, err := ListDailyRollups(db, RollupFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []DailyRollup{
+		{Day: "2025-03-03", RepoID: "github.com/user/repo", RepoPath: "/src/repo", Source: SourcePostCommit, Events: 2, Insertions: 12, Deletions: 3},
+		{Day: "2025-03-03", RepoID: "github.com/user/repo", RepoPath: "/src/repo", Source: SourcePrePush, Events: 1, Insertions: 12, Deletions: 3},
+	}, rollups)
+
+	// Recording a commit again moves it to its new day, with its lines
+	require.NoError(t, InsertEvent(db, rollupEvent("bbb", SourcePostCommit, day2)))
+	// Imports are counted as they are inserted
+	imported := rollupEvent("ccc", SourceBackfill, day2)
+	imported.RepoID = "github.com/user/other"
+	imported.RepoPath = "/src/other"
+	imported.Device = "desktop"
+	_, err = ImportEvents(db, []RepoEvent{imported})
+	require.NoError(t, err)
+
+	since := day2
+	rollups, err = ListDailyRollups(db, RollupFilter{Since: &since})
+	require.NoError(t, err)
+	require.Equal(t, []DailyRollup{
+		{Day: "2025-03-04", RepoID: "github.com/user/other", RepoPath: "/src/other", Source: SourceBackfill, Events: 1},
+		{Day: "2025-03-04", RepoID: "github.com/user/repo", RepoPath: "/src/repo", Source: SourcePostCommit, Events: 1, Insertions: 12, Deletions: 3},
+	}, rollups)
+
+	until := day1
+	rollups, err = ListDailyRollups(db, RollupFilter{Until: &until})
+	require.NoError(t, err)
+	require.Len(t, rollups, 2)
+	require.Equal(t, 1, rollups[0].Events)
+	require.Zero(t, rollups[0].Insertions)
+
+	// Deleted events leave their days, and empty days go
+	_, err = db.Exec(`DELETE FROM repo_events WHERE device = ?`, "laptop")
+	require.NoError(t, err)
+	repoID := "github.com/user/repo"
+	rollups, err = ListDailyRollups(db, RollupFilter{RepoID: &repoID})
+	require.NoError(t, err)
+	require.Empty(t, rollups)
+
+	var rows int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM daily_rollups`).Scan(&rows))
+	require.Equal(t, 1, rows)
+}
+
+func TestRebuildDailyRollups(t *testing.T) {
+	db := newTestDB(t)
+	day := time.Date(2025, 3, 3, 10, 0, 0, 0, time.Local)
+
+	require.NoError(t, InsertEvent(db, rollupEvent("aaa", SourcePostCommit, day)))
+	require.NoError(t, InsertEvent(db, rollupEvent("bbb", SourcePostCommit, day.AddDate(0, 0, 1))))
+	require.NoError(t, SaveCommitMeta(db, "github.com/user/repo", "aaa", git.CommitMetadata{Insertions: 4, Deletions: 1}))
+
+	before, err := ListDailyRollups(db, RollupFilter{})
+	require.NoError(t, err)
+
+	_, err = db.Exec(`UPDATE daily_rollups SET events = 99`)
+	require.NoError(t, err)
+
+	n, err := RebuildDailyRollups(db)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+
+	after, err := ListDailyRollups(db, RollupFilter{})
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+	require.Equal(t, 4, after[0].Insertions)
+}
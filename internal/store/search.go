@@ -0,0 +1,164 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/footprint-tools/cli/internal/log"
+)
+
+// The full-text index over commit_text. It is an external-content FTS5
+// table, so the text itself is stored once and triggers keep the index in
+// step. FTS5 is only compiled into go-sqlite3 with the sqlite_fts5 build
+// tag; without it the table can't be created and searches fall back to
+// LIKE over commit_text, which gives the same results, only slower.
+var searchIndexSchema = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS commit_search USING fts5(
+		subject, body, content='commit_text', content_rowid='id'
+	)`,
+	`CREATE TRIGGER IF NOT EXISTS commit_text_ai AFTER INSERT ON commit_text BEGIN
+		INSERT INTO commit_search(rowid, subject, body) VALUES (new.id, new.subject, new.body);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS commit_text_ad AFTER DELETE ON commit_text BEGIN
+		INSERT INTO commit_search(commit_search, rowid, subject, body) VALUES ('delete', old.id, old.subject, old.body);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS commit_text_au AFTER UPDATE ON commit_text BEGIN
+		INSERT INTO commit_search(commit_search, rowid, subject, body) VALUES ('delete', old.id, old.subject, old.body);
+		INSERT INTO commit_search(rowid, subject, body) VALUES (new.id, new.subject, new.body);
+	END`,
+}
+
+// EnsureSearchIndex creates the full-text index over commit text if this
+// build of SQLite supports FTS5, indexing any text stored before it
+// existed. It reports whether the index is usable; when it isn't, searches
+// still work without it.
+func EnsureSearchIndex(db *sql.DB) bool {
+	if searchIndexReady(db) {
+		return true
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Debug("store: search index unavailable: %v", err)
+		return false
+	}
+	for _, stmt := range searchIndexSchema {
+		if _, err := tx.Exec(stmt); err != nil {
+			_ = tx.Rollback()
+			log.Debug("store: search index unavailable: %v", err)
+			return false
+		}
+	}
+	if _, err := tx.Exec(`INSERT INTO commit_search(commit_search) VALUES ('rebuild')`); err != nil {
+		_ = tx.Rollback()
+		log.Warn("store: could not build search index: %v", err)
+		return false
+	}
+	if err := tx.Commit(); err != nil {
+		log.Warn("store: could not build search index: %v", err)
+		return false
+	}
+	log.Debug("store: search index created")
+	return true
+}
+
+// searchIndexReady reports whether the full-text index exists and can be
+// queried. A database indexed by an FTS5 build still has the table when
+// opened by a build without it, so existence alone isn't enough.
+func searchIndexReady(db *sql.DB) bool {
+	rows, err := db.Query(`SELECT rowid FROM commit_search LIMIT 0`)
+	if err != nil {
+		return false
+	}
+	closeRows(rows)
+	return true
+}
+
+// SaveCommitText stores the subject and body of a commit for search,
+// replacing any text stored for it before.
+func SaveCommitText(db *sql.DB, repoID, commit, subject, body string) error {
+	_, err := db.Exec(`
+		INSERT INTO commit_text (repo_id, commit_hash, subject, body)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (repo_id, commit_hash) DO UPDATE SET
+			subject = excluded.subject,
+			body = excluded.body
+	`, repoID, commit, subject, body)
+	return err
+}
+
+// HasCommitText reports whether text is stored for a commit.
+func HasCommitText(db *sql.DB, repoID, commit string) (bool, error) {
+	var n int
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM commit_text WHERE repo_id = ? AND commit_hash = ?`,
+		repoID, commit,
+	).Scan(&n)
+	return n > 0, err
+}
+
+// searchTerms splits a search query into the words every match must
+// contain.
+func searchTerms(query string) []string {
+	return strings.Fields(query)
+}
+
+// ftsQuery turns the words of a search into an FTS5 query: each word is
+// quoted, so punctuation is taken literally, and matched as a prefix, so
+// results appear while a word is still being typed.
+func ftsQuery(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"*`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// likeAll returns a clause matching rows where every term appears in one
+// of columns, case-insensitively.
+func likeAll(terms []string, columns ...string) (string, []any) {
+	escaper := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	var (
+		clauses []string
+		args    []any
+	)
+	for _, term := range terms {
+		pattern := "%" + escaper.Replace(term) + "%"
+		var alts []string
+		for _, col := range columns {
+			alts = append(alts, col+` LIKE ? ESCAPE '\'`)
+			args = append(args, pattern)
+		}
+		clauses = append(clauses, "("+strings.Join(alts, " OR ")+")")
+	}
+	return strings.Join(clauses, " AND "), args
+}
+
+// searchClause matches events whose commit subject or body, or whose note,
+// contains every word of query. indexed selects the full-text index over
+// the LIKE fallback.
+func searchClause(query string, indexed bool) (string, []any) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return "1 = 1", nil
+	}
+
+	noteClause, noteArgs := likeAll(terms, "note")
+
+	if indexed {
+		clause := fmt.Sprintf(`((repo_id, commit_hash) IN (
+			SELECT t.repo_id, t.commit_hash FROM commit_search s
+			JOIN commit_text t ON t.id = s.rowid
+			WHERE commit_search MATCH ?
+		) OR (%s))`, noteClause)
+		return clause, append([]any{ftsQuery(terms)}, noteArgs...)
+	}
+
+	textClause, textArgs := likeAll(terms, "t.subject", "t.body")
+	clause := fmt.Sprintf(`(EXISTS (
+		SELECT 1 FROM commit_text t
+		WHERE t.repo_id = repo_events.repo_id AND t.commit_hash = repo_events.commit_hash AND %s
+	) OR (%s))`, textClause, noteClause)
+	return clause, append(textArgs, noteArgs...)
+}
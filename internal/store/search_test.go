@@ -0,0 +1,99 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testSearch(t *testing.T, indexed bool) {
+	t.Helper()
+	db := newTestDB(t)
+	if indexed && !EnsureSearchIndex(db) {
+		t.Skip("SQLite built without FTS5 (build with -tags sqlite_fts5)")
+	}
+
+	first := snapshotEvent("aaa")
+	second := snapshotEvent("bbb")
+	require.NoError(t, InsertEvent(db, first))
+	require.NoError(t, InsertEvent(db, second))
+	id, err := GetMaxEventID(db)
+	require.NoError(t, err)
+	require.NoError(t, SetEventNote(db, id, "the prod hotfix"))
+
+	require.NoError(t, SaveCommitText(db, first.RepoID, "aaa", "Fix login redirect", "The session cookie was dropped after OAuth."))
+	require.NoError(t, SaveCommitText(db, second.RepoID, "bbb", "Bump dependencies", ""))
+	// Replacing text keeps the index in step
+	require.NoError(t, SaveCommitText(db, second.RepoID, "bbb", "Bump go-sqlite3 to 1.14", "100%_done"))
+
+	search := func(query string) []string {
+		events, err := ListEvents(db, EventFilter{Search: &query})
+		require.NoError(t, err)
+		var commits []string
+		for _, e := range events {
+			commits = append(commits, e.Commit)
+		}
+		return commits
+	}
+
+	require.Equal(t, []string{"aaa"}, search("login"))
+	require.Equal(t, []string{"aaa"}, search("COOKIE oauth"), "body, case-insensitive, every word")
+	require.Empty(t, search("login sqlite"))
+	require.Empty(t, search("dependencies"), "replaced text no longer matches")
+	require.Equal(t, []string{"bbb"}, search("go-sqlite3"))
+	require.Equal(t, []string{"bbb"}, search("hotfix"), "notes match too")
+	require.ElementsMatch(t, []string{"aaa", "bbb"}, search("  "), "blank query matches everything")
+}
+
+func TestSearch_Fallback(t *testing.T) {
+	testSearch(t, false)
+}
+
+func TestSearch_Index(t *testing.T) {
+	testSearch(t, true)
+}
+
+func TestEnsureSearchIndex_IndexesExistingText(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, InsertEvent(db, snapshotEvent("aaa")))
+	require.NoError(t, SaveCommitText(db, "github.com/user/repo", "aaa", "Fix login redirect", ""))
+
+	if !EnsureSearchIndex(db) {
+		t.Skip("SQLite built without FTS5 (build with -tags sqlite_fts5)")
+	}
+	require.True(t, EnsureSearchIndex(db), "creating the index again is a no-op")
+
+	query := "login"
+	events, err := ListEvents(db, EventFilter{Search: &query})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+}
+
+func TestSearch_LikeWildcardsAreLiteral(t *testing.T) {
+	db := newTestDB(t)
+	require.NoError(t, InsertEvent(db, snapshotEvent("aaa")))
+	require.NoError(t, SaveCommitText(db, "github.com/user/repo", "aaa", "Raise coverage", ""))
+
+	query := "%"
+	events, err := ListEvents(db, EventFilter{Search: &query})
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestHasCommitText(t *testing.T) {
+	db := newTestDB(t)
+
+	ok, err := HasCommitText(db, "github.com/user/repo", "aaa")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, SaveCommitText(db, "github.com/user/repo", "aaa", "Initial commit", ""))
+	ok, err = HasCommitText(db, "github.com/user/repo", "aaa")
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestFTSQuery_QuotesWords(t *testing.T) {
+	require.Equal(t, `"fix"* "say ""hi"""*`, ftsQuery([]string{"fix", `say "hi"`}))
+	require.Equal(t, `"fix"* "a:b"*`, ftsQuery(searchTerms(" fix  a:b ")))
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/footprint-tools/cli/internal/store/migrations"
+)
+
+// OpenSnapshot opens a read-only connection for the interactive views.
+//
+// The store runs in WAL mode, so every statement on this connection reads a
+// consistent snapshot of the database as of when it started, and an export
+// or a hook writing at the same time neither blocks it nor makes it fail.
+// Being read-only, the connection can never take the write lock itself, so
+// a TUI left open doesn't hold up the hooks either.
+//
+// A database that doesn't exist yet, or that an older fp left behind, is
+// created or migrated first through a regular connection.
+func OpenSnapshot(path string) (*sql.DB, error) {
+	if err := unlockFor(path); err != nil {
+		return nil, err
+	}
+	current, err := schemaIsCurrent(path)
+	if err != nil {
+		return nil, err
+	}
+	if !current {
+		s, err := New(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.Close(); err != nil {
+			return nil, fmt.Errorf("close database: %w", err)
+		}
+	}
+	return openReadOnlyConn(path)
+}
+
+// schemaIsCurrent reports whether the database at path exists and
+// has every migration applied. It doesn't write to the database.
+func schemaIsCurrent(path string) (bool, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	all, err := migrations.Load()
+	if err != nil {
+		return false, err
+	}
+	if len(all) == 0 {
+		return true, nil
+	}
+
+	conn, err := openReadOnlyConn(path)
+	if err != nil {
+		return false, err
+	}
+	defer CloseDB(conn)
+
+	var version sql.NullInt64
+	if err := conn.QueryRow("SELECT MAX(version) FROM schema_migrations").Scan(&version); err != nil {
+		// No schema_migrations table: the schema was never created
+		return false, nil
+	}
+	return version.Int64 >= int64(all[len(all)-1].Version), nil
+}
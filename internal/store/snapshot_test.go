@@ -0,0 +1,63 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func snapshotEvent(commit string) RepoEvent {
+	return RepoEvent{
+		RepoID: "github.com/user/repo", Commit: commit, Branch: "main",
+		Timestamp: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+		Status:    StatusPending, Source: SourcePostCommit,
+	}
+}
+
+func TestOpenSnapshot_CreatesMissingDatabase(t *testing.T) {
+	db, err := OpenSnapshot(filepath.Join(t.TempDir(), "store.db"))
+	require.NoError(t, err)
+	defer CloseDB(db)
+
+	events, err := ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	require.Empty(t, events)
+}
+
+func TestOpenSnapshot_ReadsDuringWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.db")
+	s, err := New(path)
+	require.NoError(t, err)
+	defer func() { _ = s.Close() }()
+	require.NoError(t, InsertEvent(s.DB(), snapshotEvent("aaa")))
+
+	snap, err := OpenSnapshot(path)
+	require.NoError(t, err)
+	defer CloseDB(snap)
+
+	// An export holds the write lock with changes not yet committed
+	tx, err := s.DB().Begin()
+	require.NoError(t, err)
+	_, err = tx.Exec(`UPDATE repo_events SET status_id = ?`, int(StatusExported))
+	require.NoError(t, err)
+	_, err = tx.Exec(`INSERT INTO repo_events (repo_id, repo_path, commit_hash, branch, timestamp, status_id, source_id)
+		VALUES ('github.com/user/repo', '', 'bbb', 'main', '2024-01-16T12:00:00Z', ?, ?)`, int(StatusExported), int(SourcePostCommit))
+	require.NoError(t, err)
+
+	start := time.Now()
+	events, err := ListEvents(snap, EventFilter{})
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), time.Second, "reads must not wait for the writer")
+	require.Len(t, events, 1, "uncommitted rows are not visible")
+	require.Equal(t, StatusPending, events[0].Status)
+
+	require.NoError(t, tx.Commit())
+	events, err = ListEvents(snap, EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2, "the next read sees the committed export")
+
+	_, err = snap.Exec(`DELETE FROM repo_events`)
+	require.Error(t, err, "snapshot connections are read-only")
+}
@@ -15,4 +15,7 @@ const (
 	SourcePrePush      = domain.SourcePrePush
 	SourceManual       = domain.SourceManual
 	SourceBackfill     = domain.SourceBackfill
+	SourceBranchCreate = domain.SourceBranchCreate
+	SourceBranchDelete = domain.SourceBranchDelete
+	SourceStash        = domain.SourceStash
 )
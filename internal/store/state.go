@@ -4,25 +4,27 @@ package store
 type UpdateCache struct {
 	LastCheck     string
 	LatestVersion string
+	Channel       string
 }
 
 // GetUpdateCache retrieves the cached update check info.
 func (s *Store) GetUpdateCache() (UpdateCache, error) {
 	var cache UpdateCache
 	err := s.db.QueryRow(`
-		SELECT COALESCE(update_last_check, ''), COALESCE(update_latest_version, '')
+		SELECT COALESCE(update_last_check, ''), COALESCE(update_latest_version, ''), COALESCE(update_channel, '')
 		FROM state WHERE id = 1
-	`).Scan(&cache.LastCheck, &cache.LatestVersion)
+	`).Scan(&cache.LastCheck, &cache.LatestVersion, &cache.Channel)
 	if err != nil {
 		return UpdateCache{}, err
 	}
 	return cache, nil
 }
 
-// SetUpdateCache stores the update check info.
-func (s *Store) SetUpdateCache(lastCheck, latestVersion string) error {
+// SetUpdateCache stores the update check info, with the release channel
+// latestVersion came from.
+func (s *Store) SetUpdateCache(lastCheck, latestVersion, channel string) error {
 	_, err := s.db.Exec(`
-		UPDATE state SET update_last_check = ?, update_latest_version = ? WHERE id = 1
-	`, lastCheck, latestVersion)
+		UPDATE state SET update_last_check = ?, update_latest_version = ?, update_channel = ? WHERE id = 1
+	`, lastCheck, latestVersion, channel)
 	return err
 }
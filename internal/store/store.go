@@ -24,6 +24,10 @@ type Store struct {
 // New creates a new Store with the given database path.
 // Runs migrations automatically.
 func New(path string) (*Store, error) {
+	if err := unlockFor(path); err != nil {
+		return nil, err
+	}
+
 	db, err := sql.Open("sqlite3", path)
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
@@ -47,6 +51,8 @@ func New(path string) (*Store, error) {
 		return nil, fmt.Errorf("run migrations: %w", err)
 	}
 
+	EnsureSearchIndex(db)
+
 	return &Store{db: db, path: path}, nil
 }
 
@@ -55,8 +61,9 @@ func New(path string) (*Store, error) {
 // busy_timeout prevents "database is locked" errors during concurrent access.
 func configureSQLite(db *sql.DB) error {
 	pragmas := []string{
-		"PRAGMA journal_mode=WAL",   // Write-Ahead Logging for better concurrency
+		// First, so switching to WAL waits for a hook holding the lock
 		"PRAGMA busy_timeout=5000",  // Wait up to 5 seconds if locked
+		"PRAGMA journal_mode=WAL",   // Write-Ahead Logging for better concurrency
 		"PRAGMA synchronous=NORMAL", // Safe with WAL, better performance
 		"PRAGMA foreign_keys=ON",    // Enforce foreign key constraints
 		"PRAGMA cache_size=-64000",  // 64MB cache (negative = KB)
@@ -394,6 +401,67 @@ func (s *Store) CountOrphaned() (int64, error) {
 	return count, nil
 }
 
+// CountByStatus returns the number of events in each status.
+// Statuses without events are omitted.
+func (s *Store) CountByStatus() (map[domain.EventStatus]int64, error) {
+	rows, err := s.db.Query(`SELECT status_id, COUNT(*) FROM repo_events GROUP BY status_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	counts := make(map[domain.EventStatus]int64)
+	for rows.Next() {
+		var status int
+		var count int64
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[domain.EventStatus(status)] = count
+	}
+	return counts, rows.Err()
+}
+
+// PruneExported deletes exported events recorded before the given time.
+// Pending events are never pruned so nothing is lost before it is exported.
+// Returns the number of events deleted.
+func (s *Store) PruneExported(before time.Time) (int64, error) {
+	query := `DELETE FROM repo_events WHERE status_id = ? AND datetime(timestamp) < datetime(?)`
+	result, err := s.db.Exec(query, int(domain.StatusExported), before.UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Vacuum rebuilds the database file to reclaim space left by deleted rows.
+func (s *Store) Vacuum() error {
+	_, err := s.db.Exec(`VACUUM`)
+	return err
+}
+
+// IntegrityCheck runs PRAGMA integrity_check and returns the problems it
+// reports. An empty slice means the database is healthy.
+func (s *Store) IntegrityCheck() ([]string, error) {
+	rows, err := s.db.Query(`PRAGMA integrity_check`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	return problems, rows.Err()
+}
+
 // ListDistinctRepos returns all unique repository IDs that have recorded events.
 func (s *Store) ListDistinctRepos() ([]domain.RepoID, error) {
 	query := `SELECT DISTINCT repo_id FROM repo_events ORDER BY repo_id`
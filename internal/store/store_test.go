@@ -2,6 +2,7 @@ package store
 
 import (
 	"database/sql"
+	"fmt"
 	"testing"
 	"time"
 
@@ -505,3 +506,61 @@ func TestStore_ListDistinctRepos(t *testing.T) {
 	require.Contains(t, repos, domain.RepoID("github.com/test/repo1"))
 	require.Contains(t, repos, domain.RepoID("github.com/test/repo2"))
 }
+
+func TestStore_PruneExported(t *testing.T) {
+	s := newTestStore(t)
+
+	old := time.Now().AddDate(-2, 0, 0)
+	events := []domain.RepoEvent{
+		{RepoID: "github.com/test/repo", Commit: "old-exported", Branch: "main", Timestamp: old, Status: domain.StatusExported, Source: domain.SourcePostCommit},
+		{RepoID: "github.com/test/repo", Commit: "old-pending", Branch: "main", Timestamp: old, Status: domain.StatusPending, Source: domain.SourcePostCommit},
+		{RepoID: "github.com/test/repo", Commit: "new-exported", Branch: "main", Timestamp: time.Now(), Status: domain.StatusExported, Source: domain.SourcePostCommit},
+	}
+	for _, e := range events {
+		require.NoError(t, s.Insert(e))
+	}
+
+	deleted, err := s.PruneExported(time.Now().AddDate(-1, 0, 0))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	remaining, err := s.List(domain.EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, remaining, 2)
+	for _, e := range remaining {
+		require.NotEqual(t, "old-exported", e.Commit)
+	}
+
+	require.NoError(t, s.Vacuum())
+}
+
+func TestStore_CountByStatus(t *testing.T) {
+	s := newTestStore(t)
+
+	counts, err := s.CountByStatus()
+	require.NoError(t, err)
+	require.Empty(t, counts)
+
+	for i, status := range []domain.EventStatus{domain.StatusPending, domain.StatusPending, domain.StatusExported} {
+		require.NoError(t, s.Insert(domain.RepoEvent{
+			RepoID:    domain.RepoID("github.com/test/repo"),
+			Commit:    fmt.Sprintf("commit%d", i),
+			Branch:    "main",
+			Timestamp: time.Now(),
+			Status:    status,
+			Source:    domain.SourcePostCommit,
+		}))
+	}
+
+	counts, err = s.CountByStatus()
+	require.NoError(t, err)
+	require.Equal(t, map[domain.EventStatus]int64{domain.StatusPending: 2, domain.StatusExported: 1}, counts)
+}
+
+func TestStore_IntegrityCheck(t *testing.T) {
+	s := newTestStore(t)
+
+	problems, err := s.IntegrityCheck()
+	require.NoError(t, err)
+	require.Empty(t, problems)
+}
@@ -1,6 +1,10 @@
 package store
 
 import (
+	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/footprint-tools/cli/internal/git"
 	"github.com/footprint-tools/cli/internal/repo"
 )
@@ -74,3 +78,115 @@ func (s *Store) ListRepoPaths() ([]string, error) {
 	}
 	return paths, rows.Err()
 }
+
+// RepoActivity is the latest event of a repository and how many of its
+// events wait to be exported.
+type RepoActivity struct {
+	LastEvent RepoEvent
+	Pending   int64
+}
+
+// ActivityByRepo returns the activity of every repository with events,
+// keyed by repo ID. The daily rollups give each repository's last active
+// day, so only the events around that day are read, plus the pending ones.
+func (s *Store) ActivityByRepo() (map[string]RepoActivity, error) {
+	lastDays, err := lastActiveDays(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := make(map[string]RepoActivity, len(lastDays))
+	for repoID, day := range lastDays {
+		last, err := latestEventSince(s.db, repoID, daysBefore(day, 2))
+		if errors.Is(err, sql.ErrNoRows) {
+			// Rollups from before a move to another time zone
+			last, err = latestEventSince(s.db, repoID, "")
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		activity[repoID] = RepoActivity{LastEvent: last}
+	}
+
+	rows, err := s.db.Query(`
+		SELECT repo_id, COUNT(*) FROM repo_events
+		WHERE status_id = ?
+		GROUP BY repo_id
+	`, int(StatusPending))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+	for rows.Next() {
+		var (
+			repoID  string
+			pending int64
+		)
+		if err := rows.Scan(&repoID, &pending); err != nil {
+			return nil, err
+		}
+		a := activity[repoID]
+		a.Pending = pending
+		activity[repoID] = a
+	}
+	return activity, rows.Err()
+}
+
+// lastActiveDays returns the last day with events of each repository,
+// from the daily rollups.
+func lastActiveDays(db *sql.DB) (map[string]string, error) {
+	rows, err := db.Query(`SELECT repo_id, MAX(day) FROM daily_rollups WHERE events > 0 GROUP BY repo_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	days := make(map[string]string)
+	for rows.Next() {
+		var repoID, day string
+		if err := rows.Scan(&repoID, &day); err != nil {
+			return nil, err
+		}
+		days[repoID] = day
+	}
+	return days, rows.Err()
+}
+
+// daysBefore returns the YYYY-MM-DD day n days before day.
+func daysBefore(day string, n int) string {
+	t, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return ""
+	}
+	return t.AddDate(0, 0, -n).Format("2006-01-02")
+}
+
+// latestEventSince returns the newest event of repoID among those whose
+// timestamp sorts from from on. Rollup days are local to the machine that
+// wrote them while timestamps keep the offset they were recorded with, so
+// callers look from a couple of days before the last active day.
+func latestEventSince(db *sql.DB, repoID, from string) (RepoEvent, error) {
+	var (
+		e        RepoEvent
+		ts       string
+		sourceID int
+	)
+	err := db.QueryRow(`
+		SELECT repo_id, commit_hash, branch, timestamp, source_id
+		FROM repo_events
+		WHERE repo_id = ? AND timestamp >= ?
+		ORDER BY datetime(timestamp) DESC, id DESC
+		LIMIT 1
+	`, repoID, from).Scan(&e.RepoID, &e.Commit, &e.Branch, &ts, &sourceID)
+	if err != nil {
+		return e, err
+	}
+	if e.Timestamp, err = time.Parse(time.RFC3339, ts); err != nil {
+		return e, err
+	}
+	e.Source = Source(sourceID)
+	return e, nil
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -119,3 +120,34 @@ func TestStore_ListRepoPaths_Empty(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, paths, 0)
 }
+
+func TestStore_ActivityByRepo(t *testing.T) {
+	s := newTestStore(t)
+	ts := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	insert := func(repoID, commit string, at time.Time, status Status) {
+		require.NoError(t, InsertEvent(s.DB(), RepoEvent{
+			RepoID: repoID, Commit: commit, Branch: "main", Timestamp: at,
+			Status: status, Source: SourcePostCommit,
+		}))
+	}
+	insert("github.com/a/one", "aaa", ts, StatusExported)
+	// An earlier wall-clock time in another zone is still the newest
+	insert("github.com/a/one", "bbb", ts.Add(2*time.Hour).In(time.FixedZone("", -5*3600)), StatusPending)
+	insert("github.com/a/one", "ccc", ts.Add(time.Hour), StatusPending)
+	insert("github.com/b/two", "ddd", ts, StatusExported)
+
+	activity, err := s.ActivityByRepo()
+	require.NoError(t, err)
+	require.Len(t, activity, 2)
+	require.Equal(t, "bbb", activity["github.com/a/one"].LastEvent.Commit)
+	require.EqualValues(t, 2, activity["github.com/a/one"].Pending)
+	require.Equal(t, SourcePostCommit, activity["github.com/a/one"].LastEvent.Source)
+	require.Zero(t, activity["github.com/b/two"].Pending)
+
+	// Rollups counted in a time zone far ahead still find the last event
+	_, err = s.DB().Exec(`UPDATE daily_rollups SET day = '2025-03-09' WHERE repo_id = 'github.com/b/two'`)
+	require.NoError(t, err)
+	activity, err = s.ActivityByRepo()
+	require.NoError(t, err)
+	require.Equal(t, "ddd", activity["github.com/b/two"].LastEvent.Commit)
+}
@@ -2,32 +2,83 @@ package store
 
 import (
 	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/footprint-tools/cli/internal/log"
 )
 
+// InsertEvent stores an event. Recording the same commit and source again
+// updates the stored event; a note or tag it already has is only replaced
+// by a new one, so a later plain record doesn't clear them. A busy database,
+// as when several hooks fire at once, is retried rather than failing.
 func InsertEvent(db *sql.DB, e RepoEvent) error {
-	_, err := db.Exec(
-		`INSERT INTO repo_events
-		 (repo_id, repo_path, commit_hash, branch, timestamp, status_id, source_id)
-		 VALUES (?, ?, ?, ?, ?, ?, ?)
+	err := retryBusy("insert event", func() error {
+		_, err := db.Exec(
+			`INSERT INTO repo_events
+		 (repo_id, repo_path, commit_hash, branch, timestamp, status_id, source_id, cwd, device, note, tag)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		 ON CONFLICT(repo_id, commit_hash, source_id)
-		 DO UPDATE SET timestamp = excluded.timestamp`,
-		e.RepoID,
-		e.RepoPath,
-		e.Commit,
-		e.Branch,
-		e.Timestamp.Format(time.RFC3339),
-		int(e.Status),
-		int(e.Source),
-	)
+		 DO UPDATE SET timestamp = excluded.timestamp, cwd = excluded.cwd, device = excluded.device,
+		   note = COALESCE(NULLIF(excluded.note, ''), note),
+		   tag = COALESCE(NULLIF(excluded.tag, ''), tag)`,
+			e.RepoID,
+			e.RepoPath,
+			e.Commit,
+			e.Branch,
+			e.Timestamp.Format(time.RFC3339),
+			int(e.Status),
+			int(e.Source),
+			e.Cwd,
+			e.Device,
+			e.Note,
+			e.Tag,
+		)
+		return err
+	})
 	if err != nil {
 		log.Error("store: insert event failed: %v (repo=%s, commit=%.7s)", err, e.RepoID, e.Commit)
 	}
 	return err
 }
 
+// SetEventNote replaces the note on an event. An empty note removes it.
+// Exported events go back to pending so the next export rewrites their row
+// with the note.
+func SetEventNote(db *sql.DB, id int64, note string) error {
+	result, err := db.Exec(`
+		UPDATE repo_events
+		SET note = ?, status_id = CASE WHEN status_id = ? THEN ? ELSE status_id END
+		WHERE id = ?`,
+		note, int(StatusExported), int(StatusPending), id)
+	if err != nil {
+		log.Error("store: set note failed: %v (id=%d)", err, id)
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("event %d not found", id)
+	}
+	return nil
+}
+
+// SetEventTag replaces the tag on an event. An empty tag removes it. Like
+// SetEventNote, exported events go back to pending.
+func SetEventTag(db *sql.DB, id int64, tag string) error {
+	result, err := db.Exec(`
+		UPDATE repo_events
+		SET tag = ?, status_id = CASE WHEN status_id = ? THEN ? ELSE status_id END
+		WHERE id = ?`,
+		tag, int(StatusExported), int(StatusPending), id)
+	if err != nil {
+		log.Error("store: set tag failed: %v (id=%d)", err, id)
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("event %d not found", id)
+	}
+	return nil
+}
+
 // MarkOrphanedByRepoID marks all pending events for a repo as orphaned.
 // Returns the number of events updated.
 func MarkOrphanedByRepoID(db *sql.DB, repoID string) (int64, error) {
@@ -56,6 +107,17 @@ func DeleteOrphanedEvents(db *sql.DB) (int64, error) {
 	return result.RowsAffected()
 }
 
+// DeleteEventsByRepoPrefix deletes the events whose repository ID starts
+// with prefix. Returns the number of events deleted.
+func DeleteEventsByRepoPrefix(db *sql.DB, prefix string) (int64, error) {
+	result, err := db.Exec(`DELETE FROM repo_events WHERE substr(repo_id, 1, ?) = ?`, len(prefix), prefix)
+	if err != nil {
+		log.Error("store: delete events of repositories %s* failed: %v", prefix, err)
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 // CountOrphanedEvents returns the count of orphaned events.
 func CountOrphanedEvents(db *sql.DB) (int64, error) {
 	var count int64
@@ -67,3 +129,47 @@ func CountOrphanedEvents(db *sql.DB) (int64, error) {
 	}
 	return count, nil
 }
+
+// ImportEvents inserts events from another machine, skipping any whose
+// repo_id and commit_hash are already stored under any source. Events
+// without a repo path take the path of a locally tracked repository with
+// the same ID, if there is one. Returns the number of events inserted.
+func ImportEvents(db *sql.DB, events []RepoEvent) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO repo_events
+		 (repo_id, repo_path, commit_hash, branch, timestamp, status_id, source_id, cwd, note, device, tag)
+		SELECT ?, COALESCE(NULLIF(?, ''), (SELECT repo_path FROM tracked_repos WHERE repo_id = ? LIMIT 1), ''), ?, ?, ?, ?, ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM repo_events WHERE repo_id = ? AND commit_hash = ?)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	inserted := 0
+	for _, e := range events {
+		result, err := stmt.Exec(
+			e.RepoID, e.RepoPath, e.RepoID, e.Commit, e.Branch,
+			e.Timestamp.Format(time.RFC3339), int(e.Status), int(e.Source), e.Cwd, e.Note, e.Device, e.Tag,
+			e.RepoID, e.Commit,
+		)
+		if err != nil {
+			log.Error("store: import event failed: %v (repo=%s, commit=%.7s)", err, e.RepoID, e.Commit)
+			return 0, err
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			inserted++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
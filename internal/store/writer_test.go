@@ -181,6 +181,38 @@ func TestInsertEvent_OnConflictUpdatesTimestamp(t *testing.T) {
 	require.True(t, event2.Timestamp.Equal(parsedTime), "timestamp should be updated to event2's timestamp")
 }
 
+func TestInsertEvent_KeepsNoteAndTag(t *testing.T) {
+	db := newTestDB(t)
+
+	event := RepoEvent{
+		RepoID:    "github.com/user/repo",
+		Commit:    "abc123",
+		Timestamp: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+		Status:    StatusPending,
+		Source:    SourceManual,
+		Note:      "paired with Sam",
+		Tag:       "pairing",
+	}
+	require.NoError(t, InsertEvent(db, event))
+
+	// Recording again without annotations keeps them
+	event.Note, event.Tag = "", ""
+	require.NoError(t, InsertEvent(db, event))
+	events, err := ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "paired with Sam", events[0].Note)
+	require.Equal(t, "pairing", events[0].Tag)
+
+	// A new tag replaces the old one
+	event.Tag = "client-x"
+	require.NoError(t, InsertEvent(db, event))
+	events, err = ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	require.Equal(t, "client-x", events[0].Tag)
+	require.Equal(t, "paired with Sam", events[0].Note)
+}
+
 func TestInsertEvent_DifferentSourceCreatesNewRow(t *testing.T) {
 	db := newTestDB(t)
 
@@ -337,6 +369,27 @@ func TestDeleteOrphanedEvents(t *testing.T) {
 	require.Equal(t, 1, remaining)
 }
 
+func TestDeleteEventsByRepoPrefix(t *testing.T) {
+	db := newTestDB(t)
+
+	events := []RepoEvent{
+		{RepoID: "fp-replay/github.com/user/repo1", RepoPath: "/path/1", Commit: "abc123", Branch: "main", Timestamp: time.Now(), Status: StatusSkipped, Source: SourcePostCommit},
+		{RepoID: "github.com/user/repo1", RepoPath: "/path/1", Commit: "abc123", Branch: "main", Timestamp: time.Now(), Status: StatusPending, Source: SourcePostCommit},
+		{RepoID: "fp-replay", RepoPath: "/path/2", Commit: "789abc", Branch: "main", Timestamp: time.Now(), Status: StatusPending, Source: SourcePostCommit},
+	}
+	for _, e := range events {
+		require.NoError(t, InsertEvent(db, e))
+	}
+
+	count, err := DeleteEventsByRepoPrefix(db, "fp-replay/")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	var remaining int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM repo_events").Scan(&remaining))
+	require.Equal(t, 2, remaining)
+}
+
 func TestCountOrphanedEvents(t *testing.T) {
 	db := newTestDB(t)
 
@@ -359,3 +412,96 @@ func TestCountOrphanedEvents(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, int64(2), count)
 }
+
+func TestImportEvents_SkipsExistingCommits(t *testing.T) {
+	db := newTestDB(t)
+	ts := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, InsertEvent(db, RepoEvent{
+		RepoID: "github.com/user/repo", RepoPath: "/local/repo", Commit: "aaa",
+		Branch: "main", Timestamp: ts, Status: StatusExported, Source: SourcePostCommit,
+	}))
+	_, err := db.Exec(`INSERT INTO tracked_repos (repo_id, repo_path) VALUES (?, ?)`, "github.com/user/repo", "/local/repo")
+	require.NoError(t, err)
+
+	imported := []RepoEvent{
+		{RepoID: "github.com/user/repo", Commit: "aaa", Branch: "main", Timestamp: ts, Status: StatusPending, Source: SourceBackfill},
+		{RepoID: "github.com/user/repo", Commit: "bbb", Branch: "main", Timestamp: ts, Status: StatusPending, Source: SourceBackfill},
+		{RepoID: "github.com/user/other", Commit: "ccc", Branch: "dev", Timestamp: ts, Status: StatusPending, Source: SourceBackfill},
+	}
+
+	n, err := ImportEvents(db, imported)
+	require.NoError(t, err)
+	require.Equal(t, 2, n, "commit aaa is already stored under another source")
+
+	// Importing again adds nothing
+	n, err = ImportEvents(db, imported)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	var path string
+	require.NoError(t, db.QueryRow(`SELECT repo_path FROM repo_events WHERE commit_hash = 'bbb'`).Scan(&path))
+	require.Equal(t, "/local/repo", path, "path should come from the tracked repo")
+
+	require.NoError(t, db.QueryRow(`SELECT repo_path FROM repo_events WHERE commit_hash = 'ccc'`).Scan(&path))
+	require.Empty(t, path)
+}
+
+func TestSetEventNote(t *testing.T) {
+	db := newTestDB(t)
+	event := RepoEvent{
+		RepoID: "github.com/user/repo", Commit: "aaa", Branch: "main",
+		Timestamp: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+		Status:    StatusExported, Source: SourcePostCommit,
+	}
+	require.NoError(t, InsertEvent(db, event))
+
+	events, err := ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	id := events[0].ID
+
+	require.NoError(t, SetEventNote(db, id, "prod hotfix"))
+
+	// Recording the same commit again keeps the note
+	require.NoError(t, InsertEvent(db, event))
+
+	events, err = ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	require.Equal(t, "prod hotfix", events[0].Note)
+	require.Equal(t, StatusPending, events[0].Status, "the export row is rewritten with the note")
+
+	require.NoError(t, SetEventNote(db, id, ""))
+	events, err = ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	require.Empty(t, events[0].Note)
+
+	require.Error(t, SetEventNote(db, id+1, "missing"))
+}
+
+func TestSetEventTag(t *testing.T) {
+	db := newTestDB(t)
+	event := RepoEvent{
+		RepoID: "github.com/user/repo", Commit: "aaa", Branch: "main",
+		Timestamp: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC),
+		Status:    StatusExported, Source: SourcePostCommit,
+	}
+	require.NoError(t, InsertEvent(db, event))
+
+	events, err := ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	id := events[0].ID
+
+	require.NoError(t, SetEventTag(db, id, "pairing"))
+	events, err = ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	require.Equal(t, "pairing", events[0].Tag)
+	require.Equal(t, StatusPending, events[0].Status, "the export row is rewritten with the tag")
+
+	require.NoError(t, SetEventTag(db, id, ""))
+	events, err = ListEvents(db, EventFilter{})
+	require.NoError(t, err)
+	require.Empty(t, events[0].Tag)
+
+	require.Error(t, SetEventTag(db, id+1, "missing"))
+}
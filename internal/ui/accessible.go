@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/footprint-tools/cli/internal/config"
+)
+
+// accessibleFlag is set by --accessible for the current process.
+var accessibleFlag atomic.Bool
+
+// ErrCancelled is returned by LinearPrompt when the user answers with an
+// empty line or input ends.
+var ErrCancelled = errors.New("cancelled")
+
+// EnableAccessible turns on accessible mode for this process (used by
+// --accessible). In accessible mode interactive commands ask numbered
+// questions on plain lines instead of drawing a full-screen TUI, which
+// screen readers follow much better.
+func EnableAccessible() {
+	accessibleFlag.Store(true)
+}
+
+// IsAccessible reports whether accessible mode is on, either by
+// --accessible, FP_ACCESSIBLE or accessible=true in the config file.
+func IsAccessible() bool {
+	if accessibleFlag.Load() {
+		return true
+	}
+	if isTrueValue(os.Getenv("FP_ACCESSIBLE")) {
+		return true
+	}
+	value, _ := config.Get("accessible")
+	return isTrueValue(value)
+}
+
+func isTrueValue(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+// LinearPrompt asks questions one line at a time: a numbered menu or a
+// single value, read from in and written to out with no styling or cursor
+// movement.
+type LinearPrompt struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewLinearPrompt returns a prompt reading answers from in and writing
+// questions to out.
+func NewLinearPrompt(in io.Reader, out io.Writer) *LinearPrompt {
+	return &LinearPrompt{in: bufio.NewReader(in), out: out}
+}
+
+// NewTerminalPrompt returns a prompt on stdin, writing to stderr so the
+// questions stay out of piped output.
+func NewTerminalPrompt() *LinearPrompt {
+	return NewLinearPrompt(os.Stdin, os.Stderr)
+}
+
+// Choose prints title and options as a numbered list and returns the index
+// of the option picked, by number, by its text or by the text's first word
+// ("pager" for "pager = less"). Other answers ask again; an empty answer
+// returns ErrCancelled.
+func (p *LinearPrompt) Choose(title string, options []string) (int, error) {
+	if len(options) == 0 {
+		return 0, fmt.Errorf("%s: nothing to choose from", title)
+	}
+	_, _ = fmt.Fprintln(p.out, title)
+	for i, opt := range options {
+		_, _ = fmt.Fprintf(p.out, "  %d. %s\n", i+1, opt)
+	}
+	for {
+		answer, err := p.Ask(fmt.Sprintf("Choose 1-%d (Enter to cancel)", len(options)))
+		if err != nil {
+			return 0, err
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+		for i, opt := range options {
+			if answer == opt || strings.HasPrefix(opt, answer+" ") {
+				return i, nil
+			}
+		}
+		_, _ = fmt.Fprintf(p.out, "'%s' is not one of the choices\n", answer)
+	}
+}
+
+// Ask prints label and returns the line typed, trimmed. An empty answer
+// returns ErrCancelled.
+func (p *LinearPrompt) Ask(label string) (string, error) {
+	_, _ = fmt.Fprintf(p.out, "%s: ", label)
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		_, _ = fmt.Fprintln(p.out)
+		if err == io.EOF {
+			return "", ErrCancelled
+		}
+		return "", err
+	}
+	answer := strings.TrimSpace(line)
+	if answer == "" {
+		return "", ErrCancelled
+	}
+	return answer, nil
+}
+
+// Println writes a plain line to the prompt's output.
+func (p *LinearPrompt) Println(args ...any) {
+	_, _ = fmt.Fprintln(p.out, args...)
+}
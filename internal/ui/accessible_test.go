@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinearPrompt_ChooseByNumber(t *testing.T) {
+	var out bytes.Buffer
+	p := NewLinearPrompt(strings.NewReader("2\n"), &out)
+
+	i, err := p.Choose("Pick a theme", []string{"neon-dark", "ocean-dark"})
+
+	require.NoError(t, err)
+	require.Equal(t, 1, i)
+	require.Contains(t, out.String(), "Pick a theme\n  1. neon-dark\n  2. ocean-dark\n")
+}
+
+func TestLinearPrompt_ChooseByNameAfterBadAnswer(t *testing.T) {
+	var out bytes.Buffer
+	p := NewLinearPrompt(strings.NewReader("7\nocean-dark\n"), &out)
+
+	i, err := p.Choose("Pick a theme", []string{"neon-dark", "ocean-dark"})
+
+	require.NoError(t, err)
+	require.Equal(t, 1, i)
+	require.Contains(t, out.String(), "'7' is not one of the choices")
+}
+
+func TestLinearPrompt_EmptyOrEOFCancels(t *testing.T) {
+	for _, input := range []string{"\n", ""} {
+		p := NewLinearPrompt(strings.NewReader(input), &bytes.Buffer{})
+		_, err := p.Choose("Pick", []string{"a"})
+		require.ErrorIs(t, err, ErrCancelled)
+	}
+}
+
+func TestLinearPrompt_AskWithoutNewline(t *testing.T) {
+	p := NewLinearPrompt(strings.NewReader("  value "), &bytes.Buffer{})
+
+	answer, err := p.Ask("Value")
+
+	require.NoError(t, err)
+	require.Equal(t, "value", answer)
+}
+
+func TestEnableAccessible(t *testing.T) {
+	t.Cleanup(func() { accessibleFlag.Store(false) })
+
+	EnableAccessible()
+
+	require.True(t, IsAccessible())
+}
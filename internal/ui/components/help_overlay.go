@@ -0,0 +1,170 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/footprint-tools/cli/internal/ui/text"
+)
+
+// HelpSection is a titled group of keys in the help overlay.
+type HelpSection struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+// HelpKey returns a help entry for input that isn't a key binding, such
+// as typing or the mouse ("type", "click/wheel"). Keys are listed from
+// the views' own bindings.
+func HelpKey(input, desc string) key.Binding {
+	return key.NewBinding(key.WithKeys(input), key.WithHelp(input, desc))
+}
+
+// Describe returns b with desc as its help text, for a key that does
+// something different in each panel.
+func Describe(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// HelpProvider is implemented by full-screen models that list their keys
+// in the help overlay.
+type HelpProvider interface {
+	// HelpSections returns every key of the current view, the focused
+	// panel's first. The footer only has room for a few of them.
+	HelpSections() []HelpSection
+	// CapturingInput reports whether keys go to a text field, where ? is
+	// typed rather than opening the overlay.
+	CapturingInput() bool
+}
+
+// HelpOverlay wraps a full-screen model and shows its full keymap when ?
+// is pressed. ?, Esc or q close it again; j/k scroll when it doesn't fit.
+// While it shows, keys and mouse events are held back from the model,
+// which still gets resizes and every other message.
+type HelpOverlay struct {
+	Model tea.Model
+
+	open   bool
+	scroll int
+	width  int
+	height int
+}
+
+// NewHelpOverlay wraps model, which should implement HelpProvider. Callers
+// that need the final model after Run read it back from the Model field.
+func NewHelpOverlay(model tea.Model) HelpOverlay {
+	return HelpOverlay{Model: model}
+}
+
+// Open reports whether the overlay is showing.
+func (h HelpOverlay) Open() bool {
+	return h.open
+}
+
+func (h HelpOverlay) Init() tea.Cmd {
+	return h.Model.Init()
+}
+
+func (h HelpOverlay) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h.width, h.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		if h.open {
+			switch msg.String() {
+			case "ctrl+c":
+				return h, tea.Quit
+			case "?", "esc", "q":
+				h.open = false
+			case "j", "down":
+				h.scroll = min(h.scroll+1, h.maxScroll())
+			case "k", "up":
+				h.scroll = max(h.scroll-1, 0)
+			}
+			return h, nil
+		}
+		if msg.String() == "?" {
+			if p, ok := h.Model.(HelpProvider); ok && !p.CapturingInput() {
+				h.open = true
+				h.scroll = 0
+				return h, nil
+			}
+		}
+	case tea.MouseMsg:
+		if h.open {
+			return h, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	h.Model, cmd = h.Model.Update(msg)
+	return h, cmd
+}
+
+func (h HelpOverlay) View() string {
+	if !h.open {
+		return h.Model.View()
+	}
+
+	colors := style.GetColors()
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(colors.Border)).
+		Padding(0, 1)
+
+	lines := h.lines()
+	visible := max(h.height-2, 1)
+	if len(lines) > visible {
+		lines = lines[h.scroll:min(h.scroll+visible, len(lines))]
+	}
+	width := max(h.width-4, 1)
+	for i, line := range lines {
+		lines[i] = text.Truncate(line, width)
+	}
+
+	box := borderStyle.Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(h.width, h.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// lines renders the sections of the wrapped model, one key per line.
+func (h HelpOverlay) lines() []string {
+	colors := style.GetColors()
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Header)).Bold(true)
+	keyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Info))
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Muted))
+
+	p, ok := h.Model.(HelpProvider)
+	if !ok {
+		return nil
+	}
+	sections := p.HelpSections()
+
+	keyWidth := 0
+	for _, s := range sections {
+		for _, b := range s.Bindings {
+			keyWidth = max(keyWidth, lipgloss.Width(b.Help().Key))
+		}
+	}
+
+	lines := []string{titleStyle.Render("Keys"), ""}
+	for _, s := range sections {
+		lines = append(lines, titleStyle.Render(s.Title))
+		for _, b := range s.Bindings {
+			if !b.Enabled() || b.Help().Key == "" {
+				continue
+			}
+			pad := strings.Repeat(" ", keyWidth-lipgloss.Width(b.Help().Key))
+			lines = append(lines, "  "+keyStyle.Render(b.Help().Key)+pad+"  "+b.Help().Desc)
+		}
+		lines = append(lines, "")
+	}
+	return append(lines, mutedStyle.Render("? or Esc to close"))
+}
+
+func (h HelpOverlay) maxScroll() int {
+	return max(len(h.lines())-max(h.height-2, 1), 0)
+}
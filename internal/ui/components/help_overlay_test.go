@@ -0,0 +1,140 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpProbe is a model with a keymap; typing mimics a text field.
+type helpProbe struct {
+	sizeProbe
+	typing bool
+	rows   int
+}
+
+func (p helpProbe) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := p.sizeProbe.Update(msg)
+	p.sizeProbe = next.(sizeProbe)
+	return p, cmd
+}
+
+func (p helpProbe) HelpSections() []HelpSection {
+	s := HelpSection{Title: "List", Bindings: []key.Binding{HelpKey("j/k", "move"), HelpKey("Enter", "open")}}
+	for i := range p.rows {
+		s.Bindings = append(s.Bindings, HelpKey(fmt.Sprint(i), fmt.Sprintf("row %d", i)))
+	}
+	return []HelpSection{s}
+}
+
+func (p helpProbe) CapturingInput() bool { return p.typing }
+
+func sendHelp(h HelpOverlay, msg tea.Msg) HelpOverlay {
+	next, _ := h.Update(msg)
+	return next.(HelpOverlay)
+}
+
+func runeKey(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestHelpOverlay(t *testing.T) {
+	h := NewHelpOverlay(helpProbe{})
+	h = sendHelp(h, tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	h = sendHelp(h, runeKey("?"))
+	if !h.Open() {
+		t.Fatal("? did not open the overlay")
+	}
+	view := h.View()
+	for _, want := range []string{"Keys", "List", "j/k", "open", "? or Esc to close"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() is missing %q:\n%s", want, view)
+		}
+	}
+
+	// Keys are held back while it shows
+	h = sendHelp(h, runeKey("j"))
+	if keys := h.Model.(helpProbe).keys; keys != 0 {
+		t.Errorf("wrapped model got %d keys while the overlay showed, want 0", keys)
+	}
+
+	h = sendHelp(h, tea.KeyMsg{Type: tea.KeyEsc})
+	if h.Open() {
+		t.Fatal("Esc did not close the overlay")
+	}
+	if view := h.View(); view != "full view" {
+		t.Errorf("View() after closing = %q, want the model's view", view)
+	}
+
+	h = sendHelp(h, runeKey("j"))
+	if keys := h.Model.(helpProbe).keys; keys != 1 {
+		t.Errorf("wrapped model got %d keys after closing, want 1", keys)
+	}
+}
+
+func TestHelpOverlay_TypingKeepsQuestionMark(t *testing.T) {
+	h := NewHelpOverlay(helpProbe{typing: true})
+	h = sendHelp(h, runeKey("?"))
+	if h.Open() {
+		t.Error("? opened the overlay while a text field had focus")
+	}
+	if keys := h.Model.(helpProbe).keys; keys != 1 {
+		t.Errorf("wrapped model got %d keys, want the ? typed", keys)
+	}
+
+	// Models without a keymap keep ? too
+	h = NewHelpOverlay(sizeProbe{})
+	h = sendHelp(h, runeKey("?"))
+	if h.Open() {
+		t.Error("? opened the overlay for a model without HelpSections")
+	}
+}
+
+func TestHelpOverlay_ScrollsWhenTooTall(t *testing.T) {
+	h := NewHelpOverlay(helpProbe{rows: 40})
+	h = sendHelp(h, tea.WindowSizeMsg{Width: 60, Height: 15})
+	h = sendHelp(h, runeKey("?"))
+
+	lines := strings.Split(h.View(), "\n")
+	if len(lines) > 15 {
+		t.Errorf("View() is %d lines in a 15 line window", len(lines))
+	}
+	for _, line := range lines {
+		if w := lipgloss.Width(line); w > 60 {
+			t.Errorf("line is %d wide in a 60 column window: %q", w, line)
+		}
+	}
+
+	if !strings.Contains(h.View(), "row 0 ") {
+		t.Error("the first rows are not visible before scrolling")
+	}
+
+	for range 100 {
+		h = sendHelp(h, runeKey("j"))
+	}
+	if !strings.Contains(h.View(), "? or Esc to close") {
+		t.Error("scrolling to the end does not reach the last line")
+	}
+	if strings.Contains(h.View(), "row 0 ") {
+		t.Error("the first rows are still visible after scrolling")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	b := NewListKeyMap().UpDown
+	scroll := Describe(b, "scroll")
+	if scroll.Help().Key != "↑/k ↓/j" || scroll.Help().Desc != "scroll" {
+		t.Errorf("Describe() help = %+v", scroll.Help())
+	}
+	if !key.Matches(runeKey("j"), scroll) {
+		t.Error("Describe() dropped the keys of the binding")
+	}
+	if b.Help().Desc != "move" {
+		t.Errorf("Describe() changed the original binding: %+v", b.Help())
+	}
+}
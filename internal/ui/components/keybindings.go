@@ -87,6 +87,43 @@ func NewCommonKeyMap() CommonKeyMap {
 	}
 }
 
+// ListKeyMap is the keys that move through a list or scroll a panel in
+// the full-screen views. Views Describe them per panel, since the same
+// key moves in one panel and scrolls in another.
+type ListKeyMap struct {
+	UpDown    key.Binding
+	Page      key.Binding
+	FirstLast key.Binding
+	Help      key.Binding
+	ForceQuit key.Binding
+}
+
+// NewListKeyMap returns the list keys.
+func NewListKeyMap() ListKeyMap {
+	return ListKeyMap{
+		UpDown: key.NewBinding(
+			key.WithKeys("up", "k", "down", "j"),
+			key.WithHelp("↑/k ↓/j", "move"),
+		),
+		Page: key.NewBinding(
+			key.WithKeys("pgup", "pgdown"),
+			key.WithHelp("PgUp/PgDn", "move a page"),
+		),
+		FirstLast: key.NewBinding(
+			key.WithKeys("g", "G"),
+			key.WithHelp("g/G", "first / last"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "this help"),
+		),
+		ForceQuit: key.NewBinding(
+			key.WithKeys("ctrl+c"),
+			key.WithHelp("Ctrl+C", "quit"),
+		),
+	}
+}
+
 // NavigationBindings returns bindings for list navigation.
 func NavigationBindings() []key.Binding {
 	km := NewCommonKeyMap()
@@ -0,0 +1,104 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	"github.com/footprint-tools/cli/internal/ui/text"
+)
+
+// The smallest terminal the full-screen views are laid out for. Below it
+// panels collapse to nothing and lines wrap into each other.
+const (
+	MinWidth  = 60
+	MinHeight = 15
+)
+
+// SizeGuard wraps a full-screen model and, while the terminal is smaller
+// than MinWidth x MinHeight, shows a "window too small" notice instead of
+// the model's view. The wrapped model still gets every resize and every
+// non-input message, so it lays itself out again as soon as the window is
+// big enough. Keys and mouse events are held back while the notice shows,
+// except q, Esc and Ctrl+C, which quit.
+type SizeGuard struct {
+	Model tea.Model
+
+	minWidth  int
+	minHeight int
+	width     int
+	height    int
+}
+
+// NewSizeGuard wraps model with the default minimum size. Callers that
+// need the final model after Run read it back from the Model field.
+func NewSizeGuard(model tea.Model) SizeGuard {
+	return SizeGuard{Model: model, minWidth: MinWidth, minHeight: MinHeight}
+}
+
+// TooSmall reports whether the last known window size is below the
+// minimum. Before the first resize the size is unknown and it is false.
+func (g SizeGuard) TooSmall() bool {
+	if g.width == 0 && g.height == 0 {
+		return false
+	}
+	return g.width < g.minWidth || g.height < g.minHeight
+}
+
+func (g SizeGuard) Init() tea.Cmd {
+	return g.Model.Init()
+}
+
+func (g SizeGuard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		g.width, g.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		if g.TooSmall() {
+			switch msg.String() {
+			case "q", "esc", "ctrl+c":
+				return g, tea.Quit
+			}
+			return g, nil
+		}
+	case tea.MouseMsg:
+		if g.TooSmall() {
+			return g, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	g.Model, cmd = g.Model.Update(msg)
+	return g, cmd
+}
+
+func (g SizeGuard) View() string {
+	if !g.TooSmall() {
+		return g.Model.View()
+	}
+
+	colors := style.GetColors()
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Warning)).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Muted))
+
+	lines := []string{
+		"Window too small",
+		fmt.Sprintf("need %dx%d, have %dx%d", g.minWidth, g.minHeight, g.width, g.height),
+		"q to quit",
+	}
+	// Keep what fits; the first line says the most
+	lines = lines[:min(len(lines), max(g.height, 1))]
+	for i, line := range lines {
+		line = text.Truncate(line, max(g.width, 1))
+		if i == 0 {
+			lines[i] = titleStyle.Render(line)
+		} else {
+			lines[i] = mutedStyle.Render(line)
+		}
+	}
+
+	block := lipgloss.NewStyle().Align(lipgloss.Center).Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(g.width, g.height, lipgloss.Center, lipgloss.Center, block)
+}
@@ -0,0 +1,91 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sizeProbe records what the guard passes on.
+type sizeProbe struct {
+	width, height int
+	keys          int
+}
+
+func (p sizeProbe) Init() tea.Cmd { return nil }
+
+func (p sizeProbe) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.width, p.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		p.keys++
+	}
+	return p, nil
+}
+
+func (p sizeProbe) View() string { return "full view" }
+
+func sendGuard(g SizeGuard, msg tea.Msg) (SizeGuard, tea.Cmd) {
+	next, cmd := g.Update(msg)
+	return next.(SizeGuard), cmd
+}
+
+func TestSizeGuard(t *testing.T) {
+	g := NewSizeGuard(sizeProbe{})
+	if g.TooSmall() {
+		t.Error("TooSmall() before the first resize, want false")
+	}
+
+	g, _ = sendGuard(g, tea.WindowSizeMsg{Width: 30, Height: 8})
+	if !g.TooSmall() {
+		t.Fatal("TooSmall() at 30x8, want true")
+	}
+	if probe := g.Model.(sizeProbe); probe.width != 30 || probe.height != 8 {
+		t.Errorf("wrapped model got %dx%d, want 30x8", probe.width, probe.height)
+	}
+
+	view := g.View()
+	if !strings.Contains(view, "Window too small") || !strings.Contains(view, "need 60x15, have 30x8") {
+		t.Errorf("View() = %q, want the too-small notice", view)
+	}
+	for _, line := range strings.Split(view, "\n") {
+		if w := lipgloss.Width(line); w > 30 {
+			t.Errorf("notice line is %d wide in a 30 column window: %q", w, line)
+		}
+	}
+
+	// Keys are held back, except quitting
+	g, cmd := sendGuard(g, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if cmd != nil || g.Model.(sizeProbe).keys != 0 {
+		t.Error("key passed on while the window is too small")
+	}
+	if _, cmd := sendGuard(g, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}}); cmd == nil {
+		t.Error("q doesn't quit while the window is too small")
+	}
+
+	// Growing the window brings the view back
+	g, _ = sendGuard(g, tea.WindowSizeMsg{Width: 120, Height: 40})
+	if g.TooSmall() || g.View() != "full view" {
+		t.Errorf("View() at 120x40 = %q, want the wrapped view", g.View())
+	}
+	g, _ = sendGuard(g, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if g.Model.(sizeProbe).keys != 1 {
+		t.Error("key not passed on once the window is big enough")
+	}
+}
+
+func TestSizeGuard_TinyWindow(t *testing.T) {
+	g := NewSizeGuard(sizeProbe{})
+	g, _ = sendGuard(g, tea.WindowSizeMsg{Width: 5, Height: 1})
+
+	view := g.View()
+	if lines := strings.Split(view, "\n"); len(lines) != 1 {
+		t.Errorf("View() has %d lines in a 1 row window: %q", len(lines), view)
+	}
+	if w := lipgloss.Width(view); w > 5 {
+		t.Errorf("View() is %d wide in a 5 column window: %q", w, view)
+	}
+}
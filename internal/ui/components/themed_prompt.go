@@ -0,0 +1,220 @@
+package components
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// maxPromptChoices is how many choices a prompt shows at once.
+const maxPromptChoices = 8
+
+// ErrPromptCancelled is returned by RunPrompt when the user presses Esc or
+// Ctrl+C.
+var ErrPromptCancelled = errors.New("cancelled")
+
+// PromptResult is sent when the user answers or cancels a prompt.
+type PromptResult struct {
+	Value     string
+	Cancelled bool
+	ID        string // Optional ID to identify which prompt this is
+}
+
+// ThemedPrompt asks for one value. With choices it works as a selector
+// filtered by what is typed; without, it is a plain text input.
+type ThemedPrompt struct {
+	label    string
+	id       string
+	input    ThemedInput
+	choices  []string
+	filtered []string
+	cursor   int
+	colors   style.ColorConfig
+}
+
+// NewThemedPrompt creates a prompt labelled with label.
+func NewThemedPrompt(label, placeholder string) ThemedPrompt {
+	input := NewThemedInputWithPrompt(placeholder, "> ")
+	input.Focus()
+	return ThemedPrompt{
+		label:  label,
+		input:  input,
+		colors: style.GetColors(),
+	}
+}
+
+// WithChoices turns the prompt into a selector over choices.
+func (p ThemedPrompt) WithChoices(choices []string) ThemedPrompt {
+	p.choices = choices
+	p.filtered = choices
+	p.cursor = 0
+	return p
+}
+
+// WithID sets an identifier for this prompt.
+func (p ThemedPrompt) WithID(id string) ThemedPrompt {
+	p.id = id
+	return p
+}
+
+// Init implements tea.Model.
+func (p ThemedPrompt) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (p ThemedPrompt) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		return p, p.result(PromptResult{Cancelled: true})
+
+	case tea.KeyEnter:
+		value := strings.TrimSpace(p.input.Value())
+		if len(p.choices) > 0 {
+			if len(p.filtered) == 0 {
+				return p, nil
+			}
+			value = p.filtered[p.cursor]
+		}
+		if value == "" {
+			return p, nil
+		}
+		return p, p.result(PromptResult{Value: value})
+
+	case tea.KeyUp, tea.KeyShiftTab:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return p, nil
+
+	case tea.KeyDown, tea.KeyTab:
+		if p.cursor < len(p.filtered)-1 {
+			p.cursor++
+		}
+		return p, nil
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.filter()
+	return p, cmd
+}
+
+func (p ThemedPrompt) result(r PromptResult) tea.Cmd {
+	r.ID = p.id
+	return func() tea.Msg { return r }
+}
+
+// filter keeps the choices containing the typed text.
+func (p *ThemedPrompt) filter() {
+	if len(p.choices) == 0 {
+		return
+	}
+	query := strings.ToLower(strings.TrimSpace(p.input.Value()))
+	p.filtered = p.filtered[:0:0]
+	for _, c := range p.choices {
+		if strings.Contains(strings.ToLower(c), query) {
+			p.filtered = append(p.filtered, c)
+		}
+	}
+	p.cursor = min(p.cursor, max(len(p.filtered)-1, 0))
+}
+
+// View implements tea.Model.
+func (p ThemedPrompt) View() string {
+	colors := p.colors
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(colors.Info))
+	mutedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Muted))
+	activeStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(colors.UIActive))
+
+	var b strings.Builder
+	b.WriteString(labelStyle.Render(p.label))
+	b.WriteString("\n")
+	b.WriteString(p.input.View())
+	b.WriteString("\n")
+
+	if len(p.choices) > 0 {
+		start := max(0, p.cursor-maxPromptChoices+1)
+		end := min(len(p.filtered), start+maxPromptChoices)
+		for i := start; i < end; i++ {
+			if i == p.cursor {
+				b.WriteString(activeStyle.Render("› " + p.filtered[i]))
+			} else {
+				b.WriteString(mutedStyle.Render("  " + p.filtered[i]))
+			}
+			b.WriteString("\n")
+		}
+		if len(p.filtered) == 0 {
+			b.WriteString(mutedStyle.Render("  no matches"))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString(mutedStyle.Render("Enter to confirm, Esc to cancel"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// RefreshColors updates the prompt styling from the current theme.
+func (p *ThemedPrompt) RefreshColors() {
+	p.colors = style.GetColors()
+	p.input.RefreshColors()
+}
+
+// PromptKeyBindings returns keybindings for the prompt.
+func PromptKeyBindings() []key.Binding {
+	return []key.Binding{
+		key.NewBinding(key.WithKeys("↑", "↓"), key.WithHelp("↑/↓", "select")),
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "confirm")),
+		key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "cancel")),
+	}
+}
+
+// promptRunner ends the program once the prompt is answered.
+type promptRunner struct {
+	prompt ThemedPrompt
+	result PromptResult
+}
+
+func (r promptRunner) Init() tea.Cmd { return r.prompt.Init() }
+
+func (r promptRunner) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if res, ok := msg.(PromptResult); ok {
+		r.result = res
+		return r, tea.Quit
+	}
+	m, cmd := r.prompt.Update(msg)
+	r.prompt = m.(ThemedPrompt)
+	return r, cmd
+}
+
+func (r promptRunner) View() string {
+	if r.result.Value != "" || r.result.Cancelled {
+		return ""
+	}
+	return r.prompt.View()
+}
+
+// RunPrompt shows p inline on stderr, keeping stdout clean for the command's
+// own output, and returns the answer.
+func RunPrompt(p ThemedPrompt) (string, error) {
+	final, err := tea.NewProgram(promptRunner{prompt: p}, tea.WithOutput(os.Stderr)).Run()
+	if err != nil {
+		return "", err
+	}
+	res := final.(promptRunner).result
+	if res.Cancelled || res.Value == "" {
+		return "", ErrPromptCancelled
+	}
+	return res.Value, nil
+}
@@ -0,0 +1,69 @@
+package components
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// send feeds msg to the prompt and returns the result it emits, if any.
+func send(p ThemedPrompt, msg tea.Msg) (ThemedPrompt, *PromptResult) {
+	m, cmd := p.Update(msg)
+	p = m.(ThemedPrompt)
+	if cmd == nil {
+		return p, nil
+	}
+	if res, ok := cmd().(PromptResult); ok {
+		return p, &res
+	}
+	return p, nil
+}
+
+// typeText types s into the prompt. The commands it returns only blink the
+// cursor, so they are dropped.
+func typeText(p ThemedPrompt, s string) ThemedPrompt {
+	for _, r := range s {
+		m, _ := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		p = m.(ThemedPrompt)
+	}
+	return p
+}
+
+func TestThemedPromptText(t *testing.T) {
+	p := NewThemedPrompt("fp config get: Configuration key", "key").WithID("key")
+
+	if _, res := send(p, tea.KeyMsg{Type: tea.KeyEnter}); res != nil {
+		t.Errorf("Enter on an empty prompt should not answer, got %+v", res)
+	}
+
+	p = typeText(p, "theme")
+	_, res := send(p, tea.KeyMsg{Type: tea.KeyEnter})
+	if res == nil || res.Value != "theme" || res.ID != "key" {
+		t.Errorf("Expected answer 'theme' with id 'key', got %+v", res)
+	}
+}
+
+func TestThemedPromptChoices(t *testing.T) {
+	p := NewThemedPrompt("Theme", "name").WithChoices([]string{"default", "neon", "neon-dark", "ocean"})
+
+	p = typeText(p, "neo")
+	m, _ := p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	p = m.(ThemedPrompt)
+	_, res := send(p, tea.KeyMsg{Type: tea.KeyEnter})
+	if res == nil || res.Value != "neon-dark" {
+		t.Errorf("Expected the second match 'neon-dark', got %+v", res)
+	}
+
+	p = typeText(NewThemedPrompt("Theme", "name").WithChoices([]string{"default"}), "zzz")
+	if _, res := send(p, tea.KeyMsg{Type: tea.KeyEnter}); res != nil {
+		t.Errorf("Enter with no matching choice should not answer, got %+v", res)
+	}
+}
+
+func TestThemedPromptCancel(t *testing.T) {
+	p := NewThemedPrompt("Theme", "name")
+	_, res := send(p, tea.KeyMsg{Type: tea.KeyEsc})
+	if res == nil || !res.Cancelled {
+		t.Errorf("Expected Esc to cancel, got %+v", res)
+	}
+}
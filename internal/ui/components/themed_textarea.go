@@ -0,0 +1,103 @@
+package components
+
+import (
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/ui/style"
+)
+
+// ThemedTextArea wraps bubbles/textarea with theme-aware styling for short
+// multi-line text such as event notes.
+type ThemedTextArea struct {
+	Model textarea.Model
+}
+
+// NewThemedTextArea creates a text area styled with the current theme.
+func NewThemedTextArea(placeholder string) ThemedTextArea {
+	ta := textarea.New()
+	ta.Placeholder = placeholder
+	ta.ShowLineNumbers = false
+	ta.Prompt = "│ "
+	ta.CharLimit = 1000
+	ta.SetHeight(4)
+
+	t := ThemedTextArea{Model: ta}
+	t.RefreshColors()
+	return t
+}
+
+// Focus sets the text area to focused state.
+func (t *ThemedTextArea) Focus() tea.Cmd {
+	return t.Model.Focus()
+}
+
+// Blur removes focus from the text area.
+func (t *ThemedTextArea) Blur() {
+	t.Model.Blur()
+}
+
+// Focused returns whether the text area is focused.
+func (t ThemedTextArea) Focused() bool {
+	return t.Model.Focused()
+}
+
+// SetValue sets the text, leaving the cursor at the end.
+func (t *ThemedTextArea) SetValue(s string) {
+	t.Model.SetValue(s)
+}
+
+// Value returns the current text.
+func (t ThemedTextArea) Value() string {
+	return t.Model.Value()
+}
+
+// Reset clears the text.
+func (t *ThemedTextArea) Reset() {
+	t.Model.Reset()
+}
+
+// SetSize sets the width and height of the text area.
+func (t *ThemedTextArea) SetSize(width, height int) {
+	t.Model.SetWidth(width)
+	t.Model.SetHeight(height)
+}
+
+// Update handles a tea.Msg and returns updated model and command.
+func (t ThemedTextArea) Update(msg tea.Msg) (ThemedTextArea, tea.Cmd) {
+	var cmd tea.Cmd
+	t.Model, cmd = t.Model.Update(msg)
+	return t, cmd
+}
+
+// View renders the text area.
+func (t ThemedTextArea) View() string {
+	return t.Model.View()
+}
+
+// RefreshColors updates the text area styling from the current theme.
+// Call this if the theme changes during the session.
+func (t *ThemedTextArea) RefreshColors() {
+	colors := style.GetColors()
+	muted := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Muted))
+	text := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.Info))
+	active := lipgloss.NewStyle().Foreground(lipgloss.Color(colors.UIActive))
+
+	t.Model.FocusedStyle = textarea.Style{
+		Base:        lipgloss.NewStyle(),
+		CursorLine:  text,
+		EndOfBuffer: muted,
+		Placeholder: muted,
+		Prompt:      active,
+		Text:        text,
+	}
+	t.Model.BlurredStyle = textarea.Style{
+		Base:        lipgloss.NewStyle(),
+		CursorLine:  muted,
+		EndOfBuffer: muted,
+		Placeholder: muted,
+		Prompt:      muted,
+		Text:        muted,
+	}
+	t.Model.Cursor.Style = active
+}
@@ -0,0 +1,34 @@
+package components
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestThemedTextArea(t *testing.T) {
+	ta := NewThemedTextArea("Add a note")
+	ta.Focus()
+
+	for _, r := range "prod" {
+		ta, _ = ta.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	ta, _ = ta.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	for _, r := range "hotfix" {
+		ta, _ = ta.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	if got, want := ta.Value(), "prod\nhotfix"; got != want {
+		t.Errorf("Value() = %q, want %q", got, want)
+	}
+
+	ta.SetValue("replaced")
+	if got := ta.Value(); got != "replaced" {
+		t.Errorf("Value() after SetValue = %q", got)
+	}
+
+	ta.Reset()
+	if got := ta.Value(); got != "" {
+		t.Errorf("Value() after Reset = %q, want empty", got)
+	}
+}
@@ -0,0 +1,112 @@
+package components
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/footprint-tools/cli/internal/ui/style"
+	overlay "github.com/rmhubbert/bubbletea-overlay"
+)
+
+// ToastKind picks the color of a toast.
+type ToastKind int
+
+const (
+	ToastInfo ToastKind = iota
+	ToastSuccess
+	ToastError
+)
+
+// toastMaxWidth keeps long results (a push error) from covering the view.
+const toastMaxWidth = 60
+
+// ToastExpiredMsg hides the toast shown by Flash once its time is up.
+type ToastExpiredMsg struct {
+	id int
+}
+
+// ThemedToast is a short message drawn over the bottom right corner of a
+// view: the steps of a running operation, then its result.
+type ThemedToast struct {
+	lines   []string
+	kind    ToastKind
+	id      int
+	visible bool
+	colors  style.ColorConfig
+}
+
+// NewThemedToast creates a hidden toast.
+func NewThemedToast() ThemedToast {
+	return ThemedToast{colors: style.GetColors()}
+}
+
+// Show displays text until the next Show, Flash or Hide.
+func (t *ThemedToast) Show(text string, kind ToastKind) {
+	t.id++
+	t.lines = strings.Split(text, "\n")
+	t.kind = kind
+	t.visible = true
+}
+
+// Flash displays text and returns the command that hides it after d.
+func (t *ThemedToast) Flash(text string, kind ToastKind, d time.Duration) tea.Cmd {
+	t.Show(text, kind)
+	id := t.id
+	return tea.Tick(d, func(time.Time) tea.Msg { return ToastExpiredMsg{id: id} })
+}
+
+// Hide removes the toast.
+func (t *ThemedToast) Hide() {
+	t.id++
+	t.visible = false
+}
+
+// Update hides the toast when its Flash expires. Expiries of toasts shown
+// before the current one are ignored.
+func (t ThemedToast) Update(msg tea.Msg) ThemedToast {
+	if msg, ok := msg.(ToastExpiredMsg); ok && msg.id == t.id {
+		t.visible = false
+	}
+	return t
+}
+
+// Visible reports whether the toast is showing.
+func (t ThemedToast) Visible() bool {
+	return t.visible
+}
+
+// View renders the toast box, or "" when hidden.
+func (t ThemedToast) View() string {
+	if !t.visible {
+		return ""
+	}
+	color := t.colors.Info
+	switch t.kind {
+	case ToastSuccess:
+		color = t.colors.Success
+	case ToastError:
+		color = t.colors.Error
+	}
+
+	width := 0
+	for _, line := range t.lines {
+		width = max(width, lipgloss.Width(line))
+	}
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(color)).
+		Padding(0, 1).
+		Width(min(width, toastMaxWidth) + 2)
+	return box.Render(strings.Join(t.lines, "\n"))
+}
+
+// Render draws the toast over the bottom right of background, above its
+// last line (where the views keep their key help).
+func (t ThemedToast) Render(background string) string {
+	if !t.visible {
+		return background
+	}
+	return overlay.Composite(t.View(), background, overlay.Right, overlay.Bottom, -1, -1)
+}
@@ -0,0 +1,47 @@
+package components
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestThemedToast_FlashExpires(t *testing.T) {
+	toast := NewThemedToast()
+	toast.Flash("Exported 3 events", ToastSuccess, time.Second)
+	first := toast.id
+
+	toast.Show("Pushing to origin...", ToastInfo)
+	toast = toast.Update(ToastExpiredMsg{id: first})
+	if !toast.Visible() {
+		t.Fatal("an older toast's expiry hid the current one")
+	}
+
+	toast.Flash("Exported 3 events", ToastSuccess, time.Second)
+	toast = toast.Update(ToastExpiredMsg{id: toast.id})
+	if toast.Visible() {
+		t.Fatal("toast still visible after its expiry")
+	}
+}
+
+func TestThemedToast_RenderOverBackground(t *testing.T) {
+	background := strings.Repeat(strings.Repeat(".", 40)+"\n", 9) + strings.Repeat(".", 40)
+
+	toast := NewThemedToast()
+	if got := toast.Render(background); got != background {
+		t.Fatal("a hidden toast changed the view")
+	}
+
+	toast.Show("Done", ToastInfo)
+	got := toast.Render(background)
+	if !strings.Contains(got, "Done") {
+		t.Fatalf("toast text missing from\n%s", got)
+	}
+	lines := strings.Split(got, "\n")
+	if len(lines) != 10 {
+		t.Fatalf("render changed the view height to %d lines", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "........") {
+		t.Fatalf("toast drawn at the top: %q", lines[0])
+	}
+}
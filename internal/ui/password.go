@@ -0,0 +1,17 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// ReadPassword prints label to stderr and reads a line from the terminal
+// without echoing it, for passphrases.
+func ReadPassword(label string) (string, error) {
+	fmt.Fprint(os.Stderr, label)
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	return string(password), err
+}
@@ -33,6 +33,9 @@ var (
 	color5Style  lipgloss.Style
 	color6Style  lipgloss.Style
 	color7Style  lipgloss.Style
+	color8Style  lipgloss.Style
+	color9Style  lipgloss.Style
+	color10Style lipgloss.Style
 )
 
 // Init initializes the style package with the given enabled state and config.
@@ -86,6 +89,9 @@ func initStyles(colors ColorConfig) {
 	color5Style = makeStyle(colors.Color5)
 	color6Style = makeStyle(colors.Color6)
 	color7Style = makeStyle(colors.Color7)
+	color8Style = makeStyle(colors.Color8)
+	color9Style = makeStyle(colors.Color9)
+	color10Style = makeStyle(colors.Color10)
 }
 
 // makeStyle creates a lipgloss style from a color value.
@@ -131,11 +137,14 @@ func Muted(text string) string { return render(mutedStyle, text) }
 // Border styles text for interactive delimiters (scrollbars, card borders, etc.).
 func Border(text string) string { return render(borderStyle, text) }
 
-// Color1 through Color7 are neutral colors for visual distinction only.
-func Color1(text string) string { return render(color1Style, text) }
-func Color2(text string) string { return render(color2Style, text) }
-func Color3(text string) string { return render(color3Style, text) }
-func Color4(text string) string { return render(color4Style, text) }
-func Color5(text string) string { return render(color5Style, text) }
-func Color6(text string) string { return render(color6Style, text) }
-func Color7(text string) string { return render(color7Style, text) }
+// Color1 through Color10 are neutral colors for visual distinction only.
+func Color1(text string) string  { return render(color1Style, text) }
+func Color2(text string) string  { return render(color2Style, text) }
+func Color3(text string) string  { return render(color3Style, text) }
+func Color4(text string) string  { return render(color4Style, text) }
+func Color5(text string) string  { return render(color5Style, text) }
+func Color6(text string) string  { return render(color6Style, text) }
+func Color7(text string) string  { return render(color7Style, text) }
+func Color8(text string) string  { return render(color8Style, text) }
+func Color9(text string) string  { return render(color9Style, text) }
+func Color10(text string) string { return render(color10Style, text) }
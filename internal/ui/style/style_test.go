@@ -434,6 +434,7 @@ func TestToUpperSnake(t *testing.T) {
 	}{
 		{"color_success", "COLOR_SUCCESS"},
 		{"color_1", "COLOR_1"},
+		{"source_colors.post_commit", "SOURCE_COLORS_POST_COMMIT"},
 		{"abc", "ABC"},
 		{"", ""},
 	}
@@ -464,6 +465,9 @@ func TestColorFunctions(t *testing.T) {
 		{"Color5", Color5},
 		{"Color6", Color6},
 		{"Color7", Color7},
+		{"Color8", Color8},
+		{"Color9", Color9},
+		{"Color10", Color10},
 	}
 
 	for _, tt := range tests {
@@ -496,6 +500,9 @@ func TestColorFunctions_Disabled(t *testing.T) {
 		{"Color5", Color5},
 		{"Color6", Color6},
 		{"Color7", Color7},
+		{"Color8", Color8},
+		{"Color9", Color9},
+		{"Color10", Color10},
 	}
 
 	for _, tt := range tests {
@@ -527,6 +534,7 @@ func TestSetColorField_AllFields(t *testing.T) {
 		"color_5":       "204",
 		"color_6":       "205",
 		"color_7":       "206",
+		"color_10":      "207",
 	}
 
 	colors := LoadColorConfig(cfg)
@@ -552,6 +560,9 @@ func TestSetColorField_AllFields(t *testing.T) {
 	if colors.Color7 != "206" {
 		t.Errorf("Color7: got %q, want %q", colors.Color7, "206")
 	}
+	if colors.Color10 != "207" {
+		t.Errorf("Color10: got %q, want %q", colors.Color10, "207")
+	}
 }
 
 func TestResolveThemeName_Light(t *testing.T) {
@@ -591,6 +602,48 @@ func TestIsDarkBackground(t *testing.T) {
 	_ = result // Result depends on terminal environment
 }
 
+func TestIsDarkBackground_EnvOverride(t *testing.T) {
+	t.Setenv("FP_BACKGROUND", "light")
+	if IsDarkBackground() {
+		t.Error("FP_BACKGROUND=light should mean a light background")
+	}
+	t.Setenv("FP_BACKGROUND", "Dark")
+	if !IsDarkBackground() {
+		t.Error("FP_BACKGROUND=Dark should mean a dark background")
+	}
+}
+
+func TestPickTheme_Auto(t *testing.T) {
+	cfg := map[string]string{"theme_dark": "neon", "theme_light": "ocean-light"}
+	tests := []struct {
+		background, name, want string
+		cfg                    map[string]string
+	}{
+		{"dark", "auto", "neon-dark", cfg},
+		{"light", "auto", "ocean-light", cfg},
+		{"light", "auto", "default-light", nil},
+		{"dark", "aurora", "aurora-dark", cfg},
+		{"light", "mono-dark", "mono-dark", cfg},
+	}
+	for _, tt := range tests {
+		t.Setenv("FP_BACKGROUND", tt.background)
+		if got := PickTheme(tt.name, tt.cfg); got != tt.want {
+			t.Errorf("PickTheme(%q) on %s = %q, want %q", tt.name, tt.background, got, tt.want)
+		}
+	}
+}
+
+func TestLoadColorConfig_AutoTheme(t *testing.T) {
+	clearColorEnvVars(t)
+	t.Setenv("FP_BACKGROUND", "light")
+
+	colors := LoadColorConfig(map[string]string{"theme": "auto", "theme_dark": "neon-dark"})
+
+	if colors != Themes["default-light"] {
+		t.Errorf("auto on a light background without theme_light should use default-light, got %+v", colors)
+	}
+}
+
 func TestGetColors(t *testing.T) {
 	clearColorEnvVars(t)
 
@@ -632,3 +685,49 @@ func clearColorEnvVars(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadColorConfig_SourceColorOverrides(t *testing.T) {
+	t.Setenv("FP_SOURCE_COLORS_PRE_PUSH", "#f0e442")
+
+	cfg := map[string]string{
+		"theme":                     "default-dark",
+		"color_1":                   "100",
+		"source_colors.post_commit": "#56b4e9",
+		"source_colors.backfill":    "244",
+	}
+
+	colors := LoadColorConfig(cfg)
+
+	// source_colors.* wins over the generic color_N key
+	if colors.Color1 != "#56b4e9" {
+		t.Errorf("Color1 = %q, want %q", colors.Color1, "#56b4e9")
+	}
+	if colors.Color6 != "244" {
+		t.Errorf("Color6 = %q, want %q", colors.Color6, "244")
+	}
+	if colors.Color5 != "#f0e442" {
+		t.Errorf("Color5 = %q, want env value %q", colors.Color5, "#f0e442")
+	}
+	if colors.Color2 != Themes["default-dark"].Color2 {
+		t.Errorf("Color2 = %q, want theme value", colors.Color2)
+	}
+}
+
+func TestColorblindThemeSourceColorsAreDistinct(t *testing.T) {
+	for _, name := range []string{"colorblind-dark", "colorblind-light"} {
+		theme, ok := Themes[name]
+		if !ok {
+			t.Fatalf("theme %s missing", name)
+		}
+		seen := map[string]string{}
+		for field, color := range map[string]string{
+			"Color1": theme.Color1, "Color2": theme.Color2, "Color3": theme.Color3,
+			"Color4": theme.Color4, "Color5": theme.Color5,
+		} {
+			if other, dup := seen[color]; dup {
+				t.Errorf("%s: %s and %s share color %s", name, field, other, color)
+			}
+			seen[color] = field
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package style
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Theme file formats, named by their file extension.
+const (
+	ThemeFormatTOML = "toml"
+	ThemeFormatJSON = "json"
+)
+
+// CustomThemeNames lists the themes loaded from theme files by
+// LoadThemeFiles, sorted.
+var CustomThemeNames []string
+
+// themeFileKeys maps the keys of a theme file to ColorConfig field names,
+// in the order theme files are written.
+var themeFileKeys = []struct{ key, field string }{
+	{"success", "Success"},
+	{"warning", "Warning"},
+	{"error", "Error"},
+	{"info", "Info"},
+	{"muted", "Muted"},
+	{"header", "Header"},
+	{"border", "Border"},
+	{"ui_active", "UIActive"},
+	{"ui_dim", "UIDim"},
+	{"post_commit", "Color1"},
+	{"post_rewrite", "Color2"},
+	{"post_checkout", "Color3"},
+	{"post_merge", "Color4"},
+	{"pre_push", "Color5"},
+	{"backfill", "Color6"},
+	{"manual", "Color7"},
+	{"branch_create", "Color8"},
+	{"branch_delete", "Color9"},
+	{"stash", "Color10"},
+}
+
+// themeBaseKey names the built-in theme a theme file starts from. Colors
+// the file leaves out are taken from it.
+const themeBaseKey = "base"
+
+// defaultThemeBase is the base of theme files without one.
+const defaultThemeBase = "default-dark"
+
+// themeNamePattern is what custom theme names may look like.
+var themeNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// AllThemeNames returns the built-in theme variants followed by the
+// custom themes.
+func AllThemeNames() []string {
+	return append(append([]string{}, ThemeNames...), CustomThemeNames...)
+}
+
+// ThemeFormat returns the format of a theme file from its extension, or ""
+// for files that aren't theme files.
+func ThemeFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return ThemeFormatTOML
+	case ".json":
+		return ThemeFormatJSON
+	}
+	return ""
+}
+
+// ValidateCustomThemeName checks name can name a custom theme: lowercase
+// letters, digits, - and _, and not the name of a built-in theme.
+func ValidateCustomThemeName(name string) error {
+	if !themeNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid theme name '%s': use lowercase letters, digits, - and _", name)
+	}
+	if slices.Contains(BaseThemeNames, name) || slices.Contains(ThemeNames, name) {
+		return fmt.Errorf("'%s' is a built-in theme", name)
+	}
+	return nil
+}
+
+// LoadThemeFiles adds the themes defined by the .toml and .json files in
+// dir to Themes, named after their files, and lists them in
+// CustomThemeNames. Themes loaded before are dropped first, so calling it
+// again picks up changed files. A missing dir is not an error; each file
+// that can't be used is returned as an error and skipped.
+func LoadThemeFiles(dir string) []error {
+	for _, name := range CustomThemeNames {
+		delete(Themes, name)
+	}
+	CustomThemeNames = nil
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return []error{err}
+	}
+
+	var errs []error
+	for _, e := range entries {
+		format := ThemeFormat(e.Name())
+		if e.IsDir() || format == "" {
+			continue
+		}
+		name := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+		if err := ValidateCustomThemeName(name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Name(), err))
+			continue
+		}
+		if _, ok := Themes[name]; ok {
+			errs = append(errs, fmt.Errorf("%s: theme '%s' is already defined by another file", e.Name(), name))
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		theme, err := ParseTheme(data, format)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Name(), err))
+			continue
+		}
+		Themes[name] = theme
+		CustomThemeNames = append(CustomThemeNames, name)
+	}
+	slices.Sort(CustomThemeNames)
+	return errs
+}
+
+// ParseTheme reads a theme file in format. A theme file sets colors by
+// name (success, ui_active, post_commit, ...) as ANSI numbers (0-255) or
+// #hex, header may also be "bold", and base names the built-in theme the
+// colors left out come from (default-dark if unset).
+func ParseTheme(data []byte, format string) (ColorConfig, error) {
+	var values map[string]string
+	var err error
+	switch format {
+	case ThemeFormatTOML:
+		values, err = parseThemeTOML(string(data))
+	case ThemeFormatJSON:
+		values, err = parseThemeJSON(data)
+	default:
+		return ColorConfig{}, fmt.Errorf("unknown theme format '%s'", format)
+	}
+	if err != nil {
+		return ColorConfig{}, err
+	}
+
+	base := defaultThemeBase
+	if b, ok := values[themeBaseKey]; ok {
+		base = b
+		delete(values, themeBaseKey)
+	}
+	theme, ok := Themes[base]
+	if !ok || slices.Contains(CustomThemeNames, base) {
+		return ColorConfig{}, fmt.Errorf("unknown base theme '%s': use one of %s", base, strings.Join(ThemeNames, ", "))
+	}
+
+	for _, k := range themeFileKeys {
+		value, ok := values[k.key]
+		if !ok {
+			continue
+		}
+		delete(values, k.key)
+		if !isThemeColor(value) && (k.key != "header" || value != "bold") {
+			return ColorConfig{}, fmt.Errorf("%s must be an ANSI color (0-255) or #hex, got '%s'", k.key, value)
+		}
+		setColorField(&theme, k.field, value)
+	}
+	if len(values) > 0 {
+		unknown := slices.Sorted(maps.Keys(values))
+		return ColorConfig{}, fmt.Errorf("unknown theme key '%s'", unknown[0])
+	}
+	return theme, nil
+}
+
+// MarshalTheme writes theme as a theme file in format, with every color
+// spelled out so it doesn't depend on a base.
+func MarshalTheme(name string, theme ColorConfig, format string) ([]byte, error) {
+	colors := themeFields(theme)
+	switch format {
+	case ThemeFormatJSON:
+		var b strings.Builder
+		b.WriteString("{\n")
+		for i, k := range themeFileKeys {
+			key, _ := json.Marshal(k.key)
+			value, _ := json.Marshal(colors[k.field])
+			fmt.Fprintf(&b, "  %s: %s", key, value)
+			if i < len(themeFileKeys)-1 {
+				b.WriteString(",")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("}\n")
+		return []byte(b.String()), nil
+
+	case ThemeFormatTOML:
+		var b strings.Builder
+		fmt.Fprintf(&b, "# fp theme %s\n", name)
+		for _, k := range themeFileKeys {
+			fmt.Fprintf(&b, "%s = %s\n", k.key, strconv.Quote(colors[k.field]))
+		}
+		return []byte(b.String()), nil
+	}
+	return nil, fmt.Errorf("unknown theme format '%s'", format)
+}
+
+// themeFields returns the colors of theme by ColorConfig field name.
+func themeFields(c ColorConfig) map[string]string {
+	return map[string]string{
+		"Success": c.Success, "Warning": c.Warning, "Error": c.Error, "Info": c.Info,
+		"Muted": c.Muted, "Header": c.Header, "Border": c.Border,
+		"UIActive": c.UIActive, "UIDim": c.UIDim,
+		"Color1": c.Color1, "Color2": c.Color2, "Color3": c.Color3, "Color4": c.Color4,
+		"Color5": c.Color5, "Color6": c.Color6, "Color7": c.Color7, "Color8": c.Color8,
+		"Color9": c.Color9, "Color10": c.Color10,
+	}
+}
+
+// parseThemeTOML reads the part of TOML theme files use: one key = value
+// per line, values quoted or bare numbers, and # comments. Tables aren't
+// supported, a theme is flat.
+func parseThemeTOML(data string) (map[string]string, error) {
+	values := make(map[string]string)
+	for i, line := range strings.Split(strings.ReplaceAll(data, "\r", ""), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, raw, found := strings.Cut(line, "=")
+		key, raw = strings.TrimSpace(key), strings.TrimSpace(raw)
+		if !found || key == "" {
+			return nil, fmt.Errorf("line %d: expected key = value", i+1)
+		}
+
+		var value string
+		switch {
+		case strings.HasPrefix(raw, `"`):
+			quoted, err := strconv.QuotedPrefix(raw)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: bad string %s", i+1, raw)
+			}
+			value, _ = strconv.Unquote(quoted)
+			raw = raw[len(quoted):]
+		case strings.HasPrefix(raw, "'"):
+			end := strings.Index(raw[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: bad string %s", i+1, raw)
+			}
+			value, raw = raw[1:end+1], raw[end+2:]
+		default:
+			value, _, _ = strings.Cut(raw, "#")
+			value = strings.TrimSpace(value)
+			raw = ""
+		}
+		if rest := strings.TrimSpace(raw); rest != "" && !strings.HasPrefix(rest, "#") {
+			return nil, fmt.Errorf("line %d: unexpected %s after value", i+1, rest)
+		}
+		if _, dup := values[key]; dup {
+			return nil, fmt.Errorf("line %d: %s is set twice", i+1, key)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// parseThemeJSON reads a JSON object of color names to strings or numbers.
+func parseThemeJSON(data []byte) (map[string]string, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	values := make(map[string]string, len(raw))
+	for key, v := range raw {
+		switch v := v.(type) {
+		case string:
+			values[key] = v
+		case float64:
+			values[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return nil, fmt.Errorf("%s must be a string or a number", key)
+		}
+	}
+	return values, nil
+}
+
+// isThemeColor reports whether value is an ANSI 256 color number or a #rgb
+// or #rrggbb hex color.
+func isThemeColor(value string) bool {
+	if hex, ok := strings.CutPrefix(value, "#"); ok {
+		if len(hex) != 3 && len(hex) != 6 {
+			return false
+		}
+		_, err := strconv.ParseUint(hex, 16, 32)
+		return err == nil
+	}
+	n, err := strconv.Atoi(value)
+	return err == nil && n >= 0 && n <= 255
+}
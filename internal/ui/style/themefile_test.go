@@ -0,0 +1,129 @@
+package style
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseTheme_TOML(t *testing.T) {
+	data := `# My theme
+base = "ocean-dark"
+success = "#56b4e9"   # sky blue
+post_commit = 74
+header = 'bold'
+`
+	theme, err := ParseTheme([]byte(data), ThemeFormatTOML)
+	if err != nil {
+		t.Fatalf("ParseTheme: %v", err)
+	}
+	if theme.Success != "#56b4e9" || theme.Color1 != "74" || theme.Header != "bold" {
+		t.Errorf("colors not set: %+v", theme)
+	}
+	if theme.Error != Themes["ocean-dark"].Error {
+		t.Errorf("Error = %q, want ocean-dark's %q", theme.Error, Themes["ocean-dark"].Error)
+	}
+}
+
+func TestParseTheme_JSONDefaultsToDefaultDark(t *testing.T) {
+	theme, err := ParseTheme([]byte(`{"stash": 180, "ui_dim": "240"}`), ThemeFormatJSON)
+	if err != nil {
+		t.Fatalf("ParseTheme: %v", err)
+	}
+	want := Themes["default-dark"]
+	want.Color10, want.UIDim = "180", "240"
+	if theme != want {
+		t.Errorf("theme = %+v, want %+v", theme, want)
+	}
+}
+
+func TestParseTheme_Errors(t *testing.T) {
+	tests := []struct {
+		name, format, data, want string
+	}{
+		{"unknown key", ThemeFormatTOML, `sucess = "10"`, "unknown theme key 'sucess'"},
+		{"bad color", ThemeFormatTOML, `error = "red"`, "error must be an ANSI color"},
+		{"out of range", ThemeFormatJSON, `{"info": 300}`, "info must be an ANSI color"},
+		{"bold only for header", ThemeFormatTOML, `muted = "bold"`, "muted must be an ANSI color"},
+		{"unknown base", ThemeFormatTOML, `base = "lagoon"`, "unknown base theme 'lagoon'"},
+		{"no equals", ThemeFormatTOML, "success", "line 1: expected key = value"},
+		{"table", ThemeFormatTOML, "[colors]", "line 1: expected key = value"},
+		{"trailing junk", ThemeFormatTOML, `success = "10" 11`, "unexpected 11"},
+		{"set twice", ThemeFormatTOML, "info = 1\ninfo = 2", "line 2: info is set twice"},
+		{"bad json", ThemeFormatJSON, `{"info": }`, "invalid JSON"},
+		{"json list", ThemeFormatJSON, `{"info": [1]}`, "info must be a string or a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTheme([]byte(tt.data), tt.format)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalTheme_RoundTrips(t *testing.T) {
+	for _, format := range []string{ThemeFormatTOML, ThemeFormatJSON} {
+		data, err := MarshalTheme("sunset-light", Themes["sunset-light"], format)
+		if err != nil {
+			t.Fatalf("MarshalTheme(%s): %v", format, err)
+		}
+		theme, err := ParseTheme(data, format)
+		if err != nil {
+			t.Fatalf("ParseTheme(%s): %v\n%s", format, err, data)
+		}
+		if theme != Themes["sunset-light"] {
+			t.Errorf("%s round trip = %+v, want %+v", format, theme, Themes["sunset-light"])
+		}
+	}
+}
+
+func TestLoadThemeFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"dracula.json":      `{"success": "84"}`,
+		"team.json":         `{"info": "#8be9fd"}`,
+		"broken.toml":       `success = "green"`,
+		"neon-dark.toml":    `success = "1"`,
+		"Upper.toml":        `success = "1"`,
+		"notes.txt":         "not a theme",
+		"dracula.toml":      `success = "1"`,
+		"ocean-custom.toml": "",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() { LoadThemeFiles(t.TempDir()) })
+
+	errs := LoadThemeFiles(dir)
+
+	want := []string{"dracula", "ocean-custom", "team"}
+	if !slices.Equal(CustomThemeNames, want) {
+		t.Errorf("CustomThemeNames = %v, want %v", CustomThemeNames, want)
+	}
+	if len(errs) != 4 {
+		t.Errorf("errors = %v, want 4 (broken, neon-dark, Upper, dracula.toml)", errs)
+	}
+	if Themes["dracula"].Success != "84" || Themes["team"].Info != "#8be9fd" {
+		t.Errorf("custom themes not loaded: %+v %+v", Themes["dracula"], Themes["team"])
+	}
+	if Themes["neon-dark"].Success == "1" {
+		t.Error("a theme file must not replace a built-in theme")
+	}
+	if got := ResolveThemeName("dracula"); got != "dracula" {
+		t.Errorf("ResolveThemeName(dracula) = %q, want dracula", got)
+	}
+
+	// Loading again drops themes whose files are gone
+	if errs := LoadThemeFiles(t.TempDir()); len(errs) != 0 {
+		t.Errorf("errors = %v", errs)
+	}
+	if _, ok := Themes["dracula"]; ok || len(CustomThemeNames) != 0 {
+		t.Error("custom themes should be dropped on reload")
+	}
+}
@@ -2,7 +2,9 @@ package style
 
 import (
 	"os"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/muesli/termenv"
 )
@@ -26,6 +28,9 @@ type ColorConfig struct {
 	Color5   string // PRE-PUSH
 	Color6   string // BACKFILL
 	Color7   string // MANUAL
+	Color8   string // BRANCH-CREATE
+	Color9   string // BRANCH-DELETE
+	Color10  string // STASH
 }
 
 // BaseThemeNames lists available theme bases (auto-detects dark/light).
@@ -38,6 +43,7 @@ var BaseThemeNames = []string{
 	"sunset",
 	"candy",
 	"contrast",
+	"colorblind",
 }
 
 // ThemeNames lists all themes with explicit dark/light variants.
@@ -50,6 +56,7 @@ var ThemeNames = []string{
 	"sunset-dark", "sunset-light",
 	"candy-dark", "candy-light",
 	"contrast-dark", "contrast-light",
+	"colorblind-dark", "colorblind-light",
 }
 
 // Themes contains the built-in color themes.
@@ -75,6 +82,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "11",  // PRE-PUSH (bright yellow)
 		Color6:   "8",   // BACKFILL (dark gray)
 		Color7:   "15",  // MANUAL (white)
+		Color8:   "119", // BRANCH-CREATE (light green)
+		Color9:   "203", // BRANCH-DELETE (salmon)
+		Color10:  "180", // STASH (tan)
 	},
 
 	// Classic light - dark saturated colors for light/white backgrounds.
@@ -96,6 +106,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "130", // PRE-PUSH (dark orange)
 		Color6:   "240", // BACKFILL (dark gray)
 		Color7:   "235", // MANUAL (near black)
+		Color8:   "64",  // BRANCH-CREATE (olive)
+		Color9:   "160", // BRANCH-DELETE (red)
+		Color10:  "94",  // STASH (brown)
 	},
 
 	// Neon dark - vivid saturated colors, cyberpunk aesthetic.
@@ -117,6 +130,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "226", // PRE-PUSH (yellow)
 		Color6:   "242", // BACKFILL (gray)
 		Color7:   "231", // MANUAL (white)
+		Color8:   "118", // BRANCH-CREATE (chartreuse)
+		Color9:   "196", // BRANCH-DELETE (red)
+		Color10:  "208", // STASH (orange)
 	},
 
 	// Neon light - deep saturated colors for light backgrounds.
@@ -138,6 +154,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "166", // PRE-PUSH (dark orange)
 		Color6:   "241", // BACKFILL (gray)
 		Color7:   "236", // MANUAL (dark gray)
+		Color8:   "34",  // BRANCH-CREATE (green)
+		Color9:   "160", // BRANCH-DELETE (red)
+		Color10:  "166", // STASH (orange)
 	},
 
 	// Aurora dark - northern lights inspired palette for dark backgrounds.
@@ -159,6 +178,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "222", // PRE-PUSH (gold)
 		Color6:   "245", // BACKFILL (gray)
 		Color7:   "189", // MANUAL (light lavender)
+		Color8:   "121", // BRANCH-CREATE (mint)
+		Color9:   "210", // BRANCH-DELETE (light coral)
+		Color10:  "180", // STASH (tan)
 	},
 
 	// Aurora light - deep jewel tones for light backgrounds.
@@ -180,6 +202,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "136", // PRE-PUSH (amber)
 		Color6:   "241", // BACKFILL (dark gray)
 		Color7:   "96",  // MANUAL (plum)
+		Color8:   "29",  // BRANCH-CREATE (sea green)
+		Color9:   "161", // BRANCH-DELETE (crimson)
+		Color10:  "130", // STASH (rust)
 	},
 
 	// Mono dark - minimalist grayscale with cyan accent.
@@ -201,6 +226,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "229", // PRE-PUSH (pale yellow)
 		Color6:   "243", // BACKFILL (dim gray)
 		Color7:   "255", // MANUAL (white)
+		Color8:   "252", // BRANCH-CREATE (light gray)
+		Color9:   "242", // BRANCH-DELETE (gray)
+		Color10:  "248", // STASH (silver)
 	},
 
 	// Mono light - minimalist grayscale with teal accent.
@@ -222,6 +250,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "136", // PRE-PUSH (amber)
 		Color6:   "247", // BACKFILL (light gray)
 		Color7:   "235", // MANUAL (near black)
+		Color8:   "236", // BRANCH-CREATE (charcoal)
+		Color9:   "246", // BRANCH-DELETE (gray)
+		Color10:  "241", // STASH (dim gray)
 	},
 
 	// Ocean dark - cool blues and teals, like deep water.
@@ -243,6 +274,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "221", // PRE-PUSH (gold)
 		Color6:   "67",  // BACKFILL (steel blue)
 		Color7:   "159", // MANUAL (light cyan)
+		Color8:   "115", // BRANCH-CREATE (aquamarine)
+		Color9:   "174", // BRANCH-DELETE (light red)
+		Color10:  "152", // STASH (pale cyan)
 	},
 
 	// Ocean light - deep sea colors for light backgrounds.
@@ -264,6 +298,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "130", // PRE-PUSH (dark orange)
 		Color6:   "66",  // BACKFILL (grayish cyan)
 		Color7:   "17",  // MANUAL (navy)
+		Color8:   "29",  // BRANCH-CREATE (sea green)
+		Color9:   "131", // BRANCH-DELETE (indian red)
+		Color10:  "66",  // STASH (slate)
 	},
 
 	// Sunset dark - warm gradient from orange to magenta to purple.
@@ -285,6 +322,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "221", // PRE-PUSH (gold)
 		Color6:   "139", // BACKFILL (dusty rose)
 		Color7:   "224", // MANUAL (misty rose)
+		Color8:   "186", // BRANCH-CREATE (khaki)
+		Color9:   "203", // BRANCH-DELETE (coral red)
+		Color10:  "180", // STASH (tan)
 	},
 
 	// Sunset light - deep warm tones for light backgrounds.
@@ -306,6 +346,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "136", // PRE-PUSH (dark gold)
 		Color6:   "95",  // BACKFILL (dusty purple)
 		Color7:   "52",  // MANUAL (dark red)
+		Color8:   "100", // BRANCH-CREATE (olive)
+		Color9:   "160", // BRANCH-DELETE (red)
+		Color10:  "94",  // STASH (brown)
 	},
 
 	// Candy dark - sweet pastel colors on dark background.
@@ -327,6 +370,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "222", // PRE-PUSH (peach)
 		Color6:   "188", // BACKFILL (light lavender)
 		Color7:   "231", // MANUAL (white)
+		Color8:   "157", // BRANCH-CREATE (pale green)
+		Color9:   "217", // BRANCH-DELETE (light pink)
+		Color10:  "223", // STASH (peach)
 	},
 
 	// Candy light - deeper candy colors for light backgrounds.
@@ -348,6 +394,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "172", // PRE-PUSH (dark peach)
 		Color6:   "103", // BACKFILL (medium purple)
 		Color7:   "240", // MANUAL (dark gray)
+		Color8:   "71",  // BRANCH-CREATE (fern)
+		Color9:   "168", // BRANCH-DELETE (rose)
+		Color10:  "137", // STASH (light brown)
 	},
 
 	// Contrast dark - maximum readability with pure primaries.
@@ -369,6 +418,9 @@ var Themes = map[string]ColorConfig{
 		Color5:   "226", // PRE-PUSH (yellow)
 		Color6:   "245", // BACKFILL (gray)
 		Color7:   "231", // MANUAL (white)
+		Color8:   "118", // BRANCH-CREATE (chartreuse)
+		Color9:   "196", // BRANCH-DELETE (red)
+		Color10:  "208", // STASH (orange)
 	},
 
 	// Contrast light - maximum readability for light backgrounds.
@@ -390,6 +442,56 @@ var Themes = map[string]ColorConfig{
 		Color5:   "130", // PRE-PUSH (dark orange)
 		Color6:   "243", // BACKFILL (gray)
 		Color7:   "232", // MANUAL (near black)
+		Color8:   "28",  // BRANCH-CREATE (green)
+		Color9:   "160", // BRANCH-DELETE (red)
+		Color10:  "94",  // STASH (brown)
+	},
+
+	// Colorblind dark - Okabe-Ito palette, distinguishable with protanopia,
+	// deuteranopia, and tritanopia. Avoids red/green pairs entirely.
+	"colorblind-dark": {
+		Success:  "74",  // sky blue
+		Warning:  "214", // orange
+		Error:    "166", // vermillion
+		Info:     "227", // yellow
+		Muted:    "245", // gray
+		Header:   "bold",
+		Border:   "244", // medium gray for borders
+		UIActive: "74",  // sky blue for active UI elements
+		UIDim:    "240", // dark gray for inactive UI elements
+		Color1:   "74",  // POST-COMMIT (sky blue)
+		Color2:   "175", // POST-REWRITE (reddish purple)
+		Color3:   "36",  // POST-CHECKOUT (bluish green)
+		Color4:   "214", // POST-MERGE (orange)
+		Color5:   "227", // PRE-PUSH (yellow)
+		Color6:   "245", // BACKFILL (gray)
+		Color7:   "231", // MANUAL (white)
+		Color8:   "32",  // BRANCH-CREATE (blue)
+		Color9:   "166", // BRANCH-DELETE (vermillion)
+		Color10:  "250", // STASH (light gray)
+	},
+
+	// Colorblind light - Okabe-Ito palette darkened for light backgrounds.
+	"colorblind-light": {
+		Success:  "25",  // blue
+		Warning:  "172", // dark orange
+		Error:    "166", // vermillion
+		Info:     "31",  // dark sky blue
+		Muted:    "243", // medium-dark gray
+		Header:   "bold",
+		Border:   "250", // light gray for borders
+		UIActive: "25",  // blue for active UI elements
+		UIDim:    "252", // very light gray for inactive UI elements
+		Color1:   "25",  // POST-COMMIT (blue)
+		Color2:   "132", // POST-REWRITE (reddish purple)
+		Color3:   "29",  // POST-CHECKOUT (bluish green)
+		Color4:   "172", // POST-MERGE (orange)
+		Color5:   "166", // PRE-PUSH (vermillion)
+		Color6:   "243", // BACKFILL (gray)
+		Color7:   "235", // MANUAL (near black)
+		Color8:   "31",  // BRANCH-CREATE (dark sky blue)
+		Color9:   "124", // BRANCH-DELETE (dark red)
+		Color10:  "240", // STASH (dark gray)
 	},
 }
 
@@ -411,22 +513,80 @@ var colorConfigKeys = map[string]string{
 	"color_5":         "Color5",
 	"color_6":         "Color6",
 	"color_7":         "Color7",
+	"color_8":         "Color8",
+	"color_9":         "Color9",
+	"color_10":        "Color10",
+}
+
+// sourceColorKeys maps per-source color keys to ColorConfig field names.
+// They are applied after colorConfigKeys, so they win over color_1..color_7.
+var sourceColorKeys = []struct{ key, field string }{
+	{"source_colors.post_commit", "Color1"},
+	{"source_colors.post_rewrite", "Color2"},
+	{"source_colors.post_checkout", "Color3"},
+	{"source_colors.post_merge", "Color4"},
+	{"source_colors.pre_push", "Color5"},
+	{"source_colors.backfill", "Color6"},
+	{"source_colors.manual", "Color7"},
+	{"source_colors.branch_create", "Color8"},
+	{"source_colors.branch_delete", "Color9"},
+	{"source_colors.stash", "Color10"},
 }
 
+// AutoTheme is the theme setting that picks theme_dark or theme_light by
+// the terminal background each time fp starts.
+const AutoTheme = "auto"
+
+var (
+	backgroundOnce sync.Once
+	darkBackground bool
+)
+
 // IsDarkBackground returns true if the terminal has a dark background.
-// Uses termenv to query the terminal. Returns true if detection fails.
+// FP_BACKGROUND=dark or light says so for terminals that don't answer;
+// otherwise the terminal is asked once per process (OSC 11, then
+// COLORFGBG). Returns true if detection fails.
 func IsDarkBackground() bool {
-	return termenv.HasDarkBackground()
+	switch strings.ToLower(os.Getenv("FP_BACKGROUND")) {
+	case "dark":
+		return true
+	case "light":
+		return false
+	}
+	backgroundOnce.Do(func() {
+		darkBackground = termenv.HasDarkBackground()
+	})
+	return darkBackground
+}
+
+// PickTheme resolves a theme setting to the theme to use: auto becomes
+// theme_dark or theme_light from cfg (default if unset) for the terminal
+// background, then ResolveThemeName adds the variant to base names.
+func PickTheme(name string, cfg map[string]string) string {
+	if name == AutoTheme {
+		key := "theme_light"
+		if IsDarkBackground() {
+			key = "theme_dark"
+		}
+		name = cfg[key]
+		if name == "" || name == AutoTheme {
+			name = "default"
+		}
+	}
+	return ResolveThemeName(name)
 }
 
 // ResolveThemeName takes a theme name and returns the full theme name.
-// If the name doesn't have a -dark/-light suffix, it appends one based
-// on terminal background detection.
+// If the name doesn't have a -dark/-light suffix and isn't a custom theme,
+// it appends one based on terminal background detection.
 func ResolveThemeName(name string) string {
 	// If already has suffix, return as-is
 	if strings.HasSuffix(name, "-dark") || strings.HasSuffix(name, "-light") {
 		return name
 	}
+	if slices.Contains(CustomThemeNames, name) {
+		return name
+	}
 
 	// Auto-detect and append suffix
 	if IsDarkBackground() {
@@ -443,14 +603,15 @@ func ResolveThemeName(name string) string {
 // 4. Default theme (auto-detected based on terminal background)
 func LoadColorConfig(cfg map[string]string) ColorConfig {
 	// Start with auto-detected default
-	themeName := ResolveThemeName("default")
+	themeName := "default"
 
 	// Check env for theme override
 	if envTheme := os.Getenv("FP_COLOR_THEME"); envTheme != "" {
-		themeName = ResolveThemeName(envTheme)
+		themeName = envTheme
 	} else if cfgTheme, ok := cfg["theme"]; ok && cfgTheme != "" {
-		themeName = ResolveThemeName(cfgTheme)
+		themeName = cfgTheme
 	}
+	themeName = PickTheme(themeName, cfg)
 
 	// Get base theme (fall back to default-dark if unknown)
 	theme, ok := Themes[themeName]
@@ -475,7 +636,23 @@ func LoadColorConfig(cfg map[string]string) ColorConfig {
 		}
 	}
 
-	return result
+	return ApplySourceColors(result, cfg)
+}
+
+// ApplySourceColors returns c with the per-source color overrides
+// (source_colors.* in config, FP_SOURCE_COLORS_* in env) applied.
+// Values can be ANSI color numbers (0-255) or hex colors ("#56b4e9").
+func ApplySourceColors(c ColorConfig, cfg map[string]string) ColorConfig {
+	for _, sc := range sourceColorKeys {
+		if envVal := os.Getenv("FP_" + toUpperSnake(sc.key)); envVal != "" {
+			setColorField(&c, sc.field, envVal)
+			continue
+		}
+		if cfgVal := cfg[sc.key]; cfgVal != "" {
+			setColorField(&c, sc.field, cfgVal)
+		}
+	}
+	return c
 }
 
 // setColorField sets a field on ColorConfig by name.
@@ -513,10 +690,17 @@ func setColorField(c *ColorConfig, field, value string) {
 		c.Color6 = value
 	case "Color7":
 		c.Color7 = value
+	case "Color8":
+		c.Color8 = value
+	case "Color9":
+		c.Color9 = value
+	case "Color10":
+		c.Color10 = value
 	}
 }
 
-// toUpperSnake converts "color_success" to "COLOR_SUCCESS".
+// toUpperSnake converts "color_success" to "COLOR_SUCCESS" and
+// "source_colors.post_commit" to "SOURCE_COLORS_POST_COMMIT".
 func toUpperSnake(s string) string {
-	return strings.ToUpper(s)
+	return strings.ToUpper(strings.ReplaceAll(s, ".", "_"))
 }
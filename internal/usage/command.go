@@ -0,0 +1,53 @@
+package usage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Command describes the command a usage error came from, so the error can
+// show how to call it instead of sending the user to --help.
+type Command struct {
+	Path    string // e.g. "fp activity"
+	Usage   string
+	Args    []Item // arguments relevant to the error
+	Flags   []Item // flags relevant to the error
+	Example string
+}
+
+// Item is one argument or flag listed under a usage error.
+type Item struct {
+	Name        string
+	Description string
+}
+
+// String renders the usage line, the relevant arguments and flags, and the
+// example, in the layout used for every usage error.
+func (c *Command) String() string {
+	var b strings.Builder
+
+	if c.Usage != "" {
+		fmt.Fprintf(&b, "usage: %s\n", c.Usage)
+	}
+	for _, items := range [][]Item{c.Args, c.Flags} {
+		if len(items) == 0 {
+			continue
+		}
+		width := 0
+		for _, it := range items {
+			width = max(width, len(it.Name))
+		}
+		b.WriteString("\n")
+		for _, it := range items {
+			fmt.Fprintf(&b, "    %-*s  %s\n", width, it.Name, it.Description)
+		}
+	}
+	if c.Example != "" {
+		fmt.Fprintf(&b, "\nexample: %s\n", c.Example)
+	}
+	if c.Path != "" {
+		fmt.Fprintf(&b, "\nSee '%s --help' for more.", c.Path)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
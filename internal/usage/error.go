@@ -17,6 +17,8 @@ const (
 	ErrGitNotInstalled
 	ErrInvalidConfigKey
 	ErrFailedConfigPath
+	ErrReadOnly
+	ErrUpdateAvailable
 )
 
 // Exit codes:
@@ -30,6 +32,7 @@ const (
 //	  - Git not installed
 //	  - Invalid config key
 //	  - Failed config path
+//	  - Read-only mode
 //
 //	Exit 2: User input errors
 //	  - Invalid flag
@@ -37,6 +40,9 @@ const (
 //	  - Missing argument
 //	  - Missing remote
 //	  - Ambiguous remote
+//
+//	Exit 10: Not a failure
+//	  - Update available (fp update --check)
 var exitCodes = map[ErrorKind]int{
 	ErrUnknown:          1,
 	ErrInvalidFlag:      2,
@@ -51,18 +57,31 @@ var exitCodes = map[ErrorKind]int{
 	ErrGitNotInstalled:  1,
 	ErrInvalidConfigKey: 1,
 	ErrFailedConfigPath: 1,
+	ErrReadOnly:         1,
+	ErrUpdateAvailable:  10,
 }
 
 // Error represents a user-facing usage error with semantic type information.
 type Error struct {
 	Kind     ErrorKind
 	Message  string
-	ExitCode int // kept for backward compatibility, computed from Kind if zero
+	ExitCode int      // kept for backward compatibility, computed from Kind if zero
+	Command  *Command // set for input errors once the command is known
 }
 
-// Error implements the error interface.
+// Error implements the error interface. Input errors include how to call the
+// command they came from.
 func (e *Error) Error() string {
-	return e.Message
+	if e.Command == nil {
+		return e.Message
+	}
+	return e.Message + "\n\n" + e.Command.String()
+}
+
+// IsInputError reports whether the error is about how the command was
+// called (exit code 2), rather than about the environment.
+func (e *Error) IsInputError() bool {
+	return exitCodes[e.Kind] == 2
 }
 
 // GetExitCode returns the appropriate exit code for this error.
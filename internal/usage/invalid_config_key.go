@@ -1,10 +1,28 @@
 package usage
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
+
+func InvalidConfigKey(key string, suggestions ...string) *Error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "fp: unknown config key '%s'", key)
+
+	if len(suggestions) > 0 {
+		msg.WriteString("\n\n")
+		if len(suggestions) == 1 {
+			msg.WriteString("Did you mean this?\n")
+		} else {
+			msg.WriteString("Did you mean one of these?\n")
+		}
+		for _, s := range suggestions {
+			fmt.Fprintf(&msg, "    %s\n", s)
+		}
+	}
 
-func InvalidConfigKey(key string) *Error {
 	return &Error{
 		Kind:    ErrInvalidConfigKey,
-		Message: fmt.Sprintf("fp: unknown config key '%s'", key),
+		Message: strings.TrimSuffix(msg.String(), "\n"),
 	}
 }
@@ -0,0 +1,11 @@
+package usage
+
+import "fmt"
+
+func ReadOnly(command string) *Error {
+	return &Error{
+		Kind: ErrReadOnly,
+		Message: fmt.Sprintf("fp: '%s' is disabled in read-only mode\n"+
+			"Hint: Drop --read-only, or remove read_only from ~/.fprc", command),
+	}
+}
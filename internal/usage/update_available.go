@@ -0,0 +1,11 @@
+package usage
+
+import "fmt"
+
+func UpdateAvailable(version string) *Error {
+	return &Error{
+		Kind: ErrUpdateAvailable,
+		Message: fmt.Sprintf("fp: %s is available\n"+
+			"Hint: Run 'fp update' to install it", version),
+	}
+}
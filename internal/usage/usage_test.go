@@ -192,3 +192,48 @@ func TestError_ImplementsError(t *testing.T) {
 	require.NotNil(t, err)
 	require.NotEmpty(t, err.Error())
 }
+
+// =========== READ-ONLY TESTS ===========
+
+func TestReadOnly(t *testing.T) {
+	err := ReadOnly("fp setup")
+
+	require.NotNil(t, err)
+	require.Contains(t, err.Message, "'fp setup'")
+	require.Contains(t, err.Message, "read-only mode")
+	require.Equal(t, 1, err.GetExitCode())
+	require.Equal(t, ErrReadOnly, err.Kind)
+}
+
+func TestUpdateAvailable(t *testing.T) {
+	err := UpdateAvailable("v1.2.0")
+
+	require.NotNil(t, err)
+	require.Contains(t, err.Message, "v1.2.0 is available")
+	require.Equal(t, 10, err.GetExitCode())
+	require.False(t, err.IsInputError())
+}
+
+// =========== COMMAND CONTEXT TESTS ===========
+
+func TestError_WithCommand(t *testing.T) {
+	err := InvalidFlag("--jsn")
+	err.Command = &Command{
+		Path:    "fp activity",
+		Usage:   "fp activity [options]",
+		Flags:   []Item{{Name: "--json", Description: "Output as JSON"}, {Name: "-e, --enrich", Description: "Show commit message"}},
+		Example: "fp activity -i",
+	}
+
+	require.True(t, err.IsInputError())
+	require.Equal(t, `fp: invalid flag '--jsn'
+
+usage: fp activity [options]
+
+    --json        Output as JSON
+    -e, --enrich  Show commit message
+
+example: fp activity -i
+
+See 'fp activity --help' for more.`, err.Error())
+}